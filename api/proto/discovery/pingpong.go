@@ -39,6 +39,9 @@ func NewPingMessage(peer p2p.Peer, isClient bool) *PingMessageType {
 	ping.Node.IP = peer.IP
 	ping.Node.Port = peer.Port
 	ping.Node.PeerID = peer.PeerID
+	for _, addr := range peer.Addrs {
+		ping.Node.Addrs = append(ping.Node.Addrs, addr.String())
+	}
 	if !isClient {
 		ping.Node.PubKey = peer.ConsensusPubKey.Serialize()
 		ping.Node.Role = node.ValidatorRole
@@ -541,6 +541,7 @@ type ConsensusRequest struct {
 	Block                []byte   `protobuf:"bytes,5,opt,name=block,proto3" json:"block,omitempty"`
 	SenderPubkey         []byte   `protobuf:"bytes,6,opt,name=sender_pubkey,json=senderPubkey,proto3" json:"sender_pubkey,omitempty"`
 	Payload              []byte   `protobuf:"bytes,7,opt,name=payload,proto3" json:"payload,omitempty"`
+	Epoch                uint64   `protobuf:"varint,8,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -620,6 +621,13 @@ func (m *ConsensusRequest) GetPayload() []byte {
 	return nil
 }
 
+func (m *ConsensusRequest) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
 type DrandRequest struct {
 	ShardId              uint32   `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`               // Deprecated: Do not use.
 	SenderPubkey         []byte   `protobuf:"bytes,2,opt,name=sender_pubkey,json=senderPubkey,proto3" json:"sender_pubkey,omitempty"` // Deprecated: Do not use.
@@ -702,6 +710,7 @@ type ViewChangeRequest struct {
 	M2Bitmap             []byte   `protobuf:"bytes,10,opt,name=m2_bitmap,json=m2Bitmap,proto3" json:"m2_bitmap,omitempty"`
 	M3Aggsigs            []byte   `protobuf:"bytes,11,opt,name=m3_aggsigs,json=m3Aggsigs,proto3" json:"m3_aggsigs,omitempty"`
 	M3Bitmap             []byte   `protobuf:"bytes,12,opt,name=m3_bitmap,json=m3Bitmap,proto3" json:"m3_bitmap,omitempty"`
+	Epoch                uint64   `protobuf:"varint,13,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -816,6 +825,13 @@ func (m *ViewChangeRequest) GetM3Bitmap() []byte {
 	return nil
 }
 
+func (m *ViewChangeRequest) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("message.ServiceType", ServiceType_name, ServiceType_value)
 	proto.RegisterEnum("message.MessageType", MessageType_name, MessageType_value)
@@ -835,70 +851,71 @@ func init() {
 }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 998 bytes of a gzipped FileDescriptorProto
+	// 1011 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xdf, 0x6e, 0xe3, 0xc4,
-	0x17, 0xae, 0xf3, 0xcf, 0xc9, 0xb1, 0x93, 0x7a, 0xe7, 0xd7, 0x1f, 0xf5, 0x76, 0xbb, 0xab, 0x92,
+	0x17, 0xae, 0xf3, 0xcf, 0xc9, 0xb1, 0x9d, 0x7a, 0xe7, 0xd7, 0x1f, 0xf5, 0x76, 0xbb, 0xab, 0x92,
 	0x82, 0xa8, 0xf6, 0xa2, 0x82, 0x14, 0x69, 0x05, 0xe2, 0x26, 0x4d, 0xcc, 0xd6, 0x6a, 0xeb, 0x84,
-	0xb1, 0xbb, 0x15, 0x57, 0xd6, 0x34, 0x1e, 0x25, 0x56, 0x13, 0x3b, 0x78, 0x9c, 0xae, 0xf2, 0x02,
-	0xbc, 0x02, 0xef, 0x03, 0xbc, 0x00, 0x57, 0xbc, 0x0e, 0x9a, 0x19, 0xdb, 0x71, 0x52, 0xb8, 0x43,
-	0xdc, 0xe5, 0x7c, 0xdf, 0xf9, 0xce, 0x99, 0xf3, 0x8d, 0xcf, 0xb4, 0xd0, 0x5e, 0x50, 0xc6, 0xc8,
-	0x94, 0x9e, 0x2f, 0x93, 0x38, 0x8d, 0x91, 0x9a, 0x85, 0xdd, 0x5f, 0xab, 0xa0, 0xde, 0xca, 0xdf,
-	0xe8, 0x1d, 0xe8, 0x8c, 0x26, 0x4f, 0xe1, 0x84, 0xfa, 0xe9, 0x7a, 0x49, 0x4d, 0xe5, 0x44, 0x39,
-	0xeb, 0xf4, 0x0e, 0xce, 0x73, 0xa9, 0x2b, 0x49, 0x6f, 0xbd, 0xa4, 0x58, 0x63, 0x9b, 0x00, 0x9d,
-	0x41, 0x4d, 0x08, 0x2a, 0x3b, 0x82, 0xac, 0xb0, 0x10, 0x88, 0x0c, 0x74, 0x0c, 0x2d, 0x16, 0x4e,
-	0x23, 0x92, 0xae, 0x12, 0x6a, 0x56, 0x4f, 0x94, 0x33, 0x1d, 0x6f, 0x00, 0xf4, 0x0e, 0x54, 0x96,
-	0x92, 0xc7, 0x30, 0x9a, 0x9a, 0xb5, 0x13, 0xe5, 0x4c, 0xeb, 0x1d, 0x6e, 0x7a, 0x4b, 0x1c, 0xd3,
-	0x9f, 0x56, 0x94, 0xa5, 0x97, 0x15, 0x53, 0xb9, 0xda, 0xc3, 0x79, 0x36, 0xfa, 0x06, 0x5a, 0x93,
-	0x38, 0x62, 0x34, 0x62, 0x2b, 0x66, 0xd6, 0x85, 0xf4, 0x65, 0x21, 0x1d, 0xe4, 0x4c, 0x26, 0xbe,
-	0xda, 0xc3, 0x9b, 0x6c, 0xf4, 0x15, 0xd4, 0x83, 0x84, 0x44, 0x81, 0xd9, 0x10, 0xb2, 0xff, 0x17,
-	0xb2, 0x21, 0x47, 0xb7, 0xfb, 0xc9, 0x4c, 0xf4, 0x1d, 0xc0, 0x53, 0x48, 0x3f, 0x4e, 0x66, 0x24,
-	0x9a, 0x52, 0x53, 0x15, 0xba, 0xa3, 0x42, 0xf7, 0x21, 0xa4, 0x1f, 0x07, 0x82, 0xda, 0xf4, 0x2b,
-	0xe5, 0xa3, 0xef, 0x61, 0x7f, 0x1e, 0xa7, 0x29, 0x4d, 0xd6, 0x7e, 0x22, 0x13, 0xcc, 0xe6, 0xce,
-	0xb0, 0x37, 0x92, 0xdf, 0x6e, 0xde, 0x99, 0x6f, 0xa3, 0x2d, 0x50, 0x33, 0x7d, 0xf7, 0x77, 0x05,
-	0x9a, 0x98, 0xb2, 0x25, 0x1f, 0xea, 0xbf, 0xb8, 0x45, 0x1b, 0x8c, 0xcd, 0x08, 0xb2, 0xad, 0xb8,
-	0x4c, 0xad, 0x67, 0x3e, 0x9f, 0x41, 0xf2, 0xd9, 0x10, 0xfb, 0xf3, 0x1d, 0x18, 0xa0, 0x99, 0x97,
-	0xe8, 0x8e, 0x60, 0x7f, 0x47, 0x85, 0x8e, 0x41, 0x5d, 0xce, 0xc9, 0x9a, 0x26, 0xcc, 0xac, 0x9c,
-	0x54, 0xcf, 0x5a, 0xbc, 0x0c, 0xce, 0x21, 0xf4, 0x06, 0x9a, 0x0f, 0x64, 0x4e, 0xa2, 0x09, 0x65,
-	0x66, 0xb5, 0xa0, 0x0b, 0xac, 0xfb, 0x9b, 0x02, 0x9d, 0x6d, 0x2f, 0xd1, 0xd7, 0xd9, 0x90, 0xd2,
-	0x95, 0xe3, 0x7f, 0xb0, 0xfc, 0x9c, 0x0f, 0x2b, 0x8a, 0xc9, 0x81, 0x4f, 0x41, 0x5b, 0x26, 0xe1,
-	0x13, 0x49, 0xa9, 0xff, 0x48, 0xd7, 0xc2, 0x21, 0xd9, 0x0b, 0x32, 0xf8, 0x9a, 0xae, 0xd1, 0x11,
-	0x34, 0xc8, 0x22, 0x5e, 0x45, 0xa9, 0xf0, 0xa2, 0x2a, 0xf8, 0x0c, 0xe9, 0x7e, 0x0b, 0x35, 0xe1,
-	0x71, 0x1b, 0xea, 0x96, 0xe3, 0x59, 0xd8, 0xd8, 0x3b, 0xaa, 0x34, 0x15, 0xd4, 0x81, 0x06, 0xb6,
-	0xdc, 0xbb, 0x1b, 0xcf, 0x50, 0x44, 0xfc, 0x3f, 0xd0, 0xc6, 0xf6, 0xe0, 0xda, 0xbf, 0xb7, 0x1d,
-	0xc7, 0xc2, 0x46, 0x85, 0x83, 0x5d, 0x17, 0x3a, 0xdb, 0x5f, 0x3f, 0xfa, 0x0c, 0xb4, 0x34, 0x21,
-	0x11, 0x23, 0x93, 0x34, 0x8c, 0x23, 0x31, 0x8b, 0x2e, 0xda, 0x95, 0x61, 0xf4, 0x0a, 0xd4, 0x28,
-	0x0e, 0xa8, 0x1f, 0x06, 0xa5, 0x03, 0x37, 0x38, 0x64, 0x07, 0xdd, 0x3f, 0x15, 0x30, 0x76, 0x17,
-	0x03, 0x1d, 0x82, 0xca, 0x3f, 0x54, 0xae, 0xe0, 0x35, 0x6b, 0xb8, 0xc1, 0x43, 0x3b, 0x40, 0xaf,
-	0xa0, 0xf5, 0x30, 0x8f, 0x27, 0x8f, 0x7e, 0xb4, 0x5a, 0x88, 0x62, 0x35, 0xdc, 0x14, 0x80, 0xb3,
-	0x5a, 0xa0, 0x97, 0xd0, 0x64, 0x33, 0x92, 0x04, 0x5c, 0xc6, 0x27, 0x6f, 0x63, 0x55, 0xc4, 0x76,
-	0x80, 0x5e, 0x03, 0x48, 0xdd, 0x8c, 0xb0, 0x99, 0xd8, 0x69, 0x1d, 0xcb, 0x4a, 0x57, 0x84, 0xcd,
-	0xd0, 0x01, 0xd4, 0x45, 0x20, 0x56, 0x56, 0xc7, 0x32, 0x40, 0xa7, 0xd0, 0x66, 0x34, 0x0a, 0x68,
-	0xe2, 0x2f, 0x57, 0x0f, 0xdc, 0xee, 0x86, 0x60, 0x75, 0x09, 0x8e, 0x05, 0x86, 0x4c, 0x50, 0x97,
-	0x64, 0x3d, 0x8f, 0x49, 0x20, 0x16, 0x50, 0xc7, 0x79, 0xd8, 0xfd, 0x45, 0x01, 0xbd, 0xbc, 0xbb,
-	0xe8, 0x75, 0xe9, 0x7c, 0x7c, 0xac, 0xb6, 0xfc, 0x88, 0xf2, 0x33, 0x7e, 0xb1, 0xdb, 0xae, 0x52,
-	0xd8, 0xb9, 0xdd, 0xf2, 0xd3, 0xad, 0x61, 0xaa, 0x45, 0x56, 0x69, 0xa0, 0xe3, 0xcd, 0xa9, 0x6a,
-	0x05, 0x5f, 0x9c, 0xec, 0xe7, 0x2a, 0xbc, 0x78, 0xf6, 0x3a, 0xfc, 0xfb, 0xa6, 0x3f, 0xf3, 0xaf,
-	0xf6, 0x37, 0xfe, 0x9d, 0x42, 0x7b, 0x4e, 0x49, 0x29, 0x49, 0x5e, 0x81, 0x2e, 0xc1, 0xe7, 0x26,
-	0x37, 0xb6, 0x4c, 0x46, 0x9f, 0x43, 0x67, 0xf3, 0xa4, 0xf9, 0x2c, 0x9c, 0x66, 0xb7, 0xd0, 0xde,
-	0xa0, 0x6e, 0x38, 0xe5, 0xf7, 0xcf, 0x81, 0x30, 0x10, 0x29, 0x4d, 0x79, 0xff, 0x12, 0xc9, 0xe8,
-	0x45, 0xcf, 0x27, 0xd3, 0x29, 0x0b, 0xa7, 0xcc, 0x6c, 0x49, 0x7a, 0xd1, 0xeb, 0x4b, 0x80, 0x1b,
-	0xb0, 0xe8, 0xf9, 0x0f, 0x61, 0xba, 0x20, 0x4b, 0x13, 0x04, 0xdb, 0x5c, 0xf4, 0x2e, 0x45, 0x2c,
-	0xb4, 0x17, 0x85, 0x56, 0xcb, 0xb4, 0x17, 0x65, 0xed, 0x45, 0xae, 0xd5, 0x33, 0xed, 0x85, 0xd4,
-	0xbe, 0x1d, 0x83, 0x56, 0x7a, 0x05, 0x51, 0x1b, 0x5a, 0x83, 0x91, 0xe3, 0x5a, 0x8e, 0x7b, 0xe7,
-	0x1a, 0x7b, 0x68, 0x1f, 0x54, 0xd7, 0xeb, 0x5f, 0xdb, 0xce, 0xfb, 0x6c, 0x2b, 0xdb, 0x50, 0x1f,
-	0xe2, 0xbe, 0x33, 0x94, 0xfb, 0x88, 0x10, 0x74, 0x06, 0x37, 0xb6, 0xe5, 0x78, 0xbe, 0x7b, 0x37,
-	0x1e, 0x8f, 0xb0, 0x67, 0x54, 0xdf, 0xfe, 0xa1, 0x80, 0x56, 0x7a, 0x27, 0xd1, 0x1b, 0xf8, 0xc4,
-	0xb1, 0xee, 0x9d, 0xd1, 0xd0, 0xf2, 0x2f, 0xad, 0xfe, 0x60, 0xe4, 0xf8, 0x79, 0x49, 0xb9, 0xf8,
-	0x3a, 0x34, 0xfb, 0x8e, 0x33, 0xba, 0x73, 0x06, 0x96, 0xa1, 0x20, 0x0d, 0xd4, 0x31, 0xb6, 0xc6,
-	0x7d, 0x6c, 0x19, 0x15, 0x4e, 0x65, 0xc1, 0xd0, 0xa8, 0x22, 0x80, 0xc6, 0x60, 0x74, 0x7b, 0x6b,
-	0x7b, 0x46, 0x4d, 0x9e, 0x93, 0xff, 0xf6, 0xac, 0xa1, 0x51, 0x47, 0x1d, 0x80, 0x0f, 0xb6, 0x75,
-	0x3f, 0xb8, 0xea, 0x3b, 0xef, 0x2d, 0xa3, 0xc1, 0xab, 0x38, 0xd6, 0x3d, 0x87, 0x0c, 0x15, 0x21,
-	0x00, 0x71, 0x66, 0xdf, 0x76, 0x6c, 0xcf, 0x00, 0xd1, 0xf4, 0x00, 0x74, 0x89, 0x65, 0x15, 0x35,
-	0x81, 0x1e, 0xc2, 0xfe, 0xcd, 0xc8, 0xf3, 0x2c, 0xfc, 0xa3, 0x8f, 0xad, 0x1f, 0xee, 0x2c, 0xd7,
-	0x33, 0x74, 0x4e, 0xf4, 0xfa, 0xd0, 0x1e, 0xcc, 0x43, 0x1a, 0xa5, 0x99, 0x57, 0xe8, 0x4b, 0x50,
-	0xc7, 0x49, 0x3c, 0xa1, 0x8c, 0x21, 0x63, 0xf7, 0xaf, 0xc3, 0xd1, 0x8b, 0x02, 0xc9, 0x1f, 0xef,
-	0xee, 0xde, 0x43, 0x43, 0xfc, 0xb7, 0x71, 0xf1, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x83, 0x8d,
-	0x35, 0xd0, 0x7e, 0x08, 0x00, 0x00,
+	0x89, 0xbb, 0x15, 0x57, 0xd6, 0x34, 0x1e, 0x25, 0x56, 0x13, 0x3b, 0x78, 0x9c, 0xae, 0xf2, 0x24,
+	0xdc, 0xf0, 0x34, 0xc0, 0x0b, 0xf0, 0x20, 0xbc, 0x03, 0x9a, 0x19, 0xdb, 0x71, 0x52, 0xb8, 0x43,
+	0xdc, 0xf9, 0x7c, 0xe7, 0x7c, 0xe7, 0xcf, 0x37, 0x73, 0x26, 0x01, 0x63, 0x41, 0x19, 0x23, 0x53,
+	0x7a, 0xbe, 0x4c, 0xe2, 0x34, 0x46, 0x6a, 0x66, 0x76, 0x7e, 0xad, 0x82, 0x7a, 0x2b, 0xbf, 0xd1,
+	0x3b, 0xd0, 0x19, 0x4d, 0x9e, 0xc2, 0x09, 0xf5, 0xd3, 0xf5, 0x92, 0x5a, 0xca, 0x89, 0x72, 0xd6,
+	0xee, 0x1e, 0x9c, 0xe7, 0xd4, 0xb1, 0x74, 0x7a, 0xeb, 0x25, 0xc5, 0x1a, 0xdb, 0x18, 0xe8, 0x0c,
+	0x6a, 0x82, 0x50, 0xd9, 0x21, 0x64, 0x89, 0x05, 0x41, 0x44, 0xa0, 0x63, 0x68, 0xb1, 0x70, 0x1a,
+	0x91, 0x74, 0x95, 0x50, 0xab, 0x7a, 0xa2, 0x9c, 0xe9, 0x78, 0x03, 0xa0, 0x77, 0xa0, 0xb2, 0x94,
+	0x3c, 0x86, 0xd1, 0xd4, 0xaa, 0x9d, 0x28, 0x67, 0x5a, 0xf7, 0x70, 0x53, 0x5b, 0xe2, 0x98, 0xfe,
+	0xb4, 0xa2, 0x2c, 0xbd, 0xac, 0x58, 0xca, 0xd5, 0x1e, 0xce, 0xa3, 0xd1, 0x37, 0xd0, 0x9a, 0xc4,
+	0x11, 0xa3, 0x11, 0x5b, 0x31, 0xab, 0x2e, 0xa8, 0x2f, 0x0b, 0x6a, 0x3f, 0xf7, 0x64, 0xe4, 0xab,
+	0x3d, 0xbc, 0x89, 0x46, 0x5f, 0x41, 0x3d, 0x48, 0x48, 0x14, 0x58, 0x0d, 0x41, 0xfb, 0x7f, 0x41,
+	0x1b, 0x70, 0x74, 0xbb, 0x9e, 0x8c, 0x44, 0xdf, 0x01, 0x3c, 0x85, 0xf4, 0xe3, 0x64, 0x46, 0xa2,
+	0x29, 0xb5, 0x54, 0xc1, 0x3b, 0x2a, 0x78, 0x1f, 0x42, 0xfa, 0xb1, 0x2f, 0x5c, 0x9b, 0x7a, 0xa5,
+	0x78, 0xf4, 0x3d, 0xec, 0xcf, 0xe3, 0x34, 0xa5, 0xc9, 0xda, 0x4f, 0x64, 0x80, 0xd5, 0xdc, 0x19,
+	0xf6, 0x46, 0xfa, 0xb7, 0x8b, 0xb7, 0xe7, 0xdb, 0x68, 0x0b, 0xd4, 0x8c, 0xdf, 0xf9, 0x5d, 0x81,
+	0x26, 0xa6, 0x6c, 0xc9, 0x87, 0xfa, 0x2f, 0x4e, 0xd1, 0x01, 0x73, 0x33, 0x82, 0x2c, 0x2b, 0x0e,
+	0x53, 0xeb, 0x5a, 0xcf, 0x67, 0x90, 0xfe, 0x6c, 0x88, 0xfd, 0xf9, 0x0e, 0x0c, 0xd0, 0xcc, 0x53,
+	0x74, 0x86, 0xb0, 0xbf, 0xc3, 0x42, 0xc7, 0xa0, 0x2e, 0xe7, 0x64, 0x4d, 0x13, 0x66, 0x55, 0x4e,
+	0xaa, 0x67, 0x2d, 0x9e, 0x06, 0xe7, 0x10, 0x7a, 0x03, 0xcd, 0x07, 0x32, 0x27, 0xd1, 0x84, 0x32,
+	0xab, 0x5a, 0xb8, 0x0b, 0xac, 0xf3, 0x9b, 0x02, 0xed, 0x6d, 0x2d, 0xd1, 0xd7, 0x50, 0x2b, 0xa9,
+	0x72, 0xfc, 0x0f, 0x92, 0x9f, 0xf3, 0x61, 0x45, 0x32, 0x39, 0xf0, 0x29, 0x68, 0xcb, 0x24, 0x7c,
+	0x22, 0x29, 0xf5, 0x1f, 0xe9, 0x5a, 0x28, 0x24, 0x6b, 0x41, 0x06, 0x5f, 0xd3, 0x35, 0x3a, 0x82,
+	0x06, 0x59, 0xc4, 0xab, 0x28, 0x15, 0x5a, 0x54, 0x85, 0x3f, 0x43, 0x3a, 0xdf, 0x42, 0x4d, 0x68,
+	0x6c, 0x40, 0xdd, 0x76, 0x3d, 0x1b, 0x9b, 0x7b, 0x47, 0x95, 0xa6, 0x82, 0xda, 0xd0, 0xc0, 0xf6,
+	0xf8, 0xee, 0xc6, 0x33, 0x15, 0x61, 0xff, 0x0f, 0xb4, 0x91, 0xd3, 0xbf, 0xf6, 0xef, 0x1d, 0xd7,
+	0xb5, 0xb1, 0x59, 0xe1, 0x60, 0x67, 0x0c, 0xed, 0xed, 0xdb, 0x8f, 0x3e, 0x03, 0x2d, 0x4d, 0x48,
+	0xc4, 0xc8, 0x24, 0x0d, 0xe3, 0x48, 0xcc, 0xa2, 0x8b, 0x72, 0x65, 0x18, 0xbd, 0x02, 0x35, 0x8a,
+	0x03, 0xea, 0x87, 0x41, 0xa9, 0xe1, 0x06, 0x87, 0x9c, 0xa0, 0xf3, 0xa7, 0x02, 0xe6, 0xee, 0x62,
+	0xa0, 0x43, 0x50, 0xf9, 0x45, 0xe5, 0x0c, 0x9e, 0xb3, 0x86, 0x1b, 0xdc, 0x74, 0x02, 0xf4, 0x0a,
+	0x5a, 0x0f, 0xf3, 0x78, 0xf2, 0xe8, 0x47, 0xab, 0x85, 0x48, 0x56, 0xc3, 0x4d, 0x01, 0xb8, 0xab,
+	0x05, 0x7a, 0x09, 0x4d, 0x36, 0x23, 0x49, 0xc0, 0x69, 0x7c, 0x72, 0x03, 0xab, 0xc2, 0x76, 0x02,
+	0xf4, 0x1a, 0x40, 0xf2, 0x66, 0x84, 0xcd, 0xc4, 0x4e, 0xeb, 0x58, 0x66, 0xba, 0x22, 0x6c, 0x86,
+	0x0e, 0xa0, 0x2e, 0x0c, 0xb1, 0xb2, 0x3a, 0x96, 0x06, 0x3a, 0x05, 0x83, 0xd1, 0x28, 0xa0, 0x89,
+	0xbf, 0x5c, 0x3d, 0x70, 0xb9, 0x1b, 0xc2, 0xab, 0x4b, 0x70, 0x24, 0x30, 0x64, 0x81, 0xba, 0x24,
+	0xeb, 0x79, 0x4c, 0x02, 0xb1, 0x80, 0x3a, 0xce, 0x4d, 0x9e, 0x94, 0x2e, 0xe3, 0xc9, 0x4c, 0x6c,
+	0x55, 0x0d, 0x4b, 0xa3, 0xf3, 0xb3, 0x02, 0x7a, 0x79, 0xa3, 0xd1, 0xeb, 0x52, 0xd7, 0x7c, 0x58,
+	0x43, 0x5e, 0xad, 0xbc, 0xf3, 0x2f, 0x76, 0x9b, 0xa8, 0x14, 0x22, 0x6f, 0x37, 0xf2, 0xe9, 0xd6,
+	0x88, 0xd5, 0x22, 0xaa, 0x34, 0xe6, 0xf1, 0xa6, 0xd7, 0x5a, 0xe1, 0xcf, 0xa1, 0xce, 0x2f, 0x55,
+	0x78, 0xf1, 0xec, 0xcd, 0xf8, 0xf7, 0x8f, 0xe2, 0x99, 0xaa, 0xb5, 0xbf, 0x51, 0xf5, 0x14, 0x8c,
+	0x39, 0x25, 0xa5, 0x20, 0x79, 0x30, 0xba, 0x04, 0x9f, 0x4b, 0xdf, 0xd8, 0x96, 0xfe, 0x73, 0x68,
+	0x6f, 0x1e, 0x3a, 0x9f, 0x85, 0xd3, 0xec, 0x6c, 0x8c, 0x0d, 0x3a, 0x0e, 0xa7, 0xfc, 0x56, 0x70,
+	0x20, 0x0c, 0x44, 0x48, 0x53, 0xde, 0x0a, 0x89, 0x64, 0xee, 0x45, 0xd7, 0x27, 0xd3, 0x29, 0x0b,
+	0xa7, 0xcc, 0x6a, 0x49, 0xf7, 0xa2, 0xdb, 0x93, 0x00, 0x17, 0x60, 0xd1, 0xf5, 0x1f, 0xc2, 0x74,
+	0x41, 0x96, 0x16, 0x08, 0x6f, 0x73, 0xd1, 0xbd, 0x14, 0xb6, 0xe0, 0x5e, 0x14, 0x5c, 0x2d, 0xe3,
+	0x5e, 0x94, 0xb9, 0x17, 0x39, 0x57, 0xcf, 0xb8, 0x17, 0x19, 0xb7, 0xb8, 0x38, 0x46, 0xe9, 0xe2,
+	0xbc, 0x1d, 0x81, 0x56, 0x7a, 0x31, 0x91, 0x01, 0xad, 0xfe, 0xd0, 0x1d, 0xdb, 0xee, 0xf8, 0x6e,
+	0x6c, 0xee, 0xa1, 0x7d, 0x50, 0xc7, 0x5e, 0xef, 0xda, 0x71, 0xdf, 0x67, 0x1b, 0x6c, 0x40, 0x7d,
+	0x80, 0x7b, 0xee, 0x40, 0xee, 0x2e, 0x42, 0xd0, 0xee, 0xdf, 0x38, 0xb6, 0xeb, 0xf9, 0xe3, 0xbb,
+	0xd1, 0x68, 0x88, 0x3d, 0xb3, 0xfa, 0xf6, 0x0f, 0x05, 0xb4, 0xd2, 0x9b, 0x8a, 0xde, 0xc0, 0x27,
+	0xae, 0x7d, 0xef, 0x0e, 0x07, 0xb6, 0x7f, 0x69, 0xf7, 0xfa, 0x43, 0xd7, 0xcf, 0x53, 0xca, 0x47,
+	0x42, 0x87, 0x66, 0xcf, 0x75, 0x87, 0x77, 0x6e, 0xdf, 0x36, 0x15, 0xa4, 0x81, 0x3a, 0xc2, 0xf6,
+	0xa8, 0x87, 0x6d, 0xb3, 0xc2, 0x5d, 0x99, 0x31, 0x30, 0xab, 0x08, 0xa0, 0xd1, 0x1f, 0xde, 0xde,
+	0x3a, 0x9e, 0x59, 0x93, 0x7d, 0xf2, 0x6f, 0xcf, 0x1e, 0x98, 0x75, 0xd4, 0x06, 0xf8, 0xe0, 0xd8,
+	0xf7, 0xfd, 0xab, 0x9e, 0xfb, 0xde, 0x36, 0x1b, 0x3c, 0x8b, 0x6b, 0xdf, 0x73, 0xc8, 0x54, 0x11,
+	0x02, 0x10, 0x3d, 0xfb, 0x8e, 0xeb, 0x78, 0x26, 0x88, 0xa2, 0x07, 0xa0, 0x4b, 0x2c, 0xcb, 0xa8,
+	0x09, 0xf4, 0x10, 0xf6, 0x6f, 0x86, 0x9e, 0x67, 0xe3, 0x1f, 0x7d, 0x6c, 0xff, 0x70, 0x67, 0x8f,
+	0x3d, 0x53, 0xe7, 0x8e, 0x6e, 0x0f, 0x8c, 0xfe, 0x3c, 0xa4, 0x51, 0x9a, 0x69, 0x85, 0xbe, 0x04,
+	0x75, 0x94, 0xc4, 0x13, 0xca, 0x18, 0x32, 0x77, 0x7f, 0x49, 0x8e, 0x5e, 0x14, 0x48, 0xfe, 0xd0,
+	0x77, 0xf6, 0x1e, 0x1a, 0xe2, 0x9f, 0xc9, 0xc5, 0x5f, 0x03, 0x00, 0xb6, 0xbd, 0x79, 0xd3, 0xaa,
+	0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
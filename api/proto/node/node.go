@@ -8,11 +8,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/bls/ffi/go/bls"
 	"github.com/harmony-one/harmony/api/proto"
 	"github.com/harmony-one/harmony/block"
 	"github.com/harmony-one/harmony/consensus/engine"
 	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/crypto/hash"
 	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
 	"github.com/harmony-one/harmony/staking/slash"
 	staking "github.com/harmony-one/harmony/staking/types"
 	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
@@ -30,6 +33,7 @@ const (
 	PING       // node send ip/pki to register with leader
 	ShardState // Deprecated
 	Staking
+	ChainHead // signed chain head of a shard, published to the telemetry group
 )
 
 // BlockchainSyncMessage is a struct for blockchain sync message.
@@ -85,6 +89,7 @@ type Info struct {
 	PubKey []byte
 	Role   RoleType
 	PeerID libp2p_peer.ID // Peerstore ID
+	Addrs  []string       // multiaddrs advertised by the node, empty to dial IP and Port
 }
 
 func (info Info) String() string {
@@ -100,6 +105,9 @@ const (
 	CrossLink                       // used for crosslink from beacon chain to shard chain
 	Receipt                         // cross-shard transaction receipts
 	SlashCandidate                  // A report of a double-signing event
+	SignedSync                      // blocks along with their commit signature and bitmap
+	EpochState                      // beacon shard state of the next epoch with its signed header
+	CXDelivered                     // incoming receipts of a destination shard block with its signed header
 )
 
 var (
@@ -110,16 +118,24 @@ var (
 	txnB       = byte(Transaction)
 	sendB      = byte(Send)
 	stakingB   = byte(Staking)
+	beaconB    = byte(ChainHead)
 	syncB      = byte(Sync)
 	crossLinkB = byte(CrossLink)
 	receiptB   = byte(Receipt)
+	signedB    = byte(SignedSync)
+	epochB     = byte(EpochState)
+	deliveredB = byte(CXDelivered)
 	// H suffix means header
 	slashH           = []byte{nodeB, blockB, slashB}
 	transactionListH = []byte{nodeB, txnB, sendB}
 	stakingTxnListH  = []byte{nodeB, stakingB, sendB}
 	syncH            = []byte{nodeB, blockB, syncB}
+	signedSyncH      = []byte{nodeB, blockB, signedB}
+	epochStateH      = []byte{nodeB, blockB, epochB}
+	cxDeliveredH     = []byte{nodeB, blockB, deliveredB}
 	crossLinkH       = []byte{nodeB, blockB, crossLinkB}
 	cxReceiptH       = []byte{nodeB, blockB, receiptB}
+	headBeaconH      = []byte{nodeB, beaconB}
 )
 
 // SerializeBlockchainSyncMessage serializes BlockchainSyncMessage.
@@ -178,6 +194,158 @@ func ConstructBlocksSyncMessage(blocks []*types.Block) []byte {
 	return byteBuffer.Bytes()
 }
 
+// SignedBlock is a block along with the commit signature and bitmap of the
+// committee on it, which lets the receivers verify it without its child block.
+type SignedBlock struct {
+	Block              *types.Block
+	CommitSigAndBitmap []byte
+}
+
+// ConstructSignedBlocksSyncMessage constructs blocks sync message to send
+// blocks along with their commit signatures to other nodes
+func ConstructSignedBlocksSyncMessage(blocks []*SignedBlock) []byte {
+	byteBuffer := bytes.NewBuffer(signedSyncH)
+	blocksData, _ := rlp.EncodeToBytes(blocks)
+	byteBuffer.Write(blocksData)
+	return byteBuffer.Bytes()
+}
+
+// MaxSyncBlocks bounds the blocks decoded from a block sync message
+const MaxSyncBlocks = 1024
+
+// MaxSyncBlockSize bounds the encoded size of a block decoded from a block sync
+// message
+const MaxSyncBlockSize = 8 * 1024 * 1024
+
+// DecodeBlocksSyncMessage decodes the blocks of a block sync message payload one
+// at a time, handing each to fn as soon as decoded. The blocks handed to fn
+// before an error, whether in decoding or returned by fn, are not undone.
+func DecodeBlocksSyncMessage(payload []byte, fn func(*types.Block) error) error {
+	return decodeSyncList(payload, func(s *rlp.Stream) error {
+		block := &types.Block{}
+		if err := s.Decode(block); err != nil {
+			return err
+		}
+		return fn(block)
+	})
+}
+
+// DecodeSignedBlocksSyncMessage decodes the blocks of a signed block sync
+// message payload one at a time, handing each to fn as soon as decoded
+func DecodeSignedBlocksSyncMessage(payload []byte, fn func(*SignedBlock) error) error {
+	return decodeSyncList(payload, func(s *rlp.Stream) error {
+		sb := &SignedBlock{}
+		if err := s.Decode(sb); err != nil {
+			return err
+		}
+		return fn(sb)
+	})
+}
+
+// decodeSyncList decodes the RLP list of a sync message payload element by
+// element, so that a list longer than MaxSyncBlocks, or holding an element
+// larger than MaxSyncBlockSize, is rejected before being decoded into memory
+func decodeSyncList(payload []byte, decode func(s *rlp.Stream) error) error {
+	s := rlp.NewStream(bytes.NewReader(payload), uint64(len(payload)))
+	if _, err := s.List(); err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		_, size, err := s.Kind()
+		if err == rlp.EOL {
+			return s.ListEnd()
+		}
+		if err != nil {
+			return err
+		}
+		if i >= MaxSyncBlocks {
+			return fmt.Errorf("more than %d blocks in sync message", MaxSyncBlocks)
+		}
+		if size > MaxSyncBlockSize {
+			return fmt.Errorf("block %d of sync message is %d bytes, over %d", i, size, MaxSyncBlockSize)
+		}
+		if err := decode(s); err != nil {
+			return err
+		}
+	}
+}
+
+// EpochStateProof is the header of the last beacon block of an epoch, holding
+// the shard state of the next epoch, along with the commit signature and
+// bitmap of the beacon committee on it. It lets the shard nodes verify the
+// shard state without the beacon block.
+type EpochStateProof struct {
+	Header             *block.Header
+	CommitSigAndBitmap []byte
+}
+
+// ConstructEpochStateMessage constructs the message pushing the beacon shard
+// state of the next epoch to the shard nodes
+func ConstructEpochStateMessage(proof *EpochStateProof) []byte {
+	byteBuffer := bytes.NewBuffer(epochStateH)
+	proofData, _ := rlp.EncodeToBytes(proof)
+	byteBuffer.Write(proofData)
+	return byteBuffer.Bytes()
+}
+
+// CXDeliveryProof is the header of a destination shard block, along with the
+// commit signature and bitmap of its committee and the incoming receipts the
+// header commits to. It lets the source shards learn their cross-shard
+// transfers were delivered without the destination shard chain.
+type CXDeliveryProof struct {
+	Header             *block.Header
+	CommitSigAndBitmap []byte
+	IncomingReceipts   types.CXReceiptsProofs
+}
+
+// ConstructCXDeliveredMessage constructs the message notifying the source
+// shards of the cross-shard receipts spent by a destination shard block
+func ConstructCXDeliveredMessage(proof *CXDeliveryProof) []byte {
+	byteBuffer := bytes.NewBuffer(cxDeliveredH)
+	proofData, _ := rlp.EncodeToBytes(proof)
+	byteBuffer.Write(proofData)
+	return byteBuffer.Bytes()
+}
+
+// HeadBeacon is the head of the chain of a shard as seen by a validator at
+// the given unix time, signed with one of its BLS keys
+type HeadBeacon struct {
+	ShardID   uint32
+	Height    uint64
+	Hash      common.Hash
+	ViewID    uint64
+	Time      uint64
+	PubKey    shard.BLSPublicKey
+	Signature []byte
+}
+
+// SigningHash returns the hash signed by the validator, covering all the
+// fields of the beacon but the signature
+func (b *HeadBeacon) SigningHash() common.Hash {
+	return hash.FromRLPNew256([]interface{}{
+		b.ShardID, b.Height, b.Hash, b.ViewID, b.Time, b.PubKey,
+	})
+}
+
+// Sign sets the key of the beacon and signs it with the given BLS key
+func (b *HeadBeacon) Sign(key *bls.SecretKey) error {
+	if err := b.PubKey.FromLibBLSPublicKey(key.GetPublicKey()); err != nil {
+		return err
+	}
+	hash := b.SigningHash()
+	b.Signature = key.SignHash(hash[:]).Serialize()
+	return nil
+}
+
+// ConstructHeadBeaconMessage constructs the message publishing a head beacon
+// to the telemetry group
+func ConstructHeadBeaconMessage(beacon *HeadBeacon) []byte {
+	byteBuffer := bytes.NewBuffer(headBeaconH)
+	beaconData, _ := rlp.EncodeToBytes(beacon)
+	byteBuffer.Write(beaconData)
+	return byteBuffer.Bytes()
+}
+
 // ConstructSlashMessage ..
 func ConstructSlashMessage(witnesses slash.Records) []byte {
 	byteBuffer := bytes.NewBuffer(slashH)
@@ -1,6 +1,8 @@
 package node
 
 import (
+	"bytes"
+	"errors"
 	"math/big"
 	"reflect"
 	"strings"
@@ -9,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	blockfactory "github.com/harmony-one/harmony/block/factory"
 	"github.com/harmony-one/harmony/core/state"
@@ -89,6 +92,118 @@ func TestConstructBlocksSyncMessage(t *testing.T) {
 
 }
 
+func TestConstructSignedBlocksSyncMessage(t *testing.T) {
+	head := blockfactory.NewTestHeader().With().
+		Number(new(big.Int).SetUint64(uint64(10000))).
+		ShardID(0).
+		Header()
+	blocks := []*SignedBlock{{
+		Block:              types.NewBlock(head, nil, nil, nil, nil, nil),
+		CommitSigAndBitmap: []byte{1, 2, 3},
+	}}
+
+	buf := ConstructSignedBlocksSyncMessage(blocks)
+	if len(buf) <= len(signedSyncH) || BlockMessageType(buf[2]) != SignedSync {
+		t.Fatal("Failed to contruct signed block sync message")
+	}
+	decoded := []*SignedBlock{}
+	if err := rlp.DecodeBytes(buf[3:], &decoded); err != nil {
+		t.Fatalf("cannot decode signed block sync message: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Block.Hash() != blocks[0].Block.Hash() ||
+		!bytes.Equal(decoded[0].CommitSigAndBitmap, blocks[0].CommitSigAndBitmap) {
+		t.Error("signed block sync message mismatch")
+	}
+}
+
+func TestDecodeBlocksSyncMessage(t *testing.T) {
+	blocks := []*types.Block{}
+	for i := 0; i < 3; i++ {
+		head := blockfactory.NewTestHeader().With().
+			Number(new(big.Int).SetUint64(uint64(i))).
+			Header()
+		blocks = append(blocks, types.NewBlock(head, nil, nil, nil, nil, nil))
+	}
+	buf := ConstructBlocksSyncMessage(blocks)
+	decoded := []*types.Block{}
+	if err := DecodeBlocksSyncMessage(buf[3:], func(block *types.Block) error {
+		decoded = append(decoded, block)
+		return nil
+	}); err != nil {
+		t.Fatalf("cannot decode block sync message: %v", err)
+	}
+	if len(decoded) != len(blocks) {
+		t.Fatalf("decoded %d blocks, expected %d", len(decoded), len(blocks))
+	}
+	for i := range blocks {
+		if decoded[i].Hash() != blocks[i].Hash() {
+			t.Errorf("block %d mismatch", i)
+		}
+	}
+
+	if err := DecodeBlocksSyncMessage(buf[3:len(buf)-1], func(*types.Block) error {
+		return nil
+	}); err == nil {
+		t.Error("truncated block sync message decoded")
+	}
+
+	// decoding stops at the first block rejected
+	count := 0
+	errRejected := errors.New("rejected")
+	if err := DecodeBlocksSyncMessage(buf[3:], func(*types.Block) error {
+		count++
+		return errRejected
+	}); err != errRejected || count != 1 {
+		t.Errorf("decoded %d blocks with error %v, expected 1 with %v", count, err, errRejected)
+	}
+
+	tooMany := make([]*types.Block, MaxSyncBlocks+1)
+	for i := range tooMany {
+		tooMany[i] = blocks[0]
+	}
+	count = 0
+	if err := DecodeBlocksSyncMessage(ConstructBlocksSyncMessage(tooMany)[3:], func(*types.Block) error {
+		count++
+		return nil
+	}); err == nil {
+		t.Error("block sync message over MaxSyncBlocks decoded")
+	}
+	if count != MaxSyncBlocks {
+		t.Errorf("decoded %d blocks, expected %d", count, MaxSyncBlocks)
+	}
+
+	huge, _ := rlp.EncodeToBytes([][]byte{make([]byte, MaxSyncBlockSize+1)})
+	if err := DecodeBlocksSyncMessage(huge, func(*types.Block) error {
+		t.Error("block over MaxSyncBlockSize handed over")
+		return nil
+	}); err == nil {
+		t.Error("block sync message with a block over MaxSyncBlockSize decoded")
+	}
+}
+
+func TestConstructEpochStateMessage(t *testing.T) {
+	head := blockfactory.NewTestHeader().With().
+		Number(new(big.Int).SetUint64(uint64(10000))).
+		ShardID(0).
+		ShardState([]byte{4, 5, 6}).
+		Header()
+	proof := &EpochStateProof{Header: head, CommitSigAndBitmap: []byte{1, 2, 3}}
+
+	buf := ConstructEpochStateMessage(proof)
+	if len(buf) <= len(epochStateH) || BlockMessageType(buf[2]) != EpochState {
+		t.Fatal("Failed to contruct epoch state message")
+	}
+	decoded := &EpochStateProof{}
+	if err := rlp.DecodeBytes(buf[3:], decoded); err != nil {
+		t.Fatalf("cannot decode epoch state message: %v", err)
+	}
+	if decoded.Header.Hash() != head.Hash() ||
+		!bytes.Equal(decoded.Header.ShardState(), head.ShardState()) ||
+		!bytes.Equal(decoded.CommitSigAndBitmap, proof.CommitSigAndBitmap) {
+		t.Error("epoch state message mismatch")
+	}
+}
+
 func TestRoleTypeToString(t *testing.T) {
 	validator := ValidatorRole
 	client := ClientRole
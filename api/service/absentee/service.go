@@ -0,0 +1,296 @@
+// Package absentee records the committee members absent from the commit
+// signature of each block, and aggregates them into per-epoch statistics for
+// the availability of the validators and for alerting their operators.
+package absentee
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// Chain is the chain whose committed blocks are checked
+type Chain interface {
+	ShardID() uint32
+	ChainDb() ethdb.Database
+	GetHeaderByHash(hash common.Hash) *block.Header
+	ReadShardState(epoch *big.Int) (*shard.State, error)
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+// AlertFunc is called when a key of the node goes absent from the commit of a
+// block after having signed the previous one
+type AlertFunc func(key shard.BLSPublicKey, blockNum uint64)
+
+// Signer is the absence record of a committee member over an epoch
+type Signer struct {
+	Key        shard.BLSPublicKey
+	Absent     uint64 // blocks whose commit the key did not sign
+	LastAbsent uint64 // last block whose commit the key did not sign
+}
+
+// Stats are the absentee statistics of the committee of an epoch, with the
+// signers in the order of the committee
+type Stats struct {
+	Epoch      uint64
+	Blocks     uint64 // blocks whose commit bitmap was checked
+	FirstBlock uint64
+	LastBlock  uint64
+	Signers    []Signer
+}
+
+// Read returns the absentee statistics of the given epoch from the database
+func Read(db ethdb.Database, epoch uint64) (*Stats, error) {
+	data, err := rawdb.ReadAbsentees(db, epoch)
+	if err != nil {
+		return nil, errors.Errorf("no absentee statistics of epoch %d", epoch)
+	}
+	stats := &Stats{}
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Absentee is the exported absence record of a committee member
+type Absentee struct {
+	Key        string  `json:"bls-public-key"`
+	Absent     uint64  `json:"absent"`
+	Rate       float64 `json:"absence-rate"`
+	LastAbsent uint64  `json:"last-absent"`
+}
+
+// Report is the exported form of the statistics, with the members absent at
+// least once, most absent first
+type Report struct {
+	Epoch      uint64     `json:"epoch"`
+	Blocks     uint64     `json:"blocks"`
+	FirstBlock uint64     `json:"first-block"`
+	LastBlock  uint64     `json:"last-block"`
+	Committee  int        `json:"committee-size"`
+	Absentees  []Absentee `json:"absentees"`
+}
+
+// Export returns the report of the statistics
+func (s *Stats) Export() *Report {
+	r := &Report{
+		Epoch:      s.Epoch,
+		Blocks:     s.Blocks,
+		FirstBlock: s.FirstBlock,
+		LastBlock:  s.LastBlock,
+		Committee:  len(s.Signers),
+		Absentees:  []Absentee{},
+	}
+	for _, signer := range s.Signers {
+		if signer.Absent == 0 {
+			continue
+		}
+		a := Absentee{
+			Key:        signer.Key.Hex(),
+			Absent:     signer.Absent,
+			LastAbsent: signer.LastAbsent,
+		}
+		if s.Blocks > 0 {
+			a.Rate = float64(signer.Absent) / float64(s.Blocks)
+		}
+		r.Absentees = append(r.Absentees, a)
+	}
+	sort.SliceStable(r.Absentees, func(i, j int) bool {
+		return r.Absentees[i].Absent > r.Absentees[j].Absent
+	})
+	return r
+}
+
+// Service checks the commit bitmap carried by each block committed to the
+// chain against the committee which signed its parent
+type Service struct {
+	chain       Chain
+	ownKeys     func() []shard.BLSPublicKey
+	alert       AlertFunc
+	messageChan chan *msg_pb.Message
+	stopChan    chan struct{}
+	stoppedChan chan struct{}
+
+	lock    sync.Mutex
+	current *Stats
+}
+
+// New returns the absentee service of the chain, alerting on the absences of
+// the keys returned by ownKeys
+func New(chain Chain, ownKeys func() []shard.BLSPublicKey, alert AlertFunc) *Service {
+	return &Service{chain: chain, ownKeys: ownKeys, alert: alert}
+}
+
+// bitEnabled tells whether the bit of the given index is set in a bitmap laid
+// out as the bls.Mask ones
+func bitEnabled(bitmap []byte, i int) bool {
+	if i>>3 >= len(bitmap) {
+		return false
+	}
+	return bitmap[i>>3]&(byte(1)<<uint(i&7)) != 0
+}
+
+// statsOf returns the statistics of the epoch, read back if recorded before
+// the node restarted
+func (s *Service) statsOf(epoch uint64, committee *shard.Committee) *Stats {
+	if s.current != nil && s.current.Epoch == epoch {
+		return s.current
+	}
+	stats, err := Read(s.chain.ChainDb(), epoch)
+	if err != nil || len(stats.Signers) != len(committee.Slots) {
+		stats = &Stats{Epoch: epoch, Signers: make([]Signer, len(committee.Slots))}
+		for i, slot := range committee.Slots {
+			stats.Signers[i].Key = slot.BLSPublicKey
+		}
+	}
+	s.current = stats
+	return stats
+}
+
+// Record records the members of the committee absent from the commit bitmap
+// of the block of the given number and epoch. It returns their keys, and the
+// keys among them which signed the commit of the previous block.
+func (s *Service) Record(
+	blockNum, epoch uint64, committee *shard.Committee, bitmap []byte,
+) (absent, newlyAbsent []shard.BLSPublicKey) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stats := s.statsOf(epoch, committee)
+	if stats.Blocks > 0 && blockNum <= stats.LastBlock {
+		return nil, nil // recorded already
+	}
+	for i := range stats.Signers {
+		if bitEnabled(bitmap, i) {
+			continue
+		}
+		signer := &stats.Signers[i]
+		if signer.Absent == 0 || signer.LastAbsent+1 != blockNum {
+			newlyAbsent = append(newlyAbsent, signer.Key)
+		}
+		signer.Absent++
+		signer.LastAbsent = blockNum
+		absent = append(absent, signer.Key)
+	}
+	if stats.Blocks == 0 {
+		stats.FirstBlock = blockNum
+	}
+	stats.Blocks++
+	stats.LastBlock = blockNum
+
+	data, err := rlp.EncodeToBytes(stats)
+	if err == nil {
+		err = rawdb.WriteAbsentees(s.chain.ChainDb(), epoch, data)
+	}
+	if err != nil {
+		utils.Logger().Warn().Err(err).
+			Uint64("epoch", epoch).
+			Msg("[Absentee] Cannot store absentee statistics")
+	}
+	return absent, newlyAbsent
+}
+
+// check records the absentees of the parent of the block, whose commit bitmap
+// the block carries, alerting on the keys of the node newly absent
+func (s *Service) check(header *block.Header) error {
+	bitmap := header.LastCommitBitmap()
+	if header.Number().Sign() == 0 || len(bitmap) == 0 {
+		return nil
+	}
+	parent := s.chain.GetHeaderByHash(header.ParentHash())
+	if parent == nil {
+		return errors.Errorf("no parent of block %d", header.Number().Uint64())
+	}
+	shardState, err := s.chain.ReadShardState(parent.Epoch())
+	if err != nil {
+		return err
+	}
+	committee, err := shardState.FindCommitteeByID(s.chain.ShardID())
+	if err != nil {
+		return err
+	}
+	blockNum := parent.Number().Uint64()
+	_, newlyAbsent := s.Record(blockNum, parent.Epoch().Uint64(), committee, bitmap)
+	if len(newlyAbsent) == 0 || s.ownKeys == nil {
+		return nil
+	}
+	own := map[shard.BLSPublicKey]struct{}{}
+	for _, key := range s.ownKeys() {
+		own[key] = struct{}{}
+	}
+	for _, key := range newlyAbsent {
+		if _, ok := own[key]; !ok {
+			continue
+		}
+		utils.Logger().Warn().
+			Str("key", key.Hex()).
+			Uint64("blockNum", blockNum).
+			Msg("[Absentee] Own key absent from the commit signature")
+		if s.alert != nil {
+			s.alert(key, blockNum)
+		}
+	}
+	return nil
+}
+
+// StartService starts the absentee service.
+func (s *Service) StartService() {
+	utils.Logger().Info().Msg("Starting absentee service.")
+	s.stopChan = make(chan struct{})
+	s.stoppedChan = make(chan struct{})
+	go s.run()
+}
+
+func (s *Service) run() {
+	defer close(s.stoppedChan)
+	events := make(chan core.ChainEvent, 16)
+	sub := s.chain.SubscribeChainEvent(events)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case ev := <-events:
+			if err := s.check(ev.Block.Header()); err != nil {
+				utils.Logger().Debug().Err(err).
+					Uint64("blockNum", ev.Block.NumberU64()).
+					Msg("[Absentee] Cannot check the commit bitmap")
+			}
+		case <-sub.Err():
+			return
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// StopService stops the absentee service.
+func (s *Service) StopService() {
+	utils.Logger().Info().Msg("Stopping absentee service.")
+	close(s.stopChan)
+	<-s.stoppedChan
+	utils.Logger().Info().Msg("Absentee service stopped.")
+}
+
+// NotifyService notify service
+func (s *Service) NotifyService(params map[string]interface{}) {}
+
+// SetMessageChan sets up message channel to service.
+func (s *Service) SetMessageChan(messageChan chan *msg_pb.Message) {
+	s.messageChan = messageChan
+}
+
+// APIs for the services.
+func (s *Service) APIs() []rpc.API {
+	return nil
+}
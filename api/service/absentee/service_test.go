@@ -0,0 +1,62 @@
+package absentee
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/shard"
+)
+
+// memChain is a chain of which only the database is used
+type memChain struct {
+	Chain
+	db ethdb.Database
+}
+
+func (c memChain) ChainDb() ethdb.Database { return c.db }
+
+func TestRecord(t *testing.T) {
+	committee := &shard.Committee{Slots: make(shard.SlotList, 3)}
+	for i := range committee.Slots {
+		committee.Slots[i].BLSPublicKey[0] = byte(i + 1)
+	}
+	chain := memChain{db: ethdb.NewMemDatabase()}
+	s := New(chain, nil, nil)
+
+	// the third member is absent from both blocks, the second from the first
+	absent, newly := s.Record(10, 1, committee, []byte{0x01})
+	if len(absent) != 2 || len(newly) != 2 {
+		t.Fatalf("absent %v, newly absent %v", absent, newly)
+	}
+	absent, newly = s.Record(11, 1, committee, []byte{0x03})
+	if len(absent) != 1 || len(newly) != 0 {
+		t.Fatalf("absent %v, newly absent %v", absent, newly)
+	}
+	if absent, _ := s.Record(11, 1, committee, []byte{0x00}); absent != nil {
+		t.Fatal("block recorded twice")
+	}
+
+	stats, err := Read(chain.db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := stats.Export()
+	if report.Blocks != 2 || report.FirstBlock != 10 || report.LastBlock != 11 || report.Committee != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(report.Absentees) != 2 ||
+		report.Absentees[0].Key != committee.Slots[2].BLSPublicKey.Hex() ||
+		report.Absentees[0].Absent != 2 || report.Absentees[0].Rate != 1 ||
+		report.Absentees[1].Absent != 1 || report.Absentees[1].LastAbsent != 10 {
+		t.Fatalf("unexpected absentees %+v", report.Absentees)
+	}
+
+	// the statistics are read back after a restart
+	restarted := New(chain, nil, nil)
+	if _, newly := restarted.Record(12, 1, committee, []byte{0x03}); len(newly) != 0 {
+		t.Fatalf("absence after restart not seen as consecutive: %v", newly)
+	}
+	if stats, _ := Read(chain.db, 1); stats.Blocks != 3 {
+		t.Fatalf("%d blocks recorded, want 3", stats.Blocks)
+	}
+}
@@ -0,0 +1,182 @@
+package beaconsig
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultCapacity is the number of the most recent beacon blocks whose
+	// commit signature is kept
+	DefaultCapacity = 1024
+	// DefaultInterval is the interval between two fetches of the commit
+	// signature of the beacon head
+	DefaultInterval = 10 * time.Second
+)
+
+var (
+	// ErrNoCommitSig is returned when the commit signature of a beacon block
+	// is neither cached nor fetched
+	ErrNoCommitSig = errors.New("beacon commit signature not available")
+
+	hitCounter   = metrics.NewRegisteredCounter("beaconsig/hits", nil)
+	missCounter  = metrics.NewRegisteredCounter("beaconsig/misses", nil)
+	fetchCounter = metrics.NewRegisteredCounter("beaconsig/fetched", nil)
+)
+
+// Fetcher returns the commit signature and bitmap of the beacon block of the
+// given number, verified against the beacon committee
+type Fetcher func(number uint64) ([]byte, error)
+
+// Service caches the commit signatures and bitmaps of the recent beacon
+// blocks, received with the broadcast beacon blocks or fetched from the beacon
+// sync peers, for the shard nodes to verify the beacon crosslinks without
+// storing the beacon blocks
+type Service struct {
+	capacity    uint64
+	interval    time.Duration
+	fetch       Fetcher
+	head        func() uint64
+	messageChan chan *msg_pb.Message
+	stopChan    chan struct{}
+	stoppedChan chan struct{}
+
+	mutex   sync.RWMutex
+	sigs    map[uint64][]byte
+	highest uint64
+}
+
+// New returns a commit signature service keeping the signatures of the given
+// number of most recent beacon blocks, fetching the missing ones with fetch
+// and prefetching the signature of the beacon block numbered by head
+func New(capacity uint64, fetch Fetcher, head func() uint64) *Service {
+	if capacity == 0 {
+		capacity = DefaultCapacity
+	}
+	return &Service{
+		capacity: capacity,
+		interval: DefaultInterval,
+		fetch:    fetch,
+		head:     head,
+		sigs:     map[uint64][]byte{},
+	}
+}
+
+// Add caches the verified commit signature and bitmap of the beacon block of
+// the given number, unless older than the blocks kept
+func (s *Service) Add(number uint64, sigAndBitmap []byte) {
+	if len(sigAndBitmap) <= shard.BLSSignatureSizeInBytes {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if number+s.capacity <= s.highest {
+		return
+	}
+	s.sigs[number] = append([]byte{}, sigAndBitmap...)
+	if number > s.highest {
+		s.highest = number
+		for n := range s.sigs {
+			if n+s.capacity <= s.highest {
+				delete(s.sigs, n)
+			}
+		}
+	}
+}
+
+// cached returns the cached commit signature of the beacon block
+func (s *Service) cached(number uint64) ([]byte, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	sig, ok := s.sigs[number]
+	return sig, ok
+}
+
+// GetBeaconCommitSig returns the commit signature and bitmap of the beacon
+// block of the given number, fetched if not cached
+func (s *Service) GetBeaconCommitSig(number uint64) ([]byte, error) {
+	if sig, ok := s.cached(number); ok {
+		hitCounter.Inc(1)
+		return sig, nil
+	}
+	missCounter.Inc(1)
+	if s.fetch == nil {
+		return nil, ErrNoCommitSig
+	}
+	sig, err := s.fetch(number)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot fetch the commit signature of beacon block %d", number)
+	}
+	if len(sig) <= shard.BLSSignatureSizeInBytes {
+		return nil, ErrNoCommitSig
+	}
+	fetchCounter.Inc(1)
+	s.Add(number, sig)
+	return sig, nil
+}
+
+// prefetch fetches the commit signature of the beacon head if not cached
+func (s *Service) prefetch() {
+	if s.head == nil {
+		return
+	}
+	number := s.head()
+	if number == 0 {
+		return
+	}
+	if _, err := s.GetBeaconCommitSig(number); err != nil {
+		utils.Logger().Debug().Err(err).
+			Uint64("blockNum", number).
+			Msg("[BeaconSig] cannot prefetch the commit signature of the beacon head")
+	}
+}
+
+// StartService starts the beacon commit signature service.
+func (s *Service) StartService() {
+	utils.Logger().Info().Msg("Starting beacon commit signature service.")
+	s.stopChan = make(chan struct{})
+	s.stoppedChan = make(chan struct{})
+	go s.run()
+}
+
+func (s *Service) run() {
+	defer close(s.stoppedChan)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.prefetch()
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// StopService stops the beacon commit signature service.
+func (s *Service) StopService() {
+	utils.Logger().Info().Msg("Stopping beacon commit signature service.")
+	close(s.stopChan)
+	<-s.stoppedChan
+	utils.Logger().Info().Msg("Beacon commit signature service stopped.")
+}
+
+// NotifyService notify service
+func (s *Service) NotifyService(params map[string]interface{}) {}
+
+// SetMessageChan sets up message channel to service.
+func (s *Service) SetMessageChan(messageChan chan *msg_pb.Message) {
+	s.messageChan = messageChan
+}
+
+// APIs for the services.
+func (s *Service) APIs() []rpc.API {
+	return nil
+}
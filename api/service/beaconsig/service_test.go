@@ -0,0 +1,56 @@
+package beaconsig
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/harmony-one/harmony/shard"
+)
+
+func sigOf(number uint64) []byte {
+	sig := make([]byte, shard.BLSSignatureSizeInBytes+1)
+	sig[0] = byte(number)
+	return sig
+}
+
+func TestGetBeaconCommitSig(t *testing.T) {
+	fetched := []uint64{}
+	s := New(4, func(number uint64) ([]byte, error) {
+		fetched = append(fetched, number)
+		if number == 100 {
+			return nil, errors.New("unknown block")
+		}
+		return sigOf(number), nil
+	}, nil)
+
+	s.Add(10, sigOf(10))
+	if sig, err := s.GetBeaconCommitSig(10); err != nil || !bytes.Equal(sig, sigOf(10)) {
+		t.Fatalf("cached signature not returned: %x, %v", sig, err)
+	}
+	if len(fetched) != 0 {
+		t.Fatal("cached signature fetched")
+	}
+	if sig, err := s.GetBeaconCommitSig(11); err != nil || !bytes.Equal(sig, sigOf(11)) {
+		t.Fatalf("fetched signature not returned: %x, %v", sig, err)
+	}
+	if _, err := s.GetBeaconCommitSig(11); err != nil || len(fetched) != 1 {
+		t.Fatal("fetched signature not cached")
+	}
+	if _, err := s.GetBeaconCommitSig(100); err == nil {
+		t.Fatal("no error on a failed fetch")
+	}
+
+	s.Add(20, sigOf(20))
+	if _, ok := s.cached(10); ok {
+		t.Fatal("signature older than the capacity kept")
+	}
+	s.Add(12, sigOf(12))
+	if _, ok := s.cached(12); ok {
+		t.Fatal("signature older than the capacity added")
+	}
+	s.Add(19, []byte{1})
+	if _, ok := s.cached(19); ok {
+		t.Fatal("signature without bitmap added")
+	}
+}
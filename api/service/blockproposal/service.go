@@ -1,11 +1,15 @@
 package blockproposal
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/rpc"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
+var errProposalLoopExited = errors.New("block proposal loop exited")
+
 // Service is a block proposal service.
 type Service struct {
 	stopChan              chan struct{}
@@ -46,6 +50,22 @@ func (s *Service) StopService() {
 	utils.Logger().Info().Msg("Role conversion stopped.")
 }
 
+// Health returns an error if the block proposal loop exited without being stopped.
+func (s *Service) Health() error {
+	select {
+	case <-s.stoppedChan:
+		return errProposalLoopExited
+	default:
+		return nil
+	}
+}
+
+// Restart restarts the block proposal loop after it exited.
+func (s *Service) Restart() error {
+	s.StartService()
+	return nil
+}
+
 // NotifyService notify service
 func (s *Service) NotifyService(params map[string]interface{}) {}
 
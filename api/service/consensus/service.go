@@ -1,16 +1,20 @@
 package consensus
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/rpc"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/consensus"
-	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/pipe"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
+var errConsensusLoopExited = errors.New("consensus main loop exited")
+
 // Service is the consensus service.
 type Service struct {
-	blockChannel chan *types.Block // The channel to receive new blocks from Node
+	blockChannel *pipe.Pipe // The pipe to receive new blocks from Node
 	consensus    *consensus.Consensus
 	stopChan     chan struct{}
 	stoppedChan  chan struct{}
@@ -19,7 +23,7 @@ type Service struct {
 }
 
 // New returns consensus service.
-func New(blockChannel chan *types.Block, consensus *consensus.Consensus, startChan chan struct{}) *Service {
+func New(blockChannel *pipe.Pipe, consensus *consensus.Consensus, startChan chan struct{}) *Service {
 	return &Service{blockChannel: blockChannel, consensus: consensus, startChan: startChan}
 }
 
@@ -40,6 +44,25 @@ func (s *Service) StopService() {
 	utils.Logger().Info().Msg("Consensus service stopped.")
 }
 
+// Health returns an error if the consensus loop exited without being stopped.
+func (s *Service) Health() error {
+	select {
+	case <-s.stoppedChan:
+		return errConsensusLoopExited
+	default:
+		return nil
+	}
+}
+
+// Restart restarts the consensus loop after it exited, leaving the
+// randomness loop running.
+func (s *Service) Restart() error {
+	s.stopChan = make(chan struct{})
+	s.stoppedChan = make(chan struct{})
+	s.consensus.Start(s.blockChannel, s.stopChan, s.stoppedChan, s.startChan)
+	return nil
+}
+
 // NotifyService notify service
 func (s *Service) NotifyService(params map[string]interface{}) {}
 
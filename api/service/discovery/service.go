@@ -75,8 +75,8 @@ func (s *Service) Run() {
 func (s *Service) contactP2pPeers() {
 
 	nodeConfig := nodeconfig.GetShardConfig(s.config.ShardID)
-	// Don't send ping message for Explorer Node
-	if nodeConfig.Role() == nodeconfig.ExplorerNode {
+	// Don't send ping message for Explorer and RPC Node
+	if r := nodeConfig.Role(); r == nodeconfig.ExplorerNode || r == nodeconfig.RPCNode {
 		return
 	}
 	pingMsg := proto_discovery.NewPingMessage(s.host.GetSelfPeer(), s.config.IsClient)
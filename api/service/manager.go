@@ -1,6 +1,7 @@
 package service
 
 import (
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
@@ -29,6 +30,10 @@ const (
 	BlockProposal
 	NetworkInfo
 	PeerDiscovery
+	WalletWatch
+	StorageGuard
+	BeaconCommitSig
+	Absentee
 )
 
 func (t Type) String() string {
@@ -45,6 +50,14 @@ func (t Type) String() string {
 		return "NetworkInfo"
 	case PeerDiscovery:
 		return "PeerDiscovery"
+	case WalletWatch:
+		return "WalletWatch"
+	case StorageGuard:
+		return "StorageGuard"
+	case BeaconCommitSig:
+		return "BeaconCommitSig"
+	case Absentee:
+		return "Absentee"
 	default:
 		return "Unknown"
 	}
@@ -78,6 +91,16 @@ type Interface interface {
 type Manager struct {
 	services      map[Type]Interface
 	actionChannel chan *Action
+
+	// serializes the restarts of the supervised services with their starts
+	// and stops
+	lifecycle sync.Mutex
+
+	mu          sync.Mutex // guards the supervision state below
+	supervising bool
+	health      map[Type]error
+	restarts    map[Type]*restartState
+	stopped     map[Type]bool
 }
 
 // GetServices returns all registered services.
@@ -128,9 +151,15 @@ func (m *Manager) TakeAction(action *Action) {
 	if service, ok := m.services[action.ServiceType]; ok {
 		switch action.Action {
 		case Start:
+			m.lifecycle.Lock()
+			m.setStopped(action.ServiceType, false)
 			service.StartService()
+			m.lifecycle.Unlock()
 		case Stop:
+			m.lifecycle.Lock()
+			m.setStopped(action.ServiceType, true)
 			service.StopService()
+			m.lifecycle.Unlock()
 		case Notify:
 			service.NotifyService(action.Params)
 		}
@@ -177,6 +206,9 @@ func (m *Manager) SetupServiceMessageChan(
 // StopService stops service with type t.
 func (m *Manager) StopService(t Type) {
 	if service, ok := m.services[t]; ok {
+		m.lifecycle.Lock()
+		defer m.lifecycle.Unlock()
+		m.setStopped(t, true)
 		service.StopService()
 	}
 }
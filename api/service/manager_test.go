@@ -1,8 +1,11 @@
 package service
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/rpc"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 )
@@ -28,3 +31,117 @@ func TestInit(t *testing.T) {
 		)
 	}
 }
+
+type crashingService struct {
+	health   error
+	restarts int
+}
+
+func (s *crashingService) StartService()                               {}
+func (s *crashingService) SetMessageChan(msgChan chan *msg_pb.Message) {}
+func (s *crashingService) StopService()                                {}
+func (s *crashingService) NotifyService(map[string]interface{})        {}
+func (s *crashingService) APIs() []rpc.API                             { return nil }
+func (s *crashingService) Health() error                               { return s.health }
+func (s *crashingService) Restart() error                              { s.restarts++; return nil }
+
+func TestSuperviseRestartsWithBackoff(t *testing.T) {
+	m := &Manager{}
+	m.SetupServiceManager()
+	s := &crashingService{health: errors.New("crashed")}
+	m.RegisterService(Consensus, s)
+
+	now := time.Now()
+	m.checkServices(now)
+	m.checkServices(now.Add(restartBackoffMin / 2))
+	if s.restarts != 1 {
+		t.Fatalf("restarts within backoff: got %d, want 1", s.restarts)
+	}
+	m.checkServices(now.Add(restartBackoffMin))
+	if s.restarts != 2 {
+		t.Fatalf("restarts after backoff: got %d, want 2", s.restarts)
+	}
+	if err := m.Health()[Consensus]; err == nil {
+		t.Error("unhealthy service reported healthy")
+	}
+
+	m.StopService(Consensus)
+	m.checkServices(now.Add(restartBackoffMax))
+	if s.restarts != 2 {
+		t.Errorf("stopped service restarted: got %d restarts, want 2", s.restarts)
+	}
+	if _, ok := m.Health()[Consensus]; ok {
+		t.Error("stopped service still reported")
+	}
+}
+
+// panickingService panics when checked, then when restarted
+type panickingService struct {
+	crashingService
+}
+
+func (s *panickingService) Health() error  { panic("health check") }
+func (s *panickingService) Restart() error { s.restarts++; panic("restart") }
+
+func TestSuperviseRecoversPanics(t *testing.T) {
+	m := &Manager{}
+	m.SetupServiceManager()
+	s := &panickingService{}
+	m.RegisterService(Consensus, s)
+
+	m.checkServices(time.Now())
+	if err := m.Health()[Consensus]; err == nil {
+		t.Error("panicking service reported healthy")
+	}
+	if s.restarts != 1 {
+		t.Errorf("panicking service restarts: got %d, want 1", s.restarts)
+	}
+}
+
+// blockingService blocks in its restarts until released
+type blockingService struct {
+	crashingService
+	restarting chan struct{}
+	release    chan struct{}
+}
+
+func (s *blockingService) Restart() error {
+	s.restarts++
+	close(s.restarting)
+	<-s.release
+	return nil
+}
+
+func TestSuperviseSerializesRestarts(t *testing.T) {
+	m := &Manager{}
+	m.SetupServiceManager()
+	s := &blockingService{
+		crashingService: crashingService{health: errors.New("crashed")},
+		restarting:      make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+	m.RegisterService(Consensus, s)
+
+	now := time.Now()
+	go m.checkServices(now)
+	<-s.restarting
+
+	// the service is not stopped in the middle of its restart
+	stopped := make(chan struct{})
+	go func() {
+		m.StopService(Consensus)
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("service stopped during its restart")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(s.release)
+	<-stopped
+
+	m.checkServices(now.Add(restartBackoffMax))
+	if s.restarts != 1 {
+		t.Errorf("stopped service restarted: got %d restarts, want 1", s.restarts)
+	}
+}
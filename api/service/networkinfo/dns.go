@@ -0,0 +1,126 @@
+package networkinfo
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	coredis "github.com/libp2p/go-libp2p-core/discovery"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// dnsaddrPrefix is the optional prefix of the TXT records holding multiaddrs
+const dnsaddrPrefix = "dnsaddr="
+
+// DNSDiscovery is a discovery driver which resolves a DNS name to a seed list
+// of peers. Each TXT record of the name holds the multiaddr of a peer, either
+// bare or in the form "dnsaddr=<multiaddr>".
+type DNSDiscovery struct {
+	name      string
+	lookupTXT func(name string) ([]string, error)
+}
+
+// NewDNSDiscovery returns a discovery driver resolving the given DNS name.
+func NewDNSDiscovery(name string) *DNSDiscovery {
+	return &DNSDiscovery{name: name, lookupTXT: net.LookupTXT}
+}
+
+// Seeds resolves the DNS name to the seed list. Malformed records are skipped.
+func (d *DNSDiscovery) Seeds() (p2p.AddrList, error) {
+	records, err := d.lookupTXT(d.name)
+	if err != nil {
+		return nil, err
+	}
+	seeds := make(p2p.AddrList, 0, len(records))
+	for _, record := range records {
+		addr, err := ma.NewMultiaddr(strings.TrimPrefix(record, dnsaddrPrefix))
+		if err == nil {
+			// seeds must carry the peer ID to be dialed
+			_, err = libp2p_peer.AddrInfoFromP2pAddr(addr)
+		}
+		if err != nil {
+			utils.Logger().Warn().Err(err).
+				Str("name", d.name).
+				Str("record", record).
+				Msg("ignoring malformed DNS seed")
+			continue
+		}
+		seeds = append(seeds, addr)
+	}
+	return seeds, nil
+}
+
+// FindPeers returns the seed peers. The namespace is ignored since the seed
+// list serves all of them.
+func (d *DNSDiscovery) FindPeers(
+	ctx context.Context, ns string, opts ...coredis.Option,
+) (<-chan libp2p_peer.AddrInfo, error) {
+	var options coredis.Options
+	if err := options.Apply(opts...); err != nil {
+		return nil, err
+	}
+	seeds, err := d.Seeds()
+	if err != nil {
+		return nil, err
+	}
+	infos, err := libp2p_peer.AddrInfosFromP2pAddrs(seeds...)
+	if err != nil {
+		return nil, err
+	}
+	if options.Limit > 0 && len(infos) > options.Limit {
+		infos = infos[:options.Limit]
+	}
+	peerInfo := make(chan libp2p_peer.AddrInfo, len(infos))
+	for _, info := range infos {
+		peerInfo <- info
+	}
+	close(peerInfo)
+	return peerInfo, nil
+}
+
+// mergeAddrLists returns the union of the given address lists, in order.
+func mergeAddrLists(lists ...p2p.AddrList) p2p.AddrList {
+	seen := make(map[string]struct{})
+	var merged p2p.AddrList
+	for _, list := range lists {
+		for _, addr := range list {
+			if _, ok := seen[addr.String()]; ok {
+				continue
+			}
+			seen[addr.String()] = struct{}{}
+			merged = append(merged, addr)
+		}
+	}
+	return merged
+}
+
+// mergePeerInfo forwards the peers of all the given channels into one channel,
+// which is closed once all of them are or the context is done.
+func mergePeerInfo(
+	ctx context.Context, chans ...<-chan libp2p_peer.AddrInfo,
+) <-chan libp2p_peer.AddrInfo {
+	merged := make(chan libp2p_peer.AddrInfo)
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func(ch <-chan libp2p_peer.AddrInfo) {
+			defer wg.Done()
+			for info := range ch {
+				select {
+				case merged <- info:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
+}
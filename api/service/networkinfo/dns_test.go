@@ -0,0 +1,60 @@
+package networkinfo
+
+import (
+	"context"
+	"testing"
+
+	coredis "github.com/libp2p/go-libp2p-core/discovery"
+)
+
+const (
+	seed1 = "/ip4/100.26.90.187/tcp/9874/p2p/Qmdfjtk6hPoyrH1zVD9PEH4zfWLo38dP2mDvvKXfh3tnEv"
+	seed2 = "/ip4/54.213.43.194/tcp/9874/p2p/QmZJJx6AdaoEkGLrYG4JeLCKeCKDjnFz2wfHNHxAqFSGA9"
+)
+
+func newTestDNSDiscovery(records ...string) *DNSDiscovery {
+	return &DNSDiscovery{
+		name:      "_dnsaddr.bootstrap.test",
+		lookupTXT: func(string) ([]string, error) { return records, nil },
+	}
+}
+
+func TestDNSDiscoverySeeds(t *testing.T) {
+	d := newTestDNSDiscovery(
+		seed1,
+		"dnsaddr="+seed2,
+		"not a multiaddr",
+		"/ip4/1.2.3.4/tcp/9874", // no peer ID
+	)
+	seeds, err := d.Seeds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seeds) != 2 || seeds[0].String() != seed1 || seeds[1].String() != seed2 {
+		t.Fatalf("unexpected seeds %v", seeds)
+	}
+}
+
+func TestDNSDiscoveryFindPeers(t *testing.T) {
+	d := newTestDNSDiscovery(seed1, seed2)
+	peerInfo, err := d.FindPeers(context.Background(), "shard0", coredis.Limit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for range peerInfo {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 peer, got %d", count)
+	}
+}
+
+func TestMergeAddrLists(t *testing.T) {
+	d := newTestDNSDiscovery(seed1, seed2)
+	seeds, _ := d.Seeds()
+	merged := mergeAddrLists(seeds[:1], seeds, seeds[1:])
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 addresses, got %d", len(merged))
+	}
+}
@@ -26,7 +26,10 @@ import (
 type Service struct {
 	Host        p2p.Host
 	Rendezvous  nodeconfig.GroupID
+	DNSSeed     string // DNS name resolved to seed peers, if not empty
+	ShardID     uint32 // shard advertised along with the epoch, see ShardNamespace
 	bootnodes   p2p.AddrList
+	dnsSeeds    *DNSDiscovery
 	dht         *libp2pdht.IpfsDHT
 	cancel      context.CancelFunc
 	stopChan    chan struct{}
@@ -36,6 +39,12 @@ type Service struct {
 	discovery   *libp2pdis.RoutingDiscovery
 	messageChan chan *msg_pb.Message
 	started     bool
+
+	// CurrentEpoch returns the epoch the shard membership is advertised for;
+	// the membership is not advertised nor looked up if nil
+	CurrentEpoch    func() uint64
+	advertisedEpoch uint64
+	advertiseCancel context.CancelFunc
 }
 
 // ConnectionRetry set the number of retry of connection to bootnode in case the initial connection is failed
@@ -135,6 +144,17 @@ func (s *Service) Init() error {
 		// TODO: should've passed in bootnodes through constructor.
 		s.bootnodes = p2p.BootNodes
 	}
+	// Seed peers from DNS let the node bootstrap when the bootnodes are
+	// unreachable.
+	if s.DNSSeed != "" {
+		s.dnsSeeds = NewDNSDiscovery(s.DNSSeed)
+		seeds, err := s.dnsSeeds.Seeds()
+		if err != nil {
+			utils.Logger().Warn().Err(err).Str("name", s.DNSSeed).Msg("cannot resolve DNS seeds")
+		}
+		utils.Logger().Info().Str("name", s.DNSSeed).Int("seeds", len(seeds)).Msg("resolved DNS seeds")
+		s.bootnodes = mergeAddrLists(s.bootnodes, seeds)
+	}
 
 	connected := false
 	for _, peerAddr := range s.bootnodes {
@@ -169,6 +189,7 @@ func (s *Service) Init() error {
 	// Everyone is beacon client, which means everyone is connected via beacon client topic
 	// 0 is beacon chain FIXME: use a constant
 	libp2pdis.Advertise(ctx, s.discovery, string(nodeconfig.NewClientGroupIDByShardID(0)))
+	s.advertiseShard()
 	utils.Logger().Info().Msg("Successfully announced!")
 
 	return nil
@@ -203,16 +224,42 @@ func (s *Service) DoService() {
 				Str("Rendezvous", string(s.Rendezvous)).
 				Msg("Successfully announced!")
 		default:
-			var err error
-			s.peerInfo, err = s.discovery.FindPeers(
-				ctx, string(s.Rendezvous), coredis.Limit(discoveryLimit),
+			s.advertiseShard()
+			findCtx, findCancel := context.WithCancel(ctx)
+			peerInfo, err := s.discovery.FindPeers(
+				findCtx, string(s.Rendezvous), coredis.Limit(discoveryLimit),
 			)
 			if err != nil {
+				findCancel()
 				utils.Logger().Error().Err(err).Msg("FindPeers")
 				return
 			}
+			// Same-shard peers are found directly by their membership, the
+			// rendezvous still serving the peers not advertising it.
+			if s.CurrentEpoch != nil {
+				shardInfo, err := s.FindShardPeers(
+					findCtx, s.ShardID, s.CurrentEpoch(), discoveryLimit,
+				)
+				if err != nil {
+					utils.Logger().Warn().Err(err).Msg("FindShardPeers")
+				} else {
+					peerInfo = mergePeerInfo(findCtx, shardInfo, peerInfo)
+				}
+			}
+			if s.dnsSeeds != nil {
+				seedInfo, err := s.dnsSeeds.FindPeers(
+					findCtx, string(s.Rendezvous), coredis.Limit(discoveryLimit),
+				)
+				if err != nil {
+					utils.Logger().Warn().Err(err).Msg("FindPeers from DNS seeds")
+				} else {
+					peerInfo = mergePeerInfo(findCtx, peerInfo, seedInfo)
+				}
+			}
+			s.peerInfo = peerInfo
 
-			s.findPeers(ctx)
+			s.findPeers(findCtx)
+			findCancel()
 			time.Sleep(findPeerInterval)
 		}
 	}
@@ -224,7 +271,12 @@ func (s *Service) findPeers(ctx context.Context) {
 		utils.Logger().Error().Err(err).Msg("can't parse CIDR")
 		return
 	}
+	seen := map[libp2p_peer.ID]struct{}{}
 	for peer := range s.peerInfo {
+		if _, ok := seen[peer.ID]; ok {
+			continue
+		}
+		seen[peer.ID] = struct{}{}
 		if peer.ID != s.Host.GetP2PHost().ID() && len(peer.ID) > 0 {
 			if err := s.Host.GetP2PHost().Connect(ctx, peer); err != nil {
 				utils.Logger().Warn().Err(err).Interface("peer", peer).Msg("can't connect to peer node")
@@ -271,6 +323,9 @@ func (s *Service) StopService() {
 
 	s.stopChan <- struct{}{}
 	<-s.stoppedChan
+	if s.advertiseCancel != nil {
+		s.advertiseCancel()
+	}
 	utils.Logger().Info().Msg("Network info service stopped")
 }
 
@@ -0,0 +1,50 @@
+package networkinfo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harmony-one/harmony/internal/utils"
+	coredis "github.com/libp2p/go-libp2p-core/discovery"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	libp2pdis "github.com/libp2p/go-libp2p-discovery"
+	"github.com/pkg/errors"
+)
+
+// ShardNamespace returns the DHT namespace under which the members of the
+// shard in the epoch advertise themselves.
+func ShardNamespace(shardID uint32, epoch uint64) string {
+	return fmt.Sprintf("hmy/shard/%d/epoch/%d", shardID, epoch)
+}
+
+// advertiseShard advertises the node as a member of its shard in the current
+// epoch, dropping the advertisement of the previous epoch when it changed.
+// It is a no-op if the service has no epoch source.
+func (s *Service) advertiseShard() {
+	if s.CurrentEpoch == nil {
+		return
+	}
+	epoch := s.CurrentEpoch()
+	if s.advertiseCancel != nil {
+		if epoch == s.advertisedEpoch {
+			return
+		}
+		s.advertiseCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.advertiseCancel, s.advertisedEpoch = cancel, epoch
+	ns := ShardNamespace(s.ShardID, epoch)
+	libp2pdis.Advertise(ctx, s.discovery, ns)
+	utils.Logger().Info().Str("namespace", ns).Msg("advertised shard membership")
+}
+
+// FindShardPeers looks up the DHT for up to limit peers which advertised
+// themselves as members of the shard in the epoch.
+func (s *Service) FindShardPeers(
+	ctx context.Context, shardID uint32, epoch uint64, limit int,
+) (<-chan libp2p_peer.AddrInfo, error) {
+	if s.discovery == nil {
+		return nil, errors.New("discovery is not initialized")
+	}
+	return s.discovery.FindPeers(ctx, ShardNamespace(shardID, epoch), coredis.Limit(limit))
+}
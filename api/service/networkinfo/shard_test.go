@@ -0,0 +1,9 @@
+package networkinfo
+
+import "testing"
+
+func TestShardNamespace(t *testing.T) {
+	if ns := ShardNamespace(2, 17); ns != "hmy/shard/2/epoch/17" {
+		t.Fatalf("unexpected namespace %s", ns)
+	}
+}
@@ -0,0 +1,281 @@
+package storageguard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultInterval is the interval between two checks of the volumes
+	DefaultInterval = 30 * time.Second
+	// probeSize is the size of the file written and synced to time the writes
+	probeSize = 4096
+	// probeName is the name of the probe file in the directory of a volume
+	probeName = ".storage-probe"
+)
+
+var (
+	freeBytesGauge    = metrics.NewRegisteredGauge("storage/free_bytes", nil)
+	writeLatencyGauge = metrics.NewRegisteredGauge("storage/write_latency_us", nil)
+	degradedCounter   = metrics.NewRegisteredCounter("storage/degraded", nil)
+)
+
+// Config is the thresholds of the storage guard
+type Config struct {
+	// MinFree is the free space, in bytes, under which the node is degraded
+	MinFree uint64
+	// MaxWriteLatency is the latency of a synced write above which the node
+	// is degraded, 0 for no latency check
+	MaxWriteLatency time.Duration
+	// Interval is the interval between two checks
+	Interval time.Duration
+}
+
+// Volume is the directory of the database of a shard chain
+type Volume struct {
+	ShardID uint32
+	Dir     string
+}
+
+// Status is the storage status of a volume as of its last check
+type Status struct {
+	ShardID      uint32        `json:"shard-id"`
+	Dir          string        `json:"dir"`
+	FreeBytes    uint64        `json:"free-bytes"`
+	WriteLatency time.Duration `json:"write-latency"`
+	Degraded     string        `json:"degraded,omitempty"`
+	CheckedAt    time.Time     `json:"checked-at"`
+}
+
+// Service checks the free space and the write latency of the volumes of the
+// shard chains, and reports the node degraded while a threshold is crossed
+type Service struct {
+	config      Config
+	volumes     func() []Volume
+	onChange    func(reason string)
+	messageChan chan *msg_pb.Message
+	stopChan    chan struct{}
+	stoppedChan chan struct{}
+
+	mutex    sync.RWMutex
+	statuses []Status
+	reason   string
+}
+
+// New returns a storage guard checking the given volumes, which calls
+// onChange with the reason the node is degraded, empty once it recovers
+func New(config Config, volumes func() []Volume, onChange func(reason string)) *Service {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Service{config: config, volumes: volumes, onChange: onChange}
+}
+
+// FreeSpace returns the space available to the node on the file system of the
+// given directory
+func FreeSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, errors.Wrapf(err, "cannot stat file system of %s", dir)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// CheckFloor returns an error if the free space of the given directory is
+// below the floor, for the node to refuse to start
+func CheckFloor(dir string, floor uint64) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	free, err := FreeSpace(dir)
+	if err != nil {
+		return err
+	}
+	if free < floor {
+		return errors.Errorf(
+			"%d bytes free in %s, below the safety floor of %d bytes", free, dir, floor,
+		)
+	}
+	return nil
+}
+
+// writeLatency times a synced write of a probe file in the directory
+func writeLatency(dir string) (time.Duration, error) {
+	path := filepath.Join(dir, probeName)
+	start := time.Now()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(path)
+	if _, err := f.Write(make([]byte, probeSize)); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// check checks a volume against the thresholds
+func (s *Service) check(volume Volume) Status {
+	status := Status{ShardID: volume.ShardID, Dir: volume.Dir, CheckedAt: time.Now()}
+	free, err := FreeSpace(volume.Dir)
+	if err != nil {
+		status.Degraded = err.Error()
+		return status
+	}
+	status.FreeBytes = free
+	if free < s.config.MinFree {
+		status.Degraded = fmt.Sprintf(
+			"shard %d: %d bytes free, below %d", volume.ShardID, free, s.config.MinFree,
+		)
+		return status
+	}
+	if s.config.MaxWriteLatency > 0 {
+		latency, err := writeLatency(volume.Dir)
+		if err != nil {
+			status.Degraded = fmt.Sprintf("shard %d: cannot write: %v", volume.ShardID, err)
+			return status
+		}
+		status.WriteLatency = latency
+		if latency > s.config.MaxWriteLatency {
+			status.Degraded = fmt.Sprintf(
+				"shard %d: write latency %v, above %v",
+				volume.ShardID, latency, s.config.MaxWriteLatency,
+			)
+		}
+	}
+	return status
+}
+
+// checkVolumes checks all the volumes, calling onChange when the node gets
+// degraded or recovers
+func (s *Service) checkVolumes() {
+	statuses, reason := []Status{}, ""
+	var minFree uint64
+	var maxLatency time.Duration
+	for i, volume := range s.volumes() {
+		status := s.check(volume)
+		statuses = append(statuses, status)
+		if reason == "" {
+			reason = status.Degraded
+		}
+		if i == 0 || status.FreeBytes < minFree {
+			minFree = status.FreeBytes
+		}
+		if status.WriteLatency > maxLatency {
+			maxLatency = status.WriteLatency
+		}
+	}
+	freeBytesGauge.Update(int64(minFree))
+	writeLatencyGauge.Update(maxLatency.Microseconds())
+
+	s.mutex.Lock()
+	changed := (reason == "") != (s.reason == "")
+	s.statuses, s.reason = statuses, reason
+	s.mutex.Unlock()
+	if !changed {
+		return
+	}
+	if reason != "" {
+		degradedCounter.Inc(1)
+		utils.Logger().Warn().Str("reason", reason).Msg("[StorageGuard] node degraded")
+	} else {
+		utils.Logger().Info().Msg("[StorageGuard] node recovered")
+	}
+	if s.onChange != nil {
+		s.onChange(reason)
+	}
+}
+
+// Statuses returns the status of the volumes as of their last check
+func (s *Service) Statuses() []Status {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]Status{}, s.statuses...)
+}
+
+// Degraded returns the reason the node is degraded, empty if it is not
+func (s *Service) Degraded() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.reason
+}
+
+// StartService starts the storage guard service.
+func (s *Service) StartService() {
+	utils.Logger().Info().Msg("Starting storage guard service.")
+	s.stopChan = make(chan struct{})
+	s.stoppedChan = make(chan struct{})
+	go s.run()
+}
+
+func (s *Service) run() {
+	defer close(s.stoppedChan)
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	s.checkVolumes()
+	for {
+		select {
+		case <-ticker.C:
+			s.checkVolumes()
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// StopService stops the storage guard service.
+func (s *Service) StopService() {
+	utils.Logger().Info().Msg("Stopping storage guard service.")
+	close(s.stopChan)
+	<-s.stoppedChan
+	utils.Logger().Info().Msg("Storage guard service stopped.")
+}
+
+// NotifyService notify service
+func (s *Service) NotifyService(params map[string]interface{}) {}
+
+// SetMessageChan sets up message channel to service.
+func (s *Service) SetMessageChan(messageChan chan *msg_pb.Message) {
+	s.messageChan = messageChan
+}
+
+// APIs for the services.
+func (s *Service) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   &PrivateStorageGuardAPI{s},
+			Public:    false,
+		},
+	}
+}
+
+// PrivateStorageGuardAPI exposes the storage status of the node to the node
+// operator.
+type PrivateStorageGuardAPI struct {
+	s *Service
+}
+
+// StorageStatus returns the storage status of the shard chains of the node.
+func (api *PrivateStorageGuardAPI) StorageStatus() []Status {
+	return api.s.Statuses()
+}
@@ -0,0 +1,60 @@
+package storageguard
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCheckVolumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storageguard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	volumes := func() []Volume { return []Volume{{ShardID: 1, Dir: dir}} }
+
+	reasons := []string{}
+	s := New(Config{MinFree: 1, MaxWriteLatency: time.Minute}, volumes, func(reason string) {
+		reasons = append(reasons, reason)
+	})
+	s.checkVolumes()
+	if s.Degraded() != "" || len(reasons) != 0 {
+		t.Fatalf("degraded with free space: %q", s.Degraded())
+	}
+	if statuses := s.Statuses(); len(statuses) != 1 || statuses[0].FreeBytes == 0 {
+		t.Fatalf("unexpected statuses %+v", statuses)
+	}
+
+	s.config.MinFree = math.MaxUint64
+	s.checkVolumes()
+	if s.Degraded() == "" || len(reasons) != 1 {
+		t.Fatal("not degraded below the free space threshold")
+	}
+	s.checkVolumes()
+	if len(reasons) != 1 {
+		t.Fatal("degradation reported twice")
+	}
+
+	s.config.MinFree = 1
+	s.checkVolumes()
+	if s.Degraded() != "" || len(reasons) != 2 || reasons[1] != "" {
+		t.Fatalf("recovery not reported: %q", reasons)
+	}
+}
+
+func TestCheckFloor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storageguard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := CheckFloor(dir, 1); err != nil {
+		t.Error(err)
+	}
+	if err := CheckFloor(dir, math.MaxUint64); err == nil {
+		t.Error("started below the safety floor")
+	}
+}
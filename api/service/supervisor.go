@@ -0,0 +1,159 @@
+package service
+
+import (
+	"time"
+
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+// Constants for supervision.
+const (
+	// SuperviseInterval is the interval between two health checks of the services.
+	SuperviseInterval = 10 * time.Second
+	// restartBackoffMin is the delay before the first restart of an unhealthy service.
+	restartBackoffMin = 5 * time.Second
+	// restartBackoffMax is the maximum delay between two restarts of a service.
+	restartBackoffMax = 5 * time.Minute
+)
+
+// Supervised is implemented by the services whose health is checked by the
+// service manager, which restarts them when they are unhealthy.
+type Supervised interface {
+	Interface
+	// Health returns nil if the service is running as expected.
+	Health() error
+	// Restart restarts the service after it crashed.
+	Restart() error
+}
+
+// restartState is the restart backoff of an unhealthy service.
+type restartState struct {
+	failures int
+	next     time.Time
+}
+
+// Supervise starts checking the health of the supervised services every
+// interval, restarting the unhealthy ones with an exponential backoff. It
+// does nothing if the services are already supervised.
+func (m *Manager) Supervise(interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.supervising {
+		return
+	}
+	m.supervising = true
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.checkServices(time.Now())
+		}
+	}()
+}
+
+// Health returns the last health of each supervised service, nil if healthy.
+func (m *Manager) Health() map[Type]error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	health := make(map[Type]error, len(m.health))
+	for t, err := range m.health {
+		health[t] = err
+	}
+	return health
+}
+
+// checkServices checks the health of the running supervised services and
+// restarts the unhealthy ones whose backoff elapsed.
+func (m *Manager) checkServices(now time.Time) {
+	for t, s := range m.GetServices() {
+		supervised, ok := s.(Supervised)
+		if !ok || m.isStopped(t) {
+			continue
+		}
+		m.checkService(t, supervised, now)
+	}
+}
+
+// checkService checks the health of the supervised service and restarts it if
+// unhealthy and its backoff elapsed. A panic of the service while checked or
+// restarted is recovered and counted as a failure.
+func (m *Manager) checkService(t Type, supervised Supervised, now time.Time) {
+	err := protect(supervised.Health)
+	m.mu.Lock()
+	if m.health == nil {
+		m.health = make(map[Type]error)
+		m.restarts = make(map[Type]*restartState)
+	}
+	m.health[t] = err
+	state, ok := m.restarts[t]
+	if !ok {
+		state = &restartState{}
+		m.restarts[t] = state
+	}
+	if err == nil {
+		state.failures = 0
+		m.mu.Unlock()
+		return
+	}
+	if now.Before(state.next) {
+		m.mu.Unlock()
+		return
+	}
+	backoff := restartBackoffMin << uint(state.failures)
+	if backoff > restartBackoffMax || backoff <= 0 {
+		backoff = restartBackoffMax
+	}
+	state.failures++
+	state.next = now.Add(backoff)
+	m.mu.Unlock()
+
+	// not restarted while started or stopped on purpose
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+	if m.isStopped(t) {
+		return
+	}
+	utils.Logger().Warn().
+		Err(err).
+		Str("service", t.String()).
+		Dur("backoff", backoff).
+		Msg("Restarting unhealthy service")
+	if err := protect(supervised.Restart); err != nil {
+		utils.Logger().Error().
+			Err(err).
+			Str("service", t.String()).
+			Msg("Failed to restart service")
+	}
+}
+
+// protect calls the function of a supervised service, turning its panic into
+// an error.
+func protect(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("service panicked: %v", r)
+		}
+	}()
+	return f()
+}
+
+// setStopped records whether the service was stopped on purpose, in which
+// case it is not restarted.
+func (m *Manager) setStopped(t Type, stopped bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped == nil {
+		m.stopped = make(map[Type]bool)
+	}
+	m.stopped[t] = stopped
+	if stopped {
+		delete(m.health, t)
+	}
+}
+
+func (m *Manager) isStopped(t Type) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stopped[t]
+}
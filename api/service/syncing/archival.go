@@ -0,0 +1,213 @@
+package syncing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/node/worker"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxArchivalBatch is the number of blocks fetched at once from an
+	// archival provider
+	MaxArchivalBatch = 64
+	// maxArchivalBatchBytes bounds the size of a batch read from a provider
+	maxArchivalBatchBytes = 64 << 20
+	// archivalFetchTimeout is the timeout of a batch request to a provider
+	archivalFetchTimeout = 30 * time.Second
+	// ArchivalBlocksPath is the path the archival providers serve blocks on
+	ArchivalBlocksPath = "/blocks"
+)
+
+var archivalBlocksCounter = metrics.NewRegisteredCounter("sync/archival/blocks", nil)
+
+// ArchivalBlock is a block served by an archival provider, with the commit
+// signature and bitmap of the block
+type ArchivalBlock struct {
+	Block              *types.Block
+	CommitSigAndBitmap []byte
+}
+
+// ArchivalProviders fetches the blocks of a shard from archival HTTP(S)
+// endpoints, the fallback of the p2p sync when it makes no progress. The
+// blocks are only trusted once their commit signature is verified.
+type ArchivalProviders struct {
+	endpoints []string
+	client    *http.Client
+	mutex     sync.Mutex
+	next      int // endpoint tried first, the last one which served blocks
+}
+
+// NewArchivalProviders returns the archival providers of the given endpoints,
+// ex: https://archive.example.com
+func NewArchivalProviders(endpoints []string) *ArchivalProviders {
+	return &ArchivalProviders{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: archivalFetchTimeout},
+	}
+}
+
+// FetchBlocks fetches up to count blocks of the shard from the given number,
+// trying the endpoints in turn
+func (p *ArchivalProviders) FetchBlocks(
+	shardID uint32, from uint64, count int,
+) ([]ArchivalBlock, error) {
+	if len(p.endpoints) == 0 {
+		return nil, errors.New("no archival provider")
+	}
+	if count > MaxArchivalBatch {
+		count = MaxArchivalBatch
+	}
+	p.mutex.Lock()
+	first := p.next
+	p.mutex.Unlock()
+	var lastErr error
+	for i := 0; i < len(p.endpoints); i++ {
+		index := (first + i) % len(p.endpoints)
+		blocks, err := p.fetch(p.endpoints[index], shardID, from, count)
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+				Str("endpoint", p.endpoints[index]).
+				Uint64("from", from).
+				Msg("[SYNC] cannot fetch blocks from archival provider")
+			lastErr = err
+			continue
+		}
+		p.mutex.Lock()
+		p.next = index
+		p.mutex.Unlock()
+		return blocks, nil
+	}
+	return nil, lastErr
+}
+
+func (p *ArchivalProviders) fetch(
+	endpoint string, shardID uint32, from uint64, count int,
+) ([]ArchivalBlock, error) {
+	url := fmt.Sprintf("%s%s?shard=%d&from=%d&count=%d", endpoint, ArchivalBlocksPath, shardID, from, count)
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	blocks := []ArchivalBlock{}
+	if err := rlp.Decode(io.LimitReader(resp.Body, maxArchivalBatchBytes), &blocks); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode blocks from %s", url)
+	}
+	if len(blocks) > count {
+		return nil, errors.Errorf("%d blocks from %s, asked for %d", len(blocks), url, count)
+	}
+	return blocks, nil
+}
+
+// SyncFromArchival inserts the blocks fetched from the archival providers on
+// top of the chain, each block once its commit signature is verified against
+// its committee, until the providers have no more blocks or one fails to
+// verify. It returns the number of blocks inserted.
+func (ss *StateSync) SyncFromArchival(
+	bc *core.BlockChain, worker *worker.Worker, providers *ArchivalProviders,
+) (int, error) {
+	inserted := 0
+	for {
+		head := bc.CurrentBlock()
+		blocks, err := providers.FetchBlocks(bc.ShardID(), head.NumberU64()+1, MaxArchivalBatch)
+		if err != nil {
+			return inserted, err
+		}
+		if len(blocks) == 0 {
+			return inserted, nil
+		}
+		for _, b := range blocks {
+			if err := ss.insertArchivalBlock(bc, worker, b); err != nil {
+				return inserted, err
+			}
+			inserted++
+			archivalBlocksCounter.Inc(1)
+		}
+	}
+}
+
+// insertArchivalBlock verifies the block extends the chain and is signed by a
+// quorum of its committee, then inserts it with its commit signature
+func (ss *StateSync) insertArchivalBlock(
+	bc *core.BlockChain, worker *worker.Worker, b ArchivalBlock,
+) error {
+	block, head := b.Block, bc.CurrentBlock()
+	if block == nil {
+		return errors.New("[SYNC] archival provider served no block")
+	}
+	if block.ParentHash() != head.Hash() || block.ShardID() != bc.ShardID() {
+		return errors.Errorf(
+			"[SYNC] archival block %d of shard %d does not extend head %d",
+			block.NumberU64(), block.ShardID(), head.NumberU64(),
+		)
+	}
+	sigAndBitmap := b.CommitSigAndBitmap
+	if len(sigAndBitmap) <= shard.BLSSignatureSizeInBytes {
+		return errors.Errorf("[SYNC] archival block %d has no commit signature", block.NumberU64())
+	}
+	if err := bc.Engine().VerifyHeaderWithSignature(
+		bc, block.Header(),
+		sigAndBitmap[:shard.BLSSignatureSizeInBytes],
+		sigAndBitmap[shard.BLSSignatureSizeInBytes:], true,
+	); err != nil {
+		return errors.Wrapf(err, "[SYNC] archival block %d", block.NumberU64())
+	}
+	if err := ss.UpdateBlockAndStatus(block, bc, worker, true); err != nil {
+		return err
+	}
+	return bc.WriteCommitSig(block.NumberU64(), sigAndBitmap)
+}
+
+// ArchivalHandler serves the blocks of the chains, with their commit
+// signature, to the nodes falling back to this node as an archival provider
+func ArchivalHandler(chain func(shardID uint32) (*core.BlockChain, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		shardID, err1 := strconv.ParseUint(query.Get("shard"), 10, 32)
+		from, err2 := strconv.ParseUint(query.Get("from"), 10, 64)
+		count, err3 := strconv.Atoi(query.Get("count"))
+		if err1 != nil || err2 != nil || err3 != nil || count <= 0 {
+			http.Error(w, "expected shard, from and count", http.StatusBadRequest)
+			return
+		}
+		if count > MaxArchivalBatch {
+			count = MaxArchivalBatch
+		}
+		bc, err := chain(uint32(shardID))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		blocks := []ArchivalBlock{}
+		for number := from; number < from+uint64(count); number++ {
+			block := bc.GetBlockByNumber(number)
+			if block == nil {
+				break
+			}
+			sig, err := bc.ReadCommitSig(number)
+			if err != nil || len(sig) <= shard.BLSSignatureSizeInBytes {
+				break
+			}
+			blocks = append(blocks, ArchivalBlock{Block: block, CommitSigAndBitmap: sig})
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if err := rlp.Encode(w, blocks); err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).Msg("[SYNC] cannot serve archival blocks")
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package syncing
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestArchivalProvidersFailover(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	serving := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ArchivalBlocksPath || r.URL.Query().Get("from") != "5" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		header := blockfactory.NewTestHeader().With().Number(big.NewInt(5)).Header()
+		rlp.Encode(w, []ArchivalBlock{{
+			Block:              types.NewBlockWithHeader(header),
+			CommitSigAndBitmap: []byte{1, 2, 3},
+		}})
+	}))
+	defer serving.Close()
+
+	providers := NewArchivalProviders([]string{failing.URL, serving.URL})
+	blocks, err := providers.FetchBlocks(0, 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Block.NumberU64() != 5 {
+		t.Fatalf("unexpected blocks %+v", blocks)
+	}
+	if providers.next != 1 {
+		t.Errorf("next endpoint = %d, expected the serving one", providers.next)
+	}
+}
@@ -0,0 +1,104 @@
+package syncing
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// GetStateNodes gets the trie nodes of the given hashes from the peer.
+// The nodes unknown to the peer are empty.
+func (peerConfig *SyncPeerConfig) GetStateNodes(hashes []common.Hash) ([][]byte, error) {
+	response := peerConfig.client.GetStateNodes(hashesToBytes(hashes))
+	if response == nil || len(response.Payload) != len(hashes) {
+		return nil, ErrGetStateNodes
+	}
+	return response.Payload, nil
+}
+
+// GetCommitSig gets the commit signature and bitmap signed on the block of the
+// given number from the peer, empty if unknown to the peer.
+func (peerConfig *SyncPeerConfig) GetCommitSig(number uint64) ([]byte, error) {
+	response := peerConfig.client.GetCommitSig(number)
+	if response == nil || len(response.Payload) != 1 {
+		return nil, ErrGetCommitSig
+	}
+	return response.Payload[0], nil
+}
+
+// FetchCommitSig downloads the commit signature of the block of the given
+// number from the peers serving commit signatures, verifies it against the
+// committee of the block and stores it.
+func (ss *StateSync) FetchCommitSig(bc *core.BlockChain, number uint64) ([]byte, error) {
+	sigAndBitmap, err := ss.FindCommitSig(bc, number)
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.WriteCommitSig(number, sigAndBitmap); err != nil {
+		return nil, err
+	}
+	return sigAndBitmap, nil
+}
+
+// FindCommitSig downloads the commit signature of the block of the given
+// number from the peers serving commit signatures and verifies it against the
+// committee of the block, without storing it.
+func (ss *StateSync) FindCommitSig(bc *core.BlockChain, number uint64) ([]byte, error) {
+	header := bc.GetHeaderByNumber(number)
+	if header == nil {
+		return nil, errors.Errorf("[SYNC] no header of block %d", number)
+	}
+	peers := ss.capablePeers(downloader.FeatureCommitSig)
+	if len(peers) == 0 {
+		return nil, ErrNoCapablePeer
+	}
+	for _, peerConfig := range peers {
+		sigAndBitmap, err := peerConfig.GetCommitSig(number)
+		if err == nil && len(sigAndBitmap) <= shard.BLSSignatureSizeInBytes {
+			err = ErrGetCommitSig
+		}
+		if err == nil {
+			err = bc.Engine().VerifyHeaderWithSignature(
+				bc, header,
+				sigAndBitmap[:shard.BLSSignatureSizeInBytes],
+				sigAndBitmap[shard.BLSSignatureSizeInBytes:], true,
+			)
+		}
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+				Str("peerIP", peerConfig.ip).
+				Str("peerPort", peerConfig.port).
+				Uint64("blockNum", number).
+				Msg("[SYNC] FindCommitSig: no valid commit signature")
+			continue
+		}
+		return sigAndBitmap, nil
+	}
+	return nil, ErrGetCommitSig
+}
+
+// capablePeers returns the sync peers which advertised the given feature
+func (ss *StateSync) capablePeers(feature string) []*SyncPeerConfig {
+	peers := []*SyncPeerConfig{}
+	if ss.syncConfig == nil {
+		return peers
+	}
+	ss.syncConfig.ForEachPeer(func(peerConfig *SyncPeerConfig) (brk bool) {
+		if peerConfig.SupportsFeature(feature) {
+			peers = append(peers, peerConfig)
+		}
+		return
+	})
+	return peers
+}
+
+func hashesToBytes(hashes []common.Hash) [][]byte {
+	result := make([][]byte, len(hashes))
+	for i := range hashes {
+		result[i] = hashes[i][:]
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package syncing
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/checkpoint"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+// BootstrapFromCheckpoint makes the checkpoint block the head of a chain which
+// is still at genesis, so that it is synced from the checkpoint onwards. The
+// block and its state are downloaded from the peers and checked against the
+// checkpoint; the shard state of the checkpoint epoch is stored so that the
+// blocks after the checkpoint are verified against its committee as usual.
+func (ss *StateSync) BootstrapFromCheckpoint(bc *core.BlockChain, cp *checkpoint.Checkpoint) error {
+	if bc.CurrentBlock().NumberU64() != 0 {
+		return ErrChainNotFresh
+	}
+	block, err := ss.getCheckpointBlock(cp)
+	if err != nil {
+		return err
+	}
+	if err := ss.downloadState(bc, block.Root()); err != nil {
+		return err
+	}
+	if _, err := bc.WriteShardStateBytes(
+		bc.ChainDb(), new(big.Int).SetUint64(cp.Epoch), block.Header().ShardState(),
+	); err != nil {
+		return err
+	}
+	if err := bc.ResetWithCheckpointBlock(block); err != nil {
+		return err
+	}
+	utils.ModuleLogger(utils.ModuleSync).Info().
+		Uint64("epoch", cp.Epoch).
+		Uint64("blockNumber", cp.BlockNumber).
+		Str("blockHash", cp.BlockHash.Hex()).
+		Msg("[SYNC] bootstrapped from checkpoint")
+	return nil
+}
+
+// getCheckpointBlock downloads the checkpoint block from the first peer
+// returning a block matching the checkpoint.
+func (ss *StateSync) getCheckpointBlock(cp *checkpoint.Checkpoint) (*types.Block, error) {
+	var block *types.Block
+	ss.syncConfig.ForEachPeer(func(peerConfig *SyncPeerConfig) (brk bool) {
+		payload, err := peerConfig.GetBlocks([][]byte{cp.BlockHash[:]})
+		if err != nil || len(payload) == 0 {
+			return
+		}
+		var blockObj types.Block
+		if err := rlp.DecodeBytes(payload[0], &blockObj); err != nil {
+			return
+		}
+		if err := verifyCheckpointBlock(&blockObj, cp); err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+				Str("peerIP", peerConfig.ip).
+				Str("peerPort", peerConfig.port).
+				Msg("[SYNC] getCheckpointBlock: invalid block")
+			return
+		}
+		block = &blockObj
+		return true
+	})
+	if block == nil {
+		return nil, ErrGetBlock
+	}
+	return block, nil
+}
+
+// verifyCheckpointBlock checks the block against the checkpoint. The block
+// being the last of its epoch, the shard state it carries is that of the next
+// epoch, which must be the checkpoint epoch it is stored under.
+func verifyCheckpointBlock(block *types.Block, cp *checkpoint.Checkpoint) error {
+	if block.Hash() != cp.BlockHash || block.NumberU64() != cp.BlockNumber ||
+		block.ShardID() != cp.ShardID {
+		return ErrCheckpointMismatch
+	}
+	if block.Epoch().Uint64()+1 != cp.Epoch {
+		return errors.Wrapf(
+			ErrCheckpointMismatch, "shard state of epoch %d, checkpoint of epoch %d",
+			block.Epoch().Uint64()+1, cp.Epoch,
+		)
+	}
+	if crypto.Keccak256Hash(block.Header().ShardState()) != cp.ShardStateHash {
+		return errors.Wrap(ErrCheckpointMismatch, "shard state hash")
+	}
+	return nil
+}
+
+// downloadState downloads the state trie of the given root from the peers
+// serving state nodes and stores it.
+func (ss *StateSync) downloadState(bc *core.BlockChain, root common.Hash) error {
+	peers := ss.capablePeers(downloader.FeatureStateNodes)
+	if len(peers) == 0 {
+		return ErrNoCapablePeer
+	}
+	sched := state.NewStateSync(root, bc.ChainDb())
+	// hashes requested from the scheduler but not delivered yet
+	var retry []common.Hash
+	failures := 0
+	for next := 0; sched.Pending() > 0; next++ {
+		hashes := retry
+		if len(hashes) < MaxStateNodesPerRequest {
+			hashes = append(hashes, sched.Missing(MaxStateNodesPerRequest-len(hashes))...)
+		}
+		retry = nil
+		peerConfig := peers[next%len(peers)]
+		nodes, err := peerConfig.GetStateNodes(hashes)
+		results := make([]trie.SyncResult, 0, len(hashes))
+		if err == nil {
+			for i, hash := range hashes {
+				if len(nodes[i]) == 0 || crypto.Keccak256Hash(nodes[i]) != hash {
+					retry = append(retry, hash)
+					continue
+				}
+				results = append(results, trie.SyncResult{Hash: hash, Data: nodes[i]})
+			}
+		} else {
+			retry = hashes
+		}
+		if len(results) == 0 {
+			failures++
+			if failures > downloadBlocksRetryLimit*len(peers) {
+				return ErrGetStateNodes
+			}
+			continue
+		}
+		failures = 0
+		if _, index, err := sched.Process(results); err != nil {
+			return errors.Wrapf(err, "state node %x", results[index].Hash)
+		}
+		batch := bc.ChainDb().NewBatch()
+		if _, err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
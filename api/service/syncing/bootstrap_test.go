@@ -0,0 +1,51 @@
+package syncing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/checkpoint"
+	"github.com/pkg/errors"
+)
+
+func TestVerifyCheckpointBlock(t *testing.T) {
+	shardState := []byte{0xc0}
+	header := blockfactory.NewTestHeader().With().
+		Number(big.NewInt(32)).
+		Epoch(big.NewInt(3)).
+		ShardID(1).
+		ShardState(shardState).
+		Header()
+	block := types.NewBlockWithHeader(header)
+	valid := checkpoint.Checkpoint{
+		ShardID:        1,
+		Epoch:          4,
+		BlockNumber:    32,
+		BlockHash:      block.Hash(),
+		ShardStateHash: crypto.Keccak256Hash(shardState),
+	}
+
+	for _, test := range []struct {
+		name     string
+		change   func(cp *checkpoint.Checkpoint)
+		expected error
+	}{
+		{"matching", func(cp *checkpoint.Checkpoint) {}, nil},
+		{"other hash", func(cp *checkpoint.Checkpoint) { cp.BlockHash = common.Hash{1} }, ErrCheckpointMismatch},
+		{"other number", func(cp *checkpoint.Checkpoint) { cp.BlockNumber = 31 }, ErrCheckpointMismatch},
+		{"other shard", func(cp *checkpoint.Checkpoint) { cp.ShardID = 0 }, ErrCheckpointMismatch},
+		{"epoch of the block", func(cp *checkpoint.Checkpoint) { cp.Epoch = 3 }, ErrCheckpointMismatch},
+		{"later epoch", func(cp *checkpoint.Checkpoint) { cp.Epoch = 5 }, ErrCheckpointMismatch},
+		{"other shard state", func(cp *checkpoint.Checkpoint) { cp.ShardStateHash = common.Hash{1} }, ErrCheckpointMismatch},
+	} {
+		cp := valid
+		test.change(&cp)
+		if err := verifyCheckpointBlock(block, &cp); errors.Cause(err) != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+	}
+}
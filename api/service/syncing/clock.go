@@ -0,0 +1,103 @@
+package syncing
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Constants for the clock skew detection
+const (
+	// MinClockSamples is the number of peer clocks needed to estimate the skew
+	MinClockSamples = 3
+	// maxClockRoundTrip is the longest round trip of a height query whose peer
+	// clock is sampled, the estimate being only as precise as half of it
+	maxClockRoundTrip = time.Second
+)
+
+// EncodePeerTime encodes the time reported to the sync peers along with the
+// height of the node
+func EncodePeerTime(now time.Time) []byte {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, uint64(now.UnixNano()))
+	return encoded
+}
+
+// clockOffset estimates the offset of the clock of a peer to the local clock,
+// NTP style, from the time it reported and the times its query was sent and
+// answered, ok false if the round trip is too long for a sample
+func clockOffset(peerTime []byte, sent, received time.Time) (offset time.Duration, ok bool) {
+	if len(peerTime) != 8 {
+		return 0, false
+	}
+	rtt := received.Sub(sent)
+	if rtt < 0 || rtt > maxClockRoundTrip {
+		return 0, false
+	}
+	remote := time.Unix(0, int64(binary.BigEndian.Uint64(peerTime)))
+	return remote.Sub(sent.Add(rtt / 2)), true
+}
+
+// ClockSkew tracks the skew of the local clock to the median clock of the
+// sync peers. The local clock is ahead of the peers for a negative skew.
+type ClockSkew struct {
+	lock      sync.RWMutex
+	maxSkew   time.Duration
+	skew      time.Duration
+	samples   int
+	exceeded  bool
+	updatedAt time.Time
+}
+
+// NewClockSkew creates a skew tracker warning above the given skew, 0 for no limit
+func NewClockSkew(maxSkew time.Duration) *ClockSkew {
+	return &ClockSkew{maxSkew: maxSkew}
+}
+
+// Update records the clock offsets of the peers, the skew being their median.
+// Too few offsets are ignored.
+func (c *ClockSkew) Update(offsets []time.Duration) {
+	if len(offsets) < MinClockSamples {
+		return
+	}
+	sorted := append([]time.Duration{}, offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	skew := sorted[len(sorted)/2]
+
+	c.lock.Lock()
+	c.skew, c.samples, c.updatedAt = skew, len(sorted), time.Now()
+	wasExceeded := c.exceeded
+	c.exceeded = c.maxSkew > 0 && (skew > c.maxSkew || skew < -c.maxSkew)
+	exceeded := c.exceeded
+	c.lock.Unlock()
+
+	metrics.GetOrRegisterGauge("sync/clock/skew_ms", nil).Update(skew.Milliseconds())
+	if exceeded && !wasExceeded {
+		utils.Logger().Warn().
+			Dur("skew", skew).
+			Dur("maxSkew", c.maxSkew).
+			Int("peers", len(sorted)).
+			Msg("[SYNC] local clock skewed from the sync peers, check the time synchronization of the host")
+	} else if !exceeded && wasExceeded {
+		utils.Logger().Info().Dur("skew", skew).Msg("[SYNC] local clock back in sync with the sync peers")
+	}
+}
+
+// Skew returns the last estimated skew and the number of peers it is from,
+// 0 if never estimated
+func (c *ClockSkew) Skew() (time.Duration, int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.skew, c.samples
+}
+
+// Exceeded tells whether the last estimated skew is beyond the limit
+func (c *ClockSkew) Exceeded() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.exceeded
+}
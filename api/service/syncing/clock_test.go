@@ -0,0 +1,43 @@
+package syncing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockOffset(t *testing.T) {
+	sent := time.Now()
+	received := sent.Add(200 * time.Millisecond)
+	offset, ok := clockOffset(EncodePeerTime(sent.Add(3*time.Second)), sent, received)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second-100*time.Millisecond, offset)
+
+	_, ok = clockOffset(EncodePeerTime(sent), sent, sent.Add(2*maxClockRoundTrip))
+	assert.False(t, ok)
+	_, ok = clockOffset([]byte{1, 2}, sent, received)
+	assert.False(t, ok)
+}
+
+func TestClockSkew(t *testing.T) {
+	clock := NewClockSkew(time.Second)
+	clock.Update([]time.Duration{5 * time.Second, 5 * time.Second})
+	skew, peers := clock.Skew()
+	assert.Equal(t, time.Duration(0), skew)
+	assert.Equal(t, 0, peers)
+	assert.False(t, clock.Exceeded())
+
+	clock.Update([]time.Duration{-3 * time.Second, -2 * time.Second, 10 * time.Millisecond, -4 * time.Second})
+	skew, peers = clock.Skew()
+	assert.Equal(t, -2*time.Second, skew)
+	assert.Equal(t, 4, peers)
+	assert.True(t, clock.Exceeded())
+
+	clock.Update([]time.Duration{time.Millisecond, 5 * time.Second, -time.Millisecond})
+	assert.False(t, clock.Exceeded())
+
+	unlimited := NewClockSkew(0)
+	unlimited.Update([]time.Duration{time.Hour, time.Hour, time.Hour})
+	assert.False(t, unlimited.Exceeded())
+}
@@ -0,0 +1,253 @@
+package downloader
+
+import (
+	"crypto/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+// Policies of the downloader server for the peers not authenticated
+const (
+	// AuthNone answers any peer
+	AuthNone = "none"
+	// AuthRateLimit answers the anonymous peers at a limited rate
+	AuthRateLimit = "ratelimit"
+	// AuthRequire answers the anonymous peers the handshake, authentication
+	// and height requests only, at a limited rate
+	AuthRequire = "require"
+)
+
+const (
+	// authSessionTTL is the idle time after which an authentication expires
+	authSessionTTL = 30 * time.Minute
+	// challengeTTL is the time a challenge can be signed in
+	challengeTTL = time.Minute
+	// maxAuthPeers bounds the number of peers tracked, the expired ones and
+	// then the oldest challenged ones being dropped beyond it
+	maxAuthPeers = 4096
+	// authDomain separates the signatures of the challenges from the other
+	// signatures of the BLS keys
+	authDomain = "harmony-sync-auth"
+)
+
+// AuthSigningHash returns the hash signed to authenticate to the sync server
+// of the given peer with its challenge. Binding the server makes the signature
+// useless to any other server the challenge would be relayed from.
+func AuthSigningHash(server libp2p_peer.ID, challenge []byte) []byte {
+	return crypto.Keccak256([]byte(authDomain), []byte(server), challenge)
+}
+
+// authPeer is the authentication state of a connection of a sync peer
+type authPeer struct {
+	challenge []byte
+	issued    time.Time
+	key       *bls.PublicKey // nil until authenticated
+	expires   time.Time
+}
+
+// bucket is a token bucket limiting the request rate of an anonymous host
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Authenticator authenticates the sync peers proving they hold a BLS key of a
+// committee with a signature of a challenge, and limits the requests of the
+// other peers according to its policy. Peers are authenticated per
+// connection, and rate limited per host.
+type Authenticator struct {
+	policy         string
+	self           libp2p_peer.ID // the peer the signatures are bound to
+	isCommitteeKey func(*bls.PublicKey) bool
+	rate           float64 // anonymous requests per second of a host
+	burst          float64
+
+	lock    sync.Mutex
+	peers   map[string]*authPeer // connection address => state
+	buckets map[string]*bucket   // host => anonymous request budget
+}
+
+// NewAuthenticator creates an authenticator with the given policy for the
+// server of the given peer, checking the committee membership of the keys with
+// the given function. Anonymous hosts are limited to rate requests per second,
+// the handshakes and authentications included.
+func NewAuthenticator(
+	policy string, self libp2p_peer.ID, isCommitteeKey func(*bls.PublicKey) bool, rate uint,
+) (*Authenticator, error) {
+	switch policy {
+	case AuthNone, AuthRateLimit, AuthRequire:
+	default:
+		return nil, errors.Wrap(ErrUnknownAuthPolicy, policy)
+	}
+	if rate == 0 {
+		rate = 1
+	}
+	return &Authenticator{
+		policy:         policy,
+		self:           self,
+		isCommitteeKey: isCommitteeKey,
+		rate:           float64(rate),
+		burst:          2 * float64(rate),
+		peers:          map[string]*authPeer{},
+		buckets:        map[string]*bucket{},
+	}, nil
+}
+
+// Challenge returns a new nonce for the peer of the given connection to sign
+func (a *Authenticator) Challenge(peer string) ([]byte, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	p, ok := a.peers[peer]
+	if !ok {
+		a.prune()
+		if len(a.peers) >= maxAuthPeers && !a.evictChallenged() {
+			return nil, ErrRateLimited
+		}
+		p = &authPeer{}
+		a.peers[peer] = p
+	}
+	p.challenge, p.issued = challenge, time.Now()
+	return challenge, nil
+}
+
+// Authenticate authenticates the peer of the given connection with the
+// signature of its last challenge by a committee key. The challenge is
+// consumed, and the peer dropped if not authenticated before.
+func (a *Authenticator) Authenticate(peer string, key, sig []byte) error {
+	a.lock.Lock()
+	p, ok := a.peers[peer]
+	if !ok || p.challenge == nil || time.Since(p.issued) > challengeTTL {
+		a.lock.Unlock()
+		return ErrNoChallenge
+	}
+	challenge := p.challenge
+	p.challenge = nil
+	a.lock.Unlock()
+
+	pubKey, err := a.verify(challenge, key, sig)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err != nil {
+		if p.key == nil && a.peers[peer] == p {
+			delete(a.peers, peer)
+		}
+		return err
+	}
+	p.key, p.expires = pubKey, time.Now().Add(authSessionTTL)
+	return nil
+}
+
+// verify checks the signature of the challenge bound to the server by the
+// key, and the committee membership of the key
+func (a *Authenticator) verify(challenge, key, sig []byte) (*bls.PublicKey, error) {
+	pubKey := &bls.PublicKey{}
+	if err := pubKey.Deserialize(key); err != nil {
+		return nil, errors.Wrap(ErrBadAuthSig, err.Error())
+	}
+	signature := &bls.Sign{}
+	if err := signature.Deserialize(sig); err != nil {
+		return nil, errors.Wrap(ErrBadAuthSig, err.Error())
+	}
+	if !signature.VerifyHash(pubKey, AuthSigningHash(a.self, challenge)) {
+		return nil, ErrBadAuthSig
+	}
+	if !a.isCommitteeKey(pubKey) {
+		return nil, ErrNotInCommittee
+	}
+	return pubKey, nil
+}
+
+// Admit returns whether a request of the given type from the peer of the
+// given connection is answered
+func (a *Authenticator) Admit(peer string, requestType pb.DownloaderRequest_RequestType) error {
+	if a.policy == AuthNone {
+		return nil
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	now := time.Now()
+	if p, ok := a.peers[peer]; ok && p.key != nil && now.Before(p.expires) {
+		p.expires = now.Add(authSessionTTL)
+		return nil
+	}
+	switch requestType {
+	case pb.DownloaderRequest_HANDSHAKE, pb.DownloaderRequest_AUTH, pb.DownloaderRequest_BLOCKHEIGHT:
+	default:
+		if a.policy == AuthRequire {
+			return ErrAuthRequired
+		}
+	}
+
+	host, _, err := net.SplitHostPort(peer)
+	if err != nil {
+		host = peer
+	}
+	b, ok := a.buckets[host]
+	if !ok {
+		a.prune()
+		b = &bucket{tokens: a.burst, last: now}
+		a.buckets[host] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * a.rate
+	if b.tokens > a.burst {
+		b.tokens = a.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return ErrRateLimited
+	}
+	b.tokens--
+	return nil
+}
+
+// prune drops the expired peers and the full buckets once too many are
+// tracked. The caller must hold the lock.
+func (a *Authenticator) prune() {
+	now := time.Now()
+	if len(a.peers) >= maxAuthPeers {
+		for addr, p := range a.peers {
+			if now.After(p.expires) && (p.challenge == nil || now.Sub(p.issued) > challengeTTL) {
+				delete(a.peers, addr)
+			}
+		}
+	}
+	if len(a.buckets) >= maxAuthPeers {
+		for host, b := range a.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*a.rate >= a.burst {
+				delete(a.buckets, host)
+			}
+		}
+	}
+}
+
+// evictChallenged drops the peer not authenticated challenged the longest ago,
+// for the peers flooding the server with handshakes not to lock the others
+// out. It returns false if all the peers tracked are authenticated. The caller
+// must hold the lock.
+func (a *Authenticator) evictChallenged() bool {
+	oldest, found := "", false
+	for addr, p := range a.peers {
+		if p.key != nil {
+			continue
+		}
+		if !found || p.issued.Before(a.peers[oldest].issued) {
+			oldest, found = addr, true
+		}
+	}
+	if found {
+		delete(a.peers, oldest)
+	}
+	return found
+}
@@ -0,0 +1,168 @@
+package downloader
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/bls/ffi/go/bls"
+	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
+	bls2 "github.com/harmony-one/harmony/crypto/bls"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	testServer  = libp2p_peer.ID("server")
+	testRelayer = libp2p_peer.ID("relayer")
+)
+
+// newTestAuthenticator returns an authenticator of the test server accepting
+// the keys of the given committee
+func newTestAuthenticator(t *testing.T, policy string, committee ...*bls.SecretKey) *Authenticator {
+	keys := map[string]bool{}
+	for _, key := range committee {
+		keys[key.GetPublicKey().SerializeToHexStr()] = true
+	}
+	auth, err := NewAuthenticator(policy, testServer, func(key *bls.PublicKey) bool {
+		return keys[key.SerializeToHexStr()]
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+func TestAuthenticate(t *testing.T) {
+	member, outsider := bls2.RandPrivateKey(), bls2.RandPrivateKey()
+	for _, test := range []struct {
+		name     string
+		key      *bls.SecretKey // key authenticating
+		signer   *bls.SecretKey // key signing
+		server   libp2p_peer.ID // server the signature is bound to
+		expected error
+	}{
+		{"valid", member, member, testServer, nil},
+		{"relayed", member, member, testRelayer, ErrBadAuthSig},
+		{"wrong key", outsider, outsider, testServer, ErrNotInCommittee},
+		{"signed by another key", member, outsider, testServer, ErrBadAuthSig},
+	} {
+		auth := newTestAuthenticator(t, AuthRequire, member)
+		peer := "10.0.0.1:6000"
+		challenge, err := auth.Challenge(peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := test.signer.SignHash(AuthSigningHash(test.server, challenge))
+		err = auth.Authenticate(peer, test.key.GetPublicKey().Serialize(), sig.Serialize())
+		if errors.Cause(err) != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+		admitted := auth.Admit(peer, pb.DownloaderRequest_BLOCK) == nil
+		if admitted != (test.expected == nil) {
+			t.Errorf("%s: expected the blocks answered %t, got %t", test.name, test.expected == nil, admitted)
+		}
+		if _, tracked := auth.peers[peer]; tracked != (test.expected == nil) {
+			t.Errorf("%s: expected the peer tracked %t, got %t", test.name, test.expected == nil, tracked)
+		}
+	}
+}
+
+func TestAuthenticateReplayed(t *testing.T) {
+	member := bls2.RandPrivateKey()
+	auth := newTestAuthenticator(t, AuthRequire, member)
+	key := member.GetPublicKey().Serialize()
+	first, second := "10.0.0.1:6000", "10.0.0.2:6000"
+
+	challenge, err := auth.Challenge(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := member.SignHash(AuthSigningHash(testServer, challenge)).Serialize()
+	if err := auth.Authenticate(first, key, sig); err != nil {
+		t.Fatal(err)
+	}
+	// the challenge is consumed
+	if err := auth.Authenticate(first, key, sig); err != ErrNoChallenge {
+		t.Errorf("expected the challenge consumed, got %v", err)
+	}
+	// nor can the signature authenticate another connection
+	if _, err := auth.Challenge(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.Authenticate(second, key, sig); err != ErrBadAuthSig {
+		t.Errorf("expected the replayed signature rejected, got %v", err)
+	}
+	if err := auth.Admit(second, pb.DownloaderRequest_BLOCK); err != ErrAuthRequired {
+		t.Errorf("expected the replaying peer not admitted, got %v", err)
+	}
+}
+
+func TestAdmitThrottlesHandshakes(t *testing.T) {
+	member := bls2.RandPrivateKey()
+	auth := newTestAuthenticator(t, AuthRequire, member)
+	anonymous := "10.0.0.1:6000"
+	// a burst of twice the rate of 1 request per second
+	for i, expected := range []error{nil, nil, ErrRateLimited} {
+		if err := auth.Admit(anonymous, pb.DownloaderRequest_HANDSHAKE); err != expected {
+			t.Errorf("handshake %d: expected %v, got %v", i, expected, err)
+		}
+	}
+	if err := auth.Admit(anonymous, pb.DownloaderRequest_AUTH); err != ErrRateLimited {
+		t.Errorf("expected the authentication throttled, got %v", err)
+	}
+	// another connection of the host shares its budget
+	if err := auth.Admit("10.0.0.1:6001", pb.DownloaderRequest_BLOCKHEIGHT); err != ErrRateLimited {
+		t.Errorf("expected the height request throttled, got %v", err)
+	}
+
+	authenticated := "10.0.0.2:6000"
+	challenge, err := auth.Challenge(authenticated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := member.SignHash(AuthSigningHash(testServer, challenge)).Serialize()
+	if err := auth.Authenticate(authenticated, member.GetPublicKey().Serialize(), sig); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := auth.Admit(authenticated, pb.DownloaderRequest_HANDSHAKE); err != nil {
+			t.Errorf("expected the authenticated peer not throttled, got %v", err)
+		}
+	}
+}
+
+func TestChallengeEvictsUnauthenticated(t *testing.T) {
+	member := bls2.RandPrivateKey()
+	auth := newTestAuthenticator(t, AuthRequire, member)
+	authenticated := "10.0.0.1:6000"
+	challenge, err := auth.Challenge(authenticated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := member.SignHash(AuthSigningHash(testServer, challenge)).Serialize()
+	if err := auth.Authenticate(authenticated, member.GetPublicKey().Serialize(), sig); err != nil {
+		t.Fatal(err)
+	}
+	// the peers flooding the server with handshakes fill the table
+	for i := 1; i < maxAuthPeers; i++ {
+		if _, err := auth.Challenge(fmt.Sprintf("10.1.%d.%d:6000", i/256, i%256)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldest := "10.1.0.1:6000"
+	auth.peers[oldest].issued = time.Now().Add(-time.Second)
+
+	if _, err := auth.Challenge("10.2.0.1:6000"); err != nil {
+		t.Fatalf("expected a challenge for a new peer, got %v", err)
+	}
+	if len(auth.peers) != maxAuthPeers {
+		t.Errorf("expected %d peers tracked, got %d", maxAuthPeers, len(auth.peers))
+	}
+	if _, ok := auth.peers[oldest]; ok {
+		t.Error("expected the oldest challenged peer evicted")
+	}
+	if _, ok := auth.peers[authenticated]; !ok {
+		t.Error("expected the authenticated peer kept")
+	}
+}
@@ -3,11 +3,35 @@ package downloader
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/harmony-one/bls/ffi/go/bls"
 	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
 	"github.com/harmony-one/harmony/internal/utils"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Constants for the liveness of the connections to the peers.
+const (
+	// KeepaliveInterval is the idle time of a connection with a query in
+	// flight after which it is pinged, so that dead peers are detected. It is
+	// kept at the 5 minutes gRPC servers enforce by default, below which the
+	// servers of earlier versions close the connection for too many pings.
+	// Idle connections are not pinged, but probed before being reused.
+	KeepaliveInterval = 5 * time.Minute
+	// KeepaliveTimeout is the time a ping is waited for before the
+	// connection is considered dead
+	KeepaliveTimeout = 5 * time.Second
+	// MaxQueryFailures is the number of consecutive failed queries after
+	// which a peer is considered unhealthy
+	MaxQueryFailures = 3
+	// LivenessProbeAge is the time since the last successful query after
+	// which a peer is probed before its connection is reused
+	LivenessProbeAge = 30 * time.Second
 )
 
 // Client is the client model for downloader package.
@@ -15,12 +39,20 @@ type Client struct {
 	dlClient pb.DownloaderClient
 	opts     []grpc.DialOption
 	conn     *grpc.ClientConn
+	failures int32 // consecutive failed queries
+	lastSeen int64 // unix nanoseconds of the last successful query
 }
 
 // ClientSetup setups a Client given ip and port.
 func ClientSetup(ip, port string) *Client {
 	client := Client{}
-	client.opts = append(client.opts, grpc.WithInsecure())
+	client.opts = append(client.opts,
+		grpc.WithInsecure(),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    KeepaliveInterval,
+			Timeout: KeepaliveTimeout,
+		}),
+	)
 	var err error
 	client.conn, err = grpc.Dial(fmt.Sprintf(ip+":"+port), client.opts...)
 	if err != nil {
@@ -40,6 +72,46 @@ func (client *Client) Close() {
 	}
 }
 
+// record tracks the liveness of the peer from the outcome of a query
+func (client *Client) record(err error) {
+	if err != nil {
+		atomic.AddInt32(&client.failures, 1)
+		return
+	}
+	atomic.StoreInt32(&client.failures, 0)
+	atomic.StoreInt64(&client.lastSeen, time.Now().UnixNano())
+}
+
+// Healthy returns whether the connection to the peer is usable: it is not
+// failing, nor closed, and the last queries to the peer did not all fail.
+func (client *Client) Healthy() bool {
+	switch client.conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	}
+	return atomic.LoadInt32(&client.failures) < MaxQueryFailures
+}
+
+// LastSeen returns the time of the last successful query to the peer, the
+// zero time if none succeeded
+func (client *Client) LastSeen() time.Time {
+	lastSeen := atomic.LoadInt64(&client.lastSeen)
+	if lastSeen == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, lastSeen)
+}
+
+// Probe checks that the peer still answers queries, unless it answered one
+// in the last LivenessProbeAge
+func (client *Client) Probe() error {
+	if time.Since(client.LastSeen()) < LivenessProbeAge {
+		return nil
+	}
+	_, err := client.GetBlockChainHeight()
+	return err
+}
+
 // GetBlockHashes gets block hashes from all the peers by calling grpc request.
 func (client *Client) GetBlockHashes(startHash []byte, size uint32, ip, port string) *pb.DownloaderResponse {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -48,6 +120,7 @@ func (client *Client) GetBlockHashes(startHash []byte, size uint32, ip, port str
 	request.Ip = ip
 	request.Port = port
 	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
 	if err != nil {
 		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] GetBlockHashes query failed")
 	}
@@ -65,6 +138,7 @@ func (client *Client) GetBlockHeaders(hashes [][]byte) *pb.DownloaderResponse {
 		copy(request.Hashes[i], hashes[i])
 	}
 	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
 	if err != nil {
 		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] downloader/client.go:GetBlockHeaders query failed")
 	}
@@ -82,6 +156,7 @@ func (client *Client) GetBlocks(hashes [][]byte) *pb.DownloaderResponse {
 		copy(request.Hashes[i], hashes[i])
 	}
 	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
 	if err != nil {
 		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] downloader/client.go:GetBlocks query failed")
 	}
@@ -99,6 +174,7 @@ func (client *Client) Register(hash []byte, ip, port string) *pb.DownloaderRespo
 	request.Ip = ip
 	request.Port = port
 	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
 	if err != nil || response == nil {
 		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Interface("response", response).Msg("[SYNC] client.go:Register failed")
 	}
@@ -121,6 +197,7 @@ func (client *Client) PushNewBlock(selfPeerHash [20]byte, blockHash []byte, time
 	}
 
 	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
 	if err != nil {
 		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] unable to send new block to unsync node")
 	}
@@ -133,8 +210,88 @@ func (client *Client) GetBlockChainHeight() (*pb.DownloaderResponse, error) {
 	defer cancel()
 	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_BLOCKHEIGHT}
 	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
+
+// Handshake exchanges the protocol version and capabilities with the peer
+func (client *Client) Handshake(local *pb.Handshake) (*pb.Handshake, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_HANDSHAKE, Handshake: local}
+	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetHandshake(), nil
+}
+
+// Auth authenticates to the server of the given peer with the signature by the
+// given committee key of the challenge the server sent in its handshake
+func (client *Client) Auth(key *bls.SecretKey, server libp2p_peer.ID, challenge []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{
+		Type:    pb.DownloaderRequest_AUTH,
+		AuthKey: key.GetPublicKey().Serialize(),
+		AuthSig: key.SignHash(AuthSigningHash(server, challenge)).Serialize(),
+	}
+	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
+	if err != nil {
+		return err
+	}
+	if response.Type != pb.DownloaderResponse_SUCCESS {
+		return ErrBadAuthSig
+	}
+	return nil
+}
+
+// GetStateNodes gets the trie nodes of the given hashes by calling a grpc request.
+func (client *Client) GetStateNodes(hashes [][]byte) *pb.DownloaderResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_STATENODE}
+	request.Hashes = make([][]byte, len(hashes))
+	for i := range hashes {
+		request.Hashes[i] = make([]byte, len(hashes[i]))
+		copy(request.Hashes[i], hashes[i])
+	}
+	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
+	if err != nil {
+		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] downloader/client.go:GetStateNodes query failed")
+	}
+	return response
+}
+
+// GetCommitSig gets the commit signature and bitmap signed on the block of
+// the given number by calling a grpc request.
+func (client *Client) GetCommitSig(number uint64) *pb.DownloaderResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_COMMITSIG, BlockNumber: number}
+	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
+	if err != nil {
+		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] downloader/client.go:GetCommitSig query failed")
+	}
+	return response
+}
+
+// GetCanonicalHeaders gets the RLP encoded headers of the canonical blocks starting at the given number by calling a grpc request.
+func (client *Client) GetCanonicalHeaders(number uint64, size uint32) *pb.DownloaderResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_CANONICALHEADERS, BlockNumber: number, Size: size}
+	response, err := client.dlClient.Query(ctx, request)
+	client.record(err)
+	if err != nil {
+		utils.Logger().Error().Err(err).Str("target", client.conn.Target()).Msg("[SYNC] downloader/client.go:GetCanonicalHeaders query failed")
+	}
+	return response
+}
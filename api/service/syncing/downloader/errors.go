@@ -5,4 +5,10 @@ import "errors"
 // Errors for downloader package.
 var (
 	ErrDownloaderWithNoNode = errors.New("no node attached")
+	ErrUnknownAuthPolicy    = errors.New("unknown sync authentication policy")
+	ErrAuthRequired         = errors.New("authentication required")
+	ErrRateLimited          = errors.New("request rate of anonymous peer exceeded")
+	ErrNoChallenge          = errors.New("no pending challenge")
+	ErrBadAuthSig           = errors.New("invalid challenge signature")
+	ErrNotInCommittee       = errors.New("key not in any committee")
 )
@@ -0,0 +1,52 @@
+package downloader
+
+import (
+	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
+)
+
+// ProtocolVersion is the version of the downloader protocol spoken by this node.
+// Peers which do not answer the handshake are considered to speak LegacyProtocolVersion.
+const (
+	ProtocolVersion       = 1
+	LegacyProtocolVersion = 0
+)
+
+// Sync features advertised in the handshake
+const (
+	FeatureRangeRequest     = "range"
+	FeatureStateNodes       = "statenodes"
+	FeatureCanonicalHeaders = "canonicalheaders"
+	FeatureSnapshots        = "snapshots"
+	FeatureCommitSig        = "commitsig"
+)
+
+// SupportedFeatures are the sync features served by this node
+var SupportedFeatures = []string{
+	FeatureRangeRequest, FeatureStateNodes, FeatureCanonicalHeaders, FeatureCommitSig,
+}
+
+// NewHandshake creates the handshake advertising the capabilities of this
+// node, along with the version of its software and whether it is archival
+func NewHandshake(shardID uint32, role, version string, archival bool) *pb.Handshake {
+	features := make([]string, len(SupportedFeatures))
+	copy(features, SupportedFeatures)
+	return &pb.Handshake{
+		ProtocolVersion: ProtocolVersion,
+		Features:        features,
+		ShardID:         shardID,
+		Role:            role,
+		Version:         version,
+		Archival:        archival,
+	}
+}
+
+// HasFeature returns whether the handshake advertises the given feature.
+// A nil handshake (legacy peer) has no feature.
+func HasFeature(handshake *pb.Handshake, feature string) bool {
+	for _, f := range handshake.GetFeatures() {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
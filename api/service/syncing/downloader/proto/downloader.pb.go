@@ -27,36 +27,54 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type DownloaderRequest_RequestType int32
 
 const (
-	DownloaderRequest_BLOCKHASH       DownloaderRequest_RequestType = 0
-	DownloaderRequest_BLOCK           DownloaderRequest_RequestType = 1
-	DownloaderRequest_NEWBLOCK        DownloaderRequest_RequestType = 2
-	DownloaderRequest_BLOCKHEIGHT     DownloaderRequest_RequestType = 3
-	DownloaderRequest_REGISTER        DownloaderRequest_RequestType = 4
-	DownloaderRequest_REGISTERTIMEOUT DownloaderRequest_RequestType = 5
-	DownloaderRequest_UNKNOWN         DownloaderRequest_RequestType = 6
-	DownloaderRequest_BLOCKHEADER     DownloaderRequest_RequestType = 7
+	DownloaderRequest_BLOCKHASH        DownloaderRequest_RequestType = 0
+	DownloaderRequest_BLOCK            DownloaderRequest_RequestType = 1
+	DownloaderRequest_NEWBLOCK         DownloaderRequest_RequestType = 2
+	DownloaderRequest_BLOCKHEIGHT      DownloaderRequest_RequestType = 3
+	DownloaderRequest_REGISTER         DownloaderRequest_RequestType = 4
+	DownloaderRequest_REGISTERTIMEOUT  DownloaderRequest_RequestType = 5
+	DownloaderRequest_UNKNOWN          DownloaderRequest_RequestType = 6
+	DownloaderRequest_BLOCKHEADER      DownloaderRequest_RequestType = 7
+	DownloaderRequest_HANDSHAKE        DownloaderRequest_RequestType = 8
+	DownloaderRequest_RECEIPTS         DownloaderRequest_RequestType = 9
+	DownloaderRequest_STATENODE        DownloaderRequest_RequestType = 10
+	DownloaderRequest_CANONICALHEADERS DownloaderRequest_RequestType = 11
+	DownloaderRequest_AUTH             DownloaderRequest_RequestType = 12
+	DownloaderRequest_COMMITSIG        DownloaderRequest_RequestType = 13
 )
 
 var DownloaderRequest_RequestType_name = map[int32]string{
-	0: "BLOCKHASH",
-	1: "BLOCK",
-	2: "NEWBLOCK",
-	3: "BLOCKHEIGHT",
-	4: "REGISTER",
-	5: "REGISTERTIMEOUT",
-	6: "UNKNOWN",
-	7: "BLOCKHEADER",
+	0:  "BLOCKHASH",
+	1:  "BLOCK",
+	2:  "NEWBLOCK",
+	3:  "BLOCKHEIGHT",
+	4:  "REGISTER",
+	5:  "REGISTERTIMEOUT",
+	6:  "UNKNOWN",
+	7:  "BLOCKHEADER",
+	8:  "HANDSHAKE",
+	9:  "RECEIPTS",
+	10: "STATENODE",
+	11: "CANONICALHEADERS",
+	12: "AUTH",
+	13: "COMMITSIG",
 }
 
 var DownloaderRequest_RequestType_value = map[string]int32{
-	"BLOCKHASH":       0,
-	"BLOCK":           1,
-	"NEWBLOCK":        2,
-	"BLOCKHEIGHT":     3,
-	"REGISTER":        4,
-	"REGISTERTIMEOUT": 5,
-	"UNKNOWN":         6,
-	"BLOCKHEADER":     7,
+	"BLOCKHASH":        0,
+	"BLOCK":            1,
+	"NEWBLOCK":         2,
+	"BLOCKHEIGHT":      3,
+	"REGISTER":         4,
+	"REGISTERTIMEOUT":  5,
+	"UNKNOWN":          6,
+	"BLOCKHEADER":      7,
+	"HANDSHAKE":        8,
+	"RECEIPTS":         9,
+	"STATENODE":        10,
+	"CANONICALHEADERS": 11,
+	"AUTH":             12,
+	"COMMITSIG":        13,
 }
 
 func (x DownloaderRequest_RequestType) String() string {
@@ -100,12 +118,20 @@ type DownloaderRequest struct {
 	// Request type.
 	Type DownloaderRequest_RequestType `protobuf:"varint,1,opt,name=type,proto3,enum=downloader.DownloaderRequest_RequestType" json:"type,omitempty"`
 	// The hashes of the blocks we want to download.
-	Hashes               [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
-	PeerHash             []byte   `protobuf:"bytes,3,opt,name=peerHash,proto3" json:"peerHash,omitempty"`
-	BlockHash            []byte   `protobuf:"bytes,4,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
-	Ip                   string   `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`
-	Port                 string   `protobuf:"bytes,6,opt,name=port,proto3" json:"port,omitempty"`
-	Size                 uint32   `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`
+	Hashes    [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	PeerHash  []byte   `protobuf:"bytes,3,opt,name=peerHash,proto3" json:"peerHash,omitempty"`
+	BlockHash []byte   `protobuf:"bytes,4,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Ip        string   `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port      string   `protobuf:"bytes,6,opt,name=port,proto3" json:"port,omitempty"`
+	Size      uint32   `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`
+	// Capabilities of the requesting node, set on HANDSHAKE.
+	Handshake *Handshake `protobuf:"bytes,8,opt,name=handshake,proto3" json:"handshake,omitempty"`
+	// First block number of CANONICALHEADERS, block number of COMMITSIG.
+	BlockNumber uint64 `protobuf:"varint,9,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	// Committee BLS public key of the requesting node and its signature of
+	// the challenge of the server, set on AUTH.
+	AuthKey              []byte   `protobuf:"bytes,10,opt,name=authKey,proto3" json:"authKey,omitempty"`
+	AuthSig              []byte   `protobuf:"bytes,11,opt,name=authSig,proto3" json:"authSig,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -185,16 +211,46 @@ func (m *DownloaderRequest) GetSize() uint32 {
 	return 0
 }
 
+func (m *DownloaderRequest) GetHandshake() *Handshake {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+func (m *DownloaderRequest) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *DownloaderRequest) GetAuthKey() []byte {
+	if m != nil {
+		return m.AuthKey
+	}
+	return nil
+}
+
+func (m *DownloaderRequest) GetAuthSig() []byte {
+	if m != nil {
+		return m.AuthSig
+	}
+	return nil
+}
+
 // DownloaderResponse is the generic response of DownloaderRequest.
 type DownloaderResponse struct {
 	// payload of Block.
 	Payload [][]byte `protobuf:"bytes,1,rep,name=payload,proto3" json:"payload,omitempty"`
 	// response of registration request
-	Type                 DownloaderResponse_RegisterResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=downloader.DownloaderResponse_RegisterResponseType" json:"type,omitempty"`
-	BlockHeight          uint64                                  `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                                `json:"-"`
-	XXX_unrecognized     []byte                                  `json:"-"`
-	XXX_sizecache        int32                                   `json:"-"`
+	Type        DownloaderResponse_RegisterResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=downloader.DownloaderResponse_RegisterResponseType" json:"type,omitempty"`
+	BlockHeight uint64                                  `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	// Capabilities of the responding node, set on HANDSHAKE.
+	Handshake            *Handshake `protobuf:"bytes,4,opt,name=handshake,proto3" json:"handshake,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *DownloaderResponse) Reset()         { *m = DownloaderResponse{} }
@@ -243,11 +299,112 @@ func (m *DownloaderResponse) GetBlockHeight() uint64 {
 	return 0
 }
 
+func (m *DownloaderResponse) GetHandshake() *Handshake {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+// Handshake is exchanged between sync peers to negotiate the protocol.
+type Handshake struct {
+	ProtocolVersion uint32 `protobuf:"varint,1,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	// Supported sync features (range requests, receipts, snapshots...).
+	Features []string `protobuf:"bytes,2,rep,name=features,proto3" json:"features,omitempty"`
+	ShardID  uint32   `protobuf:"varint,3,opt,name=shardID,proto3" json:"shardID,omitempty"`
+	// Role of the node, e.g. Validator or ExplorerNode.
+	Role string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	// Nonce to sign to authenticate, set by the responding node.
+	Challenge []byte `protobuf:"bytes,5,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// Version of the node software.
+	Version string `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	// Whether the node keeps the full history of the chain.
+	Archival             bool     `protobuf:"varint,7,opt,name=archival,proto3" json:"archival,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Handshake) Reset()         { *m = Handshake{} }
+func (m *Handshake) String() string { return proto.CompactTextString(m) }
+func (*Handshake) ProtoMessage()    {}
+func (*Handshake) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6a99ec95c7ab1ff1, []int{2}
+}
+
+func (m *Handshake) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Handshake.Unmarshal(m, b)
+}
+func (m *Handshake) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Handshake.Marshal(b, m, deterministic)
+}
+func (m *Handshake) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Handshake.Merge(m, src)
+}
+func (m *Handshake) XXX_Size() int {
+	return xxx_messageInfo_Handshake.Size(m)
+}
+func (m *Handshake) XXX_DiscardUnknown() {
+	xxx_messageInfo_Handshake.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Handshake proto.InternalMessageInfo
+
+func (m *Handshake) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *Handshake) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *Handshake) GetShardID() uint32 {
+	if m != nil {
+		return m.ShardID
+	}
+	return 0
+}
+
+func (m *Handshake) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *Handshake) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *Handshake) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Handshake) GetArchival() bool {
+	if m != nil {
+		return m.Archival
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("downloader.DownloaderRequest_RequestType", DownloaderRequest_RequestType_name, DownloaderRequest_RequestType_value)
 	proto.RegisterEnum("downloader.DownloaderResponse_RegisterResponseType", DownloaderResponse_RegisterResponseType_name, DownloaderResponse_RegisterResponseType_value)
 	proto.RegisterType((*DownloaderRequest)(nil), "downloader.DownloaderRequest")
 	proto.RegisterType((*DownloaderResponse)(nil), "downloader.DownloaderResponse")
+	proto.RegisterType((*Handshake)(nil), "downloader.Handshake")
 }
 
 func init() {
@@ -255,33 +412,47 @@ func init() {
 }
 
 var fileDescriptor_6a99ec95c7ab1ff1 = []byte{
-	// 410 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xce, 0x3a, 0x8e, 0x93, 0x4c, 0xd2, 0x76, 0x19, 0x10, 0x5a, 0x55, 0x80, 0x2c, 0x9f, 0xcc,
-	0xc5, 0x87, 0xf6, 0xc4, 0x81, 0x43, 0x70, 0x4d, 0x6c, 0xb5, 0x38, 0x62, 0xed, 0x50, 0x71, 0x74,
-	0xe9, 0xaa, 0xb6, 0xa8, 0xea, 0xc5, 0xeb, 0x0a, 0x99, 0x17, 0xe0, 0x05, 0x79, 0x06, 0x9e, 0x03,
-	0x79, 0x9d, 0xd6, 0x96, 0x80, 0x9c, 0x76, 0xbf, 0x6f, 0x7e, 0x34, 0xf3, 0x7d, 0x03, 0xf4, 0xba,
-	0xfc, 0x7e, 0x77, 0x5b, 0x66, 0xd7, 0xa2, 0xf2, 0x64, 0x55, 0xd6, 0x25, 0x42, 0xcf, 0x38, 0xbf,
-	0x0d, 0x78, 0x72, 0xf6, 0x08, 0xb9, 0xf8, 0x76, 0x2f, 0x54, 0x8d, 0x6f, 0xc1, 0xac, 0x1b, 0x29,
-	0x18, 0xb1, 0x89, 0x7b, 0x78, 0xf2, 0xda, 0x1b, 0xb4, 0xf8, 0x2b, 0xd9, 0xdb, 0xbd, 0x69, 0x23,
-	0x05, 0xd7, 0x65, 0xf8, 0x1c, 0xac, 0x3c, 0x53, 0xb9, 0x50, 0xcc, 0xb0, 0xc7, 0xee, 0x92, 0xef,
-	0x10, 0x1e, 0xc3, 0x4c, 0x0a, 0x51, 0x85, 0x99, 0xca, 0xd9, 0xd8, 0x26, 0xee, 0x92, 0x3f, 0x62,
-	0x7c, 0x01, 0xf3, 0xab, 0xdb, 0xf2, 0xcb, 0x57, 0x1d, 0x34, 0x75, 0xb0, 0x27, 0xf0, 0x10, 0x8c,
-	0x42, 0xb2, 0x89, 0x4d, 0xdc, 0x39, 0x37, 0x0a, 0x89, 0x08, 0xa6, 0x2c, 0xab, 0x9a, 0x59, 0x9a,
-	0xd1, 0xff, 0x96, 0x53, 0xc5, 0x0f, 0xc1, 0xa6, 0x36, 0x71, 0x0f, 0xb8, 0xfe, 0x3b, 0x3f, 0x09,
-	0x2c, 0x06, 0xf3, 0xe1, 0x01, 0xcc, 0xdf, 0x5d, 0x6c, 0xfc, 0xf3, 0x70, 0x95, 0x84, 0x74, 0x84,
-	0x73, 0x98, 0x68, 0x48, 0x09, 0x2e, 0x61, 0x16, 0x07, 0x97, 0x1d, 0x32, 0xf0, 0x08, 0x16, 0x5d,
-	0x5e, 0x10, 0xad, 0xc3, 0x94, 0x8e, 0xdb, 0x30, 0x0f, 0xd6, 0x51, 0x92, 0x06, 0x9c, 0x9a, 0xf8,
-	0x14, 0x8e, 0x1e, 0x50, 0x1a, 0x7d, 0x08, 0x36, 0xdb, 0x94, 0x4e, 0x70, 0x01, 0xd3, 0x6d, 0x7c,
-	0x1e, 0x6f, 0x2e, 0x63, 0x6a, 0x0d, 0x1a, 0xac, 0xce, 0x02, 0x4e, 0xa7, 0xce, 0x2f, 0x02, 0x38,
-	0xd4, 0x4e, 0xc9, 0xf2, 0x4e, 0x09, 0x64, 0x30, 0x95, 0x59, 0xd3, 0x92, 0x8c, 0x68, 0xad, 0x1e,
-	0x20, 0xae, 0x77, 0x1e, 0x18, 0xda, 0x83, 0xd3, 0xff, 0x79, 0xd0, 0xf5, 0xf1, 0xb8, 0xb8, 0x29,
-	0x54, 0xdd, 0x13, 0x03, 0x37, 0x6c, 0x58, 0x74, 0x42, 0x8a, 0xe2, 0x26, 0xaf, 0xb5, 0xf0, 0x26,
-	0x1f, 0x52, 0xce, 0x1b, 0x78, 0xf6, 0xaf, 0xfa, 0x76, 0xa3, 0x64, 0xeb, 0xfb, 0x41, 0x92, 0xd0,
-	0x11, 0xce, 0xc0, 0x7c, 0xbf, 0x8a, 0x2e, 0x28, 0x41, 0x00, 0x2b, 0x8a, 0x93, 0xcf, 0xb1, 0x4f,
-	0x8d, 0x93, 0x4f, 0x00, 0xfd, 0x34, 0x18, 0xc2, 0xe4, 0xe3, 0xbd, 0xa8, 0x1a, 0x7c, 0xb9, 0xf7,
-	0x64, 0x8e, 0x5f, 0xed, 0xdf, 0xc6, 0x19, 0x5d, 0x59, 0xfa, 0x54, 0x4f, 0xff, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x00, 0xaf, 0x33, 0x33, 0xbe, 0x02, 0x00, 0x00,
+	// 637 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcd, 0x72, 0x9b, 0x3c,
+	0x14, 0x0d, 0x36, 0xfe, 0xe1, 0x62, 0x27, 0xfa, 0xf4, 0xa5, 0x1d, 0x26, 0xd3, 0x76, 0x18, 0xaf,
+	0xe8, 0xc6, 0x8b, 0x64, 0xd5, 0x45, 0x17, 0x14, 0x53, 0xc3, 0x38, 0xc1, 0xad, 0x84, 0x93, 0xe9,
+	0x92, 0xd8, 0xaa, 0x61, 0x42, 0x0d, 0x05, 0x9c, 0x8e, 0xfb, 0x06, 0x7d, 0xb7, 0xee, 0xfa, 0x06,
+	0x7d, 0x92, 0x8e, 0x84, 0x31, 0xf4, 0x2f, 0xd3, 0x95, 0x75, 0xce, 0x95, 0x8f, 0xa4, 0x7b, 0xcf,
+	0x01, 0xd0, 0x2a, 0xf9, 0xb4, 0x89, 0x93, 0x60, 0xc5, 0xb2, 0x71, 0x9a, 0x25, 0x45, 0x82, 0xa1,
+	0x66, 0x46, 0x5f, 0x65, 0xf8, 0x6f, 0x72, 0x80, 0x84, 0x7d, 0xdc, 0xb2, 0xbc, 0xc0, 0x2f, 0x41,
+	0x2e, 0x76, 0x29, 0xd3, 0x24, 0x5d, 0x32, 0x8e, 0xcf, 0x9f, 0x8f, 0x1b, 0x12, 0xbf, 0x6d, 0x1e,
+	0xef, 0x7f, 0xfd, 0x5d, 0xca, 0x88, 0xf8, 0x1b, 0x7e, 0x0c, 0xdd, 0x30, 0xc8, 0x43, 0x96, 0x6b,
+	0x2d, 0xbd, 0x6d, 0x0c, 0xc8, 0x1e, 0xe1, 0x33, 0xe8, 0xa7, 0x8c, 0x65, 0x4e, 0x90, 0x87, 0x5a,
+	0x5b, 0x97, 0x8c, 0x01, 0x39, 0x60, 0xfc, 0x04, 0x94, 0xdb, 0x38, 0x59, 0xde, 0x89, 0xa2, 0x2c,
+	0x8a, 0x35, 0x81, 0x8f, 0xa1, 0x15, 0xa5, 0x5a, 0x47, 0x97, 0x0c, 0x85, 0xb4, 0xa2, 0x14, 0x63,
+	0x90, 0xd3, 0x24, 0x2b, 0xb4, 0xae, 0x60, 0xc4, 0x9a, 0x73, 0x79, 0xf4, 0x99, 0x69, 0x3d, 0x5d,
+	0x32, 0x86, 0x44, 0xac, 0xf1, 0x05, 0x28, 0x61, 0xb0, 0x59, 0xe5, 0x61, 0x70, 0xc7, 0xb4, 0xbe,
+	0x2e, 0x19, 0xea, 0xf9, 0xa3, 0xe6, 0x6b, 0x9c, 0xaa, 0x48, 0xea, 0x7d, 0x58, 0x07, 0x55, 0x9c,
+	0xec, 0x6d, 0x3f, 0xdc, 0xb2, 0x4c, 0x53, 0x74, 0xc9, 0x90, 0x49, 0x93, 0xc2, 0x1a, 0xf4, 0x82,
+	0x6d, 0x11, 0xce, 0xd8, 0x4e, 0x03, 0x71, 0xd5, 0x0a, 0x56, 0x15, 0x1a, 0xad, 0x35, 0xb5, 0xae,
+	0xd0, 0x68, 0x3d, 0xfa, 0x2e, 0x81, 0xda, 0x68, 0x15, 0x1e, 0x82, 0xf2, 0xea, 0x72, 0x6e, 0xcd,
+	0x1c, 0x93, 0x3a, 0xe8, 0x08, 0x2b, 0xd0, 0x11, 0x10, 0x49, 0x78, 0x00, 0x7d, 0xcf, 0xbe, 0x29,
+	0x51, 0x0b, 0x9f, 0x80, 0x5a, 0xee, 0xb3, 0xdd, 0xa9, 0xe3, 0xa3, 0x36, 0x2f, 0x13, 0x7b, 0xea,
+	0x52, 0xdf, 0x26, 0x48, 0xc6, 0xff, 0xc3, 0x49, 0x85, 0x7c, 0xf7, 0xca, 0x9e, 0x2f, 0x7c, 0xd4,
+	0xc1, 0x2a, 0xf4, 0x16, 0xde, 0xcc, 0x9b, 0xdf, 0x78, 0xa8, 0xdb, 0x10, 0x30, 0x27, 0x36, 0x41,
+	0x3d, 0x7e, 0xb2, 0x63, 0x7a, 0x13, 0xea, 0x98, 0x33, 0x1b, 0xf5, 0x4b, 0x3d, 0xcb, 0x76, 0xdf,
+	0xf8, 0x14, 0x29, 0xbc, 0x48, 0x7d, 0xd3, 0xb7, 0xbd, 0xf9, 0xc4, 0x46, 0x80, 0x4f, 0x01, 0x59,
+	0xa6, 0x37, 0xf7, 0x5c, 0xcb, 0xbc, 0x2c, 0x05, 0x28, 0x52, 0x71, 0x1f, 0x64, 0x73, 0xe1, 0x3b,
+	0x68, 0xc0, 0xb7, 0x5b, 0xf3, 0xab, 0x2b, 0xd7, 0xa7, 0xee, 0x14, 0x0d, 0x47, 0x5f, 0x5a, 0x80,
+	0x9b, 0x0e, 0xc9, 0xd3, 0x64, 0x93, 0x33, 0xde, 0x95, 0x34, 0xd8, 0x71, 0x52, 0x93, 0x84, 0x23,
+	0x2a, 0x88, 0xa7, 0x7b, 0xa7, 0xb5, 0x84, 0xd3, 0x2e, 0xfe, 0xe6, 0xb4, 0x52, 0x67, 0x4c, 0xd8,
+	0x3a, 0xca, 0x8b, 0x9a, 0x68, 0x78, 0xae, 0x1a, 0x9a, 0xc3, 0xa2, 0x75, 0x58, 0x68, 0xed, 0xc6,
+	0xd0, 0x4a, 0xea, 0x67, 0x2f, 0xc8, 0xff, 0xe6, 0x85, 0xd1, 0x0b, 0x38, 0xfd, 0xd3, 0xa1, 0xbc,
+	0xc3, 0x74, 0x61, 0x59, 0x36, 0xa5, 0xe8, 0x88, 0xb7, 0xe3, 0xb5, 0xe9, 0x5e, 0x22, 0x09, 0x03,
+	0x74, 0x5d, 0x8f, 0xbe, 0xf3, 0x2c, 0xd4, 0x1a, 0x7d, 0x93, 0x40, 0x39, 0x68, 0x62, 0x03, 0x4e,
+	0x44, 0xfa, 0x96, 0x49, 0x7c, 0xcd, 0xb2, 0x3c, 0x4a, 0x36, 0x22, 0x5d, 0x43, 0xf2, 0x2b, 0xcd,
+	0x53, 0xf2, 0x9e, 0x05, 0xc5, 0x36, 0xdb, 0xe7, 0x47, 0x21, 0x07, 0xcc, 0x1b, 0x99, 0x87, 0x41,
+	0xb6, 0x72, 0x27, 0xe2, 0x85, 0x43, 0x52, 0x41, 0xee, 0xfe, 0x2c, 0x89, 0xcb, 0x87, 0x29, 0x44,
+	0xac, 0x79, 0xa6, 0x96, 0x61, 0x10, 0xc7, 0x6c, 0xb3, 0x66, 0x22, 0x3c, 0x03, 0x52, 0x13, 0x5c,
+	0xeb, 0x7e, 0x7f, 0x93, 0x32, 0x46, 0xbd, 0xfb, 0xfa, 0x06, 0x41, 0xb6, 0x0c, 0xa3, 0xfb, 0x20,
+	0x16, 0x69, 0xea, 0x93, 0x03, 0x3e, 0xbf, 0x06, 0xa8, 0x07, 0x83, 0x1d, 0xe8, 0xbc, 0xdd, 0xb2,
+	0x6c, 0x87, 0x9f, 0x3e, 0xf8, 0x8d, 0x38, 0x7b, 0xf6, 0xf0, 0x60, 0x47, 0x47, 0xb7, 0x5d, 0xd1,
+	0x86, 0x8b, 0x1f, 0x03, 0x00, 0xe3, 0x29, 0x28, 0x95, 0xaf, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
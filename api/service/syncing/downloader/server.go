@@ -4,11 +4,13 @@ import (
 	"context"
 	"log"
 	"net"
+	"time"
 
 	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
 	"github.com/harmony-one/harmony/internal/utils"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/peer"
 )
 
@@ -21,6 +23,7 @@ const (
 type Server struct {
 	downloadInterface DownloadInterface
 	GrpcServer        *grpc.Server
+	auth              *Authenticator // nil answers any peer
 }
 
 // Query returns the feature at the given point.
@@ -33,13 +36,37 @@ func (s *Server) Query(ctx context.Context, request *pb.DownloaderRequest) (*pb.
 	} else {
 		pinfo = p.Addr.String()
 	}
+	if s.auth != nil {
+		if err := s.auth.Admit(pinfo, request.Type); err != nil {
+			return nil, err
+		}
+		if request.Type == pb.DownloaderRequest_AUTH {
+			response := &pb.DownloaderResponse{Type: pb.DownloaderResponse_SUCCESS}
+			if err := s.auth.Authenticate(pinfo, request.AuthKey, request.AuthSig); err != nil {
+				utils.Logger().Info().Err(err).Str("peer", pinfo).Msg("[SYNC] sync peer authentication failed")
+				response.Type = pb.DownloaderResponse_FAIL
+			}
+			return response, nil
+		}
+	}
 	response, err := s.downloadInterface.CalculateResponse(request, pinfo)
 	if err != nil {
 		return nil, err
 	}
+	if s.auth != nil && request.Type == pb.DownloaderRequest_HANDSHAKE && response.Handshake != nil {
+		if response.Handshake.Challenge, err = s.auth.Challenge(pinfo); err != nil {
+			return nil, err
+		}
+	}
 	return response, nil
 }
 
+// SetAuthenticator sets the authenticator of the sync peers, nil to answer
+// any peer. It must be set before the server starts.
+func (s *Server) SetAuthenticator(auth *Authenticator) {
+	s.auth = auth
+}
+
 // Start starts the Server on given ip and port.
 func (s *Server) Start(ip, port string) (*grpc.Server, error) {
 	addr := net.JoinHostPort("", port)
@@ -47,8 +74,7 @@ func (s *Server) Start(ip, port string) (*grpc.Server, error) {
 	if err != nil {
 		log.Fatalf("[SYNC] failed to listen: %v", err)
 	}
-	var opts []grpc.ServerOption
-	grpcServer := grpc.NewServer(opts...)
+	grpcServer := grpc.NewServer(grpc.KeepaliveEnforcementPolicy(keepalivePolicy(KeepaliveInterval)))
 	pb.RegisterDownloaderServer(grpcServer, s)
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
@@ -61,6 +87,16 @@ func (s *Server) Start(ip, port string) (*grpc.Server, error) {
 	return grpcServer, nil
 }
 
+// keepalivePolicy returns the policy the servers enforce on the keepalive
+// pings of the clients, accepting the pings sent every interval by the
+// clients, idle between sync rounds or not
+func keepalivePolicy(interval time.Duration) keepalive.EnforcementPolicy {
+	return keepalive.EnforcementPolicy{
+		MinTime:             interval / 2,
+		PermitWithoutStream: true,
+	}
+}
+
 // NewServer creates new Server which implements DownloadInterface.
 func NewServer(dlInterface DownloadInterface) *Server {
 	s := &Server{downloadInterface: dlInterface}
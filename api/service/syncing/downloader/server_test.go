@@ -0,0 +1,88 @@
+package downloader
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+)
+
+// pingServer pings the server at addr every interval n times from an idle
+// connection, and returns whether the server closed the connection
+func pingServer(t *testing.T, addr string, interval time.Duration, n int) bool {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(http2.ClientPreface)); err != nil {
+		t.Fatal(err)
+	}
+	framer, lock := http2.NewFramer(conn, conn), sync.Mutex{}
+	if err := framer.WriteSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			frame, err := framer.ReadFrame()
+			if err != nil {
+				return
+			}
+			switch frame := frame.(type) {
+			case *http2.GoAwayFrame:
+				return
+			case *http2.SettingsFrame:
+				if !frame.IsAck() {
+					lock.Lock()
+					framer.WriteSettingsAck()
+					lock.Unlock()
+				}
+			}
+		}
+	}()
+	for i := 0; i < n; i++ {
+		time.Sleep(interval)
+		lock.Lock()
+		err := framer.WritePing(false, [8]byte{byte(i)})
+		lock.Unlock()
+		if err != nil {
+			return true
+		}
+	}
+	select {
+	case <-closed:
+		return true
+	case <-time.After(interval):
+		return false
+	}
+}
+
+func TestKeepalivePolicy(t *testing.T) {
+	const interval = 200 * time.Millisecond
+	for _, test := range []struct {
+		name   string
+		every  time.Duration // interval the client pings at
+		closed bool
+	}{
+		{"idle client at the keepalive interval", interval, false},
+		{"idle client pinging too often", interval / 10, true},
+	} {
+		lis, err := net.Listen("tcp4", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := grpc.NewServer(grpc.KeepaliveEnforcementPolicy(keepalivePolicy(interval)))
+		go server.Serve(lis)
+
+		if closed := pingServer(t, lis.Addr().String(), test.every, 5); closed != test.closed {
+			t.Errorf("%s: expected the connection closed %t, got %t", test.name, test.closed, closed)
+		}
+		server.Stop()
+	}
+}
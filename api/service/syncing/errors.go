@@ -13,4 +13,14 @@ var (
 	ErrDownloadBlocks        = errors.New("[SYNC]: get download blocks failed")
 	ErrUpdateBlockAndStatus  = errors.New("[SYNC]: update block and status failed")
 	ErrGenerateNewState      = errors.New("[SYNC]: get generate new state failed")
+	ErrGetStateNodes         = errors.New("[SYNC]: get state nodes failed")
+	ErrNoCapablePeer         = errors.New("[SYNC]: no peer supports the requested feature")
+	ErrGetCanonicalHeaders   = errors.New("[SYNC]: get canonical headers failed")
+	ErrInvalidHeaderChain    = errors.New("[SYNC]: headers do not form a chain")
+	ErrForkTooDeep           = errors.New("[SYNC]: fork is deeper than the checked blocks")
+	ErrChainNotFresh         = errors.New("[SYNC]: chain is past genesis")
+	ErrCheckpointMismatch    = errors.New("[SYNC]: block does not match the checkpoint")
+	ErrSyncPeerUnreachable   = errors.New("[SYNC]: cannot connect to sync peer")
+	ErrSyncPeerNoResponse    = errors.New("[SYNC]: sync peer did not respond")
+	ErrGetCommitSig          = errors.New("[SYNC]: get commit signature failed")
 )
@@ -0,0 +1,204 @@
+package syncing
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+// Constants for fork detection
+const (
+	MaxCanonicalHeadersPerRequest = 64 // maximum number of canonical headers to fetch in one query
+	ForkCheckDepth                = 32 // number of recent blocks compared with the quorum-signed headers of the peers
+	ForkCheckInterval             = 30 * time.Second
+)
+
+var (
+	forkDetectedCounter = metrics.NewRegisteredCounter("sync/fork/detected", nil)
+	forkRollbackCounter = metrics.NewRegisteredCounter("sync/fork/rolledback", nil)
+)
+
+// Fork is a minority fork of the local chain
+type Fork struct {
+	Number     uint64      // number of the first local block not on the quorum-signed chain
+	LocalHash  common.Hash // hash of the local canonical block at Number
+	QuorumHash common.Hash // hash of the quorum-signed block at Number
+}
+
+// GetCanonicalHeaders gets the headers of the canonical blocks of the peer starting at the given number
+func (peerConfig *SyncPeerConfig) GetCanonicalHeaders(number uint64, size uint32) ([]*block.Header, error) {
+	response := peerConfig.client.GetCanonicalHeaders(number, size)
+	if response == nil {
+		return nil, ErrGetCanonicalHeaders
+	}
+	headers := make([]*block.Header, 0, len(response.Payload))
+	for _, payload := range response.Payload {
+		header := new(block.Header)
+		if err := rlp.DecodeBytes(payload, header); err != nil {
+			return nil, errors.Wrap(err, ErrGetCanonicalHeaders.Error())
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
+
+// DetectFork compares the local canonical blocks of the last ForkCheckDepth
+// heights with the quorum-signed headers of the peers. It returns the fork
+// the local chain is on, or nil if the local chain agrees with the peers.
+func (ss *StateSync) DetectFork(bc *core.BlockChain) *Fork {
+	current := bc.CurrentBlock().NumberU64()
+	if current < 1 {
+		return nil
+	}
+	from := uint64(1)
+	if current > ForkCheckDepth {
+		from = current - ForkCheckDepth
+	}
+	// the header above the local head carries the commit signature of the head
+	size := uint32(current - from + 2)
+	for _, peerConfig := range ss.capablePeers(downloader.FeatureCanonicalHeaders) {
+		var fork *Fork
+		headers, err := peerConfig.GetCanonicalHeaders(from, size)
+		if err == nil {
+			fork, err = findFork(bc.GetHeaderByNumber, quorumVerifier(bc), from, headers)
+		}
+		switch {
+		case err != nil:
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+				Str("peerIP", peerConfig.ip).
+				Str("peerPort", peerConfig.port).
+				Msg("[SYNC] DetectFork: cannot compare with peer")
+		case fork != nil:
+			forkDetectedCounter.Inc(1)
+			utils.ModuleLogger(utils.ModuleSync).Warn().
+				Str("peerIP", peerConfig.ip).
+				Str("peerPort", peerConfig.port).
+				Uint64("forkNumber", fork.Number).
+				Str("localHash", fork.LocalHash.Hex()).
+				Str("quorumHash", fork.QuorumHash.Hex()).
+				Msg("[SYNC] DetectFork: local chain is on a minority fork")
+			return fork
+		}
+	}
+	return nil
+}
+
+// quorumVerifier verifies the header is signed by the quorum of its committee,
+// the commit signature carried by its child
+func quorumVerifier(bc *core.BlockChain) func(header, child *block.Header) error {
+	return func(header, child *block.Header) error {
+		sig := child.LastCommitSignature()
+		return bc.Engine().VerifyHeaderWithSignature(
+			bc, header, sig[:], child.LastCommitBitmap(), false,
+		)
+	}
+}
+
+// findFork finds the first local canonical block, looked up by number, which
+// differs from the quorum-signed header of the peer at the same height
+func findFork(
+	localHeader func(number uint64) *block.Header,
+	verifyQuorum func(header, child *block.Header) error,
+	from uint64, headers []*block.Header,
+) (*Fork, error) {
+	if err := checkHeaderChain(from, headers); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(headers); i++ {
+		header, child := headers[i], headers[i+1]
+		number := from + uint64(i)
+		local := localHeader(number)
+		if local == nil {
+			return nil, nil
+		}
+		if local.Hash() == header.Hash() {
+			continue
+		}
+		// the fork has to branch off a block shared with the peer
+		if i == 0 {
+			return nil, ErrForkTooDeep
+		}
+		if err := verifyQuorum(header, child); err != nil {
+			return nil, errors.Wrapf(err, "[SYNC] header %d of peer is not quorum-signed", number)
+		}
+		return &Fork{Number: number, LocalHash: local.Hash(), QuorumHash: header.Hash()}, nil
+	}
+	return nil, nil
+}
+
+// checkHeaderChain checks that the headers are consecutive from the given
+// number and linked by their parent hashes
+func checkHeaderChain(from uint64, headers []*block.Header) error {
+	for i, header := range headers {
+		if header.Number().Uint64() != from+uint64(i) {
+			return ErrInvalidHeaderChain
+		}
+		if i > 0 && header.ParentHash() != headers[i-1].Hash() {
+			return ErrInvalidHeaderChain
+		}
+	}
+	return nil
+}
+
+// GetCanonicalHeaders gets up to size canonical headers from the given number
+// onwards from the first peer returning a chain of them
+func (ss *StateSync) GetCanonicalHeaders(number uint64, size uint32) ([]*block.Header, error) {
+	for _, peerConfig := range ss.capablePeers(downloader.FeatureCanonicalHeaders) {
+		headers, err := peerConfig.GetCanonicalHeaders(number, size)
+		if err == nil && len(headers) == 0 {
+			continue
+		}
+		if err == nil {
+			err = checkHeaderChain(number, headers)
+		}
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+				Str("peerIP", peerConfig.ip).
+				Str("peerPort", peerConfig.port).
+				Msg("[SYNC] GetCanonicalHeaders: query failed")
+			continue
+		}
+		return headers, nil
+	}
+	return nil, ErrGetCanonicalHeaders
+}
+
+// RollbackFork rolls back the local blocks of the fork, so the quorum-signed
+// blocks can be synced from the peers
+func (ss *StateSync) RollbackFork(bc *core.BlockChain, fork *Fork) error {
+	current := bc.CurrentBlock().NumberU64()
+	if fork.Number > current {
+		return nil
+	}
+	if current-fork.Number >= MaxCanonicalHeadersPerRequest {
+		return ErrForkTooDeep
+	}
+	if local := bc.GetHeaderByNumber(fork.Number); local == nil || local.Hash() != fork.LocalHash {
+		// the local chain has moved since the fork was detected
+		return nil
+	}
+	hashes := make([]common.Hash, 0, current-fork.Number+1)
+	for number := fork.Number; number <= current; number++ {
+		header := bc.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		hashes = append(hashes, header.Hash())
+	}
+	bc.Rollback(hashes)
+	ss.purgeAllBlocksFromCache()
+	forkRollbackCounter.Inc(1)
+	utils.ModuleLogger(utils.ModuleSync).Warn().
+		Uint64("forkNumber", fork.Number).
+		Int("rolledBack", len(hashes)).
+		Uint64("newHead", bc.CurrentBlock().NumberU64()).
+		Msg("[SYNC] RollbackFork: rolled back minority fork")
+	return nil
+}
@@ -0,0 +1,107 @@
+package syncing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/pkg/errors"
+)
+
+// testHeaderChain returns the headers of a chain from the given number, the
+// given extra data telling apart the chains of the same numbers
+func testHeaderChain(from uint64, count int, parent common.Hash, extra string) []*block.Header {
+	headers := []*block.Header{}
+	for i := 0; i < count; i++ {
+		header := blockfactory.NewTestHeader().With().
+			Number(new(big.Int).SetUint64(from + uint64(i))).
+			ParentHash(parent).
+			Extra([]byte(extra)).
+			Header()
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+	return headers
+}
+
+func testLocalChain(headers ...[]*block.Header) func(uint64) *block.Header {
+	byNumber := map[uint64]*block.Header{}
+	for _, chain := range headers {
+		for _, header := range chain {
+			byNumber[header.Number().Uint64()] = header
+		}
+	}
+	return func(number uint64) *block.Header { return byNumber[number] }
+}
+
+func quorumSigned(header, child *block.Header) error { return nil }
+
+func TestCheckHeaderChain(t *testing.T) {
+	headers := testHeaderChain(5, 4, common.Hash{}, "")
+	if err := checkHeaderChain(5, headers); err != nil {
+		t.Errorf("expected a chain, got %v", err)
+	}
+	if err := checkHeaderChain(4, headers); err != ErrInvalidHeaderChain {
+		t.Errorf("expected the headers from another number rejected, got %v", err)
+	}
+	gap := append(append([]*block.Header{}, headers[:2]...), headers[3:]...)
+	if err := checkHeaderChain(5, gap); err != ErrInvalidHeaderChain {
+		t.Errorf("expected the missing header rejected, got %v", err)
+	}
+	unlinked := append(append([]*block.Header{}, headers[:2]...), testHeaderChain(7, 1, common.Hash{}, "")...)
+	if err := checkHeaderChain(5, unlinked); err != ErrInvalidHeaderChain {
+		t.Errorf("expected the unlinked header rejected, got %v", err)
+	}
+	if err := checkHeaderChain(5, nil); err != nil {
+		t.Errorf("expected no header accepted, got %v", err)
+	}
+}
+
+func TestFindFork(t *testing.T) {
+	shared := testHeaderChain(1, 3, common.Hash{}, "")
+	local := testHeaderChain(4, 3, shared[2].Hash(), "local")
+	quorum := append(
+		append([]*block.Header{}, shared...),
+		testHeaderChain(4, 4, shared[2].Hash(), "quorum")...,
+	)
+
+	// the local chain agrees with the peer
+	fork, err := findFork(testLocalChain(shared), quorumSigned, 1, quorum[:4])
+	if err != nil || fork != nil {
+		t.Errorf("expected no fork, got %+v %v", fork, err)
+	}
+
+	// the local chain branches off at 4
+	fork, err = findFork(testLocalChain(shared, local), quorumSigned, 1, quorum)
+	if err != nil || fork == nil {
+		t.Fatalf("expected a fork, got %v", err)
+	}
+	if fork.Number != 4 || fork.LocalHash != local[0].Hash() || fork.QuorumHash != quorum[3].Hash() {
+		t.Errorf("unexpected fork %+v", fork)
+	}
+
+	// the blocks of the peer above the local head are not a fork
+	fork, err = findFork(testLocalChain(shared), quorumSigned, 1, quorum)
+	if err != nil || fork != nil {
+		t.Errorf("expected no fork above the local head, got %+v %v", fork, err)
+	}
+
+	// the fork has to branch off a block shared with the peer
+	if _, err := findFork(testLocalChain(shared, local), quorumSigned, 4, quorum[3:]); err != ErrForkTooDeep {
+		t.Errorf("expected the fork too deep, got %v", err)
+	}
+
+	// the headers of the peer have to be quorum-signed
+	errNotSigned := errors.New("not signed")
+	notSigned := func(header, child *block.Header) error { return errNotSigned }
+	if _, err := findFork(testLocalChain(shared, local), notSigned, 1, quorum); errors.Cause(err) != errNotSigned {
+		t.Errorf("expected the unsigned header rejected, got %v", err)
+	}
+
+	// the headers of the peer have to form a chain
+	if _, err := findFork(testLocalChain(shared, local), quorumSigned, 2, quorum); err != ErrInvalidHeaderChain {
+		t.Errorf("expected the headers of other numbers rejected, got %v", err)
+	}
+}
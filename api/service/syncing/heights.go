@@ -0,0 +1,192 @@
+package syncing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Constants for the shard height table
+const (
+	// ShardHeightRefreshInterval is the interval at which the shard heights are refreshed in background
+	ShardHeightRefreshInterval = 3 * time.Second
+	// ShardHeightTTL is the age after which a shard height is queried again from the peers on use
+	ShardHeightTTL = ShardHeightRefreshInterval
+)
+
+// ShardHeight is the view of the node on the head of a shard, as reported by its sync peers
+type ShardHeight struct {
+	ShardID   uint32
+	Height    uint64      // highest height reached by at least half of the peers
+	Hash      common.Hash // head hash reported by most peers at Height, zero if unknown
+	MaxHeight uint64      // highest height reported by any peer
+	Peers     int         // number of peers which reported their height
+	UpdatedAt time.Time
+}
+
+// HeightTable holds the latest known ShardHeight of each shard
+type HeightTable struct {
+	lock    sync.RWMutex
+	heights map[uint32]ShardHeight
+}
+
+// NewHeightTable creates an empty height table
+func NewHeightTable() *HeightTable {
+	return &HeightTable{heights: map[uint32]ShardHeight{}}
+}
+
+// Get returns the height of the given shard, if known
+func (t *HeightTable) Get(shardID uint32) (ShardHeight, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	height, ok := t.heights[shardID]
+	return height, ok
+}
+
+// All returns the known heights of all shards, ordered by shard id
+func (t *HeightTable) All() []ShardHeight {
+	t.lock.RLock()
+	heights := make([]ShardHeight, 0, len(t.heights))
+	for _, height := range t.heights {
+		heights = append(heights, height)
+	}
+	t.lock.RUnlock()
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i].ShardID < heights[j].ShardID
+	})
+	return heights
+}
+
+// Set records the height of a shard and exports it to the metrics
+func (t *HeightTable) Set(height ShardHeight) {
+	t.lock.Lock()
+	t.heights[height.ShardID] = height
+	t.lock.Unlock()
+	prefix := fmt.Sprintf("sync/shard/%d/", height.ShardID)
+	metrics.GetOrRegisterGauge(prefix+"height", nil).Update(int64(height.Height))
+	metrics.GetOrRegisterGauge(prefix+"maxheight", nil).Update(int64(height.MaxHeight))
+	metrics.GetOrRegisterGauge(prefix+"peers", nil).Update(int64(height.Peers))
+}
+
+// peerHeight is the head of a sync peer
+type peerHeight struct {
+	height uint64
+	hash   common.Hash
+	// offset of the clock of the peer to the local clock, if sampled
+	clockOffset time.Duration
+	hasClock    bool
+}
+
+// majorityHeight computes the shard height from the heads reported by the peers
+func majorityHeight(reports []peerHeight) ShardHeight {
+	result := ShardHeight{Peers: len(reports)}
+	if len(reports) == 0 {
+		return result
+	}
+	heights := make([]uint64, len(reports))
+	for i, report := range reports {
+		heights[i] = report.height
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	result.MaxHeight = heights[0]
+	result.Height = heights[(len(heights)-1)/2]
+
+	votes := map[common.Hash]int{}
+	for _, report := range reports {
+		if report.height == result.Height && report.hash != (common.Hash{}) {
+			votes[report.hash]++
+		}
+	}
+	for hash, count := range votes {
+		if count > votes[result.Hash] {
+			result.Hash = hash
+		}
+	}
+	return result
+}
+
+// SetHeightTable sets the table in which the shard heights seen by this state sync are kept
+func (ss *StateSync) SetHeightTable(heights *HeightTable) {
+	ss.heights = heights
+}
+
+// SetClockSkew sets the tracker of the skew of the local clock to the clocks
+// of the sync peers, sampled on each height refresh
+func (ss *StateSync) SetClockSkew(clock *ClockSkew) {
+	ss.clock = clock
+}
+
+// ShardHeight returns the height of the given shard from the height table,
+// querying the peers if the known height is older than ShardHeightTTL
+func (ss *StateSync) ShardHeight(shardID uint32) ShardHeight {
+	if height, ok := ss.heights.Get(shardID); ok && time.Since(height.UpdatedAt) < ShardHeightTTL {
+		return height
+	}
+	return ss.RefreshShardHeight(shardID)
+}
+
+// RefreshShardHeight queries the heads of the peers and records the resulting
+// height of the given shard in the height table, one refresh at a time
+func (ss *StateSync) RefreshShardHeight(shardID uint32) ShardHeight {
+	ss.heightsMux.Lock()
+	defer ss.heightsMux.Unlock()
+	reports := ss.getPeerHeights()
+	if ss.clock != nil {
+		offsets := []time.Duration{}
+		for _, report := range reports {
+			if report.hasClock {
+				offsets = append(offsets, report.clockOffset)
+			}
+		}
+		ss.clock.Update(offsets)
+	}
+	height := majorityHeight(reports)
+	height.ShardID = shardID
+	height.UpdatedAt = time.Now()
+	ss.heights.Set(height)
+	return height
+}
+
+// getPeerHeights gets the heads of the peers
+func (ss *StateSync) getPeerHeights() []peerHeight {
+	reports := []peerHeight{}
+	if ss.syncConfig == nil {
+		return reports
+	}
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+	)
+	ss.syncConfig.ForEachPeer(func(peerConfig *SyncPeerConfig) (brk bool) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sent := time.Now()
+			response, err := peerConfig.client.GetBlockChainHeight()
+			received := time.Now()
+			if err != nil || response == nil {
+				utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).Str("peerIP", peerConfig.ip).Str("peerPort", peerConfig.port).Msg("[Sync]GetBlockChainHeight failed")
+				return
+			}
+			report := peerHeight{height: response.BlockHeight}
+			// legacy peers only report their height
+			if len(response.Payload) > 0 {
+				report.hash = common.BytesToHash(response.Payload[0])
+			}
+			if len(response.Payload) > 1 {
+				report.clockOffset, report.hasClock = clockOffset(response.Payload[1], sent, received)
+			}
+			lock.Lock()
+			reports = append(reports, report)
+			lock.Unlock()
+		}()
+		return
+	})
+	wg.Wait()
+	return reports
+}
@@ -0,0 +1,39 @@
+package syncing
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMajorityHeight(t *testing.T) {
+	hashA, hashB := common.HexToHash("0xa"), common.HexToHash("0xb")
+	height := majorityHeight([]peerHeight{
+		{height: 10, hash: hashA},
+		{height: 10, hash: hashA},
+		{height: 10, hash: hashB},
+		{height: 9},
+		{height: 1000},
+	})
+	assert.Equal(t, uint64(10), height.Height)
+	assert.Equal(t, hashA, height.Hash)
+	assert.Equal(t, uint64(1000), height.MaxHeight)
+	assert.Equal(t, 5, height.Peers)
+
+	assert.Equal(t, ShardHeight{}, majorityHeight(nil))
+}
+
+func TestHeightTable(t *testing.T) {
+	table := NewHeightTable()
+	table.Set(ShardHeight{ShardID: 1, Height: 5})
+	table.Set(ShardHeight{ShardID: 0, Height: 7})
+	height, ok := table.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), height.Height)
+	_, ok = table.Get(2)
+	assert.False(t, ok)
+	all := table.All()
+	assert.Len(t, all, 2)
+	assert.Equal(t, uint32(0), all[0].ShardID)
+}
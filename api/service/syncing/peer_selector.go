@@ -0,0 +1,139 @@
+package syncing
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// Constants for the sync peer selection
+const (
+	// SyncPeersCount is the number of peers synced from
+	SyncPeersCount = 7
+	// peerScoreDecay is the weight of the previous measures in the moving
+	// averages of a peer
+	peerScoreDecay = 0.7
+	// maxScoredPeers bounds the number of peers whose measures are kept
+	maxScoredPeers = 1024
+)
+
+// peerScore is the performance measured from the requests to a sync peer
+type peerScore struct {
+	latency    time.Duration // moving average of the response latency
+	throughput float64       // moving average of the bytes received per second
+	failures   int           // consecutive failed requests
+	updatedAt  time.Time
+}
+
+// value ranks the peer, halving its throughput per consecutive failure
+func (s *peerScore) value() float64 {
+	v := s.throughput
+	for i := 0; i < s.failures && v > 0; i++ {
+		v /= 2
+	}
+	return v
+}
+
+// PeerSelector picks the sync peers by their measured throughput
+type PeerSelector struct {
+	mtx    sync.Mutex
+	scores map[string]*peerScore // keyed by the host:port of the peer
+}
+
+// NewPeerSelector creates a selector without any measure
+func NewPeerSelector() *PeerSelector {
+	return &PeerSelector{scores: map[string]*peerScore{}}
+}
+
+func peerAddr(ip, port string) string {
+	return net.JoinHostPort(ip, port)
+}
+
+// Record notes a request to the peer, which took d to return size bytes or
+// failed with err
+func (s *PeerSelector) Record(ip, port string, d time.Duration, size int, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	addr := peerAddr(ip, port)
+	score, ok := s.scores[addr]
+	if !ok {
+		if len(s.scores) >= maxScoredPeers {
+			s.evictOldest()
+		}
+		score = &peerScore{}
+		s.scores[addr] = score
+	}
+	score.updatedAt = time.Now()
+	if err != nil {
+		score.failures++
+		return
+	}
+	score.failures = 0
+	throughput := float64(size)
+	if d > 0 {
+		throughput /= d.Seconds()
+	}
+	if score.latency == 0 {
+		score.latency, score.throughput = d, throughput
+		return
+	}
+	score.latency = time.Duration(peerScoreDecay*float64(score.latency) + (1-peerScoreDecay)*float64(d))
+	score.throughput = peerScoreDecay*score.throughput + (1-peerScoreDecay)*throughput
+}
+
+// evictOldest drops the measures of the peer least recently requested.
+// Caller shall hold mtx.
+func (s *PeerSelector) evictOldest() {
+	var oldest string
+	for addr, score := range s.scores {
+		if oldest == "" || score.updatedAt.Before(s.scores[oldest].updatedAt) {
+			oldest = addr
+		}
+	}
+	delete(s.scores, oldest)
+}
+
+// Select returns up to k of the peers, the best measured first. One slot is
+// kept for a peer not measured yet if any, so new peers get the chance to
+// prove themselves; more are taken when too few peers were measured. All the
+// peers are returned if there are no more than k.
+func (s *PeerSelector) Select(peers []p2p.Peer, k int) []p2p.Peer {
+	if k <= 0 {
+		return nil
+	}
+	if len(peers) <= k {
+		return append([]p2p.Peer{}, peers...)
+	}
+	s.mtx.Lock()
+	measured, unmeasured := []p2p.Peer{}, []p2p.Peer{}
+	values := map[string]float64{}
+	for _, peer := range peers {
+		addr := peerAddr(peer.IP, peer.Port)
+		if score, ok := s.scores[addr]; ok {
+			measured = append(measured, peer)
+			values[addr] = score.value()
+		} else {
+			unmeasured = append(unmeasured, peer)
+		}
+	}
+	s.mtx.Unlock()
+	sort.SliceStable(measured, func(i, j int) bool {
+		return values[peerAddr(measured[i].IP, measured[i].Port)] >
+			values[peerAddr(measured[j].IP, measured[j].Port)]
+	})
+
+	explore := 0
+	if len(unmeasured) > 0 {
+		explore = 1
+		if k-len(measured) > explore {
+			explore = k - len(measured)
+		}
+	}
+	selected := make([]p2p.Peer, 0, k)
+	selected = append(selected, measured[:k-explore]...)
+	selected = append(selected, unmeasured[:explore]...)
+	return selected
+}
@@ -0,0 +1,46 @@
+package syncing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+func TestPeerSelector(t *testing.T) {
+	peers := []p2p.Peer{}
+	for _, port := range []string{"1", "2", "3", "4", "5"} {
+		peers = append(peers, p2p.Peer{IP: "127.0.0.1", Port: port})
+	}
+	s := NewPeerSelector()
+
+	// fewer peers than wanted
+	if selected := s.Select(peers[:2], 3); len(selected) != 2 {
+		t.Fatalf("selected %d of 2 peers", len(selected))
+	}
+
+	// port 3 is the fastest, 1 the slowest, 2 fails, 4 and 5 are not measured
+	s.Record("127.0.0.1", "1", time.Second, 1000, nil)
+	s.Record("127.0.0.1", "2", time.Second, 5000, nil)
+	s.Record("127.0.0.1", "2", 0, 0, errors.New("timeout"))
+	s.Record("127.0.0.1", "2", 0, 0, errors.New("timeout"))
+	s.Record("127.0.0.1", "2", 0, 0, errors.New("timeout"))
+	s.Record("127.0.0.1", "3", time.Second, 4000, nil)
+
+	selected := s.Select(peers, 3)
+	ports := []string{}
+	for _, peer := range selected {
+		ports = append(ports, peer.Port)
+	}
+	// best two measured, then one slot for an unmeasured peer
+	if len(ports) != 3 || ports[0] != "3" || ports[1] != "1" || ports[2] != "4" {
+		t.Errorf("selected ports %v, want [3 1 4]", ports)
+	}
+
+	// too few measured peers leaves more slots to the others
+	selected = s.Select(append([]p2p.Peer{peers[2]}, peers[3:]...), 2)
+	if len(selected) != 2 || selected[0].Port != "3" || selected[1].Port != "4" {
+		t.Errorf("unexpected selection %v", selected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 	"strconv"
@@ -13,6 +14,7 @@ import (
 	"github.com/Workiva/go-datastructures/queue"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/bls/ffi/go/bls"
 	"github.com/harmony-one/harmony/api/service/syncing/downloader"
 	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
 	"github.com/harmony-one/harmony/consensus"
@@ -22,6 +24,7 @@ import (
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node/worker"
 	"github.com/harmony-one/harmony/p2p"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 )
 
@@ -36,6 +39,10 @@ const (
 	verifyHeaderBatchSize    uint64 = 100  // block chain header verification batch size
 	SyncLoopFrequency               = 1    // unit in second
 	LastMileBlocksSize              = 50
+	MaxReceiptsPerRequest           = 128 // maximum number of blocks to fetch receipts for in one query
+	MaxStateNodesPerRequest         = 384 // maximum number of trie nodes to fetch in one query
+	BulkImportThreshold             = 256 // minimum number of blocks to download for importing them in bulk
+	BulkImportInterval              = 128 // number of blocks imported in bulk between flushes to disk
 )
 
 // SyncPeerConfig is peer config to sync.
@@ -46,6 +53,7 @@ type SyncPeerConfig struct {
 	client      *downloader.Client
 	blockHashes [][]byte       // block hashes before node doing sync
 	newBlocks   []*types.Block // blocks after node doing sync
+	handshake   *pb.Handshake  // capabilities of the peer, nil for legacy peers
 	mux         sync.Mutex
 }
 
@@ -54,6 +62,19 @@ func (peerConfig *SyncPeerConfig) GetClient() *downloader.Client {
 	return peerConfig.client
 }
 
+// ProtocolVersion returns the sync protocol version negotiated with the peer
+func (peerConfig *SyncPeerConfig) ProtocolVersion() uint32 {
+	if peerConfig.handshake == nil {
+		return downloader.LegacyProtocolVersion
+	}
+	return peerConfig.handshake.GetProtocolVersion()
+}
+
+// SupportsFeature returns whether the peer advertised the given sync feature
+func (peerConfig *SyncPeerConfig) SupportsFeature(feature string) bool {
+	return downloader.HasFeature(peerConfig.handshake, feature)
+}
+
 // SyncBlockTask is the task struct to sync a specific block.
 type SyncBlockTask struct {
 	index     int
@@ -96,6 +117,8 @@ func CreateStateSync(ip string, port string, peerHash [20]byte) *StateSync {
 	stateSync.selfPeerHash = peerHash
 	stateSync.commonBlocks = make(map[int]*types.Block)
 	stateSync.lastMileBlocks = []*types.Block{}
+	stateSync.heights = NewHeightTable()
+	stateSync.selector = NewPeerSelector()
 	return stateSync
 }
 
@@ -110,6 +133,42 @@ type StateSync struct {
 	stateSyncTaskQueue *queue.Queue
 	syncMux            sync.Mutex
 	lastMileMux        sync.Mutex
+	handshake          *pb.Handshake    // capabilities advertised to the sync peers
+	heights            *HeightTable     // shard heights reported by the sync peers
+	heightsMux         sync.Mutex       // serializes the refreshes of the shard heights
+	clock              *ClockSkew       // skew of the local clock to the sync peers, nil if not tracked
+	authKeys           []*bls.SecretKey // committee keys to authenticate to the sync peers with
+	selector           *PeerSelector    // picks the sync peers by measured throughput
+}
+
+// SetHandshake sets the capabilities advertised to the sync peers
+func (ss *StateSync) SetHandshake(handshake *pb.Handshake) {
+	ss.handshake = handshake
+}
+
+// SetAuthKeys sets the committee keys to authenticate to the sync peers
+// requiring it with
+func (ss *StateSync) SetAuthKeys(keys []*bls.SecretKey) {
+	ss.authKeys = keys
+}
+
+// authenticate authenticates to the server of the given peer with the first
+// key it accepts, if the server sent a challenge. The peers of unknown ID,
+// such as those found through DNS, are synced with anonymously, as the
+// signatures are bound to the server.
+func (ss *StateSync) authenticate(
+	client *downloader.Client, server libp2p_peer.ID, handshake *pb.Handshake,
+) error {
+	if len(handshake.GetChallenge()) == 0 || len(ss.authKeys) == 0 || server == "" {
+		return nil
+	}
+	var err error
+	for _, key := range ss.authKeys {
+		if err = client.Auth(key, server, handshake.GetChallenge()); err == nil {
+			return nil
+		}
+	}
+	return err
 }
 
 func (ss *StateSync) purgeAllBlocksFromCache() {
@@ -160,6 +219,24 @@ func (sc *SyncConfig) CloseConnections() {
 	}
 }
 
+// takeReusableClients empties the config, closing the connections to the
+// peers no longer healthy, and returns the others by address for reuse with
+// their handshake
+func (sc *SyncConfig) takeReusableClients() map[string]*SyncPeerConfig {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	reusable := map[string]*SyncPeerConfig{}
+	for _, pc := range sc.peers {
+		if !pc.client.Healthy() {
+			pc.client.Close()
+			continue
+		}
+		reusable[net.JoinHostPort(pc.ip, pc.port)] = pc
+	}
+	sc.peers = nil
+	return reusable
+}
+
 // FindPeerByHash returns the peer with the given hash, or nil if not found.
 func (sc *SyncConfig) FindPeerByHash(peerHash []byte) *SyncPeerConfig {
 	sc.mtx.RLock()
@@ -184,7 +261,7 @@ func (ss *StateSync) AddNewBlock(peerHash []byte, block *types.Block) {
 	pc.mux.Lock()
 	defer pc.mux.Unlock()
 	pc.newBlocks = append(pc.newBlocks, block)
-	utils.Logger().Debug().
+	utils.ModuleLogger(utils.ModuleSync).Debug().
 		Int("total", len(pc.newBlocks)).
 		Uint64("blockHeight", block.NumberU64()).
 		Msg("[SYNC] new block received")
@@ -223,9 +300,11 @@ func (peerConfig *SyncPeerConfig) GetBlocks(hashes [][]byte) ([][]byte, error) {
 	return response.Payload, nil
 }
 
-// CreateSyncConfig creates SyncConfig for StateSync object.
+// CreateSyncConfig creates SyncConfig for StateSync object, with the best
+// SyncPeersCount of the given peers. The connections of the previous round
+// to the selected peers are reused if still healthy.
 func (ss *StateSync) CreateSyncConfig(peers []p2p.Peer, isBeacon bool) error {
-	utils.Logger().Debug().
+	utils.ModuleLogger(utils.ModuleSync).Debug().
 		Int("len", len(peers)).
 		Bool("isBeacon", isBeacon).
 		Msg("[SYNC] CreateSyncConfig: len of peers")
@@ -233,17 +312,45 @@ func (ss *StateSync) CreateSyncConfig(peers []p2p.Peer, isBeacon bool) error {
 	if len(peers) == 0 {
 		return errors.New("[SYNC] no peers to connect to")
 	}
+	peers = ss.selector.Select(peers, SyncPeersCount)
+	// Keep the connections to the healthy peers of the last round
+	reusable := map[string]*SyncPeerConfig{}
 	if ss.syncConfig != nil {
-		ss.syncConfig.CloseConnections()
+		reusable = ss.syncConfig.takeReusableClients()
 	}
 	ss.syncConfig = &SyncConfig{}
 	var wg sync.WaitGroup
 	for _, peer := range peers {
+		addr := net.JoinHostPort(peer.IP, peer.Port)
+		if old, ok := reusable[addr]; ok {
+			delete(reusable, addr)
+			wg.Add(1)
+			go func(old *SyncPeerConfig) {
+				defer wg.Done()
+				if err := old.client.Probe(); err != nil {
+					ss.selector.Record(old.ip, old.port, 0, 0, err)
+					utils.ModuleLogger(utils.ModuleSync).Debug().Err(err).
+						Str("peerIP", old.ip).
+						Str("peerPort", old.port).
+						Msg("[SYNC] peer failed liveness probe, dropping connection")
+					old.client.Close()
+					return
+				}
+				ss.syncConfig.AddPeer(&SyncPeerConfig{
+					ip:        old.ip,
+					port:      old.port,
+					client:    old.client,
+					handshake: old.handshake,
+				})
+			}(old)
+			continue
+		}
 		wg.Add(1)
 		go func(peer p2p.Peer) {
 			defer wg.Done()
 			client := downloader.ClientSetup(peer.IP, peer.Port)
 			if client == nil {
+				ss.selector.Record(peer.IP, peer.Port, 0, 0, ErrSyncPeerUnreachable)
 				return
 			}
 			peerConfig := &SyncPeerConfig{
@@ -251,11 +358,30 @@ func (ss *StateSync) CreateSyncConfig(peers []p2p.Peer, isBeacon bool) error {
 				port:   peer.Port,
 				client: client,
 			}
+			if ss.handshake != nil {
+				handshake, err := client.Handshake(ss.handshake)
+				if err != nil {
+					utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+						Str("peerIP", peer.IP).
+						Str("peerPort", peer.Port).
+						Msg("[SYNC] handshake failed, treating peer as legacy")
+				} else if err := ss.authenticate(client, peer.PeerID, handshake); err != nil {
+					utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
+						Str("peerIP", peer.IP).
+						Str("peerPort", peer.Port).
+						Msg("[SYNC] authentication failed, syncing as anonymous peer")
+				}
+				peerConfig.handshake = handshake
+			}
 			ss.syncConfig.AddPeer(peerConfig)
 		}(peer)
 	}
 	wg.Wait()
-	utils.Logger().Info().
+	// Close the connections to the peers no longer wanted
+	for _, old := range reusable {
+		old.client.Close()
+	}
+	utils.ModuleLogger(utils.ModuleSync).Info().
 		Int("len", len(ss.syncConfig.peers)).
 		Bool("isBeacon", isBeacon).
 		Msg("[SYNC] Finished making connection to peers")
@@ -335,13 +461,30 @@ func (sc *SyncConfig) GetBlockHashesConsensusAndCleanUp() {
 		return CompareSyncPeerConfigByblockHashes(sc.peers[i], sc.peers[j]) == -1
 	})
 	maxFirstID, maxCount := sc.getHowManyMaxConsensus()
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleSync).Info().
 		Int("maxFirstID", maxFirstID).
 		Int("maxCount", maxCount).
 		Msg("[SYNC] block consensus hashes")
 	sc.cleanUpPeers(maxFirstID)
 }
 
+// recordResponse notes the response of the peer to a request sent at start
+func (ss *StateSync) recordResponse(peerConfig *SyncPeerConfig, start time.Time, response *pb.DownloaderResponse) {
+	if response == nil {
+		ss.selector.Record(peerConfig.ip, peerConfig.port, time.Since(start), 0, ErrSyncPeerNoResponse)
+		return
+	}
+	ss.selector.Record(peerConfig.ip, peerConfig.port, time.Since(start), payloadSize(response.Payload), nil)
+}
+
+func payloadSize(payload [][]byte) int {
+	size := 0
+	for _, p := range payload {
+		size += len(p)
+	}
+	return size
+}
+
 // getConsensusHashes gets all hashes needed to download.
 func (ss *StateSync) getConsensusHashes(startHash []byte, size uint32) {
 	var wg sync.WaitGroup
@@ -350,16 +493,18 @@ func (ss *StateSync) getConsensusHashes(startHash []byte, size uint32) {
 		go func() {
 			defer wg.Done()
 
+			start := time.Now()
 			response := peerConfig.client.GetBlockHashes(startHash, size, ss.selfip, ss.selfport)
+			ss.recordResponse(peerConfig, start, response)
 			if response == nil {
-				utils.Logger().Warn().
+				utils.ModuleLogger(utils.ModuleSync).Warn().
 					Str("peerIP", peerConfig.ip).
 					Str("peerPort", peerConfig.port).
 					Msg("[SYNC] getConsensusHashes Nil Response")
 				return
 			}
 			if len(response.Payload) > int(size+1) {
-				utils.Logger().Warn().
+				utils.ModuleLogger(utils.ModuleSync).Warn().
 					Uint32("requestSize", size).
 					Int("respondSize", len(response.Payload)).
 					Msg("[SYNC] getConsensusHashes: receive more blockHahses than request!")
@@ -372,7 +517,7 @@ func (ss *StateSync) getConsensusHashes(startHash []byte, size uint32) {
 	})
 	wg.Wait()
 	ss.syncConfig.GetBlockHashesConsensusAndCleanUp()
-	utils.Logger().Info().Msg("[SYNC] Finished getting consensus block hashes")
+	utils.ModuleLogger(utils.ModuleSync).Info().Msg("[SYNC] Finished getting consensus block hashes")
 }
 
 func (ss *StateSync) generateStateSyncTaskQueue(bc *core.BlockChain) {
@@ -380,7 +525,7 @@ func (ss *StateSync) generateStateSyncTaskQueue(bc *core.BlockChain) {
 	ss.syncConfig.ForEachPeer(func(configPeer *SyncPeerConfig) (brk bool) {
 		for id, blockHash := range configPeer.blockHashes {
 			if err := ss.stateSyncTaskQueue.Put(SyncBlockTask{index: id, blockHash: blockHash}); err != nil {
-				utils.Logger().Warn().
+				utils.ModuleLogger(utils.ModuleSync).Warn().
 					Err(err).
 					Int("taskIndex", id).
 					Str("taskBlock", hex.EncodeToString(blockHash)).
@@ -390,7 +535,7 @@ func (ss *StateSync) generateStateSyncTaskQueue(bc *core.BlockChain) {
 		brk = true
 		return
 	})
-	utils.Logger().Info().Int64("length", ss.stateSyncTaskQueue.Len()).Msg("[SYNC] generateStateSyncTaskQueue: finished")
+	utils.ModuleLogger(utils.ModuleSync).Info().Int64("length", ss.stateSyncTaskQueue.Len()).Msg("[SYNC] generateStateSyncTaskQueue: finished")
 }
 
 // downloadBlocks downloads blocks from state sync task queue.
@@ -405,20 +550,26 @@ func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
 			for !stateSyncTaskQueue.Empty() {
 				task, err := ss.stateSyncTaskQueue.Poll(1, time.Millisecond)
 				if err == queue.ErrTimeout || len(task) == 0 {
-					utils.Logger().Error().Err(err).Msg("[SYNC] downloadBlocks: ss.stateSyncTaskQueue poll timeout")
+					utils.ModuleLogger(utils.ModuleSync).Error().Err(err).Msg("[SYNC] downloadBlocks: ss.stateSyncTaskQueue poll timeout")
 					break
 				}
 				syncTask := task[0].(SyncBlockTask)
 				//id := syncTask.index
+				start := time.Now()
 				payload, err := peerConfig.GetBlocks([][]byte{syncTask.blockHash})
+				if err == nil && len(payload) == 0 {
+					ss.selector.Record(peerConfig.ip, peerConfig.port, time.Since(start), 0, ErrGetBlock)
+				} else {
+					ss.selector.Record(peerConfig.ip, peerConfig.port, time.Since(start), payloadSize(payload), err)
+				}
 				if err != nil || len(payload) == 0 {
 					count++
-					utils.Logger().Error().Err(err).Int("failNumber", count).Msg("[SYNC] downloadBlocks: GetBlocks failed")
+					utils.ModuleLogger(utils.ModuleSync).Error().Err(err).Int("failNumber", count).Msg("[SYNC] downloadBlocks: GetBlocks failed")
 					if count > downloadBlocksRetryLimit {
 						break
 					}
 					if err := ss.stateSyncTaskQueue.Put(syncTask); err != nil {
-						utils.Logger().Warn().
+						utils.ModuleLogger(utils.ModuleSync).Warn().
 							Err(err).
 							Int("taskIndex", syncTask.index).
 							Str("taskBlock", hex.EncodeToString(syncTask.blockHash)).
@@ -433,12 +584,12 @@ func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
 
 				if err != nil {
 					count++
-					utils.Logger().Error().Err(err).Msg("[SYNC] downloadBlocks: failed to DecodeBytes from received new block")
+					utils.ModuleLogger(utils.ModuleSync).Error().Err(err).Msg("[SYNC] downloadBlocks: failed to DecodeBytes from received new block")
 					if count > downloadBlocksRetryLimit {
 						break
 					}
 					if err := ss.stateSyncTaskQueue.Put(syncTask); err != nil {
-						utils.Logger().Warn().
+						utils.ModuleLogger(utils.ModuleSync).Warn().
 							Err(err).
 							Int("taskIndex", syncTask.index).
 							Str("taskBlock", hex.EncodeToString(syncTask.blockHash)).
@@ -454,7 +605,7 @@ func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
 		return
 	})
 	wg.Wait()
-	utils.Logger().Info().Msg("[SYNC] downloadBlocks: finished")
+	utils.ModuleLogger(utils.ModuleSync).Info().Msg("[SYNC] downloadBlocks: finished")
 }
 
 // CompareBlockByHash compares two block by hash, it will be used in sort the blocks
@@ -509,7 +660,7 @@ func (ss *StateSync) getMaxConsensusBlockFromParentHash(parentHash common.Hash)
 	})
 	maxFirstID, maxCount := GetHowManyMaxConsensus(candidateBlocks)
 	hash := candidateBlocks[maxFirstID].Hash()
-	utils.Logger().Debug().
+	utils.ModuleLogger(utils.ModuleSync).Debug().
 		Hex("parentHash", parentHash[:]).
 		Hex("hash", hash[:]).
 		Int("maxCount", maxCount).
@@ -540,7 +691,7 @@ func (ss *StateSync) getBlockFromLastMileBlocksByParentHash(parentHash common.Ha
 // UpdateBlockAndStatus ...
 func (ss *StateSync) UpdateBlockAndStatus(block *types.Block, bc *core.BlockChain, worker *worker.Worker, verifyAllSig bool) error {
 	if block.NumberU64() != bc.CurrentBlock().NumberU64()+1 {
-		utils.Logger().Info().Uint64("curBlockNum", bc.CurrentBlock().NumberU64()).Uint64("receivedBlockNum", block.NumberU64()).Msg("[SYNC] Inappropriate block number, ignore!")
+		utils.ModuleLogger(utils.ModuleSync).Info().Uint64("curBlockNum", bc.CurrentBlock().NumberU64()).Uint64("receivedBlockNum", block.NumberU64()).Msg("[SYNC] Inappropriate block number, ignore!")
 		return nil
 	}
 
@@ -555,10 +706,10 @@ func (ss *StateSync) UpdateBlockAndStatus(block *types.Block, bc *core.BlockChai
 		if err == engine.ErrUnknownAncestor {
 			return err
 		} else if err != nil {
-			utils.Logger().Error().Err(err).Msgf("[SYNC] UpdateBlockAndStatus: failed verifying signatures for new block %d", block.NumberU64())
+			utils.ModuleLogger(utils.ModuleSync).Error().Err(err).Msgf("[SYNC] UpdateBlockAndStatus: failed verifying signatures for new block %d", block.NumberU64())
 
 			if !verifyAllSig {
-				utils.Logger().Debug().Interface("block", bc.CurrentBlock()).Msg("[SYNC] UpdateBlockAndStatus: Rolling back last 99 blocks!")
+				utils.ModuleLogger(utils.ModuleSync).Debug().Interface("block", bc.CurrentBlock()).Msg("[SYNC] UpdateBlockAndStatus: Rolling back last 99 blocks!")
 				for i := uint64(0); i < verifyHeaderBatchSize-1; i++ {
 					bc.Rollback([]common.Hash{bc.CurrentBlock().Hash()})
 				}
@@ -567,9 +718,9 @@ func (ss *StateSync) UpdateBlockAndStatus(block *types.Block, bc *core.BlockChai
 		}
 	}
 
-	_, err := bc.InsertChain([]*types.Block{block}, false /* verifyHeaders */)
+	err := bc.InsertPipeline().Insert(block, core.InsertSync, false /* verifyHeaders */)
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleSync).Error().
 			Err(err).
 			Msgf(
 				"[SYNC] UpdateBlockAndStatus: Error adding new block to blockchain %d %d",
@@ -577,20 +728,20 @@ func (ss *StateSync) UpdateBlockAndStatus(block *types.Block, bc *core.BlockChai
 				block.ShardID(),
 			)
 
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleSync).Debug().
 			Interface("block", bc.CurrentBlock()).
 			Msg("[SYNC] UpdateBlockAndStatus: Rolling back current block!")
 		bc.Rollback([]common.Hash{bc.CurrentBlock().Hash()})
 		return err
 	}
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleSync).Info().
 		Uint64("blockHeight", block.NumberU64()).
 		Uint64("blockEpoch", block.Epoch().Uint64()).
 		Str("blockHex", block.Hash().Hex()).
 		Uint32("ShardID", block.ShardID()).
 		Msg("[SYNC] UpdateBlockAndStatus: New Block Added to Blockchain")
 	for i, tx := range block.StakingTransactions() {
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleSync).Info().
 			Msgf(
 				"StakingTxn %d: %s, %v", i, tx.StakingType().String(), tx.StakingMessage(),
 			)
@@ -663,10 +814,20 @@ func (ss *StateSync) ProcessStateSync(startHash []byte, size uint32, bc *core.Bl
 	ss.getConsensusHashes(startHash, size)
 	ss.generateStateSyncTaskQueue(bc)
 	// Download blocks.
-	if ss.stateSyncTaskQueue.Len() > 0 {
+	queued := ss.stateSyncTaskQueue.Len()
+	if queued > 0 {
 		ss.downloadBlocks(bc)
 	}
-	return ss.generateNewState(bc, worker)
+	if queued < BulkImportThreshold {
+		return ss.generateNewState(bc, worker)
+	}
+	// Far behind, write the blocks to disk in batches
+	bc.BeginBulkImport(BulkImportInterval)
+	err := ss.generateNewState(bc, worker)
+	if flushErr := bc.EndBulkImport(); err == nil {
+		err = flushErr
+	}
+	return err
 }
 
 func (peerConfig *SyncPeerConfig) registerToBroadcast(peerHash []byte, ip, port string) error {
@@ -683,14 +844,14 @@ func (peerConfig *SyncPeerConfig) registerToBroadcast(peerHash []byte, ip, port
 // return number of successful registration
 func (ss *StateSync) RegisterNodeInfo() int {
 	registrationNumber := RegistrationNumber
-	utils.Logger().Debug().
+	utils.ModuleLogger(utils.ModuleSync).Debug().
 		Int("registrationNumber", registrationNumber).
 		Int("activePeerNumber", len(ss.syncConfig.peers)).
 		Msg("[SYNC] node registration to peers")
 
 	count := 0
 	ss.syncConfig.ForEachPeer(func(peerConfig *SyncPeerConfig) (brk bool) {
-		logger := utils.Logger().With().Str("peerPort", peerConfig.port).Str("peerIP", peerConfig.ip).Logger()
+		logger := utils.ModuleLogger(utils.ModuleSync).With().Str("peerPort", peerConfig.port).Str("peerIP", peerConfig.ip).Logger()
 		if count >= registrationNumber {
 			brk = true
 			return
@@ -717,45 +878,18 @@ func (ss *StateSync) RegisterNodeInfo() int {
 	return count
 }
 
-// getMaxPeerHeight gets the maximum blockchain heights from peers
-func (ss *StateSync) getMaxPeerHeight(isBeacon bool) uint64 {
-	maxHeight := uint64(0)
-	var wg sync.WaitGroup
-	ss.syncConfig.ForEachPeer(func(peerConfig *SyncPeerConfig) (brk bool) {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			//debug
-			// utils.Logger().Debug().Bool("isBeacon", isBeacon).Str("peerIP", peerConfig.ip).Str("peerPort", peerConfig.port).Msg("[Sync]getMaxPeerHeight")
-			response, err := peerConfig.client.GetBlockChainHeight()
-			if err != nil {
-				utils.Logger().Warn().Err(err).Str("peerIP", peerConfig.ip).Str("peerPort", peerConfig.port).Msg("[Sync]GetBlockChainHeight failed")
-				return
-			}
-			ss.syncMux.Lock()
-			if response != nil && maxHeight < response.BlockHeight {
-				maxHeight = response.BlockHeight
-			}
-			ss.syncMux.Unlock()
-		}()
-		return
-	})
-	wg.Wait()
-	return maxHeight
-}
-
 // IsSameBlockchainHeight checks whether the node is out of sync from other peers
 func (ss *StateSync) IsSameBlockchainHeight(bc *core.BlockChain) (uint64, bool) {
-	otherHeight := ss.getMaxPeerHeight(false)
+	otherHeight := ss.ShardHeight(bc.ShardID()).Height
 	currentHeight := bc.CurrentBlock().NumberU64()
 	return otherHeight, currentHeight == otherHeight
 }
 
 // IsOutOfSync checks whether the node is out of sync from other peers
 func (ss *StateSync) IsOutOfSync(bc *core.BlockChain) bool {
-	otherHeight := ss.getMaxPeerHeight(false)
+	otherHeight := ss.ShardHeight(bc.ShardID()).Height
 	currentHeight := bc.CurrentBlock().NumberU64()
-	utils.Logger().Debug().
+	utils.ModuleLogger(utils.ModuleSync).Debug().
 		Uint64("OtherHeight", otherHeight).
 		Uint64("MyHeight", currentHeight).
 		Bool("IsOutOfSync", currentHeight+inSyncThreshold < otherHeight).
@@ -772,15 +906,15 @@ func (ss *StateSync) SyncLoop(bc *core.BlockChain, worker *worker.Worker, isBeac
 	ticker := time.NewTicker(SyncLoopFrequency * time.Second)
 	defer ticker.Stop()
 	for range ticker.C {
-		otherHeight := ss.getMaxPeerHeight(isBeacon)
+		otherHeight := ss.ShardHeight(bc.ShardID()).Height
 		currentHeight := bc.CurrentBlock().NumberU64()
 		if currentHeight >= otherHeight {
-			utils.Logger().Info().
+			utils.ModuleLogger(utils.ModuleSync).Info().
 				Msgf("[SYNC] Node is now IN SYNC! (isBeacon: %t, ShardID: %d, otherHeight: %d, currentHeight: %d)",
 					isBeacon, bc.ShardID(), otherHeight, currentHeight)
 			return
 		}
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleSync).Debug().
 			Msgf("[SYNC] Node is OUT OF SYNC (isBeacon: %t, ShardID: %d, otherHeight: %d, currentHeight: %d)",
 				isBeacon, bc.ShardID(), otherHeight, currentHeight)
 
@@ -791,7 +925,7 @@ func (ss *StateSync) SyncLoop(bc *core.BlockChain, worker *worker.Worker, isBeac
 		}
 		err := ss.ProcessStateSync(startHash[:], size, bc, worker)
 		if err != nil {
-			utils.Logger().Error().Err(err).
+			utils.ModuleLogger(utils.ModuleSync).Error().Err(err).
 				Msgf("[SYNC] ProcessStateSync failed (isBeacon: %t, ShardID: %d, otherHeight: %d, currentHeight: %d)",
 					isBeacon, bc.ShardID(), otherHeight, currentHeight)
 		}
@@ -49,3 +49,14 @@ func TestCreateStateSync(t *testing.T) {
 		t.Error("Unable to create stateSync")
 	}
 }
+
+func TestSyncPeerConfigHandshake(t *testing.T) {
+	syncPeerConfig := CreateTestSyncPeerConfig(&downloader.Client{}, nil)
+	assert.Equal(t, uint32(downloader.LegacyProtocolVersion), syncPeerConfig.ProtocolVersion(), "legacy peer version")
+	assert.False(t, syncPeerConfig.SupportsFeature(downloader.FeatureRangeRequest), "legacy peer has no feature")
+
+	syncPeerConfig.handshake = downloader.NewHandshake(1, "Validator", "", false)
+	assert.Equal(t, uint32(downloader.ProtocolVersion), syncPeerConfig.ProtocolVersion(), "negotiated version")
+	assert.True(t, syncPeerConfig.SupportsFeature(downloader.FeatureRangeRequest), "range request supported")
+	assert.False(t, syncPeerConfig.SupportsFeature(downloader.FeatureSnapshots), "snapshots not supported")
+}
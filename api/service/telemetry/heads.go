@@ -0,0 +1,187 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// Constants for the head collector
+const (
+	// HeadBeaconTTL is the age after which the head reported by a validator is
+	// dropped from the distribution
+	HeadBeaconTTL = 5 * time.Minute
+	// maxClockDrift is how far in the future a head beacon may be dated
+	maxClockDrift = 30 * time.Second
+)
+
+var (
+	errStaleBeacon      = errors.New("head beacon older than its time to live")
+	errFutureBeacon     = errors.New("head beacon dated in the future")
+	errReplayedBeacon   = errors.New("head beacon not newer than the known one of its key")
+	errNotCommitteeKey  = errors.New("head beacon key not in the committee of its shard")
+	errInvalidBeaconSig = errors.New("invalid head beacon signature")
+)
+
+// MemberFunc tells whether a BLS key is in the committee of a shard
+type MemberFunc func(shardID uint32, key *bls.PublicKey) bool
+
+// HeadCount is a head of a shard along with the number of validators reporting it
+type HeadCount struct {
+	Height    uint64
+	Hash      common.Hash
+	Reporters int
+}
+
+// ShardHeads is the distribution of the heads of a shard reported by its validators
+type ShardHeads struct {
+	ShardID   uint32
+	Reporters int         // number of validator keys which reported their head
+	Height    uint64      // median height of the reported heads
+	MinHeight uint64      // lowest reported height
+	MaxHeight uint64      // highest reported height
+	MaxViewID uint64      // highest reported view id
+	Heads     []HeadCount // distinct heads, the highest first
+	UpdatedAt time.Time   // time of the latest beacon
+}
+
+// HeadCollector aggregates the head beacons of the validators of all shards
+type HeadCollector struct {
+	lock     sync.Mutex
+	isMember MemberFunc
+	heads    map[uint32]map[shard.BLSPublicKey]proto_node.HeadBeacon
+}
+
+// NewHeadCollector creates an empty head collector accepting the beacons
+// signed by the committee keys of their shard
+func NewHeadCollector(isMember MemberFunc) *HeadCollector {
+	return &HeadCollector{
+		isMember: isMember,
+		heads:    map[uint32]map[shard.BLSPublicKey]proto_node.HeadBeacon{},
+	}
+}
+
+// Add verifies a head beacon and records it as the latest head of its key
+func (c *HeadCollector) Add(beacon *proto_node.HeadBeacon, now time.Time) error {
+	at := time.Unix(int64(beacon.Time), 0)
+	if now.Sub(at) > HeadBeaconTTL {
+		return errStaleBeacon
+	}
+	if at.Sub(now) > maxClockDrift {
+		return errFutureBeacon
+	}
+	c.lock.Lock()
+	known, ok := c.heads[beacon.ShardID][beacon.PubKey]
+	c.lock.Unlock()
+	if ok && known.Time >= beacon.Time {
+		return errReplayedBeacon
+	}
+
+	pubKey := &bls.PublicKey{}
+	if err := beacon.PubKey.ToLibBLSPublicKey(pubKey); err != nil {
+		return errors.Wrap(err, "cannot parse head beacon key")
+	}
+	if c.isMember != nil && !c.isMember(beacon.ShardID, pubKey) {
+		return errNotCommitteeKey
+	}
+	sig := &bls.Sign{}
+	if err := sig.Deserialize(beacon.Signature); err != nil {
+		return errors.Wrap(err, "cannot parse head beacon signature")
+	}
+	hash := beacon.SigningHash()
+	if !sig.VerifyHash(pubKey, hash[:]) {
+		return errInvalidBeaconSig
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	heads, ok := c.heads[beacon.ShardID]
+	if !ok {
+		heads = map[shard.BLSPublicKey]proto_node.HeadBeacon{}
+		c.heads[beacon.ShardID] = heads
+	}
+	if known, ok := heads[beacon.PubKey]; !ok || known.Time < beacon.Time {
+		heads[beacon.PubKey] = *beacon
+	}
+	return nil
+}
+
+// Distribution drops the expired heads and returns the distribution of the
+// heads of each shard, ordered by shard id. It exports it to the metrics too.
+func (c *HeadCollector) Distribution(now time.Time) []ShardHeads {
+	c.lock.Lock()
+	result := make([]ShardHeads, 0, len(c.heads))
+	for shardID, heads := range c.heads {
+		beacons := make([]proto_node.HeadBeacon, 0, len(heads))
+		for key, beacon := range heads {
+			if now.Sub(time.Unix(int64(beacon.Time), 0)) > HeadBeaconTTL {
+				delete(heads, key)
+				continue
+			}
+			beacons = append(beacons, beacon)
+		}
+		if len(beacons) == 0 {
+			delete(c.heads, shardID)
+			continue
+		}
+		result = append(result, shardHeads(shardID, beacons))
+	}
+	c.lock.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ShardID < result[j].ShardID
+	})
+	for _, heads := range result {
+		prefix := fmt.Sprintf("telemetry/shard/%d/", heads.ShardID)
+		metrics.GetOrRegisterGauge(prefix+"height", nil).Update(int64(heads.Height))
+		metrics.GetOrRegisterGauge(prefix+"minheight", nil).Update(int64(heads.MinHeight))
+		metrics.GetOrRegisterGauge(prefix+"maxheight", nil).Update(int64(heads.MaxHeight))
+		metrics.GetOrRegisterGauge(prefix+"reporters", nil).Update(int64(heads.Reporters))
+		metrics.GetOrRegisterGauge(prefix+"heads", nil).Update(int64(len(heads.Heads)))
+	}
+	return result
+}
+
+// shardHeads computes the distribution of the given non empty head beacons of a shard
+func shardHeads(shardID uint32, beacons []proto_node.HeadBeacon) ShardHeads {
+	result := ShardHeads{ShardID: shardID, Reporters: len(beacons)}
+	heights := make([]uint64, len(beacons))
+	counts := map[HeadCount]int{}
+	for i, beacon := range beacons {
+		heights[i] = beacon.Height
+		counts[HeadCount{Height: beacon.Height, Hash: beacon.Hash}]++
+		if beacon.ViewID > result.MaxViewID {
+			result.MaxViewID = beacon.ViewID
+		}
+		if at := time.Unix(int64(beacon.Time), 0); at.After(result.UpdatedAt) {
+			result.UpdatedAt = at
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	result.MaxHeight = heights[0]
+	result.MinHeight = heights[len(heights)-1]
+	result.Height = heights[(len(heights)-1)/2]
+
+	for head, count := range counts {
+		head.Reporters = count
+		result.Heads = append(result.Heads, head)
+	}
+	sort.Slice(result.Heads, func(i, j int) bool {
+		if result.Heads[i].Height != result.Heads[j].Height {
+			return result.Heads[i].Height > result.Heads[j].Height
+		}
+		if result.Heads[i].Reporters != result.Heads[j].Reporters {
+			return result.Heads[i].Reporters > result.Heads[j].Reporters
+		}
+		return result.Heads[i].Hash.Hex() < result.Heads[j].Hash.Hex()
+	})
+	return result
+}
@@ -0,0 +1,70 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	internal_bls "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedBeacon(t *testing.T, key *bls.SecretKey, height uint64, hash common.Hash, at time.Time) *proto_node.HeadBeacon {
+	beacon := &proto_node.HeadBeacon{
+		ShardID: 1,
+		Height:  height,
+		Hash:    hash,
+		ViewID:  height + 1,
+		Time:    uint64(at.Unix()),
+	}
+	assert.NoError(t, beacon.Sign(key))
+	return beacon
+}
+
+func TestHeadCollectorAdd(t *testing.T) {
+	member, outsider := internal_bls.RandPrivateKey(), internal_bls.RandPrivateKey()
+	collector := NewHeadCollector(func(shardID uint32, key *bls.PublicKey) bool {
+		return shardID == 1 && key.IsEqual(member.GetPublicKey())
+	})
+	now := time.Now()
+	hash := common.HexToHash("0xa")
+
+	assert.NoError(t, collector.Add(signedBeacon(t, member, 10, hash, now), now))
+	assert.Equal(t, errReplayedBeacon, collector.Add(signedBeacon(t, member, 11, hash, now), now))
+	assert.Equal(t, errNotCommitteeKey, collector.Add(signedBeacon(t, outsider, 10, hash, now), now))
+	assert.Equal(t, errStaleBeacon, collector.Add(signedBeacon(t, member, 10, hash, now.Add(-2*HeadBeaconTTL)), now))
+	assert.Equal(t, errFutureBeacon, collector.Add(signedBeacon(t, member, 10, hash, now.Add(time.Hour)), now))
+
+	forged := signedBeacon(t, member, 12, hash, now.Add(time.Second))
+	forged.Height++
+	assert.Equal(t, errInvalidBeaconSig, collector.Add(forged, now))
+}
+
+func TestHeadCollectorDistribution(t *testing.T) {
+	collector := NewHeadCollector(nil)
+	now := time.Now()
+	hashA, hashB := common.HexToHash("0xa"), common.HexToHash("0xb")
+	for _, height := range []uint64{10, 10, 9} {
+		assert.NoError(t, collector.Add(signedBeacon(t, internal_bls.RandPrivateKey(), height, hashA, now), now))
+	}
+	assert.NoError(t, collector.Add(signedBeacon(t, internal_bls.RandPrivateKey(), 10, hashB, now), now))
+
+	all := collector.Distribution(now)
+	assert.Len(t, all, 1)
+	heads := all[0]
+	assert.Equal(t, uint32(1), heads.ShardID)
+	assert.Equal(t, 4, heads.Reporters)
+	assert.Equal(t, uint64(10), heads.Height)
+	assert.Equal(t, uint64(9), heads.MinHeight)
+	assert.Equal(t, uint64(10), heads.MaxHeight)
+	assert.Equal(t, uint64(11), heads.MaxViewID)
+	assert.Equal(t, []HeadCount{
+		{Height: 10, Hash: hashA, Reporters: 2},
+		{Height: 10, Hash: hashB, Reporters: 1},
+		{Height: 9, Hash: hashA, Reporters: 1},
+	}, heads.Heads)
+
+	assert.Empty(t, collector.Distribution(now.Add(2*HeadBeaconTTL)))
+}
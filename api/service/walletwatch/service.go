@@ -0,0 +1,266 @@
+package walletwatch
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/common/denominations"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	common2 "github.com/harmony-one/harmony/internal/common"
+	"github.com/harmony-one/harmony/internal/utils"
+	staking "github.com/harmony-one/harmony/staking/types"
+)
+
+// maxDelegationChanges is the number of the most recent delegation changes
+// kept for each watched wallet
+const maxDelegationChanges = 100
+
+// DelegationChange is a change of the amount delegated to a watched validator
+type DelegationChange struct {
+	BlockNumber uint64   `json:"block-number"`
+	Delegator   string   `json:"delegator"`
+	OldAmount   *big.Int `json:"old-amount"`
+	NewAmount   *big.Int `json:"new-amount"`
+}
+
+// WalletStatus is the status of a watched validator wallet as of a block
+type WalletStatus struct {
+	Address           string             `json:"address"`
+	BlockNumber       uint64             `json:"block-number"`
+	Balance           *big.Int           `json:"balance"`
+	SelfStake         *big.Int           `json:"self-stake"`
+	TotalDelegation   *big.Int           `json:"total-delegation"`
+	AccumulatedReward *big.Int           `json:"reward-accumulated"`
+	RewardSinceStart  *big.Int           `json:"reward-since-start"`
+	DelegationChanges []DelegationChange `json:"delegation-changes"`
+
+	startReward *big.Int
+	delegations map[common.Address]*big.Int
+}
+
+// Service watches the balance, the block rewards and the delegations of the
+// validator wallets run by the node after each block of the beacon chain.
+type Service struct {
+	chain       *core.BlockChain
+	addresses   func() []common.Address
+	messageChan chan *msg_pb.Message
+	stopChan    chan struct{}
+	stoppedChan chan struct{}
+
+	mutex    sync.RWMutex
+	statuses map[common.Address]*WalletStatus
+}
+
+// New returns a wallet watch service following the given beacon chain, which
+// watches the wallets returned by addresses.
+func New(chain *core.BlockChain, addresses func() []common.Address) *Service {
+	return &Service{
+		chain:     chain,
+		addresses: addresses,
+		statuses:  map[common.Address]*WalletStatus{},
+	}
+}
+
+// StartService starts the wallet watch service.
+func (s *Service) StartService() {
+	utils.Logger().Info().Msg("Starting wallet watch service.")
+	s.stopChan = make(chan struct{})
+	s.stoppedChan = make(chan struct{})
+	go s.run()
+}
+
+func (s *Service) run() {
+	defer close(s.stoppedChan)
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := s.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	s.update(s.chain.CurrentBlock())
+	for {
+		select {
+		case head := <-heads:
+			s.update(head.Block)
+		case err := <-sub.Err():
+			utils.Logger().Warn().Err(err).Msg("[WalletWatch] chain subscription failed")
+			return
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// update refreshes the status of the watched wallets as of the given block
+func (s *Service) update(block *types.Block) {
+	if block == nil {
+		return
+	}
+	state, err := s.chain.StateAt(block.Root())
+	if err != nil {
+		utils.Logger().Warn().Err(err).
+			Uint64("blockNum", block.NumberU64()).
+			Msg("[WalletWatch] cannot read state")
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, addr := range s.addresses() {
+		status, ok := s.statuses[addr]
+		if !ok {
+			status = &WalletStatus{Address: common2.MustAddressToBech32(addr)}
+			s.statuses[addr] = status
+		}
+		status.BlockNumber = block.NumberU64()
+		status.Balance = state.GetBalance(addr)
+		if wrapper, err := state.ValidatorWrapper(addr); err == nil {
+			status.updateStaking(block.NumberU64(), wrapper)
+		}
+		status.updateMetrics()
+	}
+}
+
+// updateStaking records the rewards and the delegations of the validator
+func (status *WalletStatus) updateStaking(
+	blockNum uint64, wrapper *staking.ValidatorWrapper,
+) {
+	if status.startReward == nil {
+		status.startReward = new(big.Int).Set(wrapper.BlockReward)
+	}
+	status.AccumulatedReward = new(big.Int).Set(wrapper.BlockReward)
+	status.RewardSinceStart = new(big.Int).Sub(wrapper.BlockReward, status.startReward)
+
+	status.TotalDelegation = big.NewInt(0)
+	status.SelfStake = big.NewInt(0)
+	delegations := map[common.Address]*big.Int{}
+	for _, d := range wrapper.Delegations {
+		delegations[d.DelegatorAddress] = d.Amount
+		status.TotalDelegation.Add(status.TotalDelegation, d.Amount)
+		if d.DelegatorAddress == wrapper.Address {
+			status.SelfStake.Set(d.Amount)
+		}
+	}
+	if status.delegations != nil {
+		status.recordDelegationChanges(blockNum, delegations)
+	}
+	status.delegations = delegations
+}
+
+// recordDelegationChanges records the differences between the previously seen
+// delegations and the given ones
+func (status *WalletStatus) recordDelegationChanges(
+	blockNum uint64, delegations map[common.Address]*big.Int,
+) {
+	zero := big.NewInt(0)
+	changes := []DelegationChange{}
+	for delegator, amount := range delegations {
+		old, ok := status.delegations[delegator]
+		if !ok {
+			old = zero
+		}
+		if old.Cmp(amount) != 0 {
+			changes = append(changes, DelegationChange{
+				BlockNumber: blockNum,
+				Delegator:   common2.MustAddressToBech32(delegator),
+				OldAmount:   old,
+				NewAmount:   amount,
+			})
+		}
+	}
+	for delegator, old := range status.delegations {
+		if _, ok := delegations[delegator]; !ok {
+			changes = append(changes, DelegationChange{
+				BlockNumber: blockNum,
+				Delegator:   common2.MustAddressToBech32(delegator),
+				OldAmount:   old,
+				NewAmount:   zero,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Delegator < changes[j].Delegator
+	})
+	status.DelegationChanges = append(status.DelegationChanges, changes...)
+	if n := len(status.DelegationChanges); n > maxDelegationChanges {
+		status.DelegationChanges = status.DelegationChanges[n-maxDelegationChanges:]
+	}
+}
+
+// updateMetrics reports the status of the wallet in ONE
+func (status *WalletStatus) updateMetrics() {
+	prefix := "walletwatch/" + status.Address + "/"
+	gauge := func(name string, value *big.Int) {
+		if value == nil {
+			return
+		}
+		one, _ := new(big.Float).Quo(
+			new(big.Float).SetInt(value), big.NewFloat(denominations.One),
+		).Float64()
+		metrics.GetOrRegisterGaugeFloat64(prefix+name, nil).Update(one)
+	}
+	gauge("balance", status.Balance)
+	gauge("selfstake", status.SelfStake)
+	gauge("delegation", status.TotalDelegation)
+	gauge("reward", status.AccumulatedReward)
+}
+
+// Statuses returns a copy of the status of each watched wallet
+func (s *Service) Statuses() []WalletStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	statuses := make([]WalletStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		copied := *status
+		copied.DelegationChanges = append(
+			[]DelegationChange{}, status.DelegationChanges...,
+		)
+		statuses = append(statuses, copied)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Address < statuses[j].Address
+	})
+	return statuses
+}
+
+// StopService stops the wallet watch service.
+func (s *Service) StopService() {
+	utils.Logger().Info().Msg("Stopping wallet watch service.")
+	close(s.stopChan)
+	<-s.stoppedChan
+	utils.Logger().Info().Msg("Wallet watch service stopped.")
+}
+
+// NotifyService notify service
+func (s *Service) NotifyService(params map[string]interface{}) {}
+
+// SetMessageChan sets up message channel to service.
+func (s *Service) SetMessageChan(messageChan chan *msg_pb.Message) {
+	s.messageChan = messageChan
+}
+
+// APIs for the services.
+func (s *Service) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   &PrivateWalletWatchAPI{s},
+			Public:    false,
+		},
+	}
+}
+
+// PrivateWalletWatchAPI exposes the watched validator wallets to the node
+// operator.
+type PrivateWalletWatchAPI struct {
+	s *Service
+}
+
+// WatchedWallets returns the status of the validator wallets run by the node.
+func (api *PrivateWalletWatchAPI) WatchedWallets() []WalletStatus {
+	return api.s.Statuses()
+}
@@ -0,0 +1,68 @@
+package walletwatch
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	staking "github.com/harmony-one/harmony/staking/types"
+)
+
+func TestUpdateStaking(t *testing.T) {
+	validator := common.BigToAddress(big.NewInt(1))
+	delegator := common.BigToAddress(big.NewInt(2))
+	newDelegator := common.BigToAddress(big.NewInt(3))
+	wrapper := func(reward int64, delegations ...staking.Delegation) *staking.ValidatorWrapper {
+		w := &staking.ValidatorWrapper{
+			Delegations: delegations,
+			BlockReward: big.NewInt(reward),
+		}
+		w.Address = validator
+		return w
+	}
+	delegation := func(addr common.Address, amount int64) staking.Delegation {
+		return staking.NewDelegation(addr, big.NewInt(amount))
+	}
+
+	status := &WalletStatus{}
+	status.updateStaking(10, wrapper(100,
+		delegation(validator, 1000), delegation(delegator, 50),
+	))
+	if len(status.DelegationChanges) != 0 {
+		t.Errorf("got %d delegation changes on first update, want 0",
+			len(status.DelegationChanges))
+	}
+	status.updateStaking(11, wrapper(130,
+		delegation(validator, 1000), delegation(newDelegator, 20),
+	))
+
+	if status.SelfStake.Int64() != 1000 {
+		t.Errorf("self stake = %v, want 1000", status.SelfStake)
+	}
+	if status.TotalDelegation.Int64() != 1020 {
+		t.Errorf("total delegation = %v, want 1020", status.TotalDelegation)
+	}
+	if status.RewardSinceStart.Int64() != 30 {
+		t.Errorf("reward since start = %v, want 30", status.RewardSinceStart)
+	}
+	if len(status.DelegationChanges) != 2 {
+		t.Fatalf("got %d delegation changes, want 2", len(status.DelegationChanges))
+	}
+	for _, change := range status.DelegationChanges {
+		if change.BlockNumber != 11 {
+			t.Errorf("change at block %d, want 11", change.BlockNumber)
+		}
+		switch change.NewAmount.Int64() {
+		case 0:
+			if change.OldAmount.Int64() != 50 {
+				t.Errorf("undelegated %v, want 50", change.OldAmount)
+			}
+		case 20:
+			if change.OldAmount.Sign() != 0 {
+				t.Errorf("new delegation from %v, want 0", change.OldAmount)
+			}
+		default:
+			t.Errorf("unexpected delegation change %+v", change)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -19,18 +20,24 @@ import (
 	"time"
 
 	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/api/service/storageguard"
 	"github.com/harmony-one/harmony/api/service/syncing"
+	"github.com/harmony-one/harmony/api/service/syncing/downloader"
 	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/consensus/ledger"
 	"github.com/harmony-one/harmony/consensus/quorum"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/internal/blsgen"
+	"github.com/harmony-one/harmony/internal/checkpoint"
 	"github.com/harmony-one/harmony/internal/common"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	viperconfig "github.com/harmony-one/harmony/internal/configs/viper"
 	"github.com/harmony-one/harmony/internal/genesis"
+	"github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/shardchain"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/multibls"
@@ -39,6 +46,7 @@ import (
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/shard"
 	"github.com/harmony-one/harmony/webhooks"
+	libp2p "github.com/libp2p/go-libp2p"
 	"github.com/pkg/errors"
 )
 
@@ -66,22 +74,48 @@ var (
 	port        = flag.String("port", "9000", "port of the node.")
 	logFolder   = flag.String("log_folder", "latest", "the folder collecting the logs of this execution")
 	logMaxSize  = flag.Int("log_max_size", 100, "the max size in megabytes of the log file before it gets rotated")
+	logMaxAge   = flag.String("log_max_age", "0s", "the age of the log file it gets rotated at, ex: 24h; 0 rotates by size only")
+	logBackups  = flag.Int("log_max_backups", 0, "the number of rotated log files kept (default: 0, keep all)")
 	freshDB     = flag.Bool("fresh_db", false, "true means the existing disk based db will be removed")
-	pprof       = flag.String("pprof", "", "what address and port the pprof profiling server should listen on")
+	pprof       = flag.String("pprof", "", "what address and port the debug server of the pprof profiles and the analysis timers (/debug/timers) should listen on")
+	healthz     = flag.String("healthz", "", "what address and port the /healthz server should listen on")
 	versionFlag = flag.Bool("version", false, "Output version info")
 	onlyLogTps  = flag.Bool("only_log_tps", false, "Only log TPS if true")
 	dnsZone     = flag.String("dns_zone", "", "if given and not empty, use peers from the zone (default: use libp2p peer discovery instead)")
 	dnsFlag     = flag.Bool("dns", true, "[deprecated] equivalent to -dns_zone t.hmny.io")
+	dnsSeed     = flag.String("dns_seed", "", "if given and not empty, DNS name resolved to seed peers when bootnodes are unreachable")
+	staticPeers = flag.String("static_peers", "", "comma separated multiaddrs of peers always kept connected")
+	trustPeers  = flag.String("trusted_peers", "", "comma separated IDs of peers exempt from rate limiting")
+	sentries    = flag.String("sentries", "", "comma separated multiaddrs of sentries to connect through, hiding this validator from the network")
+	sentryFor   = flag.String("sentry_for", "", "comma separated IDs of validators to relay messages for as their sentry")
+	// Addresses advertised to the peers, for the nodes behind load balancers or NAT
+	advertiseAddrs  = flag.String("advertise_addrs", "", "comma separated external multiaddrs advertised to the peers ahead of the listen addresses")
+	suppressPrivate = flag.Bool("suppress_private_addrs", false, "do not advertise the private and loopback listen addresses")
 	//Leader needs to have a minimal number of peers to start consensus
 	minPeers = flag.Int("min_peers", 32, "Minimal number of Peers in shard")
+	// Operators allowed to force a view change on a stuck shard through the admin API
+	viewChangeOperators = flag.String("view_change_operators", "", "comma separated BLS public keys of the operators allowed to force a view change")
+	viewChangeThreshold = flag.Int("view_change_threshold", 1, "number of operators who must approve a forced view change")
 	// Key file to store the private key
-	keyFile = flag.String("key", "./.hmykey", "the p2p key file of the harmony node")
+	keyFile = flag.String("key", "", "the p2p key file of the harmony node (default: .hmykey under -db_dir)")
 	// isArchival indicates this node is an archival node that will save and archive current blockchain
 	isArchival = flag.Bool("is_archival", false, "false will enable cached state pruning")
+	// shardChainIdleTimeout is how long other shard chains than the node's own stay open unused
+	shardChainIdleTimeout = flag.String("shardchain_idle_timeout", "0s", "close other shard chains than the node's own after being unused this long, ex: 10m; 0 keeps them open")
+	// Transaction pool limits, also adjustable at runtime through the admin API
+	txPoolPriceBump    = flag.Uint("txpool_price_bump", uint(core.DefaultTxPoolConfig.PriceBump), "minimum price bump percentage to replace a pending transaction")
+	txPoolAccountSlots = flag.Uint("txpool_account_slots", uint(core.DefaultTxPoolConfig.AccountSlots), "number of executable transaction slots guaranteed per account")
+	txPoolGlobalSlots  = flag.Uint("txpool_global_slots", uint(core.DefaultTxPoolConfig.GlobalSlots), "maximum number of executable transaction slots for all accounts")
+	txPoolAccountQueue = flag.Uint("txpool_account_queue", uint(core.DefaultTxPoolConfig.AccountQueue), "maximum number of non-executable transaction slots permitted per account")
+	txPoolGlobalQueue  = flag.Uint("txpool_global_queue", uint(core.DefaultTxPoolConfig.GlobalQueue), "maximum number of non-executable transaction slots for all accounts")
+	txPoolLifetime     = flag.String("txpool_lifetime", core.DefaultTxPoolConfig.Lifetime.String(), "maximum amount of time non-executable transactions are queued")
+	// Broadcast of the transactions received, see nodeconfig.TxBroadcastConfig
+	txBroadcastClients = flag.String("tx_broadcast_clients", "", "whether transactions are also published to the client group of their shard, true or false (default: per network type)")
+	txFanout           = flag.String("tx_fanout", "", "how transactions are sent to the validators of their shard: all, or leader for a direct stream to the shard leader (default: per network type)")
 	// delayCommit is the commit-delay timer, used by Harmony nodes
 	delayCommit = flag.String("delay_commit", "0ms", "how long to delay sending commit messages in consensus, ex: 500ms, 1s")
-	// nodeType indicates the type of the node: validator, explorer
-	nodeType = flag.String("node_type", "validator", "node type: validator, explorer")
+	// nodeType indicates the type of the node: validator, explorer, rpc
+	nodeType = flag.String("node_type", "validator", "node type: validator, explorer, rpc")
 	// networkType indicates the type of the network
 	networkType = flag.String("network_type", "mainnet", "type of the network: mainnet, testnet, pangaea, partner, stressnet, devnet, localnet")
 	// blockPeriod indicates the how long the leader waits to propose a new block.
@@ -114,14 +148,61 @@ var (
 	webHookYamlPath = flag.String(
 		"webhook_yaml", "", "path for yaml config reporting double signing",
 	)
+	// Trusted checkpoint to sync a fresh chain from instead of genesis
+	checkpointURL     = flag.String("checkpoint_url", "", "https URL of a JSON list of signed checkpoints to sync a fresh chain from")
+	checkpointDNS     = flag.String("checkpoint_dns", "", "DNS name whose TXT records hold signed checkpoints to sync a fresh chain from")
+	checkpointSigners = flag.String("checkpoint_signers", "", "comma separated addresses trusted to sign checkpoints, in addition to the built-in ones")
+	// Consensus vote ledger
+	voteLedgerRetention = flag.Uint("vote_ledger_retention", 0, "number of blocks to keep the consensus votes of in the vote ledger (default: 0, no vote ledger)")
+	// Bad block diagnosis
+	diagnoseBadBlocks = flag.Bool("diagnose_bad_blocks", false, "re-execute the proposed blocks failing verification against their header and log the divergent transaction")
+	// Cross shard delivery tracking
+	cxDeliveryTracking = flag.Bool("cx_delivery_tracking", false, "record the delivery of the outgoing cross shard transfers and notify the source shards of the receipts spent")
+	// Chain head telemetry
+	headBeaconInterval = flag.String("head_beacon_interval", "1m", "interval at which a validator publishes the signed head of its shard to the telemetry group, ex: 30s; 0 for never")
+	headCollector      = flag.Bool("head_collector", false, "collect the head beacons of the validators of all shards, for the head distribution RPC and metrics")
+	// Reward history
+	rewardIndex = flag.Bool("reward_index", false, "index the block rewards and undelegations paid out by the beacon chain, for the reward history RPC")
+	// State pruning
+	statePruneRetention = flag.Uint("state_prune_retention", 0, "number of blocks to keep the state of, pruning older state between block imports (default: 0, no pruning)")
+	statePruneBudget    = flag.String("state_prune_budget", "50ms", "longest pause of the block imports for state pruning, ex: 50ms")
+	dbCheckDepth        = flag.Uint("db_check_depth", core.DefaultIntegrityCheckDepth, "number of last blocks checked for corruption on startup, truncating the chain below corrupted ones (0: no check)")
+	// Storage guard of the shard chain databases
+	storageMinFree    = flag.Uint("storage_min_free", 0, "free megabytes of the database volumes under which the node is degraded, pausing the explorer dumps (default: 0, no storage guard)")
+	storageFloor      = flag.Uint("storage_floor", 0, "free megabytes of the database volume under which the node refuses to start (default: 0, no floor)")
+	storageMaxLatency = flag.String("storage_max_write_latency", "0s", "latency of a synced write to the database volumes above which the node is degraded, ex: 500ms; 0 for no latency check")
+	// Archival providers the sync falls back to
+	archivalProviders = flag.String("archival_providers", "", "comma separated https URLs of archival providers to fetch the blocks from when the p2p sync makes no progress")
+	archivalFallback  = flag.String("archival_fallback_after", "10m", "time without p2p sync progress after which the blocks are fetched from the archival providers")
+	archivalServe     = flag.String("archival_serve", "", "what address and port to serve the blocks of the node on as an archival provider (default: none)")
+	// Clock skew to the sync peers above which the node refuses to lead
+	maxClockSkew = flag.String("max_clock_skew", "2s", "skew of the local clock to the median clock of the sync peers above which the node warns and refuses to lead, ex: 500ms; 0 for no limit")
+	// Declarative node configuration, the flags given overriding its keys
+	configFile = flag.String("config", "", "path to a YAML or TOML node configuration file, its keys overridden by the HMY_<SECTION>_<KEY> environment variables and the flags given")
+	// Block limits of the blocks proposed, overriding the sharding schedule's
+	blockGasFloor = flag.Uint("block_gas_floor", 0, "gas limit the proposed blocks trend to when not full (default: 0, from the sharding schedule)")
+	blockGasCeil  = flag.Uint("block_gas_ceil", 0, "gas limit the proposed blocks trend to when full (default: 0, from the sharding schedule)")
+	maxBlockBytes = flag.Uint("max_block_bytes", 0, "largest encoded size of the proposed blocks (default: 0, from the sharding schedule)")
+	// Transactions executed at once when proposing a block
+	txParallelism = flag.Uint("tx_parallelism", 0, "transactions of distinct accounts executed at once when proposing a block (default: 0, serial execution)")
+	// Authentication of the sync peers by committee key
+	syncAuth     = flag.String("sync_auth", downloader.AuthNone, "policy of the sync server for the peers not authenticated by a committee key: none, ratelimit or require")
+	syncAnonRate = flag.Uint("sync_anon_rate", 20, "sync requests per second answered to a host not authenticated under the ratelimit policy")
+	// Chain dumps exported and imported through the API
+	chainDumpDir = flag.String("chain_dump_dir", "", "directory of the chain dumps exported and imported through the debug API (default: none, disabled)")
+	// Fork schedule overriding the built-in fork epochs of a test network
+	forkSchedule        = flag.String("fork_schedule", "", "path to a signed JSON fork schedule overriding the fork epochs of the network")
+	forkScheduleSigners = flag.String("fork_schedule_signers", "", "comma separated addresses trusted to sign fork schedules")
 	// aws credentials
 	awsSettingString = ""
 )
 
 func initSetup() {
 
-	// Setup pprof
+	// Setup pprof and the analysis timers
 	if addr := *pprof; addr != "" {
+		utils.EnableAnalysisTimers()
+		http.Handle("/debug/timers", utils.AnalysisHandler())
 		go func() { http.ListenAndServe(addr, nil) }()
 	}
 
@@ -136,7 +217,15 @@ func initSetup() {
 	// Configure log parameters
 	utils.SetLogContext(*port, *ip)
 	utils.SetLogVerbosity(log.Lvl(*verbosity))
-	utils.AddLogFile(fmt.Sprintf("%v/validator-%v-%v.log", *logFolder, *ip, *port), *logMaxSize)
+	maxAge, err := time.ParseDuration(*logMaxAge)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid log_max_age %q: %s\n", *logMaxAge, err)
+		os.Exit(1)
+	}
+	utils.AddRotatingLogFile(
+		fmt.Sprintf("%v/validator-%v-%v.log", *logFolder, *ip, *port),
+		utils.LogRotation{MaxSize: *logMaxSize, MaxAge: maxAge, MaxBackups: *logBackups},
+	)
 
 	if *onlyLogTps {
 		matchFilterHandler := log.MatchFilterHandler("msg", "TPS Report", utils.GetLogInstance().GetHandler())
@@ -378,10 +467,13 @@ func createGlobalConfig() (*nodeconfig.ConfigType, error) {
 		initialAccounts = append(initialAccounts, &genesis.DeployAccount{ShardID: uint32(*shardID)})
 	}
 	nodeConfig := nodeconfig.GetShardConfig(initialAccounts[0].ShardID)
-	if *nodeType == "validator" {
+	switch *nodeType {
+	case "validator":
 		// Set up consensus keys.
 		setupConsensusKey(nodeConfig)
-	} else {
+	case "rpc":
+		// an RPC node runs no consensus and loads no key
+	default:
 		// set dummy bls key for consensus object
 		nodeConfig.ConsensusPriKey = multibls.GetPrivateKey(&bls.SecretKey{})
 		nodeConfig.ConsensusPubKey = multibls.GetPublicKey(&bls.PublicKey{})
@@ -393,19 +485,36 @@ func createGlobalConfig() (*nodeconfig.ConfigType, error) {
 	nodeConfig.SetArchival(*isArchival)
 
 	// P2P private key is used for secure message transfer between p2p nodes.
-	nodeConfig.P2PPriKey, _, err = utils.LoadKeyFromFile(*keyFile)
+	nodeConfig.P2PKeyFile = p2pKeyFile()
+	nodeConfig.P2PPriKey, _, err = utils.LoadKeyFromFile(nodeConfig.P2PKeyFile)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot load or create P2P key at %#v",
-			*keyFile)
+			nodeConfig.P2PKeyFile)
 	}
 
-	selfPeer := p2p.Peer{
-		IP:              *ip,
-		Port:            *port,
-		ConsensusPubKey: nodeConfig.ConsensusPubKey.PublicKey[0],
+	selfPeer := p2p.Peer{IP: *ip, Port: *port}
+	if nodeConfig.ConsensusPubKey != nil {
+		selfPeer.ConsensusPubKey = nodeConfig.ConsensusPubKey.PublicKey[0]
 	}
 
-	myHost, err = p2p.NewHost(&selfPeer, nodeConfig.P2PPriKey)
+	if *advertiseAddrs != "" {
+		nodeConfig.AdvertiseAddrs = strings.Split(*advertiseAddrs, ",")
+	}
+	nodeConfig.SuppressPrivateAddrs = *suppressPrivate
+	external, err := p2p.ParseMultiaddrs(nodeConfig.AdvertiseAddrs)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse the advertised addresses")
+	}
+
+	var hostOpts []libp2p.Option
+	if *sentries != "" {
+		hostOpts = append(hostOpts, p2p.SuppressAddrs())
+	} else if len(external) > 0 || nodeConfig.SuppressPrivateAddrs {
+		// the peers pinged dial the advertised addresses instead of IP and port
+		selfPeer.Addrs = external
+		hostOpts = append(hostOpts, p2p.AdvertiseAddrs(external, nodeConfig.SuppressPrivateAddrs))
+	}
+	myHost, err = p2p.NewHost(&selfPeer, nodeConfig.P2PPriKey, hostOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot create P2P network host")
 	}
@@ -426,8 +535,56 @@ func createGlobalConfig() (*nodeconfig.ConfigType, error) {
 	return nodeConfig, nil
 }
 
-func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
-	// Consensus object.
+// legacyKeyFile is where the P2P key was persisted before it moved under the
+// data directory.
+const legacyKeyFile = "./.hmykey"
+
+// p2pKeyFile returns the file the P2P identity key is persisted in. Unless
+// given by -key, the key lives under the data directory; a key left in the
+// working directory by older versions is moved there so the identity is kept.
+func p2pKeyFile() string {
+	if *keyFile != "" {
+		return *keyFile
+	}
+	if *dbDir != "" {
+		if err := os.MkdirAll(*dbDir, 0700); err != nil {
+			utils.Logger().Warn().Err(err).Str("dir", *dbDir).Msg("cannot create data directory")
+		}
+	}
+	path := filepath.Join(*dbDir, node.IdentityKeyFile)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return path
+	}
+	if _, err := os.Stat(legacyKeyFile); err != nil {
+		return path
+	}
+	if err := os.Rename(legacyKeyFile, path); err != nil {
+		utils.Logger().Warn().Err(err).Str("path", path).Msg("cannot move P2P key")
+		return legacyKeyFile
+	}
+	return path
+}
+
+// setupTxPoolLimits applies the transaction pool limits given on the command
+// line; out of bound limits are clamped by the pool.
+func setupTxPoolLimits(txPool *core.TxPool) {
+	lifetime, err := time.ParseDuration(*txPoolLifetime)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid txpool lifetime %#v", *txPoolLifetime)
+		os.Exit(1)
+	}
+	txPool.SetLimits(core.TxPoolLimits{
+		PriceBump:    uint64(*txPoolPriceBump),
+		AccountSlots: uint64(*txPoolAccountSlots),
+		GlobalSlots:  uint64(*txPoolGlobalSlots),
+		AccountQueue: uint64(*txPoolAccountQueue),
+		GlobalQueue:  uint64(*txPoolGlobalQueue),
+		Lifetime:     lifetime,
+	})
+}
+
+// setupConsensus creates the consensus object of the node.
+func setupConsensus(nodeConfig *nodeconfig.ConfigType) *consensus.Consensus {
 	// TODO: consensus object shouldn't start here
 	// TODO(minhdoan): During refactoring, found out that the peers list is actually empty. Need to clean up the logic of consensus later.
 	decider := quorum.NewDecider(quorum.SuperMajorityVote, uint32(*shardID))
@@ -435,14 +592,13 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 	currentConsensus, err := consensus.New(
 		myHost, nodeConfig.ShardID, p2p.Peer{}, nodeConfig.ConsensusPriKey, decider,
 	)
-	currentConsensus.Decider.SetMyPublicKeyProvider(func() (*multibls.PublicKey, error) {
-		return currentConsensus.PubKey, nil
-	})
-
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error :%v \n", err)
 		os.Exit(1)
 	}
+	currentConsensus.Decider.SetMyPublicKeyProvider(func() (*multibls.PublicKey, error) {
+		return currentConsensus.PubKey, nil
+	})
 	commitDelay, err := time.ParseDuration(*delayCommit)
 	if err != nil || commitDelay < 0 {
 		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid commit delay %#v", *delayCommit)
@@ -450,6 +606,122 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 	}
 	currentConsensus.SetCommitDelay(commitDelay)
 	currentConsensus.MinPeers = *minPeers
+	if *viewChangeOperators != "" {
+		operators := []*bls.PublicKey{}
+		for _, hexKey := range strings.Split(*viewChangeOperators, ",") {
+			key := &bls.PublicKey{}
+			if err := key.DeserializeHexStr(hexKey); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid view change operator key %#v", hexKey)
+				os.Exit(1)
+			}
+			operators = append(operators, key)
+		}
+		if err := currentConsensus.SetViewChangeOperators(operators, *viewChangeThreshold); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ERROR %v", err)
+			os.Exit(1)
+		}
+	}
+	setupFBFTLogSpill(currentConsensus, nodeConfig)
+	return currentConsensus
+}
+
+// setupFBFTLogSpill spills the FBFT log beyond its memory bounds to a database
+// under the data directory, emptied as the log of a previous run is useless.
+// The log drops the entries beyond its memory bounds if the database cannot
+// be opened. The database is closed with the log on shutdown.
+func setupFBFTLogSpill(currentConsensus *consensus.Consensus, nodeConfig *nodeconfig.ConfigType) {
+	dir := filepath.Join(nodeConfig.DBDir, fmt.Sprintf("fbft_log_%d", nodeConfig.ShardID))
+	if err := os.RemoveAll(dir); err != nil {
+		utils.Logger().Warn().Err(err).Str("dir", dir).Msg("cannot empty the FBFT log spill database")
+		return
+	}
+	db, err := ethdb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		utils.Logger().Warn().Err(err).Str("dir", dir).Msg("cannot open the FBFT log spill database")
+		return
+	}
+	currentConsensus.FBFTLog.SetSpillStore(db)
+}
+
+func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
+	schedule, baseChainConfig, err := setupForkSchedule()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot apply fork schedule: %s\n", err)
+		os.Exit(1)
+	}
+
+	// Consensus object, none for an RPC node.
+	var currentConsensus *consensus.Consensus
+	if *nodeType != "rpc" {
+		currentConsensus = setupConsensus(nodeConfig)
+	}
+
+	idleTimeout, err := time.ParseDuration(*shardChainIdleTimeout)
+	if err != nil || idleTimeout < 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid shard chain idle timeout %#v", *shardChainIdleTimeout)
+		os.Exit(1)
+	}
+	nodeConfig.ShardChainIdleTimeout = idleTimeout
+
+	pruneBudget, err := time.ParseDuration(*statePruneBudget)
+	if err != nil || pruneBudget <= 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid state prune budget %#v", *statePruneBudget)
+		os.Exit(1)
+	}
+	nodeConfig.StatePruneRetention = uint64(*statePruneRetention)
+	nodeConfig.StatePruneBudget = pruneBudget
+	nodeConfig.IntegrityCheckDepth = uint64(*dbCheckDepth)
+
+	if *blockGasCeil > 0 && *blockGasFloor > *blockGasCeil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR block gas floor %d above the ceil %d", *blockGasFloor, *blockGasCeil)
+		os.Exit(1)
+	}
+	if *maxBlockBytes > 0 && uint64(*maxBlockBytes) <= 2*shardingconfig.MinBlockHeadroom {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR block size limit %d must be above %d bytes", *maxBlockBytes, 2*shardingconfig.MinBlockHeadroom)
+		os.Exit(1)
+	}
+	nodeConfig.BlockLimits = shardingconfig.BlockLimits{
+		GasFloor: uint64(*blockGasFloor),
+		GasCeil:  uint64(*blockGasCeil),
+		MaxBytes: uint64(*maxBlockBytes),
+	}
+	nodeConfig.TxParallelism = int(*txParallelism)
+
+	switch *syncAuth {
+	case downloader.AuthNone, downloader.AuthRateLimit, downloader.AuthRequire:
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid sync authentication policy %#v", *syncAuth)
+		os.Exit(1)
+	}
+	nodeConfig.SyncAuthPolicy = *syncAuth
+	nodeConfig.SyncAnonRate = *syncAnonRate
+	nodeConfig.ChainDumpDir = *chainDumpDir
+
+	if *storageFloor > 0 {
+		if err := storageguard.CheckFloor(nodeConfig.DBDir, uint64(*storageFloor)<<20); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot start: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	storageLatency, err := time.ParseDuration(*storageMaxLatency)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid storage_max_write_latency %#v: %s\n", *storageMaxLatency, err)
+		os.Exit(1)
+	}
+	nodeConfig.StorageMinFree = uint64(*storageMinFree) << 20
+	nodeConfig.StorageMaxWriteLatency = storageLatency
+
+	if *archivalProviders != "" {
+		nodeConfig.ArchivalProviders = strings.Split(*archivalProviders, ",")
+	}
+	if nodeConfig.ArchivalFallbackAfter, err = time.ParseDuration(*archivalFallback); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid archival_fallback_after %#v: %s\n", *archivalFallback, err)
+		os.Exit(1)
+	}
+	if nodeConfig.MaxClockSkew, err = time.ParseDuration(*maxClockSkew); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid max_clock_skew %#v: %s\n", *maxClockSkew, err)
+		os.Exit(1)
+	}
 
 	blacklist, err := setupBlacklist()
 	if err != nil {
@@ -459,7 +731,12 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 	// Current node.
 	chainDBFactory := &shardchain.LDBFactory{RootDir: nodeConfig.DBDir}
 
-	currentNode := node.New(myHost, currentConsensus, chainDBFactory, blacklist, *isArchival)
+	var currentNode *node.Node
+	if currentConsensus != nil {
+		currentNode = node.New(myHost, currentConsensus, chainDBFactory, blacklist, *isArchival)
+	} else {
+		currentNode = node.NewRPCNode(myHost, nodeConfig.ShardID, chainDBFactory, blacklist, *isArchival)
+	}
 
 	switch {
 	case *networkType == nodeconfig.Localnet:
@@ -482,9 +759,51 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 
 	}
 
+	if schedule != nil {
+		head := currentNode.Blockchain().CurrentHeader().Epoch()
+		if err := schedule.CheckPending(&baseChainConfig, head); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot apply fork schedule: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	currentNode.Checkpoint = setupCheckpoint(nodeConfig.ShardID)
+
 	// TODO: refactor the creation of blockchain out of node.New()
-	currentConsensus.ChainReader = currentNode.Blockchain()
+	if currentConsensus != nil {
+		currentConsensus.ChainReader = currentNode.Blockchain()
+	}
+	setupTxPoolLimits(currentNode.TxPool)
 	currentNode.NodeConfig.DNSZone = *dnsZone
+	currentNode.NodeConfig.DNSSeed = *dnsSeed
+	txBroadcast, err := nodeconfig.ParseTxBroadcastConfig(
+		nodeconfig.NetworkType(*networkType), *txBroadcastClients, *txFanout,
+	)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid transaction broadcast: %s\n", err)
+		os.Exit(1)
+	}
+	currentNode.NodeConfig.TxBroadcast = txBroadcast
+	if *staticPeers != "" {
+		currentNode.NodeConfig.StaticPeers = strings.Split(*staticPeers, ",")
+	}
+	if *trustPeers != "" {
+		currentNode.NodeConfig.TrustedPeers = strings.Split(*trustPeers, ",")
+	}
+	if err := currentNode.PinPeers(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot pin peers: %s\n", err)
+		os.Exit(1)
+	}
+	if *sentries != "" {
+		currentNode.NodeConfig.Sentries = strings.Split(*sentries, ",")
+	}
+	if *sentryFor != "" {
+		currentNode.NodeConfig.SentryFor = strings.Split(*sentryFor, ",")
+	}
+	if err := currentNode.SetupSentry(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot set up sentry: %s\n", err)
+		os.Exit(1)
+	}
 
 	currentNode.NodeConfig.SetBeaconGroupID(
 		nodeconfig.NewGroupIDByShardID(shard.BeaconChainShardID),
@@ -501,6 +820,14 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 		currentNode.NodeConfig.SetClientGroupID(
 			nodeconfig.NewClientGroupIDByShardID(nodeconfig.ShardID(*shardID)),
 		)
+	case "rpc":
+		nodeconfig.SetDefaultRole(nodeconfig.RPCNode)
+		currentNode.NodeConfig.SetShardGroupID(
+			nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(nodeConfig.ShardID)),
+		)
+		currentNode.NodeConfig.SetClientGroupID(
+			nodeconfig.NewClientGroupIDByShardID(nodeconfig.ShardID(nodeConfig.ShardID)),
+		)
 	case "validator":
 		nodeconfig.SetDefaultRole(nodeconfig.Validator)
 		currentNode.NodeConfig.SetRole(nodeconfig.Validator)
@@ -515,6 +842,35 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 	currentNode.NodeConfig.ConsensusPubKey = nodeConfig.ConsensusPubKey
 	currentNode.NodeConfig.ConsensusPriKey = nodeConfig.ConsensusPriKey
 
+	if currentConsensus != nil {
+		setupNodeConsensus(currentNode, currentConsensus)
+	}
+	if *rewardIndex {
+		currentNode.Beaconchain().EnableRewardIndex()
+	}
+	if *diagnoseBadBlocks {
+		currentNode.Blockchain().EnableBadBlockDiagnosis()
+	}
+	if *cxDeliveryTracking {
+		currentNode.EnableCXDeliveryTracking()
+	}
+	beaconInterval, err := time.ParseDuration(*headBeaconInterval)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR invalid head_beacon_interval %#v: %s\n", *headBeaconInterval, err)
+		os.Exit(1)
+	}
+	if beaconInterval > 0 {
+		currentNode.EnableHeadBeacons(beaconInterval)
+	}
+	if *headCollector {
+		currentNode.EnableHeadCollector()
+	}
+	currentNode.State = node.NodeWaitToJoin
+	return currentNode
+}
+
+// setupNodeConsensus ties the consensus to the node and its chain.
+func setupNodeConsensus(currentNode *node.Node, currentConsensus *consensus.Consensus) {
 	// This needs to be executed after consensus setup
 	if err := currentNode.InitConsensusWithValidators(); err != nil {
 		utils.Logger().Warn().
@@ -531,15 +887,15 @@ func setupConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 		Msg("Init Blockchain")
 
 	// Assign closure functions to the consensus object
-	currentConsensus.BlockVerifier = currentNode.VerifyNewBlock
+	currentConsensus.BlockVerifier = currentNode
 	currentConsensus.OnConsensusDone = currentNode.PostConsensusProcessing
-	currentNode.State = node.NodeWaitToJoin
+	if *voteLedgerRetention > 0 {
+		currentConsensus.VoteLedger = ledger.New(currentNode.Blockchain().ChainDb(), uint64(*voteLedgerRetention))
+	}
 	// update consensus information based on the blockchain
 	currentConsensus.SetMode(currentConsensus.UpdateConsensusInformation())
 	// Setup block period and block due time.
 	currentConsensus.BlockPeriod = time.Duration(*blockPeriod) * time.Second
-	currentConsensus.NextBlockDue = time.Now()
-	return currentNode
 }
 
 func setupBlacklist() (map[ethCommon.Address]struct{}, error) {
@@ -562,6 +918,107 @@ func setupBlacklist() (map[ethCommon.Address]struct{}, error) {
 	return addrMap, nil
 }
 
+// setupCheckpoint returns the latest trusted checkpoint of the shard among the
+// built-in ones and the ones fetched from -checkpoint_url and -checkpoint_dns.
+func setupCheckpoint(shardID uint32) *checkpoint.Checkpoint {
+	netType := nodeconfig.NetworkType(*networkType)
+	checkpoints := append([]checkpoint.Checkpoint{}, checkpoint.Builtin[netType]...)
+	if *checkpointURL != "" {
+		fetched, err := checkpoint.FetchHTTPS(*checkpointURL)
+		if err != nil {
+			utils.Logger().Warn().Err(err).Str("url", *checkpointURL).Msg("cannot fetch checkpoints")
+		}
+		checkpoints = append(checkpoints, fetched...)
+	}
+	if *checkpointDNS != "" {
+		fetched, err := checkpoint.FetchDNS(*checkpointDNS)
+		if err != nil {
+			utils.Logger().Warn().Err(err).Str("name", *checkpointDNS).Msg("cannot fetch checkpoints")
+		}
+		checkpoints = append(checkpoints, fetched...)
+	}
+	trusted := append([]ethCommon.Address{}, checkpoint.TrustedSigners[netType]...)
+	for _, signer := range strings.Split(*checkpointSigners, ",") {
+		if signer = strings.TrimSpace(signer); signer != "" {
+			trusted = append(trusted, common.ParseAddr(signer))
+		}
+	}
+	cp := checkpoint.Latest(checkpoints, shardID, trusted)
+	if cp != nil {
+		utils.Logger().Info().
+			Uint64("epoch", cp.Epoch).
+			Uint64("blockNumber", cp.BlockNumber).
+			Msg("Using trusted checkpoint")
+	}
+	return cp
+}
+
+// setupGenesisSpec loads the genesis spec of the data directory, if any, and
+// launches the private network of the spec in place of the network type: its
+// shards, genesis committees and chain config.
+func setupGenesisSpec() error {
+	spec, err := genesis.LoadSpec(*dbDir)
+	if err != nil || spec == nil {
+		return err
+	}
+	netType := nodeconfig.NetworkType(*networkType)
+	if err := netType.ApplyGenesisSpec(spec); err != nil {
+		return err
+	}
+	instance, err := shardingconfig.NewInstance(
+		spec.NumShards, spec.NumNodesPerShard(), spec.NumNodesPerShard(), numeric.OneDec(),
+		spec.HmyAccounts(), nil, nil, spec.BlocksPerEpoch,
+	)
+	if err != nil {
+		return err
+	}
+	shard.Schedule = shardingconfig.NewFixedSchedule(instance)
+	nodeconfig.SetGenesisSpec(spec)
+	chainConfig := netType.ChainConfig()
+	utils.Logger().Info().
+		Uint32("numShards", spec.NumShards).
+		Int("numNodesPerShard", spec.NumNodesPerShard()).
+		Str("chainConfig", chainConfig.String()).
+		Msg("Applied genesis spec")
+	return nil
+}
+
+// setupForkSchedule loads the fork schedule, if any, and reschedules the forks
+// of the network with it. It returns the chain config as it was before.
+func setupForkSchedule() (*params.ForkSchedule, params.ChainConfig, error) {
+	netType := nodeconfig.NetworkType(*networkType)
+	if *forkSchedule == "" {
+		return nil, netType.ChainConfig(), nil
+	}
+	data, err := ioutil.ReadFile(*forkSchedule)
+	if err != nil {
+		return nil, params.ChainConfig{}, err
+	}
+	schedule := &params.ForkSchedule{}
+	if err := json.Unmarshal(data, schedule); err != nil {
+		return nil, params.ChainConfig{}, errors.Wrapf(err, "invalid fork schedule %s", *forkSchedule)
+	}
+	trusted := []ethCommon.Address{}
+	for _, signer := range strings.Split(*forkScheduleSigners, ",") {
+		if signer = strings.TrimSpace(signer); signer != "" {
+			trusted = append(trusted, common.ParseAddr(signer))
+		}
+	}
+	if err := schedule.Verify(trusted); err != nil {
+		return nil, params.ChainConfig{}, err
+	}
+	base, err := netType.ApplyForkSchedule(schedule)
+	if err != nil {
+		return nil, params.ChainConfig{}, err
+	}
+	chainConfig := netType.ChainConfig()
+	utils.Logger().Info().
+		Interface("forks", schedule.Forks).
+		Str("chainConfig", chainConfig.String()).
+		Msg("Applied fork schedule")
+	return schedule, base, nil
+}
+
 func setupViperConfig() {
 	// read from environment
 	envViper := viperconfig.CreateEnvViper()
@@ -571,16 +1028,39 @@ func setupViperConfig() {
 	viperconfig.ResetConfString(port, envViper, configFileViper, "", "port")
 	viperconfig.ResetConfString(logFolder, envViper, configFileViper, "", "log_folder")
 	viperconfig.ResetConfInt(logMaxSize, envViper, configFileViper, "", "log_max_size")
+	viperconfig.ResetConfString(logMaxAge, envViper, configFileViper, "", "log_max_age")
+	viperconfig.ResetConfInt(logBackups, envViper, configFileViper, "", "log_max_backups")
 	viperconfig.ResetConfBool(freshDB, envViper, configFileViper, "", "fresh_db")
 	viperconfig.ResetConfString(pprof, envViper, configFileViper, "", "pprof")
+	viperconfig.ResetConfString(healthz, envViper, configFileViper, "", "healthz")
 	viperconfig.ResetConfBool(versionFlag, envViper, configFileViper, "", "version")
 	viperconfig.ResetConfBool(onlyLogTps, envViper, configFileViper, "", "only_log_tps")
 	viperconfig.ResetConfString(dnsZone, envViper, configFileViper, "", "dns_zone")
 	viperconfig.ResetConfBool(dnsFlag, envViper, configFileViper, "", "dns")
+	viperconfig.ResetConfString(dnsSeed, envViper, configFileViper, "", "dns_seed")
+	viperconfig.ResetConfString(staticPeers, envViper, configFileViper, "", "static_peers")
+	viperconfig.ResetConfString(forkSchedule, envViper, configFileViper, "", "fork_schedule")
+	viperconfig.ResetConfString(forkScheduleSigners, envViper, configFileViper, "", "fork_schedule_signers")
+	viperconfig.ResetConfString(trustPeers, envViper, configFileViper, "", "trusted_peers")
+	viperconfig.ResetConfString(sentries, envViper, configFileViper, "", "sentries")
+	viperconfig.ResetConfString(advertiseAddrs, envViper, configFileViper, "", "advertise_addrs")
+	viperconfig.ResetConfBool(suppressPrivate, envViper, configFileViper, "", "suppress_private_addrs")
+	viperconfig.ResetConfString(sentryFor, envViper, configFileViper, "", "sentry_for")
 	viperconfig.ResetConfInt(minPeers, envViper, configFileViper, "", "min_peers")
+	viperconfig.ResetConfString(viewChangeOperators, envViper, configFileViper, "", "view_change_operators")
+	viperconfig.ResetConfInt(viewChangeThreshold, envViper, configFileViper, "", "view_change_threshold")
 	viperconfig.ResetConfString(keyFile, envViper, configFileViper, "", "key")
 	viperconfig.ResetConfBool(isArchival, envViper, configFileViper, "", "is_archival")
 	viperconfig.ResetConfString(delayCommit, envViper, configFileViper, "", "delay_commit")
+	viperconfig.ResetConfString(shardChainIdleTimeout, envViper, configFileViper, "", "shardchain_idle_timeout")
+	viperconfig.ResetConfUInt(txPoolPriceBump, envViper, configFileViper, "", "txpool_price_bump")
+	viperconfig.ResetConfUInt(txPoolAccountSlots, envViper, configFileViper, "", "txpool_account_slots")
+	viperconfig.ResetConfUInt(txPoolGlobalSlots, envViper, configFileViper, "", "txpool_global_slots")
+	viperconfig.ResetConfUInt(txPoolAccountQueue, envViper, configFileViper, "", "txpool_account_queue")
+	viperconfig.ResetConfUInt(txPoolGlobalQueue, envViper, configFileViper, "", "txpool_global_queue")
+	viperconfig.ResetConfString(txPoolLifetime, envViper, configFileViper, "", "txpool_lifetime")
+	viperconfig.ResetConfString(txBroadcastClients, envViper, configFileViper, "", "tx_broadcast_clients")
+	viperconfig.ResetConfString(txFanout, envViper, configFileViper, "", "tx_fanout")
 	viperconfig.ResetConfString(nodeType, envViper, configFileViper, "", "node_type")
 	viperconfig.ResetConfString(networkType, envViper, configFileViper, "", "network_type")
 	viperconfig.ResetConfInt(blockPeriod, envViper, configFileViper, "", "block_period")
@@ -590,6 +1070,29 @@ func setupViperConfig() {
 	viperconfig.ResetConfString(blsFolder, envViper, configFileViper, "", "blsfolder")
 	viperconfig.ResetConfString(blsPass, envViper, configFileViper, "", "blsPass")
 	viperconfig.ResetConfUInt(devnetNumShards, envViper, configFileViper, "", "dn_num_shards")
+	viperconfig.ResetConfUInt(voteLedgerRetention, envViper, configFileViper, "", "vote_ledger_retention")
+	viperconfig.ResetConfBool(rewardIndex, envViper, configFileViper, "", "reward_index")
+	viperconfig.ResetConfBool(diagnoseBadBlocks, envViper, configFileViper, "", "diagnose_bad_blocks")
+	viperconfig.ResetConfBool(cxDeliveryTracking, envViper, configFileViper, "", "cx_delivery_tracking")
+	viperconfig.ResetConfString(headBeaconInterval, envViper, configFileViper, "", "head_beacon_interval")
+	viperconfig.ResetConfBool(headCollector, envViper, configFileViper, "", "head_collector")
+	viperconfig.ResetConfUInt(statePruneRetention, envViper, configFileViper, "", "state_prune_retention")
+	viperconfig.ResetConfUInt(storageMinFree, envViper, configFileViper, "", "storage_min_free")
+	viperconfig.ResetConfUInt(storageFloor, envViper, configFileViper, "", "storage_floor")
+	viperconfig.ResetConfString(storageMaxLatency, envViper, configFileViper, "", "storage_max_write_latency")
+	viperconfig.ResetConfString(archivalProviders, envViper, configFileViper, "", "archival_providers")
+	viperconfig.ResetConfString(archivalFallback, envViper, configFileViper, "", "archival_fallback_after")
+	viperconfig.ResetConfString(archivalServe, envViper, configFileViper, "", "archival_serve")
+	viperconfig.ResetConfString(maxClockSkew, envViper, configFileViper, "", "max_clock_skew")
+	viperconfig.ResetConfString(statePruneBudget, envViper, configFileViper, "", "state_prune_budget")
+	viperconfig.ResetConfUInt(dbCheckDepth, envViper, configFileViper, "", "db_check_depth")
+	viperconfig.ResetConfUInt(blockGasFloor, envViper, configFileViper, "", "block_gas_floor")
+	viperconfig.ResetConfUInt(blockGasCeil, envViper, configFileViper, "", "block_gas_ceil")
+	viperconfig.ResetConfUInt(maxBlockBytes, envViper, configFileViper, "", "max_block_bytes")
+	viperconfig.ResetConfUInt(txParallelism, envViper, configFileViper, "", "tx_parallelism")
+	viperconfig.ResetConfString(syncAuth, envViper, configFileViper, "", "sync_auth")
+	viperconfig.ResetConfUInt(syncAnonRate, envViper, configFileViper, "", "sync_anon_rate")
+	viperconfig.ResetConfString(chainDumpDir, envViper, configFileViper, "", "chain_dump_dir")
 	viperconfig.ResetConfInt(devnetShardSize, envViper, configFileViper, "", "dn_shard_size")
 	viperconfig.ResetConfInt(devnetHarmonySize, envViper, configFileViper, "", "dn_hmy_size")
 	viperconfig.ResetConfInt(verbosity, envViper, configFileViper, "", "verbosity")
@@ -600,6 +1103,42 @@ func setupViperConfig() {
 	viperconfig.ResetConfBool(revertBeacon, envViper, configFileViper, "", "revert_beacon")
 	viperconfig.ResetConfString(blacklistPath, envViper, configFileViper, "", "blacklist")
 	viperconfig.ResetConfString(webHookYamlPath, envViper, configFileViper, "", "webhook_yaml")
+	viperconfig.ResetConfString(checkpointURL, envViper, configFileViper, "", "checkpoint_url")
+	viperconfig.ResetConfString(checkpointDNS, envViper, configFileViper, "", "checkpoint_dns")
+	viperconfig.ResetConfString(checkpointSigners, envViper, configFileViper, "", "checkpoint_signers")
+}
+
+// applyConfigFile sets the flags not given on the command line to the values
+// of the configuration file
+func applyConfigFile(path string) error {
+	cfg, err := nodeconfig.LoadFileConfig(path)
+	if err != nil {
+		return err
+	}
+	given := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for name, value := range cfg.FlagValues() {
+		if given[name] || value == "" {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return errors.Wrapf(err, "cannot set %s", name)
+		}
+	}
+	return nil
+}
+
+// setEffectiveConfig records the configuration the node runs with, once the
+// flags, environment variables and configuration file are applied
+func setEffectiveConfig() {
+	values := map[string]string{}
+	flag.VisitAll(func(f *flag.Flag) { values[f.Name] = f.Value.String() })
+	cfg, err := nodeconfig.FileConfigFromFlagValues(values)
+	if err != nil {
+		utils.Logger().Warn().Err(err).Msg("cannot record the effective configuration")
+		return
+	}
+	nodeconfig.SetEffectiveConfig(cfg)
 }
 
 func main() {
@@ -611,9 +1150,16 @@ func main() {
 	flag.Var(&p2p.BootNodes, "bootnodes", "a list of bootnode multiaddress (delimited by ,)")
 	flag.Parse()
 
+	if *configFile != "" {
+		if err := applyConfigFile(*configFile); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot apply config file: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	switch *nodeType {
 	case "validator":
-	case "explorer":
+	case "explorer", "rpc":
 		break
 	default:
 		_, _ = fmt.Fprintf(os.Stderr, "Unknown node type: %s\n", *nodeType)
@@ -662,8 +1208,15 @@ func main() {
 
 	setupViperConfig()
 
+	if err := setupGenesisSpec(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot apply genesis spec: %s\n", err)
+		os.Exit(1)
+	}
+
 	initSetup()
 
+	setEffectiveConfig()
+
 	if *nodeType == "validator" {
 		var err error
 		if *stakingFlag {
@@ -742,8 +1295,11 @@ func main() {
 	}
 
 	startMsg := "==== New Harmony Node ===="
-	if *nodeType == "explorer" {
+	switch *nodeType {
+	case "explorer":
 		startMsg = "==== New Explorer Node ===="
+	case "rpc":
+		startMsg = "==== New RPC Node ===="
 	}
 
 	utils.Logger().Info().
@@ -761,6 +1317,18 @@ func main() {
 	go currentNode.SupportSyncing()
 	currentNode.ServiceManagerSetup()
 	currentNode.RunServices()
+	currentNode.SuperviseServices()
+	if addr := *healthz; addr != "" {
+		if err := currentNode.ServeHealthz(addr); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ERROR cannot serve /healthz on %s: %s\n", addr, err)
+			os.Exit(1)
+		}
+	}
+	if addr := *archivalServe; addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(syncing.ArchivalBlocksPath, currentNode.ArchivalHandler())
+		go func() { http.ListenAndServe(addr, mux) }()
+	}
 	// RPC for SDK not supported for mainnet.
 	if err := currentNode.StartRPC(*port); err != nil {
 		utils.Logger().Warn().
@@ -0,0 +1,55 @@
+package consensus
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/chain"
+	"github.com/pkg/errors"
+)
+
+var (
+	verifyHeaderTimer = metrics.NewRegisteredTimer("consensus/verify/header", nil)
+	verifyBodyTimer   = metrics.NewRegisteredTimer("consensus/verify/body", nil)
+	verifyStateTimer  = metrics.NewRegisteredTimer("consensus/verify/state", nil)
+)
+
+// BlockVerifier verifies the blocks to commit in stages, each stage assuming
+// the block passed the previous ones
+type BlockVerifier interface {
+	// VerifyBlockHeader verifies the header of the block, including the
+	// commit signature of the parent it holds
+	VerifyBlockHeader(block *types.Block) error
+	// VerifyBlockBody verifies the content of the block against its header
+	VerifyBlockBody(block *types.Block) error
+	// VerifyBlockState verifies the state transition of the block against
+	// the roots of its header
+	VerifyBlockState(block *types.Block) error
+}
+
+// verifyBlock runs each stage of the block verification once, in order. With
+// no block verifier, only the header is verified.
+func (consensus *Consensus) verifyBlock(block *types.Block) error {
+	if consensus.BlockVerifier == nil {
+		return chain.Engine.VerifyHeader(consensus.ChainReader, block.Header(), true)
+	}
+	stages := []struct {
+		name   string
+		verify func(*types.Block) error
+		timer  metrics.Timer
+	}{
+		{"header", consensus.BlockVerifier.VerifyBlockHeader, verifyHeaderTimer},
+		{"body", consensus.BlockVerifier.VerifyBlockBody, verifyBodyTimer},
+		{"state", consensus.BlockVerifier.VerifyBlockState, verifyStateTimer},
+	}
+	for _, stage := range stages {
+		start := time.Now()
+		err := stage.verify(block)
+		stage.timer.UpdateSince(start)
+		if err != nil {
+			return errors.Wrapf(err, "block %s verification failed", stage.name)
+		}
+	}
+	return nil
+}
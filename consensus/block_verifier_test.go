@@ -0,0 +1,75 @@
+package consensus
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/consensus/engine"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// stagesVerifier records the stages it runs, failing the given one
+type stagesVerifier struct {
+	failing string
+	err     error
+	stages  []string
+}
+
+func (v *stagesVerifier) run(stage string) error {
+	v.stages = append(v.stages, stage)
+	if stage == v.failing {
+		return v.err
+	}
+	return nil
+}
+
+func (v *stagesVerifier) VerifyBlockHeader(*types.Block) error { return v.run("header") }
+func (v *stagesVerifier) VerifyBlockBody(*types.Block) error   { return v.run("body") }
+func (v *stagesVerifier) VerifyBlockState(*types.Block) error  { return v.run("state") }
+
+func TestVerifyBlock(t *testing.T) {
+	errStage := errors.New("stage failed")
+	block := types.NewBlockWithHeader(blockfactory.ForTest.NewHeader(big.NewInt(0)))
+	for _, test := range []struct {
+		name     string
+		failing  string
+		expected []string
+	}{
+		{"all stages pass", "", []string{"header", "body", "state"}},
+		{"header fails", "header", []string{"header"}},
+		{"body fails", "body", []string{"header", "body"}},
+		{"state fails", "state", []string{"header", "body", "state"}},
+	} {
+		verifier := &stagesVerifier{failing: test.failing, err: errStage}
+		consensus := &Consensus{BlockVerifier: verifier}
+		err := consensus.verifyBlock(block)
+		if !reflect.DeepEqual(verifier.stages, test.expected) {
+			t.Errorf("%s: expected stages %v, got %v", test.name, test.expected, verifier.stages)
+		}
+		if test.failing == "" && err != nil {
+			t.Errorf("%s: expected no error, got %v", test.name, err)
+		}
+		if test.failing != "" && errors.Cause(err) != errStage {
+			t.Errorf("%s: expected %v, got %v", test.name, errStage, err)
+		}
+	}
+}
+
+func TestVerifyBlockWithoutVerifier(t *testing.T) {
+	chain := newTestChain(t, params.TestChainConfig, shard.State{})
+	defer chain.Stop()
+	consensus := &Consensus{ChainReader: chain}
+
+	// without block verifier, the header is verified by the engine, which
+	// rejects the block of an unknown parent
+	header := blockfactory.ForTest.NewHeader(big.NewInt(0))
+	header.SetNumber(big.NewInt(1))
+	if err := consensus.verifyBlock(types.NewBlockWithHeader(header)); err != engine.ErrUnknownAncestor {
+		t.Errorf("expected %v, got %v", engine.ErrUnknownAncestor, err)
+	}
+}
@@ -0,0 +1,124 @@
+// +build byzantine
+
+package consensus
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/api/proto"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/consensus/signature"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// ByzantineConfig are the faults a node built with the byzantine tag
+// injects in its consensus, to test view changes and slashing
+type ByzantineConfig struct {
+	// Equivocate also signs a conflicting block besides each prepare and
+	// commit sent
+	Equivocate bool
+	// WithholdCommits never sends the commits
+	WithholdCommits bool
+	// Delay holds each message sent for this long
+	Delay time.Duration
+	// StaleViews is subtracted from the view ID of the messages sent
+	StaleViews uint64
+}
+
+// byzantineConfigs are the faults injected, keyed by the consensus and by its
+// message sender
+var byzantineConfigs sync.Map
+
+// SetByzantine makes the consensus inject the faults of the config, the zero
+// config restoring an honest node
+func (consensus *Consensus) SetByzantine(config ByzantineConfig) {
+	byzantineConfigs.Store(consensus, config)
+	byzantineConfigs.Store(consensus.msgSender, config)
+}
+
+// Byzantine returns the faults the consensus injects
+func (consensus *Consensus) Byzantine() ByzantineConfig {
+	return loadByzantine(consensus)
+}
+
+func loadByzantine(key interface{}) ByzantineConfig {
+	if config, ok := byzantineConfigs.Load(key); ok {
+		return config.(ByzantineConfig)
+	}
+	return ByzantineConfig{}
+}
+
+// withholdCommit tells whether to drop the commits
+func (consensus *Consensus) withholdCommit() bool {
+	return loadByzantine(consensus).WithholdCommits
+}
+
+// outboundViewID returns the view ID put in the messages sent
+func (consensus *Consensus) outboundViewID(viewID uint64) uint64 {
+	stale := loadByzantine(consensus).StaleViews
+	if stale > viewID {
+		return 0
+	}
+	return viewID - stale
+}
+
+// equivocate sends a vote of type p, prepare or commit, on a block
+// conflicting with the one under agreement
+func (consensus *Consensus) equivocate(
+	p msg_pb.MessageType, groups []nodeconfig.GroupID, pubKey *bls.PublicKey, priKey *bls.SecretKey,
+) {
+	if !loadByzantine(consensus).Equivocate {
+		return
+	}
+	conflicting := crypto.Keccak256Hash(consensus.blockHash[:])
+	message := &msg_pb.Message{
+		ServiceType: msg_pb.ServiceType_CONSENSUS,
+		Type:        p,
+		Request: &msg_pb.Message_Consensus{
+			Consensus: &msg_pb.ConsensusRequest{},
+		},
+	}
+	request := consensus.populateMessageFields(message.GetConsensus(), conflicting[:], pubKey)
+	switch p {
+	case msg_pb.MessageType_PREPARE:
+		request.Payload = priKey.SignHash(conflicting[:]).Serialize()
+	case msg_pb.MessageType_COMMIT:
+		payload := signature.ConstructCommitPayload(consensus.ChainReader,
+			new(big.Int).SetUint64(consensus.epoch), conflicting, request.BlockNum, request.ViewId)
+		request.Payload = priKey.SignHash(payload).Serialize()
+	default:
+		return
+	}
+	marshaled, err := consensus.signAndMarshalConsensusMessage(message, priKey)
+	if err != nil {
+		consensus.getLogger().Err(err).Msg("[Byzantine] cannot marshal the conflicting vote")
+		return
+	}
+	if err := consensus.msgSender.SendWithoutRetry(
+		groups, p2p.ConstructMessage(proto.ConstructConsensusMessage(marshaled)),
+	); err != nil {
+		consensus.getLogger().Warn().Err(err).Msg("[Byzantine] cannot send the conflicting vote")
+		return
+	}
+	consensus.getLogger().Info().
+		Str("phase", p.String()).
+		Hex("blockHash", conflicting[:]).
+		Msg("[Byzantine] sent a conflicting vote")
+}
+
+// send sends the message to the groups, once the delay injected elapsed
+func (sender *MessageSender) send(groups []nodeconfig.GroupID, p2pMsg []byte) error {
+	delay := loadByzantine(sender).Delay
+	if delay <= 0 {
+		return sender.host.SendMessageToGroups(groups, p2pMsg)
+	}
+	time.AfterFunc(delay, func() {
+		sender.host.SendMessageToGroups(groups, p2pMsg)
+	})
+	return nil
+}
@@ -0,0 +1,29 @@
+// +build !byzantine
+
+package consensus
+
+import (
+	"github.com/harmony-one/bls/ffi/go/bls"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+)
+
+// The fault injection hooks of an honest node; see byzantine.go for those of
+// a node built with the byzantine tag.
+
+func (consensus *Consensus) withholdCommit() bool {
+	return false
+}
+
+func (consensus *Consensus) outboundViewID(viewID uint64) uint64 {
+	return viewID
+}
+
+func (consensus *Consensus) equivocate(
+	p msg_pb.MessageType, groups []nodeconfig.GroupID, pubKey *bls.PublicKey, priKey *bls.SecretKey,
+) {
+}
+
+func (sender *MessageSender) send(groups []nodeconfig.GroupID, p2pMsg []byte) error {
+	return sender.host.SendMessageToGroups(groups, p2pMsg)
+}
@@ -0,0 +1,57 @@
+// +build byzantine
+
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/consensus/quorum"
+	"github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestByzantineHooks(t *testing.T) {
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9904"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9904")
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := New(
+		host, shard.BeaconChainShardID, leader, multibls.GetPrivateKey(bls.RandPrivateKey()), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	if consensus.withholdCommit() || consensus.outboundViewID(5) != 5 {
+		t.Fatal("faults injected by default")
+	}
+
+	config := ByzantineConfig{WithholdCommits: true, Delay: time.Second, StaleViews: 2}
+	consensus.SetByzantine(config)
+	if consensus.Byzantine() != config {
+		t.Fatalf("got config %+v", consensus.Byzantine())
+	}
+	if !consensus.withholdCommit() {
+		t.Error("commits not withheld")
+	}
+	if id := consensus.outboundViewID(5); id != 3 {
+		t.Errorf("got view ID %d, want 3", id)
+	}
+	if id := consensus.outboundViewID(1); id != 0 {
+		t.Errorf("got view ID %d, want 0", id)
+	}
+	if delay := loadByzantine(consensus.msgSender).Delay; delay != time.Second {
+		t.Errorf("got sender delay %v", delay)
+	}
+
+	consensus.SetByzantine(ByzantineConfig{})
+	if consensus.withholdCommit() || consensus.outboundViewID(5) != 5 {
+		t.Fatal("faults injected after reset")
+	}
+}
@@ -3,7 +3,6 @@ package consensus
 import (
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/core/types"
-	"github.com/harmony-one/harmony/internal/chain"
 	"github.com/harmony-one/harmony/shard"
 )
 
@@ -31,7 +30,7 @@ func (consensus *Consensus) validatorSanityChecks(msg *msg_pb.Message) bool {
 		return false
 	}
 
-	if !senderKey.IsEqual(consensus.LeaderPubKey) &&
+	if !senderKey.IsEqual(consensus.LeaderPubKey()) &&
 		consensus.current.Mode() == Normal && !consensus.ignoreViewIDCheck {
 		consensus.getLogger().Warn().Msgf(
 			"[%s] SenderKey not match leader PubKey",
@@ -40,7 +39,7 @@ func (consensus *Consensus) validatorSanityChecks(msg *msg_pb.Message) bool {
 		return false
 	}
 
-	if err := verifyMessageSig(senderKey, msg); err != nil {
+	if err := consensus.verifyMessageSig(senderKey, msg); err != nil {
 		consensus.getLogger().Error().Err(err).Msg(
 			"Failed to verify sender's signature",
 		)
@@ -75,7 +74,7 @@ func (consensus *Consensus) leaderSanityChecks(msg *msg_pb.Message) bool {
 		}
 		return false
 	}
-	if err = verifyMessageSig(senderKey, msg); err != nil {
+	if err = consensus.verifyMessageSig(senderKey, msg); err != nil {
 		consensus.getLogger().Error().Err(err).Msgf(
 			"[%s] Failed to verify sender's signature",
 			msg.GetType().String(),
@@ -88,11 +87,11 @@ func (consensus *Consensus) leaderSanityChecks(msg *msg_pb.Message) bool {
 
 func (consensus *Consensus) isRightBlockNumAndViewID(recvMsg *FBFTMessage,
 ) bool {
-	if recvMsg.ViewID != consensus.viewID || recvMsg.BlockNum != consensus.blockNum {
+	if recvMsg.ViewID != consensus.GetViewID() || recvMsg.BlockNum != consensus.BlockNum() {
 		consensus.getLogger().Debug().
 			Uint64("MsgViewID", recvMsg.ViewID).
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("blockNum", consensus.BlockNum()).
 			Str("ValidatorPubKey", recvMsg.SenderPubkey.SerializeToHexStr()).
 			Msg("[OnCommit] BlockNum/viewID not match")
 		return false
@@ -114,30 +113,30 @@ func (consensus *Consensus) onAnnounceSanityChecks(recvMsg *FBFTMessage) bool {
 				Uint64("recvMsg.BlockNum", recvMsg.BlockNum).
 				Uint64("recvMsg.ViewID", recvMsg.ViewID).
 				Str("recvMsgBlockHash", recvMsg.BlockHash.Hex()).
-				Str("LeaderKey", consensus.LeaderPubKey.SerializeToHexStr()).
+				Str("LeaderKey", consensus.LeaderPubKey().SerializeToHexStr()).
 				Msg("[OnAnnounce] Leader is malicious")
 			if consensus.current.Mode() == ViewChanging {
 				consensus.getLogger().Debug().Msg(
 					"[OnAnnounce] Already in ViewChanging mode, conflicing announce, doing noop",
 				)
 			} else {
-				consensus.startViewChange(consensus.viewID + 1)
+				consensus.startViewChange(consensus.GetViewID() + 1)
 			}
 		}
 		consensus.getLogger().Debug().
-			Str("leaderKey", consensus.LeaderPubKey.SerializeToHexStr()).
+			Str("leaderKey", consensus.LeaderPubKey().SerializeToHexStr()).
 			Msg("[OnAnnounce] Announce message received again")
 	}
 	return consensus.isRightBlockNumCheck(recvMsg)
 }
 
 func (consensus *Consensus) isRightBlockNumCheck(recvMsg *FBFTMessage) bool {
-	if recvMsg.BlockNum < consensus.blockNum {
+	if recvMsg.BlockNum < consensus.BlockNum() {
 		consensus.getLogger().Debug().
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Msg("Wrong BlockNum Received, ignoring!")
 		return false
-	} else if recvMsg.BlockNum-consensus.blockNum > MaxBlockNumDiff {
+	} else if recvMsg.BlockNum-consensus.BlockNum() > MaxBlockNumDiff {
 		consensus.getLogger().Debug().
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Uint64("MaxBlockNumDiff", MaxBlockNumDiff).
@@ -151,7 +150,7 @@ func (consensus *Consensus) onPreparedSanityChecks(
 	blockObj *types.Block, recvMsg *FBFTMessage,
 ) bool {
 	if blockObj.NumberU64() != recvMsg.BlockNum ||
-		recvMsg.BlockNum < consensus.blockNum {
+		recvMsg.BlockNum < consensus.BlockNum() {
 		consensus.getLogger().Warn().
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Uint64("blockNum", blockObj.NumberU64()).
@@ -167,19 +166,12 @@ func (consensus *Consensus) onPreparedSanityChecks(
 		return false
 	}
 	if consensus.current.Mode() == Normal {
-		err := chain.Engine.VerifyHeader(consensus.ChainReader, blockObj.Header(), true)
-		if err != nil {
+		if err := consensus.verifyBlock(blockObj); err != nil {
 			consensus.getLogger().Error().
 				Err(err).
 				Str("inChain", consensus.ChainReader.CurrentHeader().Number().String()).
 				Str("MsgBlockNum", blockObj.Header().Number().String()).
-				Msg("[OnPrepared] Block header is not verified successfully")
-			return false
-		}
-		if consensus.BlockVerifier == nil {
-			// do nothing
-		} else if err := consensus.BlockVerifier(blockObj); err != nil {
-			consensus.getLogger().Error().Err(err).Msg("[OnPrepared] Block verification failed")
+				Msg("[OnPrepared] Block verification failed")
 			return false
 		}
 	}
@@ -209,7 +201,7 @@ func (consensus *Consensus) viewChangeSanityCheck(msg *msg_pb.Message) bool {
 		}
 		return false
 	}
-	if err := verifyMessageSig(senderKey, msg); err != nil {
+	if err := consensus.verifyMessageSig(senderKey, msg); err != nil {
 		consensus.getLogger().Error().Err(err).Msgf(
 			"[%s] Failed To Verify Sender's Signature",
 			msg.GetType().String(),
@@ -222,13 +214,13 @@ func (consensus *Consensus) viewChangeSanityCheck(msg *msg_pb.Message) bool {
 func (consensus *Consensus) onViewChangeSanityCheck(recvMsg *FBFTMessage) bool {
 	// TODO: if difference is only one, new leader can still propose the same committed block to avoid another view change
 	// TODO: new leader catchup without ignore view change message
-	if consensus.blockNum > recvMsg.BlockNum {
+	if consensus.BlockNum() > recvMsg.BlockNum {
 		consensus.getLogger().Debug().
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Msg("[onViewChange] Message BlockNum Is Low")
 		return false
 	}
-	if consensus.blockNum < recvMsg.BlockNum {
+	if consensus.BlockNum() < recvMsg.BlockNum {
 		consensus.getLogger().Warn().
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Msg("[onViewChange] New Leader Has Lower Blocknum")
@@ -253,9 +245,9 @@ func (consensus *Consensus) onViewChangeSanityCheck(recvMsg *FBFTMessage) bool {
 }
 
 func (consensus *Consensus) onNewViewSanityCheck(recvMsg *FBFTMessage) bool {
-	if recvMsg.ViewID <= consensus.viewID {
+	if recvMsg.ViewID <= consensus.GetViewID() {
 		consensus.getLogger().Warn().
-			Uint64("LastSuccessfulConsensusViewID", consensus.viewID).
+			Uint64("LastSuccessfulConsensusViewID", consensus.GetViewID()).
 			Uint64("MsgViewChangingID", recvMsg.ViewID).
 			Msg("[onNewView] ViewID should be larger than the viewID of the last successful consensus")
 		return false
@@ -13,6 +13,8 @@ const (
 	phaseDuration     time.Duration = 60 * time.Second
 	bootstrapDuration time.Duration = 600 * time.Second
 	maxLogSize        uint32        = 1000
+	// blocks of the FBFT log kept in memory, the others being spilled
+	maxLogBlocks = 64
 	// threshold between received consensus message blockNum and my blockNum
 	consensusBlockNumBuffer uint64 = 2
 )
@@ -3,13 +3,18 @@ package consensus
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/harmony-one/bls/ffi/go/bls"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/consensus/ledger"
 	"github.com/harmony-one/harmony/consensus/quorum"
+	"github.com/harmony-one/harmony/consensus/signature"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/pipe"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/multibls"
 	"github.com/harmony-one/harmony/p2p"
@@ -22,6 +27,20 @@ const (
 	vdfAndSeedSize  = 548 // size of VDF/Proof and Seed
 )
 
+const (
+	commitFinishPipeSize     = 16
+	rndPipeSize              = 4
+	verifiedNewBlockPipeSize = 8
+	pipeSendTimeout          = time.Second
+	// log only one of every tickerLogSampling main loop ticks
+	tickerLogSampling = 20
+	// number of verified consensus signatures remembered
+	sigCacheSize = 4096
+	// prepare and commit votes arriving within voteBatchWindow are verified together
+	voteBatchWindow  = 20 * time.Millisecond
+	voteBatchMaxSize = 256
+)
+
 var errLeaderPriKeyNotFound = errors.New("getting leader private key from consensus public keys failed")
 
 // Consensus is the main struct with all states and data related to consensus process.
@@ -29,21 +48,26 @@ type Consensus struct {
 	Decider quorum.Decider
 	// FBFTLog stores the pbft messages and blocks during FBFT process
 	FBFTLog *FBFTLog
-	// phase: different phase of FBFT protocol: pre-prepare, prepare, commit, finish etc
-	phase FBFTPhase
+	// round: the state of the current round, see roundState
+	round atomic.Value
+	// serializes the updates of round
+	roundMutex sync.Mutex
 	// current indicates what state a node is in
 	current State
 	// epoch: current epoch number
 	epoch uint64
-	// blockNum: the next blockNumber that FBFT is going to agree on,
-	// should be equal to the blockNumber of next block
-	blockNum uint64
-	// channel to receive consensus message
-	MsgChan chan []byte
+	// queues of the received consensus messages, by priority
+	msgQueues [NumMsgPriorities]chan *msg_pb.Message
 	// How long to delay sending commit messages.
 	delayCommit time.Duration
 	// Consensus rounds whose commit phase finished
-	commitFinishChan chan uint64
+	commitFinishChan *pipe.Pipe
+	// Signatures already verified, to skip re-delivered messages
+	sigCache *signature.VerifyCache
+	// Verifies the prepare and commit signatures in batches
+	voteBatcher *voteBatcher
+	// Blocks proposed at the heights not committed yet
+	proposals *proposalCache
 	// 2 types of timeouts: normal and viewchange
 	consensusTimeout map[TimeoutType]*utils.Timeout
 	// Commits collected from validators.
@@ -67,6 +91,10 @@ type Consensus struct {
 	viewIDBitmap map[uint64]*bls_cosi.Mask
 	m1Payload    []byte     // message payload for type m1 := |vcBlockHash|prepared_agg_sigs|prepared_bitmap|, new leader only need one
 	vcLock       sync.Mutex // mutex for view change
+	// approvals of the operators for forcing a view change
+	forcedViewChange forcedViewChange
+	// transitions of the phases, modes and views, fed to the subscribers
+	phaseEvents phaseEvents
 	// The chain reader for the blockchain this consensus is working on
 	ChainReader *core.BlockChain
 	// map of nodeID to validator Peer object
@@ -78,32 +106,55 @@ type Consensus struct {
 	// private/public keys of current node
 	priKey *multibls.PrivateKey
 	PubKey *multibls.PublicKey
-	// the publickey of leader
-	LeaderPubKey *bls.PublicKey
-	viewID       uint64
+	// where the keys of the node serve in the epoch of the committee
+	keyShards keyShards
 	// Blockhash - 32 byte
 	blockHash [32]byte
 	// Block to run consensus on
 	block []byte
 	// BlockHeader to run consensus on
 	blockHeader []byte
+	// Block carried by the PREPARED message of the leader, the header first
+	// proposal of the block in the shards proposing header first
+	preparedBlock []byte
 	// Shard Id which this node belongs to
 	ShardID uint32
 	// whether to ignore viewID check
 	ignoreViewIDCheck bool
-	// global consensus mutex
-	mutex sync.Mutex
-	// consensus information update mutex
-	infoMutex sync.Mutex
+	// Locks, each taken before those following it, never after:
+	//  - vcLock serializes the view change handlers
+	//  - prepareMutex serializes the prepare phase handlers and guards the
+	//    block under agreement, its hash, the prepare bitmap and signature
+	//  - commitMutex serializes the commit phase handlers and guards the
+	//    commit bitmap and signature; the handlers ending the round, which
+	//    reset the state of both phases, take both locks
+	//  - infoMutex guards the consensus information updated on epoch change
+	//  - roundMutex serializes the updates of round
+	// Decider and FBFTLog synchronize themselves with their own locks, taken
+	// last, so they are safe to use from the vote and view change handlers
+	// alike.
+	prepareMutex sync.Mutex
+	commitMutex  sync.Mutex
+	infoMutex    sync.Mutex
 	// Signal channel for starting a new consensus process
 	ReadySignal chan struct{}
 	// The post-consensus processing func passed from Node object
 	// Called when consensus on a new block is done
 	OnConsensusDone func(*types.Block)
-	// The verifier func passed from Node object
-	BlockVerifier func(*types.Block) error
+	// The block verifier passed from Node object
+	BlockVerifier BlockVerifier
+	// The fetcher of the blocks proposed header first passed from Node object
+	FetchProposal ProposalFetcher
+	// blocks fetched for the header first proposals
+	fetched *fetchedProposals
+	// The ledger the votes of the committed rounds are recorded in, if any
+	VoteLedger *ledger.Ledger
+	// the start of the current round, as recorded in the vote ledger
+	voteRound voteRound
+	// statistics of the leaders of the rounds seen
+	leaderTracker *leaderTracker
 	// verified block to state sync broadcast
-	VerifiedNewBlock chan *types.Block
+	VerifiedNewBlock *pipe.Pipe
 	// will trigger state syncing when blockNum is low
 	BlockNumLowChan chan struct{}
 	// Channel for DRG protocol to send pRnd (preimage of randomness resulting from combined vrf
@@ -111,7 +162,7 @@ type Consensus struct {
 	PRndChannel chan []byte
 	// Channel for DRG protocol to send VDF. The first 516 bytes are the VDF/Proof and the last 32
 	// bytes are the seed for deriving VDF
-	RndChannel  chan [vdfAndSeedSize]byte
+	RndChannel  *pipe.Pipe
 	pendingRnds [][vdfAndSeedSize]byte // A list of pending randomness
 	// The p2p host used to send/receive p2p messages
 	host p2p.Host
@@ -127,8 +178,8 @@ type Consensus struct {
 	SlashChan chan slash.Record
 	// How long in second the leader needs to wait to propose a new block.
 	BlockPeriod time.Duration
-	// The time due for next block proposal
-	NextBlockDue time.Time
+	// drives the block proposals, signaled on ReadySignal
+	proposer *proposalController
 }
 
 // SetCommitDelay sets the commit message delay.  If set to non-zero,
@@ -165,7 +216,15 @@ func (consensus *Consensus) GetLeaderPrivateKey(leaderKey *bls.PublicKey) (*bls.
 
 // GetConsensusLeaderPrivateKey returns consensus leader private key if node is the leader
 func (consensus *Consensus) GetConsensusLeaderPrivateKey() (*bls.SecretKey, error) {
-	return consensus.GetLeaderPrivateKey(consensus.LeaderPubKey)
+	return consensus.GetLeaderPrivateKey(consensus.LeaderPubKey())
+}
+
+// GetPrivateKeys returns the BLS private keys of the node
+func (consensus *Consensus) GetPrivateKeys() []*bls.SecretKey {
+	if consensus.priKey == nil {
+		return nil
+	}
+	return consensus.priKey.PrivateKey
 }
 
 // TODO: put shardId into chain reader's chain config
@@ -179,12 +238,16 @@ func New(
 	consensus.Decider = Decider
 	consensus.host = host
 	consensus.msgSender = NewMessageSender(host)
+	consensus.msgSender.viewID = consensus.GetViewID
 	consensus.BlockNumLowChan = make(chan struct{})
 	// FBFT related
 	consensus.FBFTLog = NewFBFTLog()
-	consensus.phase = FBFTAnnounce
+	consensus.round.Store(&roundState{phase: FBFTAnnounce})
 	// TODO Refactor consensus.block* into State?
 	consensus.current = State{mode: Normal}
+	consensus.current.onModeChange = func(from, to Mode) {
+		consensus.emitPhaseEvent(ModeChanged, from.String(), to.String())
+	}
 	// FBFT timeout
 	consensus.consensusTimeout = createTimeout()
 	consensus.validators.Store(leader.ConsensusPubKey.SerializeToHexStr(), leader)
@@ -201,16 +264,36 @@ func New(
 
 	// viewID has to be initialized as the height of
 	// the blockchain during initialization as it was
-	// displayed on explorer as Height right now
-	consensus.viewID = 0
+	// displayed on explorer as Height right now,
+	// which the zero roundState stored above does
 	consensus.ShardID = shard
-	consensus.MsgChan = make(chan []byte)
+	consensus.msgQueues = newMsgQueues()
 	consensus.syncReadyChan = make(chan struct{})
 	consensus.syncNotReadyChan = make(chan struct{})
 	consensus.SlashChan = make(chan slash.Record)
-	consensus.commitFinishChan = make(chan uint64)
-	consensus.ReadySignal = make(chan struct{})
+	consensus.commitFinishChan = pipe.New(pipe.Config{
+		Name:        "consensus/commitFinish",
+		Size:        commitFinishPipeSize,
+		SendTimeout: pipeSendTimeout,
+		Policy:      pipe.DropOldest,
+	})
+	consensus.ReadySignal = make(chan struct{}, 1)
+	consensus.proposer = newProposalController(consensus.ReadySignal)
 	// channel for receiving newly generated VDF
-	consensus.RndChannel = make(chan [vdfAndSeedSize]byte)
+	consensus.RndChannel = pipe.New(pipe.Config{
+		Name:   "consensus/rnd",
+		Size:   rndPipeSize,
+		Policy: pipe.Block,
+	})
+	consensus.VerifiedNewBlock = pipe.New(pipe.Config{
+		Name:   "consensus/verifiedNewBlock",
+		Size:   verifiedNewBlockPipeSize,
+		Policy: pipe.DropOldest,
+	})
+	consensus.sigCache = signature.NewVerifyCache(sigCacheSize)
+	consensus.voteBatcher = newVoteBatcher(voteBatchWindow, voteBatchMaxSize, consensus.sigCache)
+	consensus.proposals = newProposalCache()
+	consensus.fetched = newFetchedProposals()
+	consensus.leaderTracker = newLeaderTracker()
 	return &consensus, nil
 }
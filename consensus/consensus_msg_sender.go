@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/metrics"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/utils"
@@ -13,6 +14,23 @@ import (
 const (
 	// RetryIntervalInSec is the interval for message retry
 	RetryIntervalInSec = 10
+	// outboundQueueSize bounds the messages queued to be resent after their
+	// sending failed
+	outboundQueueSize = 64
+	// outboundMaxAttempts bounds the resends of a message whose sending failed
+	outboundMaxAttempts = 5
+	// outboundBackoff is the pause before the first resend of a message,
+	// doubled at each attempt
+	outboundBackoff = 200 * time.Millisecond
+	// outboundTTL is how long a message is resent for at most
+	outboundTTL = 10 * time.Second
+)
+
+var (
+	outboundFailedCounter  = metrics.NewRegisteredCounter("consensus/outbound/failed", nil)
+	outboundResentCounter  = metrics.NewRegisteredCounter("consensus/outbound/resent", nil)
+	outboundStaleCounter   = metrics.NewRegisteredCounter("consensus/outbound/stale", nil)
+	outboundDroppedCounter = metrics.NewRegisteredCounter("consensus/outbound/dropped", nil)
 )
 
 // MessageSender is the wrapper object that controls how a consensus message is sent
@@ -24,6 +42,24 @@ type MessageSender struct {
 	host p2p.Host
 	// RetryTimes is number of retry attempts
 	retryTimes int
+	// outbound are the messages to resend after their sending failed
+	outbound chan *outboundMessage
+	// viewID returns the view ID of the current round, if set
+	viewID func() uint64
+}
+
+// outboundMessage is a message queued to be resent after its sending failed.
+// It goes stale once the consensus moves to a later block or view, unless it
+// is a committed message, which lives across rounds.
+type outboundMessage struct {
+	groups     []nodeconfig.GroupID
+	p2pMsg     []byte
+	crossRound bool
+	blockNum   uint64
+	viewID     uint64
+	attempts   int
+	next       time.Time
+	expiry     time.Time
 }
 
 // MessageRetry controls the message that can be retried
@@ -39,7 +75,13 @@ type MessageRetry struct {
 
 // NewMessageSender initializes the consensus message sender.
 func NewMessageSender(host p2p.Host) *MessageSender {
-	return &MessageSender{host: host, retryTimes: int(phaseDuration.Seconds()) / RetryIntervalInSec}
+	sender := &MessageSender{
+		host:       host,
+		retryTimes: int(phaseDuration.Seconds()) / RetryIntervalInSec,
+		outbound:   make(chan *outboundMessage, outboundQueueSize),
+	}
+	go sender.resendFailed()
+	return sender
 }
 
 // Reset resets the sender's state for new block
@@ -68,12 +110,94 @@ func (sender *MessageSender) SendWithRetry(blockNum uint64, msgType msg_pb.Messa
 			sender.Retry(&msgRetry)
 		}()
 	}
-	return sender.host.SendMessageToGroups(groups, p2pMsg)
+	return sender.deliver(groups, p2pMsg, msgType == msg_pb.MessageType_COMMITTED)
 }
 
-// SendWithoutRetry sends message without retry logic.
+// SendWithoutRetry sends message without retry logic. The message is still
+// resent if sending it fails.
 func (sender *MessageSender) SendWithoutRetry(groups []nodeconfig.GroupID, p2pMsg []byte) error {
-	return sender.host.SendMessageToGroups(groups, p2pMsg)
+	return sender.deliver(groups, p2pMsg, false)
+}
+
+// deliver sends the message, queueing it to be resent if that fails
+func (sender *MessageSender) deliver(groups []nodeconfig.GroupID, p2pMsg []byte, crossRound bool) error {
+	err := sender.send(groups, p2pMsg)
+	if err == nil {
+		return nil
+	}
+	outboundFailedCounter.Inc(1)
+	now := time.Now()
+	msg := &outboundMessage{
+		groups:     groups,
+		p2pMsg:     p2pMsg,
+		crossRound: crossRound,
+		blockNum:   sender.currentBlockNum(),
+		viewID:     sender.currentViewID(),
+		next:       now.Add(outboundBackoff),
+		expiry:     now.Add(outboundTTL),
+	}
+	select {
+	case sender.outbound <- msg:
+	default:
+		outboundDroppedCounter.Inc(1)
+	}
+	return err
+}
+
+func (sender *MessageSender) currentBlockNum() uint64 {
+	sender.blockNumMutex.Lock()
+	defer sender.blockNumMutex.Unlock()
+	return sender.blockNum
+}
+
+func (sender *MessageSender) currentViewID() uint64 {
+	if sender.viewID == nil {
+		return 0
+	}
+	return sender.viewID()
+}
+
+// isStale tells whether the consensus moved past the round of the message
+func (sender *MessageSender) isStale(msg *outboundMessage) bool {
+	if msg.crossRound {
+		return false
+	}
+	return msg.blockNum < sender.currentBlockNum() || msg.viewID < sender.currentViewID()
+}
+
+// resendFailed resends the queued messages, with an exponential backoff,
+// until sent, stale, expired or out of attempts
+func (sender *MessageSender) resendFailed() {
+	for msg := range sender.outbound {
+		if wait := time.Until(msg.next); wait > 0 {
+			time.Sleep(wait)
+		}
+		if sender.isStale(msg) || time.Now().After(msg.expiry) {
+			outboundStaleCounter.Inc(1)
+			continue
+		}
+		msg.attempts++
+		outboundResentCounter.Inc(1)
+		err := sender.send(msg.groups, msg.p2pMsg)
+		if err == nil {
+			continue
+		}
+		if msg.attempts >= outboundMaxAttempts {
+			outboundDroppedCounter.Inc(1)
+			utils.Logger().Warn().Err(err).
+				Str("groupID[0]", msg.groups[0].String()).
+				Uint64("blockNum", msg.blockNum).
+				Int("attempts", msg.attempts).
+				Msg("[resendFailed] Giving up re-sending consensus message")
+			continue
+		}
+		msg.next = time.Now().Add(outboundBackoff << uint(msg.attempts))
+		select {
+		case sender.outbound <- msg:
+		default:
+			outboundDroppedCounter.Inc(1)
+		}
+	}
 }
 
 // Retry will retry the consensus message for <RetryTimes> times.
@@ -105,7 +229,7 @@ func (sender *MessageSender) Retry(msgRetry *MessageRetry) {
 		}
 
 		msgRetry.retryCount++
-		if err := sender.host.SendMessageToGroups(msgRetry.groups, msgRetry.p2pMsg); err != nil {
+		if err := sender.send(msgRetry.groups, msgRetry.p2pMsg); err != nil {
 			utils.Logger().Warn().Str("groupID[0]", msgRetry.groups[0].String()).Uint64("blockNum", msgRetry.blockNum).Str("MsgType", msgRetry.msgType.String()).Int("RetryCount", msgRetry.retryCount).Msg("[Retry] Failed re-sending consensus message")
 		} else {
 			utils.Logger().Info().Str("groupID[0]", msgRetry.groups[0].String()).Uint64("blockNum", msgRetry.blockNum).Str("MsgType", msgRetry.msgType.String()).Int("RetryCount", msgRetry.retryCount).Msg("[Retry] Successfully resent consensus message")
@@ -0,0 +1,75 @@
+package consensus
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// flakyHost fails the first sends of each message
+type flakyHost struct {
+	p2p.Host
+	lock     sync.Mutex
+	failures int
+	sent     [][]byte
+}
+
+func (h *flakyHost) SendMessageToGroups(groups []nodeconfig.GroupID, msg []byte) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("cannot publish")
+	}
+	h.sent = append(h.sent, msg)
+	return nil
+}
+
+func (h *flakyHost) sentCount() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.sent)
+}
+
+func TestMessageSenderResendsFailedMessages(t *testing.T) {
+	host := &flakyHost{failures: 2}
+	sender := NewMessageSender(host)
+	groups := []nodeconfig.GroupID{"shard"}
+	if err := sender.SendWithoutRetry(groups, []byte("prepare")); err == nil {
+		t.Fatal("expected the first send to fail")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for host.sentCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if host.sentCount() != 1 {
+		t.Fatalf("message resent %d times, expected once", host.sentCount())
+	}
+}
+
+func TestMessageSenderDropsStaleMessages(t *testing.T) {
+	host := &flakyHost{failures: 1}
+	sender := NewMessageSender(host)
+	viewID := uint64(1)
+	var lock sync.Mutex
+	sender.viewID = func() uint64 {
+		lock.Lock()
+		defer lock.Unlock()
+		return viewID
+	}
+	groups := []nodeconfig.GroupID{"shard"}
+	if err := sender.SendWithoutRetry(groups, []byte("prepare")); err == nil {
+		t.Fatal("expected the first send to fail")
+	}
+	lock.Lock()
+	viewID = 2
+	lock.Unlock()
+	time.Sleep(2 * outboundBackoff)
+	if host.sentCount() != 0 {
+		t.Error("stale message was resent")
+	}
+}
@@ -11,12 +11,11 @@ import (
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/block"
 	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
-	"github.com/harmony-one/harmony/consensus/quorum"
 	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
 	"github.com/harmony-one/harmony/crypto/hash"
 	"github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/pipe"
 	"github.com/harmony-one/harmony/internal/utils"
-	"github.com/harmony-one/harmony/multibls"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/shard"
 	"github.com/harmony-one/harmony/shard/committee"
@@ -29,7 +28,7 @@ import (
 func (consensus *Consensus) WaitForNewRandomness() {
 	go func() {
 		for {
-			vdfOutput := <-consensus.RndChannel
+			vdfOutput := (<-consensus.RndChannel.C()).([vdfAndSeedSize]byte)
 			consensus.pendingRnds = append(consensus.pendingRnds, vdfOutput)
 		}
 	}()
@@ -83,11 +82,6 @@ func (consensus *Consensus) GetNodeIDs() []libp2p_peer.ID {
 	return nodes
 }
 
-// GetViewID returns the consensus ID
-func (consensus *Consensus) GetViewID() uint64 {
-	return consensus.viewID
-}
-
 // UpdatePublicKeys updates the PublicKeys for
 // quorum on current subcommittee, protected by a mutex
 func (consensus *Consensus) UpdatePublicKeys(pubKeys []*bls.PublicKey) int64 {
@@ -100,9 +94,9 @@ func (consensus *Consensus) UpdatePublicKeys(pubKeys []*bls.PublicKey) int64 {
 			Str("BLSPubKey", pubKeys[i].SerializeToHexStr()).
 			Msg("Member")
 	}
-	consensus.LeaderPubKey = pubKeys[0]
+	consensus.SetLeaderPubKey(pubKeys[0])
 	utils.Logger().Info().
-		Str("info", consensus.LeaderPubKey.SerializeToHexStr()).Msg("My Leader")
+		Str("info", consensus.LeaderPubKey().SerializeToHexStr()).Msg("My Leader")
 	consensus.pubKeyLock.Unlock()
 	// reset states after update public keys
 	consensus.ResetState()
@@ -173,12 +167,13 @@ func (consensus *Consensus) GetNilSigsArray(viewID uint64) []*bls.Sign {
 // ResetState resets the state of the consensus
 func (consensus *Consensus) ResetState() {
 	consensus.getLogger().Debug().
-		Str("Phase", consensus.phase.String()).
+		Str("Phase", consensus.Phase().String()).
 		Msg("[ResetState] Resetting consensus state")
 	consensus.switchPhase(FBFTAnnounce, true)
 	consensus.blockHash = [32]byte{}
 	consensus.blockHeader = []byte{}
 	consensus.block = []byte{}
+	consensus.preparedBlock = []byte{}
 	consensus.Decider.ResetPrepareAndCommitVotes()
 	members := consensus.Decider.Participants()
 	prepareBitmap, _ := bls_cosi.NewMask(members, nil)
@@ -203,8 +198,8 @@ func (consensus *Consensus) String() string {
 		duty,
 		consensus.PubKey.SerializeToHexStr(),
 		hex.EncodeToString(consensus.blockHeader),
-		consensus.blockNum,
-		consensus.viewID,
+		consensus.BlockNum(),
+		consensus.GetViewID(),
 		consensus.ShardID,
 		consensus.epoch,
 	)
@@ -223,7 +218,7 @@ func (consensus *Consensus) IsValidatorInCommittee(pubKey *bls.PublicKey) bool {
 }
 
 // Verify the signature of the message are valid from the signer's public key.
-func verifyMessageSig(signerPubKey *bls.PublicKey, message *msg_pb.Message) error {
+func (consensus *Consensus) verifyMessageSig(signerPubKey *bls.PublicKey, message *msg_pb.Message) error {
 	signature := message.Signature
 	message.Signature = nil
 	messageBytes, err := protobuf.Marshal(message)
@@ -237,13 +232,26 @@ func verifyMessageSig(signerPubKey *bls.PublicKey, message *msg_pb.Message) erro
 		return err
 	}
 	msgHash := hash.Keccak256(messageBytes)
-	if !msgSig.VerifyHash(signerPubKey, msgHash[:]) {
+	if !consensus.sigCache.VerifyHash(&msgSig, signerPubKey, msgHash[:]) {
 		return errors.New("failed to verify the signature")
 	}
 	message.Signature = signature
 	return nil
 }
 
+// VerifyLeaderMessage checks the consensus message is signed by the leader of
+// the current round
+func (consensus *Consensus) VerifyLeaderMessage(msg *msg_pb.Message) error {
+	senderKey, err := bls_cosi.BytesToBLSPublicKey(msg.GetConsensus().GetSenderPubkey())
+	if err != nil {
+		return err
+	}
+	if leader := consensus.LeaderPubKey(); leader == nil || !senderKey.IsEqual(leader) {
+		return errors.New("message not sent by the leader")
+	}
+	return consensus.verifyMessageSig(senderKey, msg)
+}
+
 // verifySenderKey verifys the message senderKey is properly signed and senderAddr is valid
 func (consensus *Consensus) verifySenderKey(msg *msg_pb.Message) (*bls.PublicKey, error) {
 	consensusMsg := msg.GetConsensus()
@@ -273,8 +281,8 @@ func (consensus *Consensus) verifyViewChangeSenderKey(msg *msg_pb.Message) (*bls
 
 // SetViewID set the viewID to the height of the blockchain
 func (consensus *Consensus) SetViewID(height uint64) {
-	consensus.viewID = height
-	consensus.current.viewID = height
+	consensus.setRoundViewID(height)
+	consensus.current.SetViewID(height)
 }
 
 // SetMode sets the mode of consensus
@@ -293,7 +301,7 @@ func (consensus *Consensus) RegisterPRndChannel(pRndChannel chan []byte) {
 }
 
 // RegisterRndChannel registers the channel for receiving final randomness from DRG protocol
-func (consensus *Consensus) RegisterRndChannel(rndChannel chan [548]byte) {
+func (consensus *Consensus) RegisterRndChannel(rndChannel *pipe.Pipe) {
 	consensus.RndChannel = rndChannel
 }
 
@@ -304,23 +312,27 @@ func (consensus *Consensus) checkViewID(msg *FBFTMessage) error {
 		//in syncing mode, node accepts incoming messages without viewID/leaderKey checking
 		//so only set mode to normal when new node enters consensus and need checking viewID
 		consensus.current.SetMode(Normal)
-		consensus.viewID = msg.ViewID
+		consensus.updateRound(func(round *roundState) {
+			round.viewID = msg.ViewID
+			round.leader = msg.SenderPubkey
+		})
 		consensus.current.SetViewID(msg.ViewID)
-		consensus.LeaderPubKey = msg.SenderPubkey
 		consensus.ignoreViewIDCheck = false
 		consensus.consensusTimeout[timeoutConsensus].Start()
 		utils.Logger().Debug().
-			Uint64("viewID", consensus.viewID).
-			Str("leaderKey", consensus.LeaderPubKey.SerializeToHexStr()[:20]).
+			Uint64("viewID", consensus.GetViewID()).
+			Str("leaderKey", consensus.LeaderPubKey().SerializeToHexStr()[:20]).
 			Msg("viewID and leaderKey override")
 		utils.Logger().Debug().
-			Uint64("viewID", consensus.viewID).
-			Uint64("block", consensus.blockNum).
+			Uint64("viewID", consensus.GetViewID()).
+			Uint64("block", consensus.BlockNum()).
 			Msg("Start consensus timer")
 		return nil
-	} else if msg.ViewID > consensus.viewID {
+	}
+	viewID := consensus.GetViewID()
+	if msg.ViewID > viewID {
 		return consensus_engine.ErrViewIDNotMatch
-	} else if msg.ViewID < consensus.viewID {
+	} else if msg.ViewID < viewID {
 		return errors.New("view ID belongs to the past")
 	}
 	return nil
@@ -328,9 +340,9 @@ func (consensus *Consensus) checkViewID(msg *FBFTMessage) error {
 
 // SetBlockNum sets the blockNum in consensus object, called at node bootstrap
 func (consensus *Consensus) SetBlockNum(blockNum uint64) {
-	consensus.infoMutex.Lock()
-	defer consensus.infoMutex.Unlock()
-	consensus.blockNum = blockNum
+	consensus.updateRound(func(round *roundState) {
+		round.blockNum = blockNum
+	})
 }
 
 // SetEpochNum sets the epoch in consensus object
@@ -355,11 +367,11 @@ func (consensus *Consensus) ReadSignatureBitmapPayload(
 
 // getLogger returns logger for consensus contexts added
 func (consensus *Consensus) getLogger() *zerolog.Logger {
-	logger := utils.Logger().With().
+	logger := utils.ModuleLogger(utils.ModuleConsensus).With().
 		Uint64("myEpoch", consensus.epoch).
-		Uint64("myBlock", consensus.blockNum).
-		Uint64("myViewID", consensus.viewID).
-		Interface("phase", consensus.phase).
+		Uint64("myBlock", consensus.BlockNum()).
+		Uint64("myViewID", consensus.GetViewID()).
+		Interface("phase", consensus.Phase()).
 		Str("mode", consensus.current.Mode().String()).
 		Logger()
 	return &logger
@@ -422,23 +434,10 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 	curHeader := consensus.ChainReader.CurrentHeader()
 	curEpoch := curHeader.Epoch()
 	nextEpoch := new(big.Int).Add(curHeader.Epoch(), common.Big1)
-	isFirstTimeStaking := consensus.ChainReader.Config().IsStaking(nextEpoch) &&
-		len(curHeader.ShardState()) > 0 &&
-		!consensus.ChainReader.Config().IsStaking(curEpoch)
-	haventUpdatedDecider := consensus.ChainReader.Config().IsStaking(curEpoch) &&
-		consensus.Decider.Policy() != quorum.SuperMajorityStake
-
-	// Only happens once, the flip-over to a new Decider policy
-	if isFirstTimeStaking || haventUpdatedDecider {
-		decider := quorum.NewDecider(quorum.SuperMajorityStake, consensus.ShardID)
-		decider.SetMyPublicKeyProvider(func() (*multibls.PublicKey, error) {
-			return consensus.PubKey, nil
-		})
-		consensus.Decider = decider
-	}
 
 	committeeToSet := &shard.Committee{}
 	epochToSet := curEpoch
+	var stateToSet *shard.State
 	hasError := false
 	curShardState, err := committee.WithStakingEnabled.ReadFromDB(
 		curEpoch, consensus.ChainReader,
@@ -483,6 +482,7 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 
 		committeeToSet = subComm
 		epochToSet = nextEpoch
+		stateToSet = nextShardState
 	} else {
 		consensus.SetEpochNum(curEpoch.Uint64())
 		subComm, err := curShardState.FindCommitteeByID(curHeader.ShardID())
@@ -495,7 +495,9 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 		}
 
 		committeeToSet = subComm
+		stateToSet = curShardState
 	}
+	consensus.updateKeyShards(stateToSet, epochToSet)
 
 	if len(committeeToSet.Slots) == 0 {
 		consensus.getLogger().Warn().
@@ -504,7 +506,7 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 	}
 
 	// update public keys in the committee
-	oldLeader := consensus.LeaderPubKey
+	oldLeader := consensus.LeaderPubKey()
 	pubKeys, _ := committeeToSet.BLSPublicKeys()
 
 	consensus.getLogger().Info().
@@ -512,7 +514,9 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 		Msg("[UpdateConsensusInformation] Successfully updated public keys")
 	consensus.UpdatePublicKeys(pubKeys)
 
-	// Update voters in the committee
+	// Switch to the policy of the epoch, ex: stake weighted votes from the
+	// staking epoch on, then update voters in the committee
+	consensus.setDeciderPolicy(epochToSet)
 	if _, err := consensus.Decider.SetVoters(
 		committeeToSet, epochToSet,
 	); err != nil {
@@ -542,7 +546,16 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 			consensus.getLogger().Debug().
 				Str("leaderPubKey", leaderPubKey.SerializeToHexStr()).
 				Msg("[UpdateConsensusInformation] Most Recent LeaderPubKey Updated Based on BlockChain")
-			consensus.LeaderPubKey = leaderPubKey
+			consensus.SetLeaderPubKey(leaderPubKey)
+		}
+	}
+	if !hasError && consensus.electsLeader(curHeader) {
+		leaderPubKey, err := consensus.electLeader(curHeader, consensus.GetViewID())
+		if err != nil {
+			consensus.getLogger().Warn().Err(err).
+				Msg("[UpdateConsensusInformation] Unable to elect the leader")
+		} else {
+			consensus.SetLeaderPubKey(leaderPubKey)
 		}
 	}
 
@@ -554,15 +567,13 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 			}
 
 			// If the leader changed and I myself become the leader
-			if !consensus.LeaderPubKey.IsEqual(oldLeader) && consensus.IsLeader() {
-				go func() {
-					utils.Logger().Debug().
-						Str("myKey", consensus.PubKey.SerializeToHexStr()).
-						Uint64("viewID", consensus.viewID).
-						Uint64("block", consensus.blockNum).
-						Msg("[UpdateConsensusInformation] I am the New Leader")
-					consensus.ReadySignal <- struct{}{}
-				}()
+			if !consensus.LeaderPubKey().IsEqual(oldLeader) && consensus.IsLeader() {
+				utils.Logger().Debug().
+					Str("myKey", consensus.PubKey.SerializeToHexStr()).
+					Uint64("viewID", consensus.GetViewID()).
+					Uint64("block", consensus.BlockNum()).
+					Msg("[UpdateConsensusInformation] I am the New Leader")
+				consensus.proposer.propose()
 			}
 			return Normal
 		}
@@ -575,7 +586,7 @@ func (consensus *Consensus) UpdateConsensusInformation() Mode {
 // the node with the leader public key
 func (consensus *Consensus) IsLeader() bool {
 	for _, key := range consensus.PubKey.PublicKey {
-		if key.IsEqual(consensus.LeaderPubKey) {
+		if key.IsEqual(consensus.LeaderPubKey()) {
 			return true
 		}
 	}
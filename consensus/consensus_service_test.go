@@ -30,7 +30,7 @@ func TestPopulateMessageFields(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Cannot craeate consensus: %v", err)
 	}
-	consensus.viewID = 2
+	consensus.SetViewID(2)
 	blockHash := [32]byte{}
 	consensus.blockHash = blockHash
 
@@ -69,7 +69,7 @@ func TestSignAndMarshalConsensusMessage(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Cannot craeate consensus: %v", err)
 	}
-	consensus.viewID = 2
+	consensus.SetViewID(2)
 	consensus.blockHash = [32]byte{}
 
 	msg := &msg_pb.Message{}
@@ -103,7 +103,7 @@ func TestSetViewID(t *testing.T) {
 
 	height := uint64(1000)
 	consensus.SetViewID(height)
-	if consensus.viewID != height {
-		t.Errorf("Cannot set consensus ID. Got: %v, Expected: %v", consensus.viewID, height)
+	if consensus.GetViewID() != height {
+		t.Errorf("Cannot set consensus ID. Got: %v, Expected: %v", consensus.GetViewID(), height)
 	}
 }
@@ -1,8 +1,11 @@
 package consensus
 
 import (
+	"sync"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	bls_core "github.com/harmony-one/bls/ffi/go/bls"
 	"github.com/harmony-one/harmony/consensus/quorum"
 	"github.com/harmony-one/harmony/crypto/bls"
 	"github.com/harmony-one/harmony/internal/utils"
@@ -27,11 +30,77 @@ func TestNew(test *testing.T) {
 	if err != nil {
 		test.Fatalf("Cannot craeate consensus: %v", err)
 	}
-	if consensus.viewID != 0 {
-		test.Errorf("Consensus Id is initialized to the wrong value: %d", consensus.viewID)
+	if consensus.GetViewID() != 0 {
+		test.Errorf("Consensus Id is initialized to the wrong value: %d", consensus.GetViewID())
 	}
 
 	if consensus.ReadySignal == nil {
 		test.Error("Consensus ReadySignal should be initialized")
 	}
 }
+
+func TestConcurrentPhaseVotes(t *testing.T) {
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9906"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9906")
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := New(
+		host, shard.BeaconChainShardID, leader, multibls.GetPrivateKey(bls.RandPrivateKey()), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	keys := make([]*bls_core.SecretKey, 9)
+	pubKeys := make([]*bls_core.PublicKey, len(keys))
+	for i := range keys {
+		keys[i] = bls.RandPrivateKey()
+		pubKeys[i] = keys[i].GetPublicKey()
+	}
+	consensus.Decider.UpdateParticipants(pubKeys)
+	consensus.ResetState()
+	blockHash := [32]byte{1}
+	consensus.blockHash = blockHash
+	commitPayload := append(blockHash[:], 'c')
+
+	// short of the quorum, the prepare and commit votes are counted
+	// concurrently, under the lock of their phase
+	const voters = 5
+	wg := sync.WaitGroup{}
+	for i, key := range keys[:voters] {
+		vote := &FBFTMessage{
+			ViewID:       consensus.GetViewID(),
+			BlockNum:     consensus.BlockNum(),
+			BlockHash:    common.BytesToHash(blockHash[:]),
+			SenderPubkey: pubKeys[i],
+		}
+		prepareSig, commitSig := key.SignHash(blockHash[:]), key.SignHash(commitPayload)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			consensus.onVerifiedPrepare(vote, prepareSig, blockHash)
+		}()
+		go func() {
+			defer wg.Done()
+			consensus.onVerifiedCommit(vote, commitSig)
+		}()
+	}
+	wg.Wait()
+
+	for _, test := range []struct {
+		phase  quorum.Phase
+		bitmap *bls.Mask
+	}{
+		{quorum.Prepare, consensus.prepareBitmap},
+		{quorum.Commit, consensus.commitBitmap},
+	} {
+		if count := consensus.Decider.SignersCount(test.phase); count != voters {
+			t.Errorf("%s: expected %d signers, got %d", test.phase, voters, count)
+		}
+		if count := test.bitmap.CountEnabled(); count != voters {
+			t.Errorf("%s: expected %d keys in the bitmap, got %d", test.phase, voters, count)
+		}
+	}
+}
@@ -5,13 +5,14 @@ import (
 	"encoding/hex"
 	"time"
 
-	protobuf "github.com/golang/protobuf/proto"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/block"
 	"github.com/harmony-one/harmony/consensus/quorum"
 	"github.com/harmony-one/harmony/core/types"
 	vrf_bls "github.com/harmony-one/harmony/crypto/vrf/bls"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/pipe"
+	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/shard"
 	"github.com/harmony-one/vdf/src/vdf_go"
@@ -19,16 +20,7 @@ import (
 )
 
 // handlemessageupdate will update the consensus state according to received message
-func (consensus *Consensus) handleMessageUpdate(payload []byte) {
-	if len(payload) == 0 {
-		return
-	}
-	msg := &msg_pb.Message{}
-	if err := protobuf.Unmarshal(payload, msg); err != nil {
-		consensus.getLogger().Error().Err(err).Msg("Failed to unmarshal message payload.")
-		return
-	}
-
+func (consensus *Consensus) handleMessageUpdate(msg *msg_pb.Message) {
 	// when node is in ViewChanging mode, it still accepts normal messages into FBFTLog
 	// in order to avoid possible trap forever but drop PREPARE and COMMIT
 	// which are message types specifically for a node acting as leader
@@ -59,6 +51,14 @@ func (consensus *Consensus) handleMessageUpdate(payload []byte) {
 		}
 	}
 
+	if err := consensus.checkMessageEpoch(msg); err != nil {
+		consensus.getLogger().Debug().
+			Err(err).
+			Str("msgType", msg.Type.String()).
+			Msg("Rejected consensus message")
+		return
+	}
+
 	intendedForValidator, intendedForLeader :=
 		!consensus.IsLeader(),
 		consensus.IsLeader()
@@ -77,6 +77,10 @@ func (consensus *Consensus) handleMessageUpdate(payload []byte) {
 		intendedForValidator &&
 		consensus.validatorSanityChecks(msg):
 		consensus.onCommitted(msg)
+	case t == msg_pb.MessageType_COMMIT &&
+		intendedForValidator &&
+		msg.GetConsensus() != nil:
+		consensus.onObservedCommit(msg)
 	// Handle leader intended messages now
 	case t == msg_pb.MessageType_PREPARE &&
 		intendedForLeader &&
@@ -99,7 +103,7 @@ func (consensus *Consensus) finalizeCommits() {
 	consensus.getLogger().Info().
 		Int64("NumCommits", consensus.Decider.SignersCount(quorum.Commit)).
 		Msg("[finalizeCommits] Finalizing Block")
-	beforeCatchupNum := consensus.blockNum
+	beforeCatchupNum := consensus.BlockNum()
 	leaderPriKey, err := consensus.GetConsensusLeaderPrivateKey()
 	if err != nil {
 		consensus.getLogger().Error().Err(err).Msg("[FinalizeCommits] leader not found")
@@ -129,7 +133,7 @@ func (consensus *Consensus) finalizeCommits() {
 	}
 
 	consensus.tryCatchup()
-	if consensus.blockNum-beforeCatchupNum != 1 {
+	if consensus.BlockNum()-beforeCatchupNum != 1 {
 		consensus.getLogger().Warn().
 			Uint64("beforeCatchupBlockNum", beforeCatchupNum).
 			Msg("[FinalizeCommits] Leader cannot provide the correct block for committed message")
@@ -147,7 +151,7 @@ func (consensus *Consensus) finalizeCommits() {
 	} else {
 		consensus.getLogger().Info().
 			Hex("blockHash", curBlockHash[:]).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("blockNum", consensus.BlockNum()).
 			Msg("[finalizeCommits] Sent Committed Message")
 	}
 
@@ -170,34 +174,26 @@ func (consensus *Consensus) finalizeCommits() {
 		Uint64("epochNum", block.Epoch().Uint64()).
 		Uint64("ViewId", block.Header().ViewID().Uint64()).
 		Str("blockHash", block.Hash().String()).
-		Int("index", consensus.Decider.IndexOf(consensus.LeaderPubKey)).
+		Int("index", consensus.Decider.IndexOf(consensus.LeaderPubKey())).
 		Int("numTxns", len(block.Transactions())).
 		Int("numStakingTxns", len(block.StakingTransactions())).
 		Msg("HOORAY!!!!!!! CONSENSUS REACHED!!!!!!!")
 
-	if n := time.Now(); n.Before(consensus.NextBlockDue) {
-		// Sleep to wait for the full block time
-		consensus.getLogger().Debug().Msg("[finalizeCommits] Waiting for Block Time")
-		time.Sleep(consensus.NextBlockDue.Sub(n))
-	}
-	// Send signal to Node to propose the new block for consensus
-	consensus.ReadySignal <- struct{}{}
-
-	// Update time due for next block
-	consensus.NextBlockDue = time.Now().Add(consensus.BlockPeriod)
+	// Signal Node to propose the new block once the full block time elapsed
+	consensus.proposer.finalize()
 }
 
 // BlockCommitSig returns the byte array of aggregated
 // commit signature and bitmap signed on the block
 func (consensus *Consensus) BlockCommitSig(blockNum uint64) ([]byte, []byte, error) {
-	if consensus.blockNum <= 1 {
+	if consensus.BlockNum() <= 1 {
 		return nil, nil, nil
 	}
 	lastCommits, err := consensus.ChainReader.ReadCommitSig(blockNum)
 	if err != nil ||
 		len(lastCommits) < shard.BLSSignatureSizeInBytes {
 		msgs := consensus.FBFTLog.GetMessagesByTypeSeq(
-			msg_pb.MessageType_COMMITTED, consensus.blockNum-1,
+			msg_pb.MessageType_COMMITTED, consensus.BlockNum()-1,
 		)
 		if len(msgs) != 1 {
 			consensus.getLogger().Error().
@@ -223,10 +219,10 @@ func (consensus *Consensus) BlockCommitSig(blockNum uint64) ([]byte, []byte, err
 // try to catch up if fall behind
 func (consensus *Consensus) tryCatchup() {
 	consensus.getLogger().Info().Msg("[TryCatchup] commit new blocks")
-	currentBlockNum := consensus.blockNum
+	currentBlockNum := consensus.BlockNum()
 	for {
 		msgs := consensus.FBFTLog.GetMessagesByTypeSeq(
-			msg_pb.MessageType_COMMITTED, consensus.blockNum,
+			msg_pb.MessageType_COMMITTED, consensus.BlockNum(),
 		)
 		if len(msgs) == 0 {
 			break
@@ -256,11 +252,15 @@ func (consensus *Consensus) tryCatchup() {
 					Msg("[TryCatchup] Failed finding a matching block for committed message")
 				continue
 			}
-
-			if consensus.BlockVerifier == nil {
-				// do nothing
-			} else if err := consensus.BlockVerifier(tmpBlock); err != nil {
-				consensus.getLogger().Info().Msg("[TryCatchup] block verification failed")
+			// The state of the block is verified on top of the current head
+			if tmpBlock.ParentHash() != consensus.ChainReader.CurrentHeader().Hash() {
+				consensus.getLogger().Debug().
+					Str("blockHash", tmpBlock.Hash().Hex()).
+					Msg("[TryCatchup] parent block hash not match")
+				continue
+			}
+			if err := consensus.verifyBlock(tmpBlock); err != nil {
+				consensus.getLogger().Info().Err(err).Msg("[TryCatchup] block verification failed")
 				continue
 			}
 			committedMsg = msgs[i]
@@ -271,12 +271,15 @@ func (consensus *Consensus) tryCatchup() {
 			consensus.getLogger().Error().Msg("[TryCatchup] Failed finding a valid committed message.")
 			break
 		}
+		consensus.getLogger().Info().Msg("[TryCatchup] block found to commit")
 
-		if block.ParentHash() != consensus.ChainReader.CurrentHeader().Hash() {
-			consensus.getLogger().Debug().Msg("[TryCatchup] parent block hash not match")
+		// the block may be the first of an epoch under another quorum policy
+		if err := consensus.catchupDeciderPolicy(block.Epoch()); err != nil {
+			consensus.getLogger().Error().Err(err).
+				Uint64("epoch", block.Epoch().Uint64()).
+				Msg("[TryCatchup] Cannot switch to the quorum policy of the block")
 			break
 		}
-		consensus.getLogger().Info().Msg("[TryCatchup] block found to commit")
 
 		preparedMsgs := consensus.FBFTLog.GetMessagesByTypeSeqHash(
 			msg_pb.MessageType_PREPARED, committedMsg.BlockNum, committedMsg.BlockHash,
@@ -287,22 +290,54 @@ func (consensus *Consensus) tryCatchup() {
 		}
 		consensus.getLogger().Info().Msg("[TryCatchup] prepared message found to commit")
 
+		consensus.recordVotes(block, msg, committedMsg)
+		consensus.leaderTracker.committed(committedMsg.SenderPubkey)
+
+		nextLeader, wasLeader := committedMsg.SenderPubkey, consensus.IsLeader()
+		if consensus.electsLeader(block.Header()) {
+			elected, err := consensus.electLeader(block.Header(), committedMsg.ViewID+1)
+			if err != nil {
+				consensus.getLogger().Warn().Err(err).
+					Msg("[TryCatchup] Cannot elect the next leader, keeping the current one")
+			} else {
+				nextLeader = elected
+			}
+		}
+
 		// TODO(Chao): Explain the reasoning for these code
 		consensus.blockHash = [32]byte{}
-		consensus.blockNum = consensus.blockNum + 1
-		consensus.viewID = committedMsg.ViewID + 1
-		consensus.LeaderPubKey = committedMsg.SenderPubkey
+		consensus.updateRound(func(round *roundState) {
+			round.blockNum++
+			round.viewID = committedMsg.ViewID + 1
+			round.leader = nextLeader
+		})
+		consensus.startVoteRound()
+		consensus.leaderTracker.begin(nextLeader, committedMsg.BlockNum+1)
+		// the leader committing the block schedules its next proposal itself
+		if !wasLeader && consensus.IsLeader() {
+			consensus.getLogger().Info().
+				Uint64("blockNum", consensus.BlockNum()).
+				Msg("[TryCatchup] Elected leader of the next block")
+			consensus.proposer.start(consensus.BlockPeriod)
+			consensus.proposer.finalize()
+		}
 
 		consensus.getLogger().Info().Msg("[TryCatchup] Adding block to chain")
+		consensus.publishPhaseEvent(PhaseEvent{
+			Kind:     BlockFinalized,
+			ShardID:  consensus.ShardID,
+			BlockNum: block.NumberU64(),
+			ViewID:   committedMsg.ViewID,
+			From:     consensus.Phase().String(),
+			To:       block.Hash().Hex(),
+		})
 
 		// Fill in the commit signatures
 		block.SetCurrentCommitSig(committedMsg.Payload)
 		consensus.OnConsensusDone(block)
 		consensus.ResetState()
 
-		select {
-		case consensus.VerifiedNewBlock <- block:
-		default:
+		if !consensus.VerifiedNewBlock.Send(block) {
 			consensus.getLogger().Info().
 				Str("blockHash", block.Hash().String()).
 				Msg("[TryCatchup] consensus verified block send to chan failed")
@@ -311,27 +346,28 @@ func (consensus *Consensus) tryCatchup() {
 
 		break
 	}
-	if currentBlockNum < consensus.blockNum {
+	if currentBlockNum < consensus.BlockNum() {
 		consensus.getLogger().Info().
 			Uint64("From", currentBlockNum).
-			Uint64("To", consensus.blockNum).
+			Uint64("To", consensus.BlockNum()).
 			Msg("[TryCatchup] Caught up!")
 		consensus.switchPhase(FBFTAnnounce, true)
 	}
 	// catup up and skip from view change trap
-	if currentBlockNum < consensus.blockNum &&
+	if currentBlockNum < consensus.BlockNum() &&
 		consensus.current.Mode() == ViewChanging {
 		consensus.current.SetMode(Normal)
 		consensus.consensusTimeout[timeoutViewChange].Stop()
 	}
 	// clean up old log
-	consensus.FBFTLog.DeleteBlocksLessThan(consensus.blockNum - 1)
-	consensus.FBFTLog.DeleteMessagesLessThan(consensus.blockNum - 1)
+	consensus.FBFTLog.DeleteBlocksLessThan(consensus.BlockNum() - 1)
+	consensus.FBFTLog.DeleteMessagesLessThan(consensus.BlockNum() - 1)
+	consensus.proposals.prune(consensus.BlockNum())
 }
 
 // Start waits for the next new block and run consensus
 func (consensus *Consensus) Start(
-	blockChannel chan *types.Block, stopChan, stoppedChan, startChannel chan struct{},
+	blockChannel *pipe.Pipe, stopChan, stoppedChan, startChannel chan struct{},
 ) {
 	go func() {
 		toStart := false
@@ -344,26 +380,36 @@ func (consensus *Consensus) Start(
 				<-startChannel
 				toStart = true
 				consensus.getLogger().Info().Time("time", time.Now()).Msg("[ConsensusMainLoop] Send ReadySignal")
-				consensus.ReadySignal <- struct{}{}
+				consensus.proposer.propose()
 			}()
 		}
 		consensus.getLogger().Info().Time("time", time.Now()).Msg("[ConsensusMainLoop] Consensus started")
+		// A panic stops the loop, closing stoppedChan first, for the service
+		// supervisor to restart it
+		defer func() {
+			if r := recover(); r != nil {
+				consensus.getLogger().Error().
+					Interface("panic", r).
+					Msg("[ConsensusMainLoop] Consensus main loop panicked")
+			}
+		}()
 		defer close(stoppedChan)
 		ticker := time.NewTicker(3 * time.Second)
 		defer ticker.Stop()
 		consensus.consensusTimeout[timeoutBootstrap].Start()
 		consensus.getLogger().Debug().
-			Uint64("viewID", consensus.viewID).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("viewID", consensus.GetViewID()).
+			Uint64("blockNum", consensus.BlockNum()).
 			Msg("[ConsensusMainLoop] Start bootstrap timeout (only once)")
 
 		vdfInProgress := false
 		// Set up next block due time.
-		consensus.NextBlockDue = time.Now().Add(consensus.BlockPeriod)
+		consensus.proposer.start(consensus.BlockPeriod)
 		for {
 			select {
 			case <-ticker.C:
-				consensus.getLogger().Debug().Msg("[ConsensusMainLoop] Ticker")
+				utils.SampledLogger(utils.ModuleConsensus, "ticker", tickerLogSampling).
+					Debug().Msg("[ConsensusMainLoop] Ticker")
 				if !toStart && isInitialLeader {
 					continue
 				}
@@ -377,7 +423,7 @@ func (consensus *Consensus) Start(
 					}
 					if k != timeoutViewChange {
 						consensus.getLogger().Debug().Msg("[ConsensusMainLoop] Ops Consensus Timeout!!!")
-						consensus.startViewChange(consensus.viewID + 1)
+						consensus.startViewChange(consensus.GetViewID() + 1)
 						break
 					} else {
 						consensus.getLogger().Debug().Msg("[ConsensusMainLoop] Ops View Change Timeout!!!")
@@ -400,7 +446,8 @@ func (consensus *Consensus) Start(
 				consensus.current.SetMode(Syncing)
 				consensus.getLogger().Info().Msg("[ConsensusMainLoop] Node is OUT OF SYNC")
 
-			case newBlock := <-blockChannel:
+			case v := <-blockChannel.C():
+				newBlock := v.(*types.Block)
 				consensus.getLogger().Info().
 					Uint64("MsgBlockNum", newBlock.NumberU64()).
 					Msg("[ConsensusMainLoop] Received Proposed New Block!")
@@ -489,17 +536,29 @@ func (consensus *Consensus) Start(
 					Msg("[ConsensusMainLoop] STARTING CONSENSUS")
 				consensus.announce(newBlock)
 
-			case msg := <-consensus.MsgChan:
-				consensus.handleMessageUpdate(msg)
+			case msg := <-consensus.msgQueues[PriorityFinality]:
+				consensus.handleQueuedMessage(msg)
+
+			case msg := <-consensus.msgQueues[PriorityQuorum]:
+				consensus.handleQueuedMessage(msg)
+
+			case msg := <-consensus.msgQueues[PriorityAnnounce]:
+				consensus.handleQueuedMessage(msg)
+
+			case msg := <-consensus.msgQueues[PriorityOther]:
+				consensus.handleQueuedMessage(msg)
 
-			case viewID := <-consensus.commitFinishChan:
+			case v := <-consensus.commitFinishChan.C():
+				viewID := v.(uint64)
 				consensus.getLogger().Debug().Msg("[ConsensusMainLoop] commitFinishChan")
 
 				// Only Leader execute this condition
 				func() {
-					consensus.mutex.Lock()
-					defer consensus.mutex.Unlock()
-					if viewID == consensus.viewID {
+					consensus.prepareMutex.Lock()
+					defer consensus.prepareMutex.Unlock()
+					consensus.commitMutex.Lock()
+					defer consensus.commitMutex.Unlock()
+					if viewID == consensus.GetViewID() {
 						consensus.finalizeCommits()
 					}
 				}()
@@ -544,7 +603,7 @@ func (consensus *Consensus) GenerateVrfAndProof(newBlock *types.Block, vrfBlockN
 
 // ValidateVrfAndProof validates a VRF/Proof from hash of previous block
 func (consensus *Consensus) ValidateVrfAndProof(headerObj *block.Header) bool {
-	vrfPk := vrf_bls.NewVRFVerifier(consensus.LeaderPubKey)
+	vrfPk := vrf_bls.NewVRFVerifier(consensus.LeaderPubKey())
 	var blockHash [32]byte
 	previousHeader := consensus.ChainReader.GetHeaderByNumber(
 		headerObj.Number().Uint64() - 1,
@@ -614,7 +673,7 @@ func (consensus *Consensus) GenerateVdfAndProof(newBlock *types.Block, vrfBlockN
 		rndBytes := [548]byte{}
 		copy(rndBytes[:516], output[:])
 		copy(rndBytes[516:], seed[:])
-		consensus.RndChannel <- rndBytes
+		consensus.RndChannel.Send(rndBytes)
 	}()
 }
 
@@ -22,16 +22,17 @@ func (consensus *Consensus) constructViewChangeMessage(pubKey *bls.PublicKey, pr
 
 	vcMsg := message.GetViewchange()
 	vcMsg.ViewId = consensus.current.ViewID()
-	vcMsg.BlockNum = consensus.blockNum
+	vcMsg.BlockNum = consensus.BlockNum()
 	vcMsg.ShardId = consensus.ShardID
+	vcMsg.Epoch = consensus.epoch
 	// sender address
 	vcMsg.SenderPubkey = pubKey.Serialize()
 
 	// next leader key already updated
-	vcMsg.LeaderPubkey = consensus.LeaderPubKey.Serialize()
+	vcMsg.LeaderPubkey = consensus.LeaderPubKey().Serialize()
 
 	preparedMsgs := consensus.FBFTLog.GetMessagesByTypeSeqHash(
-		msg_pb.MessageType_PREPARED, consensus.blockNum, consensus.blockHash,
+		msg_pb.MessageType_PREPARED, consensus.BlockNum(), consensus.blockHash,
 	)
 	preparedMsg := consensus.FBFTLog.FindMessageByMaxViewID(preparedMsgs)
 
@@ -74,8 +75,9 @@ func (consensus *Consensus) constructViewChangeMessage(pubKey *bls.PublicKey, pr
 	return proto.ConstructConsensusMessage(marshaledMessage)
 }
 
-// new leader construct newview message
-func (consensus *Consensus) constructNewViewMessage(viewID uint64, pubKey *bls.PublicKey, priKey *bls.SecretKey) []byte {
+// new leader construct newview message, which fails without a quorum of
+// M3 (viewID) signatures
+func (consensus *Consensus) constructNewViewMessage(viewID uint64, pubKey *bls.PublicKey, priKey *bls.SecretKey) ([]byte, error) {
 	message := &msg_pb.Message{
 		ServiceType: msg_pb.ServiceType_CONSENSUS,
 		Type:        msg_pb.MessageType_NEWVIEW,
@@ -86,8 +88,9 @@ func (consensus *Consensus) constructNewViewMessage(viewID uint64, pubKey *bls.P
 
 	vcMsg := message.GetViewchange()
 	vcMsg.ViewId = consensus.current.ViewID()
-	vcMsg.BlockNum = consensus.blockNum
+	vcMsg.BlockNum = consensus.BlockNum()
 	vcMsg.ShardId = consensus.ShardID
+	vcMsg.Epoch = consensus.epoch
 	// sender address
 	vcMsg.SenderPubkey = pubKey.Serialize()
 	vcMsg.Payload = consensus.m1Payload
@@ -102,17 +105,18 @@ func (consensus *Consensus) constructNewViewMessage(viewID uint64, pubKey *bls.P
 
 	sig3arr := consensus.GetViewIDSigsArray(viewID)
 	consensus.getLogger().Debug().Int("len", len(sig3arr)).Msg("[constructNewViewMessage] M3 (ViewID) type signatures")
-	// even we check here for safty, m3 type signatures must >= 2f+1
-	if len(sig3arr) > 0 {
-		m3Sig := bls_cosi.AggregateSig(sig3arr)
-		vcMsg.M3Aggsigs = m3Sig.Serialize()
-		vcMsg.M3Bitmap = consensus.viewIDBitmap[viewID].Bitmap
+	// m3 type signatures must >= 2f+1, the validators reject the message otherwise
+	if len(sig3arr) == 0 || !consensus.Decider.IsQuorumAchievedByMask(consensus.viewIDBitmap[viewID]) {
+		return nil, errNewViewM3NoQuorum
 	}
+	m3Sig := bls_cosi.AggregateSig(sig3arr)
+	vcMsg.M3Aggsigs = m3Sig.Serialize()
+	vcMsg.M3Bitmap = consensus.viewIDBitmap[viewID].Bitmap
 
 	marshaledMessage, err := consensus.signAndMarshalConsensusMessage(message, priKey)
 	if err != nil {
 		utils.Logger().Error().Err(err).
 			Msg("[constructNewViewMessage] failed to sign and marshal the new view message")
 	}
-	return proto.ConstructConsensusMessage(marshaledMessage)
+	return proto.ConstructConsensusMessage(marshaledMessage), nil
 }
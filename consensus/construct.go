@@ -24,9 +24,10 @@ type NetworkMessage struct {
 func (consensus *Consensus) populateMessageFields(
 	request *msg_pb.ConsensusRequest, blockHash []byte, pubKey *bls.PublicKey,
 ) *msg_pb.ConsensusRequest {
-	request.ViewId = consensus.viewID
-	request.BlockNum = consensus.blockNum
+	request.ViewId = consensus.outboundViewID(consensus.GetViewID())
+	request.BlockNum = consensus.BlockNum()
 	request.ShardId = consensus.ShardID
+	request.Epoch = consensus.epoch
 	// 32 byte block hash
 	request.BlockHash = blockHash
 	// sender address
@@ -57,7 +58,7 @@ func (consensus *Consensus) construct(
 	// Do the signing, 96 byte of bls signature
 	switch p {
 	case msg_pb.MessageType_PREPARED:
-		consensusMsg.Block = consensus.block
+		consensusMsg.Block = consensus.preparedBlock
 		// Payload
 		buffer := bytes.Buffer{}
 		// 96 bytes aggregated signature
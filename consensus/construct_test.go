@@ -68,16 +68,16 @@ func TestConstructPreparedMessage(test *testing.T) {
 		leaderPubKey,
 		leaderPriKey.Sign(message),
 		common.BytesToHash(consensus.blockHash[:]),
-		consensus.blockNum,
-		consensus.viewID,
+		consensus.BlockNum(),
+		consensus.GetViewID(),
 	)
 	if _, err := consensus.Decider.SubmitVote(
 		quorum.Prepare,
 		validatorPubKey,
 		validatorPriKey.Sign(message),
 		common.BytesToHash(consensus.blockHash[:]),
-		consensus.blockNum,
-		consensus.viewID,
+		consensus.BlockNum(),
+		consensus.GetViewID(),
 	); err != nil {
 		test.Log(err)
 	}
@@ -0,0 +1,58 @@
+package consensus
+
+import (
+	"math/big"
+
+	"github.com/harmony-one/harmony/consensus/quorum"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/shard/committee"
+)
+
+// deciderPolicy returns the quorum policy of the epoch on the network of the
+// shard
+func (consensus *Consensus) deciderPolicy(epoch *big.Int) quorum.Policy {
+	network := nodeconfig.GetShardConfig(consensus.ShardID).GetNetworkType()
+	return quorum.PolicyOf(string(network), consensus.ChainReader.Config(), epoch)
+}
+
+// setDeciderPolicy replaces the decider with one of the policy of the epoch
+// if it differs, returning whether it did. The voters of the new decider are
+// to be set by the caller.
+func (consensus *Consensus) setDeciderPolicy(epoch *big.Int) bool {
+	policy := consensus.deciderPolicy(epoch)
+	if consensus.Decider != nil && consensus.Decider.Policy() == policy {
+		return false
+	}
+	decider := quorum.NewDecider(policy, consensus.ShardID)
+	decider.SetMyPublicKeyProvider(func() (*multibls.PublicKey, error) {
+		return consensus.PubKey, nil
+	})
+	consensus.getLogger().Info().
+		Uint64("epoch", epoch.Uint64()).
+		Str("policy", policy.String()).
+		Msg("[setDeciderPolicy] switching quorum policy")
+	consensus.Decider = decider
+	return true
+}
+
+// catchupDeciderPolicy switches the decider to the policy of the epoch of a
+// block committed while catching up, with the committee of that epoch as the
+// voters, for the votes of the blocks crossing into a new policy to be
+// weighted as the chain weighs them
+func (consensus *Consensus) catchupDeciderPolicy(epoch *big.Int) error {
+	if consensus.Decider != nil && consensus.Decider.Policy() == consensus.deciderPolicy(epoch) {
+		return nil
+	}
+	state, err := committee.WithStakingEnabled.ReadFromDB(epoch, consensus.ChainReader)
+	if err != nil {
+		return err
+	}
+	subComm, err := state.FindCommitteeByID(consensus.ShardID)
+	if err != nil {
+		return err
+	}
+	consensus.setDeciderPolicy(epoch)
+	_, err = consensus.Decider.SetVoters(subComm, epoch)
+	return err
+}
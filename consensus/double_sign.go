@@ -69,7 +69,7 @@ func (consensus *Consensus) checkDoubleSign(recvMsg *FBFTMessage) bool {
 							return true
 						}
 
-						leaderShardKey := shard.FromLibBLSPublicKeyUnsafe(consensus.LeaderPubKey)
+						leaderShardKey := shard.FromLibBLSPublicKeyUnsafe(consensus.LeaderPubKey())
 						if leaderShardKey == nil {
 							consensus.getLogger().Error().
 								Str("msg", recvMsg.String()).
@@ -121,7 +121,7 @@ func (consensus *Consensus) checkDoubleSign(recvMsg *FBFTMessage) bool {
 func (consensus *Consensus) couldThisBeADoubleSigner(
 	recvMsg *FBFTMessage,
 ) bool {
-	num, hash := consensus.blockNum, recvMsg.BlockHash
+	num, hash := consensus.BlockNum(), recvMsg.BlockHash
 	suspicious := !consensus.FBFTLog.HasMatchingAnnounce(num, hash) ||
 		!consensus.FBFTLog.HasMatchingPrepared(num, hash)
 	if suspicious {
@@ -0,0 +1,171 @@
+package consensus
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/consensus/signature"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/harmony-one/harmony/staking/slash"
+	"github.com/pkg/errors"
+)
+
+var (
+	errEvidenceNotStaking   = errors.New("no slashing before the staking epoch")
+	errEvidenceInvalidSig   = errors.New("commit with an invalid signature")
+	errEvidenceNoReporter   = errors.New("no key of this node in the committee to report with")
+	errEvidenceSelfReported = errors.New("reporter and offender are the same validator")
+)
+
+// onObservedCommit is run by a validator on the commit votes gossiped to the
+// leader. The commits of the current round are recorded in the FBFT log, and a
+// commit conflicting with one recorded before, signed by the same key at the
+// same block number and view id on another block, is turned into a slashing
+// record handed to the broadcaster through SlashChan.
+func (consensus *Consensus) onObservedCommit(msg *msg_pb.Message) {
+	recvMsg, err := ParseFBFTMessage(msg)
+	if err != nil {
+		consensus.getLogger().Debug().Err(err).Msg("[onObservedCommit] Parse pbft message failed")
+		return
+	}
+	// only the commits around the round of the node are kept
+	if num := consensus.BlockNum(); recvMsg.BlockNum+1 < num || recvMsg.BlockNum > num+1 {
+		return
+	}
+	if !consensus.IsValidatorInCommittee(recvMsg.SenderPubkey) {
+		return
+	}
+	for _, known := range consensus.FBFTLog.GetMessagesByTypeSeqViewHash(
+		msg_pb.MessageType_COMMIT, recvMsg.BlockNum, recvMsg.ViewID, recvMsg.BlockHash,
+	) {
+		if known.SenderPubkey.IsEqual(recvMsg.SenderPubkey) &&
+			bytes.Equal(known.Payload, recvMsg.Payload) {
+			return
+		}
+	}
+
+	conflicting := consensus.FBFTLog.GetConflictingCommits(recvMsg)
+	if len(conflicting) == 0 {
+		// the signatures are only verified once a conflict shows up
+		consensus.FBFTLog.AddMessage(recvMsg)
+		return
+	}
+	if err := consensus.verifyObservedCommit(recvMsg); err != nil {
+		consensus.getLogger().Debug().Err(err).
+			Str("msg", recvMsg.String()).
+			Msg("[onObservedCommit] dropping the conflicting commit")
+		return
+	}
+	consensus.FBFTLog.AddMessage(recvMsg)
+	for _, first := range conflicting {
+		// a forged commit recorded first must not hide the genuine ones
+		if err := consensus.verifyObservedCommit(first); err != nil {
+			consensus.FBFTLog.DeleteMessage(first)
+			continue
+		}
+		record, err := consensus.buildDoubleSignRecord(first, recvMsg)
+		if err != nil {
+			consensus.getLogger().Warn().Err(err).
+				Str("msg", recvMsg.String()).
+				Msg("[onObservedCommit] cannot build the double sign evidence")
+			return
+		}
+		consensus.getLogger().Info().
+			Str("offender", record.Evidence.Offender.Hex()).
+			Uint64("blockNum", recvMsg.BlockNum).
+			Uint64("viewID", recvMsg.ViewID).
+			Msg("[onObservedCommit] double sign observed")
+		go func() {
+			consensus.SlashChan <- *record
+		}()
+		return
+	}
+}
+
+// verifyObservedCommit verifies the signature of a commit on its block
+func (consensus *Consensus) verifyObservedCommit(commit *FBFTMessage) error {
+	var sig bls.Sign
+	if err := sig.Deserialize(commit.Payload); err != nil {
+		return errors.Wrap(err, "cannot deserialize the commit signature")
+	}
+	payload := signature.ConstructCommitPayload(
+		consensus.ChainReader, new(big.Int).SetUint64(consensus.epoch),
+		commit.BlockHash, commit.BlockNum, commit.ViewID,
+	)
+	if !sig.VerifyHash(commit.SenderPubkey, payload) {
+		return errEvidenceInvalidSig
+	}
+	return nil
+}
+
+// buildDoubleSignRecord constructs the slashing record of the signer of two
+// verified commits at the same block number and view id on different blocks,
+// reported by the first key of this node in the committee
+func (consensus *Consensus) buildDoubleSignRecord(
+	first, second *FBFTMessage,
+) (*slash.Record, error) {
+	epoch := new(big.Int).SetUint64(consensus.epoch)
+	if !consensus.ChainReader.Config().IsStaking(epoch) {
+		return nil, errEvidenceNotStaking
+	}
+	signer := shard.FromLibBLSPublicKeyUnsafe(first.SenderPubkey)
+	if signer == nil {
+		return nil, errors.New("cannot get the shard key of the signer")
+	}
+	votes := [2]slash.Vote{}
+	for i, commit := range []*FBFTMessage{first, second} {
+		votes[i] = slash.Vote{
+			SignerPubKey:    *signer,
+			BlockHeaderHash: commit.BlockHash,
+			Signature:       commit.Payload,
+		}
+	}
+
+	shardState, err := consensus.ChainReader.ReadShardState(epoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read the shard state of epoch %v", epoch)
+	}
+	committee, err := shardState.FindCommitteeByID(consensus.ShardID)
+	if err != nil {
+		return nil, err
+	}
+	offender, err := committee.AddressForBLSKey(votes[0].SignerPubKey)
+	if err != nil {
+		return nil, err
+	}
+	var reporterAddr *common.Address
+	for _, key := range consensus.PubKey.PublicKey {
+		if k := shard.FromLibBLSPublicKeyUnsafe(key); k != nil {
+			if addr, err := committee.AddressForBLSKey(*k); err == nil {
+				reporterAddr = addr
+				break
+			}
+		}
+	}
+	if reporterAddr == nil {
+		return nil, errEvidenceNoReporter
+	}
+	if *reporterAddr == *offender {
+		return nil, errEvidenceSelfReported
+	}
+
+	return &slash.Record{
+		Evidence: slash.Evidence{
+			Moment: slash.Moment{
+				Epoch:   epoch,
+				ShardID: consensus.ShardID,
+				Height:  first.BlockNum,
+				ViewID:  first.ViewID,
+			},
+			ConflictingVotes: slash.ConflictingVotes{
+				FirstVote:  votes[0],
+				SecondVote: votes[1],
+			},
+			Offender: *offender,
+		},
+		Reporter: *reporterAddr,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package consensus
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/pkg/errors"
+)
+
+var (
+	errStaleEpoch       = errors.New("message of a previous epoch")
+	errPreStakingFormat = errors.New("message without epoch after the strict message epoch")
+
+	staleEpochCounter       = metrics.NewRegisteredCounter("consensus/rejected/stale_epoch", nil)
+	preStakingFormatCounter = metrics.NewRegisteredCounter("consensus/rejected/pre_staking_format", nil)
+)
+
+// messageEpoch returns the epoch the sender of the message was in, 0 for the
+// messages of the pre-staking format, which carry none
+func messageEpoch(msg *msg_pb.Message) uint64 {
+	if vc := msg.GetViewchange(); vc != nil {
+		return vc.GetEpoch()
+	}
+	return msg.GetConsensus().GetEpoch()
+}
+
+// checkMessageEpoch rejects the messages of the epochs before the one of the
+// block under consensus, and the messages carrying no epoch once the strict
+// message epoch is reached, counting them by reason
+func (consensus *Consensus) checkMessageEpoch(msg *msg_pb.Message) error {
+	msgEpoch, epoch := messageEpoch(msg), consensus.epoch
+	if msgEpoch == 0 {
+		if epoch > 0 && consensus.ChainReader != nil &&
+			consensus.ChainReader.Config().IsStrictMessageEpoch(new(big.Int).SetUint64(epoch)) {
+			preStakingFormatCounter.Inc(1)
+			return errPreStakingFormat
+		}
+		return nil
+	}
+	if msgEpoch < epoch {
+		staleEpochCounter.Inc(1)
+		return errors.Wrapf(errStaleEpoch, "epoch %d", msgEpoch)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/vm"
+	chain2 "github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// newTestChain returns a chain of the config whose genesis holds the given
+// shard state
+func newTestChain(t *testing.T, config *params.ChainConfig, state shard.State) *core.BlockChain {
+	db := ethdb.NewMemDatabase()
+	gspec := core.Genesis{Config: config, Factory: blockfactory.ForTest, ShardState: state}
+	gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, config, chain2.Engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+func TestCheckMessageEpoch(t *testing.T) {
+	consensus := &Consensus{epoch: 5}
+	announce := func(epoch uint64) *msg_pb.Message {
+		return &msg_pb.Message{
+			Type:    msg_pb.MessageType_ANNOUNCE,
+			Request: &msg_pb.Message_Consensus{Consensus: &msg_pb.ConsensusRequest{Epoch: epoch}},
+		}
+	}
+	viewChange := func(epoch uint64) *msg_pb.Message {
+		return &msg_pb.Message{
+			Type:    msg_pb.MessageType_VIEWCHANGE,
+			Request: &msg_pb.Message_Viewchange{Viewchange: &msg_pb.ViewChangeRequest{Epoch: epoch}},
+		}
+	}
+	for _, msg := range []*msg_pb.Message{announce(5), announce(6), viewChange(5)} {
+		if err := consensus.checkMessageEpoch(msg); err != nil {
+			t.Errorf("%s of epoch %d rejected: %v", msg.Type, messageEpoch(msg), err)
+		}
+	}
+	for _, msg := range []*msg_pb.Message{announce(4), viewChange(1)} {
+		if err := consensus.checkMessageEpoch(msg); errors.Cause(err) != errStaleEpoch {
+			t.Errorf("%s of epoch %d not rejected as stale: %v", msg.Type, messageEpoch(msg), err)
+		}
+	}
+	// without chain config, the messages without epoch are accepted
+	if err := consensus.checkMessageEpoch(announce(0)); err != nil {
+		t.Errorf("message without epoch rejected: %v", err)
+	}
+}
+
+func TestCheckMessageEpochStrict(t *testing.T) {
+	config := *params.TestChainConfig
+	config.StrictMessageEpoch = big.NewInt(5)
+	chain := newTestChain(t, &config, shard.State{})
+	defer chain.Stop()
+	withoutEpoch := &msg_pb.Message{
+		Type:    msg_pb.MessageType_ANNOUNCE,
+		Request: &msg_pb.Message_Consensus{Consensus: &msg_pb.ConsensusRequest{}},
+	}
+
+	for _, test := range []struct {
+		epoch    uint64
+		expected error
+	}{
+		{4, nil},
+		{5, errPreStakingFormat},
+		{6, errPreStakingFormat},
+	} {
+		consensus := &Consensus{epoch: test.epoch, ChainReader: chain}
+		if err := consensus.checkMessageEpoch(withoutEpoch); err != test.expected {
+			t.Errorf("epoch %d: expected %v, got %v", test.epoch, test.expected, err)
+		}
+	}
+}
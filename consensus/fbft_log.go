@@ -12,11 +12,17 @@ import (
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
-// FBFTLog represents the log stored by a node during FBFT process
+// FBFTLog represents the log stored by a node during FBFT process. It is
+// synchronized, so the log needs no consensus lock. Beyond maxBlocks blocks
+// and maxLogSize messages in memory, the least recently used ones are spilled
+// to the spill store, and moved back to memory when looked up, or dropped
+// without a spill store.
 type FBFTLog struct {
 	blocks     mapset.Set //store blocks received in FBFT
 	messages   mapset.Set // store messages received in FBFT
 	maxLogSize uint32
+	maxBlocks  int
+	spill      *fbftLogSpill
 }
 
 // FBFTMessage is the record of pbft messages received by a node during FBFT process
@@ -50,130 +56,253 @@ func (m *FBFTMessage) String() string {
 	)
 }
 
-// NewFBFTLog returns new instance of FBFTLog
+// NewFBFTLog returns new instance of FBFTLog, dropping the entries beyond its
+// memory bounds until a spill store is set
 func NewFBFTLog() *FBFTLog {
 	blocks := mapset.NewSet()
 	messages := mapset.NewSet()
 	logSize := maxLogSize
-	pbftLog := FBFTLog{blocks: blocks, messages: messages, maxLogSize: logSize}
+	pbftLog := FBFTLog{
+		blocks:     blocks,
+		messages:   messages,
+		maxLogSize: logSize,
+		maxBlocks:  maxLogBlocks,
+		spill:      newFBFTLogSpill(),
+	}
 	return &pbftLog
 }
 
-// Blocks return the blocks stored in the log
+// Blocks return the blocks stored in memory in the log
 func (log *FBFTLog) Blocks() mapset.Set {
 	return log.blocks
 }
 
-// Messages return the messages stored in the log
+// Messages return the messages stored in memory in the log
 func (log *FBFTLog) Messages() mapset.Set {
 	return log.messages
 }
 
 // AddBlock add a new block into the log
 func (log *FBFTLog) AddBlock(block *types.Block) {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
 	log.blocks.Add(block)
+	log.track(block)
+	log.enforceBounds()
 }
 
-// GetBlockByHash returns the block matches the given block hash
-func (log *FBFTLog) GetBlockByHash(hash common.Hash) *types.Block {
-	var found *types.Block
-	it := log.Blocks().Iterator()
+// findBlocks returns the blocks of matching hash and number, the spilled ones
+// being read outside the lock and moved back to memory
+func (log *FBFTLog) findBlocks(match func(hash common.Hash, number uint64) bool) []*types.Block {
+	log.spill.lock.Lock()
+	found := []*types.Block{}
+	it := log.blocks.Iterator()
 	for block := range it.C {
-		if block.(*types.Block).Header().Hash() == hash {
-			found = block.(*types.Block)
-			it.Stop()
+		if b := block.(*types.Block); match(b.Header().Hash(), b.NumberU64()) {
+			found = append(found, b)
 		}
 	}
+	for _, b := range found {
+		log.touch(b)
+	}
+	hashes, reads := []common.Hash{}, []*spillRead{}
+	for hash, number := range log.spill.blocks {
+		if match(hash, number) {
+			hashes = append(hashes, hash)
+			reads = append(reads, log.spillRead(fbftBlockKey(hash)))
+		}
+	}
+	store := log.spill.store
+	log.spill.lock.Unlock()
+	if len(reads) == 0 {
+		return found
+	}
+
+	readSpilled(store, reads)
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	for i, hash := range hashes {
+		if b := log.loadBlock(hash, reads[i]); b != nil {
+			found = append(found, b)
+		}
+	}
+	log.enforceBounds()
 	return found
 }
 
+// GetBlockByHash returns the block matches the given block hash
+func (log *FBFTLog) GetBlockByHash(hash common.Hash) *types.Block {
+	found := log.findBlocks(func(h common.Hash, _ uint64) bool {
+		return h == hash
+	})
+	if len(found) == 0 {
+		return nil
+	}
+	return found[0]
+}
+
 // GetBlocksByNumber returns the blocks match the given block number
 func (log *FBFTLog) GetBlocksByNumber(number uint64) []*types.Block {
+	return log.findBlocks(func(_ common.Hash, n uint64) bool {
+		return n == number
+	})
+}
+
+// deleteBlocks deletes the blocks of matching number, in memory or spilled
+func (log *FBFTLog) deleteBlocks(match func(number uint64) bool) {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
 	found := []*types.Block{}
-	it := log.Blocks().Iterator()
+	it := log.blocks.Iterator()
 	for block := range it.C {
-		if block.(*types.Block).NumberU64() == number {
-			found = append(found, block.(*types.Block))
+		if b := block.(*types.Block); match(b.NumberU64()) {
+			found = append(found, b)
 		}
 	}
-	return found
+	for _, b := range found {
+		log.blocks.Remove(b)
+		log.untrack(b)
+	}
+	log.deleteSpilledBlocks(match)
+	log.updateGauges()
 }
 
 // DeleteBlocksLessThan deletes blocks less than given block number
 func (log *FBFTLog) DeleteBlocksLessThan(number uint64) {
-	found := mapset.NewSet()
-	it := log.Blocks().Iterator()
-	for block := range it.C {
-		if block.(*types.Block).NumberU64() < number {
-			found.Add(block)
-		}
-	}
-	log.blocks = log.blocks.Difference(found)
+	log.deleteBlocks(func(n uint64) bool { return n < number })
 }
 
 // DeleteBlockByNumber deletes block of specific number
 func (log *FBFTLog) DeleteBlockByNumber(number uint64) {
-	found := mapset.NewSet()
-	it := log.Blocks().Iterator()
-	for block := range it.C {
-		if block.(*types.Block).NumberU64() == number {
-			found.Add(block)
-		}
-	}
-	log.blocks = log.blocks.Difference(found)
+	log.deleteBlocks(func(n uint64) bool { return n == number })
 }
 
 // DeleteMessagesLessThan deletes messages less than given block number
 func (log *FBFTLog) DeleteMessagesLessThan(number uint64) {
-	found := mapset.NewSet()
-	it := log.Messages().Iterator()
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	found := []*FBFTMessage{}
+	it := log.messages.Iterator()
 	for msg := range it.C {
-		if msg.(*FBFTMessage).BlockNum < number {
-			found.Add(msg)
+		if m := msg.(*FBFTMessage); m.BlockNum < number {
+			found = append(found, m)
 		}
 	}
-	log.messages = log.messages.Difference(found)
+	for _, m := range found {
+		log.messages.Remove(m)
+		log.untrack(m)
+	}
+	log.deleteSpilledMessages(func(header *FBFTMessage) bool {
+		return header.BlockNum < number
+	})
+	log.updateGauges()
 }
 
 // AddMessage adds a pbft message into the log
 func (log *FBFTLog) AddMessage(msg *FBFTMessage) {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
 	log.messages.Add(msg)
+	log.track(msg)
+	log.enforceBounds()
 }
 
-// GetMessagesByTypeSeqViewHash returns pbft messages with matching type, blockNum, viewID and blockHash
-func (log *FBFTLog) GetMessagesByTypeSeqViewHash(typ msg_pb.MessageType, blockNum uint64, viewID uint64, blockHash common.Hash) []*FBFTMessage {
+// findMessages returns the messages matching, the spilled ones being read
+// outside the lock and moved back to memory. The spilled ones are matched on
+// their header only.
+func (log *FBFTLog) findMessages(match func(msg *FBFTMessage) bool) []*FBFTMessage {
+	log.spill.lock.Lock()
 	found := []*FBFTMessage{}
-	it := log.Messages().Iterator()
+	it := log.messages.Iterator()
 	for msg := range it.C {
-		if msg.(*FBFTMessage).MessageType == typ && msg.(*FBFTMessage).BlockNum == blockNum && msg.(*FBFTMessage).ViewID == viewID && msg.(*FBFTMessage).BlockHash == blockHash {
-			found = append(found, msg.(*FBFTMessage))
+		if m := msg.(*FBFTMessage); match(m) {
+			found = append(found, m)
+		}
+	}
+	for _, m := range found {
+		log.touch(m)
+	}
+	headers, reads := []*FBFTMessage{}, []*spillRead{}
+	for header, seq := range log.spill.messages {
+		if match(header) {
+			headers = append(headers, header)
+			reads = append(reads, log.spillRead(fbftMessageKey(seq)))
 		}
 	}
+	store := log.spill.store
+	log.spill.lock.Unlock()
+	if len(reads) == 0 {
+		return found
+	}
+
+	readSpilled(store, reads)
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	for i, header := range headers {
+		if m := log.loadMessage(header, reads[i]); m != nil {
+			found = append(found, m)
+		}
+	}
+	log.enforceBounds()
 	return found
 }
 
+// GetMessagesByTypeSeqViewHash returns pbft messages with matching type, blockNum, viewID and blockHash
+func (log *FBFTLog) GetMessagesByTypeSeqViewHash(typ msg_pb.MessageType, blockNum uint64, viewID uint64, blockHash common.Hash) []*FBFTMessage {
+	return log.findMessages(func(m *FBFTMessage) bool {
+		return m.MessageType == typ && m.BlockNum == blockNum && m.ViewID == viewID && m.BlockHash == blockHash
+	})
+}
+
 // GetMessagesByTypeSeq returns pbft messages with matching type, blockNum
 func (log *FBFTLog) GetMessagesByTypeSeq(typ msg_pb.MessageType, blockNum uint64) []*FBFTMessage {
-	found := []*FBFTMessage{}
-	it := log.Messages().Iterator()
-	for msg := range it.C {
-		if msg.(*FBFTMessage).MessageType == typ && msg.(*FBFTMessage).BlockNum == blockNum {
-			found = append(found, msg.(*FBFTMessage))
-		}
-	}
-	return found
+	return log.findMessages(func(m *FBFTMessage) bool {
+		return m.MessageType == typ && m.BlockNum == blockNum
+	})
 }
 
 // GetMessagesByTypeSeqHash returns pbft messages with matching type, blockNum
 func (log *FBFTLog) GetMessagesByTypeSeqHash(typ msg_pb.MessageType, blockNum uint64, blockHash common.Hash) []*FBFTMessage {
-	found := []*FBFTMessage{}
-	it := log.Messages().Iterator()
-	for msg := range it.C {
-		if msg.(*FBFTMessage).MessageType == typ && msg.(*FBFTMessage).BlockNum == blockNum && msg.(*FBFTMessage).BlockHash == blockHash {
-			found = append(found, msg.(*FBFTMessage))
-		}
+	return log.findMessages(func(m *FBFTMessage) bool {
+		return m.MessageType == typ && m.BlockNum == blockNum && m.BlockHash == blockHash
+	})
+}
+
+// GetConflictingCommits returns the commits of the sender of the given commit
+// at the same blockNum and viewID but on another block
+func (log *FBFTLog) GetConflictingCommits(commit *FBFTMessage) []*FBFTMessage {
+	return log.findMessages(func(m *FBFTMessage) bool {
+		return m.MessageType == msg_pb.MessageType_COMMIT &&
+			m.BlockNum == commit.BlockNum && m.ViewID == commit.ViewID &&
+			m.BlockHash != commit.BlockHash &&
+			m.SenderPubkey != nil && m.SenderPubkey.IsEqual(commit.SenderPubkey)
+	})
+}
+
+// DeleteMessage removes a pbft message from the log, along with the spilled
+// messages of the same header
+func (log *FBFTLog) DeleteMessage(msg *FBFTMessage) {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	log.messages.Remove(msg)
+	log.untrack(msg)
+	log.deleteSpilledMessages(func(header *FBFTMessage) bool {
+		return sameHeader(header, msg)
+	})
+	log.updateGauges()
+}
+
+// sameHeader tells whether the messages have the same type, block, view and
+// sender
+func sameHeader(a, b *FBFTMessage) bool {
+	if a.MessageType != b.MessageType || a.BlockNum != b.BlockNum ||
+		a.ViewID != b.ViewID || a.BlockHash != b.BlockHash {
+		return false
 	}
-	return found
+	if a.SenderPubkey == nil || b.SenderPubkey == nil {
+		return a.SenderPubkey == b.SenderPubkey
+	}
+	return a.SenderPubkey.IsEqual(b.SenderPubkey)
 }
 
 // HasMatchingAnnounce returns whether the log contains announce type message with given blockNum, blockHash
@@ -202,15 +331,9 @@ func (log *FBFTLog) HasMatchingViewPrepared(blockNum uint64, viewID uint64, bloc
 
 // GetMessagesByTypeSeqView returns pbft messages with matching type, blockNum and viewID
 func (log *FBFTLog) GetMessagesByTypeSeqView(typ msg_pb.MessageType, blockNum uint64, viewID uint64) []*FBFTMessage {
-	found := []*FBFTMessage{}
-	it := log.Messages().Iterator()
-	for msg := range it.C {
-		if msg.(*FBFTMessage).MessageType != typ || msg.(*FBFTMessage).BlockNum != blockNum || msg.(*FBFTMessage).ViewID != viewID {
-			continue
-		}
-		found = append(found, msg.(*FBFTMessage))
-	}
-	return found
+	return log.findMessages(func(m *FBFTMessage) bool {
+		return m.MessageType == typ && m.BlockNum == blockNum && m.ViewID == viewID
+	})
 }
 
 // FindMessageByMaxViewID returns the message that has maximum ViewID
@@ -329,7 +452,10 @@ func (consensus *Consensus) ParseNewViewMessage(msg *msg_pb.Message) (*FBFTMessa
 			utils.Logger().Warn().Err(err).Msg("ParseViewChangeMessage failed to create mask for multi signature")
 			return nil, err
 		}
-		m3mask.SetMask(vcMsg.M3Bitmap)
+		if err := m3mask.SetMask(vcMsg.M3Bitmap); err != nil {
+			utils.Logger().Warn().Err(err).Msg("ParseNewViewMessage failed to set the M3 bitmap")
+			return nil, err
+		}
 		FBFTMsg.M3AggSig = &m3Sig
 		FBFTMsg.M3Bitmap = m3mask
 	}
@@ -346,7 +472,10 @@ func (consensus *Consensus) ParseNewViewMessage(msg *msg_pb.Message) (*FBFTMessa
 			utils.Logger().Warn().Err(err).Msg("ParseViewChangeMessage failed to create mask for multi signature")
 			return nil, err
 		}
-		m2mask.SetMask(vcMsg.M2Bitmap)
+		if err := m2mask.SetMask(vcMsg.M2Bitmap); err != nil {
+			utils.Logger().Warn().Err(err).Msg("ParseNewViewMessage failed to set the M2 bitmap")
+			return nil, err
+		}
 		FBFTMsg.M2AggSig = &m2Sig
 		FBFTMsg.M2Bitmap = m2mask
 	}
@@ -0,0 +1,558 @@
+package consensus
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/core/types"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+var (
+	fbftLogBlocksGauge        = metrics.NewRegisteredGauge("consensus/fbftlog/blocks", nil)
+	fbftLogMessagesGauge      = metrics.NewRegisteredGauge("consensus/fbftlog/messages", nil)
+	fbftLogSpilledGauge       = metrics.NewRegisteredGauge("consensus/fbftlog/spilled", nil)
+	fbftLogSpillCounter       = metrics.NewRegisteredCounter("consensus/fbftlog/spills", nil)
+	fbftLogSpillFailedCounter = metrics.NewRegisteredCounter("consensus/fbftlog/spills/failed", nil)
+	fbftLogDroppedCounter     = metrics.NewRegisteredCounter("consensus/fbftlog/dropped", nil)
+)
+
+var (
+	fbftBlockPrefix   = []byte("fbft-b")
+	fbftMessagePrefix = []byte("fbft-m")
+)
+
+// fbftLogSpill holds the entries of the log beyond its memory bounds, the
+// least recently used entries being spilled to the store first. The spilled
+// entries are indexed in memory, and written to the store in the background.
+type fbftLogSpill struct {
+	lock sync.Mutex
+	// store spilled to, nil to drop the entries beyond the bounds instead
+	store ethdb.Database
+	// in-memory blocks and messages, the most recently used first
+	recent   *list.List
+	elements map[interface{}]*list.Element
+	// in-memory counts by kind
+	numBlocks, numMessages int
+	// number of the spilled blocks by hash
+	blocks map[common.Hash]uint64
+	// sequence in the store of the spilled messages by their header, the
+	// message without its block, payload and signatures
+	messages map[*FBFTMessage]uint64
+	seq      uint64
+	// spilled entries not written to the store yet, by key
+	unwritten map[string]*spillWrite
+	// keys of the entries no longer spilled, to delete from the store
+	stale [][]byte
+	// whether the store is being written to, idle signalled once done
+	flushing bool
+	idle     *sync.Cond
+}
+
+// spillWrite is an entry spilled, to be written to the store
+type spillWrite struct {
+	entry interface{}
+}
+
+// spillRead is a spilled entry looked up, read from the store outside the
+// spill lock unless not written yet
+type spillRead struct {
+	key   []byte
+	entry interface{}
+	err   error
+}
+
+func newFBFTLogSpill() *fbftLogSpill {
+	spill := &fbftLogSpill{
+		recent:    list.New(),
+		elements:  map[interface{}]*list.Element{},
+		blocks:    map[common.Hash]uint64{},
+		messages:  map[*FBFTMessage]uint64{},
+		unwritten: map[string]*spillWrite{},
+	}
+	spill.idle = sync.NewCond(&spill.lock)
+	return spill
+}
+
+// SetSpillStore sets the store the log spills its entries beyond its memory
+// bounds to, ex: a disk-backed database, closing the previous one. The
+// entries already spilled are lost.
+func (log *FBFTLog) SetSpillStore(store ethdb.Database) {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	log.resetSpill(store)
+}
+
+// Close closes the spill store once the entries spilled are written, the
+// entries beyond the memory bounds being dropped from then on
+func (log *FBFTLog) Close() {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	log.resetSpill(nil)
+}
+
+// resetSpill closes the spill store once written to, and spills to the given
+// one from now on.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) resetSpill(store ethdb.Database) {
+	for log.spill.flushing {
+		log.spill.idle.Wait()
+	}
+	if log.spill.store != nil {
+		log.spill.store.Close()
+	}
+	log.spill.store = store
+	log.spill.blocks = map[common.Hash]uint64{}
+	log.spill.messages = map[*FBFTMessage]uint64{}
+	log.spill.unwritten = map[string]*spillWrite{}
+	log.spill.stale = nil
+	log.updateGauges()
+}
+
+func fbftBlockKey(hash common.Hash) []byte {
+	return append(append([]byte{}, fbftBlockPrefix...), hash[:]...)
+}
+
+func fbftMessageKey(seq uint64) []byte {
+	key := make([]byte, len(fbftMessagePrefix)+8)
+	copy(key, fbftMessagePrefix)
+	binary.BigEndian.PutUint64(key[len(fbftMessagePrefix):], seq)
+	return key
+}
+
+// track records the entry added to the memory as the most recently used.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) track(entry interface{}) {
+	if _, ok := log.spill.elements[entry]; ok {
+		log.touch(entry)
+		return
+	}
+	log.spill.elements[entry] = log.spill.recent.PushFront(entry)
+	switch entry.(type) {
+	case *types.Block:
+		log.spill.numBlocks++
+	case *FBFTMessage:
+		log.spill.numMessages++
+	}
+}
+
+// touch records the in-memory entry as the most recently used.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) touch(entry interface{}) {
+	if e, ok := log.spill.elements[entry]; ok {
+		log.spill.recent.MoveToFront(e)
+	}
+}
+
+// untrack forgets the entry removed from the memory.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) untrack(entry interface{}) {
+	e, ok := log.spill.elements[entry]
+	if !ok {
+		return
+	}
+	log.spill.recent.Remove(e)
+	delete(log.spill.elements, entry)
+	switch entry.(type) {
+	case *types.Block:
+		log.spill.numBlocks--
+	case *FBFTMessage:
+		log.spill.numMessages--
+	}
+}
+
+// enforceBounds spills the least recently used entries until the memory
+// bounds of the log hold, or drops them without a spill store. The spilled
+// entries are written to the store in the background.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) enforceBounds() {
+	defer log.updateGauges()
+	for e := log.spill.recent.Back(); e != nil; {
+		if log.spill.numBlocks <= log.maxBlocks && log.spill.numMessages <= int(log.maxLogSize) {
+			break
+		}
+		prev := e.Prev()
+		switch entry := e.Value.(type) {
+		case *types.Block:
+			if log.spill.numBlocks > log.maxBlocks {
+				log.blocks.Remove(entry)
+				log.untrack(entry)
+				log.spillBlock(entry)
+			}
+		case *FBFTMessage:
+			if log.spill.numMessages > int(log.maxLogSize) && spillable(entry) {
+				log.messages.Remove(entry)
+				log.untrack(entry)
+				log.spillMessage(entry)
+			}
+		}
+		e = prev
+	}
+	log.scheduleFlush()
+}
+
+// spillable tells whether the message can be spilled, the new view messages
+// carrying masks over the committee being kept in memory
+func spillable(msg *FBFTMessage) bool {
+	return msg.M2Bitmap == nil && msg.M3Bitmap == nil
+}
+
+func (log *FBFTLog) updateGauges() {
+	fbftLogBlocksGauge.Update(int64(log.spill.numBlocks))
+	fbftLogMessagesGauge.Update(int64(log.spill.numMessages))
+	fbftLogSpilledGauge.Update(int64(len(log.spill.blocks) + len(log.spill.messages)))
+}
+
+// spillBlock indexes the block removed from the memory as spilled, to be
+// written to the store, or drops it without a store.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) spillBlock(block *types.Block) {
+	if log.spill.store == nil {
+		fbftLogDroppedCounter.Inc(1)
+		return
+	}
+	hash := block.Hash()
+	log.spill.blocks[hash] = block.NumberU64()
+	log.spill.unwritten[string(fbftBlockKey(hash))] = &spillWrite{entry: block}
+	fbftLogSpillCounter.Inc(1)
+}
+
+// spillMessage indexes the message removed from the memory as spilled by its
+// header, to be written to the store, or drops it without a store.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) spillMessage(msg *FBFTMessage) {
+	if log.spill.store == nil {
+		fbftLogDroppedCounter.Inc(1)
+		return
+	}
+	log.spill.seq++
+	header := &FBFTMessage{
+		MessageType:  msg.MessageType,
+		ViewID:       msg.ViewID,
+		BlockNum:     msg.BlockNum,
+		BlockHash:    msg.BlockHash,
+		SenderPubkey: msg.SenderPubkey,
+	}
+	log.spill.messages[header] = log.spill.seq
+	log.spill.unwritten[string(fbftMessageKey(log.spill.seq))] = &spillWrite{entry: msg}
+	fbftLogSpillCounter.Inc(1)
+}
+
+// unspill forgets the entry of the key no longer spilled, deleting it from the
+// store in the background.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) unspill(key []byte) {
+	delete(log.spill.unwritten, string(key))
+	log.spill.stale = append(log.spill.stale, key)
+	log.scheduleFlush()
+}
+
+// scheduleFlush writes the spilled entries to the store in the background,
+// unless already writing.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) scheduleFlush() {
+	if log.spill.flushing || log.spill.store == nil ||
+		len(log.spill.unwritten) == 0 && len(log.spill.stale) == 0 {
+		return
+	}
+	log.spill.flushing = true
+	go log.flush()
+}
+
+// flush writes the spilled entries to the store, and deletes the stale ones,
+// until none is left. The store is written to outside the spill lock, the
+// deletions first for an entry spilled again to be written after.
+func (log *FBFTLog) flush() {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	for len(log.spill.unwritten) > 0 || len(log.spill.stale) > 0 {
+		store, stale := log.spill.store, log.spill.stale
+		writes := make(map[string]*spillWrite, len(log.spill.unwritten))
+		for key, w := range log.spill.unwritten {
+			writes[key] = w
+		}
+		log.spill.stale = nil
+		log.spill.lock.Unlock()
+
+		for _, key := range stale {
+			store.Delete(key)
+		}
+		failed := map[string]error{}
+		for key, w := range writes {
+			if err := writeSpilled(store, []byte(key), w.entry); err != nil {
+				failed[key] = err
+			}
+		}
+
+		log.spill.lock.Lock()
+		for key, w := range writes {
+			if log.spill.unwritten[key] != w {
+				// looked up or deleted meanwhile
+				continue
+			}
+			delete(log.spill.unwritten, key)
+			if err, ok := failed[key]; ok {
+				fbftLogSpillFailedCounter.Inc(1)
+				utils.Logger().Warn().Err(err).Msg("[FBFTLog] cannot spill the log, dropping the entry")
+				log.forgetSpilled([]byte(key))
+			}
+		}
+		log.updateGauges()
+	}
+	log.spill.flushing = false
+	log.spill.idle.Broadcast()
+}
+
+// writeSpilled writes the encoded entry to the store
+func writeSpilled(store ethdb.Database, key []byte, entry interface{}) error {
+	var data []byte
+	var err error
+	switch entry := entry.(type) {
+	case *types.Block:
+		data, err = rlp.EncodeToBytes(entry)
+	case *FBFTMessage:
+		data, err = encodeFBFTMessage(entry)
+	}
+	if err != nil {
+		return err
+	}
+	return store.Put(key, data)
+}
+
+// forgetSpilled removes the entry of the key from the index of the spilled
+// entries.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) forgetSpilled(key []byte) {
+	if bytes.HasPrefix(key, fbftBlockPrefix) {
+		delete(log.spill.blocks, common.BytesToHash(key[len(fbftBlockPrefix):]))
+		return
+	}
+	seq := binary.BigEndian.Uint64(key[len(fbftMessagePrefix):])
+	for header, s := range log.spill.messages {
+		if s == seq {
+			delete(log.spill.messages, header)
+			return
+		}
+	}
+}
+
+// spillRead returns the lookup of the spilled entry of the key, read at once
+// if not written to the store yet.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) spillRead(key []byte) *spillRead {
+	read := &spillRead{key: key}
+	if w, ok := log.spill.unwritten[string(key)]; ok {
+		read.entry = w.entry
+	}
+	return read
+}
+
+// readSpilled reads the spilled entries looked up from the store, without the
+// spill lock held
+func readSpilled(store ethdb.Database, reads []*spillRead) {
+	for _, read := range reads {
+		if read.entry != nil {
+			continue
+		}
+		data, err := store.Get(read.key)
+		if err != nil {
+			read.err = err
+			continue
+		}
+		if bytes.HasPrefix(read.key, fbftBlockPrefix) {
+			block := new(types.Block)
+			if read.err = rlp.DecodeBytes(data, block); read.err == nil {
+				read.entry = block
+			}
+		} else {
+			read.entry, read.err = decodeFBFTMessage(data)
+		}
+	}
+}
+
+// loadBlock moves the spilled block read back to the memory, nil if it cannot
+// be read or is no longer spilled.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) loadBlock(hash common.Hash, read *spillRead) *types.Block {
+	if _, ok := log.spill.blocks[hash]; !ok {
+		// looked up or deleted meanwhile
+		return nil
+	}
+	delete(log.spill.blocks, hash)
+	log.unspill(read.key)
+	block, _ := read.entry.(*types.Block)
+	if block == nil {
+		utils.Logger().Warn().Err(read.err).Str("hash", hash.Hex()).Msg("[FBFTLog] cannot read the spilled block")
+		return nil
+	}
+	log.blocks.Add(block)
+	log.track(block)
+	return block
+}
+
+// deleteSpilledBlocks removes the spilled blocks matching.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) deleteSpilledBlocks(match func(number uint64) bool) {
+	for hash, number := range log.spill.blocks {
+		if match(number) {
+			delete(log.spill.blocks, hash)
+			log.unspill(fbftBlockKey(hash))
+		}
+	}
+}
+
+// fbftMessageRecord is the encoding of a spilled message
+type fbftMessageRecord struct {
+	MessageType   uint32
+	ViewID        uint64
+	BlockNum      uint64
+	BlockHash     common.Hash
+	Block         []byte
+	SenderPubkey  []byte
+	LeaderPubkey  []byte
+	Payload       []byte
+	ViewchangeSig []byte
+	ViewidSig     []byte
+	M2AggSig      []byte
+	M3AggSig      []byte
+}
+
+func serializeKey(key *bls.PublicKey) []byte {
+	if key == nil {
+		return nil
+	}
+	return key.Serialize()
+}
+
+func serializeSig(sig *bls.Sign) []byte {
+	if sig == nil {
+		return nil
+	}
+	return sig.Serialize()
+}
+
+func deserializeKey(data []byte) (*bls.PublicKey, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return bls_cosi.BytesToBLSPublicKey(data)
+}
+
+func deserializeSig(data []byte) (*bls.Sign, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	sig := &bls.Sign{}
+	if err := sig.Deserialize(data); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func encodeFBFTMessage(msg *FBFTMessage) ([]byte, error) {
+	return rlp.EncodeToBytes(&fbftMessageRecord{
+		MessageType:   uint32(msg.MessageType),
+		ViewID:        msg.ViewID,
+		BlockNum:      msg.BlockNum,
+		BlockHash:     msg.BlockHash,
+		Block:         msg.Block,
+		SenderPubkey:  serializeKey(msg.SenderPubkey),
+		LeaderPubkey:  serializeKey(msg.LeaderPubkey),
+		Payload:       msg.Payload,
+		ViewchangeSig: serializeSig(msg.ViewchangeSig),
+		ViewidSig:     serializeSig(msg.ViewidSig),
+		M2AggSig:      serializeSig(msg.M2AggSig),
+		M3AggSig:      serializeSig(msg.M3AggSig),
+	})
+}
+
+func decodeFBFTMessage(data []byte) (*FBFTMessage, error) {
+	record := fbftMessageRecord{}
+	if err := rlp.DecodeBytes(data, &record); err != nil {
+		return nil, err
+	}
+	msg := &FBFTMessage{
+		MessageType: msg_pb.MessageType(record.MessageType),
+		ViewID:      record.ViewID,
+		BlockNum:    record.BlockNum,
+		BlockHash:   record.BlockHash,
+		Block:       record.Block,
+		Payload:     record.Payload,
+	}
+	var err error
+	if msg.SenderPubkey, err = deserializeKey(record.SenderPubkey); err != nil {
+		return nil, err
+	}
+	if msg.LeaderPubkey, err = deserializeKey(record.LeaderPubkey); err != nil {
+		return nil, err
+	}
+	for _, sig := range []struct {
+		to   **bls.Sign
+		data []byte
+	}{
+		{&msg.ViewchangeSig, record.ViewchangeSig},
+		{&msg.ViewidSig, record.ViewidSig},
+		{&msg.M2AggSig, record.M2AggSig},
+		{&msg.M3AggSig, record.M3AggSig},
+	} {
+		if *sig.to, err = deserializeSig(sig.data); err != nil {
+			return nil, err
+		}
+	}
+	return msg, nil
+}
+
+// loadMessage moves the spilled message of the header read back to the
+// memory, nil if it cannot be read or is no longer spilled.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) loadMessage(header *FBFTMessage, read *spillRead) *FBFTMessage {
+	if _, ok := log.spill.messages[header]; !ok {
+		// looked up or deleted meanwhile
+		return nil
+	}
+	delete(log.spill.messages, header)
+	log.unspill(read.key)
+	msg, _ := read.entry.(*FBFTMessage)
+	if msg == nil {
+		utils.Logger().Warn().Err(read.err).Msg("[FBFTLog] cannot read the spilled message")
+		return nil
+	}
+	log.messages.Add(msg)
+	log.track(msg)
+	return msg
+}
+
+// deleteSpilledMessages removes the spilled messages matching.
+//
+// Note, this method assumes the spill lock is held!
+func (log *FBFTLog) deleteSpilledMessages(match func(header *FBFTMessage) bool) {
+	for header, seq := range log.spill.messages {
+		if match(header) {
+			delete(log.spill.messages, header)
+			log.unspill(fbftMessageKey(seq))
+		}
+	}
+}
@@ -1,9 +1,16 @@
 package consensus
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/bls/ffi/go/bls"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/core/types"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
 )
 
 func TestGetMessagesByTypeSeqViewHash(t *testing.T) {
@@ -50,3 +57,246 @@ func TestHasMatchingAnnounce(t *testing.T) {
 		t.Error("notFound should be false")
 	}
 }
+
+func TestGetConflictingCommits(t *testing.T) {
+	signer := bls_cosi.RandPrivateKey().GetPublicKey()
+	other := bls_cosi.RandPrivateKey().GetPublicKey()
+	commit := func(key *bls.PublicKey, hash byte, viewID uint64) *FBFTMessage {
+		return &FBFTMessage{
+			MessageType:  msg_pb.MessageType_COMMIT,
+			BlockNum:     2,
+			ViewID:       viewID,
+			BlockHash:    [32]byte{hash},
+			SenderPubkey: key,
+		}
+	}
+	log := NewFBFTLog()
+	first := commit(signer, 1, 3)
+	log.AddMessage(first)
+	log.AddMessage(commit(other, 2, 3))
+	log.AddMessage(commit(signer, 2, 4))
+
+	found := log.GetConflictingCommits(commit(signer, 2, 3))
+	if len(found) != 1 || found[0] != first {
+		t.Errorf("expected the commit of the same key and view on another block, got %v", found)
+	}
+	if found := log.GetConflictingCommits(commit(signer, 1, 3)); len(found) != 0 {
+		t.Errorf("a commit on the same block is no conflict, got %v", found)
+	}
+
+	log.DeleteMessage(first)
+	if found := log.GetConflictingCommits(commit(signer, 2, 3)); len(found) != 0 {
+		t.Errorf("the deleted commit is still found, got %v", found)
+	}
+}
+
+// blockingStore is a spill store whose writes wait for the release channel
+// to be closed, tracking whether it is closed
+type blockingStore struct {
+	*ethdb.MemDatabase
+	release chan struct{}
+	closed  bool
+}
+
+func (s *blockingStore) Put(key []byte, value []byte) error {
+	<-s.release
+	return s.MemDatabase.Put(key, value)
+}
+
+func (s *blockingStore) Close() {
+	s.closed = true
+}
+
+// newSpillingLog returns a log spilling to a memory store, beyond the given
+// blocks and messages in memory
+func newSpillingLog(maxBlocks int, maxMessages uint32) (*FBFTLog, *ethdb.MemDatabase) {
+	log := NewFBFTLog()
+	log.maxBlocks, log.maxLogSize = maxBlocks, maxMessages
+	store := ethdb.NewMemDatabase()
+	log.SetSpillStore(store)
+	return log, store
+}
+
+// waitFlushed waits for the spilled entries of the log to be written
+func waitFlushed(log *FBFTLog) {
+	log.spill.lock.Lock()
+	defer log.spill.lock.Unlock()
+	for log.spill.flushing {
+		log.spill.idle.Wait()
+	}
+}
+
+func TestFBFTLogSpillBlocks(t *testing.T) {
+	log, store := newSpillingLog(2, maxLogSize)
+	blocks := []*types.Block{}
+	for i := uint64(1); i <= 3; i++ {
+		block := testProposal(i, 1, common.Hash{})
+		blocks = append(blocks, block)
+		log.AddBlock(block)
+	}
+	if n := log.Blocks().Cardinality(); n != 2 {
+		t.Fatalf("expected 2 blocks in memory, got %d", n)
+	}
+	if _, ok := log.spill.blocks[blocks[0].Hash()]; !ok {
+		t.Fatal("expected the least recently used block spilled")
+	}
+	waitFlushed(log)
+	if ok, _ := store.Has(fbftBlockKey(blocks[0].Hash())); !ok {
+		t.Fatal("expected the spilled block written to the store")
+	}
+
+	found := log.GetBlockByHash(blocks[0].Hash())
+	if found == nil || found.Hash() != blocks[0].Hash() {
+		t.Fatalf("cannot find the spilled block, got %v", found)
+	}
+	if n := log.Blocks().Cardinality(); n != 2 {
+		t.Errorf("expected the bound kept after loading, got %d blocks in memory", n)
+	}
+	if _, ok := log.spill.blocks[blocks[1].Hash()]; !ok {
+		t.Error("expected the block least recently used in turn spilled")
+	}
+
+	log.DeleteBlocksLessThan(3)
+	if len(log.spill.blocks) != 0 || len(log.GetBlocksByNumber(2)) != 0 {
+		t.Error("expected the spilled blocks deleted")
+	}
+	waitFlushed(log)
+	if n := store.Len(); n != 0 {
+		t.Errorf("expected the store emptied, got %d entries", n)
+	}
+	if len(log.GetBlocksByNumber(3)) != 1 {
+		t.Error("cannot find the remaining block")
+	}
+}
+
+func TestFBFTLogSpillMessages(t *testing.T) {
+	log, _ := newSpillingLog(maxLogBlocks, 2)
+	key := bls_cosi.RandPrivateKey()
+	sig := key.SignHash([]byte{1})
+	for i := uint64(1); i <= 3; i++ {
+		log.AddMessage(&FBFTMessage{
+			MessageType:   msg_pb.MessageType_VIEWCHANGE,
+			BlockNum:      i,
+			ViewID:        4,
+			Payload:       []byte{byte(i)},
+			SenderPubkey:  key.GetPublicKey(),
+			LeaderPubkey:  key.GetPublicKey(),
+			ViewchangeSig: sig,
+		})
+	}
+	if n := log.Messages().Cardinality(); n != 2 || len(log.spill.messages) != 1 {
+		t.Fatalf("expected 2 messages in memory and 1 spilled, got %d and %d", n, len(log.spill.messages))
+	}
+
+	found := log.GetMessagesByTypeSeqView(msg_pb.MessageType_VIEWCHANGE, 1, 4)
+	if len(found) != 1 {
+		t.Fatalf("cannot find the spilled message, got %v", found)
+	}
+	msg := found[0]
+	if !bytes.Equal(msg.Payload, []byte{1}) ||
+		!msg.SenderPubkey.IsEqual(key.GetPublicKey()) ||
+		!msg.ViewchangeSig.IsEqual(sig) || msg.ViewidSig != nil {
+		t.Errorf("the spilled message is not restored, got %v", msg)
+	}
+
+	log.DeleteMessagesLessThan(3)
+	if n := log.Messages().Cardinality(); n != 1 || len(log.spill.messages) != 0 {
+		t.Errorf("expected the messages deleted, got %d in memory and %d spilled", n, len(log.spill.messages))
+	}
+}
+
+func TestFBFTLogDeleteSpilledMessage(t *testing.T) {
+	key := bls_cosi.RandPrivateKey().GetPublicKey()
+	commit := func(num uint64) *FBFTMessage {
+		return &FBFTMessage{
+			MessageType:  msg_pb.MessageType_COMMIT,
+			BlockNum:     num,
+			ViewID:       1,
+			SenderPubkey: key,
+		}
+	}
+	log, store := newSpillingLog(maxLogBlocks, 1)
+	first := commit(1)
+	log.AddMessage(first)
+	log.AddMessage(commit(2))
+	if len(log.spill.messages) != 1 {
+		t.Fatalf("expected the first commit spilled, got %d spilled", len(log.spill.messages))
+	}
+
+	log.DeleteMessage(first)
+	if len(log.spill.messages) != 0 {
+		t.Error("expected the spilled commit deleted")
+	}
+	if found := log.GetMessagesByTypeSeq(msg_pb.MessageType_COMMIT, 1); len(found) != 0 {
+		t.Errorf("the deleted commit is still found, got %v", found)
+	}
+	waitFlushed(log)
+	if n := store.Len(); n != 0 {
+		t.Errorf("expected the store emptied, got %d entries", n)
+	}
+}
+
+func TestFBFTLogBounds(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		store   bool
+		spilled int
+	}{
+		{"spill store", true, 2},
+		{"no spill store", false, 0},
+	} {
+		log := NewFBFTLog()
+		log.maxLogSize = 1
+		if test.store {
+			log.SetSpillStore(ethdb.NewMemDatabase())
+		}
+		for i := uint64(1); i <= 3; i++ {
+			log.AddMessage(&FBFTMessage{MessageType: msg_pb.MessageType_PREPARE, BlockNum: i})
+		}
+		if n := log.Messages().Cardinality(); n != 1 {
+			t.Errorf("%s: expected 1 message in memory, got %d", test.name, n)
+		}
+		if n := len(log.spill.messages); n != test.spilled {
+			t.Errorf("%s: expected %d messages spilled, got %d", test.name, test.spilled, n)
+		}
+		found := log.GetMessagesByTypeSeq(msg_pb.MessageType_PREPARE, 1)
+		if len(found) != test.spilled/2 {
+			t.Errorf("%s: expected %d messages found, got %d", test.name, test.spilled/2, len(found))
+		}
+		log.Close()
+	}
+}
+
+func TestFBFTLogSpillInBackground(t *testing.T) {
+	store := &blockingStore{MemDatabase: ethdb.NewMemDatabase(), release: make(chan struct{})}
+	log := NewFBFTLog()
+	log.maxBlocks = 1
+	log.SetSpillStore(store)
+	blocks := []*types.Block{testProposal(1, 1, common.Hash{}), testProposal(2, 1, common.Hash{})}
+
+	added := make(chan struct{})
+	go func() {
+		for _, block := range blocks {
+			log.AddBlock(block)
+		}
+		// the block not written yet is read from memory
+		if found := log.GetBlockByHash(blocks[0].Hash()); found != blocks[0] {
+			t.Errorf("cannot find the block being spilled, got %v", found)
+		}
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the log waits for the spill store")
+	}
+
+	close(store.release)
+	log.Close()
+	if !store.closed {
+		t.Error("expected the spill store closed")
+	}
+	if ok, _ := store.Has(fbftBlockKey(blocks[1].Hash())); !ok {
+		t.Error("expected the spilled block written before closing the store")
+	}
+}
@@ -0,0 +1,287 @@
+package consensus
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/shard"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+// headerFirstPrefix prefixes the block of the PREPARED messages proposed
+// header first, no encoded block starting with it
+const headerFirstPrefix byte = 0x01
+
+// fetchedProposalsLimit bounds the fetched blocks waiting for their PREPARED
+// message to be handled again
+const fetchedProposalsLimit = 16
+
+var (
+	// ErrProposalBodyMissing is returned for a block proposed header first
+	// whose body was not fetched yet
+	ErrProposalBodyMissing = errors.New("body of the header first proposal not fetched")
+	errProposalMismatch    = errors.New("block does not match the header first proposal")
+
+	proposalFetchedCounter     = metrics.NewRegisteredCounter("consensus/headerfirst/fetched", nil)
+	proposalFetchFailedCounter = metrics.NewRegisteredCounter("consensus/headerfirst/fetch_failed", nil)
+)
+
+// ProposalFetcher fetches the encoded block of the given hash from the leader
+// or the peers
+type ProposalFetcher func(hash common.Hash) ([]byte, error)
+
+// headerFirstProposal is the block of the PREPARED messages of the shards
+// proposing header first: its header and the hashes of its transactions, the
+// validators fetching the body from the leader or their peers instead of the
+// leader publishing the whole block to all of them
+type headerFirstProposal struct {
+	Header          *block.Header
+	TxHashes        []common.Hash
+	StakingTxHashes []common.Hash
+}
+
+// encodeHeaderFirst returns the header first proposal of the block, prefixed
+func encodeHeaderFirst(b *types.Block) ([]byte, error) {
+	proposal := headerFirstProposal{Header: b.Header()}
+	for _, tx := range b.Transactions() {
+		proposal.TxHashes = append(proposal.TxHashes, tx.Hash())
+	}
+	for _, tx := range b.StakingTransactions() {
+		proposal.StakingTxHashes = append(proposal.StakingTxHashes, tx.Hash())
+	}
+	data, err := rlp.EncodeToBytes(&proposal)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{headerFirstPrefix}, data...), nil
+}
+
+// isHeaderFirst tells whether the block of a PREPARED message is proposed
+// header first
+func isHeaderFirst(data []byte) bool {
+	return len(data) > 0 && data[0] == headerFirstPrefix
+}
+
+func decodeHeaderFirst(data []byte) (*headerFirstProposal, error) {
+	proposal := &headerFirstProposal{}
+	if err := rlp.DecodeBytes(data[1:], proposal); err != nil {
+		return nil, err
+	}
+	if proposal.Header == nil {
+		return nil, errors.New("header first proposal without header")
+	}
+	return proposal, nil
+}
+
+// matches checks the block has the header and the transactions proposed, its
+// receipts and signatures being checked with the block
+func (p *headerFirstProposal) matches(b *types.Block) error {
+	if b.Hash() != p.Header.Hash() {
+		return errors.Wrapf(errProposalMismatch, "block hash %s", b.Hash().Hex())
+	}
+	txs, stakingTxs := b.Transactions(), b.StakingTransactions()
+	if len(txs) != len(p.TxHashes) || len(stakingTxs) != len(p.StakingTxHashes) {
+		return errors.Wrapf(errProposalMismatch,
+			"%d transactions and %d staking transactions", len(txs), len(stakingTxs),
+		)
+	}
+	for i, tx := range txs {
+		if tx.Hash() != p.TxHashes[i] {
+			return errors.Wrapf(errProposalMismatch, "transaction %d", i)
+		}
+	}
+	for i, tx := range stakingTxs {
+		if tx.Hash() != p.StakingTxHashes[i] {
+			return errors.Wrapf(errProposalMismatch, "staking transaction %d", i)
+		}
+	}
+	return nil
+}
+
+// fetchedProposals are the blocks fetched for the header first proposals,
+// kept until their PREPARED message is handled again
+type fetchedProposals struct {
+	lock     sync.Mutex
+	inFlight map[common.Hash]struct{}
+	blocks   *lru.Cache
+}
+
+func newFetchedProposals() *fetchedProposals {
+	blocks, _ := lru.New(fetchedProposalsLimit)
+	return &fetchedProposals{inFlight: map[common.Hash]struct{}{}, blocks: blocks}
+}
+
+// start records the fetching of the block, false if already in flight
+func (f *fetchedProposals) start(hash common.Hash) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, ok := f.inFlight[hash]; ok {
+		return false
+	}
+	f.inFlight[hash] = struct{}{}
+	return true
+}
+
+func (f *fetchedProposals) done(hash common.Hash) {
+	f.lock.Lock()
+	delete(f.inFlight, hash)
+	f.lock.Unlock()
+}
+
+func (f *fetchedProposals) get(hash common.Hash) *types.Block {
+	if b, ok := f.blocks.Get(hash); ok {
+		return b.(*types.Block)
+	}
+	return nil
+}
+
+// proposesHeaderFirst tells whether the block is proposed header first, after
+// the sharding schedule of its epoch
+func (consensus *Consensus) proposesHeaderFirst(b *types.Block) bool {
+	return shard.Schedule.InstanceForEpoch(b.Epoch()).HeaderFirstProposals(consensus.ShardID)
+}
+
+// preparedPayload returns the block carried by the PREPARED messages of the
+// leader, the header first proposal of the block in the shards proposing
+// header first
+func (consensus *Consensus) preparedPayload(b *types.Block, encodedBlock []byte) ([]byte, error) {
+	if !consensus.proposesHeaderFirst(b) {
+		return encodedBlock, nil
+	}
+	return encodeHeaderFirst(b)
+}
+
+// PreparedBlock returns the block of the PREPARED message along with its
+// encoding. The block proposed header first is looked up in the log and among
+// the fetched ones, ErrProposalBodyMissing if not there.
+func (consensus *Consensus) PreparedBlock(recvMsg *FBFTMessage) (*types.Block, []byte, error) {
+	if !isHeaderFirst(recvMsg.Block) {
+		b := &types.Block{}
+		if err := rlp.DecodeBytes(recvMsg.Block, b); err != nil {
+			return nil, nil, err
+		}
+		return b, recvMsg.Block, nil
+	}
+	proposal, err := consensus.headerFirstProposal(recvMsg)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := consensus.FBFTLog.GetBlockByHash(recvMsg.BlockHash)
+	if b == nil || proposal.matches(b) != nil {
+		b = consensus.fetched.get(recvMsg.BlockHash)
+	}
+	if b == nil || proposal.matches(b) != nil {
+		return nil, nil, ErrProposalBodyMissing
+	}
+	encoded, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, encoded, nil
+}
+
+// headerFirstProposal decodes the header first proposal of the PREPARED
+// message, checking it is the block of the message
+func (consensus *Consensus) headerFirstProposal(recvMsg *FBFTMessage) (*headerFirstProposal, error) {
+	proposal, err := decodeHeaderFirst(recvMsg.Block)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Header.Hash() != recvMsg.BlockHash {
+		return nil, errors.Wrapf(errProposalMismatch,
+			"header hash %s", proposal.Header.Hash().Hex(),
+		)
+	}
+	return proposal, nil
+}
+
+// FetchPreparedBlock returns the block of the PREPARED message, fetching the
+// body of the block proposed header first if missing
+func (consensus *Consensus) FetchPreparedBlock(recvMsg *FBFTMessage) (*types.Block, error) {
+	b, _, err := consensus.PreparedBlock(recvMsg)
+	if err != ErrProposalBodyMissing {
+		return b, err
+	}
+	proposal, err := consensus.headerFirstProposal(recvMsg)
+	if err != nil {
+		return nil, err
+	}
+	return consensus.fetchProposal(proposal)
+}
+
+// fetchProposal fetches the block of the header first proposal, kept among
+// the fetched ones if it matches
+func (consensus *Consensus) fetchProposal(proposal *headerFirstProposal) (*types.Block, error) {
+	if consensus.FetchProposal == nil {
+		return nil, errors.New("no proposal fetcher")
+	}
+	hash := proposal.Header.Hash()
+	encoded, err := consensus.FetchProposal(hash)
+	if err != nil {
+		proposalFetchFailedCounter.Inc(1)
+		return nil, err
+	}
+	b := &types.Block{}
+	if err := rlp.DecodeBytes(encoded, b); err != nil {
+		proposalFetchFailedCounter.Inc(1)
+		return nil, err
+	}
+	if err := proposal.matches(b); err != nil {
+		proposalFetchFailedCounter.Inc(1)
+		return nil, err
+	}
+	proposalFetchedCounter.Inc(1)
+	consensus.fetched.blocks.Add(hash, b)
+	return b, nil
+}
+
+// fetchPreparedInBackground fetches the body of the block of the PREPARED
+// message proposed header first, and queues the message again once fetched
+// for the consensus loop not to wait for the peers
+func (consensus *Consensus) fetchPreparedInBackground(msg *msg_pb.Message, recvMsg *FBFTMessage) {
+	proposal, err := consensus.headerFirstProposal(recvMsg)
+	if err != nil {
+		consensus.getLogger().Warn().Err(err).
+			Uint64("MsgBlockNum", recvMsg.BlockNum).
+			Msg("[OnPrepared] Invalid header first proposal")
+		return
+	}
+	hash := proposal.Header.Hash()
+	if !consensus.fetched.start(hash) {
+		return
+	}
+	go func() {
+		defer consensus.fetched.done(hash)
+		if _, err := consensus.fetchProposal(proposal); err != nil {
+			consensus.getLogger().Warn().Err(err).
+				Uint64("MsgBlockNum", recvMsg.BlockNum).
+				Hex("blockHash", hash[:]).
+				Msg("[OnPrepared] Cannot fetch the block proposed header first")
+			return
+		}
+		consensus.EnqueueMessage(msg)
+	}()
+}
+
+// EncodedProposal returns the encoded block of the given hash proposed in the
+// rounds of the log or fetched, nil if unknown, for the peers fetching it
+func (consensus *Consensus) EncodedProposal(hash common.Hash) []byte {
+	b := consensus.FBFTLog.GetBlockByHash(hash)
+	if b == nil {
+		b = consensus.fetched.get(hash)
+	}
+	if b == nil {
+		return nil
+	}
+	encoded, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
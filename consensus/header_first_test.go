@@ -0,0 +1,104 @@
+package consensus
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/pkg/errors"
+)
+
+func testProposalWithTxs(nonces ...uint64) *types.Block {
+	txs := []*types.Transaction{}
+	for _, nonce := range nonces {
+		txs = append(txs, types.NewTransaction(
+			nonce, common.Address{}, 0, big.NewInt(1), 21000, big.NewInt(1), nil,
+		))
+	}
+	return testProposal(5, 1, common.HexToHash("0x1")).WithBody(txs, nil, nil, nil)
+}
+
+func TestPreparedBlock(t *testing.T) {
+	consensus := &Consensus{FBFTLog: NewFBFTLog(), fetched: newFetchedProposals()}
+	block := testProposalWithTxs(1, 2)
+	encoded, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, payload, err := consensus.PreparedBlock(&FBFTMessage{BlockHash: block.Hash(), Block: encoded})
+	if err != nil || found.Hash() != block.Hash() || !bytes.Equal(payload, encoded) {
+		t.Fatalf("cannot get the whole prepared block: %v", err)
+	}
+
+	headerFirst, err := encodeHeaderFirst(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isHeaderFirst(headerFirst) || isHeaderFirst(encoded) {
+		t.Fatal("header first proposal not told from the whole block")
+	}
+	msg := &FBFTMessage{BlockHash: block.Hash(), Block: headerFirst}
+	if _, _, err := consensus.PreparedBlock(msg); err != ErrProposalBodyMissing {
+		t.Errorf("expected the missing body, got %v", err)
+	}
+
+	// another body under the same header is not the block proposed
+	consensus.fetched.blocks.Add(block.Hash(), testProposalWithTxs(1, 3))
+	if _, _, err := consensus.PreparedBlock(msg); err != ErrProposalBodyMissing {
+		t.Errorf("expected the mismatching body ignored, got %v", err)
+	}
+
+	consensus.fetched.blocks.Add(block.Hash(), block)
+	found, payload, err = consensus.PreparedBlock(msg)
+	if err != nil || found.Hash() != block.Hash() || !bytes.Equal(payload, encoded) {
+		t.Fatalf("cannot get the fetched prepared block: %v", err)
+	}
+
+	msg.BlockHash = common.HexToHash("0x2")
+	if _, _, err := consensus.PreparedBlock(msg); errors.Cause(err) != errProposalMismatch {
+		t.Errorf("expected the proposal of another block rejected, got %v", err)
+	}
+}
+
+func TestFetchPreparedBlock(t *testing.T) {
+	consensus := &Consensus{FBFTLog: NewFBFTLog(), fetched: newFetchedProposals()}
+	block := testProposalWithTxs(1)
+	headerFirst, err := encodeHeaderFirst(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := &FBFTMessage{BlockHash: block.Hash(), Block: headerFirst}
+
+	consensus.FetchProposal = func(hash common.Hash) ([]byte, error) {
+		return rlp.EncodeToBytes(testProposalWithTxs(2))
+	}
+	if _, err := consensus.FetchPreparedBlock(msg); errors.Cause(err) != errProposalMismatch {
+		t.Errorf("expected the fetched block of other transactions rejected, got %v", err)
+	}
+
+	consensus.FetchProposal = func(hash common.Hash) ([]byte, error) {
+		if hash != block.Hash() {
+			return nil, errors.New("unknown proposal")
+		}
+		return rlp.EncodeToBytes(block)
+	}
+	found, err := consensus.FetchPreparedBlock(msg)
+	if err != nil || found.Hash() != block.Hash() {
+		t.Fatalf("cannot fetch the prepared block: %v", err)
+	}
+	if !bytes.Equal(consensus.EncodedProposal(block.Hash()), mustEncode(t, block)) {
+		t.Error("the fetched block is not served to the peers")
+	}
+}
+
+func mustEncode(t *testing.T, block *types.Block) []byte {
+	encoded, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
@@ -0,0 +1,91 @@
+package consensus
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/shard"
+)
+
+var (
+	foreignKeysGauge = metrics.NewRegisteredGauge("consensus/keys/foreign", nil)
+	idleKeysGauge    = metrics.NewRegisteredGauge("consensus/keys/idle", nil)
+)
+
+// keyShards is where the keys of the node serve in the epoch of the committee
+// the consensus runs with. Keys elected to other shards than the node's are
+// foreign: the node does not sign with them, and they miss their slots.
+type keyShards struct {
+	lock    sync.RWMutex
+	epoch   *big.Int
+	foreign map[uint32]*multibls.PublicKey
+	idle    []*bls.PublicKey
+}
+
+// updateKeyShards finds the shards the keys of the node serve in the given
+// shard state, and flags those serving other shards
+func (consensus *Consensus) updateKeyShards(state *shard.State, epoch *big.Int) {
+	if state == nil || consensus.PubKey == nil {
+		return
+	}
+	shardOf := map[shard.BLSPublicKey]uint32{}
+	for _, committee := range state.Shards {
+		for _, slot := range committee.Slots {
+			shardOf[slot.BLSPublicKey] = committee.ShardID
+		}
+	}
+	idle := []*bls.PublicKey{}
+	groups := consensus.PubKey.GroupByShard(func(key *bls.PublicKey) (uint32, bool) {
+		k := shard.BLSPublicKey{}
+		if err := k.FromLibBLSPublicKey(key); err != nil {
+			return 0, false
+		}
+		shardID, ok := shardOf[k]
+		if !ok {
+			idle = append(idle, key)
+		}
+		return shardID, ok
+	})
+	delete(groups, consensus.ShardID)
+
+	foreign := 0
+	for shardID, keys := range groups {
+		foreign += len(keys.PublicKey)
+		consensus.getLogger().Warn().
+			Uint64("epoch", epoch.Uint64()).
+			Uint32("keyShard", shardID).
+			Str("keys", keys.SerializeToHexStr()).
+			Msg("[UpdateConsensusInformation] Keys elected to another shard are not served by this node")
+	}
+	foreignKeysGauge.Update(int64(foreign))
+	idleKeysGauge.Update(int64(len(idle)))
+
+	consensus.keyShards.lock.Lock()
+	defer consensus.keyShards.lock.Unlock()
+	consensus.keyShards.epoch = epoch
+	consensus.keyShards.foreign = groups
+	consensus.keyShards.idle = idle
+}
+
+// ForeignKeys returns the keys of the node elected to other shards than the
+// node's, by shard, in the epoch of the current committee
+func (consensus *Consensus) ForeignKeys() (*big.Int, map[uint32]*multibls.PublicKey) {
+	consensus.keyShards.lock.RLock()
+	defer consensus.keyShards.lock.RUnlock()
+	foreign := make(map[uint32]*multibls.PublicKey, len(consensus.keyShards.foreign))
+	for shardID, keys := range consensus.keyShards.foreign {
+		foreign[shardID] = keys
+	}
+	return consensus.keyShards.epoch, foreign
+}
+
+// IdleKeys returns the keys of the node elected to no committee in the epoch
+// of the current committee
+func (consensus *Consensus) IdleKeys() []*bls.PublicKey {
+	consensus.keyShards.lock.RLock()
+	defer consensus.keyShards.lock.RUnlock()
+	return append([]*bls.PublicKey{}, consensus.keyShards.idle...)
+}
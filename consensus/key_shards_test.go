@@ -0,0 +1,73 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/harmony-one/harmony/consensus/quorum"
+	"github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestUpdateKeyShards(t *testing.T) {
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9902"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9902")
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	// the node runs shard 1 with one key there, two keys elected to shard 2
+	// and one key elected nowhere
+	keys := &multibls.PrivateKey{}
+	for i := 0; i < 4; i++ {
+		multibls.AppendPriKey(keys, bls.RandPrivateKey())
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, 1)
+	consensus, err := New(host, 1, leader, keys, decider)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	pubKeys := keys.GetPublicKey().PublicKey
+	slot := func(i int) shard.Slot {
+		s := shard.Slot{}
+		if err := s.BLSPublicKey.FromLibBLSPublicKey(pubKeys[i]); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	state := &shard.State{
+		Epoch: big.NewInt(3),
+		Shards: []shard.Committee{
+			{ShardID: 0, Slots: shard.SlotList{}},
+			{ShardID: 1, Slots: shard.SlotList{slot(0)}},
+			{ShardID: 2, Slots: shard.SlotList{slot(1), slot(2)}},
+		},
+	}
+
+	consensus.updateKeyShards(state, big.NewInt(3))
+	epoch, foreign := consensus.ForeignKeys()
+	if epoch.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("expected the foreign keys of epoch 3, got epoch %v", epoch)
+	}
+	if len(foreign) != 1 || foreign[2] == nil || len(foreign[2].PublicKey) != 2 ||
+		!foreign[2].Contains(pubKeys[1]) || !foreign[2].Contains(pubKeys[2]) {
+		t.Errorf("expected keys 1 and 2 foreign in shard 2, got %v", foreign)
+	}
+	if idle := consensus.IdleKeys(); len(idle) != 1 || !idle[0].IsEqual(pubKeys[3]) {
+		t.Errorf("expected key 3 idle, got %v", idle)
+	}
+
+	// once all the keys are elected to the node's shard, none is flagged
+	state.Shards[1].Slots = shard.SlotList{slot(0), slot(1), slot(2), slot(3)}
+	state.Shards[2].Slots = shard.SlotList{}
+	consensus.updateKeyShards(state, big.NewInt(4))
+	if epoch, foreign := consensus.ForeignKeys(); epoch.Cmp(big.NewInt(4)) != 0 || len(foreign) != 0 {
+		t.Errorf("expected no foreign keys in epoch 4, got %v in epoch %v", foreign, epoch)
+	}
+	if idle := consensus.IdleKeys(); len(idle) != 0 {
+		t.Errorf("expected no idle keys, got %d", len(idle))
+	}
+}
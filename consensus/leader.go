@@ -30,8 +30,15 @@ func (consensus *Consensus) announce(block *types.Block) {
 		return
 	}
 
+	preparedBlock, err := consensus.preparedPayload(block, encodedBlock)
+	if err != nil {
+		consensus.getLogger().Debug().Msg("[Announce] Failed encoding header first proposal")
+		return
+	}
+
 	consensus.block = encodedBlock
 	consensus.blockHeader = encodedBlockHeader
+	consensus.preparedBlock = preparedBlock
 
 	key, err := consensus.GetConsensusLeaderPrivateKey()
 	if err != nil {
@@ -55,6 +62,9 @@ func (consensus *Consensus) announce(block *types.Block) {
 		Uint64("MsgBlockNum", FPBTMsg.BlockNum).
 		Msg("[Announce] Added Announce message in FPBT")
 	consensus.FBFTLog.AddBlock(block)
+	consensus.proposals.add(block)
+	consensus.leaderTracker.proposed(key.GetPublicKey(), FPBTMsg.BlockNum)
+	consensus.proposer.collect()
 
 	// Leader sign the block hash itself
 	for i, key := range consensus.PubKey.PublicKey {
@@ -63,8 +73,8 @@ func (consensus *Consensus) announce(block *types.Block) {
 			key,
 			consensus.priKey.PrivateKey[i].SignHash(consensus.blockHash[:]),
 			common.BytesToHash(consensus.blockHash[:]),
-			consensus.blockNum,
-			consensus.viewID,
+			consensus.BlockNum(),
+			consensus.GetViewID(),
 		); err != nil {
 			return
 		}
@@ -77,7 +87,7 @@ func (consensus *Consensus) announce(block *types.Block) {
 	}
 	// Construct broadcast p2p message
 	if err := consensus.msgSender.SendWithRetry(
-		consensus.blockNum, msg_pb.MessageType_ANNOUNCE, []nodeconfig.GroupID{
+		consensus.BlockNum(), msg_pb.MessageType_ANNOUNCE, []nodeconfig.GroupID{
 			nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(consensus.ShardID)),
 		}, p2p.ConstructMessage(msgToSend)); err != nil {
 		consensus.getLogger().Warn().
@@ -93,7 +103,7 @@ func (consensus *Consensus) announce(block *types.Block) {
 	}
 
 	consensus.getLogger().Debug().
-		Str("From", consensus.phase.String()).
+		Str("From", consensus.Phase().String()).
 		Str("To", FBFTPrepare.String()).
 		Msg("[Announce] Switching phase")
 	consensus.switchPhase(FBFTPrepare, true)
@@ -106,32 +116,31 @@ func (consensus *Consensus) onPrepare(msg *msg_pb.Message) {
 		return
 	}
 
-	if recvMsg.ViewID != consensus.viewID || recvMsg.BlockNum != consensus.blockNum {
+	if recvMsg.ViewID != consensus.GetViewID() || recvMsg.BlockNum != consensus.BlockNum() {
 		consensus.getLogger().Debug().
 			Uint64("MsgViewID", recvMsg.ViewID).
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("blockNum", consensus.BlockNum()).
 			Msg("[OnPrepare] Message ViewId or BlockNum not match")
 		return
 	}
 
 	if !consensus.FBFTLog.HasMatchingViewAnnounce(
-		consensus.blockNum, consensus.viewID, recvMsg.BlockHash,
+		consensus.BlockNum(), consensus.GetViewID(), recvMsg.BlockHash,
 	) {
 		consensus.getLogger().Debug().
 			Uint64("MsgViewID", recvMsg.ViewID).
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("blockNum", consensus.BlockNum()).
 			Msg("[OnPrepare] No Matching Announce message")
 		//return
 	}
 
 	validatorPubKey := recvMsg.SenderPubkey
 	prepareSig := recvMsg.Payload
-	prepareBitmap := consensus.prepareBitmap
 
-	consensus.mutex.Lock()
-	defer consensus.mutex.Unlock()
+	consensus.prepareMutex.Lock()
+	defer consensus.prepareMutex.Unlock()
 	logger := consensus.getLogger().With().
 		Str("validatorPubKey", validatorPubKey.SerializeToHexStr()).Logger()
 
@@ -157,8 +166,45 @@ func (consensus *Consensus) onPrepare(msg *msg_pb.Message) {
 			Msg("[OnPrepare] Failed to deserialize bls signature")
 		return
 	}
-	if !sign.VerifyHash(recvMsg.SenderPubkey, consensus.blockHash[:]) {
-		consensus.getLogger().Error().Msg("[OnPrepare] Received invalid BLS signature")
+	blockHash := consensus.blockHash
+	consensus.voteBatcher.submit(&pendingVote{
+		pubKey: validatorPubKey,
+		sig:    &sign,
+		hash:   blockHash[:],
+		onVerified: func() {
+			consensus.onVerifiedPrepare(recvMsg, &sign, blockHash)
+		},
+		onInvalid: func() {
+			logger.Error().Msg("[OnPrepare] Received invalid BLS signature")
+		},
+	})
+}
+
+// onVerifiedPrepare counts the prepare vote whose signature on blockHash has been verified
+func (consensus *Consensus) onVerifiedPrepare(
+	recvMsg *FBFTMessage, sign *bls.Sign, blockHash [32]byte,
+) {
+	consensus.prepareMutex.Lock()
+	defer consensus.prepareMutex.Unlock()
+
+	validatorPubKey, prepareBitmap := recvMsg.SenderPubkey, consensus.prepareBitmap
+	logger := consensus.getLogger().With().
+		Str("validatorPubKey", validatorPubKey.SerializeToHexStr()).Logger()
+
+	// the round may have moved on while the signature was being verified
+	if recvMsg.ViewID != consensus.GetViewID() || recvMsg.BlockNum != consensus.BlockNum() ||
+		blockHash != consensus.blockHash {
+		logger.Debug().Msg("[OnPrepare] Consensus round changed before the signature was verified")
+		return
+	}
+	if consensus.Decider.ReadBallot(quorum.Prepare, validatorPubKey) != nil {
+		logger.Debug().
+			Msg("[OnPrepare] Already Received prepare message from the validator")
+		return
+	}
+	if consensus.Decider.IsQuorumAchieved(quorum.Prepare) {
+		// already have enough signatures
+		logger.Debug().Msg("[OnPrepare] Received Additional Prepare Message")
 		return
 	}
 
@@ -168,7 +214,7 @@ func (consensus *Consensus) onPrepare(msg *msg_pb.Message) {
 	logger.Info().Msg("[OnPrepare] Received New Prepare Signature")
 	if _, err := consensus.Decider.SubmitVote(
 		quorum.Prepare, validatorPubKey,
-		&sign, recvMsg.BlockHash,
+		sign, recvMsg.BlockHash,
 		recvMsg.BlockNum, recvMsg.ViewID,
 	); err != nil {
 		consensus.getLogger().Warn().Err(err).Msg("submit vote prepare failed")
@@ -181,6 +227,9 @@ func (consensus *Consensus) onPrepare(msg *msg_pb.Message) {
 	}
 
 	if consensus.Decider.IsQuorumAchieved(quorum.Prepare) {
+		// the leader commits to the block itself, starting the commit phase
+		consensus.commitMutex.Lock()
+		defer consensus.commitMutex.Unlock()
 		// NOTE Let it handle its own logs
 		if err := consensus.didReachPrepareQuorum(); err != nil {
 			return
@@ -201,21 +250,18 @@ func (consensus *Consensus) onCommit(msg *msg_pb.Message) {
 		return
 	}
 
-	consensus.mutex.Lock()
-	defer consensus.mutex.Unlock()
+	consensus.commitMutex.Lock()
+	defer consensus.commitMutex.Unlock()
 
 	// Check for potential double signing
 	if consensus.checkDoubleSign(recvMsg) {
 		return
 	}
 
-	validatorPubKey, commitSig, commitBitmap :=
-		recvMsg.SenderPubkey, recvMsg.Payload, consensus.commitBitmap
+	validatorPubKey, commitSig := recvMsg.SenderPubkey, recvMsg.Payload
 	logger := consensus.getLogger().With().
 		Str("validatorPubKey", validatorPubKey.SerializeToHexStr()).Logger()
 
-	// has to be called before verifying signature
-	quorumWasMet := consensus.Decider.IsQuorumAchieved(quorum.Commit)
 	// Verify the signature on commitPayload is correct
 	var sign bls.Sign
 	if err := sign.Deserialize(commitSig); err != nil {
@@ -224,17 +270,46 @@ func (consensus *Consensus) onCommit(msg *msg_pb.Message) {
 	}
 
 	commitPayload := signature.ConstructCommitPayload(consensus.ChainReader,
-		new(big.Int).SetUint64(consensus.epoch), recvMsg.BlockHash, recvMsg.BlockNum, consensus.viewID)
+		new(big.Int).SetUint64(consensus.epoch), recvMsg.BlockHash, recvMsg.BlockNum, consensus.GetViewID())
 	logger = logger.With().
 		Uint64("MsgViewID", recvMsg.ViewID).
 		Uint64("MsgBlockNum", recvMsg.BlockNum).
 		Logger()
 
-	if !sign.VerifyHash(recvMsg.SenderPubkey, commitPayload) {
-		logger.Error().Msg("[OnCommit] Cannot verify commit message")
+	consensus.voteBatcher.submit(&pendingVote{
+		pubKey: validatorPubKey,
+		sig:    &sign,
+		hash:   commitPayload,
+		onVerified: func() {
+			consensus.onVerifiedCommit(recvMsg, &sign)
+		},
+		onInvalid: func() {
+			logger.Error().Msg("[OnCommit] Cannot verify commit message")
+		},
+	})
+}
+
+// onVerifiedCommit counts the commit vote whose signature has been verified
+func (consensus *Consensus) onVerifiedCommit(recvMsg *FBFTMessage, sign *bls.Sign) {
+	consensus.commitMutex.Lock()
+	defer consensus.commitMutex.Unlock()
+
+	validatorPubKey, commitBitmap := recvMsg.SenderPubkey, consensus.commitBitmap
+	logger := consensus.getLogger().With().
+		Str("validatorPubKey", validatorPubKey.SerializeToHexStr()).
+		Uint64("MsgViewID", recvMsg.ViewID).
+		Uint64("MsgBlockNum", recvMsg.BlockNum).
+		Logger()
+
+	// the round may have moved on while the signature was being verified
+	if recvMsg.ViewID != consensus.GetViewID() || recvMsg.BlockNum != consensus.BlockNum() {
+		logger.Debug().Msg("[OnCommit] Consensus round changed before the signature was verified")
 		return
 	}
 
+	// has to be called before submitting the vote
+	quorumWasMet := consensus.Decider.IsQuorumAchieved(quorum.Commit)
+
 	logger = logger.With().
 		Int64("numReceivedSoFar", consensus.Decider.SignersCount(quorum.Commit)).
 		Logger()
@@ -242,7 +317,7 @@ func (consensus *Consensus) onCommit(msg *msg_pb.Message) {
 
 	if _, err := consensus.Decider.SubmitVote(
 		quorum.Commit, validatorPubKey,
-		&sign, recvMsg.BlockHash,
+		sign, recvMsg.BlockHash,
 		recvMsg.BlockNum, recvMsg.ViewID,
 	); err != nil {
 		return
@@ -261,21 +336,21 @@ func (consensus *Consensus) onCommit(msg *msg_pb.Message) {
 			consensus.getLogger().Debug().Msg("[OnCommit] Starting Grace Period")
 			// Always wait for 2 seconds as minimum grace period
 			time.Sleep(2 * time.Second)
-			if n := time.Now(); n.Before(consensus.NextBlockDue) {
+			if due, n := consensus.proposer.nextDue(), time.Now(); n.Before(due) {
 				// Sleep to wait for the full block time
-				time.Sleep(consensus.NextBlockDue.Sub(n))
+				time.Sleep(due.Sub(n))
 			}
 			logger.Debug().Msg("[OnCommit] Commit Grace Period Ended")
-			consensus.commitFinishChan <- viewID
-		}(consensus.viewID)
+			consensus.commitFinishChan.Send(viewID)
+		}(consensus.GetViewID())
 
 		consensus.msgSender.StopRetry(msg_pb.MessageType_PREPARED)
 	}
 
 	if consensus.Decider.IsAllSigsCollected() {
 		go func(viewID uint64) {
-			consensus.commitFinishChan <- viewID
+			consensus.commitFinishChan.Send(viewID)
 			logger.Info().Msg("[OnCommit] 100% Enough commits received")
-		}(consensus.viewID)
+		}(consensus.GetViewID())
 	}
 }
@@ -0,0 +1,140 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/consensus/votepower"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// electsLeader returns whether the leader of the block following the parent,
+// the current head if nil, is elected instead of kept until a view change
+// rotates it through the committee. From the VRF leader election epoch, the
+// leader is drawn from the VRF output of the parent block, or its hash when
+// it has none, and the view ID, with a chance proportional to its voting
+// power.
+func (consensus *Consensus) electsLeader(parent *block.Header) bool {
+	if consensus.ChainReader == nil {
+		return false
+	}
+	if parent == nil {
+		parent = consensus.ChainReader.CurrentHeader()
+	}
+	return consensus.ChainReader.Config().IsVRFLeaderElection(electionEpoch(parent))
+}
+
+// electionEpoch returns the epoch of the block following the parent, the
+// parent holding the shard state of the next epoch if it is the last block of
+// its epoch, unlike the genesis block holding the one of its own
+func electionEpoch(parent *block.Header) *big.Int {
+	if len(parent.ShardState()) > 0 && parent.Number().Sign() > 0 {
+		return new(big.Int).Add(parent.Epoch(), common.Big1)
+	}
+	return parent.Epoch()
+}
+
+// electionSeed derives the randomness of the election of the leader of the
+// block following the parent at the given view
+func electionSeed(parent *block.Header, viewID uint64) common.Hash {
+	source := parent.Hash().Bytes()
+	if vrf := parent.Vrf(); len(vrf) >= 32 {
+		source = vrf[:32]
+	}
+	view := make([]byte, 8)
+	binary.BigEndian.PutUint64(view, viewID)
+	return crypto.Keccak256Hash(source, view)
+}
+
+// electByWeight draws a key with a chance proportional to its weight, the
+// seed picking a point on the cumulated weights in the order of the keys
+func electByWeight(
+	seed common.Hash, keys []shard.BLSPublicKey, weights []*big.Int,
+) (shard.BLSPublicKey, error) {
+	total := big.NewInt(0)
+	for _, w := range weights {
+		total.Add(total, w)
+	}
+	if total.Sign() <= 0 {
+		return shard.BLSPublicKey{}, errors.New("no voting power to elect a leader from")
+	}
+	point := new(big.Int).Mod(new(big.Int).SetBytes(seed[:]), total)
+	for i, w := range weights {
+		if point.Cmp(w) < 0 {
+			return keys[i], nil
+		}
+		point.Sub(point, w)
+	}
+	return keys[len(keys)-1], nil
+}
+
+// electLeader returns the leader elected for the block following the parent
+// at the given view, from the committee of the epoch of that block. Before
+// staking, all the members weigh the same.
+func (consensus *Consensus) electLeader(
+	parent *block.Header, viewID uint64,
+) (*bls.PublicKey, error) {
+	epoch := electionEpoch(parent)
+	shardState, err := consensus.ChainReader.ReadShardState(epoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read shard state of epoch %v", epoch)
+	}
+	committee, err := shardState.FindCommitteeByID(consensus.ShardID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]shard.BLSPublicKey, len(committee.Slots))
+	weights := make([]*big.Int, len(committee.Slots))
+	if consensus.ChainReader.Config().IsStaking(epoch) {
+		roster, err := votepower.Compute(committee, epoch)
+		if err != nil {
+			return nil, err
+		}
+		for i, slot := range committee.Slots {
+			keys[i], weights[i] = slot.BLSPublicKey, big.NewInt(0)
+			if voter, ok := roster.Voters[slot.BLSPublicKey]; ok {
+				weights[i] = voter.OverallPercent.Int
+			}
+		}
+	} else {
+		for i, slot := range committee.Slots {
+			keys[i], weights[i] = slot.BLSPublicKey, common.Big1
+		}
+	}
+
+	elected, err := electByWeight(electionSeed(parent, viewID), keys, weights)
+	if err != nil {
+		return nil, err
+	}
+	leader := new(bls.PublicKey)
+	if err := elected.ToLibBLSPublicKey(leader); err != nil {
+		return nil, err
+	}
+	return leader, nil
+}
+
+// isElectedLeader checks the sender of the announce is the leader elected for
+// its block and view
+func (consensus *Consensus) isElectedLeader(recvMsg *FBFTMessage) error {
+	parent := consensus.ChainReader.GetHeaderByNumber(recvMsg.BlockNum - 1)
+	if parent == nil {
+		return errors.Errorf("unknown parent of block %d", recvMsg.BlockNum)
+	}
+	leader, err := consensus.electLeader(parent, recvMsg.ViewID)
+	if err != nil {
+		return err
+	}
+	if !leader.IsEqual(recvMsg.SenderPubkey) {
+		return errors.Errorf(
+			"announce from %s, elected leader is %s",
+			recvMsg.SenderPubkey.SerializeToHexStr(), leader.SerializeToHexStr(),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	bls2 "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/shard"
+)
+
+// newElectionTestCommittee returns the public keys of n random BLS keys, along
+// with the slots of shard 0 they hold, staked as given if any
+func newElectionTestCommittee(n int, stakes ...int64) ([]*bls.PublicKey, shard.SlotList) {
+	keys := make([]*bls.PublicKey, n)
+	slots := make(shard.SlotList, n)
+	for i := range keys {
+		keys[i] = bls2.RandPrivateKey().GetPublicKey()
+		slots[i].EcdsaAddress = common.Address{byte(i + 1)}
+		slots[i].BLSPublicKey.FromLibBLSPublicKey(keys[i])
+		if i < len(stakes) {
+			stake := numeric.NewDec(stakes[i])
+			slots[i].EffectiveStake = &stake
+		}
+	}
+	return keys, slots
+}
+
+// testElectionParent returns a header of the given epoch holding no shard
+// state, whose next block is of the same epoch
+func testElectionParent(epoch, number int64) *block.Header {
+	return blockfactory.ForTest.NewHeader(big.NewInt(epoch)).With().Number(big.NewInt(number)).Header()
+}
+
+func TestElectByWeight(t *testing.T) {
+	keys := []shard.BLSPublicKey{{1}, {2}, {3}}
+	weights := []*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(3)}
+	tests := []struct {
+		seed   int64
+		expect shard.BLSPublicKey
+	}{
+		{0, keys[0]},
+		{1, keys[2]},
+		{3, keys[2]},
+		{4, keys[0]},
+		{7, keys[2]},
+	}
+	for _, test := range tests {
+		seed := common.BigToHash(big.NewInt(test.seed))
+		elected, err := electByWeight(seed, keys, weights)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if elected != test.expect {
+			t.Errorf("seed %d elected %x, expected %x", test.seed, elected[:1], test.expect[:1])
+		}
+	}
+
+	if _, err := electByWeight(common.Hash{}, keys, []*big.Int{
+		big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	}); err == nil {
+		t.Error("expected an error without voting power")
+	}
+}
+
+func TestElectLeader(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		staking bool
+		stakes  []int64
+		elected func(keys []*bls.PublicKey, seed common.Hash) *bls.PublicKey
+	}{
+		{
+			"pre-staking equal weights", false, nil,
+			func(keys []*bls.PublicKey, seed common.Hash) *bls.PublicKey {
+				n := big.NewInt(int64(len(keys)))
+				return keys[new(big.Int).Mod(new(big.Int).SetBytes(seed[:]), n).Int64()]
+			},
+		},
+		{
+			"staking voting power", true, []int64{0, 0, 100},
+			func(keys []*bls.PublicKey, seed common.Hash) *bls.PublicKey {
+				// the only member holding voting power
+				return keys[2]
+			},
+		},
+	} {
+		config := *params.TestChainConfig
+		if !test.staking {
+			config.StakingEpoch = params.EpochTBD
+		}
+		keys, slots := newElectionTestCommittee(3, test.stakes...)
+		chain := newTestChain(t, &config, shard.State{})
+		// the stakes are only kept in the staking format of the shard state
+		encoded, err := shard.EncodeWrapper(shard.State{
+			Epoch: big.NewInt(1), Shards: []shard.Committee{{ShardID: 0, Slots: slots}},
+		}, test.staking)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.WriteShardStateBytes(chain.ChainDb(), big.NewInt(1), encoded); err != nil {
+			t.Fatal(err)
+		}
+		consensus := &Consensus{ChainReader: chain}
+
+		parent := testElectionParent(1, 14)
+		for viewID := uint64(1); viewID <= 8; viewID++ {
+			leader, err := consensus.electLeader(parent, viewID)
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if expected := test.elected(keys, electionSeed(parent, viewID)); !leader.IsEqual(expected) {
+				t.Errorf("%s: view %d elected %s, expected %s",
+					test.name, viewID, leader.SerializeToHexStr(), expected.SerializeToHexStr())
+			}
+		}
+		chain.Stop()
+	}
+}
+
+func TestIsElectedLeader(t *testing.T) {
+	config := *params.TestChainConfig
+	config.StakingEpoch = params.EpochTBD
+	keys, slots := newElectionTestCommittee(3)
+	chain := newTestChain(t, &config, shard.State{
+		Epoch: big.NewInt(0), Shards: []shard.Committee{{ShardID: 0, Slots: slots}},
+	})
+	defer chain.Stop()
+	consensus := &Consensus{ChainReader: chain}
+	const viewID = 3
+	// the genesis block holds the shard state of its own epoch
+	elected, err := consensus.electLeader(chain.Genesis().Header(), viewID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := keys[0]
+	if other.IsEqual(elected) {
+		other = keys[1]
+	}
+
+	for _, test := range []struct {
+		name     string
+		blockNum uint64
+		sender   *bls.PublicKey
+		rejected bool
+	}{
+		{"elected leader", 1, elected, false},
+		{"other member", 1, other, true},
+		{"unknown parent", 5, elected, true},
+	} {
+		err := consensus.isElectedLeader(&FBFTMessage{
+			BlockNum: test.blockNum, ViewID: viewID, SenderPubkey: test.sender,
+		})
+		if (err != nil) != test.rejected {
+			t.Errorf("%s: expected rejected %t, got %v", test.name, test.rejected, err)
+		}
+	}
+}
+
+func TestElectsLeader(t *testing.T) {
+	config := *params.TestChainConfig
+	config.VRFLeaderElectionEpoch = big.NewInt(2)
+	chain := newTestChain(t, &config, shard.State{})
+	defer chain.Stop()
+	lastOfEpoch := testElectionParent(1, 9)
+	lastOfEpoch.SetShardState([]byte{0x01})
+
+	for _, test := range []struct {
+		name    string
+		chain   bool
+		parent  *block.Header
+		elected bool
+	}{
+		{"no chain", false, testElectionParent(5, 50), false},
+		{"current head", true, nil, false},
+		{"before the fork", true, testElectionParent(1, 8), false},
+		{"last block before the fork", true, lastOfEpoch, true},
+		{"after the fork", true, testElectionParent(2, 10), true},
+	} {
+		consensus := &Consensus{}
+		if test.chain {
+			consensus.ChainReader = chain
+		}
+		if elected := consensus.electsLeader(test.parent); elected != test.elected {
+			t.Errorf("%s: expected the leader elected %t, got %t", test.name, test.elected, elected)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package consensus
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/bls/ffi/go/bls"
+)
+
+// maxTrackedLeaders bounds the number of leaders whose statistics are kept,
+// the least recently seen ones being dropped first
+const maxTrackedLeaders = 1024
+
+var (
+	leaderProposalTimer     = metrics.NewRegisteredTimer("consensus/leader/proposal", nil)
+	leaderViewChangeCounter = metrics.NewRegisteredCounter("consensus/leader/viewchange", nil)
+	leaderMissedCounter     = metrics.NewRegisteredCounter("consensus/leader/missed", nil)
+)
+
+// LeaderStats is the performance of a leader in the rounds it led, as seen by
+// this node since it started
+type LeaderStats struct {
+	Leader          string    `json:"leader"`
+	Rounds          uint64    `json:"rounds"`           // rounds led
+	Proposed        uint64    `json:"proposed"`         // rounds a block was announced in
+	Committed       uint64    `json:"committed"`        // rounds a block was committed in
+	ViewChanges     uint64    `json:"view-changes"`     // rounds ended by a view change
+	MissedProposals uint64    `json:"missed-proposals"` // rounds ended by a view change with no block announced
+	AvgLatency      uint64    `json:"avg-proposal-latency-ms"`
+	Score           float64   `json:"score"` // share of the rounds led that committed a block
+	LastSeen        time.Time `json:"last-seen"`
+	totalLatency    time.Duration
+}
+
+// leaderRound is the round currently led
+type leaderRound struct {
+	leader   string
+	blockNum uint64
+	start    time.Time
+	proposed bool
+}
+
+// leaderTracker collects the statistics of the leaders from the rounds seen
+type leaderTracker struct {
+	mutex sync.Mutex
+	round leaderRound
+	stats map[string]*LeaderStats
+}
+
+func newLeaderTracker() *leaderTracker {
+	return &leaderTracker{stats: map[string]*LeaderStats{}}
+}
+
+// get returns the statistics of the leader, tracking it as necessary. The
+// caller must hold the mutex.
+func (t *leaderTracker) get(leader string) *LeaderStats {
+	s, ok := t.stats[leader]
+	if !ok {
+		if len(t.stats) >= maxTrackedLeaders {
+			var oldest *LeaderStats
+			for _, s := range t.stats {
+				if oldest == nil || s.LastSeen.Before(oldest.LastSeen) {
+					oldest = s
+				}
+			}
+			delete(t.stats, oldest.Leader)
+		}
+		s = &LeaderStats{Leader: leader}
+		t.stats[leader] = s
+	}
+	s.LastSeen = time.Now()
+	return s
+}
+
+// begin notes the start of a round led by the leader, unless it is the
+// current one
+func (t *leaderTracker) begin(leader *bls.PublicKey, blockNum uint64) {
+	if leader == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	key := leader.SerializeToHexStr()
+	if t.round.leader == key && t.round.blockNum == blockNum {
+		return
+	}
+	t.round = leaderRound{leader: key, blockNum: blockNum, start: time.Now()}
+	t.get(key).Rounds++
+}
+
+// proposed notes the announce of the block of the current round by its leader
+func (t *leaderTracker) proposed(leader *bls.PublicKey, blockNum uint64) {
+	if leader == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	key := leader.SerializeToHexStr()
+	if t.round.proposed || t.round.leader != key || t.round.blockNum != blockNum {
+		return
+	}
+	t.round.proposed = true
+	latency := time.Since(t.round.start)
+	leaderProposalTimer.Update(latency)
+	s := t.get(key)
+	s.Proposed++
+	s.totalLatency += latency
+}
+
+// committed notes the commit of a block by the leader
+func (t *leaderTracker) committed(leader *bls.PublicKey) {
+	if leader == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.get(leader.SerializeToHexStr()).Committed++
+}
+
+// viewChanged notes the end of the current round by a view change
+func (t *leaderTracker) viewChanged() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.round.leader == "" {
+		return
+	}
+	s := t.get(t.round.leader)
+	s.ViewChanges++
+	leaderViewChangeCounter.Inc(1)
+	if !t.round.proposed {
+		s.MissedProposals++
+		leaderMissedCounter.Inc(1)
+	}
+	t.round = leaderRound{}
+}
+
+// snapshot returns the statistics of the leaders, best scores first
+func (t *leaderTracker) snapshot() []LeaderStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := make([]LeaderStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		stats := *s
+		if stats.Proposed > 0 {
+			stats.AvgLatency = uint64(stats.totalLatency / time.Duration(stats.Proposed) / time.Millisecond)
+		}
+		if stats.Rounds > 0 {
+			stats.Score = float64(stats.Committed) / float64(stats.Rounds)
+			if stats.Score > 1 {
+				stats.Score = 1 // rounds started before the node joined
+			}
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Leader < result[j].Leader
+	})
+	return result
+}
+
+// LeaderStats returns the performance of the leaders of the rounds seen by
+// this node, best scores first
+func (consensus *Consensus) LeaderStats() []LeaderStats {
+	return consensus.leaderTracker.snapshot()
+}
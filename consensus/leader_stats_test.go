@@ -0,0 +1,44 @@
+package consensus
+
+import (
+	"testing"
+
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+)
+
+func TestLeaderTracker(t *testing.T) {
+	tracker := newLeaderTracker()
+	good := bls_cosi.RandPrivateKey().GetPublicKey()
+	bad := bls_cosi.RandPrivateKey().GetPublicKey()
+
+	// good leads and commits block 10
+	tracker.begin(good, 10)
+	tracker.begin(good, 10) // the same round seen again
+	tracker.proposed(good, 10)
+	tracker.proposed(good, 10)
+	tracker.committed(good)
+	// bad leads block 11 without proposing, then good takes over
+	tracker.begin(bad, 11)
+	tracker.viewChanged()
+	tracker.begin(good, 11)
+	tracker.proposed(bad, 11) // not the leader of the round
+	tracker.proposed(good, 11)
+	tracker.committed(good)
+
+	stats := tracker.snapshot()
+	if len(stats) != 2 {
+		t.Fatalf("got stats of %d leaders, want 2", len(stats))
+	}
+	first, second := stats[0], stats[1]
+	if first.Leader != good.SerializeToHexStr() {
+		t.Fatalf("best leader is not the one committing")
+	}
+	if first.Rounds != 2 || first.Proposed != 2 || first.Committed != 2 ||
+		first.ViewChanges != 0 || first.Score != 1 {
+		t.Errorf("unexpected stats of good leader %+v", first)
+	}
+	if second.Rounds != 1 || second.Proposed != 0 || second.Committed != 0 ||
+		second.ViewChanges != 1 || second.MissedProposals != 1 || second.Score != 0 {
+		t.Errorf("unexpected stats of bad leader %+v", second)
+	}
+}
@@ -0,0 +1,212 @@
+// Package ledger keeps the on-disk ledger of the votes cast in the committed
+// consensus rounds, for auditing the behavior of validators over time.
+package ledger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultRetention is the number of blocks the records are kept for
+	DefaultRetention = 864000
+	// MaxRange is the maximum number of records read at once
+	MaxRange = 1000
+	// maxPrunePerPut bounds the records pruned when a record is put, so that
+	// a large backlog is pruned over several rounds
+	maxPrunePerPut = 256
+)
+
+var errInvalidRange = errors.New("invalid block range")
+
+// Record is the record of the votes cast in the consensus round of a block.
+// The votes are kept as bitmaps over the committee of the epoch.
+type Record struct {
+	BlockNum      uint64
+	BlockHash     common.Hash
+	Epoch         uint64
+	ViewID        uint64
+	ViewChanges   uint64 // view changes in the round before the block was committed
+	Leader        shard.BLSPublicKey
+	PrepareBitmap []byte
+	CommitBitmap  []byte
+	StartedAt     uint64 // unix milliseconds the round started at, 0 if unknown
+	CommittedAt   uint64 // unix milliseconds the block was committed at
+}
+
+// Ledger persists the records of the committed rounds, keeping those of the
+// last retention blocks.
+type Ledger struct {
+	db        ethdb.Database
+	retention uint64
+	lock      sync.Mutex
+}
+
+// New returns the vote ledger kept in the given database
+func New(db ethdb.Database, retention uint64) *Ledger {
+	return &Ledger{db: db, retention: retention}
+}
+
+// Put stores the record of a round, and prunes the records which fell out of
+// the retention.
+func (l *Ledger) Put(r *Record) error {
+	data, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := rawdb.WriteVoteRecord(l.db, r.BlockNum, data); err != nil {
+		return err
+	}
+	tail, ok := rawdb.ReadVoteLedgerTail(l.db)
+	if !ok {
+		return rawdb.WriteVoteLedgerTail(l.db, r.BlockNum)
+	}
+	if l.retention == 0 || r.BlockNum < tail+l.retention {
+		return nil
+	}
+	pruned := 0
+	for ; tail+l.retention <= r.BlockNum && pruned < maxPrunePerPut; tail++ {
+		rawdb.DeleteVoteRecord(l.db, tail)
+		pruned++
+	}
+	utils.Logger().Debug().
+		Int("pruned", pruned).
+		Uint64("tail", tail).
+		Msg("[VoteLedger] Pruned vote records")
+	return rawdb.WriteVoteLedgerTail(l.db, tail)
+}
+
+// Get returns the record of the round of the given block, if any
+func (l *Ledger) Get(blockNum uint64) (*Record, error) {
+	return Read(l.db, blockNum)
+}
+
+// Read returns the record of the round of the given block from the database
+func Read(db ethdb.Database, blockNum uint64) (*Record, error) {
+	data, err := rawdb.ReadVoteRecord(db, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	r := &Record{}
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Range returns the records kept of the rounds of the blocks from..to
+func Range(db ethdb.Database, from, to uint64) ([]*Record, error) {
+	if to < from || to-from >= MaxRange {
+		return nil, errors.Wrapf(errInvalidRange, "%d..%d, at most %d blocks", from, to, MaxRange)
+	}
+	records := []*Record{}
+	for num := from; num <= to; num++ {
+		if r, err := Read(db, num); err == nil {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// Entry is the exported form of a record, with the votes resolved to the keys
+// of the committee
+type Entry struct {
+	BlockNum    uint64   `json:"block-num"`
+	BlockHash   string   `json:"block-hash"`
+	Epoch       uint64   `json:"epoch"`
+	ViewID      uint64   `json:"view-id"`
+	ViewChanges uint64   `json:"view-changes"`
+	Leader      string   `json:"leader"`
+	Prepared    []string `json:"prepared"`
+	Committed   []string `json:"committed"`
+	Absent      []string `json:"absent"`
+	StartedAt   uint64   `json:"started-at"`
+	CommittedAt uint64   `json:"committed-at"`
+}
+
+// Export resolves the votes of the record against the committee of its epoch
+func (r *Record) Export(committee *shard.Committee) *Entry {
+	e := &Entry{
+		BlockNum:    r.BlockNum,
+		BlockHash:   r.BlockHash.Hex(),
+		Epoch:       r.Epoch,
+		ViewID:      r.ViewID,
+		ViewChanges: r.ViewChanges,
+		Leader:      r.Leader.Hex(),
+		Prepared:    []string{},
+		Committed:   []string{},
+		Absent:      []string{},
+		StartedAt:   r.StartedAt,
+		CommittedAt: r.CommittedAt,
+	}
+	for i, slot := range committee.Slots {
+		key := slot.BLSPublicKey.Hex()
+		if bitEnabled(r.PrepareBitmap, i) {
+			e.Prepared = append(e.Prepared, key)
+		}
+		if bitEnabled(r.CommitBitmap, i) {
+			e.Committed = append(e.Committed, key)
+		} else {
+			e.Absent = append(e.Absent, key)
+		}
+	}
+	return e
+}
+
+// bitEnabled tells whether the bit of the given index is set in a bitmap laid
+// out as the bls.Mask ones
+func bitEnabled(bitmap []byte, i int) bool {
+	if i>>3 >= len(bitmap) {
+		return false
+	}
+	return bitmap[i>>3]&(byte(1)<<uint(i&7)) != 0
+}
+
+// csvHeader is the header row of the CSV export
+var csvHeader = []string{
+	"block-num", "block-hash", "epoch", "view-id", "view-changes", "leader",
+	"prepared", "committed", "absent", "started-at", "committed-at",
+}
+
+// CSV renders the entries as CSV, with one row per round and the keys of a
+// vote column separated by spaces
+func CSV(entries []*Entry) (string, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write(csvHeader); err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		row := []string{
+			strconv.FormatUint(e.BlockNum, 10),
+			e.BlockHash,
+			strconv.FormatUint(e.Epoch, 10),
+			strconv.FormatUint(e.ViewID, 10),
+			strconv.FormatUint(e.ViewChanges, 10),
+			e.Leader,
+			strings.Join(e.Prepared, " "),
+			strings.Join(e.Committed, " "),
+			strings.Join(e.Absent, " "),
+			strconv.FormatUint(e.StartedAt, 10),
+			strconv.FormatUint(e.CommittedAt, 10),
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
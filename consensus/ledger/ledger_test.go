@@ -0,0 +1,62 @@
+package ledger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestPutPrunesBeyondRetention(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	l := New(db, 10)
+	for num := uint64(1); num <= 30; num++ {
+		if err := l.Put(&Record{BlockNum: num}); err != nil {
+			t.Fatalf("put record %d: %v", num, err)
+		}
+	}
+	for num := uint64(1); num <= 30; num++ {
+		_, err := l.Get(num)
+		if kept := num > 20; kept != (err == nil) {
+			t.Errorf("record %d: kept %v, got error %v", num, kept, err)
+		}
+	}
+	records, err := Range(db, 1, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 10 || records[0].BlockNum != 21 {
+		t.Errorf("unexpected range of %d records", len(records))
+	}
+	if _, err := Range(db, 0, MaxRange); err == nil {
+		t.Error("expected an error for a range over MaxRange")
+	}
+}
+
+func TestExport(t *testing.T) {
+	committee := &shard.Committee{Slots: make(shard.SlotList, 10)}
+	for i := range committee.Slots {
+		committee.Slots[i].BLSPublicKey[0] = byte(i)
+	}
+	record := &Record{
+		BlockNum:      5,
+		PrepareBitmap: []byte{0xff, 0x03},
+		CommitBitmap:  []byte{0x0f, 0x02},
+	}
+	entry := record.Export(committee)
+	if len(entry.Prepared) != 10 || len(entry.Committed) != 5 || len(entry.Absent) != 5 {
+		t.Errorf("prepared %d, committed %d, absent %d",
+			len(entry.Prepared), len(entry.Committed), len(entry.Absent))
+	}
+	if entry.Committed[4] != committee.Slots[9].BLSPublicKey.Hex() {
+		t.Errorf("committed %s, expected the key of slot 9", entry.Committed[4])
+	}
+	out, err := CSV([]*Entry{entry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 {
+		t.Errorf("expected a header and a row, got %d lines", len(lines))
+	}
+}
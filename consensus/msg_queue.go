@@ -0,0 +1,86 @@
+package consensus
+
+import (
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+)
+
+// MsgPriority is the priority in which consensus messages are handled, lower
+// values first
+type MsgPriority int
+
+// Priorities of the consensus messages
+const (
+	// PriorityFinality is the priority of COMMITTED and NEWVIEW messages
+	PriorityFinality MsgPriority = iota
+	// PriorityQuorum is the priority of PREPARED and VIEWCHANGE messages
+	PriorityQuorum
+	// PriorityAnnounce is the priority of ANNOUNCE messages
+	PriorityAnnounce
+	// PriorityOther is the priority of all other messages
+	PriorityOther
+	// NumMsgPriorities is the number of message priorities
+	NumMsgPriorities
+)
+
+// msgQueueSize is the capacity of the queue of each message priority
+const msgQueueSize = 256
+
+func (p MsgPriority) String() string {
+	switch p {
+	case PriorityFinality:
+		return "finality"
+	case PriorityQuorum:
+		return "quorum"
+	case PriorityAnnounce:
+		return "announce"
+	}
+	return "other"
+}
+
+// PriorityOf returns the priority of the consensus message
+func PriorityOf(msg *msg_pb.Message) MsgPriority {
+	switch msg.Type {
+	case msg_pb.MessageType_COMMITTED, msg_pb.MessageType_NEWVIEW:
+		return PriorityFinality
+	case msg_pb.MessageType_PREPARED, msg_pb.MessageType_VIEWCHANGE:
+		return PriorityQuorum
+	case msg_pb.MessageType_ANNOUNCE:
+		return PriorityAnnounce
+	}
+	return PriorityOther
+}
+
+func newMsgQueues() [NumMsgPriorities]chan *msg_pb.Message {
+	var queues [NumMsgPriorities]chan *msg_pb.Message
+	for i := range queues {
+		queues[i] = make(chan *msg_pb.Message, msgQueueSize)
+	}
+	return queues
+}
+
+// EnqueueMessage queues the message for the consensus loop, blocking while
+// the queue of its priority is full
+func (consensus *Consensus) EnqueueMessage(msg *msg_pb.Message) {
+	consensus.msgQueues[PriorityOf(msg)] <- msg
+}
+
+// handleQueuedMessage handles the given message after all the queued messages
+// of higher priority
+func (consensus *Consensus) handleQueuedMessage(msg *msg_pb.Message) {
+	for p := PriorityFinality; p < PriorityOf(msg); p++ {
+		consensus.drainMsgQueue(p)
+	}
+	consensus.handleMessageUpdate(msg)
+}
+
+// drainMsgQueue handles the queued messages of the given priority
+func (consensus *Consensus) drainMsgQueue(p MsgPriority) {
+	for {
+		select {
+		case msg := <-consensus.msgQueues[p]:
+			consensus.handleMessageUpdate(msg)
+		default:
+			return
+		}
+	}
+}
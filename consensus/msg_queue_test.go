@@ -0,0 +1,28 @@
+package consensus
+
+import (
+	"testing"
+
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+)
+
+func TestPriorityOf(t *testing.T) {
+	tests := []struct {
+		msgType msg_pb.MessageType
+		want    MsgPriority
+	}{
+		{msg_pb.MessageType_COMMITTED, PriorityFinality},
+		{msg_pb.MessageType_NEWVIEW, PriorityFinality},
+		{msg_pb.MessageType_PREPARED, PriorityQuorum},
+		{msg_pb.MessageType_VIEWCHANGE, PriorityQuorum},
+		{msg_pb.MessageType_ANNOUNCE, PriorityAnnounce},
+		{msg_pb.MessageType_PREPARE, PriorityOther},
+		{msg_pb.MessageType_COMMIT, PriorityOther},
+	}
+	for _, test := range tests {
+		msg := &msg_pb.Message{Type: test.msgType}
+		if got := PriorityOf(msg); got != test.want {
+			t.Errorf("PriorityOf(%v) = %v, want %v", test.msgType, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package consensus
+
+import (
+	"encoding/binary"
+
+	"github.com/harmony-one/bls/ffi/go/bls"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+var (
+	errNewViewNoM3           = errors.New("NEWVIEW without M3 (viewID) aggregate")
+	errNewViewM3NoQuorum     = errors.New("NEWVIEW M3 (viewID) aggregate without quorum")
+	errNewViewInvalidM3Sig   = errors.New("invalid NEWVIEW M3 (viewID) aggregate signature")
+	errNewViewNoM2Bitmap     = errors.New("NEWVIEW M2 (NIL) aggregate without bitmap")
+	errNewViewInvalidM2Sig   = errors.New("invalid NEWVIEW M2 (NIL) aggregate signature")
+	errNewViewM2NotInM3      = errors.New("NEWVIEW M2 (NIL) signers missing from the M3 signers")
+	errNewViewNoM1           = errors.New("NEWVIEW without the M1 (prepared) payload its M3 signers require")
+	errNewViewM1NoQuorum     = errors.New("NEWVIEW M1 (prepared) aggregate without quorum")
+	errNewViewInvalidM1Sig   = errors.New("invalid NEWVIEW M1 (prepared) aggregate signature")
+	errNewViewBitmapMismatch = errors.New("NEWVIEW aggregates over different committees")
+)
+
+// newViewM1 is the prepared block carried by a NEWVIEW message
+type newViewM1 struct {
+	blockHash []byte
+	aggSig    *bls.Sign
+	mask      *bls_cosi.Mask
+}
+
+// verifyNewView checks the aggregates of a NEWVIEW message: the M3 (viewID)
+// signers reach quorum, the M2 (NIL) signers are among them, and the
+// M3 signers which did not sign NIL back a M1 (prepared) block itself signed
+// by a quorum. It returns the prepared block if the message carries one.
+func (consensus *Consensus) verifyNewView(recvMsg *FBFTMessage) (*newViewM1, error) {
+	m3Sig, m3Mask := recvMsg.M3AggSig, recvMsg.M3Bitmap
+	if m3Sig == nil || m3Mask == nil {
+		return nil, errNewViewNoM3
+	}
+	if !consensus.Decider.IsQuorumAchievedByMask(m3Mask) {
+		return nil, errNewViewM3NoQuorum
+	}
+	viewIDBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(viewIDBytes, recvMsg.ViewID)
+	if !m3Sig.VerifyHash(m3Mask.AggregatePublic, viewIDBytes) {
+		return nil, errNewViewInvalidM3Sig
+	}
+
+	m2Mask := recvMsg.M2Bitmap
+	if recvMsg.M2AggSig != nil {
+		if m2Mask == nil || m2Mask.Bitmap == nil {
+			return nil, errNewViewNoM2Bitmap
+		}
+		if !recvMsg.M2AggSig.VerifyHash(m2Mask.AggregatePublic, NIL) {
+			return nil, errNewViewInvalidM2Sig
+		}
+		if len(m2Mask.Bitmap) != len(m3Mask.Bitmap) {
+			return nil, errNewViewBitmapMismatch
+		}
+		for i := range m2Mask.Bitmap {
+			if m2Mask.Bitmap[i]&^m3Mask.Bitmap[i] != 0 {
+				return nil, errNewViewM2NotInM3
+			}
+		}
+	} else {
+		m2Mask = nil
+	}
+
+	// the M3 signers which did not sign NIL signed a prepared block
+	if m2Mask != nil && utils.CountOneBits(m3Mask.Bitmap) <= utils.CountOneBits(m2Mask.Bitmap) {
+		return nil, nil
+	}
+	if len(recvMsg.Payload) <= 32 {
+		return nil, errNewViewNoM1
+	}
+	blockHash := recvMsg.Payload[:32]
+	aggSig, mask, err := consensus.ReadSignatureBitmapPayload(recvMsg.Payload, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read NEWVIEW M1 (prepared) aggregate")
+	}
+	if !consensus.Decider.IsQuorumAchievedByMask(mask) {
+		return nil, errNewViewM1NoQuorum
+	}
+	if !aggSig.VerifyHash(mask.AggregatePublic, blockHash) {
+		return nil, errNewViewInvalidM1Sig
+	}
+	return &newViewM1{blockHash: blockHash, aggSig: aggSig, mask: mask}, nil
+}
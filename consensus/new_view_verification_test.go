@@ -0,0 +1,105 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/consensus/quorum"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestVerifyNewView(t *testing.T) {
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9904"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9904")
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := New(
+		host, shard.BeaconChainShardID, leader, multibls.GetPrivateKey(bls_cosi.RandPrivateKey()), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	keys := make([]*bls.SecretKey, 4)
+	pubKeys := make([]*bls.PublicKey, len(keys))
+	for i := range keys {
+		keys[i] = bls_cosi.RandPrivateKey()
+		pubKeys[i] = keys[i].GetPublicKey()
+	}
+	consensus.Decider.UpdateParticipants(pubKeys)
+
+	const viewID = 7
+	viewIDBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(viewIDBytes, viewID)
+	blockHash := [32]byte{1}
+	// aggregate signs the payload with the keys of the given indexes
+	aggregate := func(payload []byte, signers ...int) (*bls.Sign, *bls_cosi.Mask) {
+		mask, _ := bls_cosi.NewMask(pubKeys, nil)
+		sigs := []*bls.Sign{}
+		for _, i := range signers {
+			sigs = append(sigs, keys[i].SignHash(payload))
+			mask.SetKey(pubKeys[i], true)
+		}
+		return bls_cosi.AggregateSig(sigs), mask
+	}
+	m1Payload := func(signers ...int) []byte {
+		sig, mask := aggregate(blockHash[:], signers...)
+		payload := append(blockHash[:], sig.Serialize()...)
+		return append(payload, mask.Bitmap...)
+	}
+	newView := func(m3, m2 []int, payload []byte) *FBFTMessage {
+		msg := &FBFTMessage{ViewID: viewID, Payload: payload}
+		msg.M3AggSig, msg.M3Bitmap = aggregate(viewIDBytes, m3...)
+		if len(m2) > 0 {
+			msg.M2AggSig, msg.M2Bitmap = aggregate(NIL, m2...)
+		}
+		return msg
+	}
+
+	forgedM3 := newView([]int{0, 1, 2}, []int{0, 1, 2}, nil)
+	forgedM3.M3AggSig, _ = aggregate(viewIDBytes, 0, 1, 3)
+	forgedM2 := newView([]int{0, 1, 2}, []int{0, 1, 2}, nil)
+	forgedM2.M2AggSig, _ = aggregate([]byte("not nil"), 0, 1, 2)
+	noM2Bitmap := newView([]int{0, 1, 2}, []int{0, 1, 2}, nil)
+	noM2Bitmap.M2Bitmap = nil
+	noM3 := newView([]int{0, 1, 2}, nil, m1Payload(0, 1, 2))
+	noM3.M3AggSig = nil
+	forgedM1 := m1Payload(0, 1, 2)
+	forgedM1[40] ^= 0xff
+
+	tests := []struct {
+		name string
+		msg  *FBFTMessage
+		err  error
+		m1   bool
+	}{
+		{"all nil", newView([]int{0, 1, 2}, []int{0, 1, 2}, nil), nil, false},
+		{"prepared", newView([]int{0, 1, 2, 3}, []int{3}, m1Payload(0, 1, 2)), nil, true},
+		{"no m3", noM3, errNewViewNoM3, false},
+		{"m3 without quorum", newView([]int{0, 1}, []int{0, 1}, nil), errNewViewM3NoQuorum, false},
+		{"forged m3", forgedM3, errNewViewInvalidM3Sig, false},
+		{"forged m2", forgedM2, errNewViewInvalidM2Sig, false},
+		{"m2 without bitmap", noM2Bitmap, errNewViewNoM2Bitmap, false},
+		{"m2 beyond m3", newView([]int{0, 1, 2}, []int{2, 3}, m1Payload(0, 1, 2)), errNewViewM2NotInM3, false},
+		{"missing m1", newView([]int{0, 1, 2}, []int{0}, nil), errNewViewNoM1, false},
+		{"m1 without quorum", newView([]int{0, 1, 2}, []int{0}, m1Payload(1, 2)), errNewViewM1NoQuorum, false},
+	}
+	for _, test := range tests {
+		m1, err := consensus.verifyNewView(test.msg)
+		if err != test.err || (m1 != nil) != test.m1 {
+			t.Errorf("%s: got prepared block %v, error %v, expected %v", test.name, m1 != nil, err, test.err)
+		}
+	}
+	if _, err := consensus.verifyNewView(
+		newView([]int{0, 1, 2}, nil, forgedM1),
+	); err == nil {
+		t.Error("forged m1 accepted")
+	}
+}
@@ -0,0 +1,109 @@
+package consensus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// PhaseEventKind is the kind of a consensus transition
+type PhaseEventKind string
+
+const (
+	// PhaseChanged is a move to another FBFT phase, ex: Announce to Prepare
+	PhaseChanged PhaseEventKind = "phase"
+	// BlockFinalized is the commit of the block of a round
+	BlockFinalized PhaseEventKind = "finalize"
+	// ViewChangeStarted is the start of a view change
+	ViewChangeStarted PhaseEventKind = "view-change"
+	// NewViewAccepted is the end of a view change on a NEWVIEW message
+	NewViewAccepted PhaseEventKind = "new-view"
+	// ModeChanged is a move to another mode, ex: Normal to Syncing
+	ModeChanged PhaseEventKind = "mode"
+)
+
+// PhaseEvent is a transition of the consensus of a shard. Duration is the
+// time spent in From: in the previous phase or mode, or since the start of
+// the round for a finalized block, or of the view change for a new view.
+type PhaseEvent struct {
+	Kind     PhaseEventKind `json:"kind"`
+	ShardID  uint32         `json:"shard-id"`
+	BlockNum uint64         `json:"block-num"`
+	ViewID   uint64         `json:"view-id"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Time     time.Time      `json:"time"`
+	Duration time.Duration  `json:"duration"`
+}
+
+// phaseEvents tracks when the consensus entered its current phase, mode,
+// round and view change, and feeds the transitions to the subscribers
+type phaseEvents struct {
+	feed            event.Feed
+	mutex           sync.Mutex
+	phaseSince      time.Time
+	modeSince       time.Time
+	roundSince      time.Time
+	viewChangeSince time.Time
+}
+
+// SubscribePhaseEvents subscribes the channel to the transitions of the
+// consensus. The events are sent without holding up the consensus, so they
+// may be received out of order; their Time orders them.
+func (consensus *Consensus) SubscribePhaseEvents(ch chan<- PhaseEvent) event.Subscription {
+	return consensus.phaseEvents.feed.Subscribe(ch)
+}
+
+// emitPhaseEvent sends the transition of the current round to the subscribers
+func (consensus *Consensus) emitPhaseEvent(kind PhaseEventKind, from, to string) {
+	round := consensus.roundSnapshot()
+	consensus.publishPhaseEvent(PhaseEvent{
+		Kind:     kind,
+		ShardID:  consensus.ShardID,
+		BlockNum: round.blockNum,
+		ViewID:   round.viewID,
+		From:     from,
+		To:       to,
+	})
+}
+
+// publishPhaseEvent timestamps the transition, measures the time spent in its
+// From state, then sends it to the subscribers
+func (consensus *Consensus) publishPhaseEvent(evt PhaseEvent) {
+	events := &consensus.phaseEvents
+	now := time.Now()
+	evt.Time = now
+
+	events.mutex.Lock()
+	since := func(mark time.Time) time.Duration {
+		if mark.IsZero() {
+			return 0
+		}
+		return now.Sub(mark)
+	}
+	switch evt.Kind {
+	case PhaseChanged:
+		evt.Duration = since(events.phaseSince)
+		events.phaseSince = now
+		if evt.To == FBFTAnnounce.String() {
+			events.roundSince = now
+		}
+	case BlockFinalized:
+		evt.Duration = since(events.roundSince)
+	case ViewChangeStarted:
+		if events.viewChangeSince.IsZero() {
+			events.viewChangeSince = now
+		}
+	case NewViewAccepted:
+		evt.Duration = since(events.viewChangeSince)
+		events.viewChangeSince = time.Time{}
+	case ModeChanged:
+		evt.Duration = since(events.modeSince)
+		events.modeSince = now
+	}
+	events.mutex.Unlock()
+
+	// a slow subscriber must not stall the consensus
+	go events.feed.Send(evt)
+}
@@ -0,0 +1,52 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/consensus/quorum"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestPhaseEvents(t *testing.T) {
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9905"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9905")
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := New(
+		host, shard.BeaconChainShardID, leader, multibls.GetPrivateKey(bls_cosi.RandPrivateKey()), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	events := make(chan PhaseEvent, 4)
+	sub := consensus.SubscribePhaseEvents(events)
+	defer sub.Unsubscribe()
+
+	receive := func() PhaseEvent {
+		select {
+		case evt := <-events:
+			return evt
+		case <-time.After(time.Second):
+			t.Fatal("no phase event")
+		}
+		return PhaseEvent{}
+	}
+
+	consensus.switchPhase(FBFTPrepare, false)
+	if evt := receive(); evt.Kind != PhaseChanged || evt.From != "Announce" || evt.To != "Prepare" {
+		t.Errorf("unexpected phase event %+v", evt)
+	}
+	consensus.switchPhase(FBFTPrepare, false)
+	consensus.SetMode(Syncing)
+	if evt := receive(); evt.Kind != ModeChanged || evt.From != "Normal" || evt.To != "Syncing" {
+		t.Errorf("unexpected mode event %+v", evt)
+	}
+}
@@ -0,0 +1,66 @@
+package consensus
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// proposalCache keeps the blocks proposed at the heights not committed yet,
+// keyed by block number, so that a block announced before a view change can
+// be re-announced by its leader instead of being rebuilt from scratch.
+type proposalCache struct {
+	lock   sync.RWMutex
+	blocks map[uint64]map[common.Hash]*types.Block
+}
+
+func newProposalCache() *proposalCache {
+	return &proposalCache{blocks: map[uint64]map[common.Hash]*types.Block{}}
+}
+
+// add caches a proposed block
+func (c *proposalCache) add(block *types.Block) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	number := block.NumberU64()
+	if _, ok := c.blocks[number]; !ok {
+		c.blocks[number] = map[common.Hash]*types.Block{}
+	}
+	c.blocks[number][block.Hash()] = block
+}
+
+// get returns the cached block of the given number built on top of parentHash.
+// If several views proposed such a block, the one of the latest view is returned.
+func (c *proposalCache) get(number uint64, parentHash common.Hash) *types.Block {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var found *types.Block
+	for _, block := range c.blocks[number] {
+		if block.ParentHash() != parentHash {
+			continue
+		}
+		if found == nil || block.Header().ViewID().Cmp(found.Header().ViewID()) > 0 {
+			found = block
+		}
+	}
+	return found
+}
+
+// prune drops the blocks below the given number, which are committed
+func (c *proposalCache) prune(number uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for n := range c.blocks {
+		if n < number {
+			delete(c.blocks, n)
+		}
+	}
+}
+
+// CachedProposal returns the block proposed at the given number on top of
+// parentHash in an earlier view, or nil if no such block was proposed.
+// It lets the leader re-announce the block after a view change.
+func (consensus *Consensus) CachedProposal(number uint64, parentHash common.Hash) *types.Block {
+	return consensus.proposals.get(number, parentHash)
+}
@@ -0,0 +1,38 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func testProposal(number, viewID uint64, parentHash common.Hash) *types.Block {
+	return types.NewBlockWithHeader(blockfactory.NewTestHeader().With().
+		Number(new(big.Int).SetUint64(number)).
+		ViewID(new(big.Int).SetUint64(viewID)).
+		ParentHash(parentHash).
+		Header())
+}
+
+func TestProposalCache(t *testing.T) {
+	parent, otherParent := common.HexToHash("0x1"), common.HexToHash("0x2")
+	cache := newProposalCache()
+	cache.add(testProposal(5, 1, parent))
+	cache.add(testProposal(5, 2, parent))
+	cache.add(testProposal(5, 3, otherParent))
+
+	block := cache.get(5, parent)
+	if block == nil || block.Header().ViewID().Uint64() != 2 {
+		t.Fatal("expected the proposal of the latest view on top of the parent")
+	}
+	if cache.get(6, parent) != nil {
+		t.Fatal("no block was proposed at height 6")
+	}
+	cache.prune(6)
+	if cache.get(5, parent) != nil {
+		t.Fatal("committed heights should be pruned")
+	}
+}
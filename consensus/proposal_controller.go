@@ -0,0 +1,148 @@
+package consensus
+
+import (
+	"sync"
+	"time"
+)
+
+// ProposalState is the state of the leader between two block proposals
+type ProposalState byte
+
+// The states of the leader between two block proposals. A round goes from
+// collecting the votes on the announced block to finalizing it, waiting out
+// the block period, then to proposing the next block.
+const (
+	Collecting ProposalState = iota
+	Finalizing
+	Proposing
+)
+
+var proposalStateNames = map[ProposalState]string{
+	Collecting: "Collecting",
+	Finalizing: "Finalizing",
+	Proposing:  "Proposing",
+}
+
+func (s ProposalState) String() string {
+	if name, ok := proposalStateNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// scheduleFunc runs f once d elapsed, returning the func cancelling the run
+type scheduleFunc func(d time.Duration, f func()) (cancel func())
+
+func afterFunc(d time.Duration, f func()) func() {
+	t := time.AfterFunc(d, f)
+	return func() { t.Stop() }
+}
+
+// proposalController drives the block proposals of the leader. A finalized
+// round schedules the next proposal at the end of the block period; the
+// proposal is signaled on the ready channel, a pending signal absorbing the
+// later ones so the proposer never sees more than one.
+type proposalController struct {
+	mutex    sync.Mutex
+	state    ProposalState
+	period   time.Duration
+	due      time.Time
+	cancel   func()
+	round    uint64 // incremented on each transition, voiding stale timers
+	ready    chan struct{}
+	now      func() time.Time
+	schedule scheduleFunc
+}
+
+func newProposalController(ready chan struct{}) *proposalController {
+	return &proposalController{
+		ready:    ready,
+		now:      time.Now,
+		schedule: afterFunc,
+	}
+}
+
+// transition moves to the state, cancelling the scheduled proposal if any.
+// The caller must hold the mutex.
+func (c *proposalController) transition(state ProposalState) {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	c.round++
+	c.state = state
+}
+
+// start sets the block period and the first block due after it
+func (c *proposalController) start(period time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.period = period
+	c.due = c.now().Add(period)
+	c.transition(Collecting)
+}
+
+// propose signals the proposal of a new block now
+func (c *proposalController) propose() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.proposeLocked()
+}
+
+func (c *proposalController) proposeLocked() {
+	c.transition(Proposing)
+	c.due = c.now().Add(c.period)
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// collect notes the announce of the proposed block
+func (c *proposalController) collect() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.transition(Collecting)
+}
+
+// finalize notes the commit of the proposed block and schedules the next
+// proposal once the block is due
+func (c *proposalController) finalize() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	wait := c.due.Sub(c.now())
+	if wait <= 0 {
+		c.proposeLocked()
+		return
+	}
+	c.transition(Finalizing)
+	round := c.round
+	c.cancel = c.schedule(wait, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if c.round == round {
+			c.proposeLocked()
+		}
+	})
+}
+
+// reset drops the scheduled proposal, the round having ended otherwise
+func (c *proposalController) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.transition(Collecting)
+}
+
+// nextDue returns the time the next block is due
+func (c *proposalController) nextDue() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.due
+}
+
+// ProposalState returns the state of the leader between two block proposals
+func (consensus *Consensus) ProposalState() ProposalState {
+	consensus.proposer.mutex.Lock()
+	defer consensus.proposer.mutex.Unlock()
+	return consensus.proposer.state
+}
@@ -0,0 +1,103 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock runs the scheduled funcs when advanced past their time
+type fakeClock struct {
+	now   time.Time
+	tasks []*fakeTask
+}
+
+type fakeTask struct {
+	at        time.Time
+	f         func()
+	cancelled bool
+}
+
+func (c *fakeClock) schedule(d time.Duration, f func()) func() {
+	task := &fakeTask{at: c.now.Add(d), f: f}
+	c.tasks = append(c.tasks, task)
+	return func() { task.cancelled = true }
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	tasks := c.tasks
+	c.tasks = nil
+	for _, task := range tasks {
+		if task.cancelled {
+			continue
+		}
+		if task.at.After(c.now) {
+			c.tasks = append(c.tasks, task)
+			continue
+		}
+		task.f()
+	}
+}
+
+func signaled(ready chan struct{}) bool {
+	select {
+	case <-ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestProposalController(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	ready := make(chan struct{}, 1)
+	c := newProposalController(ready)
+	c.now = func() time.Time { return clock.now }
+	c.schedule = clock.schedule
+
+	c.start(8 * time.Second)
+	c.propose()
+	if c.state != Proposing || !signaled(ready) {
+		t.Fatalf("proposal not signaled, state %s", c.state)
+	}
+
+	// committed early, the proposal waits for the block period
+	c.collect()
+	clock.advance(3 * time.Second)
+	c.finalize()
+	if c.state != Finalizing || signaled(ready) {
+		t.Fatalf("proposal signaled before block period, state %s", c.state)
+	}
+	clock.advance(4 * time.Second)
+	if signaled(ready) {
+		t.Fatal("proposal signaled before block period")
+	}
+	clock.advance(time.Second)
+	if c.state != Proposing || !signaled(ready) {
+		t.Fatalf("proposal not signaled at block period, state %s", c.state)
+	}
+
+	// committed late, the proposal is immediate
+	c.collect()
+	clock.advance(10 * time.Second)
+	c.finalize()
+	if c.state != Proposing || !signaled(ready) {
+		t.Fatalf("late proposal not signaled, state %s", c.state)
+	}
+
+	// a view change drops the scheduled proposal
+	c.collect()
+	c.finalize()
+	c.reset()
+	clock.advance(time.Minute)
+	if c.state != Collecting || signaled(ready) {
+		t.Fatalf("proposal signaled after reset, state %s", c.state)
+	}
+
+	// repeated signals are absorbed by the pending one
+	c.propose()
+	c.propose()
+	if !signaled(ready) || signaled(ready) {
+		t.Fatal("pending signals not coalesced")
+	}
+}
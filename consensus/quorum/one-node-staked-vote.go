@@ -3,6 +3,7 @@ package quorum
 import (
 	"encoding/json"
 	"math/big"
+	"sync"
 
 	"github.com/harmony-one/harmony/consensus/votepower"
 	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
@@ -38,6 +39,8 @@ type stakedVoteWeight struct {
 	SignatureReader
 	DependencyInjectionWriter
 	DependencyInjectionReader
+	// guards roster and ballotBox, taken before the lock of the ballots
+	mutex     sync.Mutex
 	roster    votepower.Roster
 	ballotBox box
 }
@@ -78,6 +81,8 @@ func (v *stakedVoteWeight) IsQuorumAchievedByMask(mask *bls_cosi.Mask) bool {
 	return (*currentTotalPower).GT(threshold)
 }
 func (v *stakedVoteWeight) computeCurrentTotalPower(p Phase) (*numeric.Dec, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
 	w := shard.BLSPublicKey{}
 	members := v.Participants()
 	ballot := func() *voteBox {
@@ -107,7 +112,8 @@ func (v *stakedVoteWeight) computeCurrentTotalPower(p Phase) (*numeric.Dec, erro
 		}
 	}
 
-	return &ballot.currentTotal, nil
+	total := ballot.currentTotal
+	return &total, nil
 }
 
 // ComputeTotalPowerByMask computes the total power indicated by bitmap mask
@@ -116,6 +122,9 @@ func (v *stakedVoteWeight) computeTotalPowerByMask(mask *bls_cosi.Mask) *numeric
 	w := shard.BLSPublicKey{}
 	currentTotal := numeric.ZeroDec()
 
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
 	for i := range pubKeys {
 		if err := w.FromLibBLSPublicKey(pubKeys[i]); err != nil {
 			return nil
@@ -150,7 +159,9 @@ func (v *stakedVoteWeight) SetVoters(
 		return nil, err
 	}
 	// Hold onto this calculation
+	v.mutex.Lock()
 	v.roster = *roster
+	v.mutex.Unlock()
 	return &TallyResult{
 		roster.OurVotingPowerTotalPercentage,
 		roster.TheirVotingPowerTotalPercentage,
@@ -164,6 +175,8 @@ func (v *stakedVoteWeight) String() string {
 
 // HACK later remove - unify votepower in UI (aka MarshalJSON)
 func (v *stakedVoteWeight) SetRawStake(key shard.BLSPublicKey, d numeric.Dec) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
 	if voter, ok := v.roster.Voters[key]; ok {
 		voter.RawStake = d
 	}
@@ -172,6 +185,8 @@ func (v *stakedVoteWeight) SetRawStake(key shard.BLSPublicKey, d numeric.Dec) {
 // TODO remove this large method, use roster's own Marshal, mix it
 // specific logic here
 func (v *stakedVoteWeight) MarshalJSON() ([]byte, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
 	voterCount := len(v.roster.Voters)
 	type u struct {
 		IsHarmony      bool   `json:"is-harmony-slot"`
@@ -236,6 +251,8 @@ func (v *stakedVoteWeight) AmIMemberOfCommitee() bool {
 		return false
 	}
 	identity, _ := pubKeyFunc()
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
 	for _, key := range identity.PublicKey {
 		if w := (shard.BLSPublicKey{}); w.FromLibBLSPublicKey(key) != nil {
 			_, ok := v.roster.Voters[w]
@@ -261,11 +278,15 @@ func newBallotBox() box {
 
 func (v *stakedVoteWeight) ResetPrepareAndCommitVotes() {
 	v.reset([]Phase{Prepare, Commit})
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
 	v.ballotBox.Prepare = newBox()
 	v.ballotBox.Commit = newBox()
 }
 
 func (v *stakedVoteWeight) ResetViewChangeVotes() {
 	v.reset([]Phase{ViewChange})
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
 	v.ballotBox.ViewChange = newBox()
 }
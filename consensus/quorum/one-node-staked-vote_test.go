@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"math/rand"
 	"strconv"
+	"sync"
 	"testing"
 
 	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
@@ -69,7 +70,7 @@ func setupBaseCase() (Decider, *TallyResult, shard.SlotList, map[string]secretKe
 	decider := NewDecider(SuperMajorityStake, shard.BeaconChainShardID)
 	decider.UpdateParticipants(pubKeys)
 	tally, err := decider.SetVoters(&shard.Committee{
-		shard.BeaconChainShardID, slotList,
+		ShardID: shard.BeaconChainShardID, Slots: slotList,
 	}, big.NewInt(3))
 	if err != nil {
 		panic("Unable to SetVoters for Base Case")
@@ -96,7 +97,7 @@ func setupEdgeCase() (Decider, *TallyResult, shard.SlotList, secretKeyMap) {
 	decider := NewDecider(SuperMajorityStake, shard.BeaconChainShardID)
 	decider.UpdateParticipants(pubKeys)
 	tally, err := decider.SetVoters(&shard.Committee{
-		shard.BeaconChainShardID, slotList,
+		ShardID: shard.BeaconChainShardID, Slots: slotList,
 	}, big.NewInt(3))
 	if err != nil {
 		panic("Unable to SetVoters for Edge Case")
@@ -234,3 +235,38 @@ func Test33HarmonyNodes(t *testing.T) {
 			strconv.FormatBool(rewarded))
 	}
 }
+
+// TestConcurrentVotes is meant for the race detector: the vote and the view
+// change handlers use the decider without a common lock
+func TestConcurrentVotes(t *testing.T) {
+	stakedVote, _, _, sKeys := setupBaseCase()
+	var wg sync.WaitGroup
+	for _, phase := range []Phase{Prepare, Commit, ViewChange} {
+		wg.Add(2)
+		go func(p Phase) {
+			defer wg.Done()
+			sign(stakedVote, sKeys[hmy], p)
+			sign(stakedVote, sKeys[reg], p)
+		}(phase)
+		go func(p Phase) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				stakedVote.IsQuorumAchieved(p)
+				stakedVote.ReadAllBallots(p)
+				stakedVote.SignersCount(p)
+			}
+		}(phase)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			stakedVote.ResetViewChangeVotes()
+			_ = stakedVote.String()
+		}
+	}()
+	wg.Wait()
+	if !stakedVote.IsQuorumAchieved(Commit) {
+		t.Error("quorum not achieved after all commits")
+	}
+}
@@ -0,0 +1,44 @@
+package quorum
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+// Schedule picks the policy deciding the quorum of the committees of an epoch
+type Schedule func(config *params.ChainConfig, epoch *big.Int) Policy
+
+// StakingSchedule is the default schedule: one vote per key before the
+// staking epoch, stake weighted votes from it
+func StakingSchedule(config *params.ChainConfig, epoch *big.Int) Policy {
+	if config.IsStaking(epoch) {
+		return SuperMajorityStake
+	}
+	return SuperMajorityVote
+}
+
+var (
+	schedulesMutex sync.RWMutex
+	schedules      = map[string]Schedule{}
+)
+
+// RegisterSchedule sets the schedule of the policies of the given network
+// type, replacing the default StakingSchedule
+func RegisterSchedule(network string, schedule Schedule) {
+	schedulesMutex.Lock()
+	defer schedulesMutex.Unlock()
+	schedules[network] = schedule
+}
+
+// PolicyOf returns the policy deciding the quorum of the epoch on the network
+func PolicyOf(network string, config *params.ChainConfig, epoch *big.Int) Policy {
+	schedulesMutex.RLock()
+	schedule, ok := schedules[network]
+	schedulesMutex.RUnlock()
+	if !ok {
+		schedule = StakingSchedule
+	}
+	return schedule(config, epoch)
+}
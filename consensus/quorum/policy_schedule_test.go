@@ -0,0 +1,30 @@
+package quorum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+func TestPolicyOf(t *testing.T) {
+	config := &params.ChainConfig{StakingEpoch: big.NewInt(10)}
+	for epoch, expected := range map[int64]Policy{
+		0: SuperMajorityVote, 9: SuperMajorityVote, 10: SuperMajorityStake, 11: SuperMajorityStake,
+	} {
+		if policy := PolicyOf("testnet", config, big.NewInt(epoch)); policy != expected {
+			t.Errorf("epoch %d: got %s, expected %s", epoch, policy, expected)
+		}
+	}
+
+	RegisterSchedule("localnet", func(*params.ChainConfig, *big.Int) Policy {
+		return SuperMajorityVote
+	})
+	defer RegisterSchedule("localnet", StakingSchedule)
+	if policy := PolicyOf("localnet", config, big.NewInt(11)); policy != SuperMajorityVote {
+		t.Errorf("got %s from the registered schedule, expected %s", policy, SuperMajorityVote)
+	}
+	if policy := PolicyOf("testnet", config, big.NewInt(11)); policy != SuperMajorityStake {
+		t.Errorf("got %s on another network, expected %s", policy, SuperMajorityStake)
+	}
+}
@@ -3,6 +3,7 @@ package quorum
 import (
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/harmony-one/bls/ffi/go/bls"
@@ -142,8 +143,11 @@ type Transition struct {
 }
 
 // These maps represent the signatories (validators), keys are BLS public keys
-// and values are BLS private key signed signatures
+// and values are BLS private key signed signatures. The ballots are read and
+// written by both the vote and the view change handlers, so cIdentities
+// guards them with its own lock, taken after any consensus lock.
 type cIdentities struct {
+	mutex sync.RWMutex
 	// Public keys of the committee including leader and validators
 	publicKeys []*bls.PublicKey
 	prepare    *votepower.Round
@@ -173,6 +177,12 @@ func (s *cIdentities) AggregateVotes(p Phase) *bls.Sign {
 }
 
 func (s *cIdentities) IndexOf(pubKey *bls.PublicKey) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.indexOf(pubKey)
+}
+
+func (s *cIdentities) indexOf(pubKey *bls.PublicKey) int {
 	idx := -1
 	for k, v := range s.publicKeys {
 		if v.IsEqual(pubKey) {
@@ -183,16 +193,20 @@ func (s *cIdentities) IndexOf(pubKey *bls.PublicKey) int {
 }
 
 func (s *cIdentities) NextAfter(pubKey *bls.PublicKey) (bool, *bls.PublicKey) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	found := false
-	idx := s.IndexOf(pubKey)
+	idx := s.indexOf(pubKey)
 	if idx != -1 {
 		found = true
 	}
-	idx = (idx + 1) % int(s.ParticipantsCount())
+	idx = (idx + 1) % len(s.publicKeys)
 	return found, s.publicKeys[idx]
 }
 
 func (s *cIdentities) Participants() []*bls.PublicKey {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.publicKeys
 }
 
@@ -201,14 +215,20 @@ func (s *cIdentities) UpdateParticipants(pubKeys []*bls.PublicKey) {
 		k := shard.BLSPublicKey{}
 		k.FromLibBLSPublicKey(pubKeys[i])
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.publicKeys = append(pubKeys[:0:0], pubKeys...)
 }
 
 func (s *cIdentities) ParticipantsCount() int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return int64(len(s.publicKeys))
 }
 
 func (s *cIdentities) SignersCount(p Phase) int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	switch p {
 	case Prepare:
 		return int64(len(s.prepare.BallotBox))
@@ -237,6 +257,8 @@ func (s *cIdentities) SubmitVote(
 		Height:          height,
 		ViewID:          viewID,
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	switch p {
 	case Prepare:
 		s.prepare.BallotBox[key] = ballot
@@ -251,6 +273,8 @@ func (s *cIdentities) SubmitVote(
 }
 
 func (s *cIdentities) reset(ps []Phase) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	for i := range ps {
 		switch m := votepower.NewRound(); ps[i] {
 		case Prepare:
@@ -271,6 +295,8 @@ func (s *cIdentities) ReadBallot(p Phase, PubKey *bls.PublicKey) *votepower.Ball
 	ballotBox := map[shard.BLSPublicKey]*votepower.Ballot{}
 	key := *shard.FromLibBLSPublicKeyUnsafe(PubKey)
 
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	switch p {
 	case Prepare:
 		ballotBox = s.prepare.BallotBox
@@ -288,6 +314,8 @@ func (s *cIdentities) ReadBallot(p Phase, PubKey *bls.PublicKey) *votepower.Ball
 }
 
 func (s *cIdentities) ReadAllBallots(p Phase) []*votepower.Ballot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	m := map[shard.BLSPublicKey]*votepower.Ballot{}
 	switch p {
 	case Prepare:
@@ -339,11 +367,11 @@ func NewDecider(p Policy, shardID uint32) Decider {
 		}
 	case SuperMajorityStake:
 		return &stakedVoteWeight{
-			c.SignatureReader,
-			c.DependencyInjectionWriter,
-			c.DependencyInjectionWriter.(DependencyInjectionReader),
-			*votepower.NewRoster(shardID),
-			newBallotBox(),
+			SignatureReader:           c.SignatureReader,
+			DependencyInjectionWriter: c.DependencyInjectionWriter,
+			DependencyInjectionReader: c.DependencyInjectionWriter.(DependencyInjectionReader),
+			roster:                    *votepower.NewRoster(shardID),
+			ballotBox:                 newBallotBox(),
 		}
 	default:
 		// Should not be possible
@@ -13,6 +13,15 @@ type Payout struct {
 	Addr        common.Address
 	NewlyEarned *big.Int
 	EarningKey  shard.BLSPublicKey
+	// Delegators are the shares of the payout credited to the delegators
+	Delegators []DelegatorPayout
+}
+
+// DelegatorPayout is an amount paid to a delegator of a validator
+type DelegatorPayout struct {
+	Validator common.Address
+	Delegator common.Address
+	Amount    *big.Int
 }
 
 // CompletedRound ..
@@ -20,6 +29,9 @@ type CompletedRound struct {
 	Total            *big.Int
 	BeaconchainAward []Payout
 	ShardChainAward  []Payout
+	// Undelegations are the unlocked undelegations paid out at the start of
+	// the epoch
+	Undelegations []DelegatorPayout
 }
 
 // Reader ..
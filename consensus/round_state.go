@@ -0,0 +1,81 @@
+package consensus
+
+import (
+	"github.com/harmony-one/bls/ffi/go/bls"
+)
+
+// roundState is the state of the current consensus round. A published
+// roundState is never modified; updates publish a modified copy instead, so
+// the fields read from one snapshot are always consistent with each other.
+type roundState struct {
+	// blockNum: the next blockNumber that FBFT is going to agree on,
+	// should be equal to the blockNumber of next block
+	blockNum uint64
+	viewID   uint64
+	// the publickey of leader
+	leader *bls.PublicKey
+	// phase: different phase of FBFT protocol: pre-prepare, prepare, commit, finish etc
+	phase FBFTPhase
+}
+
+// roundSnapshot returns the current round state. Callers reading several
+// fields of the round should read them from a single snapshot.
+func (consensus *Consensus) roundSnapshot() *roundState {
+	return consensus.round.Load().(*roundState)
+}
+
+// updateRound applies the update to a copy of the current round state and
+// publishes the copy, so the fields changed together are seen together.
+func (consensus *Consensus) updateRound(update func(round *roundState)) {
+	consensus.roundMutex.Lock()
+	defer consensus.roundMutex.Unlock()
+	next := *consensus.roundSnapshot()
+	update(&next)
+	consensus.round.Store(&next)
+}
+
+// BlockNum returns the block number the current round is agreeing on
+func (consensus *Consensus) BlockNum() uint64 {
+	return consensus.roundSnapshot().blockNum
+}
+
+// GetViewID returns the consensus ID
+func (consensus *Consensus) GetViewID() uint64 {
+	return consensus.roundSnapshot().viewID
+}
+
+// LeaderPubKey returns the public key of the leader of the current round
+func (consensus *Consensus) LeaderPubKey() *bls.PublicKey {
+	return consensus.roundSnapshot().leader
+}
+
+// Phase returns the FBFT phase of the current round
+func (consensus *Consensus) Phase() FBFTPhase {
+	return consensus.roundSnapshot().phase
+}
+
+// SetLeaderPubKey sets the public key of the leader of the current round
+func (consensus *Consensus) SetLeaderPubKey(leader *bls.PublicKey) {
+	consensus.updateRound(func(round *roundState) {
+		round.leader = leader
+	})
+}
+
+// setRoundViewID sets the viewID of the current round, leaving the view
+// changing ID as it is
+func (consensus *Consensus) setRoundViewID(viewID uint64) {
+	consensus.updateRound(func(round *roundState) {
+		round.viewID = viewID
+	})
+}
+
+// setPhase sets the FBFT phase of the current round
+func (consensus *Consensus) setPhase(phase FBFTPhase) {
+	var from FBFTPhase
+	consensus.updateRound(func(round *roundState) {
+		from, round.phase = round.phase, phase
+	})
+	if from != phase {
+		consensus.emitPhaseEvent(PhaseChanged, from.String(), phase.String())
+	}
+}
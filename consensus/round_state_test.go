@@ -0,0 +1,56 @@
+package consensus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/harmony-one/harmony/crypto/bls"
+)
+
+func TestUpdateRound(t *testing.T) {
+	consensus := &Consensus{}
+	consensus.round.Store(&roundState{phase: FBFTAnnounce})
+
+	leader := bls.RandPrivateKey().GetPublicKey()
+	before := consensus.roundSnapshot()
+	consensus.updateRound(func(round *roundState) {
+		round.blockNum = 10
+		round.viewID = 12
+		round.leader = leader
+	})
+	if before.blockNum != 0 || before.viewID != 0 || before.leader != nil {
+		t.Fatal("published round state was modified")
+	}
+	if consensus.BlockNum() != 10 || consensus.GetViewID() != 12 ||
+		!consensus.LeaderPubKey().IsEqual(leader) || consensus.Phase() != FBFTAnnounce {
+		t.Fatalf("unexpected round state %+v", consensus.roundSnapshot())
+	}
+}
+
+func TestUpdateRoundConcurrent(t *testing.T) {
+	consensus := &Consensus{}
+	consensus.round.Store(&roundState{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			consensus.updateRound(func(round *roundState) {
+				round.blockNum++
+				round.viewID = round.blockNum
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			round := consensus.roundSnapshot()
+			if round.viewID != round.blockNum {
+				t.Errorf("torn round state %+v", round)
+			}
+		}()
+	}
+	wg.Wait()
+	if consensus.BlockNum() != 100 {
+		t.Fatalf("expected block 100, got %d", consensus.BlockNum())
+	}
+}
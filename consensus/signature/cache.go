@@ -0,0 +1,79 @@
+package signature
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var (
+	verifyCacheHitCounter  = metrics.NewRegisteredCounter("consensus/sigcache/hit", nil)
+	verifyCacheMissCounter = metrics.NewRegisteredCounter("consensus/sigcache/miss", nil)
+	_                      = metrics.NewRegisteredFunctionalGauge("consensus/sigcache/hitrate", nil, func() int64 {
+		hits, misses := verifyCacheHitCounter.Count(), verifyCacheMissCounter.Count()
+		if hits+misses == 0 {
+			return 0
+		}
+		return hits * 100 / (hits + misses)
+	})
+)
+
+// VerifyCache remembers the BLS signatures already verified, so the messages
+// re-delivered by pubsub do not go through the pairing operations again.
+// Only successful verifications are cached.
+type VerifyCache struct {
+	cache *lru.Cache
+}
+
+// NewVerifyCache creates a verified signature cache holding up to size entries
+func NewVerifyCache(size int) *VerifyCache {
+	cache, _ := lru.New(size)
+	return &VerifyCache{cache: cache}
+}
+
+// VerifyHash verifies the signature of pubKey on hash, skipping the
+// verification if the same signature was already verified.
+// A nil cache verifies every signature.
+func (c *VerifyCache) VerifyHash(sig *bls.Sign, pubKey *bls.PublicKey, hash []byte) bool {
+	if c == nil {
+		return sig.VerifyHash(pubKey, hash)
+	}
+	key := verifyCacheKey(sig, pubKey, hash)
+	if _, ok := c.cache.Get(key); ok {
+		verifyCacheHitCounter.Inc(1)
+		return true
+	}
+	verifyCacheMissCounter.Inc(1)
+	if !sig.VerifyHash(pubKey, hash) {
+		return false
+	}
+	c.cache.Add(key, struct{}{})
+	return true
+}
+
+// Contains returns whether the signature of pubKey on hash was already verified
+func (c *VerifyCache) Contains(sig *bls.Sign, pubKey *bls.PublicKey, hash []byte) bool {
+	if c == nil {
+		return false
+	}
+	return c.cache.Contains(verifyCacheKey(sig, pubKey, hash))
+}
+
+// Add records the signature of pubKey on hash as verified, e.g. after a batch verification
+func (c *VerifyCache) Add(sig *bls.Sign, pubKey *bls.PublicKey, hash []byte) {
+	if c == nil {
+		return
+	}
+	c.cache.Add(verifyCacheKey(sig, pubKey, hash), struct{}{})
+}
+
+// Len returns the number of cached verifications
+func (c *VerifyCache) Len() int {
+	return c.cache.Len()
+}
+
+func verifyCacheKey(sig *bls.Sign, pubKey *bls.PublicKey, hash []byte) common.Hash {
+	return crypto.Keccak256Hash(pubKey.Serialize(), sig.Serialize(), hash)
+}
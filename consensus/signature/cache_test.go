@@ -0,0 +1,50 @@
+package signature
+
+import (
+	"testing"
+
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+)
+
+func TestVerifyCache(t *testing.T) {
+	cache := NewVerifyCache(2)
+	key := bls_cosi.RandPrivateKey()
+	hash := []byte("commit payload")
+	sig := key.SignHash(hash)
+
+	if !cache.VerifyHash(sig, key.GetPublicKey(), hash) {
+		t.Fatal("valid signature should verify")
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected one cached verification, got %d", cache.Len())
+	}
+	if !cache.Contains(sig, key.GetPublicKey(), hash) {
+		t.Fatal("valid signature should be cached")
+	}
+	if !cache.VerifyHash(sig, key.GetPublicKey(), hash) {
+		t.Fatal("cached signature should verify")
+	}
+
+	other := bls_cosi.RandPrivateKey()
+	if cache.VerifyHash(sig, other.GetPublicKey(), hash) {
+		t.Fatal("signature should not verify with another key")
+	}
+	if cache.Len() != 1 || cache.Contains(sig, other.GetPublicKey(), hash) {
+		t.Fatal("failed verification should not be cached")
+	}
+
+	// a cached signature is not verified again
+	cache.Add(sig, other.GetPublicKey(), hash)
+	if !cache.VerifyHash(sig, other.GetPublicKey(), hash) {
+		t.Fatal("second verification should hit the cache")
+	}
+
+	// the least recently verified signatures are evicted beyond the size
+	third := bls_cosi.RandPrivateKey()
+	if !cache.VerifyHash(third.SignHash(hash), third.GetPublicKey(), hash) {
+		t.Fatal("valid signature should verify")
+	}
+	if cache.Len() != 2 || cache.Contains(sig, key.GetPublicKey(), hash) {
+		t.Fatal("least recent verification should be evicted")
+	}
+}
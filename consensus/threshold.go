@@ -22,7 +22,7 @@ func (consensus *Consensus) didReachPrepareQuorum() error {
 	}
 	// Construct and broadcast prepared message
 	networkMessage, err := consensus.construct(
-		msg_pb.MessageType_PREPARED, nil, consensus.LeaderPubKey, leaderPriKey,
+		msg_pb.MessageType_PREPARED, nil, consensus.LeaderPubKey(), leaderPriKey,
 	)
 	if err != nil {
 		consensus.getLogger().Err(err).
@@ -39,7 +39,7 @@ func (consensus *Consensus) didReachPrepareQuorum() error {
 	consensus.FBFTLog.AddMessage(FBFTMsg)
 	// Leader add commit phase signature
 	commitPayload := signature.ConstructCommitPayload(consensus.ChainReader,
-		new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.blockNum, consensus.viewID)
+		new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.BlockNum(), consensus.GetViewID())
 
 	// so by this point, everyone has committed to the blockhash of this block
 	// in prepare and so this is the actual block.
@@ -49,8 +49,8 @@ func (consensus *Consensus) didReachPrepareQuorum() error {
 			key,
 			consensus.priKey.PrivateKey[i].SignHash(commitPayload),
 			common.BytesToHash(consensus.blockHash[:]),
-			consensus.blockNum,
-			consensus.viewID,
+			consensus.BlockNum(),
+			consensus.GetViewID(),
 		); err != nil {
 			return err
 		}
@@ -61,7 +61,7 @@ func (consensus *Consensus) didReachPrepareQuorum() error {
 		}
 	}
 	if err := consensus.msgSender.SendWithRetry(
-		consensus.blockNum,
+		consensus.BlockNum(),
 		msg_pb.MessageType_PREPARED, []nodeconfig.GroupID{
 			nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(consensus.ShardID)),
 		},
@@ -71,7 +71,7 @@ func (consensus *Consensus) didReachPrepareQuorum() error {
 	} else {
 		consensus.getLogger().Debug().
 			Hex("blockHash", consensus.blockHash[:]).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("blockNum", consensus.BlockNum()).
 			Msg("[OnPrepare] Sent Prepared Message!!")
 	}
 	consensus.msgSender.StopRetry(msg_pb.MessageType_ANNOUNCE)
@@ -79,7 +79,7 @@ func (consensus *Consensus) didReachPrepareQuorum() error {
 	consensus.msgSender.StopRetry(msg_pb.MessageType_COMMITTED)
 
 	consensus.getLogger().Debug().
-		Str("From", consensus.phase.String()).
+		Str("From", consensus.Phase().String()).
 		Str("To", FBFTCommit.String()).
 		Msg("[OnPrepare] Switching phase")
 
@@ -7,10 +7,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/rlp"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/consensus/signature"
-	"github.com/harmony-one/harmony/core/types"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/p2p"
 )
@@ -29,14 +27,24 @@ func (consensus *Consensus) onAnnounce(msg *msg_pb.Message) {
 	if !consensus.onAnnounceSanityChecks(recvMsg) {
 		return
 	}
+	if consensus.electsLeader(nil) {
+		if err := consensus.isElectedLeader(recvMsg); err != nil {
+			consensus.getLogger().Warn().Err(err).
+				Uint64("MsgViewID", recvMsg.ViewID).
+				Uint64("MsgBlockNum", recvMsg.BlockNum).
+				Msg("[OnAnnounce] Announce not from the elected leader")
+			return
+		}
+	}
 
 	consensus.getLogger().Debug().
 		Uint64("MsgViewID", recvMsg.ViewID).
 		Uint64("MsgBlockNum", recvMsg.BlockNum).
 		Msg("[OnAnnounce] Announce message Added")
 	consensus.FBFTLog.AddMessage(recvMsg)
-	consensus.mutex.Lock()
-	defer consensus.mutex.Unlock()
+	consensus.leaderTracker.proposed(recvMsg.SenderPubkey, recvMsg.BlockNum)
+	consensus.prepareMutex.Lock()
+	defer consensus.prepareMutex.Unlock()
 	consensus.blockHash = recvMsg.BlockHash
 	// we have already added message and block, skip check viewID
 	// and send prepare message if is in ViewChanging mode
@@ -81,10 +89,11 @@ func (consensus *Consensus) prepare() {
 					Str("blockHash", hex.EncodeToString(consensus.blockHash[:])).
 					Msg("[OnAnnounce] Sent Prepare Message!!")
 			}
+			consensus.equivocate(msg_pb.MessageType_PREPARE, groupID, key, consensus.priKey.PrivateKey[i])
 		}
 	}
 	consensus.getLogger().Debug().
-		Str("From", consensus.phase.String()).
+		Str("From", consensus.Phase().String()).
 		Str("To", FBFTPrepare.String()).
 		Msg("[Announce] Switching Phase")
 	consensus.switchPhase(FBFTPrepare, true)
@@ -103,7 +112,7 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 		Uint64("MsgViewID", recvMsg.ViewID).
 		Msg("[OnPrepared] Received prepared message")
 
-	if recvMsg.BlockNum < consensus.blockNum {
+	if recvMsg.BlockNum < consensus.BlockNum() {
 		consensus.getLogger().Debug().Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Msg("Wrong BlockNum Received, ignoring!")
 		return
@@ -134,8 +143,12 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 	}
 
 	// check validity of block
-	var blockObj types.Block
-	if err := rlp.DecodeBytes(recvMsg.Block, &blockObj); err != nil {
+	blockObj, encodedBlock, err := consensus.PreparedBlock(recvMsg)
+	if err == ErrProposalBodyMissing {
+		consensus.fetchPreparedInBackground(msg, recvMsg)
+		return
+	}
+	if err != nil {
 		consensus.getLogger().Warn().
 			Err(err).
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
@@ -143,16 +156,20 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 		return
 	}
 	// let this handle it own logs
-	if !consensus.onPreparedSanityChecks(&blockObj, recvMsg) {
+	if !consensus.onPreparedSanityChecks(blockObj, recvMsg) {
 		return
 	}
-	consensus.mutex.Lock()
-	defer consensus.mutex.Unlock()
-
-	consensus.FBFTLog.AddBlock(&blockObj)
+	// catching up may end the round, resetting the commit phase too
+	consensus.prepareMutex.Lock()
+	defer consensus.prepareMutex.Unlock()
+	consensus.commitMutex.Lock()
+	defer consensus.commitMutex.Unlock()
+
+	consensus.FBFTLog.AddBlock(blockObj)
+	consensus.proposals.add(blockObj)
 	// add block field
-	blockPayload := make([]byte, len(recvMsg.Block))
-	copy(blockPayload[:], recvMsg.Block[:])
+	blockPayload := make([]byte, len(encodedBlock))
+	copy(blockPayload[:], encodedBlock[:])
 	consensus.block = blockPayload
 	recvMsg.Block = []byte{} // save memory space
 	consensus.FBFTLog.AddMessage(recvMsg)
@@ -178,10 +195,10 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 		}
 		return
 	}
-	if recvMsg.BlockNum > consensus.blockNum {
+	if recvMsg.BlockNum > consensus.BlockNum() {
 		consensus.getLogger().Debug().
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
-			Uint64("blockNum", consensus.blockNum).
+			Uint64("blockNum", consensus.BlockNum()).
 			Msg("[OnPrepared] Future Block Received, ignoring!!")
 		return
 	}
@@ -204,7 +221,7 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 
 	// local viewID may not be constant with other, so use received msg viewID.
 	commitPayload := signature.ConstructCommitPayload(consensus.ChainReader,
-		new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.blockNum, recvMsg.ViewID)
+		new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.BlockNum(), recvMsg.ViewID)
 	groupID := []nodeconfig.GroupID{
 		nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(consensus.ShardID)),
 	}
@@ -215,7 +232,7 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 			key, consensus.priKey.PrivateKey[i],
 		)
 
-		if consensus.current.Mode() != Listening {
+		if consensus.current.Mode() != Listening && !consensus.withholdCommit() {
 			if err := consensus.msgSender.SendWithoutRetry(
 				groupID,
 				p2p.ConstructMessage(networkMessage.Bytes),
@@ -223,14 +240,15 @@ func (consensus *Consensus) onPrepared(msg *msg_pb.Message) {
 				consensus.getLogger().Warn().Msg("[OnPrepared] Cannot send commit message!!")
 			} else {
 				consensus.getLogger().Info().
-					Uint64("blockNum", consensus.blockNum).
+					Uint64("blockNum", consensus.BlockNum()).
 					Hex("blockHash", consensus.blockHash[:]).
 					Msg("[OnPrepared] Sent Commit Message!!")
 			}
+			consensus.equivocate(msg_pb.MessageType_COMMIT, groupID, key, consensus.priKey.PrivateKey[i])
 		}
 	}
 	consensus.getLogger().Debug().
-		Str("From", consensus.phase.String()).
+		Str("From", consensus.Phase().String()).
 		Str("To", FBFTCommit.String()).
 		Msg("[OnPrepared] Switching phase")
 	consensus.switchPhase(FBFTCommit, true)
@@ -271,13 +289,16 @@ func (consensus *Consensus) onCommitted(msg *msg_pb.Message) {
 
 	consensus.FBFTLog.AddMessage(recvMsg)
 
-	consensus.mutex.Lock()
-	defer consensus.mutex.Unlock()
+	// catching up may end the round, resetting the prepare phase too
+	consensus.prepareMutex.Lock()
+	defer consensus.prepareMutex.Unlock()
+	consensus.commitMutex.Lock()
+	defer consensus.commitMutex.Unlock()
 
 	consensus.aggregatedCommitSig = aggSig
 	consensus.commitBitmap = mask
 
-	if recvMsg.BlockNum-consensus.blockNum > consensusBlockNumBuffer {
+	if recvMsg.BlockNum-consensus.BlockNum() > consensusBlockNumBuffer {
 		consensus.getLogger().Debug().Uint64("MsgBlockNum", recvMsg.BlockNum).Msg("[OnCommitted] OUT OF SYNC")
 		go func() {
 			select {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math/big"
+	"strconv"
 	"sync"
 	"time"
 
@@ -26,6 +27,8 @@ type State struct {
 	mode   Mode
 	viewID uint64
 	mux    sync.Mutex
+	// onModeChange is called on the change of the mode, if set
+	onModeChange func(from, to Mode)
 }
 
 // Mode return the current node mode
@@ -36,8 +39,12 @@ func (pm *State) Mode() Mode {
 // SetMode set the node mode as required
 func (pm *State) SetMode(s Mode) {
 	pm.mux.Lock()
-	defer pm.mux.Unlock()
+	from := pm.mode
 	pm.mode = s
+	pm.mux.Unlock()
+	if from != s && pm.onModeChange != nil {
+		pm.onModeChange(from, s)
+	}
 }
 
 // ViewID return the current viewchanging id
@@ -60,12 +67,12 @@ func (pm *State) GetViewID() uint64 {
 // switchPhase will switch FBFTPhase to nextPhase if the desirePhase equals the nextPhase
 func (consensus *Consensus) switchPhase(desired FBFTPhase, override bool) {
 	if override {
-		consensus.phase = desired
+		consensus.setPhase(desired)
 		return
 	}
 
 	var nextPhase FBFTPhase
-	switch consensus.phase {
+	switch consensus.Phase() {
 	case FBFTAnnounce:
 		nextPhase = FBFTPrepare
 	case FBFTPrepare:
@@ -74,16 +81,26 @@ func (consensus *Consensus) switchPhase(desired FBFTPhase, override bool) {
 		nextPhase = FBFTAnnounce
 	}
 	if nextPhase == desired {
-		consensus.phase = nextPhase
+		consensus.setPhase(nextPhase)
 	}
 }
 
 // GetNextLeaderKey uniquely determine who is the leader for given viewID
 func (consensus *Consensus) GetNextLeaderKey() *bls.PublicKey {
-	wasFound, next := consensus.Decider.NextAfter(consensus.LeaderPubKey)
+	if consensus.electsLeader(nil) {
+		leader, err := consensus.electLeader(
+			consensus.ChainReader.CurrentHeader(), consensus.current.ViewID(),
+		)
+		if err == nil {
+			return leader
+		}
+		consensus.getLogger().Warn().Err(err).
+			Msg("GetNextLeaderKey: cannot elect leader, rotating instead")
+	}
+	wasFound, next := consensus.Decider.NextAfter(consensus.LeaderPubKey())
 	if !wasFound {
 		consensus.getLogger().Warn().
-			Str("key", consensus.LeaderPubKey.SerializeToHexStr()).
+			Str("key", consensus.LeaderPubKey().SerializeToHexStr()).
 			Msg("GetNextLeaderKey: currentLeaderKey not found")
 	}
 	return next
@@ -92,7 +109,7 @@ func (consensus *Consensus) GetNextLeaderKey() *bls.PublicKey {
 // ResetViewChangeState reset the state for viewchange
 func (consensus *Consensus) ResetViewChangeState() {
 	consensus.getLogger().Debug().
-		Str("Phase", consensus.phase.String()).
+		Str("Phase", consensus.Phase().String()).
 		Msg("[ResetViewChangeState] Resetting view change state")
 	consensus.current.SetMode(Normal)
 	consensus.m1Payload = []byte{}
@@ -120,25 +137,34 @@ func (consensus *Consensus) startViewChange(viewID uint64) {
 	}
 	consensus.consensusTimeout[timeoutConsensus].Stop()
 	consensus.consensusTimeout[timeoutBootstrap].Stop()
+	consensus.emitPhaseEvent(
+		ViewChangeStarted,
+		strconv.FormatUint(consensus.GetViewID(), 10), strconv.FormatUint(viewID, 10),
+	)
 	consensus.current.SetMode(ViewChanging)
 	consensus.current.SetViewID(viewID)
-	consensus.LeaderPubKey = consensus.GetNextLeaderKey()
+	consensus.proposer.reset()
+	consensus.leaderTracker.viewChanged()
+	consensus.SetLeaderPubKey(consensus.GetNextLeaderKey())
+	consensus.leaderTracker.begin(consensus.LeaderPubKey(), consensus.BlockNum())
 
-	diff := int64(viewID - consensus.viewID)
+	diff := int64(viewID - consensus.GetViewID())
 	duration := time.Duration(diff * diff * int64(viewChangeDuration))
 	consensus.getLogger().Info().
 		Uint64("ViewChangingID", viewID).
 		Dur("timeoutDuration", duration).
-		Str("NextLeader", consensus.LeaderPubKey.SerializeToHexStr()).
+		Str("NextLeader", consensus.LeaderPubKey().SerializeToHexStr()).
 		Msg("[startViewChange]")
 
 	for i, key := range consensus.PubKey.PublicKey {
 		msgToSend := consensus.constructViewChangeMessage(key, consensus.priKey.PrivateKey[i])
-		consensus.host.SendMessageToGroups([]nodeconfig.GroupID{
+		if err := consensus.msgSender.SendWithoutRetry([]nodeconfig.GroupID{
 			nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(consensus.ShardID)),
 		},
 			p2p.ConstructMessage(msgToSend),
-		)
+		); err != nil {
+			consensus.getLogger().Warn().Err(err).Msg("[startViewChange] Cannot send view change message")
+		}
 	}
 
 	consensus.consensusTimeout[timeoutViewChange].SetDuration(duration)
@@ -338,16 +364,14 @@ func (consensus *Consensus) onViewChange(msg *msg_pb.Message) {
 	// received enough view change messages, change state to normal consensus
 	if consensus.Decider.IsQuorumAchievedByMask(consensus.viewIDBitmap[recvMsg.ViewID]) {
 		consensus.current.SetMode(Normal)
-		consensus.LeaderPubKey = newLeaderKey
+		consensus.SetLeaderPubKey(newLeaderKey)
 		consensus.ResetState()
 		if len(consensus.m1Payload) == 0 {
 			// TODO(Chao): explain why ReadySignal is sent only in this case but not the other case.
-			go func() {
-				consensus.ReadySignal <- struct{}{}
-			}()
+			consensus.proposer.propose()
 		} else {
 			consensus.getLogger().Debug().
-				Str("From", consensus.phase.String()).
+				Str("From", consensus.Phase().String()).
 				Str("To", FBFTCommit.String()).
 				Msg("[OnViewChange] Switching phase")
 			consensus.switchPhase(FBFTCommit, true)
@@ -364,7 +388,7 @@ func (consensus *Consensus) onViewChange(msg *msg_pb.Message) {
 			consensus.prepareBitmap = mask
 			// Leader sign and add commit message
 			commitPayload := signature.ConstructCommitPayload(consensus.ChainReader,
-				new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.blockNum, recvMsg.ViewID)
+				new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.BlockNum(), recvMsg.ViewID)
 			for i, key := range consensus.PubKey.PublicKey {
 				priKey := consensus.priKey.PrivateKey[i]
 				if _, err := consensus.Decider.SubmitVote(
@@ -372,7 +396,7 @@ func (consensus *Consensus) onViewChange(msg *msg_pb.Message) {
 					key,
 					priKey.SignHash(commitPayload),
 					common.BytesToHash(consensus.blockHash[:]),
-					consensus.blockNum,
+					consensus.BlockNum(),
 					recvMsg.ViewID,
 				); err != nil {
 					consensus.getLogger().Debug().Msg("submit vote on viewchange commit failed")
@@ -388,16 +412,21 @@ func (consensus *Consensus) onViewChange(msg *msg_pb.Message) {
 		}
 
 		consensus.current.SetViewID(recvMsg.ViewID)
-		msgToSend := consensus.constructNewViewMessage(
+		msgToSend, err := consensus.constructNewViewMessage(
 			recvMsg.ViewID, newLeaderKey, newLeaderPriKey,
 		)
+		if err != nil {
+			consensus.getLogger().Error().Err(err).
+				Msg("[onViewChange] Cannot construct the NewView Message")
+			return
+		}
 
 		consensus.getLogger().Warn().
 			Int("payloadSize", len(consensus.m1Payload)).
 			Hex("M1Payload", consensus.m1Payload).
 			Msg("[onViewChange] Sent NewView Message")
 		if err := consensus.msgSender.SendWithRetry(
-			consensus.blockNum,
+			consensus.BlockNum(),
 			msg_pb.MessageType_NEWVIEW,
 			[]nodeconfig.GroupID{
 				nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(consensus.ShardID))},
@@ -407,8 +436,11 @@ func (consensus *Consensus) onViewChange(msg *msg_pb.Message) {
 				Msg("could not send out the NEWVIEW message")
 		}
 
-		consensus.viewID = recvMsg.ViewID
+		consensus.setRoundViewID(recvMsg.ViewID)
 		consensus.ResetViewChangeState()
+		consensus.emitPhaseEvent(
+			NewViewAccepted, newLeaderKey.SerializeToHexStr(), strconv.FormatUint(recvMsg.ViewID, 10),
+		)
 		consensus.consensusTimeout[timeoutViewChange].Stop()
 		consensus.consensusTimeout[timeoutConsensus].Start()
 		consensus.getLogger().Debug().
@@ -416,8 +448,8 @@ func (consensus *Consensus) onViewChange(msg *msg_pb.Message) {
 			Msg("[onViewChange] New Leader Start Consensus Timer and Stop View Change Timer")
 		consensus.getLogger().Debug().
 			Str("myKey", newLeaderKey.SerializeToHexStr()).
-			Uint64("viewID", consensus.viewID).
-			Uint64("block", consensus.blockNum).
+			Uint64("viewID", consensus.GetViewID()).
+			Uint64("block", consensus.BlockNum()).
 			Msg("[onViewChange] I am the New Leader")
 	}
 }
@@ -439,67 +471,18 @@ func (consensus *Consensus) onNewView(msg *msg_pb.Message) {
 	consensus.vcLock.Lock()
 	defer consensus.vcLock.Unlock()
 
-	if recvMsg.M3AggSig == nil || recvMsg.M3Bitmap == nil {
-		consensus.getLogger().Error().Msg("[onNewView] M3AggSig or M3Bitmap is nil")
-		return
-	}
-	m3Sig := recvMsg.M3AggSig
-	m3Mask := recvMsg.M3Bitmap
-
-	viewIDBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(viewIDBytes, recvMsg.ViewID)
-
-	if !consensus.Decider.IsQuorumAchievedByMask(m3Mask) {
-		consensus.getLogger().Warn().
-			Msgf("[onNewView] Quorum Not achieved")
-		return
-	}
-
-	if !m3Sig.VerifyHash(m3Mask.AggregatePublic, viewIDBytes) {
-		consensus.getLogger().Warn().
-			Str("m3Sig", m3Sig.SerializeToHexStr()).
-			Hex("m3Mask", m3Mask.Bitmap).
+	m1, err := consensus.verifyNewView(recvMsg)
+	if err != nil {
+		consensus.getLogger().Warn().Err(err).
 			Uint64("MsgViewID", recvMsg.ViewID).
-			Msg("[onNewView] Unable to Verify Aggregated Signature of M3 (ViewID) payload")
+			Uint64("MsgBlockNum", recvMsg.BlockNum).
+			Msg("[onNewView] Rejected NewView Message")
 		return
 	}
-
-	m2Mask := recvMsg.M2Bitmap
-	if recvMsg.M2AggSig != nil {
-		consensus.getLogger().Debug().Msg("[onNewView] M2AggSig (NIL) is Not Empty")
-		m2Sig := recvMsg.M2AggSig
-		if !m2Sig.VerifyHash(m2Mask.AggregatePublic, NIL) {
-			consensus.getLogger().Warn().
-				Msg("[onNewView] Unable to Verify Aggregated Signature of M2 (NIL) payload")
-			return
-		}
-	}
-
-	// check when M3 sigs > M2 sigs, then M1 (recvMsg.Payload) should not be empty
-	if m2Mask == nil || m2Mask.Bitmap == nil ||
-		(m2Mask != nil && m2Mask.Bitmap != nil &&
-			utils.CountOneBits(m3Mask.Bitmap) > utils.CountOneBits(m2Mask.Bitmap)) {
-		if len(recvMsg.Payload) <= 32 {
-			consensus.getLogger().Debug().
-				Msg("[onNewView] M1 (prepared) Type Payload Not Have Enough Length")
-			return
-		}
-		// m1 is not empty, check it's valid
-		blockHash := recvMsg.Payload[:32]
-		aggSig, mask, err := consensus.ReadSignatureBitmapPayload(recvMsg.Payload, 32)
-		if err != nil {
-			consensus.getLogger().Error().Err(err).
-				Msg("[onNewView] ReadSignatureBitmapPayload Failed")
-			return
-		}
-		if !aggSig.VerifyHash(mask.AggregatePublic, blockHash) {
-			consensus.getLogger().Warn().
-				Msg("[onNewView] Failed to Verify Signature for M1 (prepare) message")
-			return
-		}
-		copy(consensus.blockHash[:], blockHash)
-		consensus.aggregatedPrepareSig = aggSig
-		consensus.prepareBitmap = mask
+	if m1 != nil {
+		copy(consensus.blockHash[:], m1.blockHash)
+		consensus.aggregatedPrepareSig = m1.aggSig
+		consensus.prepareBitmap = m1.mask
 		// create prepared message from newview
 		preparedMsg := FBFTMessage{
 			MessageType: msg_pb.MessageType_PREPARED,
@@ -507,7 +490,7 @@ func (consensus *Consensus) onNewView(msg *msg_pb.Message) {
 			BlockNum:    recvMsg.BlockNum,
 		}
 		preparedMsg.BlockHash = common.Hash{}
-		copy(preparedMsg.BlockHash[:], blockHash[:])
+		copy(preparedMsg.BlockHash[:], m1.blockHash[:])
 		preparedMsg.Payload = make([]byte, len(recvMsg.Payload)-32)
 		copy(preparedMsg.Payload[:], recvMsg.Payload[32:])
 		preparedMsg.SenderPubkey = senderKey
@@ -515,15 +498,21 @@ func (consensus *Consensus) onNewView(msg *msg_pb.Message) {
 	}
 
 	// newView message verified success, override my state
-	consensus.viewID = recvMsg.ViewID
+	consensus.updateRound(func(round *roundState) {
+		round.viewID = recvMsg.ViewID
+		round.leader = senderKey
+	})
 	consensus.current.SetViewID(recvMsg.ViewID)
-	consensus.LeaderPubKey = senderKey
 	consensus.ResetViewChangeState()
+	consensus.leaderTracker.begin(senderKey, recvMsg.BlockNum)
+	consensus.emitPhaseEvent(
+		NewViewAccepted, senderKey.SerializeToHexStr(), strconv.FormatUint(recvMsg.ViewID, 10),
+	)
 
 	// change view and leaderKey to keep in sync with network
-	if consensus.blockNum != recvMsg.BlockNum {
+	if consensus.BlockNum() != recvMsg.BlockNum {
 		consensus.getLogger().Debug().
-			Str("newLeaderKey", consensus.LeaderPubKey.SerializeToHexStr()).
+			Str("newLeaderKey", consensus.LeaderPubKey().SerializeToHexStr()).
 			Uint64("MsgBlockNum", recvMsg.BlockNum).
 			Msg("[onNewView] New Leader Changed")
 		return
@@ -534,7 +523,7 @@ func (consensus *Consensus) onNewView(msg *msg_pb.Message) {
 	if len(recvMsg.Payload) > 32 {
 		// Construct and send the commit message
 		commitPayload := signature.ConstructCommitPayload(consensus.ChainReader,
-			new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.blockNum, consensus.viewID)
+			new(big.Int).SetUint64(consensus.epoch), consensus.blockHash, consensus.BlockNum(), consensus.GetViewID())
 		groupID := []nodeconfig.GroupID{
 			nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(consensus.ShardID))}
 		for i, key := range consensus.PubKey.PublicKey {
@@ -549,13 +538,15 @@ func (consensus *Consensus) onNewView(msg *msg_pb.Message) {
 			}
 			msgToSend := network.Bytes
 			consensus.getLogger().Info().Msg("onNewView === commit")
-			consensus.host.SendMessageToGroups(
+			if err := consensus.msgSender.SendWithoutRetry(
 				groupID,
 				p2p.ConstructMessage(msgToSend),
-			)
+			); err != nil {
+				consensus.getLogger().Warn().Err(err).Msg("[onNewView] Cannot send commit message")
+			}
 		}
 		consensus.getLogger().Debug().
-			Str("From", consensus.phase.String()).
+			Str("From", consensus.Phase().String()).
 			Str("To", FBFTCommit.String()).
 			Msg("[OnViewChange] Switching phase")
 		consensus.switchPhase(FBFTCommit, true)
@@ -564,7 +555,7 @@ func (consensus *Consensus) onNewView(msg *msg_pb.Message) {
 		consensus.getLogger().Info().Msg("onNewView === announce")
 	}
 	consensus.getLogger().Debug().
-		Str("newLeaderKey", consensus.LeaderPubKey.SerializeToHexStr()).
+		Str("newLeaderKey", consensus.LeaderPubKey().SerializeToHexStr()).
 		Msg("new leader changed")
 	consensus.getLogger().Debug().
 		Msg("validator start consensus timer and stop view change timer")
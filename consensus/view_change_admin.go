@@ -0,0 +1,164 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/pkg/errors"
+)
+
+// Errors of the forced view change
+var (
+	ErrNoViewChangeOperators   = errors.New("no view change operator is configured")
+	ErrUnknownViewChangeOp     = errors.New("not a view change operator")
+	ErrInvalidViewChangeOpSig  = errors.New("invalid signature of the view change operator")
+	ErrStaleForcedViewChangeID = errors.New("forced view change ID not beyond the current view ID")
+)
+
+// forceViewChangePrefix domain-separates the hash signed by the operators
+var forceViewChangePrefix = []byte("harmony/forceviewchange")
+
+// ViewChangeState is the snapshot of the view change state, the M1, M2 and
+// M3 votes being those collected for the view changing ID
+type ViewChangeState struct {
+	Mode           string `json:"mode"`
+	ViewID         uint64 `json:"viewId"`
+	ViewChangingID uint64 `json:"viewChangingId"`
+	BlockNum       uint64 `json:"blockNum"`
+	Leader         string `json:"leader"`
+	M1Count        int    `json:"m1Count"`
+	M1Bitmap       string `json:"m1Bitmap"`
+	M2Count        int    `json:"m2Count"`
+	M2Bitmap       string `json:"m2Bitmap"`
+	M3Count        int    `json:"m3Count"`
+	M3Bitmap       string `json:"m3Bitmap"`
+	// the forced view change being approved by the operators, if any
+	ForcedViewID    uint64 `json:"forcedViewId"`
+	ForcedApprovals int    `json:"forcedApprovals"`
+	ForcedThreshold int    `json:"forcedThreshold"`
+}
+
+func maskHex(mask *bls_cosi.Mask) string {
+	if mask == nil {
+		return ""
+	}
+	return hex.EncodeToString(mask.Bitmap)
+}
+
+// ViewChangeState returns the snapshot of the view change state
+func (consensus *Consensus) ViewChangeState() ViewChangeState {
+	consensus.vcLock.Lock()
+	defer consensus.vcLock.Unlock()
+	viewChangingID := consensus.current.ViewID()
+	state := ViewChangeState{
+		Mode:           consensus.current.Mode().String(),
+		ViewID:         consensus.GetViewID(),
+		ViewChangingID: viewChangingID,
+		BlockNum:       consensus.BlockNum(),
+		M1Count:        len(consensus.bhpSigs[viewChangingID]),
+		M1Bitmap:       maskHex(consensus.bhpBitmap[viewChangingID]),
+		M2Count:        len(consensus.nilSigs[viewChangingID]),
+		M2Bitmap:       maskHex(consensus.nilBitmap[viewChangingID]),
+		M3Count:        len(consensus.viewIDSigs[viewChangingID]),
+		M3Bitmap:       maskHex(consensus.viewIDBitmap[viewChangingID]),
+	}
+	if leader := consensus.LeaderPubKey(); leader != nil {
+		state.Leader = leader.SerializeToHexStr()
+	}
+	f := &consensus.forcedViewChange
+	f.mutex.Lock()
+	state.ForcedViewID, state.ForcedApprovals = f.viewID, len(f.approvals)
+	state.ForcedThreshold = f.threshold
+	f.mutex.Unlock()
+	return state
+}
+
+// forcedViewChange collects the approvals of the operators for forcing a
+// view change on a stuck shard
+type forcedViewChange struct {
+	mutex     sync.Mutex
+	operators map[string]*bls.PublicKey // keyed by their serialized hex
+	threshold int
+	viewID    uint64
+	approvals map[string]struct{}
+}
+
+// SetViewChangeOperators sets the keys of the operators allowed to force a
+// view change, and the number of them who must approve it
+func (consensus *Consensus) SetViewChangeOperators(keys []*bls.PublicKey, threshold int) error {
+	if len(keys) > 0 && (threshold < 1 || threshold > len(keys)) {
+		return errors.Errorf("view change threshold %d not within 1 and %d", threshold, len(keys))
+	}
+	f := &consensus.forcedViewChange
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.operators = make(map[string]*bls.PublicKey, len(keys))
+	for _, key := range keys {
+		f.operators[key.SerializeToHexStr()] = key
+	}
+	f.threshold = threshold
+	f.viewID, f.approvals = 0, nil
+	return nil
+}
+
+// ForceViewChangeHash returns the hash the operators sign to approve forcing
+// the view change to viewID on the shard
+func (consensus *Consensus) ForceViewChangeHash(viewID uint64) []byte {
+	var ids [12]byte
+	binary.LittleEndian.PutUint32(ids[:4], consensus.ShardID)
+	binary.LittleEndian.PutUint64(ids[4:], viewID)
+	return crypto.Keccak256(forceViewChangePrefix, ids[:])
+}
+
+// ForceViewChange records the approval of the operator, signed over
+// ForceViewChangeHash, for forcing the view change to viewID. The view change
+// starts once the threshold of operators approved the same viewID; an
+// approval of another viewID drops those collected so far. It returns the
+// number of approvals collected, zero once the view change started.
+func (consensus *Consensus) ForceViewChange(
+	viewID uint64, operator *bls.PublicKey, sig *bls.Sign,
+) (int, error) {
+	f := &consensus.forcedViewChange
+	f.mutex.Lock()
+	if len(f.operators) == 0 {
+		f.mutex.Unlock()
+		return 0, ErrNoViewChangeOperators
+	}
+	key := operator.SerializeToHexStr()
+	if _, ok := f.operators[key]; !ok {
+		f.mutex.Unlock()
+		return 0, ErrUnknownViewChangeOp
+	}
+	if !sig.VerifyHash(operator, consensus.ForceViewChangeHash(viewID)) {
+		f.mutex.Unlock()
+		return 0, ErrInvalidViewChangeOpSig
+	}
+	if viewID <= consensus.GetViewID() {
+		f.mutex.Unlock()
+		return 0, ErrStaleForcedViewChangeID
+	}
+	if f.approvals == nil || f.viewID != viewID {
+		f.viewID, f.approvals = viewID, map[string]struct{}{}
+	}
+	f.approvals[key] = struct{}{}
+	approvals := len(f.approvals)
+	if approvals < f.threshold {
+		f.mutex.Unlock()
+		return approvals, nil
+	}
+	f.viewID, f.approvals = 0, nil
+	f.mutex.Unlock()
+
+	consensus.getLogger().Warn().
+		Uint64("ViewChangingID", viewID).
+		Int("approvals", approvals).
+		Msg("[ForceViewChange] starting the view change approved by the operators")
+	consensus.vcLock.Lock()
+	defer consensus.vcLock.Unlock()
+	consensus.startViewChange(viewID)
+	return 0, nil
+}
@@ -0,0 +1,76 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/consensus/quorum"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestForceViewChangeApprovals(t *testing.T) {
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9903"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9903")
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := New(
+		host, shard.BeaconChainShardID, leader, multibls.GetPrivateKey(bls_cosi.RandPrivateKey()), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	consensus.SetViewID(10)
+
+	op1, op2, outsider := bls_cosi.RandPrivateKey(), bls_cosi.RandPrivateKey(), bls_cosi.RandPrivateKey()
+	if _, err := consensus.ForceViewChange(
+		11, op1.GetPublicKey(), op1.SignHash(consensus.ForceViewChangeHash(11)),
+	); err != ErrNoViewChangeOperators {
+		t.Fatalf("got %v without operators", err)
+	}
+	if err := consensus.SetViewChangeOperators(
+		[]*bls.PublicKey{op1.GetPublicKey(), op2.GetPublicKey()}, 3,
+	); err == nil {
+		t.Fatal("threshold beyond the operators accepted")
+	}
+	if err := consensus.SetViewChangeOperators(
+		[]*bls.PublicKey{op1.GetPublicKey(), op2.GetPublicKey()}, 2,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		viewID    uint64
+		signer    *bls.SecretKey
+		operator  *bls.SecretKey
+		approvals int
+		err       error
+	}{
+		{"outsider", 11, outsider, outsider, 0, ErrUnknownViewChangeOp},
+		{"forged", 11, op2, op1, 0, ErrInvalidViewChangeOpSig},
+		{"stale", 10, op1, op1, 0, ErrStaleForcedViewChangeID},
+		{"first", 11, op1, op1, 1, nil},
+		{"repeated", 11, op1, op1, 1, nil},
+		{"other view", 12, op2, op2, 1, nil},
+	}
+	for _, test := range tests {
+		approvals, err := consensus.ForceViewChange(
+			test.viewID, test.operator.GetPublicKey(),
+			test.signer.SignHash(consensus.ForceViewChangeHash(test.viewID)),
+		)
+		if err != test.err || approvals != test.approvals {
+			t.Errorf("%s: got %d approvals, error %v", test.name, approvals, err)
+		}
+	}
+	if state := consensus.ViewChangeState(); state.ForcedViewID != 12 ||
+		state.ForcedApprovals != 1 || state.ForcedThreshold != 2 {
+		t.Errorf("unexpected forced view change state %+v", state)
+	}
+}
@@ -0,0 +1,173 @@
+package consensus
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/consensus/signature"
+)
+
+var (
+	voteBatchCounter         = metrics.NewRegisteredCounter("consensus/votebatch/batches", nil)
+	voteBatchVoteCounter     = metrics.NewRegisteredCounter("consensus/votebatch/votes", nil)
+	voteBatchFallbackCounter = metrics.NewRegisteredCounter("consensus/votebatch/fallbacks", nil)
+)
+
+// pendingVote is a prepare or commit vote waiting for its signature to be verified
+type pendingVote struct {
+	pubKey *bls.PublicKey
+	sig    *bls.Sign
+	hash   []byte
+	// onVerified is called once the signature is verified, onInvalid if it is not valid
+	onVerified func()
+	onInvalid  func()
+}
+
+// voteBatcher queues the arriving votes for a short window and verifies
+// their signatures as an aggregate, so a flood of prepare or commit messages
+// costs one pairing check instead of one per vote. Each vote is weighted by a
+// random 64-bit scalar in the aggregate, so invalid signatures cannot cancel
+// each other out. If the aggregate does not verify, the votes are verified
+// one by one to identify the bad ones.
+type voteBatcher struct {
+	window  time.Duration
+	maxSize int
+	cache   *signature.VerifyCache
+	lock    sync.Mutex
+	pending []*pendingVote
+}
+
+func newVoteBatcher(window time.Duration, maxSize int, cache *signature.VerifyCache) *voteBatcher {
+	return &voteBatcher{window: window, maxSize: maxSize, cache: cache}
+}
+
+// submit queues the vote. Its callbacks are called from another goroutine,
+// so they may take the consensus lock.
+func (b *voteBatcher) submit(vote *pendingVote) {
+	b.lock.Lock()
+	b.pending = append(b.pending, vote)
+	size := len(b.pending)
+	b.lock.Unlock()
+	switch {
+	case size >= b.maxSize:
+		go b.flush()
+	case size == 1:
+		time.AfterFunc(b.window, b.flush)
+	}
+}
+
+// flush verifies the queued votes and calls their callbacks
+func (b *voteBatcher) flush() {
+	b.lock.Lock()
+	votes := b.pending
+	b.pending = nil
+	b.lock.Unlock()
+	if len(votes) == 0 {
+		return
+	}
+	voteBatchCounter.Inc(1)
+	voteBatchVoteCounter.Inc(int64(len(votes)))
+
+	// votes on different payloads cannot be aggregated together
+	groups := map[string][]*pendingVote{}
+	order := []string{}
+	for _, vote := range votes {
+		key := string(vote.hash)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], vote)
+	}
+	for _, key := range order {
+		group := groups[key]
+		valid := b.verify(group)
+		for i, vote := range group {
+			if valid[i] {
+				vote.onVerified()
+			} else if vote.onInvalid != nil {
+				vote.onInvalid()
+			}
+		}
+	}
+}
+
+// verify verifies the signatures of votes on the same hash
+func (b *voteBatcher) verify(votes []*pendingVote) []bool {
+	valid := make([]bool, len(votes))
+	unverified := []int{}
+	for i, vote := range votes {
+		if b.cache.Contains(vote.sig, vote.pubKey, vote.hash) {
+			valid[i] = true
+		} else {
+			unverified = append(unverified, i)
+		}
+	}
+	weights, ok := randomWeights(len(unverified))
+	if len(unverified) > 1 && ok {
+		var aggSig bls.Sign
+		aggPubKey := &bls.PublicKey{}
+		for j, i := range unverified {
+			aggSig.Add(weightSign(votes[i].sig, weights[j]))
+			aggPubKey.Add(weightPubKey(votes[i].pubKey, weights[j]))
+		}
+		if aggSig.VerifyHash(aggPubKey, votes[0].hash) {
+			for _, i := range unverified {
+				valid[i] = true
+				b.cache.Add(votes[i].sig, votes[i].pubKey, votes[i].hash)
+			}
+			return valid
+		}
+		voteBatchFallbackCounter.Inc(1)
+	}
+	for _, i := range unverified {
+		valid[i] = b.cache.VerifyHash(votes[i].sig, votes[i].pubKey, votes[i].hash)
+	}
+	return valid
+}
+
+// randomWeights returns the random non-zero weights of the votes of an
+// aggregate, false if no randomness is available, the votes being verified
+// one by one then
+func randomWeights(n int) ([]uint64, bool) {
+	buf := make([]byte, 8*n)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, false
+	}
+	weights := make([]uint64, n)
+	for i := range weights {
+		weights[i] = binary.BigEndian.Uint64(buf[8*i:]) | 1
+	}
+	return weights, true
+}
+
+// weightSign returns the signature multiplied by the weight, by doubling and
+// adding
+func weightSign(sig *bls.Sign, weight uint64) *bls.Sign {
+	result, addend := &bls.Sign{}, *sig
+	for ; weight > 0; weight >>= 1 {
+		if weight&1 == 1 {
+			result.Add(&addend)
+		}
+		double := addend
+		addend.Add(&double)
+	}
+	return result
+}
+
+// weightPubKey returns the public key multiplied by the weight, by doubling
+// and adding
+func weightPubKey(pubKey *bls.PublicKey, weight uint64) *bls.PublicKey {
+	result, addend := &bls.PublicKey{}, *pubKey
+	for ; weight > 0; weight >>= 1 {
+		if weight&1 == 1 {
+			result.Add(&addend)
+		}
+		double := addend
+		addend.Add(&double)
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package consensus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/consensus/signature"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+)
+
+func TestVoteBatcherFindsInvalidVote(t *testing.T) {
+	batcher := newVoteBatcher(10*time.Millisecond, 16, signature.NewVerifyCache(16))
+	hash := []byte("block hash")
+
+	var (
+		wg               sync.WaitGroup
+		lock             sync.Mutex
+		verified, failed int
+	)
+	for i := 0; i < 4; i++ {
+		key := bls_cosi.RandPrivateKey()
+		sig := key.SignHash(hash)
+		if i == 2 {
+			// signed by another key
+			sig = bls_cosi.RandPrivateKey().SignHash(hash)
+		}
+		wg.Add(1)
+		batcher.submit(&pendingVote{
+			pubKey: key.GetPublicKey(),
+			sig:    sig,
+			hash:   hash,
+			onVerified: func() {
+				lock.Lock()
+				verified++
+				lock.Unlock()
+				wg.Done()
+			},
+			onInvalid: func() {
+				lock.Lock()
+				failed++
+				lock.Unlock()
+				wg.Done()
+			},
+		})
+	}
+	wg.Wait()
+	if verified != 3 || failed != 1 {
+		t.Fatalf("expected 3 verified and 1 invalid votes, got %d and %d", verified, failed)
+	}
+	if batcher.cache.Len() != 3 {
+		t.Fatalf("expected the 3 valid votes to be cached, got %d", batcher.cache.Len())
+	}
+}
+
+func TestVoteBatcherRejectsCancellingForgeries(t *testing.T) {
+	batcher := newVoteBatcher(time.Hour, 16, signature.NewVerifyCache(16))
+	hash := []byte("block hash")
+	key1, key2 := bls_cosi.RandPrivateKey(), bls_cosi.RandPrivateKey()
+
+	// neither signature is valid, but their sum is the sum of the valid ones
+	forged1 := key1.SignHash(hash)
+	forged1.Add(key2.SignHash(hash))
+	forged2 := &bls.Sign{}
+	votes := []*pendingVote{
+		{pubKey: key1.GetPublicKey(), sig: forged1, hash: hash},
+		{pubKey: key2.GetPublicKey(), sig: forged2, hash: hash},
+	}
+
+	if valid := batcher.verify(votes); valid[0] || valid[1] {
+		t.Fatalf("expected the forged votes rejected, got %v", valid)
+	}
+	if batcher.cache.Len() != 0 {
+		t.Fatalf("expected no forged vote cached, got %d", batcher.cache.Len())
+	}
+}
+
+func TestWeightSign(t *testing.T) {
+	hash := []byte("block hash")
+	key := bls_cosi.RandPrivateKey()
+	sig := key.SignHash(hash)
+
+	sum := &bls.Sign{}
+	pubSum := &bls.PublicKey{}
+	for i := 0; i < 5; i++ {
+		sum.Add(sig)
+		pubSum.Add(key.GetPublicKey())
+	}
+	if weightSign(sig, 5).SerializeToHexStr() != sum.SerializeToHexStr() {
+		t.Error("weighted signature is not the signature added 5 times")
+	}
+	if !weightPubKey(key.GetPublicKey(), 5).IsEqual(pubSum) {
+		t.Error("weighted key is not the key added 5 times")
+	}
+	if !weightSign(sig, 5).VerifyHash(weightPubKey(key.GetPublicKey(), 5), hash) {
+		t.Error("weighted signature does not verify with the weighted key")
+	}
+}
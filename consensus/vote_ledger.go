@@ -0,0 +1,67 @@
+package consensus
+
+import (
+	"time"
+
+	"github.com/harmony-one/harmony/consensus/ledger"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/shard"
+)
+
+// voteRound is the start of a round, for the timing and view changes of its
+// record in the vote ledger
+type voteRound struct {
+	blockNum uint64
+	viewID   uint64
+	at       time.Time
+}
+
+// startVoteRound notes the start of the current round
+func (consensus *Consensus) startVoteRound() {
+	round := consensus.roundSnapshot()
+	consensus.voteRound = voteRound{round.blockNum, round.viewID, time.Now()}
+}
+
+// recordVotes puts the votes of the round committing the block in the vote
+// ledger, if the node keeps one
+func (consensus *Consensus) recordVotes(block *types.Block, prepared, committed *FBFTMessage) {
+	if consensus.VoteLedger == nil {
+		return
+	}
+	record := &ledger.Record{
+		BlockNum:      block.NumberU64(),
+		BlockHash:     block.Hash(),
+		Epoch:         block.Epoch().Uint64(),
+		ViewID:        committed.ViewID,
+		PrepareBitmap: bitmapOfPayload(prepared.Payload),
+		CommitBitmap:  bitmapOfPayload(committed.Payload),
+		CommittedAt:   unixMilli(time.Now()),
+	}
+	if committed.SenderPubkey != nil {
+		record.Leader.FromLibBLSPublicKey(committed.SenderPubkey)
+	}
+	// the start of rounds joined midway is unknown
+	if start := consensus.voteRound; start.blockNum == record.BlockNum {
+		record.StartedAt = unixMilli(start.at)
+		if record.ViewID > start.viewID {
+			record.ViewChanges = record.ViewID - start.viewID
+		}
+	}
+	if err := consensus.VoteLedger.Put(record); err != nil {
+		consensus.getLogger().Warn().Err(err).
+			Uint64("blockNum", record.BlockNum).
+			Msg("[recordVotes] Cannot record votes in vote ledger")
+	}
+}
+
+// bitmapOfPayload returns the bitmap of an aggregated signature payload
+func bitmapOfPayload(payload []byte) []byte {
+	if len(payload) <= shard.BLSSignatureSizeInBytes {
+		return nil
+	}
+	return payload[shard.BLSSignatureSizeInBytes:]
+}
+
+func unixMilli(t time.Time) uint64 {
+	return uint64(t.UnixNano() / int64(time.Millisecond))
+}
@@ -98,7 +98,7 @@ func TestCompute(t *testing.T) {
 	expectedRoster.TheirVotingPowerTotalPercentage = theirPercentage
 
 	computedRoster, err := Compute(&shard.Committee{
-		shard.BeaconChainShardID, slotList,
+		ShardID: shard.BeaconChainShardID, Slots: slotList,
 	}, big.NewInt(3))
 	if err != nil {
 		t.Error("Computed Roster failed on vote summation to one")
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+// TxDiagnosis is the outcome of a transaction of a block re-executed for
+// diagnosis
+type TxDiagnosis struct {
+	Index             int              `json:"index"`
+	Hash              common.Hash      `json:"hash"`
+	Status            uint64           `json:"status"`
+	GasUsed           uint64           `json:"gas-used"`
+	CumulativeGasUsed uint64           `json:"cumulative-gas-used"`
+	Logs              int              `json:"logs"`
+	Accounts          []common.Address `json:"accounts"` // accounts whose state it changed
+	Err               string           `json:"error,omitempty"`
+}
+
+// BlockDiagnosis compares the re-execution of a block with the claims of its
+// header. The header commits to the roots of the receipts and of the state
+// only, so the divergent transaction is the first whose outcome contradicts
+// the claimed gas used or logs bloom, -1 if none does.
+type BlockDiagnosis struct {
+	BlockNum           uint64        `json:"block-number"`
+	BlockHash          common.Hash   `json:"block-hash"`
+	ClaimedGasUsed     uint64        `json:"claimed-gas-used"`
+	LocalGasUsed       uint64        `json:"local-gas-used"`
+	ClaimedReceiptRoot common.Hash   `json:"claimed-receipt-root"`
+	LocalReceiptRoot   common.Hash   `json:"local-receipt-root"`
+	ClaimedRoot        common.Hash   `json:"claimed-root"`
+	LocalRoot          common.Hash   `json:"local-root"`
+	DivergentTx        int           `json:"divergent-tx"`
+	Reason             string        `json:"reason"`
+	Txs                []TxDiagnosis `json:"txs"`
+	// FinalAccounts are the accounts changed after the transactions, by the
+	// incoming receipts and the finalization of the block
+	FinalAccounts []common.Address `json:"final-accounts"`
+}
+
+// EnableBadBlockDiagnosis re-executes the blocks failing the verification
+// against their header and logs their diagnosis. It must be called before the
+// chain is in use.
+func (bc *BlockChain) EnableBadBlockDiagnosis() {
+	bc.diagnoseBadBlocks = true
+}
+
+// DiagnoseBlock re-executes the block on the state of its parent, tracking the
+// outcome of each transaction against the claims of the header
+func (bc *BlockChain) DiagnoseBlock(block *types.Block) (*BlockDiagnosis, error) {
+	parent := bc.GetHeaderByHash(block.ParentHash())
+	if parent == nil {
+		return nil, errors.Errorf("unknown parent %x of block %d", block.ParentHash(), block.NumberU64())
+	}
+	statedb, err := state.New(parent.Root(), bc.stateCache)
+	if err != nil {
+		return nil, err
+	}
+	statedb.TrackTouched()
+	header := block.Header()
+	d := &BlockDiagnosis{
+		BlockNum:           block.NumberU64(),
+		BlockHash:          block.Hash(),
+		ClaimedGasUsed:     header.GasUsed(),
+		ClaimedReceiptRoot: header.ReceiptHash(),
+		ClaimedRoot:        header.Root(),
+		DivergentTx:        -1,
+		Txs:                []TxDiagnosis{},
+	}
+	diverge := func(index int, reason string, args ...interface{}) {
+		if d.DivergentTx < 0 {
+			d.DivergentTx = index
+			d.Reason = errors.Errorf(reason, args...).Error()
+		}
+	}
+	observe := func(index int, hash common.Hash, receipt *types.Receipt, err error) {
+		tx := TxDiagnosis{Index: index, Hash: hash, Accounts: statedb.TouchedAccounts()}
+		if err != nil {
+			tx.Err = err.Error()
+			d.Txs = append(d.Txs, tx)
+			diverge(index, "cannot apply the transaction: %v", err)
+			return
+		}
+		tx.Status = receipt.Status
+		tx.GasUsed = receipt.GasUsed
+		tx.CumulativeGasUsed = receipt.CumulativeGasUsed
+		tx.Logs = len(receipt.Logs)
+		d.Txs = append(d.Txs, tx)
+		if receipt.CumulativeGasUsed > d.ClaimedGasUsed {
+			diverge(index, "cumulative gas used %d exceeds the %d claimed",
+				receipt.CumulativeGasUsed, d.ClaimedGasUsed)
+		}
+		bloom, claimed := types.CreateBloom(types.Receipts{receipt}), header.Bloom()
+		for i := range bloom {
+			if bloom[i]&^claimed[i] != 0 {
+				diverge(index, "logs outside of the claimed bloom")
+				break
+			}
+		}
+	}
+
+	processor := NewStateProcessor(bc.chainConfig, bc, bc.engine)
+	receipts, _, _, usedGas, _, err := processor.process(block, statedb, bc.vmConfig, observe)
+	if err != nil {
+		if d.DivergentTx < 0 {
+			d.Reason = err.Error()
+		}
+		return d, nil
+	}
+	d.LocalGasUsed = usedGas
+	d.LocalReceiptRoot = types.DeriveSha(receipts)
+	d.LocalRoot = statedb.IntermediateRoot(bc.chainConfig.IsS3(header.Epoch()))
+	d.FinalAccounts = statedb.TouchedAccounts()
+	switch {
+	case d.DivergentTx >= 0:
+	case usedGas < d.ClaimedGasUsed:
+		d.Reason = errors.Errorf("gas used %d below the %d claimed", usedGas, d.ClaimedGasUsed).Error()
+	case d.LocalReceiptRoot != d.ClaimedReceiptRoot:
+		d.Reason = "receipt root mismatch, with the gas used and logs bloom as claimed"
+	case d.LocalRoot != d.ClaimedRoot:
+		d.Reason = "state root mismatch, with the receipts as claimed"
+	default:
+		d.Reason = "no divergence found on re-execution"
+	}
+	return d, nil
+}
+
+// logBlockDiagnosis re-executes the block failing verification and logs its
+// diagnosis
+func (bc *BlockChain) logBlockDiagnosis(block *types.Block, verifyErr error) {
+	d, err := bc.DiagnoseBlock(block)
+	if err != nil {
+		utils.Logger().Warn().Err(err).
+			Uint64("block-number", block.NumberU64()).
+			Msg("[DiagnoseBlock] cannot diagnose the block")
+		return
+	}
+	logger := utils.Logger().Warn().
+		AnErr("verify-error", verifyErr).
+		Uint64("block-number", d.BlockNum).
+		Str("block-hash", d.BlockHash.Hex()).
+		Int("divergent-tx", d.DivergentTx).
+		Str("reason", d.Reason)
+	if d.DivergentTx >= 0 && d.DivergentTx < len(d.Txs) {
+		tx := d.Txs[d.DivergentTx]
+		logger = logger.
+			Str("divergent-tx-hash", tx.Hash.Hex()).
+			Interface("divergent-accounts", tx.Accounts)
+	}
+	logger.Interface("diagnosis", d).Msg("[DiagnoseBlock] block diverges from its header")
+}
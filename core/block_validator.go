@@ -27,8 +27,10 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/block"
 	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
+	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/crypto/hash"
 	"github.com/harmony-one/harmony/internal/params"
 	"github.com/pkg/errors"
 )
@@ -56,15 +58,8 @@ func NewBlockValidator(config *params.ChainConfig, blockchain *BlockChain, engin
 // ValidateBody verifies the block header's transaction root.
 // The headers are assumed to be already validated at this point.
 func (v *BlockValidator) ValidateBody(block *types.Block) error {
-	// Check whether the block's known, and if not, that it's linkable
-	if v.bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
-		return ErrKnownBlock
-	}
-	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
-		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
-			return consensus_engine.ErrUnknownAncestor
-		}
-		return consensus_engine.ErrPrunedAncestor
+	if err := v.bc.checkLinkable(block); err != nil {
+		return err
 	}
 	// Header validity is known at this point, check the uncles and transactions
 	header := block.Header()
@@ -80,6 +75,21 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	return nil
 }
 
+// checkLinkable checks whether the block's known, and if not, that it's
+// linkable
+func (bc *BlockChain) checkLinkable(block *types.Block) error {
+	if bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
+		return ErrKnownBlock
+	}
+	if !bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
+		if !bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
+			return consensus_engine.ErrUnknownAncestor
+		}
+		return consensus_engine.ErrPrunedAncestor
+	}
+	return nil
+}
+
 // ValidateState validates the various changes that happen after a state
 // transition, such as amount of used gas, the receipt roots and the state root
 // itself. ValidateState returns a database batch if the validation was a success
@@ -179,8 +189,28 @@ func CalcGasLimit(parent *types.Block, gasFloor, gasCeil uint64) uint64 {
 	return limit
 }
 
-// ValidateCXReceiptsProof checks whether the given CXReceiptsProof is consistency with itself
+// cxProofResult is the cached result of a CXReceiptsProof verification
+type cxProofResult struct {
+	err error
+}
+
+// ValidateCXReceiptsProof checks whether the given CXReceiptsProof is consistency with itself.
+// The results are cached by proof hash until a new shard state arrives, so that
+// a proof is verified once on ingest and reused when proposing.
 func (v *BlockValidator) ValidateCXReceiptsProof(cxp *types.CXReceiptsProof) error {
+	key := hash.FromRLP(cxp)
+	if cached, ok := v.bc.cxProofCache.Get(key); ok {
+		return cached.(cxProofResult).err
+	}
+	err := v.validateCXReceiptsProof(cxp)
+	// A missing shard state may still arrive, so the proof is verified again then
+	if errors.Cause(err) != rawdb.ErrNoShardStateFromDB {
+		v.bc.cxProofCache.Add(key, cxProofResult{err})
+	}
+	return err
+}
+
+func (v *BlockValidator) validateCXReceiptsProof(cxp *types.CXReceiptsProof) error {
 	if !v.config.AcceptsCrossTx(cxp.Header.Epoch()) {
 		return errors.New("[ValidateCXReceiptsProof] cross shard receipt received before cx fork")
 	}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -64,6 +65,10 @@ var (
 	// errExceedMaxPendingSlashes ..
 	errExceedMaxPendingSlashes = errors.New("exceeed max pending slashes")
 	errNilEpoch                = errors.New("nil epoch for voting power computation")
+	// ErrNoCXReceipts is returned when a block sent no receipts to a shard
+	ErrNoCXReceipts = errors.New("no cross shard receipts to the destination shard")
+	// ErrNoCommitSig is returned when the commit signature of a block is unknown
+	ErrNoCommitSig = errors.New("commit signature of the block not known")
 )
 
 const (
@@ -84,6 +89,7 @@ const (
 	validatorListByDelegatorCacheLimit = 1024
 	pendingCrossLinksCacheLimit        = 2
 	blockAccumulatorCacheLimit         = 256
+	cxProofCacheLimit                  = 1024
 	maxPendingSlashes                  = 512
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	BlockChainVersion = 3
@@ -126,6 +132,7 @@ type BlockChain struct {
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
+	rewardFeed    event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -154,6 +161,8 @@ type BlockChain struct {
 	validatorListByDelegatorCache *lru.Cache    // Cache of validator list by delegator
 	pendingCrossLinksCache        *lru.Cache    // Cache of last pending crosslinks
 	blockAccumulatorCache         *lru.Cache    // Cache of block accumulators
+	cxProofCache                  *lru.Cache    // Cache of CXReceiptsProof verification results
+	verifiedBlocks                *lru.Cache    // Cache of the checks passed by the blocks to insert
 	quit                          chan struct{} // blockchain quit channel
 	running                       int32         // running must be called atomically
 	// procInterrupt must be atomically called
@@ -167,6 +176,16 @@ type BlockChain struct {
 	badBlocks      *lru.Cache              // Bad block cache
 	shouldPreserve func(*types.Block) bool // Function used to determine whether should preserve the given block.
 	pendingSlashes slash.Records
+	epochChain     *EpochChain // Last header and shard state of each epoch
+	insertPipeline *InsertPipeline
+	bulkImport     *bulkImport  // deferred writes of an ongoing bulk import, if any
+	pruner         *statePruner // state pruning in steps between imports, if enabled
+	rewardIndex    bool         // whether the payouts of the blocks are indexed
+	// whether the blocks failing verification against their header are diagnosed
+	diagnoseBadBlocks bool
+	// returns the beacon chain the shard state of the blocks not confirmed
+	// by consensus is verified against, nil for no verification
+	shardStateBeacon func() (consensus_engine.ChainReader, error)
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -199,6 +218,8 @@ func NewBlockChain(
 	validatorListByDelegatorCache, _ := lru.New(validatorListByDelegatorCacheLimit)
 	pendingCrossLinksCache, _ := lru.New(pendingCrossLinksCacheLimit)
 	blockAccumulatorCache, _ := lru.New(blockAccumulatorCacheLimit)
+	cxProofCache, _ := lru.New(cxProofCacheLimit)
+	verifiedBlocks, _ := lru.New(verifiedBlocksCacheLimit)
 
 	bc := &BlockChain{
 		chainConfig:                   chainConfig,
@@ -223,11 +244,15 @@ func NewBlockChain(
 		validatorListByDelegatorCache: validatorListByDelegatorCache,
 		pendingCrossLinksCache:        pendingCrossLinksCache,
 		blockAccumulatorCache:         blockAccumulatorCache,
+		cxProofCache:                  cxProofCache,
+		verifiedBlocks:                verifiedBlocks,
 		engine:                        engine,
 		vmConfig:                      vmConfig,
 		badBlocks:                     badBlocks,
 		pendingSlashes:                slash.Records{},
 	}
+	bc.epochChain = NewEpochChain(db)
+	bc.insertPipeline = newInsertPipeline(bc)
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
 
@@ -248,6 +273,7 @@ func NewBlockChain(
 	}
 	// Take ownership of this particular state
 	go bc.update()
+	go bc.insertPipeline.loop()
 	return bc, nil
 }
 
@@ -274,6 +300,9 @@ func (bc *BlockChain) ValidateNewBlock(block *types.Block) error {
 		block, state, receipts, cxReceipts, usedGas,
 	); err != nil {
 		bc.reportBlock(block, receipts, err)
+		if bc.diagnoseBadBlocks {
+			go bc.logBlockDiagnosis(block, err)
+		}
 		return err
 	}
 	return nil
@@ -314,11 +343,23 @@ func (bc *BlockChain) loadLastState() error {
 	}
 	// Make sure the entire head block is available
 	currentBlock := bc.GetBlockByHash(head)
+	if currentBlock == nil {
+		// Partly written head, rewind to the last stored block below it, the
+		// integrity check deleting the blocks above
+		currentBlock = bc.lastStoredBlockBelow(head)
+	}
 	if currentBlock == nil {
 		// Corrupt or empty database, init from scratch
 		utils.Logger().Warn().Str("hash", head.Hex()).Msg("Head block missing, resetting chain")
 		return bc.Reset()
 	}
+	// Roll back the blocks of an interrupted bulk import past its last flush
+	if number, ok := rawdb.ReadBulkImportMarker(bc.db); ok {
+		if err := bc.rewindBulkImport(&currentBlock, number); err != nil {
+			return err
+		}
+		rawdb.DeleteBulkImportMarker(bc.db)
+	}
 	// Make sure the state associated with the block is available
 	if _, err := state.New(currentBlock.Root(), bc.stateCache); err != nil {
 		// Dangling block without a state associated, init from scratch
@@ -405,6 +446,7 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.blockCache.Purge()
 	bc.futureBlocks.Purge()
 	bc.shardStateCache.Purge()
+	bc.cxProofCache.Purge()
 
 	// Rewind the block chain, ensuring we don't end up with a stateless head block
 	if currentBlock := bc.CurrentBlock(); currentBlock != nil && currentHeader.Number().Uint64() < currentBlock.NumberU64() {
@@ -525,6 +567,46 @@ func (bc *BlockChain) ResetWithGenesisBlock(genesis *types.Block) error {
 	return nil
 }
 
+// ResetWithCheckpointBlock makes the given block the head of the chain, so
+// that the chain continues from a trusted checkpoint instead of from genesis.
+// The state of the block must already be present in the database.
+func (bc *BlockChain) ResetWithCheckpointBlock(block *types.Block) error {
+	if _, err := state.New(block.Root(), bc.stateCache); err != nil {
+		return err
+	}
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	rawdb.WriteBlock(bc.db, block)
+	bc.insert(block)
+
+	utils.Logger().Info().
+		Uint64("number", block.NumberU64()).
+		Str("hash", block.Hash().Hex()).
+		Msg("Reset chain head to checkpoint block")
+	return nil
+}
+
+// lastStoredBlockBelow returns the highest canonical block fully stored below
+// the block of the given hash, nil if the number of the block is unknown or
+// no block below it is stored.
+func (bc *BlockChain) lastStoredBlockBelow(hash common.Hash) *types.Block {
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	if number == nil {
+		return nil
+	}
+	for n := *number; n > 0; n-- {
+		if blk := rawdb.ReadBlock(bc.db, rawdb.ReadCanonicalHash(bc.db, n-1), n-1); blk != nil {
+			utils.Logger().Warn().
+				Str("hash", hash.Hex()).
+				Uint64("number", n-1).
+				Msg("Head block missing, rewinding chain")
+			return blk
+		}
+	}
+	return nil
+}
+
 // repair tries to repair the current blockchain by rolling back the current block
 // until one with associated state is found. This is needed to fix incomplete db
 // writes caused either by crashes/power outages, or simply non-committed tries.
@@ -822,6 +904,10 @@ func (bc *BlockChain) Stop() {
 
 	bc.wg.Wait()
 
+	if err := bc.EndBulkImport(); err != nil {
+		utils.Logger().Error().Err(err).Msg("Failed to flush bulk import")
+	}
+
 	// Ensure the state of a recent block is also stored to disk before exiting.
 	// We're writing three different states to catch different restart scenarios:
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
@@ -1114,13 +1200,22 @@ func (bc *BlockChain) WriteBlockWithState(
 	if err != nil {
 		return NonStatTy, err
 	}
+	if bc.pruner != nil {
+		bc.pruner.track(root)
+	}
 
 	// Flush trie state into disk if it's archival node or the block is epoch block
 	triedb := bc.stateCache.TrieDB()
-	if bc.cacheConfig.Disabled || len(block.Header().ShardState()) > 0 {
+	if bc.bulkImport != nil {
+		// Deferred until the next flush of the bulk import
+		bc.addBulkImportRoot(block, root)
+	} else if bc.cacheConfig.Disabled || len(block.Header().ShardState()) > 0 {
 		if err := triedb.Commit(root, false); err != nil {
 			return NonStatTy, err
 		}
+		if bc.pruner != nil {
+			bc.pruner.commit(root)
+		}
 	} else {
 		// Full but not archive node, do proper garbage collection
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
@@ -1152,6 +1247,9 @@ func (bc *BlockChain) WriteBlockWithState(
 				}
 				// Flush an entire trie and restart the counters
 				triedb.Commit(header.Root(), true)
+				if bc.pruner != nil {
+					bc.pruner.commit(header.Root())
+				}
 				lastWrite = chosen
 				bc.gcproc = 0
 			}
@@ -1168,8 +1266,17 @@ func (bc *BlockChain) WriteBlockWithState(
 	}
 
 	batch := bc.db.NewBatch()
-	// Write the raw block
+	// Write the raw block, with its commit signature if known
 	rawdb.WriteBlock(batch, block)
+	// Write the commit signature of the parent carried by the block in the
+	// same batch, so the signature of the latest committed block survives a
+	// crash whenever the block does
+	parentSig := bc.parentCommitSig(block)
+	if parentSig != nil {
+		if err := rawdb.WriteBlockCommitSig(batch, block.NumberU64()-1, parentSig); err != nil {
+			return NonStatTy, err
+		}
+	}
 
 	// Write offchain data
 	if status, err := bc.CommitOffChainData(
@@ -1180,9 +1287,13 @@ func (bc *BlockChain) WriteBlockWithState(
 	}
 
 	// Write the positional metadata for transaction/receipt lookups and preimages
-	rawdb.WriteTxLookupEntries(batch, block)
-	rawdb.WriteCxLookupEntries(batch, block)
-	rawdb.WritePreimages(batch, block.NumberU64(), state.Preimages())
+	var lookups rawdb.DatabaseWriter = batch
+	if bc.bulkImport != nil {
+		lookups = bc.bulkImport.batch
+	}
+	rawdb.WriteTxLookupEntries(lookups, block)
+	rawdb.WriteCxLookupEntries(lookups, block)
+	rawdb.WritePreimages(lookups, block.NumberU64(), state.Preimages())
 
 	// Update current block
 	bc.insertWithWriter(batch, block)
@@ -1190,11 +1301,27 @@ func (bc *BlockChain) WriteBlockWithState(
 	if err := batch.Write(); err != nil {
 		return NonStatTy, err
 	}
+	if parentSig != nil {
+		bc.lastCommitsCache.Add("commitSig"+strconv.FormatUint(block.NumberU64()-1, 10), parentSig)
+	}
+	if curSig := block.GetCurrentCommitSig(); len(curSig) > shard.BLSSignatureSizeInBytes {
+		bc.lastCommitsCache.Add("commitSig"+strconv.FormatUint(block.NumberU64(), 10), curSig)
+	}
+	if bc.bulkImport != nil && len(bc.bulkImport.roots) >= bc.bulkImport.interval {
+		if err := bc.flushBulkImport(); err != nil {
+			return NonStatTy, err
+		}
+	}
 
 	bc.futureBlocks.Remove(block.Hash())
 	return CanonStatTy, nil
 }
 
+// InsertPipeline returns the pipeline inserting the blocks into the chain one
+// at a time, which the block producers besides the chain itself should use
+// instead of InsertChain.
+func (bc *BlockChain) InsertPipeline() *InsertPipeline { return bc.insertPipeline }
+
 // InsertChain attempts to insert the given batch of blocks in to the canonical
 // chain or, otherwise, create a fork. If an error is returned it will return
 // the index number of the failing block as well an error describing what went
@@ -1204,6 +1331,7 @@ func (bc *BlockChain) WriteBlockWithState(
 func (bc *BlockChain) InsertChain(chain types.Blocks, verifyHeaders bool) (int, error) {
 	n, events, logs, err := bc.insertChain(chain, verifyHeaders)
 	bc.PostChainEvents(events, logs)
+	bc.pruneState()
 	return n, err
 }
 
@@ -1250,6 +1378,11 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifyHeaders bool) (int,
 
 	var verifyHeadersResults <-chan error
 
+	// The headers verified ahead, ex: by consensus, are not verified again
+	if verifyHeaders && bc.headersVerified(chain) {
+		verifyHeaders = false
+		verifiedSkipCounter.Inc(int64(len(chain)))
+	}
 	// If the block header chain has not been verified, conduct header verification here.
 	if verifyHeaders {
 		headers := make([]*block.Header, len(chain))
@@ -1283,7 +1416,12 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifyHeaders bool) (int,
 			err = <-verifyHeadersResults
 		}
 		if err == nil {
-			err = bc.Validator().ValidateBody(block)
+			if bc.blockVerified(block, VerifiedBody) {
+				verifiedSkipCounter.Inc(1)
+				err = bc.checkLinkable(block)
+			} else {
+				err = bc.Validator().ValidateBody(block)
+			}
 		}
 		switch {
 		case err == ErrKnownBlock:
@@ -1406,6 +1544,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifyHeaders bool) (int,
 			coalescedLogs = append(coalescedLogs, logs...)
 			blockInsertTimer.UpdateSince(bstart)
 			events = append(events, ChainEvent{block, block.Hash(), logs})
+			if ev := newRewardEvent(block, payout); ev != nil {
+				events = append(events, *ev)
+			}
 			lastCanon = block
 
 			// Only count canonical blocks for GC processing time
@@ -1505,6 +1646,9 @@ func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 
 		case ChainSideEvent:
 			bc.chainSideFeed.Send(ev)
+
+		case RewardEvent:
+			bc.rewardFeed.Send(ev)
 		}
 	}
 }
@@ -1716,6 +1860,11 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeRewardEvent registers a subscription of RewardEvent.
+func (bc *BlockChain) SubscribeRewardEvent(ch chan<- RewardEvent) event.Subscription {
+	return bc.scope.Track(bc.rewardFeed.Subscribe(ch))
+}
+
 // ReadShardState retrieves sharding state given the epoch number.
 func (bc *BlockChain) ReadShardState(epoch *big.Int) (*shard.State, error) {
 	cacheKey := string(epoch.Bytes())
@@ -1731,6 +1880,15 @@ func (bc *BlockChain) ReadShardState(epoch *big.Int) (*shard.State, error) {
 	return shardState, nil
 }
 
+// EpochChain returns the epoch chain of the blockchain.
+func (bc *BlockChain) EpochChain() *EpochChain { return bc.epochChain }
+
+// EpochShardState returns the shard state of the given epoch from the epoch
+// chain.
+func (bc *BlockChain) EpochShardState(epoch *big.Int) (*shard.State, error) {
+	return bc.epochChain.ShardState(epoch)
+}
+
 // WriteShardStateBytes saves the given sharding state under the given epoch number.
 func (bc *BlockChain) WriteShardStateBytes(db rawdb.DatabaseWriter,
 	epoch *big.Int, shardState []byte,
@@ -1745,12 +1903,14 @@ func (bc *BlockChain) WriteShardStateBytes(db rawdb.DatabaseWriter,
 	}
 	cacheKey := string(epoch.Bytes())
 	bc.shardStateCache.Add(cacheKey, decodeShardState)
+	// The committees proofs were verified against may have changed
+	bc.cxProofCache.Purge()
 	return decodeShardState, nil
 }
 
 // ReadCommitSig retrieves the commit signature on a block.
 func (bc *BlockChain) ReadCommitSig(blockNum uint64) ([]byte, error) {
-	if cached, ok := bc.lastCommitsCache.Get("commitSig" + string(blockNum)); ok {
+	if cached, ok := bc.lastCommitsCache.Get("commitSig" + strconv.FormatUint(blockNum, 10)); ok {
 		lastCommits := cached.([]byte)
 		return lastCommits, nil
 	}
@@ -1761,13 +1921,23 @@ func (bc *BlockChain) ReadCommitSig(blockNum uint64) ([]byte, error) {
 	return lastCommits, nil
 }
 
+// parentCommitSig returns the commit signature and bitmap of the parent of the
+// block, carried by its header, or nil for the genesis child
+func (bc *BlockChain) parentCommitSig(block *types.Block) []byte {
+	if block.NumberU64() <= 1 {
+		return nil
+	}
+	lastSig := block.Header().LastCommitSignature()
+	return append(lastSig[:], block.Header().LastCommitBitmap()...)
+}
+
 // WriteCommitSig saves the commits signatures signed on a block.
 func (bc *BlockChain) WriteCommitSig(blockNum uint64, lastCommits []byte) error {
 	err := rawdb.WriteBlockCommitSig(bc.db, blockNum, lastCommits)
 	if err != nil {
 		return err
 	}
-	bc.lastCommitsCache.Add("commitSig"+string(blockNum), lastCommits)
+	bc.lastCommitsCache.Add("commitSig"+strconv.FormatUint(blockNum, 10), lastCommits)
 	return nil
 }
 
@@ -2176,6 +2346,43 @@ func (bc *BlockChain) CXMerkleProof(toShardID uint32, block *types.Block) (*type
 	return proof, nil
 }
 
+// CXReceiptsProof builds the proof of the cross shard receipts sent by a block
+// of this chain to the destination shard, signed by the commit signature and
+// bitmap of the block, read from its child header or from the stored commits
+func (bc *BlockChain) CXReceiptsProof(toShardID uint32, block *types.Block) (*types.CXReceiptsProof, error) {
+	receipts, err := bc.ReadCXReceipts(toShardID, block.NumberU64(), block.Hash())
+	if err != nil || len(receipts) == 0 {
+		return nil, ErrNoCXReceipts
+	}
+	merkleProof, err := bc.CXMerkleProof(toShardID, block)
+	if err != nil {
+		return nil, err
+	}
+	if merkleProof == nil {
+		return nil, ErrNoCXReceipts
+	}
+
+	var commitSig, commitBitmap []byte
+	if child := bc.GetHeaderByNumber(block.NumberU64() + 1); child != nil && child.ParentHash() == block.Hash() {
+		sig := child.LastCommitSignature()
+		commitSig, commitBitmap = sig[:], child.LastCommitBitmap()
+	} else if sigAndBitmap, err := bc.ReadCommitSig(block.NumberU64()); err == nil &&
+		len(sigAndBitmap) > shard.BLSSignatureSizeInBytes {
+		commitSig = sigAndBitmap[:shard.BLSSignatureSizeInBytes]
+		commitBitmap = sigAndBitmap[shard.BLSSignatureSizeInBytes:]
+	} else {
+		return nil, ErrNoCommitSig
+	}
+
+	return &types.CXReceiptsProof{
+		Receipts:     receipts,
+		MerkleProof:  merkleProof,
+		Header:       block.Header(),
+		CommitSig:    commitSig,
+		CommitBitmap: commitBitmap,
+	}, nil
+}
+
 // WriteCXReceiptsProofSpent mark the CXReceiptsProof list with given unspent status
 // true: unspent, false: spent
 func (bc *BlockChain) WriteCXReceiptsProofSpent(db rawdb.DatabaseWriter, cxps []*types.CXReceiptsProof) {
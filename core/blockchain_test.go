@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	chain2 "github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/shard"
+)
+
+// testCommitSig returns the commit signature and bitmap of a test block
+func testCommitSig(number uint64) []byte {
+	sig := [shard.BLSSignatureSizeInBytes]byte{byte(number)}
+	return append(sig[:], 0x01)
+}
+
+// generateSignedTestBlocks generates n empty blocks on the dump test genesis,
+// each carrying the commit signature of its parent
+func generateSignedTestBlocks(n int) types.Blocks {
+	db := ethdb.NewMemDatabase()
+	genesis := dumpTestGenesis.MustCommit(db)
+	blocks, _ := GenerateChain(dumpTestGenesis.Config, genesis, unsealedEngine{chain2.Engine}, db, n, func(i int, gen *BlockGen) {
+		// the genesis is signed by no committee
+		if i > 0 {
+			sig := [shard.BLSSignatureSizeInBytes]byte{}
+			copy(sig[:], testCommitSig(uint64(i)))
+			gen.header.SetLastCommitSignature(sig)
+			gen.header.SetLastCommitBitmap([]byte{0x01})
+		}
+	})
+	return blocks
+}
+
+func TestWriteParentCommitSig(t *testing.T) {
+	blocks := generateSignedTestBlocks(3)
+	blocks[2].SetCurrentCommitSig(testCommitSig(3))
+	bc := newDumpTestChain(t, blocks)
+	defer bc.Stop()
+
+	for _, test := range []struct {
+		name     string
+		number   uint64
+		expected []byte
+	}{
+		{"genesis", 0, nil},
+		{"carried by the next block", 1, testCommitSig(1)},
+		{"carried by the head", 2, testCommitSig(2)},
+		{"head signed in consensus", 3, testCommitSig(3)},
+	} {
+		stored, _ := rawdb.ReadBlockCommitSig(bc.db, test.number)
+		if !bytes.Equal(stored, test.expected) {
+			t.Errorf("%s: expected %x stored, got %x", test.name, test.expected, stored)
+		}
+		read, _ := bc.ReadCommitSig(test.number)
+		if !bytes.Equal(read, test.expected) {
+			t.Errorf("%s: expected %x read, got %x", test.name, test.expected, read)
+		}
+	}
+
+	// the signatures survive a restart
+	bc.Stop()
+	restarted, err := NewBlockChain(bc.db, nil, dumpTestGenesis.Config, unsealedEngine{chain2.Engine}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Stop()
+	for number := uint64(1); number <= 3; number++ {
+		if read, _ := restarted.ReadCommitSig(number); !bytes.Equal(read, testCommitSig(number)) {
+			t.Errorf("expected the signature of block %d kept, got %x", number, read)
+		}
+	}
+}
+
+func TestCXReceiptsProof(t *testing.T) {
+	blocks := generateSignedTestBlocks(4)
+	blocks[2].SetCurrentCommitSig(testCommitSig(3))
+	bc := newDumpTestChain(t, blocks)
+	defer bc.Stop()
+	to := common.Address{0x11}
+	for _, blk := range blocks {
+		receipts := types.CXReceipts{{TxHash: common.Hash{byte(blk.NumberU64())}, To: &to, ToShardID: 1, Amount: big.NewInt(1)}}
+		if err := rawdb.WriteCXReceipts(bc.db, 1, blk.NumberU64(), blk.Hash(), receipts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		name      string
+		toShardID uint32
+		block     *types.Block
+		sig       []byte // commit signature and bitmap of the proof
+		err       error
+	}{
+		{"signed by the child header", 1, blocks[0], testCommitSig(1), nil},
+		{"signed in the stored commits", 1, blocks[2], testCommitSig(3), nil},
+		{"no receipts to the shard", 2, blocks[0], nil, ErrNoCXReceipts},
+		{"unsigned head", 1, blocks[3], nil, ErrNoCommitSig},
+	} {
+		proof, err := bc.CXReceiptsProof(test.toShardID, test.block)
+		if err != test.err {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if proof.Header.Hash() != test.block.Hash() || len(proof.Receipts) != 1 ||
+			proof.Receipts[0].TxHash != (common.Hash{byte(test.block.NumberU64())}) {
+			t.Errorf("%s: expected the receipts of block %d, got %+v", test.name, test.block.NumberU64(), proof)
+		}
+		if proof.MerkleProof == nil || proof.MerkleProof.BlockHash != test.block.Hash() ||
+			len(proof.MerkleProof.ShardIDs) != 1 || proof.MerkleProof.ShardIDs[0] != 1 {
+			t.Errorf("%s: unexpected merkle proof %+v", test.name, proof.MerkleProof)
+		}
+		if sig := append(append([]byte{}, proof.CommitSig...), proof.CommitBitmap...); !bytes.Equal(sig, test.sig) {
+			t.Errorf("%s: expected the commit signature %x, got %x", test.name, test.sig, sig)
+		}
+	}
+}
+
+func TestResetWithCheckpointBlock(t *testing.T) {
+	blocks := generateDumpTestBlocks(3)
+	bc := newDumpTestChain(t, nil)
+	defer bc.Stop()
+
+	// the block of no stored state is rejected, the head left at genesis
+	header := types.CopyHeader(blocks[2].Header())
+	header.SetRoot(common.Hash{1})
+	if err := bc.ResetWithCheckpointBlock(types.NewBlockWithHeader(header)); err == nil {
+		t.Error("expected the block of no stored state rejected")
+	}
+	if number := bc.CurrentBlock().NumberU64(); number != 0 {
+		t.Errorf("expected the head kept at genesis, got %d", number)
+	}
+
+	checkpoint := blocks[2]
+	if err := bc.ResetWithCheckpointBlock(checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	if head := bc.CurrentBlock(); head.Hash() != checkpoint.Hash() {
+		t.Errorf("expected head %x, got %x", checkpoint.Hash(), head.Hash())
+	}
+	if block := bc.GetBlockByNumber(3); block == nil || block.Hash() != checkpoint.Hash() {
+		t.Errorf("expected the checkpoint block canonical")
+	}
+	if hash := rawdb.ReadCanonicalHash(bc.db, 3); hash != checkpoint.Hash() {
+		t.Errorf("expected canonical hash %x, got %x", checkpoint.Hash(), hash)
+	}
+
+	// the blocks after the checkpoint are inserted on it
+	next := generateDumpTestBlocks(4)[3]
+	if _, err := bc.InsertChain(types.Blocks{next}, false); err != nil {
+		t.Fatal(err)
+	}
+	if head := bc.CurrentBlock(); head.Hash() != next.Hash() {
+		t.Errorf("expected head %x, got %x", next.Hash(), head.Hash())
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	staking "github.com/harmony-one/harmony/staking/types"
+)
+
+// bulkImportRoot is the state root of a block written during a bulk import
+type bulkImportRoot struct {
+	number  uint64
+	root    common.Hash
+	persist bool // the state must be on disk, see WriteBlockWithState
+}
+
+// bulkImport holds the writes deferred by a bulk import until its next flush
+type bulkImport struct {
+	interval int
+	roots    []bulkImportRoot
+	batch    ethdb.Batch // lookup entries and preimages of the written blocks
+}
+
+// BeginBulkImport switches the chain to bulk import, in which the state tries,
+// lookup entries and preimages of the written blocks are flushed to disk every
+// interval blocks instead of with each block. An interrupted bulk import is
+// rolled back to its last flush on the next start.
+func (bc *BlockChain) BeginBulkImport(interval int) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.bulkImport != nil || interval <= 1 {
+		return
+	}
+	bc.bulkImport = &bulkImport{
+		interval: interval,
+		batch:    bc.db.NewBatch(),
+	}
+	rawdb.WriteBulkImportMarker(bc.db, bc.CurrentBlock().NumberU64())
+	utils.Logger().Info().
+		Int("interval", interval).
+		Uint64("head", bc.CurrentBlock().NumberU64()).
+		Msg("[BulkImport] Started bulk import")
+}
+
+// EndBulkImport flushes the blocks written since the last flush and switches
+// the chain back to writing each block on its own.
+func (bc *BlockChain) EndBulkImport() error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.bulkImport == nil {
+		return nil
+	}
+	err := bc.flushBulkImport()
+	bc.bulkImport = nil
+	if err != nil {
+		return err
+	}
+	rawdb.DeleteBulkImportMarker(bc.db)
+	utils.Logger().Info().
+		Uint64("head", bc.CurrentBlock().NumberU64()).
+		Msg("[BulkImport] Finished bulk import")
+	return nil
+}
+
+// addBulkImportRoot keeps the state root of the block in memory until the next
+// flush. This method assumes that the `mu` mutex is held.
+func (bc *BlockChain) addBulkImportRoot(block *types.Block, root common.Hash) {
+	triedb := bc.stateCache.TrieDB()
+	triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
+	bc.bulkImport.roots = append(bc.bulkImport.roots, bulkImportRoot{
+		number:  block.NumberU64(),
+		root:    root,
+		persist: bc.cacheConfig.Disabled || len(block.Header().ShardState()) > 0,
+	})
+
+	// Bound the memory held by the tries, regardless of the flush interval
+	var (
+		nodes, imgs = triedb.Size()
+		limit       = common.StorageSize(bc.cacheConfig.TrieNodeLimit) * 1024 * 1024
+	)
+	if nodes > limit || imgs > 4*1024*1024 {
+		triedb.Cap(limit - ethdb.IdealBatchSize)
+	}
+}
+
+// flushBulkImport writes the deferred writes of the bulk import to disk along
+// with the state of the current block, and moves the recovery marker to it.
+// This method assumes that the `mu` mutex is held.
+func (bc *BlockChain) flushBulkImport() error {
+	bi := bc.bulkImport
+	if len(bi.roots) == 0 {
+		return nil
+	}
+	triedb := bc.stateCache.TrieDB()
+	head := bc.CurrentBlock()
+	defer func() {
+		for _, r := range bi.roots {
+			if bc.cacheConfig.Disabled {
+				triedb.Dereference(r.root)
+			} else {
+				bc.triegc.Push(r.root, -int64(r.number))
+			}
+		}
+		bi.roots = bi.roots[:0]
+	}()
+
+	for _, r := range bi.roots {
+		if r.persist {
+			if err := triedb.Commit(r.root, false); err != nil {
+				return err
+			}
+		}
+	}
+	if err := triedb.Commit(head.Root(), false); err != nil {
+		return err
+	}
+	if bc.pruner != nil {
+		bc.pruner.commit(head.Root())
+	}
+	if err := bi.batch.Write(); err != nil {
+		return err
+	}
+	bi.batch.Reset()
+
+	// Garbage collect the tries below the write retention, as is done for
+	// each block outside of bulk imports
+	if current := head.NumberU64(); !bc.cacheConfig.Disabled && current > triesInMemory {
+		chosen := current - triesInMemory
+		for !bc.triegc.Empty() {
+			root, number := bc.triegc.Pop()
+			if uint64(-number) > chosen {
+				bc.triegc.Push(root, number)
+				break
+			}
+			triedb.Dereference(root.(common.Hash))
+		}
+	}
+	rawdb.WriteBulkImportMarker(bc.db, head.NumberU64())
+	utils.Logger().Debug().
+		Int("blocks", len(bi.roots)).
+		Uint64("head", head.NumberU64()).
+		Msg("[BulkImport] Flushed bulk import")
+	return nil
+}
+
+// rewindBulkImport rolls the head block back to the last flush of a bulk
+// import interrupted by a crash, above which the state may be incomplete. The
+// head hashes are reset to the block and the canonical hashes above it are
+// removed, so the blocks rolled back are synced again.
+// This method assumes that the `mu` mutex is held.
+func (bc *BlockChain) rewindBulkImport(head **types.Block, number uint64) error {
+	oldNumber := (*head).NumberU64()
+	valsToRemove := map[common.Address]struct{}{}
+	for (*head).NumberU64() > number {
+		lastSig := (*head).Header().LastCommitSignature()
+		sigAndBitMap := append(lastSig[:], (*head).Header().LastCommitBitmap()...)
+		bc.WriteCommitSig((*head).NumberU64()-1, sigAndBitMap)
+
+		for _, stkTxn := range (*head).StakingTransactions() {
+			if stkTxn.StakingType() == staking.DirectiveCreateValidator {
+				addr, err := stkTxn.SenderAddress()
+				if err != nil {
+					return err
+				}
+				valsToRemove[addr] = struct{}{}
+			}
+		}
+		parent := bc.GetBlock((*head).ParentHash(), (*head).NumberU64()-1)
+		if parent == nil {
+			break
+		}
+		*head = parent
+	}
+	for n := oldNumber; n > (*head).NumberU64(); n-- {
+		rawdb.DeleteCanonicalHash(bc.db, n)
+	}
+	rawdb.WriteHeadBlockHash(bc.db, (*head).Hash())
+	rawdb.WriteHeadHeaderHash(bc.db, (*head).Hash())
+	rawdb.WriteHeadFastBlockHash(bc.db, (*head).Hash())
+	utils.Logger().Warn().
+		Uint64("number", (*head).NumberU64()).
+		Str("hash", (*head).Hash().Hex()).
+		Msg("[BulkImport] Rewound interrupted bulk import")
+	return bc.removeInValidatorList(valsToRemove)
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	chain2 "github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+func TestRewindBulkImport(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	gspec := Genesis{
+		Config:  params.TestChainConfig,
+		Factory: blockfactory.ForTest,
+		ShardID: 0,
+	}
+	genesis := gspec.MustCommit(db)
+
+	// empty blocks written by a bulk import, keeping the state of the genesis
+	blocks := types.Blocks{genesis}
+	for i := int64(1); i <= 5; i++ {
+		parent := blocks[i-1]
+		header := blockfactory.ForTest.NewHeader(parent.Epoch()).With().
+			Number(big.NewInt(i)).
+			ParentHash(parent.Hash()).
+			Root(genesis.Root()).
+			Header()
+		block := types.NewBlockWithHeader(header)
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteTd(db, block.Hash(), block.NumberU64(), big.NewInt(i+1))
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		blocks = append(blocks, block)
+	}
+	head := blocks[5].Hash()
+	rawdb.WriteHeadBlockHash(db, head)
+	rawdb.WriteHeadHeaderHash(db, head)
+	rawdb.WriteHeadFastBlockHash(db, head)
+	// the bulk import last flushed at block 2 before being interrupted
+	rawdb.WriteBulkImportMarker(db, 2)
+
+	bc, err := NewBlockChain(db, nil, gspec.Config, chain2.Engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+
+	expected := blocks[2].Hash()
+	if hash := bc.CurrentBlock().Hash(); hash != expected {
+		t.Errorf("expected the head block rewound to 2, got %d", bc.CurrentBlock().NumberU64())
+	}
+	if hash := bc.CurrentHeader().Hash(); hash != expected {
+		t.Errorf("expected the head header rewound to 2, got %d", bc.CurrentHeader().Number())
+	}
+	if hash := bc.CurrentFastBlock().Hash(); hash != expected {
+		t.Errorf("expected the head fast block rewound to 2, got %d", bc.CurrentFastBlock().NumberU64())
+	}
+	for name, hash := range map[string]common.Hash{
+		"head block":      rawdb.ReadHeadBlockHash(db),
+		"head header":     rawdb.ReadHeadHeaderHash(db),
+		"head fast block": rawdb.ReadHeadFastBlockHash(db),
+	} {
+		if hash != expected {
+			t.Errorf("expected the stored %s hash rewound, got %x", name, hash)
+		}
+	}
+	for number := uint64(3); number <= 5; number++ {
+		if hash := rawdb.ReadCanonicalHash(db, number); hash != (common.Hash{}) {
+			t.Errorf("expected no canonical block %d, got %x", number, hash)
+		}
+		if block := bc.GetBlockByNumber(number); block != nil {
+			t.Errorf("expected block %d rolled back", number)
+		}
+	}
+	if hash := rawdb.ReadCanonicalHash(db, 2); hash != expected {
+		t.Errorf("expected block 2 kept canonical, got %x", hash)
+	}
+	if _, ok := rawdb.ReadBulkImportMarker(db); ok {
+		t.Error("expected the bulk import marker removed")
+	}
+}
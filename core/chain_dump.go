@@ -0,0 +1,258 @@
+package core
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// chainDumpBatch is the number of blocks of a dump inserted at once, and
+	// flushed at once during its bulk import
+	chainDumpBatch = 256
+	// chainDumpManifestSuffix is appended to the path of a dump for the path
+	// of its manifest
+	chainDumpManifestSuffix = ".manifest.json"
+)
+
+// ChainDumpManifest describes a dump of the RLP encoded blocks of a chain, so
+// that it can be checked before being imported
+type ChainDumpManifest struct {
+	ShardID uint32      `json:"shard-id"`
+	Genesis common.Hash `json:"genesis"`
+	From    uint64      `json:"from"`
+	To      uint64      `json:"to"`
+	Size    int64       `json:"size"`   // bytes of the dump
+	SHA256  string      `json:"sha256"` // hex checksum of the dump
+}
+
+// Operations of a chain dump job
+const (
+	ChainDumpExport = "export"
+	ChainDumpImport = "import"
+)
+
+// ChainDumpProgress is the progress of the last chain export or import
+type ChainDumpProgress struct {
+	Operation string             `json:"operation"`
+	Path      string             `json:"path"`
+	ShardID   uint32             `json:"shard-id"`
+	From      uint64             `json:"from"`
+	To        uint64             `json:"to"`
+	Current   uint64             `json:"current"` // last block written or inserted
+	Started   time.Time          `json:"started"`
+	Finished  *time.Time         `json:"finished,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	Manifest  *ChainDumpManifest `json:"manifest,omitempty"`
+}
+
+// ChainDumpManifestPath returns the path of the manifest of the dump at path
+func ChainDumpManifestPath(path string) string {
+	return path + chainDumpManifestSuffix
+}
+
+// ReadChainDumpManifest reads the manifest of the dump at path
+func ReadChainDumpManifest(path string) (*ChainDumpManifest, error) {
+	data, err := ioutil.ReadFile(ChainDumpManifestPath(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read dump manifest")
+	}
+	manifest := &ChainDumpManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrap(err, "invalid dump manifest")
+	}
+	return manifest, nil
+}
+
+// ExportDump writes the canonical blocks from..to to a dump at path, and its
+// manifest next to it. A to of 0 or above the head exports up to the head.
+// The progress function is called with the number of each block written.
+// Unlike ExportN, it does not block the chain while exporting.
+func (bc *BlockChain) ExportDump(
+	path string, from, to uint64, progress func(uint64),
+) (*ChainDumpManifest, error) {
+	if head := bc.CurrentBlock().NumberU64(); to == 0 || to > head {
+		to = head
+	}
+	if from > to {
+		return nil, errors.Errorf("first block %d is greater than last block %d", from, to)
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	hash := sha256.New()
+	w := bufio.NewWriter(io.MultiWriter(f, hash))
+	for number := from; number <= to; number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return nil, errors.Errorf("block %d not found", number)
+		}
+		if err := block.EncodeRLP(w); err != nil {
+			return nil, err
+		}
+		progress(number)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	manifest := &ChainDumpManifest{
+		ShardID: bc.ShardID(),
+		Genesis: bc.Genesis().Hash(),
+		From:    from,
+		To:      to,
+		Size:    info.Size(),
+		SHA256:  hex.EncodeToString(hash.Sum(nil)),
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(ChainDumpManifestPath(path), data, 0644); err != nil {
+		return nil, err
+	}
+	utils.Logger().Info().
+		Uint32("shardID", manifest.ShardID).
+		Uint64("from", from).
+		Uint64("to", to).
+		Str("path", path).
+		Msg("[ChainDump] Exported blocks")
+	return manifest, nil
+}
+
+// ImportDump inserts the blocks of the dump at path missing from the chain,
+// once the dump matches the checksum of its manifest and the manifest matches
+// the chain. The blocks are verified as synced ones. The progress function is
+// called with the number of the last block of each batch inserted.
+func (bc *BlockChain) ImportDump(
+	path string, progress func(uint64),
+) (*ChainDumpManifest, error) {
+	manifest, err := ReadChainDumpManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.ShardID != bc.ShardID() {
+		return nil, errors.Errorf("dump of shard %d, not %d", manifest.ShardID, bc.ShardID())
+	}
+	if genesis := bc.Genesis().Hash(); manifest.Genesis != genesis {
+		return nil, errors.Errorf("dump of genesis %s, not %s", manifest.Genesis.Hex(), genesis.Hex())
+	}
+	if err := checkChainDump(path, manifest); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stream := rlp.NewStream(bufio.NewReader(f), 0)
+
+	bc.BeginBulkImport(chainDumpBatch)
+	defer func() {
+		if err := bc.EndBulkImport(); err != nil {
+			utils.Logger().Error().Err(err).Msg("[ChainDump] Failed to finish bulk import")
+		}
+	}()
+	batch := make(types.Blocks, 0, chainDumpBatch)
+	insert := func() error {
+		// one block at a time, as the header of a block is verified against
+		// its parent in the chain
+		for _, block := range batch {
+			if bc.HasBlock(block.Hash(), block.NumberU64()) {
+				continue
+			}
+			if _, err := bc.InsertChain(types.Blocks{block}, true); err != nil {
+				return errors.Wrapf(err, "cannot insert block %d", block.NumberU64())
+			}
+		}
+		progress(batch[len(batch)-1].NumberU64())
+		batch = batch[:0]
+		return nil
+	}
+	next := manifest.From
+	for {
+		block := &types.Block{}
+		if err := stream.Decode(block); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "cannot decode block")
+		}
+		if block.NumberU64() != next {
+			return nil, errors.Errorf("dump block %d out of order, expected %d", block.NumberU64(), next)
+		}
+		next++
+		batch = append(batch, block)
+		if len(batch) == chainDumpBatch {
+			if err := insert(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := insert(); err != nil {
+			return nil, err
+		}
+	}
+	if next != manifest.To+1 {
+		return nil, errors.Errorf("dump ends at block %d, manifest has %d", next-1, manifest.To)
+	}
+	utils.Logger().Info().
+		Uint32("shardID", manifest.ShardID).
+		Uint64("from", manifest.From).
+		Uint64("to", manifest.To).
+		Uint64("head", bc.CurrentBlock().NumberU64()).
+		Str("path", path).
+		Msg("[ChainDump] Imported blocks")
+	return manifest, nil
+}
+
+// checkChainDump checks the size and checksum of the dump at path against its
+// manifest
+func checkChainDump(path string, manifest *ChainDumpManifest) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return err
+	}
+	if size != manifest.Size {
+		return errors.Errorf("dump size %d, manifest has %d", size, manifest.Size)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != manifest.SHA256 {
+		return errors.Errorf("dump checksum %s, manifest has %s", sum, manifest.SHA256)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
+	"github.com/harmony-one/harmony/consensus/reward"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	chain2 "github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/harmony-one/harmony/staking/network"
+	"github.com/harmony-one/harmony/staking/slash"
+	staking "github.com/harmony-one/harmony/staking/types"
+)
+
+// unsealedEngine verifies the headers without their commit signatures and
+// pays no block rewards, the generated test blocks being signed by no
+// committee
+type unsealedEngine struct {
+	consensus_engine.Engine
+}
+
+func (e unsealedEngine) VerifyHeaders(
+	chain consensus_engine.ChainReader, headers []*block.Header, seals []bool,
+) (chan<- struct{}, <-chan error) {
+	return e.Engine.VerifyHeaders(chain, headers, make([]bool, len(headers)))
+}
+
+func (e unsealedEngine) Finalize(
+	chain consensus_engine.ChainReader, header *block.Header,
+	state *state.DB, txs []*types.Transaction,
+	receipts []*types.Receipt, outcxs []*types.CXReceipt,
+	incxs []*types.CXReceiptsProof, stks staking.StakingTransactions,
+	doubleSigners slash.Records,
+) (*types.Block, reward.Reader, error) {
+	header.SetRoot(state.IntermediateRoot(chain.Config().IsS3(header.Epoch())))
+	return types.NewBlock(header, txs, receipts, outcxs, incxs, stks), network.EmptyPayout, nil
+}
+
+// dumpTestGenesis is the genesis of the dump tests, its committee holding the
+// coinbase of the generated blocks
+var dumpTestGenesis = Genesis{
+	Config:  params.TestChainConfig,
+	Factory: blockfactory.ForTest,
+	ShardID: 0,
+	ShardState: shard.State{Shards: []shard.Committee{
+		{ShardID: 0, Slots: shard.SlotList{{EcdsaAddress: common.Address{}}}},
+	}},
+}
+
+// newDumpTestChain returns a chain of the dump test genesis, with the given
+// blocks inserted
+func newDumpTestChain(t *testing.T, blocks types.Blocks) *BlockChain {
+	db := ethdb.NewMemDatabase()
+	dumpTestGenesis.MustCommit(db)
+	bc, err := NewBlockChain(db, nil, dumpTestGenesis.Config, unsealedEngine{chain2.Engine}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bc.InsertChain(blocks, false); err != nil {
+		t.Fatal(err)
+	}
+	return bc
+}
+
+// generateDumpTestBlocks generates n empty blocks on the dump test genesis
+func generateDumpTestBlocks(n int) types.Blocks {
+	db := ethdb.NewMemDatabase()
+	genesis := dumpTestGenesis.MustCommit(db)
+	blocks, _ := GenerateChain(dumpTestGenesis.Config, genesis, unsealedEngine{chain2.Engine}, db, n, nil)
+	return blocks
+}
+
+// writeTestDump writes the encoded blocks from..to to a dump at path, with a
+// manifest matching the dump
+func writeTestDump(t *testing.T, bc *BlockChain, path string, data []byte, from, to uint64) {
+	sum := sha256.Sum256(data)
+	manifest := ChainDumpManifest{
+		ShardID: bc.ShardID(),
+		Genesis: bc.Genesis().Hash(),
+		From:    from,
+		To:      to,
+		Size:    int64(len(data)),
+		SHA256:  hex.EncodeToString(sum[:]),
+	}
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(ChainDumpManifestPath(path), encoded, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newDumpDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "hmy-chain-dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestChainDumpRoundTrip(t *testing.T) {
+	blocks := generateDumpTestBlocks(5)
+	source := newDumpTestChain(t, blocks)
+	defer source.Stop()
+	path := filepath.Join(newDumpDir(t), "s0.rlp")
+
+	written := []uint64{}
+	manifest, err := source.ExportDump(path, 0, 0, func(number uint64) {
+		written = append(written, number)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.From != 0 || manifest.To != 5 || len(written) != 6 || written[5] != 5 {
+		t.Errorf("unexpected export of blocks %d..%d, progress %v", manifest.From, manifest.To, written)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary dump removed, got %v", err)
+	}
+	if read, err := ReadChainDumpManifest(path); err != nil || *read != *manifest {
+		t.Errorf("expected the manifest written next to the dump, got %+v, %v", read, err)
+	}
+
+	// a chain holding the first blocks imports the missing ones only
+	target := newDumpTestChain(t, blocks[:2])
+	defer target.Stop()
+	inserted := uint64(0)
+	if _, err := target.ImportDump(path, func(number uint64) { inserted = number }); err != nil {
+		t.Fatal(err)
+	}
+	if head := target.CurrentBlock(); head.Hash() != blocks[4].Hash() {
+		t.Errorf("expected the head imported at block 5, got %d", head.NumberU64())
+	}
+	if inserted != 5 {
+		t.Errorf("expected the progress at block 5, got %d", inserted)
+	}
+	for _, block := range blocks {
+		if hash := rawdb.ReadCanonicalHash(target.db, block.NumberU64()); hash != block.Hash() {
+			t.Errorf("block %d not imported", block.NumberU64())
+		}
+	}
+}
+
+func TestChainDumpTruncated(t *testing.T) {
+	blocks := generateDumpTestBlocks(3)
+	source := newDumpTestChain(t, blocks)
+	defer source.Stop()
+	path := filepath.Join(newDumpDir(t), "s0.rlp")
+	if _, err := source.ExportDump(path, 1, 3, func(uint64) {}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data[:len(data)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	target := newDumpTestChain(t, nil)
+	defer target.Stop()
+	// the truncated dump does not match its manifest
+	if _, err := target.ImportDump(path, func(uint64) {}); err == nil ||
+		!strings.HasPrefix(err.Error(), "dump size") {
+		t.Errorf("expected a size mismatch, got %v", err)
+	}
+	// nor can it be decoded with a manifest matching it
+	writeTestDump(t, target, path, data[:len(data)-10], 1, 3)
+	if _, err := target.ImportDump(path, func(uint64) {}); err == nil ||
+		!strings.HasPrefix(err.Error(), "cannot decode block") {
+		t.Errorf("expected a decoding error, got %v", err)
+	}
+	if head := target.CurrentBlock().NumberU64(); head != 0 {
+		t.Errorf("expected nothing imported, got head %d", head)
+	}
+
+	// a dump cut at a block boundary is shorter than its manifest
+	cut := bytes.Buffer{}
+	for _, block := range blocks[:2] {
+		if err := block.EncodeRLP(&cut); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestDump(t, target, path, cut.Bytes(), 1, 3)
+	if _, err := target.ImportDump(path, func(uint64) {}); err == nil ||
+		err.Error() != "dump ends at block 2, manifest has 3" {
+		t.Errorf("expected the missing blocks reported, got %v", err)
+	}
+}
+
+func TestChainDumpOutOfOrder(t *testing.T) {
+	blocks := generateDumpTestBlocks(3)
+	target := newDumpTestChain(t, nil)
+	defer target.Stop()
+	path := filepath.Join(newDumpDir(t), "s0.rlp")
+	data := bytes.Buffer{}
+	for _, i := range []int{0, 2, 1} {
+		if err := blocks[i].EncodeRLP(&data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestDump(t, target, path, data.Bytes(), 1, 3)
+
+	if _, err := target.ImportDump(path, func(uint64) {}); err == nil ||
+		err.Error() != "dump block 3 out of order, expected 2" {
+		t.Errorf("expected the blocks out of order rejected, got %v", err)
+	}
+	if head := target.CurrentBlock().NumberU64(); head != 0 {
+		t.Errorf("expected nothing imported, got head %d", head)
+	}
+}
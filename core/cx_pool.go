@@ -1,58 +1,238 @@
 package core
 
 import (
-	mapset "github.com/deckarep/golang-set"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
 )
 
 const (
-	// CxPoolSize is the maximum size of the pool
+	// CxPoolSize is the maximum number of resends held in memory, the others
+	// being spilled to disk
 	CxPoolSize = 50
+	// CxPoolDiskSize is the maximum number of resends spilled to disk
+	CxPoolDiskSize = 4096
+	// CxRetryInterval is the delay before the receipts are resent again,
+	// doubled at each resend up to CxMaxRetryInterval
+	CxRetryInterval    = time.Minute
+	CxMaxRetryInterval = time.Hour
+	// CxMaxAttempts is the number of resends after which the receipts are
+	// given up on
+	CxMaxAttempts = 24
 )
 
-// CxEntry represents the egress receipt's blockHash and ToShardID
+// CxEntry represents the egress receipts of a block to a destination shard
 type CxEntry struct {
 	BlockHash common.Hash
+	BlockNum  uint64
 	ToShardID uint32
 }
 
-// CxPool is to hold a pool of block outgoing receipts to be resend in next round broadcast
-// When a user/client doesn't find the destination shard get the money from cross shard tx
-// it can send RPC call along with txID to allow the any validator to
-// add the corresponding block's receipts to be resent
+// CxPool is to hold a queue of block outgoing receipts to be resent until
+// delivered. When a user/client doesn't find the destination shard get the
+// money from cross shard tx it can send RPC call along with txID to allow the
+// any validator to add the corresponding block's receipts to be resent.
+// The queue is persisted in the chain database, keyed by destination shard and
+// block, so that the resends survive restarts; the resends beyond the memory
+// limit are spilled to disk and read back as the queue drains.
 type CxPool struct {
-	pool    mapset.Set
+	mutex   sync.Mutex
+	db      ethdb.Database // nil for a queue kept in memory only
+	resends map[CxEntry]*types.CXResend
+	spilled int // resends on disk only
 	maxSize int
 }
 
-// NewCxPool creates a new CxPool
-func NewCxPool(limit int) *CxPool {
-	pool := mapset.NewSet()
-	cxPool := CxPool{pool: pool, maxSize: limit}
-	return &cxPool
+// NewCxPool creates a new CxPool holding up to limit resends in memory, and
+// loads the resends queued in the database, nil for none
+func NewCxPool(db ethdb.Database, limit int) *CxPool {
+	cxPool := &CxPool{
+		db:      db,
+		resends: map[CxEntry]*types.CXResend{},
+		maxSize: limit,
+	}
+	cxPool.refill()
+	if size := cxPool.Size(); size > 0 {
+		utils.Logger().Info().Int("size", size).Msg("[CxPool] Loaded queued cross shard resends")
+	}
+	return cxPool
+}
+
+func entryOf(resend *types.CXResend) CxEntry {
+	return CxEntry{BlockHash: resend.BlockHash, BlockNum: resend.BlockNum, ToShardID: resend.ToShardID}
+}
+
+// iteratee returns the database to read the spilled resends back from, false
+// if the resends cannot be spilled
+func (cxPool *CxPool) iteratee() (rawdb.DatabaseIteratee, bool) {
+	if cxPool.db == nil {
+		return nil, false
+	}
+	db, ok := cxPool.db.(rawdb.DatabaseIteratee)
+	return db, ok
 }
 
-// Pool returns the pool of blockHashes of missing receipts
-func (cxPool *CxPool) Pool() mapset.Set {
-	return cxPool.pool
+// refill reads the resends queued on disk back into memory, up to the limit,
+// counting the others as spilled
+func (cxPool *CxPool) refill() {
+	db, ok := cxPool.iteratee()
+	if !ok {
+		return
+	}
+	spilled := 0
+	err := rawdb.IterateCXResends(db, func(resend *types.CXResend) bool {
+		entry := entryOf(resend)
+		if _, ok := cxPool.resends[entry]; ok {
+			return true
+		}
+		if len(cxPool.resends) < cxPool.maxSize {
+			cxPool.resends[entry] = resend
+		} else {
+			spilled++
+		}
+		return true
+	})
+	if err != nil {
+		utils.Logger().Error().Err(err).Msg("[CxPool] Cannot read the spilled cross shard resends")
+	}
+	cxPool.spilled = spilled
+}
+
+// persist writes the resend to the database, if any
+func (cxPool *CxPool) persist(resend *types.CXResend) {
+	if cxPool.db == nil {
+		return
+	}
+	if err := rawdb.WriteCXResend(cxPool.db, resend); err != nil {
+		utils.Logger().Error().Err(err).
+			Str("blockHash", resend.BlockHash.Hex()).
+			Msg("[CxPool] Cannot persist cross shard resend")
+	}
 }
 
-// Size return size of the pool
+// Size return size of the pool, including the resends spilled to disk
 func (cxPool *CxPool) Size() int {
-	return cxPool.pool.Cardinality()
+	cxPool.mutex.Lock()
+	defer cxPool.mutex.Unlock()
+	return len(cxPool.resends) + cxPool.spilled
 }
 
-// Add add element into the pool if not exceed limit
+// Add queues the receipts of the entry to be resent on the next round, and
+// returns false if the queue is full. An entry queued already is resent on the
+// next round again.
 func (cxPool *CxPool) Add(entry CxEntry) bool {
-	if cxPool.Size() > cxPool.maxSize {
+	cxPool.mutex.Lock()
+	defer cxPool.mutex.Unlock()
+	if resend, ok := cxPool.resends[entry]; ok {
+		resend.NextRetry = 0
+		cxPool.persist(resend)
+		return true
+	}
+	if cxPool.db != nil {
+		if resend := rawdb.ReadCXResend(
+			cxPool.db, entry.ToShardID, entry.BlockNum, entry.BlockHash,
+		); resend != nil {
+			resend.NextRetry = 0
+			cxPool.persist(resend)
+			return true
+		}
+	}
+	resend := &types.CXResend{
+		ToShardID: entry.ToShardID,
+		BlockNum:  entry.BlockNum,
+		BlockHash: entry.BlockHash,
+	}
+	if len(cxPool.resends) < cxPool.maxSize {
+		cxPool.resends[entry] = resend
+		cxPool.persist(resend)
+		return true
+	}
+	if _, ok := cxPool.iteratee(); !ok || cxPool.spilled >= CxPoolDiskSize {
 		return false
 	}
-	cxPool.pool.Add(entry)
+	cxPool.persist(resend)
+	cxPool.spilled++
 	return true
 }
 
-// Clear empty the pool
-func (cxPool *CxPool) Clear() {
-	cxPool.pool.Clear()
+// Due returns the entries whose receipts are due to be resent at the given
+// time, by destination shard and block number
+func (cxPool *CxPool) Due(now time.Time) []CxEntry {
+	cxPool.mutex.Lock()
+	defer cxPool.mutex.Unlock()
+	due := []CxEntry{}
+	for entry, resend := range cxPool.resends {
+		if resend.NextRetry <= uint64(now.Unix()) {
+			due = append(due, entry)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].ToShardID != due[j].ToShardID {
+			return due[i].ToShardID < due[j].ToShardID
+		}
+		return due[i].BlockNum < due[j].BlockNum
+	})
+	return due
+}
+
+// Retry schedules the next resend of the receipts of the entry, resent at the
+// given time, backing off exponentially. The entry is dropped after
+// CxMaxAttempts resends.
+func (cxPool *CxPool) Retry(entry CxEntry, now time.Time) {
+	cxPool.mutex.Lock()
+	resend, ok := cxPool.resends[entry]
+	if !ok {
+		cxPool.mutex.Unlock()
+		return
+	}
+	resend.Attempts++
+	if resend.Attempts < CxMaxAttempts {
+		interval := CxMaxRetryInterval
+		if shift := resend.Attempts - 1; shift < 16 && CxRetryInterval<<shift < CxMaxRetryInterval {
+			interval = CxRetryInterval << shift
+		}
+		resend.NextRetry = uint64(now.Add(interval).Unix())
+		cxPool.persist(resend)
+		cxPool.mutex.Unlock()
+		return
+	}
+	cxPool.mutex.Unlock()
+	utils.Logger().Warn().
+		Uint32("toShardID", entry.ToShardID).
+		Uint64("blockNum", entry.BlockNum).
+		Str("blockHash", entry.BlockHash.Hex()).
+		Msg("[CxPool] Giving up resending cross shard receipts")
+	cxPool.Remove(entry)
+}
+
+// Remove drops the entry from the queue, once its receipts are delivered or
+// can no longer be, reading back the resends spilled to disk
+func (cxPool *CxPool) Remove(entry CxEntry) {
+	cxPool.mutex.Lock()
+	defer cxPool.mutex.Unlock()
+	_, inMemory := cxPool.resends[entry]
+	delete(cxPool.resends, entry)
+	if cxPool.db != nil {
+		if !inMemory && cxPool.spilled > 0 && rawdb.ReadCXResend(
+			cxPool.db, entry.ToShardID, entry.BlockNum, entry.BlockHash,
+		) != nil {
+			cxPool.spilled--
+		}
+		if err := rawdb.DeleteCXResend(
+			cxPool.db, entry.ToShardID, entry.BlockNum, entry.BlockHash,
+		); err != nil {
+			utils.Logger().Error().Err(err).
+				Str("blockHash", entry.BlockHash.Hex()).
+				Msg("[CxPool] Cannot delete cross shard resend")
+		}
+	}
+	if inMemory && cxPool.spilled > 0 {
+		cxPool.refill()
+	}
 }
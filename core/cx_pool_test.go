@@ -0,0 +1,93 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestCxPoolPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cxpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := ethdb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	entries := []CxEntry{}
+	for i := 0; i < 3; i++ {
+		entries = append(entries, CxEntry{
+			BlockHash: common.BytesToHash([]byte{byte(i + 1)}),
+			BlockNum:  uint64(i + 1),
+			ToShardID: 1,
+		})
+	}
+	pool := NewCxPool(db, 2)
+	for _, entry := range entries {
+		if !pool.Add(entry) {
+			t.Fatalf("entry %v not added", entry)
+		}
+	}
+	if size := pool.Size(); size != 3 {
+		t.Fatalf("size %d with an entry spilled, want 3", size)
+	}
+	now := time.Now()
+	if due := pool.Due(now); len(due) != 2 || due[0] != entries[0] || due[1] != entries[1] {
+		t.Fatalf("unexpected due entries %v", due)
+	}
+	pool.Retry(entries[0], now)
+	if due := pool.Due(now); len(due) != 1 || due[0] != entries[1] {
+		t.Fatalf("entry resent due again: %v", pool.Due(now))
+	}
+	if due := pool.Due(now.Add(CxRetryInterval)); len(due) != 2 {
+		t.Fatalf("entry not due after the retry interval: %v", due)
+	}
+
+	// the spilled entry is read back once the memory frees up
+	pool.Remove(entries[1])
+	if due := pool.Due(now); len(due) != 1 || due[0] != entries[2] {
+		t.Fatalf("spilled entry not read back: %v", due)
+	}
+
+	// the queue survives a restart, along with its schedule
+	restarted := NewCxPool(db, 2)
+	if size := restarted.Size(); size != 2 {
+		t.Fatalf("size %d after restart, want 2", size)
+	}
+	if due := restarted.Due(now); len(due) != 1 || due[0] != entries[2] {
+		t.Fatalf("unexpected due entries after restart %v", due)
+	}
+
+	for i := 0; i < CxMaxAttempts; i++ {
+		restarted.Retry(entries[2], now)
+	}
+	if size := restarted.Size(); size != 1 {
+		t.Fatalf("entry kept after %d attempts", CxMaxAttempts)
+	}
+}
+
+func TestCxPoolInMemory(t *testing.T) {
+	pool := NewCxPool(nil, 1)
+	entry := CxEntry{BlockHash: common.BytesToHash([]byte{1}), BlockNum: 1}
+	if !pool.Add(entry) {
+		t.Fatal("entry not added")
+	}
+	if pool.Add(CxEntry{BlockHash: common.BytesToHash([]byte{2}), BlockNum: 2}) {
+		t.Fatal("entry added beyond the limit without a database to spill to")
+	}
+	if !pool.Add(entry) {
+		t.Fatal("queued entry not added again")
+	}
+	pool.Remove(entry)
+	if pool.Size() != 0 {
+		t.Fatal("entry not removed")
+	}
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	staking "github.com/harmony-one/harmony/staking/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultIntegrityCheckDepth is the default number of canonical blocks
+	// checked on startup
+	DefaultIntegrityCheckDepth = 128
+	// maxRepairRecords is the number of repairs kept in the database
+	maxRepairRecords = 32
+)
+
+var dbRepairCounter = metrics.NewRegisteredCounter("chain/repairs", nil)
+
+// checkBlockIntegrity checks that the canonical block of the given number is
+// fully stored: its header, body and receipts are present and match the roots
+// of the header. It returns the header if it could be read.
+func (bc *BlockChain) checkBlockIntegrity(number uint64) (*block.Header, error) {
+	hash := rawdb.ReadCanonicalHash(bc.db, number)
+	if hash == (common.Hash{}) {
+		return nil, errors.New("canonical hash missing")
+	}
+	header := rawdb.ReadHeader(bc.db, hash, number)
+	if header == nil {
+		return nil, errors.New("header missing")
+	}
+	if header.Hash() != hash {
+		return nil, errors.Errorf("header hash %s is not the canonical %s", header.Hash().Hex(), hash.Hex())
+	}
+	blk := rawdb.ReadBlock(bc.db, hash, number)
+	if blk == nil {
+		return header, errors.New("body missing")
+	}
+	if txHash := types.DeriveSha(
+		blk.Transactions(), blk.StakingTransactions(),
+	); txHash != header.TxHash() {
+		return header, errors.Errorf("transaction root mismatch: have %x, want %x", txHash, header.TxHash())
+	}
+	receipts := rawdb.ReadReceipts(bc.db, hash, number)
+	if receiptHash := types.DeriveSha(receipts); receiptHash != header.ReceiptHash() {
+		return header, errors.Errorf("receipt root mismatch: have %x, want %x", receiptHash, header.ReceiptHash())
+	}
+	return header, nil
+}
+
+// CheckIntegrity checks the consistency of the last depth canonical blocks, as
+// left by a crash in the middle of a write. On finding corrupted blocks, or
+// canonical blocks above a head rewound on loading the chain, the chain is
+// truncated to the last consistent block with a state below them and the
+// repair is recorded in the database. It must be called before the chain is
+// in use.
+func (bc *BlockChain) CheckIntegrity(depth uint64) (*rawdb.RepairRecord, error) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	head := bc.CurrentBlock().NumberU64()
+	var (
+		corrupted uint64
+		reason    string
+		parent    common.Hash // the parent hash of the block checked last
+	)
+	// The canonical blocks above the head are left by loading a chain whose
+	// head block or state is missing, the head being rewound below them
+	top := head
+	for rawdb.ReadCanonicalHash(bc.db, top+1) != (common.Hash{}) {
+		top++
+	}
+	if top > head {
+		corrupted, reason = head+1, "state missing"
+		if _, err := bc.checkBlockIntegrity(head + 1); err != nil {
+			reason = err.Error()
+		}
+	}
+	for number := head; number > 0 && head-number < depth; number-- {
+		header, err := bc.checkBlockIntegrity(number)
+		if err == nil && parent != (common.Hash{}) && header.Hash() != parent {
+			err = errors.New("not the parent of the next canonical block")
+		}
+		if err != nil {
+			corrupted, reason = number, err.Error()
+		}
+		parent = common.Hash{}
+		if header != nil {
+			parent = header.ParentHash()
+		}
+	}
+	if corrupted == 0 {
+		utils.Logger().Info().
+			Uint64("head", head).
+			Uint64("depth", depth).
+			Msg("[IntegrityCheck] Chain database is consistent")
+		return nil, nil
+	}
+	// The block below the lowest corrupted one may be corrupted as well, or
+	// have no state to resume the chain from
+	newHead := corrupted - 1
+	for ; newHead > 0; newHead-- {
+		if header, err := bc.checkBlockIntegrity(newHead); err == nil && bc.HasState(header.Root()) {
+			break
+		}
+	}
+	utils.Logger().Warn().
+		Uint64("head", top).
+		Uint64("corrupted", corrupted).
+		Uint64("newHead", newHead).
+		Str("reason", reason).
+		Msg("[IntegrityCheck] Corrupted blocks found, truncating chain")
+	if err := bc.truncate(top, newHead); err != nil {
+		return nil, errors.Wrap(err, "cannot truncate chain")
+	}
+
+	record := rawdb.RepairRecord{
+		Time:      uint64(time.Now().Unix()),
+		Head:      top,
+		Corrupted: corrupted,
+		NewHead:   newHead,
+		Reason:    reason,
+	}
+	records := append(rawdb.ReadRepairRecords(bc.db), record)
+	if len(records) > maxRepairRecords {
+		records = records[len(records)-maxRepairRecords:]
+	}
+	if err := rawdb.WriteRepairRecords(bc.db, records); err != nil {
+		utils.Logger().Error().Err(err).Msg("[IntegrityCheck] Failed to record the repair")
+	}
+	dbRepairCounter.Inc(1)
+	return &record, nil
+}
+
+// truncate deletes the canonical blocks above the new head, whatever parts of
+// them are still stored, and reloads the chain from the new head. Unlike
+// SetHead, it does not need the header chain above the new head to be intact.
+// This method assumes that the `mu` mutex is held.
+func (bc *BlockChain) truncate(head, newHead uint64) error {
+	newHeadHash := rawdb.ReadCanonicalHash(bc.db, newHead)
+	newHeadBlock := rawdb.ReadBlock(bc.db, newHeadHash, newHead)
+	if newHeadBlock == nil {
+		return errors.Errorf("block %d missing", newHead)
+	}
+
+	batch := bc.db.NewBatch()
+	valsToRemove := map[common.Address]struct{}{}
+	for number := head; number > newHead; number-- {
+		hash := rawdb.ReadCanonicalHash(bc.db, number)
+		if hash != (common.Hash{}) {
+			if blk := rawdb.ReadBlock(bc.db, hash, number); blk != nil {
+				for _, tx := range blk.Transactions() {
+					rawdb.DeleteTxLookupEntry(batch, tx.Hash())
+				}
+				for _, tx := range blk.StakingTransactions() {
+					rawdb.DeleteTxLookupEntry(batch, tx.Hash())
+					if tx.StakingType() == staking.DirectiveCreateValidator {
+						if addr, err := tx.SenderAddress(); err == nil {
+							valsToRemove[addr] = struct{}{}
+						}
+					}
+				}
+			}
+			// The commit signature of the new head is only in the next block
+			if number == newHead+1 {
+				if header := rawdb.ReadHeader(bc.db, hash, number); header != nil {
+					lastSig := header.LastCommitSignature()
+					sigAndBitMap := append(lastSig[:], header.LastCommitBitmap()...)
+					bc.WriteCommitSig(newHead, sigAndBitMap)
+				}
+			}
+			rawdb.DeleteBlock(batch, hash, number)
+		}
+		rawdb.DeleteCanonicalHash(batch, number)
+	}
+	rawdb.WriteHeadBlockHash(batch, newHeadHash)
+	rawdb.WriteHeadFastBlockHash(batch, newHeadHash)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	bc.bodyCache.Purge()
+	bc.bodyRLPCache.Purge()
+	bc.receiptsCache.Purge()
+	bc.blockCache.Purge()
+	bc.futureBlocks.Purge()
+	bc.shardStateCache.Purge()
+	bc.cxProofCache.Purge()
+	bc.hc.headerCache.Purge()
+	bc.hc.tdCache.Purge()
+	bc.hc.numberCache.Purge()
+	bc.hc.SetCurrentHeader(newHeadBlock.Header())
+	if err := bc.removeInValidatorList(valsToRemove); err != nil {
+		return err
+	}
+	return bc.loadLastState()
+}
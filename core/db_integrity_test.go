@@ -0,0 +1,172 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	chain2 "github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+// newIntegrityTestChain writes to a memdb a chain of n blocks of a transfer
+// each, the state of every block committed to disk, and returns the database
+// and the blocks
+func newIntegrityTestChain(t *testing.T, n int) (ethdb.Database, types.Blocks) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	gspec := dumpTestGenesis
+	gspec.Alloc = GenesisAlloc{addr: {Balance: big.NewInt(1e18)}}
+	gspec.GasLimit = params.GenesisGasLimit
+
+	db := ethdb.NewMemDatabase()
+	genesis := gspec.MustCommit(db)
+	blocks, _ := GenerateChain(gspec.Config, genesis, unsealedEngine{chain2.Engine}, db, n, func(i int, gen *BlockGen) {
+		tx, err := types.SignTx(
+			types.NewTransaction(gen.TxNonce(addr), common.Address{1}, 0, big.NewInt(1), 21000, big.NewInt(1), nil),
+			types.HomesteadSigner{}, key,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(tx)
+	})
+
+	db = ethdb.NewMemDatabase()
+	gspec.MustCommit(db)
+	bc, err := NewBlockChain(db, &CacheConfig{Disabled: true}, gspec.Config, unsealedEngine{chain2.Engine}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+	if _, err := bc.InsertChain(blocks, false); err != nil {
+		t.Fatal(err)
+	}
+	return db, blocks
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		corrupt   func(db ethdb.Database, blocks types.Blocks)
+		corrupted uint64 // lowest corrupted block, 0 for none
+		newHead   uint64
+		reason    string
+	}{
+		{
+			"clean chain",
+			func(ethdb.Database, types.Blocks) {},
+			0, 5, "",
+		},
+		{
+			"missing body",
+			func(db ethdb.Database, blocks types.Blocks) {
+				rawdb.DeleteBody(db, blocks[3].Hash(), 4)
+			},
+			4, 3, "body missing",
+		},
+		{
+			"missing receipt",
+			func(db ethdb.Database, blocks types.Blocks) {
+				rawdb.DeleteReceipts(db, blocks[2].Hash(), 3)
+			},
+			3, 2, "receipt root mismatch",
+		},
+		{
+			"missing head body",
+			func(db ethdb.Database, blocks types.Blocks) {
+				rawdb.DeleteBody(db, blocks[4].Hash(), 5)
+			},
+			5, 4, "body missing",
+		},
+		{
+			"missing receipt below the missing head body",
+			func(db ethdb.Database, blocks types.Blocks) {
+				rawdb.DeleteBody(db, blocks[4].Hash(), 5)
+				rawdb.DeleteReceipts(db, blocks[3].Hash(), 4)
+			},
+			4, 3, "receipt root mismatch",
+		},
+		{
+			"missing state root",
+			func(db ethdb.Database, blocks types.Blocks) {
+				db.Delete(blocks[4].Root().Bytes())
+			},
+			5, 4, "state missing",
+		},
+	} {
+		db, blocks := newIntegrityTestChain(t, 5)
+		test.corrupt(db, blocks)
+		// the transactions of the blocks without a body cannot be looked up
+		// to be deleted
+		bodies := map[uint64]bool{}
+		for _, blk := range blocks {
+			bodies[blk.NumberU64()] = rawdb.ReadBody(db, blk.Hash(), blk.NumberU64()) != nil
+		}
+		bc, err := NewBlockChain(db, &CacheConfig{Disabled: true}, dumpTestGenesis.Config, unsealedEngine{chain2.Engine}, vm.Config{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := bc.CheckIntegrity(DefaultIntegrityCheckDepth)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		switch {
+		case test.corrupted == 0 && record != nil:
+			t.Errorf("%s: expected no repair, got %+v", test.name, record)
+		case test.corrupted != 0 && record == nil:
+			t.Errorf("%s: expected a repair", test.name)
+		case record != nil:
+			if record.Head != 5 || record.Corrupted != test.corrupted || record.NewHead != test.newHead ||
+				len(record.Reason) < len(test.reason) || record.Reason[:len(test.reason)] != test.reason {
+				t.Errorf("%s: unexpected repair %+v", test.name, record)
+			}
+			if records := rawdb.ReadRepairRecords(db); len(records) != 1 || records[0] != *record {
+				t.Errorf("%s: expected the repair recorded, got %+v", test.name, records)
+			}
+		}
+
+		if head := bc.CurrentBlock(); head.Hash() != blocks[test.newHead-1].Hash() {
+			t.Errorf("%s: expected the head at block %d, got %d", test.name, test.newHead, head.NumberU64())
+		}
+		if head := bc.CurrentHeader().Number().Uint64(); head != test.newHead {
+			t.Errorf("%s: expected the head header at block %d, got %d", test.name, test.newHead, head)
+		}
+		if head := rawdb.ReadHeadBlockHash(db); head != blocks[test.newHead-1].Hash() {
+			t.Errorf("%s: expected the head hash of block %d stored, got %x", test.name, test.newHead, head)
+		}
+		// nothing canonical remains above the new head
+		for _, blk := range blocks[test.newHead:] {
+			number := blk.NumberU64()
+			if hash := rawdb.ReadCanonicalHash(db, number); hash != (common.Hash{}) {
+				t.Errorf("%s: expected the canonical hash of block %d deleted", test.name, number)
+			}
+			if rawdb.ReadHeader(db, blk.Hash(), number) != nil {
+				t.Errorf("%s: expected the header of block %d deleted", test.name, number)
+			}
+			if rawdb.ReadReceipts(db, blk.Hash(), number) != nil {
+				t.Errorf("%s: expected the receipts of block %d deleted", test.name, number)
+			}
+			for _, tx := range blk.Transactions() {
+				if !bodies[number] {
+					break
+				}
+				if hash, _, _ := rawdb.ReadTxLookupEntry(db, tx.Hash()); hash != (common.Hash{}) {
+					t.Errorf("%s: expected the transaction lookup of block %d deleted", test.name, number)
+				}
+			}
+		}
+		// and the blocks below are kept
+		for _, blk := range blocks[:test.newHead] {
+			if hash := rawdb.ReadCanonicalHash(db, blk.NumberU64()); hash != blk.Hash() {
+				t.Errorf("%s: expected block %d kept", test.name, blk.NumberU64())
+			}
+		}
+		bc.Stop()
+	}
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/shard"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+const epochChainCacheLimit = 256
+
+// EpochChain is a header-only chain made of the header of the last block of
+// each epoch, along with the shard state (the committees) of each epoch. It
+// answers the epoch and committee queries without going through the full
+// chain.
+type EpochChain struct {
+	db          ethdb.Database
+	headerCache *lru.Cache // epoch → header of the last block of the epoch
+	stateCache  *lru.Cache // epoch → shard state of the epoch
+}
+
+// NewEpochChain returns the epoch chain kept in the given chain database.
+func NewEpochChain(db ethdb.Database) *EpochChain {
+	headerCache, _ := lru.New(epochChainCacheLimit)
+	stateCache, _ := lru.New(epochChainCacheLimit)
+	return &EpochChain{
+		db:          db,
+		headerCache: headerCache,
+		stateCache:  stateCache,
+	}
+}
+
+// writeLastHeader stores the header of the last block of its epoch, whose
+// shard state is the one of the next epoch.
+func (ec *EpochChain) writeLastHeader(
+	db rawdb.DatabaseWriter, header *block.Header,
+	nextEpoch *big.Int, nextShardState *shard.State,
+) error {
+	if err := rawdb.WriteEpochLastHeader(db, header); err != nil {
+		return errors.Wrapf(
+			err, "cannot write last header of epoch %v", header.Epoch(),
+		)
+	}
+	ec.headerCache.Add(header.Epoch().Uint64(), header)
+	if nextShardState != nil {
+		ec.stateCache.Add(nextEpoch.Uint64(), nextShardState)
+	}
+	return nil
+}
+
+// LastHeader returns the header of the last block of the given epoch, or nil
+// if the epoch has not ended yet.
+func (ec *EpochChain) LastHeader(epoch *big.Int) *block.Header {
+	if cached, ok := ec.headerCache.Get(epoch.Uint64()); ok {
+		return cached.(*block.Header)
+	}
+	header, err := rawdb.ReadEpochLastHeader(ec.db, epoch)
+	if err != nil {
+		return nil
+	}
+	ec.headerCache.Add(epoch.Uint64(), header)
+	return header
+}
+
+// ShardState returns the shard state of the given epoch.
+func (ec *EpochChain) ShardState(epoch *big.Int) (*shard.State, error) {
+	if cached, ok := ec.stateCache.Get(epoch.Uint64()); ok {
+		return cached.(*shard.State), nil
+	}
+	shardState, err := rawdb.ReadShardState(ec.db, epoch)
+	if err != nil {
+		return nil, err
+	}
+	ec.stateCache.Add(epoch.Uint64(), shardState)
+	return shardState, nil
+}
+
+// Committee returns the committee of the given shard in the given epoch.
+func (ec *EpochChain) Committee(
+	epoch *big.Int, shardID uint32,
+) (*shard.Committee, error) {
+	shardState, err := ec.ShardState(epoch)
+	if err != nil {
+		return nil, err
+	}
+	return shardState.FindCommitteeByID(shardID)
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestEpochChain(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	ec := NewEpochChain(db)
+	header := blockfactory.NewTestHeader().With().
+		Number(big.NewInt(99)).Epoch(big.NewInt(3)).Header()
+	nextShardState := &shard.State{
+		Epoch:  big.NewInt(4),
+		Shards: []shard.Committee{{ShardID: 1}},
+	}
+	if err := ec.writeLastHeader(db, header, big.NewInt(4), nextShardState); err != nil {
+		t.Fatalf("cannot write last header: %v", err)
+	}
+
+	if got := ec.LastHeader(big.NewInt(3)); got == nil || got.Hash() != header.Hash() {
+		t.Errorf("LastHeader(3) = %v, want %v", got, header)
+	}
+	if committee, err := ec.Committee(big.NewInt(4), 1); err != nil || committee.ShardID != 1 {
+		t.Errorf("Committee(4, 1) = %v, %v; want the committee of shard 1", committee, err)
+	}
+
+	// the headers are read back from the database
+	fresh := NewEpochChain(db)
+	if got := fresh.LastHeader(big.NewInt(3)); got == nil || got.Hash() != header.Hash() {
+		t.Errorf("LastHeader(3) from database = %v, want %v", got, header)
+	}
+	if got := fresh.LastHeader(big.NewInt(4)); got != nil {
+		t.Errorf("LastHeader(4) = %v, want nil as the epoch has not ended", got)
+	}
+}
@@ -18,12 +18,31 @@ package core
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/consensus/reward"
 	"github.com/harmony-one/harmony/core/types"
 )
 
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
 type NewTxsEvent struct{ Txs []*types.Transaction }
 
+// DroppedTxsEvent is posted when a batch of transactions is dropped from the
+// transaction pool without being executed.
+type DroppedTxsEvent struct {
+	Txs    types.PoolTransactions
+	Reason error
+}
+
+// PendingTxsEvent is posted when transactions enter or leave the pending
+// transactions of the pool, the ones executable on the current state. The
+// transactions leaving were included in a block, replaced, demoted back to the
+// queue or dropped. The counts are the ones of the pool after the change.
+type PendingTxsEvent struct {
+	Added   types.PoolTransactions
+	Removed types.PoolTransactions
+	Pending int
+	Queued  int
+}
+
 // PendingLogsEvent is posted pre mining and notifies of pending logs.
 type PendingLogsEvent struct {
 	Logs []*types.Log
@@ -49,3 +68,33 @@ type ChainSideEvent struct {
 
 // ChainHeadEvent is the struct of chain head event.
 type ChainHeadEvent struct{ Block *types.Block }
+
+// RewardEvent is posted when a block of the beacon chain in the staking era
+// pays out block rewards or unlocked undelegations.
+type RewardEvent struct {
+	BlockNum      uint64
+	BlockHash     common.Hash
+	Epoch         uint64
+	Rewards       []reward.Payout
+	Undelegations []reward.DelegatorPayout
+}
+
+// newRewardEvent returns the event of the payouts of the block, nil if it paid
+// out nothing
+func newRewardEvent(block *types.Block, payout reward.Reader) *RewardEvent {
+	if payout == nil {
+		return nil
+	}
+	round := payout.ReadRoundResult()
+	ev := &RewardEvent{
+		BlockNum:      block.NumberU64(),
+		BlockHash:     block.Hash(),
+		Epoch:         block.Epoch().Uint64(),
+		Rewards:       append(append([]reward.Payout{}, round.BeaconchainAward...), round.ShardChainAward...),
+		Undelegations: round.Undelegations,
+	}
+	if len(ev.Rewards) == 0 && len(ev.Undelegations) == 0 {
+		return nil
+	}
+	return ev
+}
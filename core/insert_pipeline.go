@@ -0,0 +1,240 @@
+package core
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// InsertPriority is the priority of a block queued for insertion, lower values
+// first.
+type InsertPriority int
+
+// Priorities of the blocks queued for insertion
+const (
+	// InsertConsensus is the priority of the blocks confirmed by consensus
+	InsertConsensus InsertPriority = iota
+	// InsertSync is the priority of the blocks downloaded by state sync
+	InsertSync
+	// InsertBroadcast is the priority of the blocks broadcast by other nodes
+	InsertBroadcast
+)
+
+// insertVerifiers is the number of blocks whose headers are verified in
+// parallel ahead of their insertion.
+const insertVerifiers = 4
+
+var (
+	errInsertPipelineStopped = errors.New("block insertion pipeline stopped")
+
+	insertQueuedGauge  = metrics.NewRegisteredGauge("chain/insert/queued", nil)
+	insertDedupCounter = metrics.NewRegisteredCounter("chain/insert/deduped", nil)
+)
+
+// insertRequest is a block queued for insertion and the callers waiting for it
+type insertRequest struct {
+	block    *types.Block
+	priority InsertPriority
+	seq      uint64
+	// verifyHeaders is set if any of the callers asks for the headers to be
+	// verified, verifiedAhead once they are; both guarded by the pipeline
+	// mutex while the block is queued
+	verifyHeaders bool
+	verifiedAhead bool
+	verified      chan error // the result of the early header verification
+	waiters       []chan error
+}
+
+// InsertPipeline is the single entry point inserting blocks into a chain. The
+// blocks are queued and deduplicated, their headers are verified in parallel
+// ahead of insertion, and they are executed one at a time, consensus confirmed
+// blocks first.
+type InsertPipeline struct {
+	bc       *BlockChain
+	mutex    sync.Mutex
+	requests map[common.Hash]*insertRequest
+	seq      uint64
+	wake     chan struct{}
+	verifier chan struct{}
+	stopped  bool
+}
+
+func newInsertPipeline(bc *BlockChain) *InsertPipeline {
+	return &InsertPipeline{
+		bc:       bc,
+		requests: map[common.Hash]*insertRequest{},
+		wake:     make(chan struct{}, 1),
+		verifier: make(chan struct{}, insertVerifiers),
+	}
+}
+
+// Insert queues the block for insertion and waits until it is inserted. The
+// block headers are verified, including the seals, if verifyHeaders is set.
+// A block already queued is inserted once, with the highest priority it was
+// queued with, its headers verified if any of the callers asks for it.
+func (p *InsertPipeline) Insert(
+	block *types.Block, priority InsertPriority, verifyHeaders bool,
+) error {
+	done := make(chan error, 1)
+	p.mutex.Lock()
+	if p.stopped {
+		p.mutex.Unlock()
+		return errInsertPipelineStopped
+	}
+	if req, ok := p.requests[block.Hash()]; ok {
+		insertDedupCounter.Inc(1)
+		if priority < req.priority {
+			req.priority = priority
+		}
+		req.verifyHeaders = req.verifyHeaders || verifyHeaders
+		req.waiters = append(req.waiters, done)
+		p.mutex.Unlock()
+		return <-done
+	}
+	p.seq++
+	req := &insertRequest{
+		block:         block,
+		priority:      priority,
+		seq:           p.seq,
+		verifyHeaders: verifyHeaders,
+		verified:      make(chan error, 1),
+		waiters:       []chan error{done},
+	}
+	p.requests[block.Hash()] = req
+	insertQueuedGauge.Update(int64(len(p.requests)))
+	p.mutex.Unlock()
+
+	go p.verify(req)
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return <-done
+}
+
+// verify verifies the header of the queued block if its parent is already in
+// the chain; the headers of the other blocks are verified on insertion.
+func (p *InsertPipeline) verify(req *insertRequest) {
+	p.mutex.Lock()
+	verifyHeaders := req.verifyHeaders
+	p.mutex.Unlock()
+	if !verifyHeaders {
+		// verified on insertion if a caller asks for it later
+		req.verified <- nil
+		return
+	}
+	if p.bc.blockVerified(req.block, VerifiedHeader) {
+		// verified before being queued, ex: by consensus
+		p.setVerifiedAhead(req)
+		req.verified <- nil
+		return
+	}
+	p.verifier <- struct{}{}
+	defer func() { <-p.verifier }()
+	err := p.bc.Engine().VerifyHeader(p.bc, req.block.Header(), true)
+	if err == nil {
+		// verified ahead, no need to verify again on insertion
+		p.setVerifiedAhead(req)
+	}
+	req.verified <- err
+}
+
+func (p *InsertPipeline) setVerifiedAhead(req *insertRequest) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	req.verifiedAhead = true
+}
+
+// loop inserts the queued blocks one at a time until the chain stops
+func (p *InsertPipeline) loop() {
+	for {
+		select {
+		case <-p.wake:
+		case <-p.bc.quit:
+			p.abort()
+			return
+		}
+		for req := p.next(); req != nil; req = p.next() {
+			p.insert(req)
+		}
+	}
+}
+
+// next removes from the queue and returns the block to insert next: the block
+// of highest priority among the blocks whose parent is in the chain, or else
+// the lowest block, so that the blocks which cannot be inserted are reported
+// as such. The block queued again while it is inserted is inserted again.
+func (p *InsertPipeline) next() *insertRequest {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var ready, lowest *insertRequest
+	for _, req := range p.requests {
+		block := req.block
+		if p.bc.HasHeader(block.ParentHash(), block.NumberU64()-1) &&
+			(ready == nil || req.priority < ready.priority ||
+				(req.priority == ready.priority && req.seq < ready.seq)) {
+			ready = req
+		}
+		if lowest == nil || block.NumberU64() < lowest.block.NumberU64() ||
+			(block.NumberU64() == lowest.block.NumberU64() && req.seq < lowest.seq) {
+			lowest = req
+		}
+	}
+	if ready == nil {
+		ready = lowest
+	}
+	if ready != nil {
+		delete(p.requests, ready.block.Hash())
+		insertQueuedGauge.Update(int64(len(p.requests)))
+	}
+	return ready
+}
+
+// insert inserts the queued block into the chain and reports the result to
+// the callers waiting for it
+func (p *InsertPipeline) insert(req *insertRequest) {
+	err := <-req.verified
+	if err == consensus_engine.ErrUnknownAncestor {
+		err = nil
+	}
+	if err == nil && req.priority != InsertConsensus {
+		// the blocks confirmed by consensus had their shard state verified
+		err = p.bc.verifyShardState(req.block)
+	}
+	if err == nil {
+		_, err = p.bc.InsertChain(types.Blocks{req.block}, req.verifyOnInsert())
+	}
+	if err != nil {
+		utils.Logger().Debug().Err(err).
+			Uint64("blockNum", req.block.NumberU64()).
+			Int("priority", int(req.priority)).
+			Msg("[InsertPipeline] cannot insert block")
+	}
+	for _, done := range req.waiters {
+		done <- err
+	}
+}
+
+// verifyOnInsert tells whether the headers of the block are verified on
+// insertion, once the request is out of the queue and its flags settled
+func (req *insertRequest) verifyOnInsert() bool {
+	return req.verifyHeaders && !req.verifiedAhead
+}
+
+// abort fails the queued blocks once the chain stops
+func (p *InsertPipeline) abort() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.stopped = true
+	for hash, req := range p.requests {
+		for _, done := range req.waiters {
+			done <- errInsertPipelineStopped
+		}
+		delete(p.requests, hash)
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/pkg/errors"
+)
+
+// waitInsertRequest waits until the block is queued for the given number of
+// callers and returns its request
+func waitInsertRequest(t *testing.T, p *InsertPipeline, block *types.Block, callers int) *insertRequest {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		p.mutex.Lock()
+		req, ok := p.requests[block.Hash()]
+		queued := ok && len(req.waiters) == callers
+		p.mutex.Unlock()
+		if queued {
+			// the early verification is done
+			err := <-req.verified
+			req.verified <- err
+			return req
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("block not queued for %d callers", callers)
+	return nil
+}
+
+func testPipelineBlock(bc *BlockChain) *types.Block {
+	header := blockfactory.NewTestHeader().With().
+		Number(big.NewInt(1)).
+		ParentHash(bc.Genesis().Hash()).
+		Header()
+	return types.NewBlockWithHeader(header)
+}
+
+func TestInsertPipelineMergesVerification(t *testing.T) {
+	bc := createBlockChain()
+	defer bc.Stop()
+	// a pipeline not inserting, to inspect the queued requests
+	p := newInsertPipeline(bc)
+	block := testPipelineBlock(bc)
+
+	go p.Insert(block, InsertBroadcast, false)
+	req := waitInsertRequest(t, p, block, 1)
+	if req.verifyOnInsert() {
+		t.Error("expected no verification asked for")
+	}
+
+	// a caller asking for the verification queues the same block
+	go p.Insert(block, InsertSync, true)
+	req = waitInsertRequest(t, p, block, 2)
+	p.mutex.Lock()
+	if !req.verifyOnInsert() || req.priority != InsertSync {
+		t.Errorf("expected the verification and priority merged, got %t %d",
+			req.verifyOnInsert(), req.priority)
+	}
+	p.mutex.Unlock()
+
+	// the block is out of the queue once picked for insertion
+	if next := p.next(); next != req {
+		t.Fatalf("expected the queued block next, got %v", next)
+	}
+	if len(p.requests) != 0 {
+		t.Errorf("expected the queue empty, got %d", len(p.requests))
+	}
+	for _, done := range req.waiters {
+		done <- nil
+	}
+}
+
+func TestInsertPipelineVerifiedAhead(t *testing.T) {
+	bc := createBlockChain()
+	defer bc.Stop()
+	p := newInsertPipeline(bc)
+	block := testPipelineBlock(bc)
+	bc.MarkBlockVerified(block, VerifiedHeader)
+
+	go p.Insert(block, InsertSync, true)
+	req := waitInsertRequest(t, p, block, 1)
+	go p.Insert(block, InsertBroadcast, false)
+	req = waitInsertRequest(t, p, block, 2)
+
+	p.mutex.Lock()
+	if !req.verifyHeaders || !req.verifiedAhead || req.verifyOnInsert() {
+		t.Errorf("expected the headers verified ahead only, got %t %t",
+			req.verifyHeaders, req.verifiedAhead)
+	}
+	if req.priority != InsertSync {
+		t.Errorf("expected the highest priority kept, got %d", req.priority)
+	}
+	p.mutex.Unlock()
+
+	// the block queued again while inserted is a new request
+	next := p.next()
+	go p.Insert(block, InsertBroadcast, false)
+	again := waitInsertRequest(t, p, block, 1)
+	if again == next || again.seq <= next.seq {
+		t.Error("expected the block queued again")
+	}
+	for _, done := range append(next.waiters, again.waiters...) {
+		done <- nil
+	}
+}
+
+// shardStateEngine counts the shard states verified, failing them with err
+type shardStateEngine struct {
+	consensus_engine.Engine
+	err      error
+	verified int
+}
+
+func (e *shardStateEngine) VerifyShardState(
+	chain consensus_engine.ChainReader, beacon consensus_engine.ChainReader, header *block.Header,
+) error {
+	e.verified++
+	return e.err
+}
+
+func TestInsertPipelineVerifiesShardState(t *testing.T) {
+	errBadShardState := errors.New("unexpected committee")
+	errNoBeacon := errors.New("beacon chain closed")
+	for _, test := range []struct {
+		name       string
+		priority   InsertPriority
+		shardState []byte
+		beacon     error // error opening the beacon chain, if verifying
+		enabled    bool
+		err        error // shard state verification error
+		verified   bool
+		rejected   error // cause of the rejection by the shard state verification
+	}{
+		{"consensus block", InsertConsensus, []byte{0x01}, nil, true, errBadShardState, false, nil},
+		{"synced block", InsertSync, []byte{0x01}, nil, true, errBadShardState, true, errBadShardState},
+		{"broadcast block", InsertBroadcast, []byte{0x01}, nil, true, errBadShardState, true, errBadShardState},
+		{"valid shard state", InsertSync, []byte{0x01}, nil, true, nil, true, nil},
+		{"no shard state", InsertSync, nil, nil, true, errBadShardState, false, nil},
+		{"verification disabled", InsertSync, []byte{0x01}, nil, false, errBadShardState, false, nil},
+		{"beacon chain unavailable", InsertSync, []byte{0x01}, errNoBeacon, true, nil, false, errNoBeacon},
+	} {
+		bc := createBlockChain()
+		engine := &shardStateEngine{Engine: bc.engine, err: test.err}
+		bc.engine = engine
+		if test.enabled {
+			bc.EnableShardStateVerification(func() (consensus_engine.ChainReader, error) {
+				if test.beacon != nil {
+					return nil, test.beacon
+				}
+				return bc, nil
+			})
+		}
+		header := blockfactory.NewTestHeader().With().
+			Number(big.NewInt(1)).
+			ParentHash(bc.Genesis().Hash()).
+			ShardState(test.shardState).
+			Header()
+		done := make(chan error, 1)
+		req := &insertRequest{
+			block:    types.NewBlockWithHeader(header),
+			priority: test.priority,
+			verified: make(chan error, 1),
+			waiters:  []chan error{done},
+		}
+		req.verified <- nil
+		newInsertPipeline(bc).insert(req)
+		err := <-done
+
+		if (engine.verified == 1) != test.verified {
+			t.Errorf("%s: expected the shard state verified %t, got %d verifications", test.name, test.verified, engine.verified)
+		}
+		if test.rejected != nil && errors.Cause(err) != test.rejected {
+			t.Errorf("%s: expected the block rejected with %v, got %v", test.name, test.rejected, err)
+		}
+		if test.rejected == nil && (errors.Cause(err) == errBadShardState || errors.Cause(err) == errNoBeacon) {
+			t.Errorf("%s: expected the block not rejected for its shard state, got %v", test.name, err)
+		}
+		bc.Stop()
+	}
+}
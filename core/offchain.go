@@ -104,11 +104,16 @@ func (bc *BlockChain) CommitOffChainData(
 	// Shard State and Validator Update
 	if isNewEpoch {
 		// Write shard state for the new epoch
-		_, err := bc.WriteShardStateBytes(batch, nextBlockEpoch, header.ShardState())
+		shardState, err := bc.WriteShardStateBytes(batch, nextBlockEpoch, header.ShardState())
 		if err != nil {
 			header.Logger(utils.Logger()).Warn().Err(err).Msg("cannot store shard state")
 			return NonStatTy, err
 		}
+		if err := bc.epochChain.writeLastHeader(
+			batch, header, nextBlockEpoch, shardState,
+		); err != nil {
+			return NonStatTy, err
+		}
 	}
 
 	// Do bookkeeping for new staking txns
@@ -226,6 +231,13 @@ func (bc *BlockChain) CommitOffChainData(
 			); err != nil {
 				return NonStatTy, err
 			}
+			if ev := newRewardEvent(block, payout); bc.rewardIndex && ev != nil {
+				if err := bc.writeRewardEvent(batch, ev); err != nil {
+					utils.Logger().Info().Err(err).
+						Uint64("block-number", block.NumberU64()).
+						Msg("could not index the payouts of the block")
+				}
+			}
 			for _, paid := range [...][]reward.Payout{
 				roundResult.BeaconchainAward, roundResult.ShardChainAward,
 			} {
@@ -26,11 +26,16 @@ import (
 	"github.com/harmony-one/harmony/block"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
 )
 
 // MsgNoShardStateFromDB error message for shard state reading failure
 var MsgNoShardStateFromDB = "failed to read shard state from DB"
 
+// ErrNoShardStateFromDB is returned when the shard state of an epoch is not in
+// the DB, which may still be written once the epoch is reached
+var ErrNoShardStateFromDB = errors.New(MsgNoShardStateFromDB)
+
 // Indicate whether the receipts corresponding to a blockHash is spent or not
 const (
 	SpentByte byte = iota
@@ -119,6 +124,31 @@ func WriteHeadFastBlockHash(db DatabaseWriter, hash common.Hash) {
 	}
 }
 
+// ReadBulkImportMarker retrieves the number of the last block whose state was
+// flushed to disk by an unfinished bulk import, if any.
+func ReadBulkImportMarker(db DatabaseReader) (uint64, bool) {
+	data, _ := db.Get(bulkImportKey)
+	if len(data) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(data), true
+}
+
+// WriteBulkImportMarker stores the number of the last block whose state was
+// flushed to disk by the ongoing bulk import.
+func WriteBulkImportMarker(db DatabaseWriter, number uint64) {
+	if err := db.Put(bulkImportKey, encodeBlockNumber(number)); err != nil {
+		utils.Logger().Error().Msg("Failed to store bulk import marker")
+	}
+}
+
+// DeleteBulkImportMarker removes the marker of a finished bulk import.
+func DeleteBulkImportMarker(db DatabaseDeleter) {
+	if err := db.Delete(bulkImportKey); err != nil {
+		utils.Logger().Error().Msg("Failed to delete bulk import marker")
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
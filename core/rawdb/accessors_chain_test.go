@@ -331,3 +331,27 @@ func TestBlockReceiptStorage(t *testing.T) {
 		t.Fatalf("deleted receipts returned: %v", rs)
 	}
 }
+
+// Tests that the database repair records are stored and retrieved in order.
+func TestRepairRecordStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	if records := ReadRepairRecords(db); len(records) != 0 {
+		t.Fatalf("non existent repair records returned: %v", records)
+	}
+	records := []RepairRecord{
+		{Time: 1, Head: 100, Corrupted: 98, NewHead: 97, Reason: "body missing"},
+		{Time: 2, Head: 200, Corrupted: 200, NewHead: 199, Reason: "receipt root mismatch"},
+	}
+	if err := WriteRepairRecords(db, records); err != nil {
+		t.Fatalf("failed to write repair records: %v", err)
+	}
+	got := ReadRepairRecords(db)
+	if len(got) != len(records) {
+		t.Fatalf("repair records mismatch: have %v, want %v", got, records)
+	}
+	for i := range records {
+		if got[i] != records[i] {
+			t.Errorf("repair record %d mismatch: have %v, want %v", i, got[i], records[i])
+		}
+	}
+}
@@ -89,3 +89,36 @@ func WritePreimages(db DatabaseWriter, number uint64, preimages map[common.Hash]
 	preimageCounter.Inc(int64(len(preimages)))
 	preimageHitCounter.Inc(int64(len(preimages)))
 }
+
+// RepairRecord is a truncation of the chain to its last consistent block,
+// done on startup on finding corrupted blocks
+type RepairRecord struct {
+	Time      uint64 // unix seconds
+	Head      uint64 // head block before the repair
+	Corrupted uint64 // lowest corrupted block
+	NewHead   uint64 // head block after the repair
+	Reason    string
+}
+
+// ReadRepairRecords retrieves the repairs of the chain database, oldest first.
+func ReadRepairRecords(db DatabaseReader) []RepairRecord {
+	data, _ := db.Get(dbRepairsKey)
+	if len(data) == 0 {
+		return nil
+	}
+	records := []RepairRecord{}
+	if err := rlp.DecodeBytes(data, &records); err != nil {
+		utils.Logger().Error().Err(err).Msg("Invalid database repair records RLP")
+		return nil
+	}
+	return records
+}
+
+// WriteRepairRecords stores the repairs of the chain database.
+func WriteRepairRecords(db DatabaseWriter, records []RepairRecord) error {
+	data, err := rlp.EncodeToBytes(records)
+	if err != nil {
+		return err
+	}
+	return db.Put(dbRepairsKey, data)
+}
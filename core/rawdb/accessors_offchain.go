@@ -1,10 +1,12 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/block"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/shard"
@@ -18,7 +20,7 @@ func ReadShardState(
 ) (*shard.State, error) {
 	data, err := db.Get(shardStateKey(epoch))
 	if err != nil {
-		return nil, errors.New(MsgNoShardStateFromDB)
+		return nil, ErrNoShardStateFromDB
 	}
 	ss, err2 := shard.DecodeWrapper(data)
 	if err2 != nil {
@@ -301,6 +303,132 @@ func WriteBlockCommitSig(db DatabaseWriter, blockNum uint64, sigAndBitmap []byte
 	return db.Put(blockCommitSigKey(blockNum), sigAndBitmap)
 }
 
+// ReadVoteRecord retrieves the vote record of the consensus round of a block.
+func ReadVoteRecord(db DatabaseReader, blockNum uint64) ([]byte, error) {
+	return db.Get(voteRecordKey(blockNum))
+}
+
+// WriteVoteRecord stores the vote record of the consensus round of a block.
+func WriteVoteRecord(db DatabaseWriter, blockNum uint64, data []byte) error {
+	return db.Put(voteRecordKey(blockNum), data)
+}
+
+// DeleteVoteRecord deletes the vote record of the consensus round of a block.
+func DeleteVoteRecord(db DatabaseDeleter, blockNum uint64) error {
+	return db.Delete(voteRecordKey(blockNum))
+}
+
+// ReadVoteLedgerTail retrieves the oldest block number kept in the vote ledger.
+func ReadVoteLedgerTail(db DatabaseReader) (uint64, bool) {
+	data, _ := db.Get(voteLedgerTailKey)
+	if len(data) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(data), true
+}
+
+// WriteVoteLedgerTail stores the oldest block number kept in the vote ledger.
+func WriteVoteLedgerTail(db DatabaseWriter, blockNum uint64) error {
+	return db.Put(voteLedgerTailKey, encodeBlockNumber(blockNum))
+}
+
+// ReadRewardEvents retrieves the payouts of a block kept in the reward index.
+func ReadRewardEvents(db DatabaseReader, blockNum uint64) ([]byte, error) {
+	return db.Get(rewardEventsKey(blockNum))
+}
+
+// WriteRewardEvents stores the payouts of a block in the reward index.
+func WriteRewardEvents(db DatabaseWriter, blockNum uint64, data []byte) error {
+	return db.Put(rewardEventsKey(blockNum), data)
+}
+
+// ReadAbsentees retrieves the absentee statistics of the committee of an epoch.
+func ReadAbsentees(db DatabaseReader, epoch uint64) ([]byte, error) {
+	return db.Get(absenteesKey(epoch))
+}
+
+// WriteAbsentees stores the absentee statistics of the committee of an epoch.
+func WriteAbsentees(db DatabaseWriter, epoch uint64, data []byte) error {
+	return db.Put(absenteesKey(epoch), data)
+}
+
+// ReadCXDelivery retrieves the delivery of an outgoing cross-shard transaction,
+// or nil if it is not known to be delivered.
+func ReadCXDelivery(db DatabaseReader, txHash common.Hash) *types.CXDelivery {
+	data, err := db.Get(cxDeliveryKey(txHash))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	delivery := &types.CXDelivery{}
+	if err := rlp.DecodeBytes(data, delivery); err != nil {
+		utils.Logger().Error().Err(err).
+			Str("txHash", txHash.Hex()).
+			Msg("Invalid cross shard delivery RLP")
+		return nil
+	}
+	return delivery
+}
+
+// WriteCXDelivery stores the delivery of an outgoing cross-shard transaction.
+func WriteCXDelivery(db DatabaseWriter, delivery *types.CXDelivery) error {
+	data, err := rlp.EncodeToBytes(delivery)
+	if err != nil {
+		return err
+	}
+	return db.Put(cxDeliveryKey(delivery.TxHash), data)
+}
+
+// ReadCXResend retrieves a queued resend of outgoing cross shard receipts, or
+// nil if it is not queued.
+func ReadCXResend(db DatabaseReader, toShardID uint32, number uint64, hash common.Hash) *types.CXResend {
+	data, err := db.Get(cxResendKey(toShardID, number, hash))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	resend := &types.CXResend{}
+	if err := rlp.DecodeBytes(data, resend); err != nil {
+		utils.Logger().Error().Err(err).
+			Str("blockHash", hash.Hex()).
+			Msg("Invalid cross shard resend RLP")
+		return nil
+	}
+	return resend
+}
+
+// WriteCXResend stores a queued resend of outgoing cross shard receipts.
+func WriteCXResend(db DatabaseWriter, resend *types.CXResend) error {
+	data, err := rlp.EncodeToBytes(resend)
+	if err != nil {
+		return err
+	}
+	return db.Put(cxResendKey(resend.ToShardID, resend.BlockNum, resend.BlockHash), data)
+}
+
+// DeleteCXResend removes a queued resend of outgoing cross shard receipts.
+func DeleteCXResend(db DatabaseDeleter, toShardID uint32, number uint64, hash common.Hash) error {
+	return db.Delete(cxResendKey(toShardID, number, hash))
+}
+
+// IterateCXResends calls fn on the queued resends of outgoing cross shard
+// receipts, by destination shard and block number, until it returns false.
+func IterateCXResends(db DatabaseIteratee, fn func(resend *types.CXResend) bool) error {
+	it := db.NewIteratorWithPrefix(cxResendPrefix)
+	defer it.Release()
+	for it.Next() {
+		resend := &types.CXResend{}
+		if err := rlp.DecodeBytes(it.Value(), resend); err != nil {
+			utils.Logger().Error().Err(err).
+				Hex("key", it.Key()).
+				Msg("Invalid cross shard resend RLP")
+			continue
+		}
+		if !fn(resend) {
+			break
+		}
+	}
+	return it.Error()
+}
+
 //// Resharding ////
 
 // ReadEpochBlockNumber retrieves the epoch block number for the given epoch,
@@ -338,4 +466,26 @@ func WriteEpochVdfBlockNum(db DatabaseWriter, epoch *big.Int, data []byte) error
 	return db.Put(epochVdfBlockNumberKey(epoch), data)
 }
 
+// ReadEpochLastHeader retrieves the header of the last block of the given epoch
+func ReadEpochLastHeader(db DatabaseReader, epoch *big.Int) (*block.Header, error) {
+	data, err := db.Get(epochLastHeaderKey(epoch))
+	if err != nil {
+		return nil, err
+	}
+	header := new(block.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode last header of epoch %v", epoch)
+	}
+	return header, nil
+}
+
+// WriteEpochLastHeader stores the header of the last block of its epoch
+func WriteEpochLastHeader(db DatabaseWriter, header *block.Header) error {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return err
+	}
+	return db.Put(epochLastHeaderKey(header.Epoch()), data)
+}
+
 //// Resharding ////
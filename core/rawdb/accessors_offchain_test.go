@@ -0,0 +1,34 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+func TestReadShardStateMissing(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	_, err := ReadShardState(db, big.NewInt(1))
+	if err != ErrNoShardStateFromDB {
+		t.Fatalf("expected ErrNoShardStateFromDB, got %v", err)
+	}
+	// callers classify the error however it is wrapped
+	wrapped := errors.Wrapf(err, "cannot read shard state of epoch %d", 1)
+	if errors.Cause(wrapped) != ErrNoShardStateFromDB {
+		t.Errorf("expected the cause of %v to be ErrNoShardStateFromDB", wrapped)
+	}
+
+	data, err := shard.EncodeWrapper(shard.State{Epoch: big.NewInt(1)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteShardStateBytes(db, big.NewInt(1), data); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := ReadShardState(db, big.NewInt(1)); err != nil || state.Epoch.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("unexpected shard state %v, error %v", state, err)
+	}
+}
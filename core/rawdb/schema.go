@@ -35,6 +35,10 @@ var (
 	headBlockKey = []byte("LastBlock")
 	// headFastBlockKey tracks the latest known incomplete block's hash duirng fast sync.
 	headFastBlockKey = []byte("LastFast")
+	// bulkImportKey tracks the last block whose state is on disk during a bulk import.
+	bulkImportKey = []byte("BulkImport")
+	// dbRepairsKey tracks the repairs of the chain database done on startup.
+	dbRepairsKey = []byte("DBRepairs")
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix                 = []byte("h")  // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix               = []byte("t")  // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -67,11 +71,20 @@ var (
 	epochVrfBlockNumbersPrefix = []byte("epoch-vrf-block-numbers")
 	// epochVdfBlockNumberPrefix  + epoch (big.Int.Bytes())
 	epochVdfBlockNumberPrefix = []byte("epoch-vdf-block-number")
+	// epochLastHeaderPrefix + epoch (big.Int.Bytes())
+	// -> header of the last block of the epoch (RLP)
+	epochLastHeaderPrefix = []byte("epoch-last-header")
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix        = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 	preimageCounter             = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter          = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 	currentRewardGivenOutPrefix = []byte("blk-rwd-")
+	voteRecordPrefix            = []byte("vote-")          // voteRecordPrefix + num (uint64 big endian) -> vote record
+	voteLedgerTailKey           = []byte("VoteLedgerTail") // oldest block number of the vote ledger
+	rewardEventsPrefix          = []byte("reward-events-") // rewardEventsPrefix + num (uint64 big endian) -> reward event
+	cxDeliveryPrefix            = []byte("cx-delivery-")   // cxDeliveryPrefix + tx hash -> cross shard delivery
+	absenteesPrefix             = []byte("absentees-")     // absenteesPrefix + epoch (uint64 big endian) -> absentee statistics
+	cxResendPrefix              = []byte("cx-resend-")     // cxResendPrefix + to shard (uint32 big endian) + num (uint64 big endian) + hash -> queued resend
 )
 
 // TxLookupEntry is a positional metadata to help looking up the data content of
@@ -165,6 +178,10 @@ func epochVdfBlockNumberKey(epoch *big.Int) []byte {
 	return append(epochVdfBlockNumberPrefix, epoch.Bytes()...)
 }
 
+func epochLastHeaderKey(epoch *big.Int) []byte {
+	return append(epochLastHeaderPrefix, epoch.Bytes()...)
+}
+
 func shardLastCrosslinkKey(shardID uint32) []byte {
 	sbKey := make([]byte, 4)
 	binary.BigEndian.PutUint32(sbKey, shardID)
@@ -222,3 +239,31 @@ func blockRewardAccumKey(number uint64) []byte {
 func blockCommitSigKey(number uint64) []byte {
 	return append(blockCommitSigPrefix, encodeBlockNumber(number)...)
 }
+
+// voteRecordKey = voteRecordPrefix + num (uint64 big endian)
+func voteRecordKey(number uint64) []byte {
+	return append(voteRecordPrefix, encodeBlockNumber(number)...)
+}
+
+// rewardEventsKey = rewardEventsPrefix + num (uint64 big endian)
+func rewardEventsKey(number uint64) []byte {
+	return append(rewardEventsPrefix, encodeBlockNumber(number)...)
+}
+
+// absenteesKey = absenteesPrefix + epoch (uint64 big endian)
+func absenteesKey(epoch uint64) []byte {
+	return append(absenteesPrefix, encodeBlockNumber(epoch)...)
+}
+
+// cxDeliveryKey = cxDeliveryPrefix + hash
+func cxDeliveryKey(hash common.Hash) []byte {
+	return append(cxDeliveryPrefix, hash.Bytes()...)
+}
+
+// cxResendKey = cxResendPrefix + shardID (uint32 big endian) + num (uint64 big endian) + hash
+func cxResendKey(toShardID uint32, number uint64, hash common.Hash) []byte {
+	key := make([]byte, len(cxResendPrefix)+4)
+	copy(key, cxResendPrefix)
+	binary.BigEndian.PutUint32(key[len(cxResendPrefix):], toShardID)
+	return append(append(key, encodeBlockNumber(number)...), hash.Bytes()...)
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/pkg/errors"
+)
+
+// MaxRewardEventsRange is the maximum number of blocks whose payouts are read
+// at once from the reward index
+const MaxRewardEventsRange = 1000
+
+// EnableRewardIndex keeps the payouts of the blocks inserted from now on in
+// the database, for the reward history queries. It must be called before the
+// chain is in use.
+func (bc *BlockChain) EnableRewardIndex() {
+	bc.rewardIndex = true
+}
+
+// RewardIndexEnabled returns whether the payouts of the blocks are indexed
+func (bc *BlockChain) RewardIndexEnabled() bool {
+	return bc.rewardIndex
+}
+
+// writeRewardEvent indexes the payouts of a block
+func (bc *BlockChain) writeRewardEvent(batch rawdb.DatabaseWriter, ev *RewardEvent) error {
+	data, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteRewardEvents(batch, ev.BlockNum, data)
+}
+
+// ReadRewardEvents returns the indexed payouts of the blocks in the given
+// inclusive range, skipping the blocks which paid out nothing
+func (bc *BlockChain) ReadRewardEvents(from, to uint64) ([]RewardEvent, error) {
+	if !bc.rewardIndex {
+		return nil, errors.New("reward index is not enabled")
+	}
+	if from > to || to-from >= MaxRewardEventsRange {
+		return nil, errors.Errorf(
+			"invalid block range [%d, %d], at most %d blocks", from, to, MaxRewardEventsRange,
+		)
+	}
+	events := []RewardEvent{}
+	for num := from; num <= to; num++ {
+		data, err := rawdb.ReadRewardEvents(bc.db, num)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		ev := RewardEvent{}
+		if err := rlp.DecodeBytes(data, &ev); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode the payouts of block %d", num)
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
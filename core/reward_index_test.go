@@ -0,0 +1,126 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/consensus/reward"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/staking/network"
+)
+
+func TestNewRewardEvent(t *testing.T) {
+	block := generateDumpTestBlocks(1)[0]
+	beacon := reward.Payout{Addr: common.Address{0x11}, NewlyEarned: big.NewInt(1)}
+	shard := reward.Payout{Addr: common.Address{0x22}, NewlyEarned: big.NewInt(2)}
+	undelegation := reward.DelegatorPayout{Delegator: common.Address{0x33}, Amount: big.NewInt(3)}
+	rewards := network.NewStakingEraRewardForRound(big.NewInt(3), nil, []reward.Payout{beacon}, []reward.Payout{shard})
+
+	for _, test := range []struct {
+		name          string
+		payout        reward.Reader
+		rewards       []reward.Payout
+		undelegations []reward.DelegatorPayout
+	}{
+		{"no payout", nil, nil, nil},
+		{"nothing paid out", network.NewStakingEraRewardForRound(big.NewInt(0), nil, nil, nil), nil, nil},
+		{"rewards", rewards, []reward.Payout{beacon, shard}, nil},
+		{
+			"undelegations",
+			network.WithUndelegations(
+				network.NewStakingEraRewardForRound(big.NewInt(0), nil, nil, nil),
+				[]reward.DelegatorPayout{undelegation},
+			),
+			nil,
+			[]reward.DelegatorPayout{undelegation},
+		},
+		{
+			"rewards and undelegations",
+			network.WithUndelegations(rewards, []reward.DelegatorPayout{undelegation}),
+			[]reward.Payout{beacon, shard},
+			[]reward.DelegatorPayout{undelegation},
+		},
+	} {
+		ev := newRewardEvent(block, test.payout)
+		if ev == nil {
+			if len(test.rewards) > 0 || len(test.undelegations) > 0 {
+				t.Errorf("%s: expected an event", test.name)
+			}
+			continue
+		}
+		if ev.BlockNum != block.NumberU64() || ev.BlockHash != block.Hash() || ev.Epoch != block.Epoch().Uint64() {
+			t.Errorf("%s: expected the event of block %d, got %+v", test.name, block.NumberU64(), ev)
+		}
+		if len(ev.Rewards) != len(test.rewards) || len(ev.Undelegations) != len(test.undelegations) {
+			t.Errorf("%s: expected %d rewards and %d undelegations, got %+v",
+				test.name, len(test.rewards), len(test.undelegations), ev)
+			continue
+		}
+		for i := range ev.Rewards {
+			if ev.Rewards[i].Addr != test.rewards[i].Addr {
+				t.Errorf("%s: expected the reward of %x, got %x", test.name, test.rewards[i].Addr, ev.Rewards[i].Addr)
+			}
+		}
+		for i := range ev.Undelegations {
+			if ev.Undelegations[i] != test.undelegations[i] {
+				t.Errorf("%s: unexpected undelegation %+v", test.name, ev.Undelegations[i])
+			}
+		}
+	}
+}
+
+func TestReadRewardEvents(t *testing.T) {
+	bc := newDumpTestChain(t, nil)
+	defer bc.Stop()
+	for _, num := range []uint64{3, 5} {
+		ev := &RewardEvent{BlockNum: num, Undelegations: []reward.DelegatorPayout{
+			{Delegator: common.Address{byte(num)}, Amount: big.NewInt(int64(num))},
+		}}
+		if err := bc.writeRewardEvent(bc.db, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rawdb.WriteRewardEvents(bc.db, 7, []byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		enabled  bool
+		from, to uint64
+		expected []uint64 // blocks of the events read
+		err      string
+	}{
+		{"index disabled", false, 0, 5, nil, "reward index is not enabled"},
+		{"inverted range", true, 5, 3, nil, "invalid block range"},
+		{"range too large", true, 0, MaxRewardEventsRange, nil, "invalid block range"},
+		{"blocks paying out", true, 0, 6, []uint64{3, 5}, ""},
+		{"single block", true, 5, 5, []uint64{5}, ""},
+		{"nothing paid out", true, 4, 4, []uint64{}, ""},
+		{"undecodable payouts", true, 5, 7, nil, "cannot decode the payouts of block 7"},
+	} {
+		bc.rewardIndex = test.enabled
+		events, err := bc.ReadRewardEvents(test.from, test.to)
+		if test.err != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+				t.Errorf("%s: expected %q, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(events) != len(test.expected) {
+			t.Errorf("%s: expected %d events, got %d", test.name, len(test.expected), len(events))
+			continue
+		}
+		for i, ev := range events {
+			if ev.BlockNum != test.expected[i] || len(ev.Undelegations) != 1 ||
+				ev.Undelegations[0].Amount.Uint64() != test.expected[i] {
+				t.Errorf("%s: unexpected event %+v", test.name, ev)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+var shardStateRejectedCounter = metrics.NewRegisteredCounter("chain/insert/shard_state_rejected", nil)
+
+// EnableShardStateVerification verifies the shard state of the blocks crossing
+// an epoch which are received from other nodes, by sync or broadcast, against
+// the committee computed from this chain and the given beacon chain, before
+// inserting them. A leader cannot smuggle a bogus committee this way into the
+// nodes not taking part in its consensus. It must be called before the chain
+// is in use.
+func (bc *BlockChain) EnableShardStateVerification(
+	beacon func() (consensus_engine.ChainReader, error),
+) {
+	bc.shardStateBeacon = beacon
+}
+
+// verifyShardState verifies the shard state of the block if it crosses an
+// epoch and the verification is enabled
+func (bc *BlockChain) verifyShardState(block *types.Block) error {
+	if bc.shardStateBeacon == nil || len(block.Header().ShardState()) == 0 {
+		return nil
+	}
+	beacon, err := bc.shardStateBeacon()
+	if err != nil {
+		return errors.Wrap(err, "cannot open beacon chain to verify shard state")
+	}
+	if err := bc.Engine().VerifyShardState(bc, beacon, block.Header()); err != nil {
+		shardStateRejectedCounter.Inc(1)
+		utils.Logger().Warn().Err(err).
+			Uint64("blockNum", block.NumberU64()).
+			Str("hash", block.Hash().Hex()).
+			Uint64("epoch", block.Epoch().Uint64()).
+			Msg("[InsertPipeline] rejecting block with unexpected shard state")
+		return errors.Wrapf(err, "shard state of block %d", block.NumberU64())
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+var errValidatorAccess = errors.New("state accessed the validator wrappers")
+
+// AccessSet is a set of accounts and storage slots of a state
+type AccessSet struct {
+	Accounts map[common.Address]struct{}
+	Slots    map[common.Address]map[common.Hash]struct{}
+}
+
+// NewAccessSet creates an empty access set
+func NewAccessSet() *AccessSet {
+	return &AccessSet{
+		Accounts: map[common.Address]struct{}{},
+		Slots:    map[common.Address]map[common.Hash]struct{}{},
+	}
+}
+
+func (s *AccessSet) addAccount(addr common.Address) {
+	s.Accounts[addr] = struct{}{}
+}
+
+func (s *AccessSet) addSlot(addr common.Address, key common.Hash) {
+	slots, ok := s.Slots[addr]
+	if !ok {
+		slots = map[common.Hash]struct{}{}
+		s.Slots[addr] = slots
+	}
+	slots[key] = struct{}{}
+}
+
+// Merge adds the accounts and slots of the other set to the set
+func (s *AccessSet) Merge(other *AccessSet) {
+	for addr := range other.Accounts {
+		s.addAccount(addr)
+	}
+	for addr, slots := range other.Slots {
+		for key := range slots {
+			s.addSlot(addr, key)
+		}
+	}
+}
+
+// Intersects tells whether the two sets share an account or a storage slot
+func (s *AccessSet) Intersects(other *AccessSet) bool {
+	for addr := range other.Accounts {
+		if _, ok := s.Accounts[addr]; ok {
+			return true
+		}
+	}
+	for addr, slots := range other.Slots {
+		mine, ok := s.Slots[addr]
+		if !ok {
+			continue
+		}
+		for key := range slots {
+			if _, ok := mine[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessTracker records the accounts and slots read and written on a state
+type accessTracker struct {
+	reads      *AccessSet
+	writes     *AccessSet
+	validators bool // whether the validator wrappers were accessed
+}
+
+// TrackAccess records the accounts and storage slots read and written from
+// now on, to be read with AccessedState. The writes reverted are recorded too.
+func (db *DB) TrackAccess() {
+	db.access = &accessTracker{reads: NewAccessSet(), writes: NewAccessSet()}
+}
+
+// AccessedState returns the accounts and storage slots read and written since
+// the access tracking started, nil if not tracked
+func (db *DB) AccessedState() (reads, writes *AccessSet) {
+	if db.access == nil {
+		return nil, nil
+	}
+	return db.access.reads, db.access.writes
+}
+
+func (db *DB) accessAccount(addr common.Address, write bool) {
+	if db.access == nil {
+		return
+	}
+	db.access.reads.addAccount(addr)
+	if write {
+		db.access.writes.addAccount(addr)
+	}
+}
+
+func (db *DB) accessSlot(addr common.Address, key common.Hash, write bool) {
+	if db.access == nil {
+		return
+	}
+	db.access.reads.addSlot(addr, key)
+	if write {
+		db.access.writes.addSlot(addr, key)
+	}
+}
+
+func (db *DB) accessValidators() {
+	if db.access != nil {
+		db.access.validators = true
+	}
+}
+
+// ApplyWrites sets the accounts and storage slots written on the finalised
+// source state, tracked since it was copied or forked from this state, to
+// their values in the source, along with its preimages. The accounts deleted
+// from the source are suicided, to be deleted on the next finalisation of
+// this state.
+// It fails, leaving this state untouched, if the source accessed the
+// validator wrappers, whose cache is not carried over.
+func (db *DB) ApplyWrites(src *DB) error {
+	if src.access == nil {
+		return errors.New("access not tracked on the source state")
+	}
+	if src.access.validators {
+		return errValidatorAccess
+	}
+	writes := src.access.writes
+	deleted := map[common.Address]struct{}{}
+	for addr := range writes.Accounts {
+		obj := src.stateObjects[addr]
+		if obj == nil || obj.deleted {
+			deleted[addr] = struct{}{}
+			db.Suicide(addr)
+			continue
+		}
+		db.SetBalance(addr, new(big.Int).Set(obj.Balance()))
+		db.SetNonce(addr, obj.Nonce())
+		if db.GetCodeHash(addr) != common.BytesToHash(obj.CodeHash()) {
+			db.SetCode(addr, obj.Code(src.db))
+		}
+	}
+	for addr, slots := range writes.Slots {
+		if _, ok := deleted[addr]; ok {
+			continue
+		}
+		obj := src.stateObjects[addr]
+		if obj == nil || obj.deleted {
+			db.Suicide(addr)
+			continue
+		}
+		for key := range slots {
+			db.SetState(addr, key, obj.GetState(src.db, key))
+		}
+	}
+	for hash, preimage := range src.preimages {
+		db.AddPreimage(hash, preimage)
+	}
+	return nil
+}
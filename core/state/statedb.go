@@ -18,6 +18,7 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"sort"
@@ -93,6 +94,13 @@ type DB struct {
 	journal        *journal
 	validRevisions []revision
 	nextRevisionID int
+
+	// Accounts finalised since last read, if tracked
+	touched map[common.Address]struct{}
+	// State read and written since tracking started, if tracked
+	access *accessTracker
+	// State forked, whose objects are copied on first access, if forked
+	parent *DB
 }
 
 // New creates a new state from a given trie.
@@ -205,18 +213,21 @@ func (db *DB) SubRefund(gas uint64) {
 // Exist reports whether the given account address exists in the state.
 // Notably this also returns true for suicided accounts.
 func (db *DB) Exist(addr common.Address) bool {
+	db.accessAccount(addr, false)
 	return db.getStateObject(addr) != nil
 }
 
 // Empty returns whether the state object is either non-existent
 // or empty according to the EIP161 specification (balance = nonce = code = 0)
 func (db *DB) Empty(addr common.Address) bool {
+	db.accessAccount(addr, false)
 	so := db.getStateObject(addr)
 	return so == nil || so.empty()
 }
 
 // GetBalance retrieves the balance from the given address or 0 if object not found
 func (db *DB) GetBalance(addr common.Address) *big.Int {
+	db.accessAccount(addr, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Balance()
@@ -226,6 +237,7 @@ func (db *DB) GetBalance(addr common.Address) *big.Int {
 
 // GetNonce ...
 func (db *DB) GetNonce(addr common.Address) uint64 {
+	db.accessAccount(addr, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Nonce()
@@ -236,6 +248,7 @@ func (db *DB) GetNonce(addr common.Address) uint64 {
 
 // GetCode ...
 func (db *DB) GetCode(addr common.Address) []byte {
+	db.accessAccount(addr, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Code(db.db)
@@ -245,6 +258,7 @@ func (db *DB) GetCode(addr common.Address) []byte {
 
 // GetCodeSize ...
 func (db *DB) GetCodeSize(addr common.Address) int {
+	db.accessAccount(addr, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject == nil {
 		return 0
@@ -263,6 +277,7 @@ func (db *DB) GetCodeSize(addr common.Address) int {
 
 // GetCodeHash ...
 func (db *DB) GetCodeHash(addr common.Address) common.Hash {
+	db.accessAccount(addr, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject == nil {
 		return common.Hash{}
@@ -272,6 +287,7 @@ func (db *DB) GetCodeHash(addr common.Address) common.Hash {
 
 // GetState retrieves a value from the given account's storage trie.
 func (db *DB) GetState(addr common.Address, hash common.Hash) common.Hash {
+	db.accessSlot(addr, hash, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetState(db.db, hash)
@@ -299,6 +315,7 @@ func (db *DB) GetStorageProof(a common.Address, key common.Hash) ([][]byte, erro
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (db *DB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
+	db.accessSlot(addr, hash, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetCommittedState(db.db, hash)
@@ -324,6 +341,7 @@ func (db *DB) StorageTrie(addr common.Address) Trie {
 
 // HasSuicided ...
 func (db *DB) HasSuicided(addr common.Address) bool {
+	db.accessAccount(addr, false)
 	stateObject := db.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.suicided
@@ -337,6 +355,7 @@ func (db *DB) HasSuicided(addr common.Address) bool {
 
 // AddBalance adds amount to the account associated with addr.
 func (db *DB) AddBalance(addr common.Address, amount *big.Int) {
+	db.accessAccount(addr, true)
 	stateObject := db.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.AddBalance(amount)
@@ -345,6 +364,7 @@ func (db *DB) AddBalance(addr common.Address, amount *big.Int) {
 
 // SubBalance subtracts amount from the account associated with addr.
 func (db *DB) SubBalance(addr common.Address, amount *big.Int) {
+	db.accessAccount(addr, true)
 	stateObject := db.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SubBalance(amount)
@@ -353,6 +373,7 @@ func (db *DB) SubBalance(addr common.Address, amount *big.Int) {
 
 // SetBalance ...
 func (db *DB) SetBalance(addr common.Address, amount *big.Int) {
+	db.accessAccount(addr, true)
 	stateObject := db.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetBalance(amount)
@@ -361,6 +382,7 @@ func (db *DB) SetBalance(addr common.Address, amount *big.Int) {
 
 // SetNonce ...
 func (db *DB) SetNonce(addr common.Address, nonce uint64) {
+	db.accessAccount(addr, true)
 	stateObject := db.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetNonce(nonce)
@@ -369,6 +391,7 @@ func (db *DB) SetNonce(addr common.Address, nonce uint64) {
 
 // SetCode ...
 func (db *DB) SetCode(addr common.Address, code []byte) {
+	db.accessAccount(addr, true)
 	stateObject := db.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetCode(crypto.Keccak256Hash(code), code)
@@ -377,6 +400,7 @@ func (db *DB) SetCode(addr common.Address, code []byte) {
 
 // SetState ...
 func (db *DB) SetState(addr common.Address, key, value common.Hash) {
+	db.accessSlot(addr, key, true)
 	stateObject := db.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetState(db.db, key, value)
@@ -389,6 +413,7 @@ func (db *DB) SetState(addr common.Address, key, value common.Hash) {
 // The account's state object is still available until the state is committed,
 // getStateObject will return a non-nil account after Suicide.
 func (db *DB) Suicide(addr common.Address) bool {
+	db.accessAccount(addr, true)
 	stateObject := db.getStateObject(addr)
 	if stateObject == nil {
 		return false
@@ -435,6 +460,18 @@ func (db *DB) getStateObject(addr common.Address) (stateObject *Object) {
 		return obj
 	}
 
+	// Copy the live object of the forked state.
+	if db.parent != nil {
+		if obj := db.parent.stateObjects[addr]; obj != nil {
+			cpy := obj.deepCopy(db)
+			db.setStateObject(cpy)
+			if cpy.deleted {
+				return nil
+			}
+			return cpy
+		}
+	}
+
 	// Load the object from the database.
 	enc, err := db.trie.TryGet(addr[:])
 	if len(enc) == 0 {
@@ -491,6 +528,7 @@ func (db *DB) createObject(addr common.Address) (newobj, prev *Object) {
 //
 // Carrying over the balance ensures that Ether doesn't disappear.
 func (db *DB) CreateAccount(addr common.Address) {
+	db.accessAccount(addr, true)
 	newObj, prev := db.createObject(addr)
 	if prev != nil {
 		newObj.setBalance(prev.data.Balance)
@@ -529,6 +567,7 @@ func (db *DB) Copy() *DB {
 		logSize:           db.logSize,
 		preimages:         make(map[common.Hash][]byte),
 		journal:           newJournal(),
+		parent:            db.parent,
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range db.journal.dirties {
@@ -565,6 +604,25 @@ func (db *DB) Copy() *DB {
 	return state
 }
 
+// Fork creates a state of the finalised state, copying its objects on first
+// access only, cheaper than a Copy to execute a transaction on. The state
+// must not be modified while its forks are in use, which may be used at once.
+// The logs and preimages of the state are not carried over.
+func (db *DB) Fork() *DB {
+	return &DB{
+		db:                db.db,
+		trie:              db.db.CopyTrie(db.trie),
+		stateObjects:      make(map[common.Address]*Object),
+		stateObjectsDirty: make(map[common.Address]struct{}),
+		stateValidators:   make(map[common.Address]*stk.ValidatorWrapper),
+		refund:            db.refund,
+		logs:              make(map[common.Hash][]*types.Log),
+		preimages:         make(map[common.Hash][]byte),
+		journal:           newJournal(),
+		parent:            db,
+	}
+}
+
 // Snapshot returns an identifier for the current revision of the state.
 func (db *DB) Snapshot() int {
 	id := db.nextRevisionID
@@ -621,11 +679,36 @@ func (db *DB) Finalise(deleteEmptyObjects bool) {
 			db.updateStateObject(stateObject)
 		}
 		db.stateObjectsDirty[addr] = struct{}{}
+		if db.touched != nil {
+			db.touched[addr] = struct{}{}
+		}
 	}
 	// Invalidate journal because reverting across transactions is not allowed.
 	db.clearJournalAndRefund()
 }
 
+// TrackTouched records the accounts finalised from now on, to be read with
+// TouchedAccounts
+func (db *DB) TrackTouched() {
+	db.touched = map[common.Address]struct{}{}
+}
+
+// TouchedAccounts returns the accounts finalised since the last call, or
+// since tracking started
+func (db *DB) TouchedAccounts() []common.Address {
+	addrs := make([]common.Address, 0, len(db.touched))
+	for addr := range db.touched {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	if db.touched != nil {
+		db.touched = map[common.Address]struct{}{}
+	}
+	return addrs
+}
+
 // IntermediateRoot computes the current root hash of the state trie.
 // It is called in between transactions to get the root hash that
 // goes into transaction receipts.
@@ -708,6 +791,7 @@ var (
 func (db *DB) ValidatorWrapper(
 	addr common.Address,
 ) (*stk.ValidatorWrapper, error) {
+	db.accessValidators()
 	// Read cache first
 	cached, ok := db.stateValidators[addr]
 	if ok {
@@ -730,6 +814,7 @@ func (db *DB) ValidatorWrapper(
 func (db *DB) ValidatorWrapperCopy(
 	addr common.Address,
 ) (*stk.ValidatorWrapper, error) {
+	db.accessValidators()
 	by := db.GetCode(addr)
 	if len(by) == 0 {
 		return nil, errAddressNotPresent
@@ -752,6 +837,7 @@ const doNotEnforceMaxBLS = -1
 func (db *DB) UpdateValidatorWrapper(
 	addr common.Address, val *stk.ValidatorWrapper,
 ) error {
+	db.accessValidators()
 	if err := val.SanityCheck(doNotEnforceMaxBLS); err != nil {
 		return err
 	}
@@ -778,6 +864,7 @@ func (db *DB) UnsetValidatorFlag(addr common.Address) {
 
 // IsValidator checks whether it is a validator object
 func (db *DB) IsValidator(addr common.Address) bool {
+	db.accessSlot(addr, staking.IsValidatorKey, false)
 	so := db.getStateObject(addr)
 	if so == nil {
 		return false
@@ -790,23 +877,27 @@ var (
 )
 
 // AddReward distributes the reward to all the delegators based on stake percentage.
-func (db *DB) AddReward(snapshot *stk.ValidatorWrapper, reward *big.Int, shareLookup map[common.Address]numeric.Dec) error {
+// It returns the reward credited to each delegator, commission included.
+func (db *DB) AddReward(
+	snapshot *stk.ValidatorWrapper, reward *big.Int, shareLookup map[common.Address]numeric.Dec,
+) (map[common.Address]*big.Int, error) {
+	credited := map[common.Address]*big.Int{}
 	if reward.Cmp(common.Big0) == 0 {
 		utils.Logger().Info().RawJSON("validator", []byte(snapshot.String())).
 			Msg("0 given as reward")
-		return nil
+		return credited, nil
 	}
 
 	curValidator, err := db.ValidatorWrapper(snapshot.Address)
 	if err != nil {
-		return errors.Wrapf(err, "failed to distribute rewards: validator does not exist")
+		return nil, errors.Wrapf(err, "failed to distribute rewards: validator does not exist")
 	}
 
 	if curValidator.Status == effective.Banned {
 		utils.Logger().Info().
 			RawJSON("slashed-validator", []byte(curValidator.String())).
 			Msg("cannot add reward to banned validator")
-		return nil
+		return credited, nil
 	}
 
 	rewardPool := big.NewInt(0).Set(reward)
@@ -818,6 +909,7 @@ func (db *DB) AddReward(snapshot *stk.ValidatorWrapper, reward *big.Int, shareLo
 			curValidator.Delegations[0].Reward,
 			commissionInt,
 		)
+		credit(credited, curValidator.Delegations[0].DelegatorAddress, commissionInt)
 		rewardPool.Sub(rewardPool, commissionInt)
 	}
 
@@ -828,12 +920,13 @@ func (db *DB) AddReward(snapshot *stk.ValidatorWrapper, reward *big.Int, shareLo
 		percentage, ok := shareLookup[delegation.DelegatorAddress]
 
 		if !ok {
-			return errors.Wrapf(err, "missing delegation shares for reward distribution")
+			return nil, errors.Wrapf(err, "missing delegation shares for reward distribution")
 		}
 
 		rewardInt := percentage.MulInt(totalRewardForDelegators).RoundInt()
 		curDelegation := curValidator.Delegations[i]
 		curDelegation.Reward.Add(curDelegation.Reward, rewardInt)
+		credit(credited, curDelegation.DelegatorAddress, rewardInt)
 		rewardPool.Sub(rewardPool, rewardInt)
 	}
 
@@ -841,7 +934,17 @@ func (db *DB) AddReward(snapshot *stk.ValidatorWrapper, reward *big.Int, shareLo
 	// always at index 0)
 	if rewardPool.Cmp(common.Big0) > 0 {
 		curValidator.Delegations[0].Reward.Add(curValidator.Delegations[0].Reward, rewardPool)
+		credit(credited, curValidator.Delegations[0].DelegatorAddress, rewardPool)
 	}
 
-	return nil
+	return credited, nil
+}
+
+// credit adds the amount to the reward credited to the delegator
+func credit(credited map[common.Address]*big.Int, delegator common.Address, amount *big.Int) {
+	if total, ok := credited[delegator]; ok {
+		total.Add(total, amount)
+	} else {
+		credited[delegator] = new(big.Int).Set(amount)
+	}
 }
@@ -34,6 +34,9 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/harmony-one/harmony/core/types"
 	common2 "github.com/harmony-one/harmony/internal/common"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/staking/effective"
+	staking "github.com/harmony-one/harmony/staking/types"
 )
 
 // Tests that updating a state trie does not leak any database writes prior to
@@ -436,3 +439,155 @@ func TestCopyOfCopy(t *testing.T) {
 		t.Fatalf("2nd copy fail, expected 42, got %v", got)
 	}
 }
+
+// Tests that the accounts finalised are tracked between reads.
+func TestTouchedAccounts(t *testing.T) {
+	sdb, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+	a, b := common.HexToAddress("aaaa"), common.HexToAddress("bbbb")
+	sdb.SetBalance(a, big.NewInt(1))
+	sdb.Finalise(true)
+	if touched := sdb.TouchedAccounts(); len(touched) != 0 {
+		t.Fatalf("untracked accounts returned: %v", touched)
+	}
+
+	sdb.TrackTouched()
+	sdb.SetBalance(b, big.NewInt(2))
+	sdb.SetNonce(a, 1)
+	sdb.Finalise(true)
+	if touched := sdb.TouchedAccounts(); len(touched) != 2 || touched[0] != a || touched[1] != b {
+		t.Fatalf("got touched accounts %v, want %v and %v", touched, a, b)
+	}
+	sdb.SetBalance(b, big.NewInt(3))
+	sdb.IntermediateRoot(true)
+	if touched := sdb.TouchedAccounts(); len(touched) != 1 || touched[0] != b {
+		t.Fatalf("got touched accounts %v, want %v", touched, b)
+	}
+}
+
+// Tests that the writes of a state copy or fork tracking its access are
+// applied back to the original state.
+func TestApplyWrites(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		cpy  func(*DB) *DB
+	}{
+		{"copy", (*DB).Copy},
+		{"fork", (*DB).Fork},
+	} {
+		sdb, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+		a, b, c := common.HexToAddress("aaaa"), common.HexToAddress("bbbb"), common.HexToAddress("cccc")
+		key, value := common.HexToHash("01"), common.HexToHash("02")
+		code := []byte{0x60, 0x00}
+		sdb.SetBalance(a, big.NewInt(10))
+		sdb.SetBalance(c, big.NewInt(5))
+		sdb.SetCode(c, code)
+		sdb.Finalise(true)
+
+		cpy := test.cpy(sdb)
+		cpy.TrackAccess()
+		cpy.SubBalance(a, big.NewInt(3))
+		cpy.AddBalance(b, big.NewInt(3))
+		cpy.SetNonce(a, 1)
+		cpy.SetState(b, key, value)
+		if got := cpy.GetCode(c); !bytes.Equal(got, code) {
+			t.Fatalf("%s: got code %x of %v, want %x", test.name, got, c, code)
+		}
+		cpy.Finalise(true)
+
+		reads, writes := cpy.AccessedState()
+		if _, ok := reads.Accounts[c]; !ok {
+			t.Fatalf("%s: read of %v not tracked", test.name, c)
+		}
+		if _, ok := writes.Accounts[c]; ok {
+			t.Fatalf("%s: read of %v tracked as a write", test.name, c)
+		}
+		read := NewAccessSet()
+		read.addAccount(c)
+		if writes.Intersects(read) {
+			t.Fatalf("%s: writes intersect the read of %v", test.name, c)
+		}
+		read.addSlot(b, key)
+		if !writes.Intersects(read) {
+			t.Fatalf("%s: writes do not intersect the slot %v of %v", test.name, key, b)
+		}
+		if balance := sdb.GetBalance(a); balance.Cmp(big.NewInt(10)) != 0 {
+			t.Fatalf("%s: original balance changed to %v", test.name, balance)
+		}
+
+		if err := sdb.ApplyWrites(cpy); err != nil {
+			t.Fatalf("%s: cannot apply the writes: %v", test.name, err)
+		}
+		sdb.Finalise(true)
+		if root, want := sdb.IntermediateRoot(true), cpy.IntermediateRoot(true); root != want {
+			t.Fatalf("%s: got root %x, want %x", test.name, root, want)
+		}
+
+		cpy = test.cpy(sdb)
+		cpy.TrackAccess()
+		cpy.ValidatorWrapper(a)
+		if err := sdb.ApplyWrites(cpy); err != errValidatorAccess {
+			t.Fatalf("%s: got error %v, want %v", test.name, err, errValidatorAccess)
+		}
+	}
+}
+
+func TestAddRewardCredited(t *testing.T) {
+	validator, delegator := common.HexToAddress("aaaa"), common.HexToAddress("bbbb")
+	half, quarter := numeric.NewDecWithPrec(5, 1), numeric.NewDecWithPrec(25, 2)
+	for _, test := range []struct {
+		name     string
+		rate     numeric.Dec
+		shares   []numeric.Dec // shares of the validator and the delegator
+		status   effective.Eligibility
+		reward   int64
+		expected map[common.Address]int64
+	}{
+		{
+			"commission and shares", numeric.NewDecWithPrec(1, 1), []numeric.Dec{half, half}, effective.Active, 1000,
+			map[common.Address]int64{validator: 550, delegator: 450},
+		},
+		{
+			"no commission", numeric.ZeroDec(), []numeric.Dec{quarter, numeric.OneDec().Sub(quarter)}, effective.Active, 1000,
+			map[common.Address]int64{validator: 250, delegator: 750},
+		},
+		{
+			"remainder to the validator", numeric.ZeroDec(), []numeric.Dec{half, half}, effective.Active, 5,
+			map[common.Address]int64{validator: 3, delegator: 2},
+		},
+		{"no reward", numeric.ZeroDec(), []numeric.Dec{half, half}, effective.Active, 0, map[common.Address]int64{}},
+		{"banned validator", numeric.ZeroDec(), []numeric.Dec{half, half}, effective.Banned, 1000, map[common.Address]int64{}},
+	} {
+		sdb, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+		wrapper := &staking.ValidatorWrapper{
+			Validator: staking.Validator{
+				Address:    validator,
+				Status:     test.status,
+				Commission: staking.Commission{CommissionRates: staking.CommissionRates{Rate: test.rate}},
+			},
+			Delegations: staking.Delegations{
+				staking.NewDelegation(validator, big.NewInt(1)),
+				staking.NewDelegation(delegator, big.NewInt(1)),
+			},
+			BlockReward: big.NewInt(0),
+		}
+		sdb.stateValidators[validator] = wrapper
+		shares := map[common.Address]numeric.Dec{validator: test.shares[0], delegator: test.shares[1]}
+
+		credited, err := sdb.AddReward(wrapper, big.NewInt(test.reward), shares)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(credited) != len(test.expected) {
+			t.Errorf("%s: expected %d delegators credited, got %v", test.name, len(test.expected), credited)
+		}
+		for i, delegation := range wrapper.Delegations {
+			expected := big.NewInt(test.expected[delegation.DelegatorAddress])
+			if amount := credited[delegation.DelegatorAddress]; len(test.expected) > 0 && amount.Cmp(expected) != 0 {
+				t.Errorf("%s: expected %v credited to delegation %d, got %v", test.name, expected, i, amount)
+			}
+			if delegation.Reward.Cmp(expected) != 0 {
+				t.Errorf("%s: expected a reward of %v of delegation %d, got %v", test.name, expected, i, delegation.Reward)
+			}
+		}
+	}
+}
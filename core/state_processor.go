@@ -69,6 +69,21 @@ func (p *StateProcessor) Process(
 ) (
 	types.Receipts, types.CXReceipts,
 	[]*types.Log, uint64, reward.Reader, error,
+) {
+	return p.process(block, statedb, cfg, nil)
+}
+
+// txObserver is called with the receipt of each transaction of a block once
+// applied, or with the error it failed with
+type txObserver func(index int, hash common.Hash, receipt *types.Receipt, err error)
+
+// process is Process, passing the outcome of the transactions to the observer
+// if any
+func (p *StateProcessor) process(
+	block *types.Block, statedb *state.DB, cfg vm.Config, observe txObserver,
+) (
+	types.Receipts, types.CXReceipts,
+	[]*types.Log, uint64, reward.Reader, error,
 ) {
 	var (
 		receipts types.Receipts
@@ -91,6 +106,9 @@ func (p *StateProcessor) Process(
 		receipt, cxReceipt, _, err := ApplyTransaction(
 			p.config, p.bc, &beneficiary, gp, statedb, header, tx, usedGas, cfg,
 		)
+		if observe != nil {
+			observe(i, tx.Hash(), receipt, err)
+		}
 		if err != nil {
 			return nil, nil, nil, 0, nil, err
 		}
@@ -107,6 +125,9 @@ func (p *StateProcessor) Process(
 		receipt, _, err := ApplyStakingTransaction(
 			p.config, p.bc, &beneficiary, gp, statedb, header, tx, usedGas, cfg,
 		)
+		if observe != nil {
+			observe(i+L, tx.Hash(), receipt, err)
+		}
 		if err != nil {
 			return nil, nil, nil, 0, nil, err
 		}
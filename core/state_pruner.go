@@ -0,0 +1,363 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+)
+
+const (
+	// pruneCheckInterval is the number of nodes or keys processed between two
+	// checks of the pause budget
+	pruneCheckInterval = 64
+	// DefaultStatePruneBudget is the default pause budget of a pruning step
+	DefaultStatePruneBudget = 50 * time.Millisecond
+)
+
+var (
+	pruneMarkedGauge    = metrics.NewRegisteredGauge("chain/prune/marked", nil)
+	pruneDeletedCounter = metrics.NewRegisteredCounter("chain/prune/deleted", nil)
+	pruneStepTimer      = metrics.NewRegisteredTimer("chain/prune/step", nil)
+
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+)
+
+// The phases of a pruning cycle
+const (
+	PruneIdle     = "idle"
+	PruneMarking  = "marking"
+	PruneSweeping = "sweeping"
+)
+
+// iterableDatabase is a database whose keys can be iterated, as leveldb's
+type iterableDatabase interface {
+	NewIterator() iterator.Iterator
+}
+
+// StatePruneProgress is the progress of the state pruning of a chain
+type StatePruneProgress struct {
+	Phase      string `json:"phase"`
+	Cycles     uint64 `json:"cycles"`      // cycles completed
+	CycleStart uint64 `json:"cycle-start"` // head block the current cycle started at
+	Retention  uint64 `json:"retention"`   // blocks whose state is kept
+	Marked     uint64 `json:"marked"`      // trie nodes and code marked reachable in the cycle
+	Swept      uint64 `json:"swept"`       // keys swept in the cycle
+	Deleted    uint64 `json:"deleted"`     // keys deleted in the cycle
+	Total      uint64 `json:"total"`       // keys deleted since the node started
+	LastStep   string `json:"last-step"`   // pause of the last step
+}
+
+// pruneRoot is a trie left to mark
+type pruneRoot struct {
+	root    common.Hash
+	account bool // whether it is an account trie, whose leaves hold storage tries
+}
+
+// statePruner deletes the trie nodes and code no longer reachable from the
+// state of the last retention blocks, of the genesis, or of the last block
+// committed to disk, which the chain restarts from after a crash. It works in
+// cycles: the nodes reachable from the kept states are marked, then the
+// database is swept of the nodes not marked. The cycle is run in small steps between block imports, each pausing
+// the imports for no longer than the budget. The states of the blocks imported
+// during a cycle are marked before the sweep resumes, so no node written after
+// the cycle started is deleted.
+type statePruner struct {
+	bc        *BlockChain
+	db        iterableDatabase
+	retention uint64
+	budget    time.Duration
+
+	lock      sync.Mutex
+	committed common.Hash // the state root last committed to disk
+	progress  StatePruneProgress
+	marked    map[common.Hash]struct{}
+	walk      []pruneRoot       // tries left to mark
+	it        trie.NodeIterator // the trie being marked
+	account   bool
+	sweepKey  []byte // the next key to sweep, nil at the start of the sweep
+}
+
+// EnableStatePruning prunes the state older than the given number of blocks,
+// in steps pausing the block imports for no longer than the budget. The state
+// of the blocks kept in memory is always retained. It must be called before
+// the chain is in use.
+func (bc *BlockChain) EnableStatePruning(retention uint64, budget time.Duration) error {
+	if bc.cacheConfig.Disabled {
+		return errors.New("cannot prune the state of an archival chain")
+	}
+	db, ok := bc.db.(iterableDatabase)
+	if !ok {
+		return errors.New("chain database cannot be iterated")
+	}
+	if retention < triesInMemory {
+		retention = triesInMemory
+	}
+	if budget <= 0 {
+		budget = DefaultStatePruneBudget
+	}
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+	bc.pruner = &statePruner{
+		bc:        bc,
+		db:        db,
+		retention: retention,
+		budget:    budget,
+		committed: bc.CurrentBlock().Root(),
+		progress:  StatePruneProgress{Phase: PruneIdle, Retention: retention},
+	}
+	utils.Logger().Info().
+		Uint64("retention", retention).
+		Dur("budget", budget).
+		Msg("[StatePruner] State pruning enabled")
+	return nil
+}
+
+// StatePruneProgress returns the progress of the state pruning, nil if the
+// state is not pruned
+func (bc *BlockChain) StatePruneProgress() *StatePruneProgress {
+	if bc.pruner == nil {
+		return nil
+	}
+	bc.pruner.lock.Lock()
+	defer bc.pruner.lock.Unlock()
+	progress := bc.pruner.progress
+	return &progress
+}
+
+// pruneState runs a step of the state pruning, if enabled
+func (bc *BlockChain) pruneState() {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+	if bc.pruner == nil || bc.bulkImport != nil {
+		return
+	}
+	if err := bc.pruner.step(); err != nil {
+		utils.Logger().Warn().Err(err).Msg("[StatePruner] Pruning step failed, restarting the cycle")
+		bc.pruner.reset()
+	}
+}
+
+// track queues the state root of a block written during a cycle for marking.
+// The caller must hold the chain insertion lock.
+func (p *statePruner) track(root common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.marked != nil {
+		p.walk = append(p.walk, pruneRoot{root, true})
+	}
+}
+
+// commit records the state root last committed to disk. The caller must hold
+// the chain insertion lock.
+func (p *statePruner) commit(root common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.committed = root
+	if p.marked != nil {
+		p.walk = append(p.walk, pruneRoot{root, true})
+	}
+}
+
+// step advances the cycle for no longer than the budget, starting a new cycle
+// once retention blocks were imported since the last one started
+func (p *statePruner) step() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	start := time.Now()
+	deadline := start.Add(p.budget)
+	defer func() {
+		pruneStepTimer.UpdateSince(start)
+		p.progress.LastStep = time.Since(start).String()
+	}()
+
+	head := p.bc.CurrentBlock().NumberU64()
+	if p.marked == nil {
+		if head < p.progress.CycleStart+p.retention || head < p.retention {
+			return nil
+		}
+		p.begin(head)
+	}
+	if p.progress.Phase == PruneMarking || len(p.walk) > 0 || p.it != nil {
+		done, err := p.mark(deadline)
+		if err != nil || !done {
+			return err
+		}
+		p.progress.Phase = PruneSweeping
+	}
+	done, err := p.sweep(deadline)
+	if err != nil || !done {
+		return err
+	}
+	utils.Logger().Info().
+		Uint64("cycleStart", p.progress.CycleStart).
+		Uint64("marked", p.progress.Marked).
+		Uint64("deleted", p.progress.Deleted).
+		Msg("[StatePruner] Pruning cycle completed")
+	p.progress.Cycles++
+	p.reset()
+	return nil
+}
+
+// begin starts a cycle keeping the state of the blocks from head-retention,
+// of the genesis and of the block last committed to disk
+func (p *statePruner) begin(head uint64) {
+	p.marked = map[common.Hash]struct{}{}
+	p.walk = []pruneRoot{
+		{p.bc.genesisBlock.Root(), true},
+		{p.committed, true},
+	}
+	from := uint64(0)
+	if head > p.retention {
+		from = head - p.retention
+	}
+	for num := from; num <= head; num++ {
+		if header := p.bc.GetHeaderByNumber(num); header != nil {
+			p.walk = append(p.walk, pruneRoot{header.Root(), true})
+		}
+	}
+	p.progress.Phase = PruneMarking
+	p.progress.CycleStart = head
+	p.progress.Marked, p.progress.Swept, p.progress.Deleted = 0, 0, 0
+	utils.Logger().Info().
+		Uint64("head", head).
+		Uint64("retention", p.retention).
+		Msg("[StatePruner] Pruning cycle started")
+}
+
+// reset drops the state of the current cycle
+func (p *statePruner) reset() {
+	p.marked, p.walk, p.it, p.sweepKey = nil, nil, nil, nil
+	p.progress.Phase = PruneIdle
+	pruneMarkedGauge.Update(0)
+}
+
+// mark walks the tries left to mark until done or past the deadline. The walk
+// does not descend into the nodes already marked, which are shared with the
+// tries already walked.
+func (p *statePruner) mark(deadline time.Time) (bool, error) {
+	defer func() { pruneMarkedGauge.Update(int64(len(p.marked))) }()
+	stateDB := p.bc.stateCache
+	for n := 0; ; {
+		if p.it == nil {
+			if len(p.walk) == 0 {
+				return true, nil
+			}
+			next := p.walk[len(p.walk)-1]
+			p.walk = p.walk[:len(p.walk)-1]
+			if _, ok := p.marked[next.root]; ok || next.root == types.EmptyRootHash {
+				continue
+			}
+			var (
+				t   state.Trie
+				err error
+			)
+			if next.account {
+				t, err = stateDB.OpenTrie(next.root)
+			} else {
+				t, err = stateDB.OpenStorageTrie(common.Hash{}, next.root)
+			}
+			if err != nil {
+				// Not on disk nor in memory, nothing to keep
+				continue
+			}
+			p.it, p.account = t.NodeIterator(nil), next.account
+		}
+		descend := true
+		for ; ; n++ {
+			if n%pruneCheckInterval == 0 && time.Now().After(deadline) {
+				return false, nil
+			}
+			if !p.it.Next(descend) {
+				break
+			}
+			descend = true
+			if h := p.it.Hash(); h != (common.Hash{}) {
+				if _, ok := p.marked[h]; ok {
+					descend = false
+					continue
+				}
+				p.marked[h] = struct{}{}
+				p.progress.Marked++
+			}
+			if p.account && p.it.Leaf() {
+				var account state.Account
+				if err := rlp.DecodeBytes(p.it.LeafBlob(), &account); err != nil {
+					return false, errors.Wrap(err, "cannot decode account")
+				}
+				if account.Root != types.EmptyRootHash {
+					p.walk = append(p.walk, pruneRoot{account.Root, false})
+				}
+				if code := common.BytesToHash(account.CodeHash); code != emptyCodeHash {
+					p.marked[code] = struct{}{}
+					p.progress.Marked++
+				}
+			}
+		}
+		err := p.it.Error()
+		p.it = nil
+		if err != nil {
+			return false, errors.Wrap(err, "cannot walk state trie")
+		}
+	}
+}
+
+// sweep deletes the trie nodes and code not marked until done or past the
+// deadline. Trie nodes and code are the only entries keyed by a bare hash.
+func (p *statePruner) sweep(deadline time.Time) (bool, error) {
+	it := p.db.NewIterator()
+	defer it.Release()
+	batch := p.bc.db.NewBatch()
+	flush := func() error {
+		if batch.ValueSize() == 0 {
+			return nil
+		}
+		err := batch.Write()
+		batch.Reset()
+		return err
+	}
+
+	ok := it.First()
+	if p.sweepKey != nil {
+		ok = it.Seek(p.sweepKey)
+	}
+	for n := 0; ok; n, ok = n+1, it.Next() {
+		if n%pruneCheckInterval == 0 && time.Now().After(deadline) {
+			p.sweepKey = common.CopyBytes(it.Key())
+			return false, flush()
+		}
+		key := it.Key()
+		p.progress.Swept++
+		if len(key) != common.HashLength {
+			continue
+		}
+		if _, marked := p.marked[common.BytesToHash(key)]; marked {
+			continue
+		}
+		if err := batch.Delete(common.CopyBytes(key)); err != nil {
+			return false, err
+		}
+		p.progress.Deleted++
+		p.progress.Total++
+		pruneDeletedCounter.Inc(1)
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := flush(); err != nil {
+				return false, err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return false, err
+	}
+	return true, flush()
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	chain2 "github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+// commitTestState commits to disk the state of the parent root with the
+// balance of the account raised
+func commitTestState(t *testing.T, bc *BlockChain, parent common.Hash, addr common.Address, amount int64) common.Hash {
+	statedb, err := state.New(parent, bc.stateCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb.AddBalance(addr, big.NewInt(amount))
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.stateCache.TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// pruneCycle runs a whole pruning cycle from the current head
+func pruneCycle(t *testing.T, p *statePruner) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.begin(p.bc.CurrentBlock().NumberU64())
+	forever := time.Now().Add(time.Hour)
+	if done, err := p.mark(forever); err != nil || !done {
+		t.Fatalf("marking not done: %v", err)
+	}
+	if done, err := p.sweep(forever); err != nil || !done {
+		t.Fatalf("sweep not done: %v", err)
+	}
+	p.reset()
+}
+
+// diskBalance reads the balance of the account in the state of the root from
+// the disk, or returns nil if the state was pruned
+func diskBalance(db ethdb.Database, root common.Hash, addr common.Address) *big.Int {
+	statedb, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		return nil
+	}
+	return statedb.GetBalance(addr)
+}
+
+func TestStatePrunerSweep(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statepruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := ethdb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	gspec := Genesis{
+		Config:  params.TestChainConfig,
+		Factory: blockfactory.ForTest,
+		Alloc:   GenesisAlloc{addr: {Balance: big.NewInt(1000)}},
+		ShardID: 0,
+	}
+	genesis := gspec.MustCommit(db)
+	bc, err := NewBlockChain(db, nil, gspec.Config, chain2.Engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.EnableStatePruning(0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// a state no block refers to anymore, and the state of a block committed
+	// to disk past the retention
+	stale := commitTestState(t, bc, genesis.Root(), addr, 1)
+	committed := commitTestState(t, bc, genesis.Root(), addr, 2)
+	bc.pruner.commit(committed)
+
+	pruneCycle(t, bc.pruner)
+	if balance := diskBalance(db, stale, addr); balance != nil {
+		t.Error("expected the stale state pruned")
+	}
+	if balance := diskBalance(db, genesis.Root(), addr); balance == nil || balance.Int64() != 1000 {
+		t.Errorf("expected the genesis state kept, got balance %v", balance)
+	}
+	if balance := diskBalance(db, committed, addr); balance == nil || balance.Int64() != 1002 {
+		t.Errorf("expected the committed state kept, got balance %v", balance)
+	}
+	if progress := bc.StatePruneProgress(); progress.Deleted == 0 || progress.Phase != PruneIdle {
+		t.Errorf("unexpected progress %+v", progress)
+	}
+	bc.Stop()
+
+	// the node crashed once the block of the committed state and a block whose
+	// state was still in memory were imported
+	blocks := types.Blocks{genesis}
+	for i, root := range []common.Hash{committed, common.HexToHash("0x2")} {
+		parent := blocks[i]
+		header := blockfactory.ForTest.NewHeader(parent.Epoch()).With().
+			Number(big.NewInt(int64(i + 1))).
+			ParentHash(parent.Hash()).
+			Root(root).
+			Header()
+		block := types.NewBlockWithHeader(header)
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteTd(db, block.Hash(), block.NumberU64(), big.NewInt(int64(i+2)))
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		blocks = append(blocks, block)
+	}
+	head := blocks[2].Hash()
+	rawdb.WriteHeadBlockHash(db, head)
+	rawdb.WriteHeadHeaderHash(db, head)
+	rawdb.WriteHeadFastBlockHash(db, head)
+
+	restarted, err := NewBlockChain(db, nil, gspec.Config, chain2.Engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("cannot restart after pruning: %v", err)
+	}
+	defer restarted.Stop()
+	if current := restarted.CurrentBlock(); current.Hash() != blocks[1].Hash() {
+		t.Fatalf("expected the chain restarted from block 1, got %d", current.NumberU64())
+	}
+	statedb, err := restarted.State()
+	if err != nil {
+		t.Fatalf("head state missing after restart: %v", err)
+	}
+	if balance := statedb.GetBalance(addr); balance.Int64() != 1002 {
+		t.Errorf("unexpected balance %v after restart", balance)
+	}
+}
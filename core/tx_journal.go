@@ -108,7 +108,7 @@ func (journal *txJournal) load(add func(types.PoolTransactions) []error) error {
 	loadBatch := func(txs types.PoolTransactions) {
 		for _, err := range add(txs) {
 			if err != nil {
-				utils.Logger().Error().Err(err).Msg("Failed to add journaled transaction")
+				utils.ModuleLogger(utils.ModuleTxPool).Error().Err(err).Msg("Failed to add journaled transaction")
 				dropped++
 			}
 		}
@@ -126,7 +126,7 @@ func (journal *txJournal) load(add func(types.PoolTransactions) []error) error {
 				if err == io.EOF { // reached end of journal file, exit with no error after loading batch
 					err = nil
 				} else {
-					utils.Logger().Info().
+					utils.ModuleLogger(utils.ModuleTxPool).Info().
 						Int("transactions", total).
 						Int("dropped", dropped).
 						Msg("Loaded local transaction journal")
@@ -140,7 +140,7 @@ func (journal *txJournal) load(add func(types.PoolTransactions) []error) error {
 
 		if err = stream.Decode(tx); err != nil {
 			// should never hit EOF here with the leading ID journal tx encoding scheme.
-			utils.Logger().Info().
+			utils.ModuleLogger(utils.ModuleTxPool).Info().
 				Int("transactions", total).
 				Int("dropped", dropped).
 				Msg("Loaded local transaction journal")
@@ -203,7 +203,7 @@ func (journal *txJournal) rotate(all map[common.Address]types.PoolTransactions)
 		return err
 	}
 	journal.writer = sink
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleTxPool).Info().
 		Int("transactions", journaled).
 		Int("accounts", len(all)).
 		Msg("Regenerated local transaction journal")
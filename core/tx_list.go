@@ -496,7 +496,7 @@ func (l *txPricedList) Underpriced(tx types.PoolTransaction, local *accountSet)
 	}
 	// Check if the transaction is underpriced or not
 	if len(*l.items) == 0 {
-		utils.Logger().Error().Msg("Pricing query for empty pool") // This cannot happen, print to catch programming errors
+		utils.ModuleLogger(utils.ModuleTxPool).Error().Msg("Pricing query for empty pool") // This cannot happen, print to catch programming errors
 		return false
 	}
 	cheapest := types.PoolTransactions(*l.items)[0]
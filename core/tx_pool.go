@@ -98,6 +98,14 @@ var (
 
 	// ErrBlacklistTo is returned if a transaction's to/destination address is blacklisted
 	ErrBlacklistTo = errors.New("`to` address of transaction in blacklist")
+
+	// ErrNonceGapExpired is returned if a queued transaction's nonce gap was not
+	// filled within the configured number of epochs
+	ErrNonceGapExpired = errors.New("nonce gap not filled in time, transaction dropped")
+
+	// ErrNonceGapEvicted is returned if a queued transaction beyond a nonce gap
+	// was evicted on request
+	ErrNonceGapEvicted = errors.New("evicted beyond a nonce gap, transaction dropped")
 )
 
 var (
@@ -117,6 +125,7 @@ var (
 	queuedReplaceCounter   = metrics.NewRegisteredCounter("txpool/queued/replace", nil)
 	queuedRateLimitCounter = metrics.NewRegisteredCounter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsCounter   = metrics.NewRegisteredCounter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
+	queuedNonceGapCounter  = metrics.NewRegisteredCounter("txpool/queued/noncegap", nil)  // Dropped due to unfilled nonce gap
 
 	// General tx metrics
 	invalidTxCounter     = metrics.NewRegisteredCounter("txpool/invalid", nil)
@@ -159,7 +168,8 @@ type TxPoolConfig struct {
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
-	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+	Lifetime       time.Duration // Maximum amount of time non-executable transaction are queued
+	NonceGapEpochs uint64        // Number of epochs a nonce gap may stay unfilled before its queued transactions are dropped (0 = disabled)
 
 	Blacklist map[common.Address]struct{} // Set of accounts that cannot be a part of any transaction
 }
@@ -178,7 +188,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue: 64,
 	GlobalQueue:  1024,
 
-	Lifetime: 30 * time.Minute,
+	Lifetime:       30 * time.Minute,
+	NonceGapEpochs: 2,
 
 	Blacklist: map[common.Address]struct{}{},
 }
@@ -188,28 +199,28 @@ var DefaultTxPoolConfig = TxPoolConfig{
 func (config *TxPoolConfig) sanitize() TxPoolConfig {
 	conf := *config
 	if conf.Rejournal < time.Second {
-		utils.Logger().Warn().
+		utils.ModuleLogger(utils.ModuleTxPool).Warn().
 			Dur("provided", conf.Rejournal).
 			Dur("updated", time.Second).
 			Msg("Sanitizing invalid txpool journal time")
 		conf.Rejournal = time.Second
 	}
 	if conf.PriceLimit < 1 {
-		utils.Logger().Warn().
+		utils.ModuleLogger(utils.ModuleTxPool).Warn().
 			Uint64("provided", conf.PriceLimit).
 			Uint64("updated", DefaultTxPoolConfig.PriceLimit).
 			Msg("Sanitizing invalid txpool price limit")
 		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
 	}
 	if conf.PriceBump < 1 {
-		utils.Logger().Warn().
+		utils.ModuleLogger(utils.ModuleTxPool).Warn().
 			Uint64("provided", conf.PriceBump).
 			Uint64("updated", DefaultTxPoolConfig.PriceBump).
 			Msg("Sanitizing invalid txpool price bump")
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
 	if conf.Blacklist == nil {
-		utils.Logger().Warn().Msg("Sanitizing nil blacklist set")
+		utils.ModuleLogger(utils.ModuleTxPool).Warn().Msg("Sanitizing nil blacklist set")
 		conf.Blacklist = DefaultTxPoolConfig.Blacklist
 	}
 
@@ -229,6 +240,8 @@ type TxPool struct {
 	chain        blockChain
 	gasPrice     *big.Int
 	txFeed       event.Feed
+	dropFeed     event.Feed
+	pendingFeed  event.Feed
 	scope        event.SubscriptionScope
 	chainHeadCh  chan ChainHeadEvent
 	chainHeadSub event.Subscription
@@ -248,6 +261,12 @@ type TxPool struct {
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
 
+	pendingChanges pendingChanges     // Changes to the pending transactions under the lock
+	pendingEvents  *pendingEventQueue // Pending events waiting for the subscribers
+
+	queuedEpochs map[common.Hash]uint64 // Epoch at which each queued transaction entered the queue
+	reapedEpoch  uint64                 // Last epoch the nonce gap reaper ran at
+
 	wg sync.WaitGroup // for shutdown sync
 
 	txErrorSink *types.TransactionErrorSink // All failed txs gets reported here
@@ -265,21 +284,24 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig,
 
 	// Create the transaction pool with its initial settings
 	pool := &TxPool{
-		config:      config,
-		chainconfig: chainconfig,
-		chain:       chain,
-		signer:      types.NewEIP155Signer(chainconfig.ChainID),
-		pending:     make(map[common.Address]*txList),
-		queue:       make(map[common.Address]*txList),
-		beats:       make(map[common.Address]time.Time),
-		all:         newTxLookup(),
-		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
-		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
-		txErrorSink: txErrorSink,
+		config:        config,
+		chainconfig:   chainconfig,
+		chain:         chain,
+		signer:        types.NewEIP155Signer(chainconfig.ChainID),
+		pending:       make(map[common.Address]*txList),
+		queue:         make(map[common.Address]*txList),
+		beats:         make(map[common.Address]time.Time),
+		all:           newTxLookup(),
+		queuedEpochs:  make(map[common.Hash]uint64),
+		reapedEpoch:   chain.CurrentBlock().Epoch().Uint64(),
+		chainHeadCh:   make(chan ChainHeadEvent, chainHeadChanSize),
+		gasPrice:      new(big.Int).SetUint64(config.PriceLimit),
+		txErrorSink:   txErrorSink,
+		pendingEvents: newPendingEventQueue(),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
-		utils.Logger().Info().Interface("address", addr).Msg("Setting new local account")
+		utils.ModuleLogger(utils.ModuleTxPool).Info().Interface("address", addr).Msg("Setting new local account")
 		pool.locals.add(addr)
 	}
 	pool.priced = newTxPricedList(pool.all)
@@ -290,18 +312,19 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig,
 		pool.journal = newTxJournal(config.Journal)
 
 		if err := pool.journal.load(pool.AddLocals); err != nil {
-			utils.Logger().Warn().Err(err).Msg("Failed to load transaction journal")
+			utils.ModuleLogger(utils.ModuleTxPool).Warn().Err(err).Msg("Failed to load transaction journal")
 		}
 		if err := pool.journal.rotate(pool.local()); err != nil {
-			utils.Logger().Warn().Err(err).Msg("Failed to rotate transaction journal")
+			utils.ModuleLogger(utils.ModuleTxPool).Warn().Err(err).Msg("Failed to rotate transaction journal")
 		}
 	}
 	// Subscribe events from blockchain
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
 
-	// Start the event loop and return
-	pool.wg.Add(1)
+	// Start the event loops and return
+	pool.wg.Add(2)
 	go pool.loop()
+	go pool.pendingEventLoop()
 
 	return pool
 }
@@ -339,7 +362,10 @@ func (pool *TxPool) loop() {
 				}
 				pool.reset(head.Header(), ev.Block.Header())
 				head = ev.Block
-				pool.mu.Unlock()
+				if epoch := ev.Block.Epoch().Uint64(); epoch > pool.reapedEpoch {
+					pool.reapNonceGaps(epoch)
+				}
+				pool.unlock()
 			}
 		// Be unsubscribed due to system stopped
 		case <-pool.chainHeadSub.Err():
@@ -353,7 +379,7 @@ func (pool *TxPool) loop() {
 			pool.mu.RUnlock()
 
 			if pending != prevPending || queued != prevQueued || stales != prevStales {
-				utils.Logger().Debug().
+				utils.ModuleLogger(utils.ModuleTxPool).Debug().
 					Int("executable", pending).
 					Int("queued", queued).
 					Int("stales", stales).
@@ -376,14 +402,14 @@ func (pool *TxPool) loop() {
 					}
 				}
 			}
-			pool.mu.Unlock()
+			pool.unlock()
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
 			if pool.journal != nil {
 				pool.mu.Lock()
 				if err := pool.journal.rotate(pool.local()); err != nil {
-					utils.Logger().Warn().Err(err).Msg("Failed to rotate local tx journal")
+					utils.ModuleLogger(utils.ModuleTxPool).Warn().Err(err).Msg("Failed to rotate local tx journal")
 				}
 				pool.mu.Unlock()
 			}
@@ -395,7 +421,7 @@ func (pool *TxPool) loop() {
 // manner. This method is only ever used in the tester!
 func (pool *TxPool) lockedReset(oldHead, newHead *block.Header) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
+	defer pool.unlock()
 
 	pool.reset(oldHead, newHead)
 }
@@ -412,7 +438,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 		newNum := newHead.Number().Uint64()
 
 		if depth := uint64(math.Abs(float64(oldNum) - float64(newNum))); depth > 64 {
-			utils.Logger().Debug().Uint64("depth", depth).Msg("Skipping deep transaction reorg")
+			utils.ModuleLogger(utils.ModuleTxPool).Debug().Uint64("depth", depth).Msg("Skipping deep transaction reorg")
 		} else {
 			// Reorg seems shallow enough to pull in all transactions into memory
 			var discarded, included types.PoolTransactions
@@ -429,7 +455,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 					discarded = append(discarded, tx)
 				}
 				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
-					utils.Logger().Error().
+					utils.ModuleLogger(utils.ModuleTxPool).Error().
 						Str("block", oldHead.Number().String()).
 						Str("hash", oldHead.Hash().Hex()).
 						Msg("Unrooted old chain seen by tx pool")
@@ -444,7 +470,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 					included = append(included, tx)
 				}
 				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
-					utils.Logger().Error().
+					utils.ModuleLogger(utils.ModuleTxPool).Error().
 						Str("block", newHead.Number().String()).
 						Str("hash", newHead.Hash().Hex()).
 						Msg("Unrooted new chain seen by tx pool")
@@ -459,7 +485,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 					discarded = append(discarded, tx)
 				}
 				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
-					utils.Logger().Error().
+					utils.ModuleLogger(utils.ModuleTxPool).Error().
 						Str("block", oldHead.Number().String()).
 						Str("hash", oldHead.Hash().Hex()).
 						Msg("Unrooted old chain seen by tx pool")
@@ -472,7 +498,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 					included = append(included, tx)
 				}
 				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
-					utils.Logger().Error().
+					utils.ModuleLogger(utils.ModuleTxPool).Error().
 						Str("block", newHead.Number().String()).
 						Str("hash", newHead.Hash().Hex()).
 						Msg("Unrooted new chain seen by tx pool")
@@ -488,7 +514,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 	}
 	statedb, err := pool.chain.StateAt(newHead.Root())
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("Failed to reset txpool state")
+		utils.ModuleLogger(utils.ModuleTxPool).Error().Err(err).Msg("Failed to reset txpool state")
 		return
 	}
 	pool.currentState = statedb
@@ -496,7 +522,7 @@ func (pool *TxPool) reset(oldHead, newHead *block.Header) {
 	pool.currentMaxGas = newHead.GasLimit()
 
 	// Inject any transactions discarded due to reorgs
-	utils.Logger().Debug().Int("count", len(reinject)).Msg("Reinjecting stale transactions")
+	utils.ModuleLogger(utils.ModuleTxPool).Debug().Int("count", len(reinject)).Msg("Reinjecting stale transactions")
 	//senderCacher.recover(pool.signer, reinject)
 	pool.addTxsLocked(reinject, false)
 
@@ -533,7 +559,7 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
-	utils.Logger().Info().Msg("Transaction pool stopped")
+	utils.ModuleLogger(utils.ModuleTxPool).Info().Msg("Transaction pool stopped")
 }
 
 // SubscribeNewTxsEvent registers a subscription of NewTxsEvent and
@@ -542,6 +568,12 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeDroppedTxsEvent registers a subscription of DroppedTxsEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDroppedTxsEvent(ch chan<- DroppedTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -554,13 +586,100 @@ func (pool *TxPool) GasPrice() *big.Int {
 // new transaction, and drops all transactions below this threshold.
 func (pool *TxPool) SetGasPrice(price *big.Int) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
+	defer pool.unlock()
 
 	pool.gasPrice = price
 	for _, tx := range pool.priced.Cap(price, pool.locals) {
 		pool.removeTx(tx.Hash(), false)
 	}
-	utils.Logger().Info().Str("price", price.String()).Msg("Transaction pool price threshold updated")
+	utils.ModuleLogger(utils.ModuleTxPool).Info().Str("price", price.String()).Msg("Transaction pool price threshold updated")
+}
+
+// TxPoolLimits are the limits of the transaction pool adjustable at runtime.
+type TxPoolLimits struct {
+	PriceBump    uint64        // Minimum price bump percentage to replace an already existing transaction (nonce)
+	AccountSlots uint64        // Number of executable transaction slots guaranteed per account
+	GlobalSlots  uint64        // Maximum number of executable transaction slots for all accounts
+	AccountQueue uint64        // Maximum number of non-executable transaction slots permitted per account
+	GlobalQueue  uint64        // Maximum number of non-executable transaction slots for all accounts
+	Lifetime     time.Duration // Maximum amount of time non-executable transaction are queued
+}
+
+// Bounds of the transaction pool limits
+const (
+	maxTxPoolPriceBump = 100
+	maxTxPoolSlots     = 65536
+	minTxPoolLifetime  = time.Minute
+	maxTxPoolLifetime  = 24 * time.Hour
+)
+
+// clamp returns the limits brought within their bounds. The per account
+// limits cannot exceed the global ones.
+func (limits TxPoolLimits) clamp() TxPoolLimits {
+	clamp := func(value, min, max uint64) uint64 {
+		if value < min {
+			return min
+		}
+		if value > max {
+			return max
+		}
+		return value
+	}
+	limits.PriceBump = clamp(limits.PriceBump, 1, maxTxPoolPriceBump)
+	limits.GlobalSlots = clamp(limits.GlobalSlots, 1, maxTxPoolSlots)
+	limits.AccountSlots = clamp(limits.AccountSlots, 1, limits.GlobalSlots)
+	limits.GlobalQueue = clamp(limits.GlobalQueue, 1, maxTxPoolSlots)
+	limits.AccountQueue = clamp(limits.AccountQueue, 1, limits.GlobalQueue)
+	if limits.Lifetime < minTxPoolLifetime {
+		limits.Lifetime = minTxPoolLifetime
+	}
+	if limits.Lifetime > maxTxPoolLifetime {
+		limits.Lifetime = maxTxPoolLifetime
+	}
+	return limits
+}
+
+// Limits returns the current limits of the transaction pool.
+func (pool *TxPool) Limits() TxPoolLimits {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return TxPoolLimits{
+		PriceBump:    pool.config.PriceBump,
+		AccountSlots: pool.config.AccountSlots,
+		GlobalSlots:  pool.config.GlobalSlots,
+		AccountQueue: pool.config.AccountQueue,
+		GlobalQueue:  pool.config.GlobalQueue,
+		Lifetime:     pool.config.Lifetime,
+	}
+}
+
+// SetLimits updates the limits of the transaction pool, clamped within their
+// bounds, and drops the transactions beyond the new limits. It returns the
+// limits in effect.
+func (pool *TxPool) SetLimits(limits TxPoolLimits) TxPoolLimits {
+	limits = limits.clamp()
+
+	pool.mu.Lock()
+	defer pool.unlock()
+
+	pool.config.PriceBump = limits.PriceBump
+	pool.config.AccountSlots = limits.AccountSlots
+	pool.config.GlobalSlots = limits.GlobalSlots
+	pool.config.AccountQueue = limits.AccountQueue
+	pool.config.GlobalQueue = limits.GlobalQueue
+	pool.config.Lifetime = limits.Lifetime
+	pool.promoteExecutables(nil)
+
+	utils.ModuleLogger(utils.ModuleTxPool).Info().
+		Uint64("priceBump", limits.PriceBump).
+		Uint64("accountSlots", limits.AccountSlots).
+		Uint64("globalSlots", limits.GlobalSlots).
+		Uint64("accountQueue", limits.AccountQueue).
+		Uint64("globalQueue", limits.GlobalQueue).
+		Dur("lifetime", limits.Lifetime).
+		Msg("Transaction pool limits updated")
+	return limits
 }
 
 // State returns the virtual managed state of the transaction pool.
@@ -594,6 +713,11 @@ func (pool *TxPool) stats() (int, int) {
 	return pending, queued
 }
 
+// Config returns the configuration of the transaction pool
+func (pool *TxPool) Config() TxPoolConfig {
+	return pool.config
+}
+
 // Content retrieves the data content of the transaction pool, returning all the
 // pending as well as queued transactions, grouped by account and sorted by nonce.
 func (pool *TxPool) Content() (map[common.Address]types.PoolTransactions, map[common.Address]types.PoolTransactions) {
@@ -862,7 +986,7 @@ func (pool *TxPool) validateStakingTx(tx *staking.StakingTransaction) error {
 // whitelisted, preventing any associated transaction from being dropped out of
 // the pool due to pricing constraints.
 func (pool *TxPool) add(tx types.PoolTransaction, local bool) (bool, error) {
-	logger := utils.Logger().With().Stack().Logger()
+	logger := utils.ModuleLogger(utils.ModuleTxPool).With().Stack().Logger()
 	// If the transaction is in the error sink, remove it as it may succeed
 	if pool.txErrorSink.Contains(tx.Hash().String()) {
 		pool.txErrorSink.Remove(tx)
@@ -916,10 +1040,12 @@ func (pool *TxPool) add(tx types.PoolTransaction, local bool) (bool, error) {
 		if old != nil {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed()
+			pool.pendingRemoved(old)
 			pendingReplaceCounter.Inc(1)
 		}
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
+		pool.pendingAdded(tx)
 		pool.journalTx(from, tx)
 
 		logger.Warn().
@@ -941,7 +1067,7 @@ func (pool *TxPool) add(tx types.PoolTransaction, local bool) (bool, error) {
 	// Mark local addresses and journal local transactions
 	if local {
 		if !pool.locals.contains(from) {
-			utils.Logger().Info().Interface("address", from).Msg("Setting new local account")
+			utils.ModuleLogger(utils.ModuleTxPool).Info().Interface("address", from).Msg("Setting new local account")
 			pool.locals.add(from)
 		}
 	}
@@ -980,9 +1106,144 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx types.PoolTransaction) (bool,
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
 	}
+	if _, ok := pool.queuedEpochs[hash]; !ok {
+		pool.queuedEpochs[hash] = pool.reapedEpoch
+	}
 	return old != nil, nil
 }
 
+// reapNonceGaps drops the non-local queued transactions whose nonce gap has not
+// been filled within NonceGapEpochs epochs, and notifies the dropped transaction
+// subscribers.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) reapNonceGaps(epoch uint64) {
+	pool.reapedEpoch = epoch
+	if pool.config.NonceGapEpochs == 0 {
+		return
+	}
+	queuedEpochs := make(map[common.Hash]uint64, len(pool.queuedEpochs))
+	dropped := types.PoolTransactions{}
+	for addr, list := range pool.queue {
+		for _, tx := range list.Flatten() {
+			hash := tx.Hash()
+			since, ok := pool.queuedEpochs[hash]
+			if !ok {
+				since = epoch
+			}
+			if pool.locals.contains(addr) || since+pool.config.NonceGapEpochs > epoch {
+				queuedEpochs[hash] = since
+				continue
+			}
+			dropped = append(dropped, tx)
+		}
+	}
+	// Forget the transactions which left the queue since the last run
+	pool.queuedEpochs = queuedEpochs
+	for _, tx := range dropped {
+		pool.removeTx(tx.Hash(), true)
+		pool.txErrorSink.Add(tx, ErrNonceGapExpired)
+	}
+	if len(dropped) > 0 {
+		queuedNonceGapCounter.Inc(int64(len(dropped)))
+		utils.ModuleLogger(utils.ModuleTxPool).Info().
+			Uint64("epoch", epoch).
+			Int("dropped", len(dropped)).
+			Msg("Dropped queued transactions with unfilled nonce gaps")
+		go pool.dropFeed.Send(DroppedTxsEvent{Txs: dropped, Reason: ErrNonceGapExpired})
+	}
+}
+
+// NonceRange is a range of nonces, From and To included.
+type NonceRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// NonceGapReport lists the nonces of the transactions of an account in the
+// pool, and the gaps which keep its queued transactions from being executed.
+type NonceGapReport struct {
+	StateNonce    uint64       `json:"state-nonce"`
+	PendingNonces []uint64     `json:"pending-nonces"`
+	QueuedNonces  []uint64     `json:"queued-nonces"`
+	Gaps          []NonceRange `json:"gaps"`
+}
+
+// NonceGaps reports the pending and queued nonces of the given account, and
+// the gaps between them.
+func (pool *TxPool) NonceGaps(addr common.Address) NonceGapReport {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.nonceGaps(addr)
+}
+
+// nonceGaps reports the nonces of the given account.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) nonceGaps(addr common.Address) NonceGapReport {
+	report := NonceGapReport{
+		StateNonce:    pool.currentState.GetNonce(addr),
+		PendingNonces: []uint64{},
+		QueuedNonces:  []uint64{},
+		Gaps:          []NonceRange{},
+	}
+	next := report.StateNonce
+	if list := pool.pending[addr]; list != nil {
+		for _, tx := range list.Flatten() {
+			report.PendingNonces = append(report.PendingNonces, tx.Nonce())
+			next = tx.Nonce() + 1
+		}
+	}
+	if list := pool.queue[addr]; list != nil {
+		for _, tx := range list.Flatten() {
+			nonce := tx.Nonce()
+			report.QueuedNonces = append(report.QueuedNonces, nonce)
+			if nonce > next {
+				report.Gaps = append(report.Gaps, NonceRange{From: next, To: nonce - 1})
+			}
+			if nonce >= next {
+				next = nonce + 1
+			}
+		}
+	}
+	return report
+}
+
+// EvictNonceGap drops the queued transactions of the given account beyond its
+// first nonce gap, so that the account can submit them again in order, and
+// notifies the dropped transaction subscribers. It returns the dropped
+// transactions.
+func (pool *TxPool) EvictNonceGap(addr common.Address) types.PoolTransactions {
+	pool.mu.Lock()
+	defer pool.unlock()
+
+	report := pool.nonceGaps(addr)
+	dropped := types.PoolTransactions{}
+	if len(report.Gaps) == 0 {
+		return dropped
+	}
+	gap := report.Gaps[0].From
+	for _, tx := range pool.queue[addr].Flatten() {
+		if tx.Nonce() > gap {
+			dropped = append(dropped, tx)
+		}
+	}
+	for _, tx := range dropped {
+		pool.removeTx(tx.Hash(), true)
+		pool.txErrorSink.Add(tx, ErrNonceGapEvicted)
+	}
+	if len(dropped) > 0 {
+		utils.ModuleLogger(utils.ModuleTxPool).Info().
+			Str("account", addr.Hex()).
+			Uint64("gap", gap).
+			Int("dropped", len(dropped)).
+			Msg("Evicted queued transactions beyond nonce gap")
+		go pool.dropFeed.Send(DroppedTxsEvent{Txs: dropped, Reason: ErrNonceGapEvicted})
+	}
+	return dropped
+}
+
 // journalTx adds the specified transaction to the local disk journal if it is
 // deemed to have been sent from a local account.
 func (pool *TxPool) journalTx(from common.Address, tx types.PoolTransaction) {
@@ -991,7 +1252,7 @@ func (pool *TxPool) journalTx(from common.Address, tx types.PoolTransaction) {
 		return
 	}
 	if err := pool.journal.insert(tx); err != nil {
-		utils.Logger().Warn().Err(err).Msg("Failed to journal local transaction")
+		utils.ModuleLogger(utils.ModuleTxPool).Warn().Err(err).Msg("Failed to journal local transaction")
 	}
 }
 
@@ -1019,9 +1280,11 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx types.Po
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed()
+		pool.pendingRemoved(old)
 
 		pendingReplaceCounter.Inc(1)
 	}
+	pool.pendingAdded(tx)
 	// Failsafe to work around direct pending inserts (tests)
 	if pool.all.Get(hash) == nil {
 		pool.all.Add(tx)
@@ -1065,7 +1328,7 @@ func (pool *TxPool) AddRemotes(txs types.PoolTransactions) []error {
 // addTx enqueues a single transaction into the pool if it is valid.
 func (pool *TxPool) addTx(tx types.PoolTransaction, local bool) error {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
+	defer pool.unlock()
 
 	// Try to inject the transaction and update any state
 	replace, err := pool.add(tx, local)
@@ -1087,7 +1350,7 @@ func (pool *TxPool) addTx(tx types.PoolTransaction, local bool) error {
 // addTxs attempts to queue a batch of transactions if they are valid.
 func (pool *TxPool) addTxs(txs types.PoolTransactions, local bool) []error {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
+	defer pool.unlock()
 
 	return pool.addTxsLocked(txs, local)
 }
@@ -1168,6 +1431,8 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 	// Remove the transaction from the pending lists and reset the account nonce
 	if pending := pool.pending[addr]; pending != nil {
 		if removed, invalids := pending.Remove(tx); removed {
+			pool.pendingRemoved(tx)
+			pool.pendingRemoved(invalids...)
 			// If no more pending transactions are left, remove the list
 			if pending.Empty() {
 				delete(pool.pending, addr)
@@ -1201,7 +1466,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 	// Track the promoted transactions to broadcast them at once
 	var promoted types.PoolTransactions
-	logger := utils.Logger().With().Stack().Logger()
+	logger := utils.ModuleLogger(utils.ModuleTxPool).With().Stack().Logger()
 
 	// Gather all the accounts potentially needing updates
 	if accounts == nil {
@@ -1300,6 +1565,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 							pool.txErrorSink.Add(tx, fmt.Errorf("fairness-exceeding pending transaction"))
 							pool.all.Remove(hash)
 							pool.priced.Removed()
+							pool.pendingRemoved(tx)
 
 							// Update the account nonce to the dropped transaction
 							if nonce := tx.Nonce(); pool.pendingState.GetNonce(offenders[i]) > nonce {
@@ -1323,6 +1589,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 						pool.txErrorSink.Add(tx, fmt.Errorf("fairness-exceeding pending transaction"))
 						pool.all.Remove(hash)
 						pool.priced.Removed()
+						pool.pendingRemoved(tx)
 
 						// Update the account nonce to the dropped transaction
 						if nonce := tx.Nonce(); pool.pendingState.GetNonce(addr) > nonce {
@@ -1385,7 +1652,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 // are moved back into the future queue.
 func (pool *TxPool) demoteUnexecutables() {
 	// Iterate over all accounts and demote any non-executable transactions
-	logger := utils.Logger().With().Stack().Logger()
+	logger := utils.ModuleLogger(utils.ModuleTxPool).With().Stack().Logger()
 
 	for addr, list := range pool.pending {
 		nonce := pool.currentState.GetNonce(addr)
@@ -1396,6 +1663,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			logger.Warn().Str("hash", hash.Hex()).Msg("Removed old pending transaction")
 			pool.all.Remove(hash)
 			pool.priced.Removed()
+			pool.pendingRemoved(tx)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
@@ -1404,11 +1672,13 @@ func (pool *TxPool) demoteUnexecutables() {
 			logger.Warn().Str("hash", hash.Hex()).Msg("Removed unpayable pending transaction")
 			pool.all.Remove(hash)
 			pool.priced.Removed()
+			pool.pendingRemoved(tx)
 			pendingNofundsCounter.Inc(1)
 		}
 		for _, tx := range invalids {
 			hash := tx.Hash()
 			logger.Warn().Str("hash", hash.Hex()).Msg("Demoting pending transaction")
+			pool.pendingRemoved(tx)
 			if _, err := pool.enqueueTx(hash, tx); err != nil {
 				pool.txErrorSink.Add(tx, err)
 			}
@@ -1418,6 +1688,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			for _, tx := range list.Cap(0) {
 				hash := tx.Hash()
 				logger.Error().Str("hash", hash.Hex()).Msg("Demoting invalidated transaction")
+				pool.pendingRemoved(tx)
 				if _, err := pool.enqueueTx(hash, tx); err != nil {
 					pool.txErrorSink.Add(tx, err)
 				}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// maxQueuedPendingEvents bounds the pending events waiting for slow
+// subscribers, the oldest being dropped beyond
+const maxQueuedPendingEvents = 4096
+
+var (
+	pendingGauge               = metrics.NewRegisteredGauge("txpool/pending", nil)
+	queuedGauge                = metrics.NewRegisteredGauge("txpool/queued", nil)
+	pendingEventDroppedCounter = metrics.NewRegisteredCounter("txpool/events/dropped", nil)
+)
+
+// pendingChange is the net change of a transaction to the pending ones, +1
+// entering, -1 leaving
+type pendingChange struct {
+	tx  types.PoolTransaction
+	net int
+}
+
+// pendingChanges are the changes to the pending transactions made while the
+// pool lock is held, posted as one event on release
+type pendingChanges struct {
+	order   []common.Hash
+	changes map[common.Hash]*pendingChange
+}
+
+func (c *pendingChanges) record(tx types.PoolTransaction, net int) {
+	hash := tx.Hash()
+	if c.changes == nil {
+		c.changes = map[common.Hash]*pendingChange{}
+	}
+	if change, ok := c.changes[hash]; ok {
+		change.net += net
+		return
+	}
+	c.order = append(c.order, hash)
+	c.changes[hash] = &pendingChange{tx: tx, net: net}
+}
+
+// event returns the net changes as an event, false if there are none, and
+// clears them
+func (c *pendingChanges) event() (PendingTxsEvent, bool) {
+	ev := PendingTxsEvent{}
+	for _, hash := range c.order {
+		switch change := c.changes[hash]; {
+		case change.net > 0:
+			ev.Added = append(ev.Added, change.tx)
+		case change.net < 0:
+			ev.Removed = append(ev.Removed, change.tx)
+		}
+	}
+	c.order, c.changes = nil, nil
+	return ev, len(ev.Added) > 0 || len(ev.Removed) > 0
+}
+
+// pendingEventQueue delivers the pending events to the subscribers in order,
+// without the pool waiting for them
+type pendingEventQueue struct {
+	lock   sync.Mutex
+	events []PendingTxsEvent
+	wake   chan struct{}
+}
+
+func newPendingEventQueue() *pendingEventQueue {
+	return &pendingEventQueue{wake: make(chan struct{}, 1)}
+}
+
+func (q *pendingEventQueue) push(ev PendingTxsEvent) {
+	q.lock.Lock()
+	if len(q.events) >= maxQueuedPendingEvents {
+		q.events = q.events[1:]
+		pendingEventDroppedCounter.Inc(1)
+	}
+	q.events = append(q.events, ev)
+	q.lock.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *pendingEventQueue) take() []PendingTxsEvent {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	events := q.events
+	q.events = nil
+	return events
+}
+
+// pendingAdded records the transaction entered the pending ones.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) pendingAdded(tx types.PoolTransaction) {
+	pool.pendingChanges.record(tx, 1)
+}
+
+// pendingRemoved records the transactions left the pending ones.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) pendingRemoved(txs ...types.PoolTransaction) {
+	for _, tx := range txs {
+		pool.pendingChanges.record(tx, -1)
+	}
+}
+
+// unlock posts the changes to the pending transactions made while the pool
+// lock was held, and releases it
+func (pool *TxPool) unlock() {
+	if ev, ok := pool.pendingChanges.event(); ok && pool.pendingEvents != nil {
+		ev.Pending, ev.Queued = pool.stats()
+		pool.pendingEvents.push(ev)
+	}
+	pool.mu.Unlock()
+}
+
+// SubscribePendingTxsEvent registers a subscription of PendingTxsEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribePendingTxsEvent(ch chan<- PendingTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.pendingFeed.Subscribe(ch))
+}
+
+// pendingEventLoop sends the pending events to the subscribers and updates
+// the pool gauges until the pool stops
+func (pool *TxPool) pendingEventLoop() {
+	defer pool.wg.Done()
+	for {
+		select {
+		case <-pool.pendingEvents.wake:
+			for _, ev := range pool.pendingEvents.take() {
+				pendingGauge.Update(int64(ev.Pending))
+				queuedGauge.Update(int64(ev.Queued))
+				pool.pendingFeed.Send(ev)
+			}
+		case <-pool.chainHeadSub.Err():
+			return
+		}
+	}
+}
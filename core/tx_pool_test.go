@@ -23,6 +23,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -1101,6 +1102,54 @@ func testTransactionQueueTimeLimiting(t *testing.T, nolocals bool) {
 	}
 }
 
+// Tests that queued transactions whose nonce gap isn't filled within the
+// configured number of epochs are dropped and reported.
+func TestTransactionQueueNonceGapReaping(t *testing.T) {
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account, _ := deriveSender(transaction(0, 0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	dropped := make(chan DroppedTxsEvent, 1)
+	sub := pool.SubscribeDroppedTxsEvent(dropped)
+	defer sub.Unsubscribe()
+
+	tx := transaction(0, 1, 100000, key)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	if _, queued := pool.Stats(); queued != 1 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+	start := pool.reapedEpoch
+
+	pool.mu.Lock()
+	pool.reapNonceGaps(start + pool.config.NonceGapEpochs - 1)
+	pool.mu.Unlock()
+	if _, queued := pool.Stats(); queued != 1 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+
+	pool.mu.Lock()
+	pool.reapNonceGaps(start + pool.config.NonceGapEpochs)
+	pool.mu.Unlock()
+	if _, queued := pool.Stats(); queued != 0 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 0)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	select {
+	case ev := <-dropped:
+		if len(ev.Txs) != 1 || ev.Txs[0].Hash() != tx.Hash() || ev.Reason != ErrNonceGapExpired {
+			t.Fatalf("unexpected dropped event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("dropped transaction event not fired")
+	}
+}
+
 // Tests that the transaction limits are enforced the same way irrelevant whether
 // the transactions are added one by one or in batches.
 func TestTransactionQueueLimitingEquivalency(t *testing.T) { testTransactionLimitingEquivalency(t, 1) }
@@ -1578,6 +1627,146 @@ func BenchmarkPoolInsert(b *testing.B) {
 	}
 }
 
+// Tests that the limits of the pool are clamped and enforced when they are
+// changed at runtime.
+func TestTransactionPoolSetLimits(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account, _ := deriveSender(transaction(0, 0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	// Queue transactions beyond a nonce gap
+	for i := uint64(1); i <= 10; i++ {
+		if err := pool.AddRemote(transaction(0, i, 100000, key)); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if _, queued := pool.Stats(); queued != 10 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 10)
+	}
+	limits := pool.Limits()
+	limits.AccountQueue = 3
+	limits.GlobalSlots = 8
+	limits.AccountSlots = 16
+	limits.Lifetime = time.Second
+
+	applied := pool.SetLimits(limits)
+	if applied.AccountSlots != 8 {
+		t.Errorf("account slots not clamped to global slots: have %d, want %d", applied.AccountSlots, 8)
+	}
+	if applied.Lifetime != minTxPoolLifetime {
+		t.Errorf("lifetime not clamped: have %v, want %v", applied.Lifetime, minTxPoolLifetime)
+	}
+	if pool.Limits() != applied {
+		t.Errorf("limits in effect mismatched: have %+v, want %+v", pool.Limits(), applied)
+	}
+	if _, queued := pool.Stats(); queued != 3 {
+		t.Errorf("queued transactions mismatched: have %d, want %d", queued, 3)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the nonce gaps of an account are reported, and that the queued
+// transactions beyond the first gap can be evicted.
+func TestTransactionNonceGaps(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account, _ := deriveSender(transaction(0, 0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	for _, nonce := range []uint64{0, 1, 3, 4, 7} {
+		if err := pool.AddRemote(transaction(0, nonce, 100000, key)); err != nil {
+			t.Fatalf("nonce %d: failed to add transaction: %v", nonce, err)
+		}
+	}
+	report := pool.NonceGaps(account)
+	if !reflect.DeepEqual(report.PendingNonces, []uint64{0, 1}) {
+		t.Errorf("pending nonces mismatched: have %v, want %v", report.PendingNonces, []uint64{0, 1})
+	}
+	if !reflect.DeepEqual(report.QueuedNonces, []uint64{3, 4, 7}) {
+		t.Errorf("queued nonces mismatched: have %v, want %v", report.QueuedNonces, []uint64{3, 4, 7})
+	}
+	wantGaps := []NonceRange{{From: 2, To: 2}, {From: 5, To: 6}}
+	if !reflect.DeepEqual(report.Gaps, wantGaps) {
+		t.Errorf("gaps mismatched: have %v, want %v", report.Gaps, wantGaps)
+	}
+
+	if dropped := pool.EvictNonceGap(account); len(dropped) != 3 {
+		t.Errorf("dropped transactions mismatched: have %d, want %d", len(dropped), 3)
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Errorf("pool stats mismatched: have %d/%d, want %d/%d", pending, queued, 2, 0)
+	}
+	if report := pool.NonceGaps(account); len(report.Gaps) != 0 {
+		t.Errorf("gaps left after eviction: %v", report.Gaps)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the transactions entering and leaving the pending ones are
+// posted to the subscribers in order.
+func TestTransactionPendingEvents(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	events := make(chan PendingTxsEvent, 10)
+	sub := pool.SubscribePendingTxsEvent(events)
+	defer sub.Unsubscribe()
+
+	account, _ := deriveSender(transaction(0, 0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000000000))
+
+	next := func() PendingTxsEvent {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("pending event not posted")
+		}
+		return PendingTxsEvent{}
+	}
+	// a queued transaction is not pending
+	if err := pool.AddRemote(transaction(0, 1, 100000, key)); err != nil {
+		t.Fatalf("failed to add queued transaction: %v", err)
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected pending event %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+	// filling the nonce gap promotes both
+	tx := pricedTransaction(0, 0, 100000, big.NewInt(1), key)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Fatalf("failed to add pending transaction: %v", err)
+	}
+	if ev := next(); len(ev.Added) != 2 || len(ev.Removed) != 0 ||
+		ev.Added[0].Hash() != tx.Hash() || ev.Pending != 2 || ev.Queued != 0 {
+		t.Fatalf("unexpected promotion event %+v", ev)
+	}
+	// a replacement enters as the replaced one leaves
+	replacement := pricedTransaction(0, 0, 100000, big.NewInt(2), key)
+	if err := pool.AddRemote(replacement); err != nil {
+		t.Fatalf("failed to replace pending transaction: %v", err)
+	}
+	if ev := next(); len(ev.Added) != 1 || len(ev.Removed) != 1 ||
+		ev.Added[0].Hash() != replacement.Hash() || ev.Removed[0].Hash() != tx.Hash() ||
+		ev.Pending != 2 {
+		t.Fatalf("unexpected replacement event %+v", ev)
+	}
+}
+
 // Benchmarks the speed of batched transaction insertion.
 func BenchmarkPoolBatchInsert100(b *testing.B)   { benchmarkPoolBatchInsert(b, 100) }
 func BenchmarkPoolBatchInsert1000(b *testing.B)  { benchmarkPoolBatchInsert(b, 1000) }
@@ -36,6 +36,25 @@ func (r *CXReceipt) Copy() *CXReceipt {
 	return &cpy
 }
 
+// CXDelivery records the spending of the receipt of an outgoing cross-shard
+// transaction by the destination shard
+type CXDelivery struct {
+	TxHash    common.Hash // hash of the cross shard transaction in source shard
+	ToShardID uint32
+	BlockNum  uint64 // number of the destination shard block spending the receipt
+	BlockHash common.Hash
+}
+
+// CXResend is the resend of the outgoing receipts of a block to a destination
+// shard, queued until they are delivered
+type CXResend struct {
+	ToShardID uint32
+	BlockNum  uint64
+	BlockHash common.Hash
+	Attempts  uint32 // resends so far
+	NextRetry uint64 // unix time of the next resend
+}
+
 // CXReceipts is a list of CXReceipt
 type CXReceipts []*CXReceipt
 
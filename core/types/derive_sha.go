@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/pkg/errors"
 )
 
 // DerivableBase ..
@@ -57,6 +58,41 @@ func DeriveSha(list ...DerivableBase) common.Hash {
 	return trie.Hash()
 }
 
+// DeriveShaProof returns the Merkle proof of the item at the given position
+// of the trie generated by the lists, as hashed by DeriveSha.
+func DeriveShaProof(position uint, list ...DerivableBase) ([][]byte, error) {
+	keybuf := new(bytes.Buffer)
+	trie := new(trie.Trie)
+	var num uint
+
+	for j := range list {
+		for i := 0; i < list[j].Len(); i++ {
+			keybuf.Reset()
+			rlp.Encode(keybuf, num)
+			trie.Update(keybuf.Bytes(), list[j].GetRlp(i))
+			num++
+		}
+	}
+	if position >= num {
+		return nil, errors.Errorf("position %d out of %d items", position, num)
+	}
+	keybuf.Reset()
+	rlp.Encode(keybuf, position)
+	var proof proofList
+	if err := trie.Prove(keybuf.Bytes(), 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// proofList collects the trie nodes of a Merkle proof
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
 //// Legacy forked logic. Keep as is, but do not use it anymore ->
 
 // DeriveOneShardSha calculates the hash of the trie of
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDeriveShaProof(t *testing.T) {
+	receipts := Receipts{}
+	for i := 0; i < 20; i++ {
+		receipts = append(receipts, &Receipt{
+			Status:            ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+		})
+	}
+	root := DeriveSha(receipts)
+	for i := range receipts {
+		proof, err := DeriveShaProof(uint(i), receipts)
+		if err != nil {
+			t.Fatalf("proof of receipt %d: %v", i, err)
+		}
+		if len(proof) == 0 || crypto.Keccak256Hash(proof[0]) != root {
+			t.Errorf("proof of receipt %d does not start at the receipt root", i)
+		}
+		if !bytes.Contains(proof[len(proof)-1], receipts.GetRlp(i)) {
+			t.Errorf("proof of receipt %d does not end at the receipt", i)
+		}
+	}
+	if _, err := DeriveShaProof(uint(len(receipts)), receipts); err == nil {
+		t.Error("expected an error for a position out of the list")
+	}
+}
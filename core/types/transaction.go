@@ -551,6 +551,18 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 	heap.Pop(&t.heads)
 }
 
+// Drop removes the transactions left of the account, as Pop would have before
+// shifting past its transaction that cannot be executed.
+func (t *TransactionsByPriceAndNonce) Drop(acc common.Address) {
+	delete(t.txs, acc)
+	for i, tx := range t.heads {
+		if from, _ := Sender(t.signer, tx); from == acc {
+			heap.Remove(&t.heads, i)
+			return
+		}
+	}
+}
+
 // Message is a fully derived transaction and implements core.Message
 // NOTE: In a future PR this will be removed.
 type Message struct {
@@ -86,6 +86,53 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 	}
 }
 
+// Tests that the transactions left of an account shifted past are dropped,
+// whether its next transaction is at the head or not.
+func TestTransactionPriceNonceDrop(t *testing.T) {
+	signer := HomesteadSigner{}
+	for _, test := range []struct {
+		name  string
+		price int64 // price of the next transaction of the account dropped
+		count int   // transactions left of the account dropped
+	}{
+		{"next first", 300, 2},
+		{"next last", 10, 2},
+		{"none left", 0, 0},
+	} {
+		dropped, droppedAddr := defaultTestKey()
+		kept, _ := crypto.GenerateKey()
+		keptAddr := crypto.PubkeyToAddress(kept.PublicKey)
+		groups := map[common.Address]Transactions{}
+		prices := []int64{200}
+		for i := 0; i < test.count; i++ {
+			prices = append(prices, test.price)
+		}
+		for nonce, price := range prices {
+			tx, _ := SignTx(NewTransaction(uint64(nonce), common.Address{}, 0, big.NewInt(100), 100, big.NewInt(price), nil), signer, dropped)
+			groups[droppedAddr] = append(groups[droppedAddr], tx)
+		}
+		for nonce := 0; nonce < 2; nonce++ {
+			tx, _ := SignTx(NewTransaction(uint64(nonce), common.Address{}, 0, big.NewInt(100), 100, big.NewInt(100), nil), signer, kept)
+			groups[keptAddr] = append(groups[keptAddr], tx)
+		}
+		txset := NewTransactionsByPriceAndNonce(signer, groups)
+		txset.Shift()
+		txset.Drop(droppedAddr)
+
+		count := 0
+		for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+			if from, _ := Sender(signer, tx); from != keptAddr {
+				t.Errorf("%s: transaction %d of the dropped account left", test.name, tx.Nonce())
+			}
+			count++
+			txset.Shift()
+		}
+		if count != 2 {
+			t.Errorf("%s: expected 2 transactions kept, found %d", test.name, count)
+		}
+	}
+}
+
 // TestTransactionJSON tests serializing/de-serializing to/from JSON.
 func TestTransactionJSON(t *testing.T) {
 	key, err := crypto.GenerateKey()
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// BlockVerification is the set of checks a block passed before its insertion,
+// as recorded in the verified block cache of the chain
+type BlockVerification uint8
+
+// Checks recorded in the verified block cache
+const (
+	// VerifiedHeader is the verification of the header with its seal, the
+	// commit signature of the parent
+	VerifiedHeader BlockVerification = 1 << iota
+	// VerifiedBody is the verification of the transactions of the block
+	// against the root of its header
+	VerifiedBody
+)
+
+// verifiedBlocksCacheLimit bounds the blocks whose verification is recorded,
+// the blocks under consensus being inserted shortly after
+const verifiedBlocksCacheLimit = 32
+
+var verifiedSkipCounter = metrics.NewRegisteredCounter("chain/insert/verified_skipped", nil)
+
+// verifiedBlock is a block whose verification is recorded, and its checks
+type verifiedBlock struct {
+	block  *types.Block
+	checks BlockVerification
+}
+
+// MarkBlockVerified records the block passed the checks, for its insertion to
+// skip them, ex: the checks of the consensus before the block is confirmed
+func (bc *BlockChain) MarkBlockVerified(block *types.Block, checks BlockVerification) {
+	hash := block.Hash()
+	if v, ok := bc.verifiedBlocks.Get(hash); ok {
+		if prev := v.(verifiedBlock); prev.block == block {
+			checks |= prev.checks
+		}
+	}
+	bc.verifiedBlocks.Add(hash, verifiedBlock{block: block, checks: checks})
+}
+
+// blockVerified tells whether the block passed the checks. The header checks
+// hold for any block of the same hash, the other checks only for the very
+// block verified, the hash not covering the body.
+func (bc *BlockChain) blockVerified(block *types.Block, checks BlockVerification) bool {
+	v, ok := bc.verifiedBlocks.Get(block.Hash())
+	if !ok {
+		return false
+	}
+	verified := v.(verifiedBlock)
+	if verified.checks&checks != checks {
+		return false
+	}
+	return checks&^VerifiedHeader == 0 || verified.block == block
+}
+
+// headersVerified tells whether the headers of all the blocks were verified
+func (bc *BlockChain) headersVerified(chain types.Blocks) bool {
+	for _, block := range chain {
+		if !bc.blockVerified(block, VerifiedHeader) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+func TestVerifiedBlocks(t *testing.T) {
+	cache, _ := lru.New(verifiedBlocksCacheLimit)
+	bc := &BlockChain{verifiedBlocks: cache}
+	header := blockfactory.NewTestHeader().With().Number(big.NewInt(1)).Header()
+	verified := types.NewBlockWithHeader(header)
+	// same header, so same hash, but another body possibly
+	other := verified.WithBody(nil, nil, nil, nil)
+
+	if bc.blockVerified(verified, VerifiedHeader) {
+		t.Fatal("block verified before being marked")
+	}
+	bc.MarkBlockVerified(verified, VerifiedHeader)
+	bc.MarkBlockVerified(verified, VerifiedBody)
+	if !bc.blockVerified(verified, VerifiedHeader|VerifiedBody) {
+		t.Error("checks of the block not merged")
+	}
+	if !bc.blockVerified(other, VerifiedHeader) {
+		t.Error("header checks not holding for a block of the same hash")
+	}
+	if bc.blockVerified(other, VerifiedBody) {
+		t.Error("body checks holding for another block of the same hash")
+	}
+	if !bc.headersVerified(types.Blocks{verified, other}) {
+		t.Error("headers of the blocks not verified")
+	}
+
+	// the checks of another block of the same hash are replaced, not merged
+	bc.MarkBlockVerified(other, VerifiedBody)
+	if bc.blockVerified(other, VerifiedHeader) {
+		t.Error("header checks of the previous block kept")
+	}
+}
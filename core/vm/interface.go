@@ -47,7 +47,7 @@ type StateDB interface {
 	SetValidatorFlag(common.Address)
 	UnsetValidatorFlag(common.Address)
 	IsValidator(common.Address) bool
-	AddReward(*staking.ValidatorWrapper, *big.Int, map[common.Address]numeric.Dec) error
+	AddReward(*staking.ValidatorWrapper, *big.Int, map[common.Address]numeric.Dec) (map[common.Address]*big.Int, error)
 
 	AddRefund(uint64)
 	SubRefund(uint64)
@@ -13,7 +13,10 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/harmony-one/harmony/api/proto"
+	"github.com/harmony-one/harmony/api/service/absentee"
 	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/consensus/ledger"
 	"github.com/harmony-one/harmony/consensus/quorum"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/state"
@@ -30,6 +33,7 @@ import (
 	"github.com/harmony-one/harmony/staking/effective"
 	"github.com/harmony-one/harmony/staking/network"
 	staking "github.com/harmony-one/harmony/staking/types"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/singleflight"
 )
@@ -43,6 +47,8 @@ type APIBackend struct {
 		TotalStaking *big.Int
 	}
 	apiCache singleflight.Group
+	// committees on record, keyed by their epoch
+	committees *lru.Cache
 }
 
 // SingleFlightRequest ...
@@ -120,10 +126,39 @@ func (b *APIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uin
 	return b.hmy.txPool.State().GetNonce(addr), nil
 }
 
+// GetPoolNonceGaps returns the pending and queued nonces of the account in the
+// transaction pool, and the gaps between them
+func (b *APIBackend) GetPoolNonceGaps(addr common.Address) core.NonceGapReport {
+	return b.hmy.txPool.NonceGaps(addr)
+}
+
+// GetNonceHints returns the next nonce of the account on the shard of the node,
+// from its transaction pool, and on the beacon chain if it is another shard,
+// from its state only
+func (b *APIBackend) GetNonceHints(addr common.Address) []commonRPC.ShardNonceHint {
+	hints := []commonRPC.ShardNonceHint{
+		commonRPC.NewPoolNonceHint(b.GetShardID(), b.hmy.txPool.NonceGaps(addr)),
+	}
+	if b.GetShardID() == shard.BeaconChainShardID {
+		return hints
+	}
+	beacon := commonRPC.ShardNonceHint{
+		ShardID:   shard.BeaconChainShardID,
+		PoolKnown: false,
+		Warnings:  []string{"pending transactions of the shard are not known to this node"},
+	}
+	if db, err := b.hmy.BeaconChain().State(); err != nil {
+		beacon.Warnings = append(beacon.Warnings, err.Error())
+	} else {
+		beacon.StateNonce = db.GetNonce(addr)
+		beacon.PendingNonce, beacon.NextNonce = beacon.StateNonce, beacon.StateNonce
+	}
+	return append(hints, beacon)
+}
+
 // SendTx ...
 func (b *APIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
-	b.hmy.nodeAPI.AddPendingTransaction(signedTx)
-	return nil
+	return b.hmy.nodeAPI.AddPendingTransaction(signedTx)
 }
 
 // ChainConfig ...
@@ -198,6 +233,22 @@ func (b *APIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subs
 	return b.hmy.TxPool().SubscribeNewTxsEvent(ch)
 }
 
+// SubscribePendingTxsEvent subcribes the transactions entering and leaving
+// the pending ones.
+func (b *APIBackend) SubscribePendingTxsEvent(ch chan<- core.PendingTxsEvent) event.Subscription {
+	return b.hmy.TxPool().SubscribePendingTxsEvent(ch)
+}
+
+// SubscribeDroppedTxsEvent subcribes dropped tx event.
+func (b *APIBackend) SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) event.Subscription {
+	return b.hmy.TxPool().SubscribeDroppedTxsEvent(ch)
+}
+
+// SubscribeConsensusPhaseEvent subcribes the consensus phase transitions.
+func (b *APIBackend) SubscribeConsensusPhaseEvent(ch chan<- consensus.PhaseEvent) event.Subscription {
+	return b.hmy.nodeAPI.SubscribeConsensusPhaseEvents(ch)
+}
+
 // SubscribeChainEvent subcribes chain event.
 // TODO: this is not implemented or verified yet for harmony.
 func (b *APIBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
@@ -241,6 +292,17 @@ func (b *APIBackend) GetPoolTransactions() (types.PoolTransactions, error) {
 	return txs, nil
 }
 
+// GetPoolContent returns the pending and queued transactions of the pool,
+// grouped by account and sorted by nonce
+func (b *APIBackend) GetPoolContent() (pending, queued map[common.Address]types.PoolTransactions) {
+	return b.hmy.txPool.Content()
+}
+
+// GetPoolConfig returns the configuration of the transaction pool
+func (b *APIBackend) GetPoolConfig() core.TxPoolConfig {
+	return b.hmy.txPool.Config()
+}
+
 // GetPoolStats returns the number of pending and queued transactions
 func (b *APIBackend) GetPoolStats() (pendingCount, queuedCount int) {
 	return b.hmy.txPool.Stats()
@@ -351,11 +413,26 @@ func (b *APIBackend) ResendCx(ctx context.Context, txID common.Hash) (uint64, bo
 	if tx.ShardID() == tx.ToShardID() || blk.Header().ShardID() != tx.ShardID() {
 		return 0, false
 	}
-	entry := core.CxEntry{blockHash, tx.ToShardID()}
+	entry := core.CxEntry{BlockHash: blockHash, BlockNum: blockNum, ToShardID: tx.ToShardID()}
 	success := b.hmy.CxPool().Add(entry)
 	return blockNum, success
 }
 
+// GetCXReceiptsProof builds the proof of the cross shard receipts sent by the
+// given block of this shard to the destination shard
+func (b *APIBackend) GetCXReceiptsProof(
+	ctx context.Context, blockHash common.Hash, toShardID uint32,
+) (*types.CXReceiptsProof, error) {
+	if toShardID == b.hmy.shardID {
+		return nil, errors.New("destination shard is the shard of the block")
+	}
+	blk := b.hmy.BlockChain().GetBlockByHash(blockHash)
+	if blk == nil {
+		return nil, errors.Errorf("block %s not found", blockHash.Hex())
+	}
+	return b.hmy.BlockChain().CXReceiptsProof(toShardID, blk)
+}
+
 // IsLeader exposes if node is currently leader
 func (b *APIBackend) IsLeader() bool {
 	return b.hmy.nodeAPI.IsCurrentlyLeader()
@@ -635,6 +712,11 @@ func (b *APIBackend) GetPendingCXReceipts() []*types.CXReceiptsProof {
 	return b.hmy.nodeAPI.PendingCXReceipts()
 }
 
+// SuggestPrice returns the gas price suggested for new transactions
+func (b *APIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	return b.hmy.nodeAPI.SuggestGasPrice(), nil
+}
+
 // GetCurrentUtilityMetrics ..
 func (b *APIBackend) GetCurrentUtilityMetrics() (*network.UtilityMetric, error) {
 	return network.NewUtilityMetricSnapshot(b.hmy.BlockChain())
@@ -798,3 +880,214 @@ func (b *APIBackend) GetNodeMetadata() commonRPC.NodeMetadata {
 		b.hmy.nodeAPI.GetNodeBootTime(),
 	}
 }
+
+// GetLeaderStats ..
+func (b *APIBackend) GetLeaderStats() []consensus.LeaderStats {
+	return b.hmy.nodeAPI.LeaderStats()
+}
+
+// GetShardHeights ..
+func (b *APIBackend) GetShardHeights() []commonRPC.ShardHeight {
+	heights := b.hmy.nodeAPI.ShardHeights()
+	result := make([]commonRPC.ShardHeight, len(heights))
+	for i, height := range heights {
+		result[i] = commonRPC.ShardHeight{
+			ShardID:   height.ShardID,
+			Height:    height.Height,
+			Hash:      height.Hash.Hex(),
+			MaxHeight: height.MaxHeight,
+			Peers:     height.Peers,
+			UpdatedAt: height.UpdatedAt.Unix(),
+		}
+	}
+	return result
+}
+
+// GetHeadDistribution ..
+func (b *APIBackend) GetHeadDistribution() []commonRPC.ShardHeads {
+	distribution := b.hmy.nodeAPI.HeadDistribution()
+	result := make([]commonRPC.ShardHeads, len(distribution))
+	for i, heads := range distribution {
+		counts := make([]commonRPC.HeadCount, len(heads.Heads))
+		for j, head := range heads.Heads {
+			counts[j] = commonRPC.HeadCount{
+				Height:    head.Height,
+				Hash:      head.Hash.Hex(),
+				Reporters: head.Reporters,
+			}
+		}
+		result[i] = commonRPC.ShardHeads{
+			ShardID:   heads.ShardID,
+			Reporters: heads.Reporters,
+			Height:    heads.Height,
+			MinHeight: heads.MinHeight,
+			MaxHeight: heads.MaxHeight,
+			MaxViewID: heads.MaxViewID,
+			Heads:     counts,
+			UpdatedAt: heads.UpdatedAt.Unix(),
+		}
+	}
+	return result
+}
+
+// GetNextShardAssignment ..
+func (b *APIBackend) GetNextShardAssignment(
+	key shard.BLSPublicKey,
+) (*commonRPC.ShardAssignment, error) {
+	beacon := b.hmy.BeaconChain()
+	header := beacon.CurrentHeader()
+	nextEpoch := new(big.Int).Add(header.Epoch(), common.Big1)
+
+	nextState, err := beacon.ReadShardState(nextEpoch)
+	if err == nil {
+		return commonRPC.NewShardAssignment(key, nextEpoch.Uint64(), nextState, true), nil
+	}
+	// The committee of the next epoch is only elected at the last block of
+	// the current epoch, project it from the current stakes until then
+	blockNr := header.Number().Uint64()
+	cacheKey := fmt.Sprintf("next-ss-%d", blockNr)
+	b.apiCache.Forget(fmt.Sprintf("next-ss-%d", blockNr-1))
+	res, err := b.SingleFlightRequest(
+		cacheKey,
+		func() (interface{}, error) {
+			return committee.WithStakingEnabled.Compute(nextEpoch, beacon)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return commonRPC.NewShardAssignment(key, nextEpoch.Uint64(), res.(*shard.State), false), nil
+}
+
+// GetCommitteesByEpoch returns the committees of all the shards in the epoch,
+// as elected on the beacon chain. The committees of the next epoch, not
+// elected until the last block of the current one, are projected from the
+// current stakes until then, and returned as not final.
+func (b *APIBackend) GetCommitteesByEpoch(epoch *big.Int) (*shard.State, bool, error) {
+	if res, ok := b.committees.Get(epoch.Uint64()); ok {
+		return res.(*shard.State), true, nil
+	}
+	beacon := b.hmy.BeaconChain()
+	header := beacon.CurrentHeader()
+	nextEpoch := new(big.Int).Add(header.Epoch(), common.Big1)
+	if epoch.Cmp(nextEpoch) > 0 {
+		return nil, false, errors.Errorf(
+			"cannot compute the committees of epoch %v beyond the next epoch %v", epoch, nextEpoch,
+		)
+	}
+	if state, err := beacon.ReadShardState(epoch); err == nil {
+		b.committees.Add(epoch.Uint64(), state)
+		return state, true, nil
+	} else if epoch.Cmp(nextEpoch) < 0 {
+		return nil, false, err
+	}
+
+	blockNr := header.Number().Uint64()
+	b.apiCache.Forget(fmt.Sprintf("next-ss-%d", blockNr-1))
+	res, err := b.SingleFlightRequest(
+		fmt.Sprintf("next-ss-%d", blockNr),
+		func() (interface{}, error) {
+			return committee.WithStakingEnabled.Compute(nextEpoch, beacon)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	return res.(*shard.State), false, nil
+}
+
+// GetShardStateProof returns the beacon header holding the shard state of the
+// epoch, that is the header of the last block of the previous epoch or the
+// genesis one, along with the commit signature and bitmap on it, nil for the
+// genesis header.
+func (b *APIBackend) GetShardStateProof(epoch *big.Int) (*block.Header, []byte, error) {
+	beacon := b.hmy.BeaconChain()
+	var header *block.Header
+	if epoch.Sign() == 0 {
+		header = beacon.GetHeaderByNumber(0)
+	} else {
+		first, err := beacon.GetEpochBlockNumber(epoch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first.Sign() > 0 {
+			header = beacon.GetHeaderByNumber(first.Uint64() - 1)
+		}
+	}
+	if header == nil || len(header.ShardState()) == 0 {
+		return nil, nil, errors.Errorf("no beacon header holding the shard state of epoch %v", epoch)
+	}
+	state, err := shard.DecodeWrapper(header.ShardState())
+	if err != nil {
+		return nil, nil, err
+	}
+	if state.Epoch != nil && state.Epoch.Cmp(epoch) != 0 {
+		return nil, nil, errors.Errorf(
+			"beacon block %d holds the shard state of epoch %v, not %v",
+			header.Number().Uint64(), state.Epoch, epoch,
+		)
+	}
+	blockNum := header.Number().Uint64()
+	if blockNum == 0 {
+		return header, nil, nil
+	}
+	sig, err := beacon.ReadCommitSig(blockNum)
+	if err != nil || len(sig) <= shard.BLSSignatureSizeInBytes {
+		// the commit signature is carried by the child block otherwise
+		child := beacon.GetHeaderByNumber(blockNum + 1)
+		if child == nil {
+			return nil, nil, errors.Errorf("no commit signature of beacon block %d", blockNum)
+		}
+		lastSig := child.LastCommitSignature()
+		sig = append(lastSig[:], child.LastCommitBitmap()...)
+	}
+	return header, sig, nil
+}
+
+// GetStatePruneProgress ..
+func (b *APIBackend) GetStatePruneProgress() *core.StatePruneProgress {
+	return b.hmy.blockchain.StatePruneProgress()
+}
+
+// GetRewardEvents returns the payouts of the blocks in the inclusive range,
+// kept in the reward index of the beacon chain
+func (b *APIBackend) GetRewardEvents(from, to uint64) ([]core.RewardEvent, error) {
+	if b.GetShardID() != shard.BeaconChainShardID {
+		return nil, errors.New("rewards are paid out on the beacon chain only")
+	}
+	return b.hmy.BlockChain().ReadRewardEvents(from, to)
+}
+
+// GetVoteLedger ..
+func (b *APIBackend) GetVoteLedger(from, to uint64) ([]*ledger.Entry, error) {
+	records, err := ledger.Range(b.ChainDb(), from, to)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*ledger.Entry{}
+	committees := map[uint64]*shard.Committee{}
+	for _, record := range records {
+		committee, ok := committees[record.Epoch]
+		if !ok {
+			shardState, err := b.hmy.BlockChain().ReadShardState(new(big.Int).SetUint64(record.Epoch))
+			if err != nil {
+				return nil, err
+			}
+			if committee, err = shardState.FindCommitteeByID(b.GetShardID()); err != nil {
+				return nil, err
+			}
+			committees[record.Epoch] = committee
+		}
+		entries = append(entries, record.Export(committee))
+	}
+	return entries, nil
+}
+
+// GetAbsentees ..
+func (b *APIBackend) GetAbsentees(epoch uint64) (*absentee.Report, error) {
+	stats, err := absentee.Read(b.ChainDb(), epoch)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Export(), nil
+}
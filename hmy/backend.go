@@ -8,18 +8,24 @@ import (
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/harmony-one/harmony/api/service/syncing"
+	"github.com/harmony-one/harmony/api/service/telemetry"
+	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	staking "github.com/harmony-one/harmony/staking/types"
+	lru "github.com/hashicorp/golang-lru"
 )
 
+// committeeCacheLimit is the number of epochs whose committees are cached
+const committeeCacheLimit = 16
+
 // Harmony implements the Harmony full node service.
 type Harmony struct {
 	// Channel for shutting down the service
 	shutdownChan  chan bool                      // Channel for shutting down the Harmony
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	blockchain    *core.BlockChain
-	beaconchain   *core.BlockChain
 	txPool        *core.TxPool
 	cxPool        *core.CxPool
 	eventMux      *event.TypeMux
@@ -48,10 +54,15 @@ type NodeAPI interface {
 	GetTransactionsCount(address, txType string) (uint64, error)
 	GetStakingTransactionsCount(address, txType string) (uint64, error)
 	IsCurrentlyLeader() bool
+	LeaderStats() []consensus.LeaderStats
+	SubscribeConsensusPhaseEvents(ch chan<- consensus.PhaseEvent) event.Subscription
 	ReportStakingErrorSink() types.TransactionErrorReports
 	ReportPlainErrorSink() types.TransactionErrorReports
 	PendingCXReceipts() []*types.CXReceiptsProof
+	SuggestGasPrice() *big.Int
 	GetNodeBootTime() int64
+	ShardHeights() []syncing.ShardHeight
+	HeadDistribution() []telemetry.ShardHeads
 }
 
 // New creates a new Harmony object (including the
@@ -65,7 +76,6 @@ func New(
 		shutdownChan:  make(chan bool),
 		bloomRequests: make(chan chan *bloombits.Retrieval),
 		blockchain:    nodeAPI.Blockchain(),
-		beaconchain:   nodeAPI.Beaconchain(),
 		txPool:        txPool,
 		cxPool:        cxPool,
 		eventMux:      eventMux,
@@ -74,6 +84,7 @@ func New(
 		networkID:     1, // TODO(ricl): this should be from config
 		shardID:       shardID,
 	}
+	committees, _ := lru.New(committeeCacheLimit)
 	hmy.APIBackend = &APIBackend{hmy: hmy,
 		TotalStakingCache: struct {
 			sync.Mutex
@@ -83,6 +94,7 @@ func New(
 			BlockHeight:  -1,
 			TotalStaking: big.NewInt(0),
 		},
+		committees: committees,
 	}
 	return hmy, nil
 }
@@ -97,7 +109,7 @@ func (s *Harmony) CxPool() *core.CxPool { return s.cxPool }
 func (s *Harmony) BlockChain() *core.BlockChain { return s.blockchain }
 
 //BeaconChain ...
-func (s *Harmony) BeaconChain() *core.BlockChain { return s.beaconchain }
+func (s *Harmony) BeaconChain() *core.BlockChain { return s.nodeAPI.Beaconchain() }
 
 // NetVersion returns the network version, i.e. network ID identifying which network we are using
 func (s *Harmony) NetVersion() uint64 { return s.networkID }
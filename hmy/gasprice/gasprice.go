@@ -0,0 +1,108 @@
+package gasprice
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/harmony-one/harmony/common/denominations"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// sampleNumber is the number of the cheapest transactions sampled in a block
+const sampleNumber = 3
+
+// DefaultMaxPrice is the highest gas price suggested by default
+var DefaultMaxPrice = big.NewInt(500 * denominations.Nano)
+
+// Config configures the gas price oracle
+type Config struct {
+	// Blocks is the number of recent blocks sampled
+	Blocks int
+	// Percentile is the percentile of the sampled prices suggested
+	Percentile int
+	// Default is the price suggested before any transaction is sampled
+	Default *big.Int
+	// MaxPrice caps the price suggested
+	MaxPrice *big.Int
+}
+
+// DefaultConfig is the gas price oracle configuration used by default
+var DefaultConfig = Config{
+	Blocks:     20,
+	Percentile: 60,
+	Default:    big.NewInt(denominations.Nano),
+	MaxPrice:   DefaultMaxPrice,
+}
+
+// Oracle suggests the gas price of new transactions from the cheapest
+// transactions of the recent blocks of a shard. The blocks are fed as they
+// are committed, the sampled prices kept for the last blocks only.
+type Oracle struct {
+	lock    sync.RWMutex
+	cfg     Config
+	samples [][]*big.Int // prices sampled in each of the last blocks, oldest first
+	last    *big.Int     // price suggested, recomputed on each update
+}
+
+// NewOracle returns an oracle suggesting the default price until it samples
+// transactions, correcting the invalid parameters of the configuration
+func NewOracle(cfg Config) *Oracle {
+	if cfg.Blocks < 1 {
+		cfg.Blocks = 1
+	}
+	if cfg.Percentile < 0 {
+		cfg.Percentile = 0
+	}
+	if cfg.Percentile > 100 {
+		cfg.Percentile = 100
+	}
+	if cfg.Default == nil {
+		cfg.Default = DefaultConfig.Default
+	}
+	if cfg.MaxPrice == nil {
+		cfg.MaxPrice = DefaultMaxPrice
+	}
+	return &Oracle{cfg: cfg, last: new(big.Int).Set(cfg.Default)}
+}
+
+// Update samples the cheapest transactions of the block. The blocks without
+// transactions are skipped, keeping the suggestion of the blocks before them.
+func (o *Oracle) Update(block *types.Block) {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return
+	}
+	prices := make([]*big.Int, len(txs))
+	for i, tx := range txs {
+		prices[i] = tx.GasPrice()
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	if len(prices) > sampleNumber {
+		prices = prices[:sampleNumber]
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.samples = append(o.samples, prices)
+	if len(o.samples) > o.cfg.Blocks {
+		o.samples = o.samples[len(o.samples)-o.cfg.Blocks:]
+	}
+	all := []*big.Int{}
+	for _, s := range o.samples {
+		all = append(all, s...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Cmp(all[j]) < 0 })
+	price := all[(len(all)-1)*o.cfg.Percentile/100]
+	if price.Cmp(o.cfg.MaxPrice) > 0 {
+		price = o.cfg.MaxPrice
+	}
+	o.last = new(big.Int).Set(price)
+}
+
+// SuggestPrice returns the gas price suggested for new transactions
+func (o *Oracle) SuggestPrice() *big.Int {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return new(big.Int).Set(o.last)
+}
@@ -0,0 +1,48 @@
+package gasprice
+
+import (
+	"math/big"
+	"testing"
+
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func newBlock(prices ...int64) *types.Block {
+	txs := make([]*types.Transaction, len(prices))
+	for i, price := range prices {
+		txs[i] = types.NewTransaction(uint64(i), [20]byte{}, 0, big.NewInt(0), 21000, big.NewInt(price), nil)
+	}
+	return types.NewBlockWithHeader(blockfactory.NewTestHeader()).WithBody(txs, nil, nil, nil)
+}
+
+func TestOracleSuggestPrice(t *testing.T) {
+	oracle := NewOracle(Config{Blocks: 2, Percentile: 50, Default: big.NewInt(7), MaxPrice: big.NewInt(100)})
+	if price := oracle.SuggestPrice(); price.Int64() != 7 {
+		t.Errorf("suggested %v before sampling, expected the default", price)
+	}
+
+	// only the 3 cheapest of a block are sampled
+	oracle.Update(newBlock(50, 10, 30, 20, 40))
+	if price := oracle.SuggestPrice(); price.Int64() != 20 {
+		t.Errorf("suggested %v, expected 20", price)
+	}
+	// empty blocks keep the suggestion
+	oracle.Update(newBlock())
+	if price := oracle.SuggestPrice(); price.Int64() != 20 {
+		t.Errorf("suggested %v after an empty block, expected 20", price)
+	}
+	oracle.Update(newBlock(60, 70, 80))
+	if price := oracle.SuggestPrice(); price.Int64() != 30 {
+		t.Errorf("suggested %v, expected 30", price)
+	}
+	// the oldest block is dropped, and the price capped
+	oracle.Update(newBlock(200, 300))
+	if price := oracle.SuggestPrice(); price.Int64() != 80 {
+		t.Errorf("suggested %v, expected 80", price)
+	}
+	oracle.Update(newBlock(400))
+	if price := oracle.SuggestPrice(); price.Int64() != 100 {
+		t.Errorf("suggested %v, expected the max price", price)
+	}
+}
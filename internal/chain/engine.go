@@ -20,6 +20,7 @@ import (
 	"github.com/harmony-one/harmony/shard"
 	"github.com/harmony-one/harmony/shard/committee"
 	"github.com/harmony-one/harmony/staking/availability"
+	"github.com/harmony-one/harmony/staking/network"
 	"github.com/harmony-one/harmony/staking/slash"
 	staking "github.com/harmony-one/harmony/staking/types"
 	"github.com/pkg/errors"
@@ -169,6 +170,8 @@ func (e *engineImpl) VerifyShardState(
 // the PoS difficulty requirements, i.e. >= 2f+1 valid signatures from the committee
 // Note that each block header contains the bls signature of the parent block
 func (e *engineImpl) VerifySeal(chain engine.ChainReader, header *block.Header) error {
+	utils.AnalysisStart("verifySeal", header.ShardID(), header.Number())
+	defer utils.AnalysisEnd("verifySeal", header.ShardID(), header.Number())
 	if chain.CurrentHeader().Number().Uint64() <= uint64(1) {
 		return nil
 	}
@@ -252,10 +255,13 @@ func (e *engineImpl) Finalize(
 
 	// Process Undelegations, set LastEpochInCommittee and set EPoS status
 	// Needs to be before AccumulateRewardsAndCountSigs
+	undelegations := []reward.DelegatorPayout{}
 	if isBeaconChain && isNewEpoch && inStakingEra {
-		if err := payoutUndelegations(chain, header, state); err != nil {
+		paid, err := payoutUndelegations(chain, header, state)
+		if err != nil {
 			return nil, nil, err
 		}
+		undelegations = paid
 
 		// Needs to be after payoutUndelegations because payoutUndelegations
 		// depends on the old LastEpochInCommittee
@@ -288,6 +294,9 @@ func (e *engineImpl) Finalize(
 	if err != nil {
 		return nil, nil, errors.New("cannot pay block reward")
 	}
+	if len(undelegations) > 0 {
+		payout = network.WithUndelegations(payout, undelegations)
+	}
 
 	// Apply slashes
 	if isBeaconChain && inStakingEra && len(doubleSigners) > 0 {
@@ -303,10 +312,11 @@ func (e *engineImpl) Finalize(
 	return types.NewBlock(header, txs, receipts, outcxs, incxs, stks), payout, nil
 }
 
-// Withdraw unlocked tokens to the delegators' accounts
+// Withdraw unlocked tokens to the delegators' accounts, returning the amounts
+// withdrawn
 func payoutUndelegations(
 	chain engine.ChainReader, header *block.Header, state *state.DB,
-) error {
+) ([]reward.DelegatorPayout, error) {
 	currentHeader := chain.CurrentHeader()
 	nowEpoch, blockNow := currentHeader.Epoch(), currentHeader.Number()
 	utils.AnalysisStart("payoutUndelegations", nowEpoch, blockNow)
@@ -316,13 +326,14 @@ func payoutUndelegations(
 	countTrack := map[common.Address]int{}
 	if err != nil {
 		const msg = "[Finalize] failed to read all validators"
-		return errors.New(msg)
+		return nil, errors.New(msg)
 	}
+	paid := []reward.DelegatorPayout{}
 	// Payout undelegated/unlocked tokens
 	for _, validator := range validators {
 		wrapper, err := state.ValidatorWrapper(validator)
 		if err != nil {
-			return errors.New(
+			return nil, errors.New(
 				"[Finalize] failed to get validator from state to finalize",
 			)
 		}
@@ -332,6 +343,13 @@ func payoutUndelegations(
 				header.Epoch(), wrapper.LastEpochInCommittee,
 			)
 			state.AddBalance(delegation.DelegatorAddress, totalWithdraw)
+			if totalWithdraw.Sign() > 0 {
+				paid = append(paid, reward.DelegatorPayout{
+					Validator: validator,
+					Delegator: delegation.DelegatorAddress,
+					Amount:    totalWithdraw,
+				})
+			}
 		}
 		countTrack[validator] = len(wrapper.Delegations)
 	}
@@ -342,7 +360,7 @@ func payoutUndelegations(
 		Interface("count-track", countTrack).
 		Msg("paid out delegations")
 
-	return nil
+	return paid, nil
 }
 
 func setLastEpochInCommittee(header *block.Header, state *state.DB) error {
@@ -486,13 +504,15 @@ func QuorumForBlock(
 // i.e. this header verification api is more flexible since the caller specifies which commit signature and bitmap to use
 // for verifying the block header, which is necessary for cross-shard block header verification. Example of such is cross-shard transaction.
 func (e *engineImpl) VerifyHeaderWithSignature(chain engine.ChainReader, header *block.Header, commitSig []byte, commitBitmap []byte, reCalculate bool) error {
+	utils.AnalysisStart("verifyHeaderWithSignature", header.ShardID(), header.Number())
+	defer utils.AnalysisEnd("verifyHeaderWithSignature", header.ShardID(), header.Number())
 	if chain.Config().IsStaking(header.Epoch()) {
 		// Never recalculate after staking is enabled
 		reCalculate = false
 	}
 	publicKeys, err := GetPublicKeys(chain, header, reCalculate)
 	if err != nil {
-		return errors.New("[VerifyHeaderWithSignature] Cannot get publickeys for block header")
+		return errors.Wrapf(err, "[VerifyHeaderWithSignature] Cannot get publickeys for block header")
 	}
 
 	payload := append(commitSig[:], commitBitmap[:]...)
@@ -117,6 +117,26 @@ func lookupDelegatorShares(
 	return shares.(map[common.Address]numeric.Dec), nil
 }
 
+// delegatorPayouts lists the rewards credited to the delegators of the
+// validator, in the order of its delegations
+func delegatorPayouts(
+	wrapper *types2.ValidatorWrapper, credited map[common.Address]*big.Int,
+) []reward.DelegatorPayout {
+	payouts := []reward.DelegatorPayout{}
+	for i := range wrapper.Delegations {
+		delegator := wrapper.Delegations[i].DelegatorAddress
+		if amount, ok := credited[delegator]; ok {
+			payouts = append(payouts, reward.DelegatorPayout{
+				Validator: wrapper.Address,
+				Delegator: delegator,
+				Amount:    amount,
+			})
+			delete(credited, delegator)
+		}
+	}
+	return payouts
+}
+
 // AccumulateRewardsAndCountSigs credits the coinbase of the given block with the mining
 // reward. The total reward consists of the static block reward
 // This func also do IncrementValidatorSigningCounts for validators
@@ -177,7 +197,7 @@ func AccumulateRewardsAndCountSigs(
 		if err != nil {
 			return network.EmptyPayout, err
 		}
-		subComm := shard.Committee{shard.BeaconChainShardID, members}
+		subComm := shard.Committee{ShardID: shard.BeaconChainShardID, Slots: members}
 
 		if err := availability.IncrementValidatorSigningCounts(
 			beaconChain,
@@ -219,7 +239,8 @@ func AccumulateRewardsAndCountSigs(
 				if err != nil {
 					return network.EmptyPayout, err
 				}
-				if err := state.AddReward(snapshot.Validator, due, shares); err != nil {
+				credited, err := state.AddReward(snapshot.Validator, due, shares)
+				if err != nil {
 					return network.EmptyPayout, err
 				}
 				beaconP = append(beaconP, reward.Payout{
@@ -227,6 +248,7 @@ func AccumulateRewardsAndCountSigs(
 					Addr:        voter.EarningAccount,
 					NewlyEarned: due,
 					EarningKey:  voter.Identity,
+					Delegators:  delegatorPayouts(snapshot.Validator, credited),
 				})
 			}
 		}
@@ -363,7 +385,8 @@ func AccumulateRewardsAndCountSigs(
 					if err != nil {
 						return network.EmptyPayout, err
 					}
-					if err := state.AddReward(snapshot.Validator, due, shares); err != nil {
+					credited, err := state.AddReward(snapshot.Validator, due, shares)
+					if err != nil {
 						return network.EmptyPayout, err
 					}
 					shardP = append(shardP, reward.Payout{
@@ -371,6 +394,7 @@ func AccumulateRewardsAndCountSigs(
 						Addr:        payable.EcdsaAddress,
 						NewlyEarned: due,
 						EarningKey:  payable.BLSPublicKey,
+						Delegators:  delegatorPayouts(snapshot.Validator, credited),
 					})
 				}
 			}
@@ -379,7 +403,10 @@ func AccumulateRewardsAndCountSigs(
 				newRewards, missing, beaconP, shardP,
 			), nil
 		}
-		return network.EmptyPayout, nil
+		utils.AnalysisEnd("accumulateRewardShardchainPayout", nowEpoch, blockNow)
+		return network.NewStakingEraRewardForRound(
+			newRewards, missing, beaconP, shardP,
+		), nil
 	}
 
 	// Before staking
@@ -0,0 +1,60 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	types2 "github.com/harmony-one/harmony/staking/types"
+)
+
+func TestDelegatorPayouts(t *testing.T) {
+	validator := common.Address{0x11}
+	first, second := common.Address{0x22}, common.Address{0x33}
+	wrapper := &types2.ValidatorWrapper{
+		Validator: types2.Validator{Address: validator},
+		Delegations: types2.Delegations{
+			types2.NewDelegation(validator, big.NewInt(1)),
+			types2.NewDelegation(first, big.NewInt(1)),
+			types2.NewDelegation(second, big.NewInt(1)),
+		},
+	}
+	for _, test := range []struct {
+		name       string
+		credited   map[common.Address]*big.Int
+		delegators []common.Address // in the order of the delegations
+	}{
+		{"nothing credited", map[common.Address]*big.Int{}, nil},
+		{
+			"all credited",
+			map[common.Address]*big.Int{second: big.NewInt(3), validator: big.NewInt(1), first: big.NewInt(2)},
+			[]common.Address{validator, first, second},
+		},
+		{
+			"some credited",
+			map[common.Address]*big.Int{second: big.NewInt(3)},
+			[]common.Address{second},
+		},
+		{
+			"credited to an unknown delegator",
+			map[common.Address]*big.Int{{0x44}: big.NewInt(4), first: big.NewInt(2)},
+			[]common.Address{first},
+		},
+	} {
+		amounts := map[common.Address]*big.Int{}
+		for addr, amount := range test.credited {
+			amounts[addr] = amount
+		}
+		payouts := delegatorPayouts(wrapper, test.credited)
+		if len(payouts) != len(test.delegators) {
+			t.Errorf("%s: expected %d payouts, got %d", test.name, len(test.delegators), len(payouts))
+			continue
+		}
+		for i, payout := range payouts {
+			if payout.Validator != validator || payout.Delegator != test.delegators[i] ||
+				payout.Amount.Cmp(amounts[test.delegators[i]]) != 0 {
+				t.Errorf("%s: unexpected payout %d %+v", test.name, i, payout)
+			}
+		}
+	}
+}
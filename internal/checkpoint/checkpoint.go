@@ -0,0 +1,80 @@
+package checkpoint
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/crypto/hash"
+)
+
+// Errors returned when validating checkpoints.
+var (
+	ErrNoSignature     = errors.New("checkpoint is not signed")
+	ErrUntrustedSigner = errors.New("checkpoint is not signed by a trusted signer")
+)
+
+// Checkpoint is a trusted point of a shard chain from which a new node can
+// start syncing instead of from genesis. The block at BlockNumber carries the
+// shard state of Epoch, whose hash is ShardStateHash; the committees in it are
+// used to verify the blocks synced after the checkpoint.
+type Checkpoint struct {
+	ShardID        uint32        `json:"shard-id"`
+	Epoch          uint64        `json:"epoch"`
+	BlockNumber    uint64        `json:"block-number"`
+	BlockHash      common.Hash   `json:"block-hash"`
+	ShardStateHash common.Hash   `json:"shard-state-hash"`
+	Signature      hexutil.Bytes `json:"signature"`
+}
+
+// SigningHash returns the hash signed by the checkpoint signer.
+func (c *Checkpoint) SigningHash() common.Hash {
+	return hash.FromRLP([]interface{}{
+		c.ShardID,
+		c.Epoch,
+		c.BlockNumber,
+		c.BlockHash,
+		c.ShardStateHash,
+	})
+}
+
+// Sign signs the checkpoint with the given key.
+func (c *Checkpoint) Sign(key *ecdsa.PrivateKey) error {
+	h := c.SigningHash()
+	sig, err := crypto.Sign(h[:], key)
+	if err != nil {
+		return err
+	}
+	c.Signature = sig
+	return nil
+}
+
+// Signer recovers the address which signed the checkpoint.
+func (c *Checkpoint) Signer() (common.Address, error) {
+	if len(c.Signature) == 0 {
+		return common.Address{}, ErrNoSignature
+	}
+	h := c.SigningHash()
+	pub, err := crypto.SigToPub(h[:], c.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks that the checkpoint is signed by one of the trusted signers.
+func (c *Checkpoint) Verify(trusted []common.Address) error {
+	signer, err := c.Signer()
+	if err != nil {
+		return err
+	}
+	for _, addr := range trusted {
+		if addr == signer {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrUntrustedSigner, "signer %s", signer.Hex())
+}
@@ -0,0 +1,66 @@
+package checkpoint
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCheckpointVerify(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	trusted := []common.Address{crypto.PubkeyToAddress(key.PublicKey)}
+
+	cp := Checkpoint{
+		ShardID:     1,
+		Epoch:       10,
+		BlockNumber: 1000,
+		BlockHash:   common.HexToHash("0x01"),
+	}
+	if err := cp.Verify(trusted); err != ErrNoSignature {
+		t.Fatalf("expected ErrNoSignature, got %v", err)
+	}
+	if err := cp.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.Verify(trusted); err != nil {
+		t.Fatalf("verification of signed checkpoint failed: %v", err)
+	}
+	cp.BlockNumber++
+	if err := cp.Verify(trusted); err == nil {
+		t.Fatal("tampered checkpoint verified")
+	}
+	cp.BlockNumber--
+	if err := cp.Sign(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.Verify(trusted); err == nil {
+		t.Fatal("checkpoint of untrusted signer verified")
+	}
+}
+
+func TestLatest(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	trusted := []common.Address{crypto.PubkeyToAddress(key.PublicKey)}
+
+	checkpoints := []Checkpoint{
+		{ShardID: 0, BlockNumber: 100},
+		{ShardID: 0, BlockNumber: 300},
+		{ShardID: 1, BlockNumber: 500},
+		{ShardID: 0, BlockNumber: 200},
+	}
+	for i := range checkpoints {
+		checkpoints[i].Sign(key)
+	}
+	// unsigned checkpoints are ignored
+	checkpoints = append(checkpoints, Checkpoint{ShardID: 0, BlockNumber: 400})
+
+	latest := Latest(checkpoints, 0, trusted)
+	if latest == nil || latest.BlockNumber != 300 {
+		t.Fatalf("expected checkpoint 300, got %v", latest)
+	}
+	if Latest(checkpoints, 2, trusted) != nil {
+		t.Fatal("expected no checkpoint for shard 2")
+	}
+}
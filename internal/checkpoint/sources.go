@@ -0,0 +1,88 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+const fetchTimeout = 10 * time.Second
+
+// Builtin are the checkpoints shipped with the binary, per network type.
+var Builtin = map[nodeconfig.NetworkType][]Checkpoint{}
+
+// TrustedSigners are the addresses whose checkpoints are accepted, per
+// network type.
+var TrustedSigners = map[nodeconfig.NetworkType][]common.Address{}
+
+// FetchHTTPS fetches a JSON encoded list of checkpoints from the given url.
+func FetchHTTPS(url string) ([]Checkpoint, error) {
+	client := http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	var checkpoints []Checkpoint
+	if err := json.NewDecoder(resp.Body).Decode(&checkpoints); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode checkpoints from %s", url)
+	}
+	return checkpoints, nil
+}
+
+// FetchDNS fetches the checkpoints published in the TXT records of the given
+// name, one JSON encoded checkpoint per record.
+func FetchDNS(name string) ([]Checkpoint, error) {
+	records, err := net.LookupTXT(name)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := make([]Checkpoint, 0, len(records))
+	for _, record := range records {
+		var cp Checkpoint
+		if err := json.Unmarshal([]byte(record), &cp); err != nil {
+			utils.Logger().Warn().Err(err).
+				Str("name", name).
+				Msg("[CHECKPOINT] ignoring malformed TXT record")
+			continue
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return checkpoints, nil
+}
+
+// Latest returns the checkpoint of the given shard with the highest block
+// number among those signed by one of the trusted signers, or nil if there is
+// none.
+func Latest(
+	checkpoints []Checkpoint, shardID uint32, trusted []common.Address,
+) *Checkpoint {
+	var latest *Checkpoint
+	for i := range checkpoints {
+		cp := &checkpoints[i]
+		if cp.ShardID != shardID {
+			continue
+		}
+		if err := cp.Verify(trusted); err != nil {
+			utils.Logger().Warn().Err(err).
+				Uint64("blockNumber", cp.BlockNumber).
+				Msg("[CHECKPOINT] ignoring checkpoint")
+			continue
+		}
+		if latest == nil || cp.BlockNumber > latest.BlockNumber {
+			latest = cp
+		}
+	}
+	return latest
+}
@@ -8,9 +8,11 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/harmony-one/bls/ffi/go/bls"
 	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
+	"github.com/harmony-one/harmony/internal/genesis"
 	"github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/multibls"
 	"github.com/harmony-one/harmony/shard"
@@ -27,6 +29,9 @@ const (
 	Unknown Role = iota
 	Validator
 	ExplorerNode
+	// RPCNode keeps up with the chain by sync only and serves the RPC, without
+	// consensus nor keys
+	RPCNode
 )
 
 func (role Role) String() string {
@@ -35,6 +40,8 @@ func (role Role) String() string {
 		return "Validator"
 	case ExplorerNode:
 		return "ExplorerNode"
+	case RPCNode:
+		return "RPCNode"
 	default:
 		return "Unknown"
 	}
@@ -62,6 +69,7 @@ const (
 
 var version string
 var publicRPC bool // enable public RPC access
+var genesisSpec *genesis.Spec
 
 // ConfigType is the structure of all node related configuration variables
 type ConfigType struct {
@@ -76,6 +84,7 @@ type ConfigType struct {
 	IP              string  // IP of the node.
 	StringRole      string
 	P2PPriKey       p2p_crypto.PrivKey
+	P2PKeyFile      string // file the P2P identity key is persisted in
 	ConsensusPriKey *multibls.PrivateKey
 	ConsensusPubKey *multibls.PublicKey
 	// Database directory
@@ -83,10 +92,63 @@ type ConfigType struct {
 	networkType      NetworkType
 	shardingSchedule shardingconfig.Schedule
 	DNSZone          string
-	isArchival       bool
-	WebHooks         struct {
+	DNSSeed          string   // DNS name resolved to seed peers for p2p discovery
+	StaticPeers      []string // multiaddrs of the peers always kept connected
+	TrustedPeers     []string // IDs of the peers exempt from rate limiting
+	Sentries         []string // multiaddrs of the sentries a validator hides behind
+	SentryFor        []string // IDs of the validators relayed for as their sentry
+	AdvertiseAddrs   []string // external multiaddrs advertised ahead of the listen addresses
+	// whether the private and loopback listen addresses are not advertised
+	SuppressPrivateAddrs bool
+	isArchival           bool
+	WebHooks             struct {
 		Hooks *webhooks.Hooks
 	}
+
+	// Idle time after which the non-primary shard chains are closed, 0 never
+	ShardChainIdleTimeout time.Duration
+
+	// Blocks whose state is kept by state pruning, 0 for no pruning
+	StatePruneRetention uint64
+	// Longest pause of the block imports for state pruning
+	StatePruneBudget time.Duration
+	// Blocks checked for corruption on startup, 0 for none
+	IntegrityCheckDepth uint64
+
+	// Overrides of the block limits of the sharding schedule, the zero
+	// fields keeping the ones of the schedule
+	BlockLimits shardingconfig.BlockLimits
+	// Transactions executed at once when proposing, 1 or less for a serial
+	// execution
+	TxParallelism int
+
+	// Policy of the sync server for the peers not authenticated by a
+	// committee key, see the downloader package
+	SyncAuthPolicy string
+	// Sync requests per second answered to an anonymous host when limited
+	SyncAnonRate uint
+	// Directory of the chain dumps exported and imported through the API,
+	// empty to disable them
+	ChainDumpDir string
+
+	// Free bytes of the shard chain volumes under which the node is degraded,
+	// 0 for no storage guard
+	StorageMinFree uint64
+	// Latency of a synced write above which the node is degraded, 0 for none
+	StorageMaxWriteLatency time.Duration
+
+	// HTTP(S) endpoints of the archival providers to fetch the blocks from
+	// when the p2p sync makes no progress, empty for none
+	ArchivalProviders []string
+	// Time without sync progress after which the archival providers are used
+	ArchivalFallbackAfter time.Duration
+
+	// Skew of the local clock to the clocks of the sync peers above which the
+	// node refuses to lead, 0 for no limit
+	MaxClockSkew time.Duration
+
+	// How the transactions received are broadcast, see TxBroadcastConfig
+	TxBroadcast TxBroadcastConfig
 }
 
 // configs is a list of node configuration.
@@ -232,6 +294,18 @@ func GetPublicRPC() bool {
 	return publicRPC
 }
 
+// SetGenesisSpec sets the genesis of the private network launched in place of
+// that of the network type
+func SetGenesisSpec(spec *genesis.Spec) {
+	genesisSpec = spec
+}
+
+// GetGenesisSpec returns the genesis of the private network launched, nil for
+// that of the network type
+func GetGenesisSpec() *genesis.Spec {
+	return genesisSpec
+}
+
 // ShardingSchedule returns the sharding schedule for this node config.
 func (conf *ConfigType) ShardingSchedule() shardingconfig.Schedule {
 	return conf.shardingSchedule
@@ -296,18 +370,53 @@ func (conf *ConfigType) ValidateConsensusKeysForSameShard(pubkeys []*bls.PublicK
 
 // ChainConfig returns the chain configuration for the network type.
 func (t NetworkType) ChainConfig() params.ChainConfig {
+	return *t.chainConfig()
+}
+
+// chainConfig returns the chain configuration in effect for the network type.
+func (t NetworkType) chainConfig() *params.ChainConfig {
 	switch t {
 	case Mainnet:
-		return *params.MainnetChainConfig
+		return params.MainnetChainConfig
 	case Pangaea:
-		return *params.PangaeaChainConfig
+		return params.PangaeaChainConfig
 	case Partner:
-		return *params.PartnerChainConfig
+		return params.PartnerChainConfig
 	case Stressnet:
-		return *params.StressnetChainConfig
+		return params.StressnetChainConfig
 	case Localnet:
-		return *params.LocalnetChainConfig
+		return params.LocalnetChainConfig
 	default:
-		return *params.TestnetChainConfig
+		return params.TestnetChainConfig
+	}
+}
+
+// ApplyGenesisSpec replaces the chain configuration of the network type with
+// that of the private network of the genesis spec. The mainnet is not
+// replaced.
+func (t NetworkType) ApplyGenesisSpec(spec *genesis.Spec) error {
+	if t == Mainnet {
+		return errors.New("mainnet genesis cannot be replaced")
+	}
+	*t.chainConfig() = *spec.Config
+	shardingconfig.ResetReshardingEpochs()
+	return nil
+}
+
+// ApplyForkSchedule reschedules the forks of the network type, and returns the
+// chain configuration as it was before. The mainnet forks are only scheduled
+// by releases.
+func (t NetworkType) ApplyForkSchedule(s *params.ForkSchedule) (params.ChainConfig, error) {
+	config := t.chainConfig()
+	base := *config
+	if t == Mainnet {
+		return base, errors.New("mainnet forks cannot be rescheduled")
+	}
+	scheduled, err := s.Apply(config)
+	if err != nil {
+		return base, err
 	}
+	*config = *scheduled
+	shardingconfig.ResetReshardingEpochs()
+	return base, nil
 }
@@ -88,3 +88,29 @@ func TestValidateConsensusKeysForSameShard(t *testing.T) {
 		t.Error("expected", e, "got", nil)
 	}
 }
+
+func TestParseTxBroadcastConfig(t *testing.T) {
+	tests := []struct {
+		networkType     NetworkType
+		clients, fanout string
+		expected        TxBroadcastConfig
+		valid           bool
+	}{
+		{Mainnet, "", "", TxBroadcastConfig{Clients: true, Fanout: TxFanoutAll}, true},
+		{Stressnet, "", "", TxBroadcastConfig{Clients: false, Fanout: TxFanoutLeader}, true},
+		{Mainnet, "false", "leader", TxBroadcastConfig{Clients: false, Fanout: TxFanoutLeader}, true},
+		{"unknown", "", "", TxBroadcastConfig{Fanout: TxFanoutAll}, true},
+		{Mainnet, "maybe", "", TxBroadcastConfig{}, false},
+		{Mainnet, "", "everyone", TxBroadcastConfig{}, false},
+	}
+	for i, test := range tests {
+		cfg, err := ParseTxBroadcastConfig(test.networkType, test.clients, test.fanout)
+		if (err == nil) != test.valid {
+			t.Errorf("test %d: unexpected error %v", i, err)
+			continue
+		}
+		if test.valid && cfg != test.expected {
+			t.Errorf("test %d: got %+v, expected %+v", i, cfg, test.expected)
+		}
+	}
+}
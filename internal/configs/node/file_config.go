@@ -0,0 +1,375 @@
+package nodeconfig
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ConfigEnvPrefix prefixes the environment variables overriding the keys of
+// the configuration file, ex: HMY_NETWORK_PORT for network.port
+const ConfigEnvPrefix = "HMY"
+
+// FileConfig is the declarative configuration of a node, read from a YAML or
+// TOML file. The keys of each section are named after the command line flags
+// they stand for.
+type FileConfig struct {
+	General   GeneralFileConfig   `mapstructure:"general" yaml:"general"`
+	Network   NetworkFileConfig   `mapstructure:"network" yaml:"network"`
+	Consensus ConsensusFileConfig `mapstructure:"consensus" yaml:"consensus"`
+	TxPool    TxPoolFileConfig    `mapstructure:"txpool" yaml:"txpool"`
+	RPC       RPCFileConfig       `mapstructure:"rpc" yaml:"rpc"`
+	Log       LogFileConfig       `mapstructure:"log" yaml:"log"`
+}
+
+// GeneralFileConfig is the general section of the configuration file
+type GeneralFileConfig struct {
+	NodeType    string `mapstructure:"node_type" yaml:"node_type"`
+	NetworkType string `mapstructure:"network_type" yaml:"network_type"`
+	ShardID     int    `mapstructure:"shard_id" yaml:"shard_id"`
+	IsArchival  bool   `mapstructure:"is_archival" yaml:"is_archival"`
+	DBDir       string `mapstructure:"db_dir" yaml:"db_dir"`
+}
+
+// NetworkFileConfig is the p2p network section of the configuration file
+type NetworkFileConfig struct {
+	IP                   string   `mapstructure:"ip" yaml:"ip"`
+	Port                 string   `mapstructure:"port" yaml:"port"`
+	BootNodes            []string `mapstructure:"bootnodes" yaml:"bootnodes"`
+	DNSZone              string   `mapstructure:"dns_zone" yaml:"dns_zone"`
+	DNSSeed              string   `mapstructure:"dns_seed" yaml:"dns_seed"`
+	StaticPeers          []string `mapstructure:"static_peers" yaml:"static_peers"`
+	AdvertiseAddrs       []string `mapstructure:"advertise_addrs" yaml:"advertise_addrs"`
+	SuppressPrivateAddrs bool     `mapstructure:"suppress_private_addrs" yaml:"suppress_private_addrs"`
+	MinPeers             int      `mapstructure:"min_peers" yaml:"min_peers"`
+	KeyFile              string   `mapstructure:"key" yaml:"key"`
+}
+
+// ConsensusFileConfig is the consensus section of the configuration file
+type ConsensusFileConfig struct {
+	BlockPeriod int    `mapstructure:"block_period" yaml:"block_period"`
+	DelayCommit string `mapstructure:"delay_commit" yaml:"delay_commit"`
+	Staking     bool   `mapstructure:"staking" yaml:"staking"`
+	BLSKeyFile  string `mapstructure:"blskey_file" yaml:"blskey_file"`
+	BLSFolder   string `mapstructure:"blsfolder" yaml:"blsfolder"`
+	BLSPass     string `mapstructure:"blspass" yaml:"blspass"`
+}
+
+// TxPoolFileConfig is the transaction pool section of the configuration file
+type TxPoolFileConfig struct {
+	PriceBump    uint   `mapstructure:"txpool_price_bump" yaml:"txpool_price_bump"`
+	AccountSlots uint   `mapstructure:"txpool_account_slots" yaml:"txpool_account_slots"`
+	GlobalSlots  uint   `mapstructure:"txpool_global_slots" yaml:"txpool_global_slots"`
+	AccountQueue uint   `mapstructure:"txpool_account_queue" yaml:"txpool_account_queue"`
+	GlobalQueue  uint   `mapstructure:"txpool_global_queue" yaml:"txpool_global_queue"`
+	Lifetime     string `mapstructure:"txpool_lifetime" yaml:"txpool_lifetime"`
+}
+
+// RPCFileConfig is the RPC section of the configuration file
+type RPCFileConfig struct {
+	Public bool `mapstructure:"public_rpc" yaml:"public_rpc"`
+}
+
+// LogFileConfig is the logging section of the configuration file
+type LogFileConfig struct {
+	Folder     string `mapstructure:"log_folder" yaml:"log_folder"`
+	MaxSize    int    `mapstructure:"log_max_size" yaml:"log_max_size"`
+	MaxAge     string `mapstructure:"log_max_age" yaml:"log_max_age"`
+	MaxBackups int    `mapstructure:"log_max_backups" yaml:"log_max_backups"`
+	Verbosity  int    `mapstructure:"verbosity" yaml:"verbosity"`
+}
+
+// networkDefaults are the seed peers of the public networks
+var networkDefaults = map[NetworkType]NetworkFileConfig{
+	Mainnet: {
+		DNSZone: "t.hmny.io",
+		BootNodes: []string{
+			"/ip4/100.26.90.187/tcp/9874/p2p/Qmdfjtk6hPoyrH1zVD9PEH4zfWLo38dP2mDvvKXfh3tnEv",
+			"/ip4/54.213.43.194/tcp/9874/p2p/QmZJJx6AdaoEkGLrYG4JeLCKeCKDjnFz2wfHNHxAqFSGA9",
+			"/ip4/13.113.101.219/tcp/12019/p2p/QmQayinFSgMMw5cSpDUiD9pQ2WeP6WNmGxpZ6ou3mdVFJX",
+			"/ip4/99.81.170.167/tcp/12019/p2p/QmRVbTpEYup8dSaURZfF6ByrMTSKa4UyUzJhSjahFzRqNj",
+		},
+	},
+	Testnet: {
+		DNSZone: "p.hmny.io",
+		BootNodes: []string{
+			"/ip4/54.218.73.167/tcp/9876/p2p/QmWBVCPXQmc2ULigm3b9ayCZa15gj25kywiQQwPhHCZeXj",
+			"/ip4/18.232.171.117/tcp/9876/p2p/QmfJ71Eb7XTDs8hX2vPJ8un4L7b7RiDk6zCzWVxLXGA6MA",
+		},
+	},
+	Pangaea: {
+		DNSZone: "os.hmny.io",
+		BootNodes: []string{
+			"/ip4/54.86.126.90/tcp/9867/p2p/Qmdfjtk6hPoyrH1zVD9PEH4zfWLo38dP2mDvvKXfh3tnEv",
+			"/ip4/52.40.84.2/tcp/9867/p2p/QmbPVwrqWsTYXq1RxGWcxx9SWaTUCfoo1wA6wmdbduWe29",
+		},
+	},
+	Partner: {
+		DNSZone: "ps.hmny.io",
+		BootNodes: []string{
+			"/ip4/52.40.84.2/tcp/9800/p2p/QmbPVwrqWsTYXq1RxGWcxx9SWaTUCfoo1wA6wmdbduWe29",
+			"/ip4/54.86.126.90/tcp/9800/p2p/Qmdfjtk6hPoyrH1zVD9PEH4zfWLo38dP2mDvvKXfh3tnEv",
+		},
+	},
+	Stressnet: {
+		DNSZone: "stn.hmny.io",
+		BootNodes: []string{
+			"/ip4/52.40.84.2/tcp/9842/p2p/QmbPVwrqWsTYXq1RxGWcxx9SWaTUCfoo1wA6wmdbduWe29",
+		},
+	},
+	Devnet: {
+		DNSZone: "pga.hmny.io",
+		BootNodes: []string{
+			"/ip4/54.86.126.90/tcp/9870/p2p/Qmdfjtk6hPoyrH1zVD9PEH4zfWLo38dP2mDvvKXfh3tnEv",
+			"/ip4/52.40.84.2/tcp/9870/p2p/QmbPVwrqWsTYXq1RxGWcxx9SWaTUCfoo1wA6wmdbduWe29",
+		},
+	},
+}
+
+// DefaultFileConfig returns the configuration of a node of the network type
+// before any file, environment variable or flag applies
+func DefaultFileConfig(networkType NetworkType) FileConfig {
+	cfg := FileConfig{
+		General: GeneralFileConfig{
+			NodeType:    "validator",
+			NetworkType: string(networkType),
+			ShardID:     -1,
+		},
+		Network: NetworkFileConfig{
+			IP:             "127.0.0.1",
+			Port:           "9000",
+			BootNodes:      []string{},
+			StaticPeers:    []string{},
+			AdvertiseAddrs: []string{},
+			MinPeers:       32,
+		},
+		Consensus: ConsensusFileConfig{
+			BlockPeriod: 8,
+			DelayCommit: "0ms",
+			BLSFolder:   ".hmy/blskeys",
+		},
+		TxPool: TxPoolFileConfig{
+			PriceBump:    10,
+			AccountSlots: 16,
+			GlobalSlots:  4096,
+			AccountQueue: 64,
+			GlobalQueue:  1024,
+			Lifetime:     (30 * time.Minute).String(),
+		},
+		Log: LogFileConfig{
+			Folder:    "latest",
+			MaxSize:   100,
+			MaxAge:    "0s",
+			Verbosity: 5,
+		},
+	}
+	if d, ok := networkDefaults[networkType]; ok {
+		cfg.Network.DNSZone = d.DNSZone
+		cfg.Network.BootNodes = append([]string{}, d.BootNodes...)
+	}
+	return cfg
+}
+
+// newConfigViper returns a viper holding the configuration, with the
+// environment variables overriding its keys
+func newConfigViper(cfg FileConfig) (*viper.Viper, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(string(data))); err != nil {
+		return nil, err
+	}
+	v.SetEnvPrefix(ConfigEnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	return v, nil
+}
+
+// LoadFileConfig reads the configuration file, YAML or TOML after its
+// extension, over the defaults of its network type. The environment variables
+// override the keys of the file, the network type included. The keys unknown
+// and the values out of bounds are rejected.
+func LoadFileConfig(path string) (*FileConfig, error) {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, errors.Wrapf(err, "cannot read config file %s", path)
+	}
+	networkType := NetworkType(file.GetString("general.network_type"))
+	if t := os.Getenv(ConfigEnvPrefix + "_GENERAL_NETWORK_TYPE"); t != "" {
+		networkType = NetworkType(t)
+	}
+	if networkType == "" {
+		networkType = Mainnet
+	}
+
+	v, err := newConfigViper(DefaultFileConfig(networkType))
+	if err != nil {
+		return nil, err
+	}
+	// the file is merged as YAML, for its values to have the types of the
+	// defaults whatever the format it is written in
+	data, err := yaml.Marshal(file.AllSettings())
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot merge config file %s", path)
+	}
+	if err := v.MergeConfig(strings.NewReader(string(data))); err != nil {
+		return nil, errors.Wrapf(err, "cannot merge config file %s", path)
+	}
+	cfg := &FileConfig{}
+	if err := v.UnmarshalExact(cfg); err != nil {
+		return nil, errors.Wrapf(err, "invalid config file %s", path)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid config file %s", path)
+	}
+	return cfg, nil
+}
+
+// Validate checks the values of the configuration are within their bounds
+func (cfg *FileConfig) Validate() error {
+	switch cfg.General.NodeType {
+	case "validator", "explorer":
+	default:
+		return errors.Errorf("general.node_type: unknown node type %q", cfg.General.NodeType)
+	}
+	switch cfg.General.NetworkType {
+	case Mainnet, Testnet, Pangaea, Partner, Stressnet, Devnet, Localnet:
+	default:
+		return errors.Errorf("general.network_type: unknown network type %q", cfg.General.NetworkType)
+	}
+	if cfg.General.ShardID < -1 || cfg.General.ShardID >= MaxShards {
+		return errors.Errorf("general.shard_id: %d out of [-1, %d)", cfg.General.ShardID, MaxShards)
+	}
+	if net.ParseIP(cfg.Network.IP) == nil {
+		return errors.Errorf("network.ip: invalid IP %q", cfg.Network.IP)
+	}
+	if port, err := strconv.Atoi(cfg.Network.Port); err != nil || port < 1 || port > 65535 {
+		return errors.Errorf("network.port: invalid port %q", cfg.Network.Port)
+	}
+	for _, addrs := range [][]string{
+		cfg.Network.BootNodes, cfg.Network.StaticPeers, cfg.Network.AdvertiseAddrs,
+	} {
+		for _, addr := range addrs {
+			if _, err := ma.NewMultiaddr(addr); err != nil {
+				return errors.Wrapf(err, "network: invalid multiaddress %q", addr)
+			}
+		}
+	}
+	if cfg.Network.MinPeers < 0 {
+		return errors.Errorf("network.min_peers: negative %d", cfg.Network.MinPeers)
+	}
+	if cfg.Consensus.BlockPeriod < 1 {
+		return errors.Errorf("consensus.block_period: %d below 1 second", cfg.Consensus.BlockPeriod)
+	}
+	if _, err := time.ParseDuration(cfg.Consensus.DelayCommit); err != nil {
+		return errors.Wrap(err, "consensus.delay_commit")
+	}
+	if _, err := time.ParseDuration(cfg.TxPool.Lifetime); err != nil {
+		return errors.Wrap(err, "txpool.txpool_lifetime")
+	}
+	if cfg.Log.MaxSize < 1 {
+		return errors.Errorf("log.log_max_size: %d below 1 megabyte", cfg.Log.MaxSize)
+	}
+	if _, err := time.ParseDuration(cfg.Log.MaxAge); err != nil {
+		return errors.Wrap(err, "log.log_max_age")
+	}
+	if cfg.Log.MaxBackups < 0 {
+		return errors.Errorf("log.log_max_backups: negative %d", cfg.Log.MaxBackups)
+	}
+	if cfg.Log.Verbosity < 0 || cfg.Log.Verbosity > 5 {
+		return errors.Errorf("log.verbosity: %d out of [0, 5]", cfg.Log.Verbosity)
+	}
+	return nil
+}
+
+// FlagValues returns the values of the configuration by the name of the
+// command line flags they stand for, the lists joined with commas
+func (cfg *FileConfig) FlagValues() map[string]string {
+	values := map[string]string{}
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			name := section.Type().Field(j).Tag.Get("mapstructure")
+			switch value := section.Field(j).Interface().(type) {
+			case []string:
+				values[name] = strings.Join(value, ",")
+			default:
+				values[name] = fmt.Sprint(value)
+			}
+		}
+	}
+	return values
+}
+
+// FileConfigFromFlagValues returns the configuration of the given flag values,
+// the flags missing keeping the defaults of the network type
+func FileConfigFromFlagValues(values map[string]string) (*FileConfig, error) {
+	cfg := DefaultFileConfig(NetworkType(values["network_type"]))
+	v, err := newConfigViper(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sections := reflect.TypeOf(cfg)
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		for j := 0; j < section.Type.NumField(); j++ {
+			name := section.Type.Field(j).Tag.Get("mapstructure")
+			if value, ok := values[name]; ok {
+				v.Set(section.Tag.Get("mapstructure")+"."+name, value)
+			}
+		}
+	}
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// String returns the configuration as YAML, the passphrase given inline
+// redacted
+func (cfg *FileConfig) String() string {
+	redacted := *cfg
+	if strings.HasPrefix(redacted.Consensus.BLSPass, "pass:") {
+		redacted.Consensus.BLSPass = "pass:<redacted>"
+	}
+	data, err := yaml.Marshal(redacted)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+var (
+	effectiveConfig     *FileConfig
+	effectiveConfigLock sync.RWMutex
+)
+
+// SetEffectiveConfig sets the configuration the node runs with
+func SetEffectiveConfig(cfg *FileConfig) {
+	effectiveConfigLock.Lock()
+	defer effectiveConfigLock.Unlock()
+	effectiveConfig = cfg
+}
+
+// GetEffectiveConfig returns the configuration the node runs with, nil if
+// not set
+func GetEffectiveConfig() *FileConfig {
+	effectiveConfigLock.RLock()
+	defer effectiveConfigLock.RUnlock()
+	return effectiveConfig
+}
@@ -0,0 +1,108 @@
+package nodeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "hmy-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileConfig(t *testing.T) {
+	path := writeConfigFile(t, "harmony.yaml", `
+general:
+  network_type: testnet
+  shard_id: 1
+network:
+  port: "9100"
+`)
+	os.Setenv("HMY_LOG_VERBOSITY", "3")
+	defer os.Unsetenv("HMY_LOG_VERBOSITY")
+
+	cfg, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.General.ShardID != 1 || cfg.Network.Port != "9100" {
+		t.Errorf("file values not loaded: %+v", cfg)
+	}
+	if cfg.Network.DNSZone != "p.hmny.io" || len(cfg.Network.BootNodes) != 2 {
+		t.Errorf("testnet defaults not applied: %+v", cfg.Network)
+	}
+	if cfg.Log.Verbosity != 3 {
+		t.Errorf("verbosity = %d, expected the environment override 3", cfg.Log.Verbosity)
+	}
+	if v := cfg.FlagValues()["shard_id"]; v != "1" {
+		t.Errorf("shard_id flag value = %q, expected 1", v)
+	}
+}
+
+func TestLoadFileConfigTOML(t *testing.T) {
+	path := writeConfigFile(t, "harmony.toml", `
+[general]
+network_type = "localnet"
+
+[consensus]
+block_period = 5
+`)
+	cfg, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Consensus.BlockPeriod != 5 || cfg.Network.DNSZone != "" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestLoadFileConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":  "network:\n  prot: \"9000\"\n",
+		"bad network":  "general:\n  network_type: moonnet\n",
+		"bad port":     "network:\n  port: \"90000\"\n",
+		"bad shard":    "general:\n  shard_id: 32\n",
+		"bad bootnode": "network:\n  bootnodes: [\"not-a-multiaddr\"]\n",
+	}
+	for name, content := range tests {
+		path := writeConfigFile(t, "harmony.yaml", content)
+		if _, err := LoadFileConfig(path); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
+
+func TestFileConfigFromFlagValues(t *testing.T) {
+	cfg, err := FileConfigFromFlagValues(map[string]string{
+		"network_type": "mainnet",
+		"static_peers": "",
+		"min_peers":    "8",
+		"blspass":      "pass:secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Network.MinPeers != 8 || len(cfg.Network.StaticPeers) != 0 {
+		t.Errorf("unexpected network config: %+v", cfg.Network)
+	}
+	if cfg.Network.DNSZone != "t.hmny.io" {
+		t.Errorf("dns_zone = %q, expected the mainnet default", cfg.Network.DNSZone)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Error(err)
+	}
+	if out := cfg.String(); len(out) == 0 || strings.Contains(out, "secret") {
+		t.Errorf("passphrase not redacted:\n%s", out)
+	}
+}
@@ -27,6 +27,7 @@ const (
 	GroupIDShardClientPrefix GroupID = "%s/0.0.1/client/shard/%s"
 	GroupIDGlobal            GroupID = "%s/0.0.1/node/global"
 	GroupIDGlobalClient      GroupID = "%s/0.0.1/node/global"
+	GroupIDTelemetry         GroupID = "%s/0.0.1/node/telemetry"
 	GroupIDUnknown           GroupID = "%s/B1acKh0lE"
 )
 
@@ -71,6 +72,12 @@ func NewClientGroupIDByShardID(shardID ShardID) GroupID {
 	return GroupID(fmt.Sprintf(GroupIDShardClientPrefix.String(), getNetworkPrefix(shardID), strconv.Itoa(int(shardID))))
 }
 
+// NewTelemetryGroupID returns the groupID the validators publish their head
+// beacons to, shared by all the shards
+func NewTelemetryGroupID() GroupID {
+	return GroupID(fmt.Sprintf(GroupIDTelemetry.String(), getNetworkPrefix(0)))
+}
+
 // ActionType lists action on group
 type ActionType uint
 
@@ -0,0 +1,74 @@
+package nodeconfig
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// TxFanout is the strategy of a node to send the transactions it receives to
+// the validators of their shard
+type TxFanout string
+
+// Constants for TxFanout
+const (
+	// TxFanoutAll publishes the transactions to the group of the shard,
+	// reaching all its validators
+	TxFanoutAll TxFanout = "all"
+	// TxFanoutLeader sends the transactions straight to the leader of the
+	// shard when known, publishing them to the group of the shard otherwise
+	TxFanoutLeader TxFanout = "leader"
+)
+
+// TxBroadcastConfig is how a node broadcasts the transactions it receives
+type TxBroadcastConfig struct {
+	// Whether the transactions are also published to the client group of
+	// their shard, for the explorer and RPC nodes to see them pending
+	Clients bool
+	Fanout  TxFanout
+}
+
+// txBroadcastDefaults are the transaction broadcast of the network types, the
+// networks under load sending the transactions to the leader only
+var txBroadcastDefaults = map[NetworkType]TxBroadcastConfig{
+	Mainnet:   {Clients: true, Fanout: TxFanoutAll},
+	Testnet:   {Clients: true, Fanout: TxFanoutAll},
+	Pangaea:   {Clients: true, Fanout: TxFanoutAll},
+	Partner:   {Clients: true, Fanout: TxFanoutAll},
+	Stressnet: {Clients: false, Fanout: TxFanoutLeader},
+	Devnet:    {Clients: true, Fanout: TxFanoutLeader},
+	Localnet:  {Clients: false, Fanout: TxFanoutAll},
+}
+
+// DefaultTxBroadcastConfig returns the transaction broadcast of the network
+// type, publishing to the group of the shard only for the unknown ones
+func DefaultTxBroadcastConfig(networkType NetworkType) TxBroadcastConfig {
+	if cfg, ok := txBroadcastDefaults[networkType]; ok {
+		return cfg
+	}
+	return TxBroadcastConfig{Fanout: TxFanoutAll}
+}
+
+// ParseTxBroadcastConfig returns the transaction broadcast of the network type
+// overridden by the given client publication, true or false, and fan-out; the
+// empty ones keep the defaults of the network type
+func ParseTxBroadcastConfig(
+	networkType NetworkType, clients, fanout string,
+) (TxBroadcastConfig, error) {
+	cfg := DefaultTxBroadcastConfig(networkType)
+	if clients != "" {
+		b, err := strconv.ParseBool(clients)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "invalid client publication %q", clients)
+		}
+		cfg.Clients = b
+	}
+	switch f := TxFanout(fanout); f {
+	case "":
+	case TxFanoutAll, TxFanoutLeader:
+		cfg.Fanout = f
+	default:
+		return cfg, errors.Errorf("unknown transaction fan-out %q", fanout)
+	}
+	return cfg, nil
+}
@@ -20,7 +20,7 @@ func (s fixedSchedule) InstanceForEpoch(epoch *big.Int) Instance {
 }
 
 func (s fixedSchedule) BlocksPerEpoch() uint64 {
-	return VLBPE
+	return s.instance.BlocksPerEpoch()
 }
 
 func (s fixedSchedule) CalcEpochNumber(blockNum uint64) *big.Int {
@@ -32,6 +32,58 @@ type instance struct {
 	fnAccounts                      []genesis.DeployAccount
 	reshardingEpoch                 []*big.Int
 	blocksPerEpoch                  uint64
+	blockLimits                     map[uint32]BlockLimits
+	headerFirstShards               map[uint32]bool
+}
+
+// BlockLimits are the targets of the size of the blocks proposed in a shard
+type BlockLimits struct {
+	// GasFloor is the gas limit the blocks trend to when not full
+	GasFloor uint64
+	// GasCeil is the gas limit the blocks trend to when full
+	GasCeil uint64
+	// MaxBytes is the largest encoded size of a block, 0 for no limit
+	MaxBytes uint64
+}
+
+// MinBlockHeadroom is the least room kept in a block beside its transactions
+const MinBlockHeadroom = 16 * 1024
+
+// TxBytes returns the room for the transactions in a block, 0 for no limit.
+// An eighth of MaxBytes, and no less than MinBlockHeadroom, is kept for the
+// header with the commit signature and bitmap, the incoming receipts, and the
+// crosslinks, slashes and shard state added once the transactions are in.
+func (l BlockLimits) TxBytes() uint64 {
+	if l.MaxBytes == 0 {
+		return 0
+	}
+	headroom := l.MaxBytes / 8
+	if headroom < MinBlockHeadroom {
+		headroom = MinBlockHeadroom
+	}
+	return l.MaxBytes - headroom
+}
+
+// DefaultBlockLimits are the block limits of the shards without others
+var DefaultBlockLimits = BlockLimits{
+	GasFloor: 80000000,
+	GasCeil:  120000000,
+}
+
+// Validate checks that the block limits are consistent
+func (l BlockLimits) Validate() error {
+	if l.GasFloor == 0 || l.GasCeil < l.GasFloor {
+		return errors.Errorf(
+			"block gas floor %d must be positive and no more than the ceil %d",
+			l.GasFloor, l.GasCeil,
+		)
+	}
+	if l.MaxBytes != 0 && l.MaxBytes <= 2*MinBlockHeadroom {
+		return errors.Errorf(
+			"block size limit %d must be above %d bytes", l.MaxBytes, 2*MinBlockHeadroom,
+		)
+	}
+	return nil
 }
 
 // NewInstance creates and validates a new sharding configuration based
@@ -106,6 +158,63 @@ func MustNewInstance(
 	return sc
 }
 
+// WithBlockLimits returns a copy of the sharding configuration whose shards
+// propose blocks with the given limits, the shards not listed keeping theirs.
+// It panics if the limits are not valid. It is intended to be used for static
+// initialization.
+func WithBlockLimits(sc Instance, limits map[uint32]BlockLimits) Instance {
+	in, ok := sc.(instance)
+	if !ok {
+		panic(errors.Errorf("cannot set block limits of sharding config %T", sc))
+	}
+	merged := make(map[uint32]BlockLimits, len(in.blockLimits)+len(limits))
+	for shardID, l := range in.blockLimits {
+		merged[shardID] = l
+	}
+	for shardID, l := range limits {
+		if err := l.Validate(); err != nil {
+			panic(errors.Wrapf(err, "shard %d", shardID))
+		}
+		merged[shardID] = l
+	}
+	in.blockLimits = merged
+	return in
+}
+
+// BlockLimits returns the limits of the blocks proposed in the given shard
+func (sc instance) BlockLimits(shardID uint32) BlockLimits {
+	if l, ok := sc.blockLimits[shardID]; ok {
+		return l
+	}
+	return DefaultBlockLimits
+}
+
+// WithHeaderFirstProposals returns a copy of the sharding configuration whose
+// given shards propose their blocks header first, the validators fetching the
+// bodies from the leader or their peers. It is intended to be used for static
+// initialization.
+func WithHeaderFirstProposals(sc Instance, shardIDs ...uint32) Instance {
+	in, ok := sc.(instance)
+	if !ok {
+		panic(errors.Errorf("cannot set header first proposals of sharding config %T", sc))
+	}
+	merged := make(map[uint32]bool, len(in.headerFirstShards)+len(shardIDs))
+	for shardID := range in.headerFirstShards {
+		merged[shardID] = true
+	}
+	for _, shardID := range shardIDs {
+		merged[shardID] = true
+	}
+	in.headerFirstShards = merged
+	return in
+}
+
+// HeaderFirstProposals returns whether the given shard proposes its blocks
+// header first
+func (sc instance) HeaderFirstProposals(shardID uint32) bool {
+	return sc.headerFirstShards[shardID]
+}
+
 // BlocksPerEpoch ..
 func (sc instance) BlocksPerEpoch() uint64 {
 	return sc.blocksPerEpoch
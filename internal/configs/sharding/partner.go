@@ -79,7 +79,7 @@ func (ps partnerSchedule) GetShardingStructure(numShard, shardID int) []map[stri
 
 var partnerReshardingEpoch = []*big.Int{
 	big.NewInt(0),
-	params.TestnetChainConfig.StakingEpoch,
+	params.PartnerChainConfig.StakingEpoch,
 }
 
 var partnerV0 = MustNewInstance(2, 15, 15, numeric.OneDec(), genesis.TNHarmonyAccounts, genesis.TNFoundationalAccounts, partnerReshardingEpoch, PartnerSchedule.BlocksPerEpoch())
@@ -9,6 +9,7 @@ import (
 	"github.com/harmony-one/harmony/numeric"
 
 	"github.com/harmony-one/harmony/internal/genesis"
+	"github.com/harmony-one/harmony/internal/params"
 )
 
 // Schedule returns the sharding configuration instance for the given
@@ -77,6 +78,32 @@ type Instance interface {
 
 	// Count of blocks per epoch
 	BlocksPerEpoch() uint64
+
+	// BlockLimits returns the limits of the blocks proposed in the given shard
+	BlockLimits(shardID uint32) BlockLimits
+
+	// HeaderFirstProposals returns whether the given shard proposes its
+	// blocks header first
+	HeaderFirstProposals(shardID uint32) bool
+}
+
+// ResetReshardingEpochs resets the staking epochs in the resharding epochs of
+// the networks other than mainnet to those of their chain configs, once their
+// forks are rescheduled.
+func ResetReshardingEpochs() {
+	for _, r := range []struct {
+		epochs []*big.Int
+		index  int
+		config *params.ChainConfig
+	}{
+		{testnetReshardingEpoch, 1, params.TestnetChainConfig},
+		{partnerReshardingEpoch, 1, params.PartnerChainConfig},
+		{pangaeaReshardingEpoch, 1, params.PangaeaChainConfig},
+		{stressnetReshardingEpoch, 1, params.StressnetChainConfig},
+		{localnetReshardingEpoch, 2, params.LocalnetChainConfig},
+	} {
+		r.epochs[r.index] = r.config.StakingEpoch
+	}
 }
 
 // genShardingStructure return sharding structure, given shard number and its patterns.
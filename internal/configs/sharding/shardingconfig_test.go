@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+
+	"github.com/harmony-one/harmony/internal/params"
 )
 
 func TestMainnetInstanceForEpoch(t *testing.T) {
@@ -118,3 +120,87 @@ func TestGetShardingStructure(t *testing.T) {
 		}
 	}
 }
+
+func TestWithBlockLimits(t *testing.T) {
+	large := BlockLimits{GasFloor: 200000000, GasCeil: 300000000, MaxBytes: 4 << 20}
+	in := WithBlockLimits(localnetV2, map[uint32]BlockLimits{1: large})
+	if in.BlockLimits(1) != large {
+		t.Errorf("expected limits %v for shard 1, got %v", large, in.BlockLimits(1))
+	}
+	if in.BlockLimits(0) != DefaultBlockLimits {
+		t.Errorf("expected default limits for shard 0, got %v", in.BlockLimits(0))
+	}
+	if localnetV2.BlockLimits(1) != DefaultBlockLimits {
+		t.Error("block limits of the original instance modified")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on gas ceil below the floor")
+		}
+	}()
+	WithBlockLimits(localnetV2, map[uint32]BlockLimits{0: {GasFloor: 2, GasCeil: 1}})
+}
+
+func TestBlockLimitsTxBytes(t *testing.T) {
+	for _, test := range []struct {
+		maxBytes, txBytes uint64
+	}{
+		{0, 0},
+		{4 << 20, 4<<20 - 4<<20/8},
+		{64 * 1024, 64*1024 - MinBlockHeadroom},
+	} {
+		limits := BlockLimits{GasFloor: 1, GasCeil: 1, MaxBytes: test.maxBytes}
+		if txBytes := limits.TxBytes(); txBytes != test.txBytes {
+			t.Errorf("expected %d bytes for the transactions of %d bytes blocks, got %d",
+				test.txBytes, test.maxBytes, txBytes)
+		}
+	}
+	if err := (BlockLimits{GasFloor: 1, GasCeil: 1, MaxBytes: 2 * MinBlockHeadroom}).Validate(); err == nil {
+		t.Error("expected a block size limit without room for the transactions rejected")
+	}
+}
+
+func TestWithHeaderFirstProposals(t *testing.T) {
+	in := WithHeaderFirstProposals(localnetV2, 1)
+	if !in.HeaderFirstProposals(1) || in.HeaderFirstProposals(0) {
+		t.Error("expected header first proposals in shard 1 only")
+	}
+	if localnetV2.HeaderFirstProposals(1) {
+		t.Error("header first proposals of the original instance modified")
+	}
+	if in := WithHeaderFirstProposals(in, 0); !in.HeaderFirstProposals(0) || !in.HeaderFirstProposals(1) {
+		t.Error("expected header first proposals in shards 0 and 1")
+	}
+}
+
+func TestResetReshardingEpochs(t *testing.T) {
+	for _, s := range []struct {
+		schedule Schedule
+		config   *params.ChainConfig
+	}{
+		{TestnetSchedule, params.TestnetChainConfig},
+		{PartnerSchedule, params.PartnerChainConfig},
+		{PangaeaSchedule, params.PangaeaChainConfig},
+		{StressNetSchedule, params.StressnetChainConfig},
+		{LocalnetSchedule, params.LocalnetChainConfig},
+	} {
+		base := *s.config
+		rescheduled := base
+		rescheduled.StakingEpoch = big.NewInt(1000)
+		*s.config = rescheduled
+		ResetReshardingEpochs()
+
+		// the instances before and after staking share the resharding epochs
+		epochs := s.schedule.InstanceForEpoch(big.NewInt(0)).ReshardingEpoch()
+		if epochs[len(epochs)-1].Cmp(big.NewInt(1000)) != 0 {
+			t.Errorf("%v: staking epoch not in resharding epochs %v", s.schedule.GetNetworkID(), epochs)
+		}
+
+		*s.config = base
+		ResetReshardingEpochs()
+		if epochs[len(epochs)-1] != base.StakingEpoch {
+			t.Errorf("%v: resharding epochs %v not reset", s.schedule.GetNetworkID(), epochs)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package genesis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	common2 "github.com/harmony-one/harmony/internal/common"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/pkg/errors"
+)
+
+// SpecFile is the name of the genesis spec file in the data directory of a
+// node launching a private network
+const SpecFile = "genesis.json"
+
+// Spec is the genesis of a private network, in place of that of the built-in
+// network type: its chain configuration, its shards and their committees, and
+// the balances of its accounts
+type Spec struct {
+	Config         *params.ChainConfig `json:"config"`
+	NumShards      uint32              `json:"num-shards"`
+	BlocksPerEpoch uint64              `json:"blocks-per-epoch"`
+	// Committee are the nodes of the genesis committees, the same number in
+	// every shard
+	Committee []SpecNode `json:"committee"`
+	// Accounts are the balances in atto of the accounts of the genesis blocks,
+	// by address
+	Accounts  map[string]*big.Int `json:"accounts"`
+	GasLimit  uint64              `json:"gas-limit"`
+	Timestamp uint64              `json:"timestamp"`
+	ExtraData string              `json:"extra-data"`
+}
+
+// SpecNode is a node of the genesis committee of a shard
+type SpecNode struct {
+	Address      string `json:"address"`
+	BLSPublicKey string `json:"bls-public-key"`
+	ShardID      uint32 `json:"shard-id"`
+}
+
+// LoadSpec loads the genesis spec file of the data directory, nil if there is
+// none
+func LoadSpec(dataDir string) (*Spec, error) {
+	path := filepath.Join(dataDir, SpecFile)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, errors.Wrapf(err, "invalid genesis spec %s", path)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid genesis spec %s", path)
+	}
+	return spec, nil
+}
+
+// Validate checks the genesis spec is complete and its committees are evenly
+// spread over its shards
+func (s *Spec) Validate() error {
+	if s.Config == nil || s.Config.ChainID == nil {
+		return errors.New("no chain config")
+	}
+	if s.NumShards < 1 {
+		return errors.New("no shard")
+	}
+	if s.BlocksPerEpoch < 1 {
+		return errors.New("no blocks per epoch")
+	}
+	if s.GasLimit == 0 {
+		return errors.New("no gas limit")
+	}
+	perShard := make([]int, s.NumShards)
+	keys := map[string]struct{}{}
+	for i, node := range s.Committee {
+		if node.ShardID >= s.NumShards {
+			return errors.Errorf("committee node %d in shard %d of %d", i, node.ShardID, s.NumShards)
+		}
+		if !isAddress(node.Address) {
+			return errors.Errorf("committee node %d of invalid address %s", i, node.Address)
+		}
+		pub := bls.PublicKey{}
+		if err := pub.DeserializeHexStr(node.BLSPublicKey); err != nil {
+			return errors.Wrapf(err, "committee node %d of invalid bls public key", i)
+		}
+		if _, ok := keys[node.BLSPublicKey]; ok {
+			return errors.Errorf("committee node %d of duplicate bls public key", i)
+		}
+		keys[node.BLSPublicKey] = struct{}{}
+		perShard[node.ShardID]++
+	}
+	for shardID, n := range perShard {
+		if n == 0 || n != perShard[0] {
+			return errors.Errorf(
+				"%d committee nodes in shard %d, %d in shard 0", n, shardID, perShard[0],
+			)
+		}
+	}
+	for address, balance := range s.Accounts {
+		if !isAddress(address) {
+			return errors.Errorf("invalid account address %s", address)
+		}
+		if balance == nil || balance.Sign() < 0 {
+			return errors.Errorf("invalid balance of account %s", address)
+		}
+	}
+	return nil
+}
+
+// isAddress tells whether the address is in bech32 or in hex
+func isAddress(address string) bool {
+	_, err := common2.Bech32ToAddress(address)
+	return err == nil || common.IsHexAddress(address)
+}
+
+// NumNodesPerShard returns the number of nodes of the genesis committee of
+// each shard
+func (s *Spec) NumNodesPerShard() int {
+	return len(s.Committee) / int(s.NumShards)
+}
+
+// HmyAccounts returns the committee nodes in the order of the sharding
+// instances, the node j of the shard i at i + j*NumShards
+func (s *Spec) HmyAccounts() []DeployAccount {
+	accounts := make([]DeployAccount, len(s.Committee))
+	next := make([]int, s.NumShards)
+	for _, node := range s.Committee {
+		index := int(node.ShardID) + next[node.ShardID]*int(s.NumShards)
+		next[node.ShardID]++
+		accounts[index] = DeployAccount{
+			Index:        strconv.Itoa(index),
+			Address:      node.Address,
+			BLSPublicKey: node.BLSPublicKey,
+			ShardID:      node.ShardID,
+		}
+	}
+	return accounts
+}
+
+// Alloc returns the balances of the accounts of the genesis blocks
+func (s *Spec) Alloc() map[common.Address]*big.Int {
+	alloc := make(map[common.Address]*big.Int, len(s.Accounts))
+	for address, balance := range s.Accounts {
+		alloc[common2.ParseAddr(address)] = balance
+	}
+	return alloc
+}
@@ -0,0 +1,94 @@
+package genesis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	bls2 "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+func testSpec(numShards uint32, committee ...uint32) *Spec {
+	spec := &Spec{
+		Config:         &params.ChainConfig{ChainID: big.NewInt(7)},
+		NumShards:      numShards,
+		BlocksPerEpoch: 64,
+		Accounts: map[string]*big.Int{
+			"0x0000000000000000000000000000000000000001": big.NewInt(100),
+		},
+		GasLimit: 80000000,
+	}
+	for i, shardID := range committee {
+		spec.Committee = append(spec.Committee, SpecNode{
+			Address:      ethCommon.BigToAddress(big.NewInt(int64(i + 2))).Hex(),
+			BLSPublicKey: bls2.RandPrivateKey().GetPublicKey().SerializeToHexStr(),
+			ShardID:      shardID,
+		})
+	}
+	return spec
+}
+
+func TestLoadSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis_spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if spec, err := LoadSpec(dir); spec != nil || err != nil {
+		t.Fatalf("expected no spec without the file, got %v %v", spec, err)
+	}
+
+	data, err := json.Marshal(testSpec(2, 0, 1, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, SpecFile), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadSpec(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.NumNodesPerShard() != 2 || spec.Config.ChainID.Int64() != 7 {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+	for i, account := range spec.HmyAccounts() {
+		if account.ShardID != uint32(i%2) {
+			t.Errorf("account %d of shard %d", i, account.ShardID)
+		}
+	}
+	alloc := spec.Alloc()
+	if balance := alloc[ethCommon.BigToAddress(big.NewInt(1))]; balance == nil || balance.Int64() != 100 {
+		t.Errorf("unexpected alloc %v", alloc)
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	if err := testSpec(2, 0, 1).Validate(); err != nil {
+		t.Errorf("expected a valid spec, got %v", err)
+	}
+	for name, spec := range map[string]*Spec{
+		"uneven shards": testSpec(2, 0, 1, 1),
+		"empty shard":   testSpec(2, 0, 0),
+		"unknown shard": testSpec(1, 0, 1),
+		"no shard":      testSpec(0),
+		"no chain id":   func() *Spec { s := testSpec(1, 0); s.Config.ChainID = nil; return s }(),
+		"duplicate key": func() *Spec {
+			s := testSpec(1, 0, 0)
+			s.Committee[1].BLSPublicKey = s.Committee[0].BLSPublicKey
+			return s
+		}(),
+		"invalid key":     func() *Spec { s := testSpec(1, 0); s.Committee[0].BLSPublicKey = "00"; return s }(),
+		"invalid address": func() *Spec { s := testSpec(1, 0); s.Accounts["one1"] = big.NewInt(1); return s }(),
+	} {
+		if err := spec.Validate(); err == nil {
+			t.Errorf("expected the spec of %s invalid", name)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package apiv1
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	internal_common "github.com/harmony-one/harmony/internal/common"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/node/worker"
+	"github.com/harmony-one/harmony/p2p"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// IdentityRotator rotates the P2P identity of a node
+type IdentityRotator interface {
+	RotateIdentity() (libp2p_peer.ID, error)
+}
+
+// ProposalReplayer proposes anew the past blocks of a node to debug them
+type ProposalReplayer interface {
+	ReplayProposal(blockNum uint64, candidates []common.Hash) (*worker.ProposalReplay, error)
+}
+
+// ChainDumper exports the chain of a node to dumps and imports them
+type ChainDumper interface {
+	ExportChain(shardID uint32, name string, from, to uint64) error
+	ImportChain(name string) error
+	ChainDumpProgress() *core.ChainDumpProgress
+}
+
+// AdminNode is the node administered through the admin API
+type AdminNode interface {
+	IdentityRotator
+	ProposalReplayer
+	ChainDumper
+}
+
+// PeerPinner pins the static and trusted peers of a node, and lists its peers
+// with the metadata they advertised
+type PeerPinner interface {
+	AddStaticPeer(addr ma.Multiaddr) (libp2p_peer.ID, error)
+	RemoveStaticPeer(id libp2p_peer.ID) bool
+	StaticPeers() []libp2p_peer.AddrInfo
+	AddTrustedPeer(id libp2p_peer.ID)
+	RemoveTrustedPeer(id libp2p_peer.ID) bool
+	TrustedPeers() []libp2p_peer.ID
+	ListPeers() []p2p.PeerInfo
+}
+
+// ViewChanger exports the view change state of the consensus and forces view
+// changes approved by the operators
+type ViewChanger interface {
+	ViewChangeState() consensus.ViewChangeState
+	ForceViewChangeHash(viewID uint64) []byte
+	ForceViewChange(viewID uint64, operator *bls.PublicKey, sig *bls.Sign) (int, error)
+}
+
+// PrivateAdminAPI offers node administration RPC methods, served on the local
+// endpoint only
+type PrivateAdminAPI struct {
+	node        AdminNode
+	peers       PeerPinner
+	txPool      *core.TxPool
+	viewChanger ViewChanger
+}
+
+var errNoConsensus = errors.New("the node runs no consensus")
+
+// NewPrivateAdminAPI creates a new admin API instance, viewChanger nil for a
+// node without consensus.
+func NewPrivateAdminAPI(
+	node AdminNode, peers PeerPinner, txPool *core.TxPool, viewChanger ViewChanger,
+) *PrivateAdminAPI {
+	return &PrivateAdminAPI{node, peers, txPool, viewChanger}
+}
+
+// RotateIdentity replaces the P2P identity key of the node and returns the new
+// PeerID, which is used from the next restart of the node
+func (s *PrivateAdminAPI) RotateIdentity() (string, error) {
+	id, err := s.node.RotateIdentity()
+	if err != nil {
+		return "", err
+	}
+	return id.Pretty(), nil
+}
+
+// ReplayProposal proposes anew the block of the given number on the state of
+// its parent, from its transactions and the given candidate ones in the pool,
+// and reports the differences with the block, such as why a transaction was
+// not selected. It is served on the admin endpoint only, as re-executing
+// blocks is costly.
+func (s *PrivateAdminAPI) ReplayProposal(
+	blockNum uint64, candidates []common.Hash,
+) (*worker.ProposalReplay, error) {
+	return s.node.ReplayProposal(blockNum, candidates)
+}
+
+// ExportChain starts writing the canonical blocks from..to of the shard to the
+// dump of the given file name in the chain dump directory of the node, with a
+// manifest holding its checksum. A to of 0 exports up to the head. It is
+// served on the admin endpoint only, as it writes to the disk of the node.
+func (s *PrivateAdminAPI) ExportChain(shardID uint32, name string, from, to uint64) error {
+	return s.node.ExportChain(shardID, name, from, to)
+}
+
+// ImportChain starts inserting the blocks of the dump of the given file name
+// in the chain dump directory of the node, once the dump is checked against
+// its manifest
+func (s *PrivateAdminAPI) ImportChain(name string) error {
+	return s.node.ImportChain(name)
+}
+
+// ChainDumpProgress returns the progress of the last chain export or import,
+// null if none was started
+func (s *PrivateAdminAPI) ChainDumpProgress() *core.ChainDumpProgress {
+	return s.node.ChainDumpProgress()
+}
+
+// AddStaticPeer pins the peer at the given multiaddress, including its PeerID,
+// to be always kept connected, and returns its PeerID
+func (s *PrivateAdminAPI) AddStaticPeer(addr string) (string, error) {
+	maddr, err := ma.NewMultiaddr(addr)
+	if err != nil {
+		return "", err
+	}
+	id, err := s.peers.AddStaticPeer(maddr)
+	if err != nil {
+		return "", err
+	}
+	return id.Pretty(), nil
+}
+
+// RemoveStaticPeer unpins the given static peer, returning false if it was not
+// static
+func (s *PrivateAdminAPI) RemoveStaticPeer(id string) (bool, error) {
+	peerID, err := libp2p_peer.IDB58Decode(id)
+	if err != nil {
+		return false, err
+	}
+	return s.peers.RemoveStaticPeer(peerID), nil
+}
+
+// StaticPeers returns the multiaddresses of the static peers
+func (s *PrivateAdminAPI) StaticPeers() ([]string, error) {
+	addrs := []string{}
+	for _, info := range s.peers.StaticPeers() {
+		p2pAddrs, err := libp2p_peer.AddrInfoToP2pAddrs(&info)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range p2pAddrs {
+			addrs = append(addrs, addr.String())
+		}
+	}
+	return addrs, nil
+}
+
+// AddTrustedPeer exempts the given peer from rate limiting
+func (s *PrivateAdminAPI) AddTrustedPeer(id string) error {
+	peerID, err := libp2p_peer.IDB58Decode(id)
+	if err != nil {
+		return err
+	}
+	s.peers.AddTrustedPeer(peerID)
+	return nil
+}
+
+// RemoveTrustedPeer subjects the given peer to rate limiting again, returning
+// false if it was not trusted
+func (s *PrivateAdminAPI) RemoveTrustedPeer(id string) (bool, error) {
+	peerID, err := libp2p_peer.IDB58Decode(id)
+	if err != nil {
+		return false, err
+	}
+	return s.peers.RemoveTrustedPeer(peerID), nil
+}
+
+// TrustedPeers returns the PeerIDs of the trusted peers
+func (s *PrivateAdminAPI) TrustedPeers() []string {
+	ids := []string{}
+	for _, id := range s.peers.TrustedPeers() {
+		ids = append(ids, id.Pretty())
+	}
+	return ids
+}
+
+// PeerInfo is a connected peer and the metadata it advertised, nil if none
+type PeerInfo struct {
+	PeerID   string        `json:"peerID"`
+	Addrs    []string      `json:"addrs"`
+	Metadata *p2p.Metadata `json:"metadata"`
+}
+
+// ListPeers returns the connected peers along with their software version,
+// role, shard and archival flag, for debugging the network
+func (s *PrivateAdminAPI) ListPeers() []PeerInfo {
+	peers := []PeerInfo{}
+	for _, info := range s.peers.ListPeers() {
+		addrs := make([]string, 0, len(info.Addrs))
+		for _, addr := range info.Addrs {
+			addrs = append(addrs, addr.String())
+		}
+		peers = append(peers, PeerInfo{
+			PeerID:   info.ID.Pretty(),
+			Addrs:    addrs,
+			Metadata: info.Metadata,
+		})
+	}
+	return peers
+}
+
+// TxPoolLimits are the limits of the transaction pool
+type TxPoolLimits struct {
+	PriceBump    *uint64 `json:"priceBump"`
+	AccountSlots *uint64 `json:"accountSlots"`
+	GlobalSlots  *uint64 `json:"globalSlots"`
+	AccountQueue *uint64 `json:"accountQueue"`
+	GlobalQueue  *uint64 `json:"globalQueue"`
+	Lifetime     *string `json:"lifetime"`
+}
+
+func newTxPoolLimits(limits core.TxPoolLimits) TxPoolLimits {
+	lifetime := limits.Lifetime.String()
+	return TxPoolLimits{
+		PriceBump:    &limits.PriceBump,
+		AccountSlots: &limits.AccountSlots,
+		GlobalSlots:  &limits.GlobalSlots,
+		AccountQueue: &limits.AccountQueue,
+		GlobalQueue:  &limits.GlobalQueue,
+		Lifetime:     &lifetime,
+	}
+}
+
+// TxPoolLimits returns the limits of the transaction pool
+func (s *PrivateAdminAPI) TxPoolLimits() TxPoolLimits {
+	return newTxPoolLimits(s.txPool.Limits())
+}
+
+// SetTxPoolLimits changes the given limits of the transaction pool, leaving
+// the omitted ones as they are. The limits are clamped within their bounds;
+// the limits in effect are returned.
+func (s *PrivateAdminAPI) SetTxPoolLimits(args TxPoolLimits) (TxPoolLimits, error) {
+	limits := s.txPool.Limits()
+	if args.PriceBump != nil {
+		limits.PriceBump = *args.PriceBump
+	}
+	if args.AccountSlots != nil {
+		limits.AccountSlots = *args.AccountSlots
+	}
+	if args.GlobalSlots != nil {
+		limits.GlobalSlots = *args.GlobalSlots
+	}
+	if args.AccountQueue != nil {
+		limits.AccountQueue = *args.AccountQueue
+	}
+	if args.GlobalQueue != nil {
+		limits.GlobalQueue = *args.GlobalQueue
+	}
+	if args.Lifetime != nil {
+		lifetime, err := time.ParseDuration(*args.Lifetime)
+		if err != nil {
+			return TxPoolLimits{}, err
+		}
+		limits.Lifetime = lifetime
+	}
+	return newTxPoolLimits(s.txPool.SetLimits(limits)), nil
+}
+
+// EvictNonceGap drops the queued transactions of the given address beyond its
+// first nonce gap, so that they can be submitted again in order, and returns
+// the hashes of the dropped transactions
+func (s *PrivateAdminAPI) EvictNonceGap(addr string) []common.Hash {
+	hashes := []common.Hash{}
+	for _, tx := range s.txPool.EvictNonceGap(internal_common.ParseAddr(addr)) {
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes
+}
+
+// ViewChangeState returns the view change state of the consensus, with the
+// votes collected for the view changing ID
+func (s *PrivateAdminAPI) ViewChangeState() consensus.ViewChangeState {
+	if s.viewChanger == nil {
+		return consensus.ViewChangeState{}
+	}
+	return s.viewChanger.ViewChangeState()
+}
+
+// ForceViewChangeHash returns the hex encoded hash each operator signs with
+// its BLS key to approve forcing the view change to viewID
+func (s *PrivateAdminAPI) ForceViewChangeHash(viewID uint64) string {
+	if s.viewChanger == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.viewChanger.ForceViewChangeHash(viewID))
+}
+
+// ForceViewChange records the approval of the operator, given as the hex
+// encoded BLS public key and signature over ForceViewChangeHash, for forcing
+// the view change to viewID on a stuck shard. The view change starts once
+// the threshold of operators approved it. It returns the number of approvals
+// collected so far, zero once the view change started.
+func (s *PrivateAdminAPI) ForceViewChange(viewID uint64, operator, signature string) (int, error) {
+	if s.viewChanger == nil {
+		return 0, errNoConsensus
+	}
+	key := &bls.PublicKey{}
+	if err := key.DeserializeHexStr(operator); err != nil {
+		return 0, err
+	}
+	sig := &bls.Sign{}
+	if err := sig.DeserializeHexStr(signature); err != nil {
+		return 0, err
+	}
+	return s.viewChanger.ForceViewChange(viewID, key, sig)
+}
+
+// PrintEffectiveConfig returns the configuration the node runs with, as YAML,
+// once the flags, environment variables and configuration file are applied
+func (s *PrivateAdminAPI) PrintEffectiveConfig() (string, error) {
+	cfg := nodeconfig.GetEffectiveConfig()
+	if cfg == nil {
+		return "", errors.New("effective configuration not recorded")
+	}
+	return cfg.String(), nil
+}
+
+// SetLogLevel sets the log level of the node on runtime, one of trace, debug,
+// info, warn, error or disabled; the modules with a level of their own keep it
+func (s *PrivateAdminAPI) SetLogLevel(level string) (string, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return "", err
+	}
+	verbosity := log.LvlTrace
+	switch lvl {
+	case zerolog.Disabled:
+		verbosity = log.LvlCrit
+	case zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel:
+		verbosity = log.LvlError
+	case zerolog.WarnLevel:
+		verbosity = log.LvlWarn
+	case zerolog.InfoLevel:
+		verbosity = log.LvlInfo
+	case zerolog.DebugLevel:
+		verbosity = log.LvlDebug
+	}
+	utils.SetLogVerbosity(verbosity)
+	return verbosity.String(), nil
+}
+
+// SetModuleLogLevel sets the log level of a module (consensus, node, sync, p2p
+// or txpool) on runtime, overriding the log level of the node; disabled
+// filters out the module and an empty level makes it follow the log level of
+// the node
+func (s *PrivateAdminAPI) SetModuleLogLevel(module, level string) (map[string]string, error) {
+	known := false
+	for _, m := range utils.Modules {
+		known = known || m == module
+	}
+	if !known {
+		return nil, errors.Errorf("unknown module %q, expected one of %v", module, utils.Modules)
+	}
+	if level == "" {
+		utils.ResetModuleLogLevel(module)
+		return utils.ModuleLogLevels(), nil
+	}
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetModuleLogLevel(module, lvl)
+	return utils.ModuleLogLevels(), nil
+}
+
+// ModuleLogLevels returns the log levels of the modules with a level of their own
+func (s *PrivateAdminAPI) ModuleLogLevels() map[string]string {
+	return utils.ModuleLogLevels()
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/api/service/absentee"
 	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/consensus/ledger"
 	"github.com/harmony-one/harmony/consensus/quorum"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/state"
@@ -52,11 +55,16 @@ type Backend interface {
 	GetPoolTransactions() (types.PoolTransactions, error)
 	GetPoolTransaction(txHash common.Hash) types.PoolTransaction
 	GetPoolStats() (pendingCount, queuedCount int)
+	GetPoolContent() (pending, queued map[common.Address]types.PoolTransactions)
+	GetPoolConfig() core.TxPoolConfig
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
+	GetPoolNonceGaps(addr common.Address) core.NonceGapReport
+	GetNonceHints(addr common.Address) []commonRPC.ShardNonceHint
 	// Get account nonce
 	GetAccountNonce(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (uint64, error)
 	// TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	SubscribePendingTxsEvent(chan<- core.PendingTxsEvent) event.Subscription
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
 	// Get balance
@@ -70,6 +78,7 @@ type Backend interface {
 	GetStakingTransactionsCount(address, txType string) (uint64, error)
 	// retrieve the blockHash using txID and add blockHash to CxPool for resending
 	ResendCx(ctx context.Context, txID common.Hash) (uint64, bool)
+	GetCXReceiptsProof(ctx context.Context, blockHash common.Hash, toShardID uint32) (*types.CXReceiptsProof, error)
 	IsLeader() bool
 	SendStakingTx(ctx context.Context, newStakingTx *staking.StakingTransaction) error
 	GetElectedValidatorAddresses() []common.Address
@@ -83,11 +92,22 @@ type Backend interface {
 	GetCurrentTransactionErrorSink() types.TransactionErrorReports
 	GetMedianRawStakeSnapshot() (*committee.CompletedEPoSRound, error)
 	GetPendingCXReceipts() []*types.CXReceiptsProof
+	SuggestPrice(ctx context.Context) (*big.Int, error)
 	GetCurrentUtilityMetrics() (*network.UtilityMetric, error)
 	GetSuperCommittees() (*quorum.Transition, error)
+	GetCommitteesByEpoch(epoch *big.Int) (*shard.State, bool, error)
+	GetShardStateProof(epoch *big.Int) (*block.Header, []byte, error)
+	GetRewardEvents(from, to uint64) ([]core.RewardEvent, error)
 	GetTotalStakingSnapshot() *big.Int
 	GetCurrentBadBlocks() []core.BadBlock
 	GetLastCrossLinks() ([]*types.CrossLink, error)
 	GetLatestChainHeaders() *block.HeaderPair
 	GetNodeMetadata() commonRPC.NodeMetadata
+	GetShardHeights() []commonRPC.ShardHeight
+	GetHeadDistribution() []commonRPC.ShardHeads
+	GetLeaderStats() []consensus.LeaderStats
+	GetNextShardAssignment(key shard.BLSPublicKey) (*commonRPC.ShardAssignment, error)
+	GetVoteLedger(from, to uint64) ([]*ledger.Entry, error)
+	GetAbsentees(epoch uint64) (*absentee.Report, error)
+	GetStatePruneProgress() *core.StatePruneProgress
 }
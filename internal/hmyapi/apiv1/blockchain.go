@@ -417,6 +417,16 @@ func (s *PublicBlockChainAPI) ResendCx(ctx context.Context, txID common.Hash) (b
 	return success, nil
 }
 
+// GetCXReceiptsProof returns the proof of the cross-shard receipts sent by the
+// given block of this shard to the destination shard, signed by the committee
+// of the block. It lets external relayers re-deliver the receipts lost on the
+// way, the answering node needing the receipts of the block in its database.
+func (s *PublicBlockChainAPI) GetCXReceiptsProof(
+	ctx context.Context, blockHash common.Hash, toShardID uint32,
+) (*types.CXReceiptsProof, error) {
+	return s.b.GetCXReceiptsProof(ctx, blockHash, toShardID)
+}
+
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
@@ -867,6 +877,66 @@ func (s *PublicBlockChainAPI) GetSuperCommittees() (*quorum.Transition, error) {
 	return nil, errNotBeaconChainShard
 }
 
+// GetCommitteesByEpoch returns the committees of all the shards in the epoch,
+// with the BLS keys, addresses and voting power of their members. The
+// committees of the next epoch are projected from the current stakes until
+// elected.
+func (s *PublicBlockChainAPI) GetCommitteesByEpoch(epoch int64) (*EpochCommittees, error) {
+	if epoch < 0 {
+		return nil, errors.Errorf("invalid epoch %d", epoch)
+	}
+	e := big.NewInt(epoch)
+	state, final, err := s.b.GetCommitteesByEpoch(e)
+	if err != nil {
+		return nil, err
+	}
+	return newEpochCommittees(state, e, final)
+}
+
+// GetShardStateProof returns the shard state of the past epoch, with the
+// committees of all the shards, along with the beacon header holding it and
+// the commit signature on the header as proof.
+func (s *PublicBlockChainAPI) GetShardStateProof(epoch int64) (*ShardStateProof, error) {
+	if epoch < 0 {
+		return nil, errors.Errorf("invalid epoch %d", epoch)
+	}
+	e := big.NewInt(epoch)
+	header, sig, err := s.b.GetShardStateProof(e)
+	if err != nil {
+		return nil, err
+	}
+	return newShardStateProof(header, sig, e)
+}
+
+// GetRewardHistory returns the block rewards and the unlocked undelegations
+// paid out to the address, as a delegator or as a validator, by the blocks in
+// the inclusive range. It requires the reward index of the beacon chain.
+func (s *PublicBlockChainAPI) GetRewardHistory(
+	ctx context.Context, address string, from, to uint64,
+) ([]RPCRewardPayout, error) {
+	addr := internal_common.ParseAddr(address)
+	events, err := s.b.GetRewardEvents(from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := []RPCRewardPayout{}
+	for i := range events {
+		result = append(result, newRPCRewardPayouts(&events[i], addr)...)
+	}
+	return result, nil
+}
+
+// GetValidatorSetSnapshot returns the validator set of the current epoch,
+// from the latest shard state
+func (s *PublicBlockChainAPI) GetValidatorSetSnapshot() (*ValidatorSetSnapshot, error) {
+	blockNum := s.b.CurrentBlock().NumberU64()
+	state, err := s.b.GetShardState()
+	if err != nil {
+		return nil, err
+	}
+	return newValidatorSetSnapshot(state, blockNum)
+}
+
 // GetCurrentBadBlocks ..
 func (s *PublicBlockChainAPI) GetCurrentBadBlocks() []core.BadBlock {
 	return s.b.GetCurrentBadBlocks()
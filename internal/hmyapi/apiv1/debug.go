@@ -5,6 +5,9 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/harmony-one/harmony/api/service/absentee"
+	"github.com/harmony-one/harmony/consensus/ledger"
+	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
@@ -30,3 +33,33 @@ func (*DebugAPI) SetLogVerbosity(ctx context.Context, level int) (map[string]int
 	utils.SetLogVerbosity(verbosity)
 	return map[string]interface{}{"verbosity": verbosity.String()}, nil
 }
+
+// GetVoteLedger Returns the votes recorded in the consensus rounds of the blocks from..to
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"hmy_getVoteLedger","params":[100,200],"id":1}' http://localhost:9500
+func (s *DebugAPI) GetVoteLedger(ctx context.Context, from, to uint64) ([]*ledger.Entry, error) {
+	return s.b.GetVoteLedger(from, to)
+}
+
+// GetVoteLedgerCSV Returns the votes recorded in the consensus rounds of the blocks from..to as CSV
+func (s *DebugAPI) GetVoteLedgerCSV(ctx context.Context, from, to uint64) (string, error) {
+	entries, err := s.b.GetVoteLedger(from, to)
+	if err != nil {
+		return "", err
+	}
+	return ledger.CSV(entries)
+}
+
+// GetAbsentees Returns the committee members absent from the commit signatures of the blocks of the epoch
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"hmy_getAbsentees","params":[10],"id":1}' http://localhost:9500
+func (s *DebugAPI) GetAbsentees(ctx context.Context, epoch uint64) (*absentee.Report, error) {
+	return s.b.GetAbsentees(epoch)
+}
+
+// GetStatePruneProgress Returns the progress of the state pruning, null if the state is not pruned
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"hmy_getStatePruneProgress","params":[],"id":1}' http://localhost:9500
+func (s *DebugAPI) GetStatePruneProgress(ctx context.Context) *core.StatePruneProgress {
+	return s.b.GetStatePruneProgress()
+}
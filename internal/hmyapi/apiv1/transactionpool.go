@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -12,6 +13,8 @@ import (
 	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/types"
 	internal_common "github.com/harmony-one/harmony/internal/common"
+	commonRPC "github.com/harmony-one/harmony/internal/hmyapi/common"
+	"github.com/harmony-one/harmony/shard"
 	staking "github.com/harmony-one/harmony/staking/types"
 	"github.com/pkg/errors"
 )
@@ -337,6 +340,62 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	return fields, nil
 }
 
+// GetTransactionReceiptProof returns the receipt of the given transaction with
+// the Merkle proof of its inclusion in the receipt root of its block, and the
+// commit signature of that block, for light clients to verify the outcome.
+func (s *PublicTransactionPoolAPI) GetTransactionReceiptProof(ctx context.Context, hash common.Hash) (*RPCReceiptProof, error) {
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
+	if tx == nil {
+		var stx *staking.StakingTransaction
+		stx, blockHash, blockNumber, index = rawdb.ReadStakingTransaction(s.b.ChainDb(), hash)
+		if stx == nil {
+			return nil, nil
+		}
+	}
+	block, err := s.b.GetBlock(ctx, blockHash)
+	if block == nil {
+		return nil, err
+	}
+	// The receipts of staking transactions follow those of plain transactions
+	if tx == nil {
+		index += uint64(len(block.Transactions()))
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) <= int(index) {
+		return nil, nil
+	}
+	proof, err := types.DeriveShaProof(uint(index), receipts)
+	if err != nil {
+		return nil, err
+	}
+	result := &RPCReceiptProof{
+		BlockHash:    blockHash,
+		BlockNumber:  hexutil.Uint64(blockNumber),
+		ShardID:      block.ShardID(),
+		TxHash:       hash,
+		ReceiptsRoot: block.ReceiptHash(),
+		Index:        hexutil.Uint(index),
+		Receipt:      receipts.GetRlp(int(index)),
+		Proof:        make([]hexutil.Bytes, len(proof)),
+	}
+	for i := range proof {
+		result.Proof[i] = proof[i]
+	}
+	if sigAndBitmap, err := rawdb.ReadBlockCommitSig(s.b.ChainDb(), blockNumber); err == nil &&
+		len(sigAndBitmap) > shard.BLSSignatureSizeInBytes {
+		result.CommitSig = sigAndBitmap[:shard.BLSSignatureSizeInBytes]
+		result.CommitBitmap = sigAndBitmap[shard.BLSSignatureSizeInBytes:]
+	} else if child, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber+1)); err == nil && child != nil {
+		lastSig := child.Header().LastCommitSignature()
+		result.CommitSig = lastSig[:]
+		result.CommitBitmap = child.Header().LastCommitBitmap()
+	}
+	return result, nil
+}
+
 // GetPoolStats returns stats for the tx-pool
 func (s *PublicTransactionPoolAPI) GetPoolStats() map[string]interface{} {
 	pendingCount, queuedCount := s.b.GetPoolStats()
@@ -346,6 +405,93 @@ func (s *PublicTransactionPoolAPI) GetPoolStats() map[string]interface{} {
 	}
 }
 
+// GetNonceGaps returns the pending and queued nonces of the given address in
+// the transaction pool, and the gaps keeping its queued transactions from
+// being executed.
+func (s *PublicTransactionPoolAPI) GetNonceGaps(ctx context.Context, addr string) core.NonceGapReport {
+	return s.b.GetPoolNonceGaps(internal_common.ParseAddr(addr))
+}
+
+// GetNonceHints returns, for each shard known to the answering node, the nonce
+// of the next transaction of the given address, counting its pending
+// transactions where the node holds the pool of the shard, with warnings when
+// queued transactions wait for missing nonces.
+func (s *PublicTransactionPoolAPI) GetNonceHints(ctx context.Context, addr string) []commonRPC.ShardNonceHint {
+	return s.b.GetNonceHints(internal_common.ParseAddr(addr))
+}
+
+// GetPoolContent returns the pending and queued transactions of the pool,
+// keyed by their sender then their nonce
+func (s *PublicTransactionPoolAPI) GetPoolContent() (*RPCPoolContent, error) {
+	pending, queued := s.b.GetPoolContent()
+	content := &RPCPoolContent{}
+	var err error
+	if content.Pending, err = groupPoolTransactions(pending, newRPCPoolTransaction); err != nil {
+		return nil, err
+	}
+	if content.Queued, err = groupPoolTransactions(queued, newRPCPoolTransaction); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// InspectPool returns the summary of the pending and queued transactions of
+// the pool, keyed by their sender then their nonce
+func (s *PublicTransactionPoolAPI) InspectPool() (*RPCPoolInspect, error) {
+	pending, queued := s.b.GetPoolContent()
+	summarize := func(tx types.PoolTransaction) (interface{}, error) {
+		return summarizePoolTransaction(tx)
+	}
+	inspect := &RPCPoolInspect{
+		Pending: map[string]map[string]string{},
+		Queued:  map[string]map[string]string{},
+	}
+	for _, group := range []struct {
+		txs    map[common.Address]types.PoolTransactions
+		result map[string]map[string]string
+	}{{pending, inspect.Pending}, {queued, inspect.Queued}} {
+		grouped, err := groupPoolTransactions(group.txs, summarize)
+		if err != nil {
+			return nil, err
+		}
+		for addr, txs := range grouped {
+			group.result[addr] = make(map[string]string, len(txs))
+			for nonce, summary := range txs {
+				group.result[addr][nonce] = summary.(string)
+			}
+		}
+	}
+	return inspect, nil
+}
+
+// GetPoolConfig returns the configuration of the transaction pool
+func (s *PublicTransactionPoolAPI) GetPoolConfig() *RPCPoolConfig {
+	return newRPCPoolConfig(s.b.GetPoolConfig())
+}
+
+// groupPoolTransactions converts the transactions of the pool keyed by their
+// bech32 sender then their nonce
+func groupPoolTransactions(
+	txs map[common.Address]types.PoolTransactions,
+	convert func(types.PoolTransaction) (interface{}, error),
+) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{}, len(txs))
+	for addr, accountTxs := range txs {
+		sender, err := internal_common.AddressToBech32(addr)
+		if err != nil {
+			return nil, err
+		}
+		byNonce := make(map[string]interface{}, len(accountTxs))
+		for _, tx := range accountTxs {
+			if byNonce[strconv.FormatUint(tx.Nonce(), 10)], err = convert(tx); err != nil {
+				return nil, err
+			}
+		}
+		result[sender] = byNonce
+	}
+	return result, nil
+}
+
 // PendingTransactions returns the plain transactions that are in the transaction pool
 func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, error) {
 	pending, err := s.b.GetPoolTransactions()
@@ -388,6 +534,34 @@ func (s *PublicTransactionPoolAPI) PendingStakingTransactions() ([]*RPCStakingTr
 	return transactions, nil
 }
 
+// PendingStakingTransactionsByType returns the staking transactions that are
+// in the transaction pool, keyed by their directive
+func (s *PublicTransactionPoolAPI) PendingStakingTransactionsByType() (map[string][]*RPCStakingTransaction, error) {
+	pending, err := s.b.GetPoolTransactions()
+	if err != nil {
+		return nil, err
+	}
+	return stakingTransactionsByType(pending)
+}
+
+// stakingTransactionsByType returns the staking transactions among the pool
+// transactions, keyed by their directive
+func stakingTransactionsByType(pending types.PoolTransactions) (map[string][]*RPCStakingTransaction, error) {
+	transactions := map[string][]*RPCStakingTransaction{}
+	for i := range pending {
+		if _, ok := pending[i].(*types.Transaction); ok {
+			continue // Do not return plain transactions here
+		} else if stakingTx, ok := pending[i].(*staking.StakingTransaction); ok {
+			if tx := newRPCStakingTransaction(stakingTx, common.Hash{}, 0, 0, 0); tx != nil {
+				transactions[tx.Type] = append(transactions[tx.Type], tx)
+			}
+		} else {
+			return nil, types.ErrUnknownPoolTxType
+		}
+	}
+	return transactions, nil
+}
+
 // GetCurrentTransactionErrorSink ..
 func (s *PublicTransactionPoolAPI) GetCurrentTransactionErrorSink() types.TransactionErrorReports {
 	return s.b.GetCurrentTransactionErrorSink()
@@ -408,6 +582,18 @@ func (s *PublicTransactionPoolAPI) GetCXReceiptByHash(
 	return nil
 }
 
+// GetCXDeliveryByHash returns the delivery of the outgoing cross-shard
+// transaction of the given hash, or nil if it is not known to be delivered.
+// The deliveries are tracked only by the nodes enabling it.
+func (s *PublicTransactionPoolAPI) GetCXDeliveryByHash(
+	ctx context.Context, hash common.Hash,
+) *RPCCXDelivery {
+	if d := rawdb.ReadCXDelivery(s.b.ChainDb(), hash); d != nil {
+		return newRPCCXDelivery(d)
+	}
+	return nil
+}
+
 // GetPendingCXReceipts ..
 func (s *PublicTransactionPoolAPI) GetPendingCXReceipts(ctx context.Context) []*types.CXReceiptsProof {
 	return s.b.GetPendingCXReceipts()
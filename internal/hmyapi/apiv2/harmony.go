@@ -3,10 +3,14 @@ package apiv2
 import (
 	"context"
 	"math/big"
+	"strings"
 
+	"github.com/harmony-one/bls/ffi/go/bls"
 	"github.com/harmony-one/harmony/api/proto"
+	"github.com/harmony-one/harmony/consensus"
 	commonRPC "github.com/harmony-one/harmony/internal/hmyapi/common"
 	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/shard"
 )
 
 // PublicHarmonyAPI provides an API to access Harmony related information.
@@ -39,8 +43,7 @@ func (s *PublicHarmonyAPI) Syncing() (interface{}, error) {
 
 // GasPrice returns a suggestion for a gas price.
 func (s *PublicHarmonyAPI) GasPrice(ctx context.Context) (*big.Int, error) {
-	// TODO(ricl): add SuggestPrice API
-	return big.NewInt(1), nil
+	return s.b.SuggestPrice(ctx)
 }
 
 // NodeMetadata captures select metadata of the RPC answering node
@@ -62,3 +65,39 @@ type NodeMetadata struct {
 func (s *PublicHarmonyAPI) GetNodeMetadata() commonRPC.NodeMetadata {
 	return s.b.GetNodeMetadata()
 }
+
+// GetShardHeights returns the heights of the shards synced by the answering RPC node,
+// as reported by the majority of its sync peers
+func (s *PublicHarmonyAPI) GetShardHeights() []commonRPC.ShardHeight {
+	return s.b.GetShardHeights()
+}
+
+// GetHeadDistribution returns the distribution of the heads of each shard, as
+// reported by its validators to the telemetry group. It is empty unless the
+// answering RPC node collects the head beacons.
+func (s *PublicHarmonyAPI) GetHeadDistribution() []commonRPC.ShardHeads {
+	return s.b.GetHeadDistribution()
+}
+
+// GetLeaderStats returns the performance of the leaders of the consensus rounds seen by the
+// answering RPC node since it started: the rounds led, the proposal latency, the view changes
+// and missed proposals, and the share of the rounds led that committed a block, best first.
+func (s *PublicHarmonyAPI) GetLeaderStats() []consensus.LeaderStats {
+	return s.b.GetLeaderStats()
+}
+
+// GetNextShardAssignment returns the shard the given BLS key is elected to serve
+// in the next epoch, for operators to provision the shard before the epoch starts.
+// Until the last block of the current epoch the assignment is projected from the
+// current stakes and may still change.
+func (s *PublicHarmonyAPI) GetNextShardAssignment(key string) (*commonRPC.ShardAssignment, error) {
+	blsKey := &bls.PublicKey{}
+	if err := blsKey.DeserializeHexStr(strings.TrimPrefix(key, "0x")); err != nil {
+		return nil, err
+	}
+	shardKey := shard.BLSPublicKey{}
+	if err := shardKey.FromLibBLSPublicKey(blsKey); err != nil {
+		return nil, err
+	}
+	return s.b.GetNextShardAssignment(shardKey)
+}
@@ -34,12 +34,11 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	}
 	// TODO(ricl): add check for shardID
 	if args.GasPrice == nil {
-		// TODO(ricl): port
-		// price, err := b.SuggestPrice(ctx)
-		// if err != nil {
-		// 	return err
-		// }
-		// args.GasPrice = (*hexutil.Big)(price)
+		price, err := b.SuggestPrice(ctx)
+		if err != nil {
+			return err
+		}
+		args.GasPrice = (*hexutil.Big)(price)
 	}
 	if args.Value == nil {
 		args.Value = new(hexutil.Big)
@@ -0,0 +1,142 @@
+package apiv2
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/harmony/core/types"
+	internal_common "github.com/harmony-one/harmony/internal/common"
+	staking "github.com/harmony-one/harmony/staking/types"
+)
+
+// unknownPoolTx is a pool transaction of neither known type
+type unknownPoolTx struct {
+	*types.Transaction
+}
+
+func stakingTx(t *testing.T, directive staking.Directive, key *ecdsa.PrivateKey, sign bool) *staking.StakingTransaction {
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	tx, err := staking.NewStakingTransaction(0, 1e6, big.NewInt(1), func() (staking.Directive, interface{}) {
+		if directive == staking.DirectiveUndelegate {
+			return directive, staking.Undelegate{DelegatorAddress: addr, ValidatorAddress: addr, Amount: big.NewInt(1)}
+		}
+		return directive, staking.Delegate{DelegatorAddress: addr, ValidatorAddress: addr, Amount: big.NewInt(1)}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sign {
+		return tx
+	}
+	if tx, err = staking.Sign(tx, staking.NewEIP155Signer(tx.ChainID()), key); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestStakingTransactionsByType(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	plain := types.NewTransaction(0, common.Address{1}, 0, big.NewInt(1), 21000, big.NewInt(1), nil)
+	delegate := stakingTx(t, staking.DirectiveDelegate, key, true)
+	undelegate := stakingTx(t, staking.DirectiveUndelegate, key, true)
+
+	for _, test := range []struct {
+		name     string
+		pending  types.PoolTransactions
+		expected map[string]int // transactions of each type
+		err      error
+	}{
+		{"empty pool", nil, map[string]int{}, nil},
+		{"plain transactions only", types.PoolTransactions{plain}, map[string]int{}, nil},
+		{
+			"staking transactions",
+			types.PoolTransactions{delegate, plain, undelegate, delegate},
+			map[string]int{"Delegate": 2, "Undelegate": 1},
+			nil,
+		},
+		{
+			"unsigned staking transaction",
+			types.PoolTransactions{delegate, stakingTx(t, staking.DirectiveDelegate, key, false)},
+			map[string]int{"Delegate": 1},
+			nil,
+		},
+		{"unknown transaction", types.PoolTransactions{delegate, unknownPoolTx{plain}}, nil, types.ErrUnknownPoolTxType},
+	} {
+		transactions, err := stakingTransactionsByType(test.pending)
+		if err != test.err {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.err, err)
+			continue
+		}
+		if len(transactions) != len(test.expected) {
+			t.Errorf("%s: expected %d types, got %d", test.name, len(test.expected), len(transactions))
+		}
+		for typ, count := range test.expected {
+			if len(transactions[typ]) != count {
+				t.Errorf("%s: expected %d %s transactions, got %d", test.name, count, typ, len(transactions[typ]))
+			}
+			for _, tx := range transactions[typ] {
+				if tx.Type != typ {
+					t.Errorf("%s: expected a %s transaction, got %s", test.name, typ, tx.Type)
+				}
+			}
+		}
+	}
+}
+
+func TestGroupPoolTransactions(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	bech32, _ := internal_common.AddressToBech32(sender)
+	plain := func(nonce uint64) types.PoolTransaction {
+		return types.NewTransaction(nonce, common.Address{1}, 0, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	nonce := func(tx types.PoolTransaction) (interface{}, error) {
+		return tx.Nonce(), nil
+	}
+
+	for _, test := range []struct {
+		name     string
+		txs      map[common.Address]types.PoolTransactions
+		convert  func(types.PoolTransaction) (interface{}, error)
+		expected map[string]map[string]interface{}
+		err      error
+	}{
+		{"empty pool", nil, nonce, map[string]map[string]interface{}{}, nil},
+		{
+			"transactions of an account",
+			map[common.Address]types.PoolTransactions{sender: {plain(3), plain(4)}},
+			nonce,
+			map[string]map[string]interface{}{bech32: {"3": uint64(3), "4": uint64(4)}},
+			nil,
+		},
+		{
+			"transaction failing to convert",
+			map[common.Address]types.PoolTransactions{sender: {plain(3), unknownPoolTx{plain(4).(*types.Transaction)}}},
+			newRPCPoolTransaction,
+			nil,
+			types.ErrUnknownPoolTxType,
+		},
+	} {
+		grouped, err := groupPoolTransactions(test.txs, test.convert)
+		if err != test.err {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.err, err)
+			continue
+		}
+		if len(grouped) != len(test.expected) {
+			t.Errorf("%s: expected %d senders, got %d", test.name, len(test.expected), len(grouped))
+		}
+		for addr, txs := range test.expected {
+			if len(grouped[addr]) != len(txs) {
+				t.Errorf("%s: expected %d transactions of %s, got %d", test.name, len(txs), addr, len(grouped[addr]))
+			}
+			for nonce, tx := range txs {
+				if grouped[addr][nonce] != tx {
+					t.Errorf("%s: expected %v at nonce %s, got %v", test.name, tx, nonce, grouped[addr][nonce])
+				}
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package apiv2
 
 import (
 	"encoding/hex"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
@@ -11,10 +12,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/consensus/reward"
+	"github.com/harmony-one/harmony/consensus/votepower"
+	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	internal_common "github.com/harmony-one/harmony/internal/common"
 	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/shard"
 )
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
@@ -68,6 +74,40 @@ type RPCCXReceipt struct {
 	Amount      *big.Int    `json:"value"`
 }
 
+// RPCCXDelivery represents the spending by the destination shard of the
+// receipt of an outgoing cross-shard transaction
+type RPCCXDelivery struct {
+	TxHash      common.Hash `json:"hash"`
+	ToShardID   uint32      `json:"toShardID"`
+	BlockHash   common.Hash `json:"blockHash"`
+	BlockNumber uint64      `json:"blockNumber"`
+}
+
+// newRPCCXDelivery returns a CXDelivery that will serialize to the RPC representation
+func newRPCCXDelivery(d *types.CXDelivery) *RPCCXDelivery {
+	return &RPCCXDelivery{
+		TxHash:      d.TxHash,
+		ToShardID:   d.ToShardID,
+		BlockHash:   d.BlockHash,
+		BlockNumber: d.BlockNum,
+	}
+}
+
+// RPCReceiptProof represents a transaction receipt with the Merkle proof of its
+// inclusion in the receipt root of its block and the commit signature of the block
+type RPCReceiptProof struct {
+	BlockHash    common.Hash     `json:"blockHash"`
+	BlockNumber  uint64          `json:"blockNumber"`
+	ShardID      uint32          `json:"shardID"`
+	TxHash       common.Hash     `json:"transactionHash"`
+	ReceiptsRoot common.Hash     `json:"receiptsRoot"`
+	Index        uint64          `json:"index"`
+	Receipt      hexutil.Bytes   `json:"receipt"`
+	Proof        []hexutil.Bytes `json:"proof"`
+	CommitSig    hexutil.Bytes   `json:"commitSig"`
+	CommitBitmap hexutil.Bytes   `json:"commitBitmap"`
+}
+
 // HeaderInformation represents the latest consensus information
 type HeaderInformation struct {
 	BlockHash        common.Hash `json:"blockHash"`
@@ -508,3 +548,293 @@ type StakingNetworkInfo struct {
 	TotalStaking      *big.Int    `json:"total-staking"`
 	MedianRawStake    numeric.Dec `json:"median-raw-stake"`
 }
+
+// ValidatorSlot is a slot of a committee in a validator set snapshot
+type ValidatorSlot struct {
+	Address        string       `json:"address"`
+	BLSPublicKey   string       `json:"bls-public-key"`
+	EffectiveStake *numeric.Dec `json:"effective-stake"`
+}
+
+// CommitteeSnapshot is the committee of a shard in a validator set snapshot
+type CommitteeSnapshot struct {
+	ShardID    uint32          `json:"shard-id"`
+	Validators int             `json:"validators"` // distinct validator addresses
+	Slots      []ValidatorSlot `json:"slots"`
+}
+
+// ValidatorSetSnapshot is the validator set of the current epoch, from the
+// latest shard state
+type ValidatorSetSnapshot struct {
+	Epoch       *big.Int            `json:"epoch"`
+	BlockNumber uint64              `json:"block-number"`
+	Committees  []CommitteeSnapshot `json:"committees"`
+}
+
+// newValidatorSetSnapshot returns the snapshot of the validator set in the
+// shard state
+func newValidatorSetSnapshot(state *shard.State, blockNum uint64) (*ValidatorSetSnapshot, error) {
+	snapshot := &ValidatorSetSnapshot{
+		Epoch:       state.Epoch,
+		BlockNumber: blockNum,
+		Committees:  make([]CommitteeSnapshot, 0, len(state.Shards)),
+	}
+	for _, committee := range state.Shards {
+		validators := map[common.Address]struct{}{}
+		slots := make([]ValidatorSlot, 0, len(committee.Slots))
+		for _, slot := range committee.Slots {
+			address, err := internal_common.AddressToBech32(slot.EcdsaAddress)
+			if err != nil {
+				return nil, err
+			}
+			validators[slot.EcdsaAddress] = struct{}{}
+			slots = append(slots, ValidatorSlot{
+				Address:        address,
+				BLSPublicKey:   slot.BLSPublicKey.Hex(),
+				EffectiveStake: slot.EffectiveStake,
+			})
+		}
+		snapshot.Committees = append(snapshot.Committees, CommitteeSnapshot{
+			ShardID:    committee.ShardID,
+			Validators: len(validators),
+			Slots:      slots,
+		})
+	}
+	return snapshot, nil
+}
+
+// RPCPoolContent is the content of the transaction pool, the plain and staking
+// transactions keyed by the bech32 address of their sender then their nonce
+type RPCPoolContent struct {
+	Pending map[string]map[string]interface{} `json:"pending"`
+	Queued  map[string]map[string]interface{} `json:"queued"`
+}
+
+// RPCPoolInspect is the summary of the content of the transaction pool, keyed
+// like RPCPoolContent
+type RPCPoolInspect struct {
+	Pending map[string]map[string]string `json:"pending"`
+	Queued  map[string]map[string]string `json:"queued"`
+}
+
+// RPCPoolConfig is the configuration of the transaction pool
+type RPCPoolConfig struct {
+	PriceLimit     uint64 `json:"priceLimit"`
+	PriceBump      uint64 `json:"priceBump"`
+	AccountSlots   uint64 `json:"accountSlots"`
+	GlobalSlots    uint64 `json:"globalSlots"`
+	AccountQueue   uint64 `json:"accountQueue"`
+	GlobalQueue    uint64 `json:"globalQueue"`
+	Lifetime       string `json:"lifetime"`
+	NonceGapEpochs uint64 `json:"nonceGapEpochs"`
+}
+
+// newRPCPoolTransaction returns the RPC representation of the plain or staking
+// transaction of the pool
+func newRPCPoolTransaction(tx types.PoolTransaction) (interface{}, error) {
+	switch tx := tx.(type) {
+	case *types.Transaction:
+		return newRPCTransaction(tx, common.Hash{}, 0, 0, 0), nil
+	case *types2.StakingTransaction:
+		return newRPCStakingTransaction(tx, common.Hash{}, 0, 0, 0), nil
+	}
+	return nil, types.ErrUnknownPoolTxType
+}
+
+// summarizePoolTransaction returns the one line summary of the plain or
+// staking transaction of the pool
+func summarizePoolTransaction(tx types.PoolTransaction) (string, error) {
+	switch tx := tx.(type) {
+	case *types.Transaction:
+		to := "contract creation"
+		if tx.To() != nil {
+			addr, err := internal_common.AddressToBech32(*tx.To())
+			if err != nil {
+				return "", err
+			}
+			to = addr
+		}
+		return fmt.Sprintf("%s: %v wei + %v gas × %v wei", to, tx.Value(), tx.Gas(), tx.GasPrice()), nil
+	case *types2.StakingTransaction:
+		return fmt.Sprintf("%s: %v gas × %v wei", tx.StakingType(), tx.Gas(), tx.GasPrice()), nil
+	}
+	return "", types.ErrUnknownPoolTxType
+}
+
+func newRPCPoolConfig(config core.TxPoolConfig) *RPCPoolConfig {
+	return &RPCPoolConfig{
+		PriceLimit:     config.PriceLimit,
+		PriceBump:      config.PriceBump,
+		AccountSlots:   config.AccountSlots,
+		GlobalSlots:    config.GlobalSlots,
+		AccountQueue:   config.AccountQueue,
+		GlobalQueue:    config.GlobalQueue,
+		Lifetime:       config.Lifetime.String(),
+		NonceGapEpochs: config.NonceGapEpochs,
+	}
+}
+
+// CommitteeMember is a slot of a committee with its voting power
+type CommitteeMember struct {
+	Address          string       `json:"address"`
+	BLSPublicKey     string       `json:"bls-public-key"`
+	EffectiveStake   *numeric.Dec `json:"effective-stake"`
+	IsHarmonyNode    bool         `json:"is-harmony-node"`
+	VotingPower      numeric.Dec  `json:"voting-power"`
+	GroupVotingPower numeric.Dec  `json:"group-voting-power"`
+}
+
+// EpochCommittee is the committee of a shard in an epoch
+type EpochCommittee struct {
+	ShardID uint32            `json:"shard-id"`
+	Members []CommitteeMember `json:"members"`
+}
+
+// EpochCommittees are the committees of all the shards in an epoch, final
+// once elected
+type EpochCommittees struct {
+	Epoch      uint64           `json:"epoch"`
+	Final      bool             `json:"final"`
+	Committees []EpochCommittee `json:"committees"`
+}
+
+// newEpochCommittees returns the committees of the shard state with the
+// voting power of their members in the epoch
+func newEpochCommittees(state *shard.State, epoch *big.Int, final bool) (*EpochCommittees, error) {
+	committees := &EpochCommittees{
+		Epoch:      epoch.Uint64(),
+		Final:      final,
+		Committees: make([]EpochCommittee, 0, len(state.Shards)),
+	}
+	for i := range state.Shards {
+		committee := &state.Shards[i]
+		roster, err := votepower.Compute(committee, epoch)
+		if err != nil {
+			return nil, err
+		}
+		members := make([]CommitteeMember, 0, len(committee.Slots))
+		for _, slot := range committee.Slots {
+			address, err := internal_common.AddressToBech32(slot.EcdsaAddress)
+			if err != nil {
+				return nil, err
+			}
+			member := CommitteeMember{
+				Address:          address,
+				BLSPublicKey:     slot.BLSPublicKey.Hex(),
+				EffectiveStake:   slot.EffectiveStake,
+				IsHarmonyNode:    slot.EffectiveStake == nil,
+				VotingPower:      numeric.ZeroDec(),
+				GroupVotingPower: numeric.ZeroDec(),
+			}
+			if voter, ok := roster.Voters[slot.BLSPublicKey]; ok {
+				member.VotingPower = voter.OverallPercent
+				member.GroupVotingPower = voter.GroupPercent
+			}
+			members = append(members, member)
+		}
+		committees.Committees = append(committees.Committees, EpochCommittee{
+			ShardID: committee.ShardID,
+			Members: members,
+		})
+	}
+	return committees, nil
+}
+
+// ShardStateProof is the shard state of an epoch along with the RLP encoded
+// beacon header holding it and the commit signature on the header, for light
+// clients and bridges to verify the committees of the epoch. The header hashes
+// to the block hash, holds the shard state, and is signed by a quorum of the
+// beacon committee of its own epoch, itself proven by the shard state of the
+// previous epoch. The genesis header holding the first shard state is not
+// signed.
+type ShardStateProof struct {
+	Epoch        uint64           `json:"epoch"`
+	Committees   []EpochCommittee `json:"committees"`
+	ShardState   hexutil.Bytes    `json:"shardState"`
+	BlockNumber  uint64           `json:"blockNumber"`
+	BlockHash    common.Hash      `json:"blockHash"`
+	Header       hexutil.Bytes    `json:"header"`
+	CommitSig    hexutil.Bytes    `json:"commitSig"`
+	CommitBitmap hexutil.Bytes    `json:"commitBitmap"`
+}
+
+// newShardStateProof returns the proof of the shard state of the epoch held by
+// the header, signed with the given commit signature and bitmap
+func newShardStateProof(
+	header *block.Header, sigAndBitmap []byte, epoch *big.Int,
+) (*ShardStateProof, error) {
+	state, err := shard.DecodeWrapper(header.ShardState())
+	if err != nil {
+		return nil, err
+	}
+	committees, err := newEpochCommittees(state, epoch, true)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	proof := &ShardStateProof{
+		Epoch:       epoch.Uint64(),
+		Committees:  committees.Committees,
+		ShardState:  header.ShardState(),
+		BlockNumber: header.Number().Uint64(),
+		BlockHash:   header.Hash(),
+		Header:      encoded,
+	}
+	if len(sigAndBitmap) > shard.BLSSignatureSizeInBytes {
+		proof.CommitSig = sigAndBitmap[:shard.BLSSignatureSizeInBytes]
+		proof.CommitBitmap = sigAndBitmap[shard.BLSSignatureSizeInBytes:]
+	}
+	return proof, nil
+}
+
+// The types of the reward payouts
+const (
+	RewardPayoutReward       = "reward"
+	RewardPayoutUndelegation = "undelegation"
+)
+
+// RPCRewardPayout is an amount paid out to a delegator by a block, as a block
+// reward or an unlocked undelegation
+type RPCRewardPayout struct {
+	BlockNumber      uint64      `json:"block-number"`
+	BlockHash        common.Hash `json:"block-hash"`
+	Epoch            uint64      `json:"epoch"`
+	Type             string      `json:"type"`
+	ValidatorAddress string      `json:"validator-address"`
+	DelegatorAddress string      `json:"delegator-address"`
+	Amount           *big.Int    `json:"amount"`
+}
+
+// newRPCRewardPayouts returns the payouts of the block to the address, as a
+// delegator or as a validator
+func newRPCRewardPayouts(ev *core.RewardEvent, addr common.Address) []RPCRewardPayout {
+	payouts := []RPCRewardPayout{}
+	add := func(typ string, paid reward.DelegatorPayout) {
+		if paid.Delegator != addr && paid.Validator != addr {
+			return
+		}
+		valAddr, _ := internal_common.AddressToBech32(paid.Validator)
+		delAddr, _ := internal_common.AddressToBech32(paid.Delegator)
+		payouts = append(payouts, RPCRewardPayout{
+			BlockNumber:      ev.BlockNum,
+			BlockHash:        ev.BlockHash,
+			Epoch:            ev.Epoch,
+			Type:             typ,
+			ValidatorAddress: valAddr,
+			DelegatorAddress: delAddr,
+			Amount:           paid.Amount,
+		})
+	}
+	for _, payout := range ev.Rewards {
+		for _, paid := range payout.Delegators {
+			add(RewardPayoutReward, paid)
+		}
+	}
+	for _, paid := range ev.Undelegations {
+		add(RewardPayoutUndelegation, paid)
+	}
+	return payouts
+}
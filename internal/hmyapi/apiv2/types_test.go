@@ -0,0 +1,237 @@
+package apiv2
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+	internal_common "github.com/harmony-one/harmony/internal/common"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/shard"
+	staking "github.com/harmony-one/harmony/staking/types"
+)
+
+func TestNewValidatorSetSnapshot(t *testing.T) {
+	stake := numeric.NewDec(100)
+	for _, test := range []struct {
+		name       string
+		state      *shard.State
+		validators []int // distinct validators of each committee
+	}{
+		{"no committee", &shard.State{Epoch: big.NewInt(3)}, []int{}},
+		{
+			"validators with several slots",
+			&shard.State{Epoch: big.NewInt(3), Shards: []shard.Committee{
+				{ShardID: 0, Slots: shard.SlotList{
+					{EcdsaAddress: common.Address{0x11}, BLSPublicKey: shard.BLSPublicKey{0x11}, EffectiveStake: &stake},
+					{EcdsaAddress: common.Address{0x11}, BLSPublicKey: shard.BLSPublicKey{0x12}, EffectiveStake: &stake},
+					{EcdsaAddress: common.Address{0x22}, BLSPublicKey: shard.BLSPublicKey{0x22}},
+				}},
+				{ShardID: 1, Slots: shard.SlotList{
+					{EcdsaAddress: common.Address{0x33}, BLSPublicKey: shard.BLSPublicKey{0x33}},
+				}},
+			}},
+			[]int{2, 1},
+		},
+		{
+			"empty committee",
+			&shard.State{Epoch: big.NewInt(3), Shards: []shard.Committee{{ShardID: 0}}},
+			[]int{0},
+		},
+	} {
+		snapshot, err := newValidatorSetSnapshot(test.state, 42)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if snapshot.Epoch.Cmp(test.state.Epoch) != 0 || snapshot.BlockNumber != 42 {
+			t.Errorf("%s: expected epoch 3 at block 42, got %v at %d", test.name, snapshot.Epoch, snapshot.BlockNumber)
+		}
+		if len(snapshot.Committees) != len(test.validators) {
+			t.Fatalf("%s: expected %d committees, got %d", test.name, len(test.validators), len(snapshot.Committees))
+		}
+		for i, committee := range snapshot.Committees {
+			expected := test.state.Shards[i]
+			if committee.ShardID != expected.ShardID || committee.Validators != test.validators[i] {
+				t.Errorf("%s: expected %d validators in shard %d, got %d in shard %d",
+					test.name, test.validators[i], expected.ShardID, committee.Validators, committee.ShardID)
+			}
+			if len(committee.Slots) != len(expected.Slots) {
+				t.Errorf("%s: expected %d slots, got %d", test.name, len(expected.Slots), len(committee.Slots))
+				continue
+			}
+			for j, slot := range committee.Slots {
+				address, _ := internal_common.AddressToBech32(expected.Slots[j].EcdsaAddress)
+				if slot.Address != address || slot.BLSPublicKey != expected.Slots[j].BLSPublicKey.Hex() ||
+					slot.EffectiveStake != expected.Slots[j].EffectiveStake {
+					t.Errorf("%s: unexpected slot %+v", test.name, slot)
+				}
+			}
+		}
+	}
+}
+
+func TestSummarizePoolTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to, _ := internal_common.AddressToBech32(common.Address{1})
+	for _, test := range []struct {
+		name     string
+		tx       types.PoolTransaction
+		expected string
+		err      error
+	}{
+		{
+			"transfer",
+			types.NewTransaction(0, common.Address{1}, 0, big.NewInt(5), 21000, big.NewInt(2), nil),
+			to + ": 5 wei + 21000 gas × 2 wei",
+			nil,
+		},
+		{
+			"contract creation",
+			types.NewContractCreation(0, 0, big.NewInt(0), 50000, big.NewInt(2), []byte{1}),
+			"contract creation: 0 wei + 50000 gas × 2 wei",
+			nil,
+		},
+		{
+			"staking transaction",
+			stakingTx(t, staking.DirectiveDelegate, key, true),
+			"Delegate: 1000000 gas × 1 wei",
+			nil,
+		},
+		{
+			"unknown transaction",
+			unknownPoolTx{types.NewTransaction(0, common.Address{1}, 0, big.NewInt(5), 21000, big.NewInt(2), nil)},
+			"",
+			types.ErrUnknownPoolTxType,
+		},
+	} {
+		summary, err := summarizePoolTransaction(test.tx)
+		if summary != test.expected || err != test.err {
+			t.Errorf("%s: expected %q, %v, got %q, %v", test.name, test.expected, test.err, summary, err)
+		}
+	}
+}
+
+func TestNewEpochCommittees(t *testing.T) {
+	epoch := big.NewInt(200)
+	instance := shard.Schedule.InstanceForEpoch(epoch)
+	harmony, external := instance.HarmonyVotePercent(), instance.ExternalVotePercent()
+	small, large := numeric.NewDec(100), numeric.NewDec(300)
+	quarter, half := numeric.NewDecWithPrec(25, 2), numeric.NewDecWithPrec(5, 1)
+
+	for _, test := range []struct {
+		name  string
+		slots shard.SlotList
+		group []numeric.Dec // group voting power of each member
+		power []numeric.Dec // overall voting power of each member
+	}{
+		{"empty committee", nil, nil, nil},
+		{
+			"harmony nodes",
+			shard.SlotList{
+				{EcdsaAddress: common.Address{0x11}, BLSPublicKey: shard.BLSPublicKey{0x11}},
+				{EcdsaAddress: common.Address{0x22}, BLSPublicKey: shard.BLSPublicKey{0x22}},
+			},
+			[]numeric.Dec{half, half},
+			[]numeric.Dec{harmony.Quo(numeric.NewDec(2)), harmony.Quo(numeric.NewDec(2))},
+		},
+		{
+			"harmony node and validators",
+			shard.SlotList{
+				{EcdsaAddress: common.Address{0x11}, BLSPublicKey: shard.BLSPublicKey{0x11}},
+				{EcdsaAddress: common.Address{0x22}, BLSPublicKey: shard.BLSPublicKey{0x22}, EffectiveStake: &small},
+				{EcdsaAddress: common.Address{0x33}, BLSPublicKey: shard.BLSPublicKey{0x33}, EffectiveStake: &large},
+			},
+			[]numeric.Dec{numeric.OneDec(), quarter, quarter.Mul(numeric.NewDec(3))},
+			[]numeric.Dec{
+				harmony,
+				quarter.Mul(external),
+				numeric.OneDec().Sub(harmony).Sub(quarter.Mul(external)),
+			},
+		},
+	} {
+		state := &shard.State{Epoch: epoch, Shards: []shard.Committee{{ShardID: 1, Slots: test.slots}}}
+		committees, err := newEpochCommittees(state, epoch, true)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if committees.Epoch != 200 || !committees.Final || len(committees.Committees) != 1 {
+			t.Fatalf("%s: unexpected committees %+v", test.name, committees)
+		}
+		committee := committees.Committees[0]
+		if committee.ShardID != 1 || len(committee.Members) != len(test.slots) {
+			t.Fatalf("%s: expected %d members in shard 1, got %+v", test.name, len(test.slots), committee)
+		}
+		for i, member := range committee.Members {
+			slot := test.slots[i]
+			if member.BLSPublicKey != slot.BLSPublicKey.Hex() || member.IsHarmonyNode != (slot.EffectiveStake == nil) {
+				t.Errorf("%s: unexpected member %+v", test.name, member)
+			}
+			if !member.GroupVotingPower.Equal(test.group[i]) || !member.VotingPower.Equal(test.power[i]) {
+				t.Errorf("%s: expected member %d voting %v of its group and %v overall, got %v and %v",
+					test.name, i, test.group[i], test.power[i], member.GroupVotingPower, member.VotingPower)
+			}
+		}
+	}
+}
+
+func TestNewShardStateProof(t *testing.T) {
+	epoch := big.NewInt(200)
+	state := shard.State{Epoch: epoch, Shards: []shard.Committee{
+		{ShardID: 0, Slots: shard.SlotList{{EcdsaAddress: common.Address{0x11}, BLSPublicKey: shard.BLSPublicKey{0x11}}}},
+		{ShardID: 1, Slots: shard.SlotList{{EcdsaAddress: common.Address{0x22}, BLSPublicKey: shard.BLSPublicKey{0x22}}}},
+	}}
+	encoded, err := shard.EncodeWrapper(state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := make([]byte, shard.BLSSignatureSizeInBytes)
+	sig[0] = 0x33
+
+	for _, test := range []struct {
+		name         string
+		number       int64
+		shardState   []byte
+		sigAndBitmap []byte
+		sig, bitmap  []byte // commit signature and bitmap of the proof
+		err          bool
+	}{
+		{"genesis", 0, encoded, nil, nil, nil, false},
+		{"signed", 1000, encoded, append(append([]byte{}, sig...), 0x01), sig, []byte{0x01}, false},
+		{"signature without bitmap", 1000, encoded, sig, nil, nil, false},
+		{"undecodable shard state", 1000, []byte{0x01}, nil, nil, nil, true},
+	} {
+		header := blockfactory.ForTest.NewHeader(big.NewInt(199))
+		header.SetNumber(big.NewInt(test.number))
+		header.SetShardState(test.shardState)
+		proof, err := newShardStateProof(header, test.sigAndBitmap, epoch)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected an error %t, got %v", test.name, test.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if proof.Epoch != 200 || proof.BlockNumber != uint64(test.number) || proof.BlockHash != header.Hash() ||
+			!bytes.Equal(proof.ShardState, encoded) {
+			t.Errorf("%s: unexpected proof %+v", test.name, proof)
+		}
+		if len(proof.Committees) != 2 || proof.Committees[1].ShardID != 1 ||
+			proof.Committees[1].Members[0].BLSPublicKey != (shard.BLSPublicKey{0x22}).Hex() {
+			t.Errorf("%s: expected the committees of both shards, got %+v", test.name, proof.Committees)
+		}
+		decoded := &block.Header{}
+		if err := rlp.DecodeBytes(proof.Header, decoded); err != nil || decoded.Hash() != header.Hash() {
+			t.Errorf("%s: expected the header encoded, got %v", test.name, err)
+		}
+		if !bytes.Equal(proof.CommitSig, test.sig) || !bytes.Equal(proof.CommitBitmap, test.bitmap) {
+			t.Errorf("%s: expected the commit signature %x and bitmap %x, got %x and %x",
+				test.name, test.sig, test.bitmap, proof.CommitSig, proof.CommitBitmap)
+		}
+	}
+}
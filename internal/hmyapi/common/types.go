@@ -1,6 +1,12 @@
 package common
 
-import "github.com/harmony-one/harmony/internal/params"
+import (
+	"fmt"
+
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/shard"
+)
 
 // NodeMetadata captures select metadata of the RPC answering node
 type NodeMetadata struct {
@@ -17,3 +23,98 @@ type NodeMetadata struct {
 	Archival       bool               `json:"is-archival"`
 	NodeBootTime   int64              `json:"node-unix-start-time"`
 }
+
+// ShardHeight captures the view of the RPC answering node on the head of a shard
+type ShardHeight struct {
+	ShardID   uint32 `json:"shard-id"`
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	MaxHeight uint64 `json:"max-height"`
+	Peers     int    `json:"peers"`
+	UpdatedAt int64  `json:"updated-unix-time"`
+}
+
+// HeadCount is a head of a shard along with the number of its validators
+// reporting it
+type HeadCount struct {
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	Reporters int    `json:"reporters"`
+}
+
+// ShardHeads is the distribution of the heads reported by the validators of a shard
+type ShardHeads struct {
+	ShardID   uint32      `json:"shard-id"`
+	Reporters int         `json:"reporters"`
+	Height    uint64      `json:"height"`
+	MinHeight uint64      `json:"min-height"`
+	MaxHeight uint64      `json:"max-height"`
+	MaxViewID uint64      `json:"max-view-id"`
+	Heads     []HeadCount `json:"heads"`
+	UpdatedAt int64       `json:"updated-unix-time"`
+}
+
+// ShardNonceHint captures the nonce recommended for the next transaction of an
+// account on a shard known to the RPC answering node
+type ShardNonceHint struct {
+	ShardID      uint32   `json:"shard-id"`
+	StateNonce   uint64   `json:"state-nonce"`
+	PendingNonce uint64   `json:"pending-nonce"`
+	NextNonce    uint64   `json:"next-nonce"`
+	PoolKnown    bool     `json:"pool-known"`
+	Warnings     []string `json:"warnings"`
+}
+
+// NewPoolNonceHint returns the nonce hint of an account on a shard whose
+// transaction pool is known, from the nonce gap report of the pool
+func NewPoolNonceHint(shardID uint32, report core.NonceGapReport) ShardNonceHint {
+	next := report.StateNonce
+	if n := len(report.PendingNonces); n > 0 {
+		next = report.PendingNonces[n-1] + 1
+	}
+	hint := ShardNonceHint{
+		ShardID:      shardID,
+		StateNonce:   report.StateNonce,
+		PendingNonce: next,
+		PoolKnown:    true,
+		Warnings:     []string{},
+	}
+	// queued transactions right after the pending ones execute next
+	for _, nonce := range report.QueuedNonces {
+		if nonce == next {
+			next++
+		}
+	}
+	hint.NextNonce = next
+	for _, gap := range report.Gaps {
+		hint.Warnings = append(hint.Warnings, fmt.Sprintf(
+			"queued transactions wait for the missing nonces %d to %d", gap.From, gap.To,
+		))
+	}
+	return hint
+}
+
+// ShardAssignment captures the shard a BLS key is elected to serve in the next epoch
+type ShardAssignment struct {
+	BLSPublicKey string  `json:"blskey"`
+	Epoch        uint64  `json:"epoch"`
+	ShardID      *uint32 `json:"shard-id"`
+	Final        bool    `json:"is-final"`
+}
+
+// NewShardAssignment returns the shard assignment of the key in the committees
+// of the given epoch, its shard left nil if the key is not elected
+func NewShardAssignment(
+	key shard.BLSPublicKey, epoch uint64, state *shard.State, final bool,
+) *ShardAssignment {
+	result := &ShardAssignment{
+		BLSPublicKey: key.Hex(),
+		Epoch:        epoch,
+		Final:        final,
+	}
+	if comm, ok := state.FindCommitteeByKey(key); ok {
+		shardID := comm.ShardID
+		result.ShardID = &shardID
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestNewShardAssignment(t *testing.T) {
+	key0, key1, outsider := shard.BLSPublicKey{0x11}, shard.BLSPublicKey{0x22}, shard.BLSPublicKey{0x33}
+	state := &shard.State{Shards: []shard.Committee{
+		{ShardID: 0, Slots: shard.SlotList{{EcdsaAddress: common.Address{0x11}, BLSPublicKey: key0}}},
+		{ShardID: 1, Slots: shard.SlotList{{EcdsaAddress: common.Address{0x22}, BLSPublicKey: key1}}},
+	}}
+	indexed := &shard.State{Shards: append([]shard.Committee{}, state.Shards...)}
+	indexed.BuildIndex()
+	shard0, shard1 := uint32(0), uint32(1)
+
+	for _, test := range []struct {
+		name     string
+		key      shard.BLSPublicKey
+		state    *shard.State
+		final    bool
+		expected *uint32
+	}{
+		{"elected to the beacon shard", key0, state, true, &shard0},
+		{"elected to shard 1", key1, state, true, &shard1},
+		{"elected in a projected committee", key1, state, false, &shard1},
+		{"elected in an indexed state", key1, indexed, true, &shard1},
+		{"not elected", outsider, state, true, nil},
+		{"no committee", key0, nil, false, nil},
+	} {
+		result := NewShardAssignment(test.key, 5, test.state, test.final)
+		if result.BLSPublicKey != test.key.Hex() || result.Epoch != 5 || result.Final != test.final {
+			t.Errorf("%s: unexpected assignment %+v", test.name, result)
+		}
+		switch {
+		case test.expected == nil && result.ShardID != nil:
+			t.Errorf("%s: expected no shard, got %d", test.name, *result.ShardID)
+		case test.expected != nil && result.ShardID == nil:
+			t.Errorf("%s: expected shard %d, got none", test.name, *test.expected)
+		case test.expected != nil && *result.ShardID != *test.expected:
+			t.Errorf("%s: expected shard %d, got %d", test.name, *test.expected, *result.ShardID)
+		}
+	}
+}
+
+func TestNewPoolNonceHint(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		report   core.NonceGapReport
+		pending  uint64
+		next     uint64
+		warnings int
+	}{
+		{"no transaction", core.NonceGapReport{StateNonce: 4}, 4, 4, 0},
+		{"pending transactions", core.NonceGapReport{StateNonce: 4, PendingNonces: []uint64{4, 5}}, 6, 6, 0},
+		{
+			"queued transactions after the pending ones",
+			core.NonceGapReport{StateNonce: 4, PendingNonces: []uint64{4}, QueuedNonces: []uint64{5, 6}},
+			5, 7, 0,
+		},
+		{
+			"queued transactions after a gap",
+			core.NonceGapReport{
+				StateNonce: 4, PendingNonces: []uint64{4}, QueuedNonces: []uint64{7, 9},
+				Gaps: []core.NonceRange{{From: 5, To: 6}, {From: 8, To: 8}},
+			},
+			5, 5, 2,
+		},
+		{
+			"queued transactions without pending ones",
+			core.NonceGapReport{StateNonce: 4, QueuedNonces: []uint64{4, 5, 7}, Gaps: []core.NonceRange{{From: 6, To: 6}}},
+			4, 6, 1,
+		},
+	} {
+		hint := NewPoolNonceHint(2, test.report)
+		if hint.ShardID != 2 || !hint.PoolKnown || hint.StateNonce != test.report.StateNonce {
+			t.Errorf("%s: unexpected hint %+v", test.name, hint)
+		}
+		if hint.PendingNonce != test.pending || hint.NextNonce != test.next {
+			t.Errorf("%s: expected the pending nonce %d and next nonce %d, got %d and %d",
+				test.name, test.pending, test.next, hint.PendingNonce, hint.NextNonce)
+		}
+		if hint.Warnings == nil || len(hint.Warnings) != test.warnings {
+			t.Errorf("%s: expected %d warnings, got %v", test.name, test.warnings, hint.Warnings)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core/types"
 )
 
@@ -151,6 +152,40 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
+// NewDroppedTransactions creates a subscription that is triggered each time a transaction
+// is dropped from the transaction pool without being executed, e.g. because its nonce gap
+// was never filled.
+func (api *PublicFilterAPI) NewDroppedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		txHashes := make(chan []common.Hash, 128)
+		droppedTxSub := api.events.SubscribeDroppedTxs(txHashes)
+
+		for {
+			select {
+			case hashes := <-txHashes:
+				for _, h := range hashes {
+					notifier.Notify(rpcSub.ID, h)
+				}
+			case <-rpcSub.Err():
+				droppedTxSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				droppedTxSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 //
@@ -216,6 +251,38 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 	return rpcSub, nil
 }
 
+// NewConsensusPhases creates a subscription that is triggered on each transition of the
+// consensus of the shard: FBFT phase changes, finalized blocks, view changes and mode
+// changes, each with the time spent in the previous state.
+func (api *PublicFilterAPI) NewConsensusPhases(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		phases := make(chan consensus.PhaseEvent, 128)
+		phasesSub := api.backend.SubscribeConsensusPhaseEvent(phases)
+
+		for {
+			select {
+			case evt := <-phases:
+				notifier.Notify(rpcSub.ID, evt)
+			case <-rpcSub.Err():
+				phasesSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				phasesSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // GetFilterChanges returns the logs for the filter with the given id since
 // last time it was called. This can be used for polling.
 //
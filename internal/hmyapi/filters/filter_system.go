@@ -52,6 +52,9 @@ const (
 	// PendingTransactionsSubscription queries tx hashes for pending
 	// transactions entering the pending state
 	PendingTransactionsSubscription
+	// DroppedTransactionsSubscription queries tx hashes for transactions
+	// dropped from the transaction pool without being executed
+	DroppedTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
 	// LastIndexSubscription keeps track of the last index
@@ -60,9 +63,11 @@ const (
 
 const (
 
-	// txChanSize is the size of channel listening to NewTxsEvent.
+	// txChanSize is the size of channel listening to PendingTxsEvent.
 	// The number is referenced from the size of tx pool.
 	txChanSize = 4096
+	// droppedTxChanSize is the size of channel listening to DroppedTxsEvent.
+	droppedTxChanSize = 128
 	// rmLogsChanSize is the size of channel listening to RemovedLogsEvent.
 	rmLogsChanSize = 10
 	// logsChanSize is the size of channel listening to LogsEvent.
@@ -92,7 +97,8 @@ type EventSystem struct {
 	lastHead  *block.Header
 
 	// Subscriptions
-	txsSub        event.Subscription         // Subscription for new transaction event
+	txsSub        event.Subscription         // Subscription for pending transaction event
+	droppedTxsSub event.Subscription         // Subscription for dropped transaction event
 	logsSub       event.Subscription         // Subscription for new log event
 	rmLogsSub     event.Subscription         // Subscription for removed log event
 	chainSub      event.Subscription         // Subscription for new chain event
@@ -101,7 +107,8 @@ type EventSystem struct {
 	// Channels
 	install   chan *subscription         // install filter for event notification
 	uninstall chan *subscription         // remove filter for event notification
-	txsCh     chan core.NewTxsEvent      // Channel to receive new transactions event
+	txsCh     chan core.PendingTxsEvent  // Channel to receive pending transactions event
+	droppedCh chan core.DroppedTxsEvent  // Channel to receive dropped transactions event
 	logsCh    chan []*types.Log          // Channel to receive new log event
 	rmLogsCh  chan core.RemovedLogsEvent // Channel to receive removed log event
 	chainCh   chan core.ChainEvent       // Channel to receive new chain event
@@ -120,14 +127,16 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 		lightMode: lightMode,
 		install:   make(chan *subscription),
 		uninstall: make(chan *subscription),
-		txsCh:     make(chan core.NewTxsEvent, txChanSize),
+		txsCh:     make(chan core.PendingTxsEvent, txChanSize),
+		droppedCh: make(chan core.DroppedTxsEvent, droppedTxChanSize),
 		logsCh:    make(chan []*types.Log, logsChanSize),
 		rmLogsCh:  make(chan core.RemovedLogsEvent, rmLogsChanSize),
 		chainCh:   make(chan core.ChainEvent, chainEvChanSize),
 	}
 
 	// Subscribe events
-	m.txsSub = m.backend.SubscribeNewTxsEvent(m.txsCh)
+	m.txsSub = m.backend.SubscribePendingTxsEvent(m.txsCh)
+	m.droppedTxsSub = m.backend.SubscribeDroppedTxsEvent(m.droppedCh)
 	m.logsSub = m.backend.SubscribeLogsEvent(m.logsCh)
 	m.rmLogsSub = m.backend.SubscribeRemovedLogsEvent(m.rmLogsCh)
 	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
@@ -135,7 +144,7 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 	m.pendingLogSub = m.mux.Subscribe(core.PendingLogsEvent{})
 
 	// Make sure none of the subscriptions are empty
-	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil ||
+	if m.txsSub == nil || m.droppedTxsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil ||
 		m.pendingLogSub.Closed() {
 		log.Crit("Subscribe for event system failed")
 	}
@@ -311,6 +320,22 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscript
 	return es.subscribe(sub)
 }
 
+// SubscribeDroppedTxs creates a subscription that writes transaction hashes for
+// transactions dropped from the transaction pool without being executed.
+func (es *EventSystem) SubscribeDroppedTxs(hashes chan []common.Hash) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       DroppedTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    hashes,
+		headers:   make(chan *block.Header),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
 type filterIndex map[Type]map[rpc.ID]*subscription
 
 // broadcast event to filters that match criteria.
@@ -344,12 +369,23 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 				}
 			}
 		}
-	case core.NewTxsEvent:
+	case core.PendingTxsEvent:
+		if len(e.Added) == 0 {
+			break
+		}
+		hashes := make([]common.Hash, 0, len(e.Added))
+		for _, tx := range e.Added {
+			hashes = append(hashes, tx.Hash())
+		}
+		for _, f := range filters[PendingTransactionsSubscription] {
+			f.hashes <- hashes
+		}
+	case core.DroppedTxsEvent:
 		hashes := make([]common.Hash, 0, len(e.Txs))
 		for _, tx := range e.Txs {
 			hashes = append(hashes, tx.Hash())
 		}
-		for _, f := range filters[PendingTransactionsSubscription] {
+		for _, f := range filters[DroppedTransactionsSubscription] {
 			f.hashes <- hashes
 		}
 	case core.ChainEvent:
@@ -447,6 +483,7 @@ func (es *EventSystem) eventLoop() {
 	defer func() {
 		es.pendingLogSub.Unsubscribe()
 		es.txsSub.Unsubscribe()
+		es.droppedTxsSub.Unsubscribe()
 		es.logsSub.Unsubscribe()
 		es.rmLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
@@ -462,6 +499,8 @@ func (es *EventSystem) eventLoop() {
 		// Handle subscribed events
 		case ev := <-es.txsCh:
 			es.broadcast(index, ev)
+		case ev := <-es.droppedCh:
+			es.broadcast(index, ev)
 		case ev := <-es.logsCh:
 			es.broadcast(index, ev)
 		case ev := <-es.rmLogsCh:
@@ -497,6 +536,8 @@ func (es *EventSystem) eventLoop() {
 		// System stopped
 		case <-es.txsSub.Err():
 			return
+		case <-es.droppedTxsSub.Err():
+			return
 		case <-es.logsSub.Err():
 			return
 		case <-es.rmLogsSub.Err():
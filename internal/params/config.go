@@ -25,77 +25,95 @@ var EpochTBD = big.NewInt(10000000)
 var (
 	// MainnetChainConfig is the chain parameters to run a node on the main network.
 	MainnetChainConfig = &ChainConfig{
-		ChainID:         MainnetChainID,
-		CrossTxEpoch:    big.NewInt(28),
-		CrossLinkEpoch:  EpochTBD,
-		StakingEpoch:    EpochTBD,
-		PreStakingEpoch: EpochTBD,
-		EIP155Epoch:     big.NewInt(28),
-		S3Epoch:         big.NewInt(28),
-		ReceiptLogEpoch: big.NewInt(101),
+		ChainID:                MainnetChainID,
+		CrossTxEpoch:           big.NewInt(28),
+		CrossLinkEpoch:         EpochTBD,
+		StakingEpoch:           EpochTBD,
+		PreStakingEpoch:        EpochTBD,
+		EIP155Epoch:            big.NewInt(28),
+		S3Epoch:                big.NewInt(28),
+		ReceiptLogEpoch:        big.NewInt(101),
+		SignedBeaconSyncEpoch:  EpochTBD,
+		StrictMessageEpoch:     EpochTBD,
+		VRFLeaderElectionEpoch: EpochTBD,
 	}
 
 	// TestnetChainConfig contains the chain parameters to run a node on the harmony test network.
 	TestnetChainConfig = &ChainConfig{
-		ChainID:         TestnetChainID,
-		CrossTxEpoch:    big.NewInt(0),
-		CrossLinkEpoch:  big.NewInt(4),
-		StakingEpoch:    big.NewInt(4),
-		PreStakingEpoch: big.NewInt(2),
-		EIP155Epoch:     big.NewInt(0),
-		S3Epoch:         big.NewInt(0),
-		ReceiptLogEpoch: big.NewInt(0),
+		ChainID:                TestnetChainID,
+		CrossTxEpoch:           big.NewInt(0),
+		CrossLinkEpoch:         big.NewInt(4),
+		StakingEpoch:           big.NewInt(4),
+		PreStakingEpoch:        big.NewInt(2),
+		EIP155Epoch:            big.NewInt(0),
+		S3Epoch:                big.NewInt(0),
+		ReceiptLogEpoch:        big.NewInt(0),
+		SignedBeaconSyncEpoch:  EpochTBD,
+		StrictMessageEpoch:     EpochTBD,
+		VRFLeaderElectionEpoch: EpochTBD,
 	}
 
 	// PangaeaChainConfig contains the chain parameters for the Pangaea network.
 	// All features except for CrossLink are enabled at launch.
 	PangaeaChainConfig = &ChainConfig{
-		ChainID:         PangaeaChainID,
-		CrossTxEpoch:    big.NewInt(0),
-		CrossLinkEpoch:  big.NewInt(2),
-		StakingEpoch:    big.NewInt(2),
-		PreStakingEpoch: big.NewInt(1),
-		EIP155Epoch:     big.NewInt(0),
-		S3Epoch:         big.NewInt(0),
-		ReceiptLogEpoch: big.NewInt(0),
+		ChainID:                PangaeaChainID,
+		CrossTxEpoch:           big.NewInt(0),
+		CrossLinkEpoch:         big.NewInt(2),
+		StakingEpoch:           big.NewInt(2),
+		PreStakingEpoch:        big.NewInt(1),
+		EIP155Epoch:            big.NewInt(0),
+		S3Epoch:                big.NewInt(0),
+		ReceiptLogEpoch:        big.NewInt(0),
+		SignedBeaconSyncEpoch:  EpochTBD,
+		StrictMessageEpoch:     EpochTBD,
+		VRFLeaderElectionEpoch: EpochTBD,
 	}
 
 	// PartnerChainConfig contains the chain parameters for the Partner network.
 	// All features except for CrossLink are enabled at launch.
 	PartnerChainConfig = &ChainConfig{
-		ChainID:         PartnerChainID,
-		CrossTxEpoch:    big.NewInt(0),
-		CrossLinkEpoch:  big.NewInt(2),
-		StakingEpoch:    big.NewInt(2),
-		PreStakingEpoch: big.NewInt(1),
-		EIP155Epoch:     big.NewInt(0),
-		S3Epoch:         big.NewInt(0),
-		ReceiptLogEpoch: big.NewInt(0),
+		ChainID:                PartnerChainID,
+		CrossTxEpoch:           big.NewInt(0),
+		CrossLinkEpoch:         big.NewInt(2),
+		StakingEpoch:           big.NewInt(2),
+		PreStakingEpoch:        big.NewInt(1),
+		EIP155Epoch:            big.NewInt(0),
+		S3Epoch:                big.NewInt(0),
+		ReceiptLogEpoch:        big.NewInt(0),
+		SignedBeaconSyncEpoch:  EpochTBD,
+		StrictMessageEpoch:     EpochTBD,
+		VRFLeaderElectionEpoch: EpochTBD,
 	}
 
 	// StressnetChainConfig contains the chain parameters for the Stress test network.
 	// All features except for CrossLink are enabled at launch.
 	StressnetChainConfig = &ChainConfig{
-		ChainID:         StressnetChainID,
-		CrossTxEpoch:    big.NewInt(0),
-		CrossLinkEpoch:  big.NewInt(2),
-		StakingEpoch:    big.NewInt(2),
-		PreStakingEpoch: big.NewInt(1),
-		EIP155Epoch:     big.NewInt(0),
-		S3Epoch:         big.NewInt(0),
-		ReceiptLogEpoch: big.NewInt(0),
+		ChainID:                StressnetChainID,
+		CrossTxEpoch:           big.NewInt(0),
+		CrossLinkEpoch:         big.NewInt(2),
+		StakingEpoch:           big.NewInt(2),
+		PreStakingEpoch:        big.NewInt(1),
+		EIP155Epoch:            big.NewInt(0),
+		S3Epoch:                big.NewInt(0),
+		ReceiptLogEpoch:        big.NewInt(0),
+		SignedBeaconSyncEpoch:  EpochTBD,
+		StrictMessageEpoch:     EpochTBD,
+		VRFLeaderElectionEpoch: EpochTBD,
 	}
 
 	// LocalnetChainConfig contains the chain parameters to run for local development.
 	LocalnetChainConfig = &ChainConfig{
-		ChainID:         TestnetChainID,
-		CrossTxEpoch:    big.NewInt(0),
-		CrossLinkEpoch:  big.NewInt(2),
-		StakingEpoch:    big.NewInt(2),
-		PreStakingEpoch: big.NewInt(0),
-		EIP155Epoch:     big.NewInt(0),
-		S3Epoch:         big.NewInt(0),
-		ReceiptLogEpoch: big.NewInt(0),
+		ChainID:                TestnetChainID,
+		CrossTxEpoch:           big.NewInt(0),
+		CrossLinkEpoch:         big.NewInt(2),
+		StakingEpoch:           big.NewInt(2),
+		PreStakingEpoch:        big.NewInt(0),
+		EIP155Epoch:            big.NewInt(0),
+		S3Epoch:                big.NewInt(0),
+		ReceiptLogEpoch:        big.NewInt(0),
+		SignedBeaconSyncEpoch:  big.NewInt(0),
+		StrictMessageEpoch:     big.NewInt(0),
+		VRFLeaderElectionEpoch: EpochTBD,
 	}
 
 	// AllProtocolChanges ...
@@ -110,6 +128,9 @@ var (
 		big.NewInt(0),             // EIP155Epoch
 		big.NewInt(0),             // S3Epoch
 		big.NewInt(0),             // ReceiptLogEpoch
+		big.NewInt(0),             // SignedBeaconSyncEpoch
+		big.NewInt(0),             // StrictMessageEpoch
+		big.NewInt(0),             // VRFLeaderElectionEpoch
 	}
 
 	// TestChainConfig ...
@@ -124,6 +145,9 @@ var (
 		big.NewInt(0), // EIP155Epoch
 		big.NewInt(0), // S3Epoch
 		big.NewInt(0), // ReceiptLogEpoch
+		big.NewInt(0), // SignedBeaconSyncEpoch
+		big.NewInt(0), // StrictMessageEpoch
+		big.NewInt(0), // VRFLeaderElectionEpoch
 	}
 
 	// TestRules ...
@@ -173,6 +197,21 @@ type ChainConfig struct {
 
 	// ReceiptLogEpoch is the first epoch support receiptlog
 	ReceiptLogEpoch *big.Int `json:"receipt-log-epoch,omitempty"`
+
+	// SignedBeaconSyncEpoch is the first epoch whose beacon blocks are
+	// broadcast along with their commit signature. The unsigned beacon blocks
+	// are still accepted in this epoch, for the nodes to upgrade.
+	SignedBeaconSyncEpoch *big.Int `json:"signed-beacon-sync-epoch,omitempty"`
+
+	// StrictMessageEpoch is the first epoch whose consensus messages must
+	// carry the epoch of their sender. The messages without epoch, of the
+	// nodes not upgraded yet, are accepted before it.
+	StrictMessageEpoch *big.Int `json:"strict-message-epoch,omitempty"`
+
+	// VRFLeaderElectionEpoch is the first epoch whose block leaders are
+	// elected from the VRF output of the parent block, weighted by voting
+	// power, instead of rotated through the committee by the view changes.
+	VRFLeaderElectionEpoch *big.Int `json:"vrf-leader-election-epoch,omitempty"`
 }
 
 // String implements the fmt.Stringer interface.
@@ -237,6 +276,39 @@ func (c *ChainConfig) IsReceiptLog(epoch *big.Int) bool {
 	return isForked(c.ReceiptLogEpoch, epoch)
 }
 
+// IsSignedBeaconSync returns whether the beacon blocks of the epoch are
+// broadcast along with their commit signature.
+func (c *ChainConfig) IsSignedBeaconSync(epoch *big.Int) bool {
+	return isForked(c.SignedBeaconSyncEpoch, epoch)
+}
+
+// AcceptsUnsignedBeaconSync returns whether the beacon blocks of the epoch are
+// accepted when broadcast without their commit signature, in the legacy
+// format.
+//
+// The unsigned beacon blocks are accepted up to SignedBeaconSyncEpoch, which
+// is the transition epoch: the nodes not upgraded yet still broadcast them,
+// and they are rejected from SignedBeaconSyncEpoch+1 and on.
+func (c *ChainConfig) AcceptsUnsignedBeaconSync(epoch *big.Int) bool {
+	if c.SignedBeaconSyncEpoch == nil {
+		return true
+	}
+	signedOnly := new(big.Int).Add(c.SignedBeaconSyncEpoch, common.Big1)
+	return !isForked(signedOnly, epoch)
+}
+
+// IsStrictMessageEpoch returns whether the consensus messages of the epoch
+// must carry the epoch of their sender.
+func (c *ChainConfig) IsStrictMessageEpoch(epoch *big.Int) bool {
+	return isForked(c.StrictMessageEpoch, epoch)
+}
+
+// IsVRFLeaderElection returns whether the block leaders of the epoch are
+// elected from the VRF output of the parent block.
+func (c *ChainConfig) IsVRFLeaderElection(epoch *big.Int) bool {
+	return isForked(c.VRFLeaderElectionEpoch, epoch)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
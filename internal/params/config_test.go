@@ -0,0 +1,34 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSignedBeaconSync(t *testing.T) {
+	config := &ChainConfig{SignedBeaconSyncEpoch: big.NewInt(5)}
+	for _, test := range []struct {
+		epoch            int64
+		signed, unsigned bool
+	}{
+		{4, false, true},
+		// the transition epoch accepts both
+		{5, true, true},
+		{6, true, false},
+		{100, true, false},
+	} {
+		epoch := big.NewInt(test.epoch)
+		if signed := config.IsSignedBeaconSync(epoch); signed != test.signed {
+			t.Errorf("epoch %d: expected signed %t, got %t", test.epoch, test.signed, signed)
+		}
+		if unsigned := config.AcceptsUnsignedBeaconSync(epoch); unsigned != test.unsigned {
+			t.Errorf("epoch %d: expected unsigned accepted %t, got %t", test.epoch, test.unsigned, unsigned)
+		}
+	}
+
+	// without the fork, the beacon blocks stay unsigned
+	unscheduled := &ChainConfig{}
+	if unscheduled.IsSignedBeaconSync(big.NewInt(100)) || !unscheduled.AcceptsUnsignedBeaconSync(big.NewInt(100)) {
+		t.Error("expected the unsigned beacon blocks without the fork")
+	}
+}
@@ -0,0 +1,162 @@
+package params
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/crypto/hash"
+)
+
+// Errors returned when validating fork schedules.
+var (
+	ErrNoForkScheduleSignature = errors.New("fork schedule is not signed")
+	ErrUntrustedForkSigner     = errors.New("fork schedule is not signed by a trusted signer")
+	ErrForkActivated           = errors.New("fork is already activated")
+)
+
+// Fork is the epoch a fork is scheduled at. The fork is named after the JSON
+// field of its epoch in ChainConfig, e.g. "staking-epoch".
+type Fork struct {
+	Name  string `json:"name"`
+	Epoch uint64 `json:"epoch"`
+}
+
+// ForkSchedule reschedules the forks of a chain without releasing a new
+// binary. It is signed by a signer trusted by the node operator, and loaded
+// at startup.
+type ForkSchedule struct {
+	ChainID   uint64        `json:"chain-id"`
+	Forks     []Fork        `json:"forks"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// SigningHash returns the hash signed by the fork schedule signer.
+func (s *ForkSchedule) SigningHash() common.Hash {
+	return hash.FromRLP([]interface{}{s.ChainID, s.Forks})
+}
+
+// Sign signs the fork schedule with the given key.
+func (s *ForkSchedule) Sign(key *ecdsa.PrivateKey) error {
+	h := s.SigningHash()
+	sig, err := crypto.Sign(h[:], key)
+	if err != nil {
+		return err
+	}
+	s.Signature = sig
+	return nil
+}
+
+// Signer recovers the address which signed the fork schedule.
+func (s *ForkSchedule) Signer() (common.Address, error) {
+	if len(s.Signature) == 0 {
+		return common.Address{}, ErrNoForkScheduleSignature
+	}
+	h := s.SigningHash()
+	pub, err := crypto.SigToPub(h[:], s.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks that the fork schedule is signed by one of the trusted signers.
+func (s *ForkSchedule) Verify(trusted []common.Address) error {
+	signer, err := s.Signer()
+	if err != nil {
+		return err
+	}
+	for _, addr := range trusted {
+		if addr == signer {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrUntrustedForkSigner, "signer %s", signer.Hex())
+}
+
+// Apply returns a copy of the given chain config with the forks rescheduled.
+func (s *ForkSchedule) Apply(c *ChainConfig) (*ChainConfig, error) {
+	if c.ChainID == nil || c.ChainID.Cmp(new(big.Int).SetUint64(s.ChainID)) != 0 {
+		return nil, errors.Errorf("fork schedule is for chain %d, not %v", s.ChainID, c.ChainID)
+	}
+	config := *c
+	epochs := config.forkEpochs()
+	seen := map[string]struct{}{}
+	for _, fork := range s.Forks {
+		epoch, ok := epochs[fork.Name]
+		if !ok {
+			return nil, errors.Errorf("unknown fork %q", fork.Name)
+		}
+		if _, ok := seen[fork.Name]; ok {
+			return nil, errors.Errorf("fork %q scheduled twice", fork.Name)
+		}
+		seen[fork.Name] = struct{}{}
+		*epoch = new(big.Int).SetUint64(fork.Epoch)
+	}
+	if err := config.checkForkOrder(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// CheckPending checks that the forks rescheduled from the base config were
+// not activated yet at the given head epoch, neither at their old epoch nor at
+// their new one, so that the chain does not change under the node.
+func (s *ForkSchedule) CheckPending(base *ChainConfig, head *big.Int) error {
+	epochs := base.forkEpochs()
+	for _, fork := range s.Forks {
+		old, ok := epochs[fork.Name]
+		if !ok {
+			return errors.Errorf("unknown fork %q", fork.Name)
+		}
+		epoch := new(big.Int).SetUint64(fork.Epoch)
+		if *old != nil && (*old).Cmp(epoch) == 0 {
+			continue
+		}
+		if isForked(*old, head) || isForked(epoch, head) {
+			return errors.Wrapf(ErrForkActivated,
+				"%s from %v to %v at epoch %v", fork.Name, *old, epoch, head,
+			)
+		}
+	}
+	return nil
+}
+
+// forkEpochs returns the fork epochs of the config by their JSON names.
+func (c *ChainConfig) forkEpochs() map[string]**big.Int {
+	return map[string]**big.Int{
+		"cross-tx-epoch":            &c.CrossTxEpoch,
+		"cross-link-epoch":          &c.CrossLinkEpoch,
+		"staking-epoch":             &c.StakingEpoch,
+		"prestaking-epoch":          &c.PreStakingEpoch,
+		"eip155-epoch":              &c.EIP155Epoch,
+		"s3-epoch":                  &c.S3Epoch,
+		"receipt-log-epoch":         &c.ReceiptLogEpoch,
+		"signed-beacon-sync-epoch":  &c.SignedBeaconSyncEpoch,
+		"strict-message-epoch":      &c.StrictMessageEpoch,
+		"vrf-leader-election-epoch": &c.VRFLeaderElectionEpoch,
+	}
+}
+
+// checkForkOrder checks that the forks depending on each other are scheduled
+// in order.
+func (c *ChainConfig) checkForkOrder() error {
+	order := []struct {
+		before, after string
+		b, a          *big.Int
+	}{
+		{"prestaking-epoch", "staking-epoch", c.PreStakingEpoch, c.StakingEpoch},
+		{"staking-epoch", "cross-link-epoch", c.StakingEpoch, c.CrossLinkEpoch},
+		{"cross-tx-epoch", "cross-link-epoch", c.CrossTxEpoch, c.CrossLinkEpoch},
+	}
+	for _, o := range order {
+		if o.b != nil && o.a != nil && o.b.Cmp(o.a) > 0 {
+			return errors.Errorf("%s %v is after %s %v", o.before, o.b, o.after, o.a)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestForkScheduleVerify(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	trusted := []common.Address{crypto.PubkeyToAddress(key.PublicKey)}
+
+	s := ForkSchedule{ChainID: 2, Forks: []Fork{{"staking-epoch", 10}}}
+	if err := s.Verify(trusted); err != ErrNoForkScheduleSignature {
+		t.Fatalf("expected ErrNoForkScheduleSignature, got %v", err)
+	}
+	if err := s.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(trusted); err != nil {
+		t.Fatalf("verification of signed fork schedule failed: %v", err)
+	}
+	s.Forks[0].Epoch++
+	if err := s.Verify(trusted); err == nil {
+		t.Fatal("tampered fork schedule verified")
+	}
+	if err := s.Sign(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(trusted); err == nil {
+		t.Fatal("fork schedule of untrusted signer verified")
+	}
+}
+
+func TestForkScheduleApply(t *testing.T) {
+	base := *TestnetChainConfig
+	s := ForkSchedule{
+		ChainID: 2,
+		Forks:   []Fork{{"staking-epoch", 10}, {"cross-link-epoch", 10}},
+	}
+	config, err := s.Apply(&base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.StakingEpoch.Uint64() != 10 || config.CrossLinkEpoch.Uint64() != 10 {
+		t.Fatalf("forks not rescheduled: %v", config)
+	}
+	if base.StakingEpoch.Cmp(TestnetChainConfig.StakingEpoch) != 0 {
+		t.Fatal("base config modified")
+	}
+
+	tests := []struct {
+		name  string
+		sched ForkSchedule
+	}{
+		{"wrong chain", ForkSchedule{ChainID: 1}},
+		{"unknown fork", ForkSchedule{ChainID: 2, Forks: []Fork{{"foo-epoch", 1}}}},
+		{"duplicate fork", ForkSchedule{ChainID: 2, Forks: []Fork{{"s3-epoch", 1}, {"s3-epoch", 2}}}},
+		{"out of order", ForkSchedule{ChainID: 2, Forks: []Fork{{"staking-epoch", 10}}}},
+	}
+	for _, test := range tests {
+		if _, err := test.sched.Apply(&base); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+func TestForkScheduleCheckPending(t *testing.T) {
+	base := *TestnetChainConfig // staking at 4
+	s := ForkSchedule{ChainID: 2, Forks: []Fork{{"staking-epoch", 6}, {"s3-epoch", 0}}}
+	if err := s.CheckPending(&base, big.NewInt(3)); err != nil {
+		t.Fatalf("pending fork rejected: %v", err)
+	}
+	if err := s.CheckPending(&base, big.NewInt(4)); err == nil {
+		t.Fatal("fork activated at its old epoch rescheduled")
+	}
+	s.Forks[0].Epoch = 2
+	if err := s.CheckPending(&base, big.NewInt(3)); err == nil {
+		t.Fatal("fork rescheduled before the head epoch")
+	}
+}
@@ -0,0 +1,171 @@
+package pipe
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// DropPolicy decides what happens to a value sent to a full pipe
+type DropPolicy byte
+
+// Enum for the supported drop policies
+const (
+	// Block waits until the receiver makes room, SendTimeout is ignored
+	Block DropPolicy = iota
+	// DropNewest discards the value being sent once SendTimeout expires
+	DropNewest
+	// DropOldest evicts the oldest buffered value once SendTimeout expires
+	DropOldest
+)
+
+func (p DropPolicy) String() string {
+	switch p {
+	case Block:
+		return "Block"
+	case DropNewest:
+		return "DropNewest"
+	case DropOldest:
+		return "DropOldest"
+	}
+	return "Unknown"
+}
+
+// Config is the configuration of a pipe
+type Config struct {
+	// Name is used as the metrics prefix and in log messages
+	Name string
+	// Size is the buffer size of the underlying channel
+	Size int
+	// SendTimeout is how long a send waits on a full pipe before the
+	// drop policy kicks in
+	SendTimeout time.Duration
+	// Policy is the drop policy applied on a full pipe
+	Policy DropPolicy
+}
+
+// Stats is a snapshot of the pipe counters
+type Stats struct {
+	Name     string `json:"name"`
+	Depth    int    `json:"depth"`
+	Capacity int    `json:"capacity"`
+	Sent     uint64 `json:"sent"`
+	Dropped  uint64 `json:"dropped"`
+	TimedOut uint64 `json:"timed-out"`
+}
+
+// Pipe is a bounded channel with depth metrics, send timeouts and drop
+// policies. Values are received with a plain channel read on C().
+type Pipe struct {
+	config Config
+	c      chan interface{}
+
+	sent     uint64
+	dropped  uint64
+	timedOut uint64
+
+	sentCounter    metrics.Counter
+	droppedCounter metrics.Counter
+}
+
+// New creates a new pipe with the given config
+func New(config Config) *Pipe {
+	if config.Size < 0 {
+		config.Size = 0
+	}
+	p := &Pipe{
+		config: config,
+		c:      make(chan interface{}, config.Size),
+	}
+	prefix := "pipe/" + config.Name
+	p.sentCounter = metrics.NewRegisteredCounter(prefix+"/sent", nil)
+	p.droppedCounter = metrics.NewRegisteredCounter(prefix+"/dropped", nil)
+	metrics.NewRegisteredFunctionalGauge(prefix+"/depth", nil, func() int64 {
+		return int64(len(p.c))
+	})
+	return p
+}
+
+// C returns the receiving end of the pipe
+func (p *Pipe) C() <-chan interface{} {
+	return p.c
+}
+
+// Send sends v into the pipe, applying the send timeout and drop policy
+// when the pipe is full. It returns false if v was dropped.
+func (p *Pipe) Send(v interface{}) bool {
+	select {
+	case p.c <- v:
+		p.onSent()
+		return true
+	default:
+	}
+
+	if p.config.Policy == Block {
+		p.c <- v
+		p.onSent()
+		return true
+	}
+
+	if p.config.SendTimeout > 0 {
+		timer := time.NewTimer(p.config.SendTimeout)
+		defer timer.Stop()
+		select {
+		case p.c <- v:
+			p.onSent()
+			return true
+		case <-timer.C:
+			atomic.AddUint64(&p.timedOut, 1)
+		}
+	}
+
+	if p.config.Policy == DropOldest {
+		select {
+		case <-p.c:
+			p.onDropped()
+		default:
+		}
+		select {
+		case p.c <- v:
+			p.onSent()
+			return true
+		default:
+		}
+	}
+	p.onDropped()
+	return false
+}
+
+// Len returns the number of values buffered in the pipe
+func (p *Pipe) Len() int {
+	return len(p.c)
+}
+
+// Stats returns a snapshot of the pipe counters
+func (p *Pipe) Stats() Stats {
+	return Stats{
+		Name:     p.config.Name,
+		Depth:    len(p.c),
+		Capacity: cap(p.c),
+		Sent:     atomic.LoadUint64(&p.sent),
+		Dropped:  atomic.LoadUint64(&p.dropped),
+		TimedOut: atomic.LoadUint64(&p.timedOut),
+	}
+}
+
+func (p *Pipe) onSent() {
+	atomic.AddUint64(&p.sent, 1)
+	p.sentCounter.Inc(1)
+}
+
+func (p *Pipe) onDropped() {
+	atomic.AddUint64(&p.dropped, 1)
+	p.droppedCounter.Inc(1)
+	utils.Logger().Warn().
+		Str("pipe", p.config.Name).
+		Str("policy", p.config.Policy.String()).
+		Int("depth", len(p.c)).
+		Msg("[pipe] dropped value on full pipe")
+}
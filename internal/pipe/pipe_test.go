@@ -0,0 +1,64 @@
+package pipe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendBlock(t *testing.T) {
+	p := New(Config{Name: "test/block", Size: 1, Policy: Block})
+	if !p.Send(1) {
+		t.Fatal("send on empty pipe should succeed")
+	}
+	done := make(chan struct{})
+	go func() {
+		p.Send(2)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("send on full blocking pipe should wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if v := <-p.C(); v.(int) != 1 {
+		t.Fatalf("expected 1, got %v", v)
+	}
+	<-done
+	if stats := p.Stats(); stats.Sent != 2 || stats.Dropped != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestSendDropNewest(t *testing.T) {
+	p := New(Config{Name: "test/newest", Size: 1, SendTimeout: 10 * time.Millisecond, Policy: DropNewest})
+	p.Send(1)
+	if p.Send(2) {
+		t.Fatal("send on full pipe should drop the new value")
+	}
+	if v := <-p.C(); v.(int) != 1 {
+		t.Fatalf("expected 1, got %v", v)
+	}
+	if stats := p.Stats(); stats.Dropped != 1 || stats.TimedOut != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestSendDropOldest(t *testing.T) {
+	p := New(Config{Name: "test/oldest", Size: 2, Policy: DropOldest})
+	for i := 1; i <= 3; i++ {
+		if !p.Send(i) {
+			t.Fatalf("send %d should succeed", i)
+		}
+	}
+	if p.Len() != 2 {
+		t.Fatalf("expected depth 2, got %d", p.Len())
+	}
+	for _, want := range []int{2, 3} {
+		if v := <-p.C(); v.(int) != want {
+			t.Fatalf("expected %d, got %v", want, v)
+		}
+	}
+	if stats := p.Stats(); stats.Dropped != 1 || stats.Sent != 3 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
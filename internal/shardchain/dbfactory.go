@@ -21,8 +21,13 @@ type LDBFactory struct {
 
 // NewChainDB returns a new LDB for the blockchain for given shard.
 func (f *LDBFactory) NewChainDB(shardID uint32) (ethdb.Database, error) {
-	dir := path.Join(f.RootDir, fmt.Sprintf("harmony_db_%d", shardID))
-	return ethdb.NewLDBDatabase(dir, 0, 0)
+	return ethdb.NewLDBDatabase(ChainDBDir(f.RootDir, shardID), 0, 0)
+}
+
+// ChainDBDir returns the directory of the LDB of the given shard under the
+// root directory.
+func ChainDBDir(rootDir string, shardID uint32) string {
+	return path.Join(rootDir, fmt.Sprintf("harmony_db_%d", shardID))
 }
 
 // MemDBFactory is a memory-backed blockchain database factory.
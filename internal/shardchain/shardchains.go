@@ -2,6 +2,7 @@ package shardchain
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -11,6 +12,7 @@ import (
 	"github.com/harmony-one/harmony/core/vm"
 	"github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
 	"github.com/pkg/errors"
 )
 
@@ -20,7 +22,11 @@ type Collection interface {
 	// opening one as necessary.
 	ShardChain(shardID uint32) (*core.BlockChain, error)
 
-	// CloseShardChain closes the given shard chain.
+	// AcquireShardChain returns the blockchain for the given shard, opening
+	// one as necessary, and keeps it open until release is called.
+	AcquireShardChain(shardID uint32) (bc *core.BlockChain, release func(), err error)
+
+	// CloseShardChain closes the given shard chain unless it is primary or in use.
 	CloseShardChain(shardID uint32) error
 
 	// Close closes all shard chains.
@@ -37,6 +43,24 @@ type CollectionImpl struct {
 	pool         map[uint32]*core.BlockChain
 	disableCache bool
 	chainConfig  *params.ChainConfig
+
+	// Reference counts and last use of the open chains, for idle closing
+	refs        map[uint32]int
+	lastUsed    map[uint32]time.Time
+	primary     map[uint32]struct{}
+	idleTimeout time.Duration
+	stopIdle    chan struct{}
+
+	// State pruning of the newly opened chains, 0 retention for none
+	pruneRetention uint64
+	pruneBudget    time.Duration
+
+	// Blocks checked for corruption when opening a chain, 0 for none
+	integrityCheckDepth uint64
+
+	// Whether the shard state of the blocks received from other nodes is
+	// verified against the beacon chain of the collection
+	verifyShardState bool
 }
 
 // NewCollection creates and returns a new shard chain collection.
@@ -55,14 +79,57 @@ func NewCollection(
 		engine:      engine,
 		pool:        make(map[uint32]*core.BlockChain),
 		chainConfig: chainConfig,
+		refs:        make(map[uint32]int),
+		lastUsed:    make(map[uint32]time.Time),
+		// the beacon chain is held by the consensus and the workers of every
+		// node, and never closed
+		primary: map[uint32]struct{}{shard.BeaconChainShardID: {}},
 	}
 }
 
 // ShardChain returns the blockchain for the given shard,
 // opening one as necessary.
+//
+// Chains returned by ShardChain may be closed once idle; callers using a
+// non-primary chain for longer than the idle timeout should acquire it with
+// AcquireShardChain instead.
 func (sc *CollectionImpl) ShardChain(shardID uint32) (*core.BlockChain, error) {
 	sc.mtx.Lock()
 	defer sc.mtx.Unlock()
+	return sc.openShardChain(shardID)
+}
+
+// AcquireShardChain returns the blockchain for the given shard, opening one
+// as necessary. The chain is not closed, neither when idle nor by
+// CloseShardChain, until the returned release function is called.
+func (sc *CollectionImpl) AcquireShardChain(
+	shardID uint32,
+) (*core.BlockChain, func(), error) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	bc, err := sc.openShardChain(shardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sc.refs[shardID]++
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			sc.mtx.Lock()
+			defer sc.mtx.Unlock()
+			if sc.refs[shardID]--; sc.refs[shardID] <= 0 {
+				delete(sc.refs, shardID)
+			}
+			sc.lastUsed[shardID] = time.Now()
+		})
+	}
+	return bc, release, nil
+}
+
+// openShardChain returns the open blockchain for the given shard, opening one
+// as necessary. The caller must hold sc.mtx.
+func (sc *CollectionImpl) openShardChain(shardID uint32) (*core.BlockChain, error) {
+	sc.lastUsed[shardID] = time.Now()
 	if bc, ok := sc.pool[shardID]; ok {
 		return bc, nil
 	}
@@ -98,6 +165,22 @@ func (sc *CollectionImpl) ShardChain(shardID uint32) (*core.BlockChain, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot create blockchain")
 	}
+	if sc.integrityCheckDepth > 0 {
+		if _, err := bc.CheckIntegrity(sc.integrityCheckDepth); err != nil {
+			bc.Stop()
+			return nil, errors.Wrapf(err, "cannot repair chain database")
+		}
+	}
+	if sc.pruneRetention > 0 && !sc.disableCache {
+		if err := bc.EnableStatePruning(sc.pruneRetention, sc.pruneBudget); err != nil {
+			utils.Logger().Warn().Err(err).
+				Uint32("shardID", shardID).
+				Msg("cannot enable state pruning")
+		}
+	}
+	if sc.verifyShardState {
+		bc.EnableShardStateVerification(sc.beaconChainReader(bc))
+	}
 	db = nil // don't close
 	sc.pool[shardID] = bc
 	return bc, nil
@@ -110,24 +193,138 @@ func (sc *CollectionImpl) DisableCache() {
 	sc.disableCache = true
 }
 
-// CloseShardChain closes the given shard chain.
+// EnableStatePruning prunes the state of newly opened chains older than the
+// given number of blocks, pausing the block imports for no longer than the
+// budget at a time. Archival chains are not pruned.
+func (sc *CollectionImpl) EnableStatePruning(retention uint64, budget time.Duration) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	sc.pruneRetention = retention
+	sc.pruneBudget = budget
+}
+
+// EnableShardStateVerification verifies the shard state of the blocks of newly
+// opened chains received from other nodes against the beacon chain of the
+// collection, before inserting them.
+func (sc *CollectionImpl) EnableShardStateVerification() {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	sc.verifyShardState = true
+}
+
+// beaconChainReader returns the beacon chain of the collection to verify the
+// shard state of the chain against, the chain itself if the beacon chain, open
+// on each use as the beacon chain may be closed once idle.
+func (sc *CollectionImpl) beaconChainReader(
+	bc *core.BlockChain,
+) func() (engine.ChainReader, error) {
+	return func() (engine.ChainReader, error) {
+		if bc.ShardID() == shard.BeaconChainShardID {
+			return bc, nil
+		}
+		beacon, err := sc.ShardChain(shard.BeaconChainShardID)
+		if err != nil {
+			return nil, err
+		}
+		return beacon, nil
+	}
+}
+
+// EnableIntegrityCheck checks the last given number of blocks of newly opened
+// chains for corruption, truncating the chains below the corrupted blocks.
+func (sc *CollectionImpl) EnableIntegrityCheck(depth uint64) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	sc.integrityCheckDepth = depth
+}
+
+// SetPrimary marks the given shard chain as primary. Primary chains, the
+// beacon chain and the chain of the node's own shard, are held for the life of
+// the node and never closed before the collection.
+func (sc *CollectionImpl) SetPrimary(shardID uint32) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	sc.primary[shardID] = struct{}{}
+}
+
+// EnableIdleClose closes the non-primary shard chains not in use that have
+// not been used for the given timeout, reclaiming their file handles and
+// caches. They are opened again on their next use.
+func (sc *CollectionImpl) EnableIdleClose(timeout time.Duration) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	if sc.stopIdle != nil || timeout <= 0 {
+		return
+	}
+	sc.idleTimeout = timeout
+	sc.stopIdle = make(chan struct{})
+	go sc.closeIdleLoop(sc.stopIdle, timeout)
+}
+
+func (sc *CollectionImpl) closeIdleLoop(stop <-chan struct{}, timeout time.Duration) {
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			sc.closeIdle(now)
+		}
+	}
+}
+
+// closeIdle closes the shard chains idle since before now minus the idle
+// timeout.
+func (sc *CollectionImpl) closeIdle(now time.Time) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	for shardID := range sc.pool {
+		if _, ok := sc.primary[shardID]; ok || sc.refs[shardID] > 0 {
+			continue
+		}
+		if now.Sub(sc.lastUsed[shardID]) < sc.idleTimeout {
+			continue
+		}
+		utils.Logger().Info().
+			Uint32("shardID", shardID).
+			Dur("idle", now.Sub(sc.lastUsed[shardID])).
+			Msg("closing idle shard chain")
+		sc.closeShardChain(shardID)
+	}
+}
+
+// CloseShardChain closes the given shard chain unless it is primary or in use.
 func (sc *CollectionImpl) CloseShardChain(shardID uint32) error {
 	sc.mtx.Lock()
 	defer sc.mtx.Unlock()
-	bc, ok := sc.pool[shardID]
-	if !ok {
+	if _, ok := sc.pool[shardID]; !ok {
 		return errors.Errorf("shard chain not found %d", shardID)
 	}
+	if _, ok := sc.primary[shardID]; ok {
+		return errors.Errorf("shard chain %d is primary", shardID)
+	}
+	if refs := sc.refs[shardID]; refs > 0 {
+		return errors.Errorf("shard chain %d in use (%d references)", shardID, refs)
+	}
+	sc.closeShardChain(shardID)
+	return nil
+}
+
+// closeShardChain closes the given open shard chain. The caller must hold
+// sc.mtx.
+func (sc *CollectionImpl) closeShardChain(shardID uint32) {
+	bc := sc.pool[shardID]
 	utils.Logger().Info().
 		Uint32("shardID", shardID).
 		Msg("closing shard chain")
 	delete(sc.pool, shardID)
+	delete(sc.lastUsed, shardID)
 	bc.Stop()
 	bc.ChainDb().Close()
 	utils.Logger().Info().
 		Uint32("shardID", shardID).
 		Msg("closed shard chain")
-	return nil
 }
 
 // Close closes all shard chains.
@@ -136,6 +333,12 @@ func (sc *CollectionImpl) Close() error {
 	sc.mtx.Lock()
 	oldPool := sc.pool
 	sc.pool = newPool
+	sc.refs = make(map[uint32]int)
+	sc.lastUsed = make(map[uint32]time.Time)
+	if sc.stopIdle != nil {
+		close(sc.stopIdle)
+		sc.stopIdle = nil
+	}
 	sc.mtx.Unlock()
 	for shardID, bc := range oldPool {
 		utils.Logger().Info().
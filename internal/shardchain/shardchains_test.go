@@ -0,0 +1,123 @@
+package shardchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+type testDBInit struct{}
+
+func (testDBInit) InitChainDB(db ethdb.Database, shardID uint32) error {
+	gspec := core.Genesis{
+		Config:  params.TestChainConfig,
+		Factory: blockfactory.ForTest,
+		ShardID: shardID,
+	}
+	_, err := gspec.Commit(db)
+	return err
+}
+
+func newTestCollection(t *testing.T, primary uint32) *CollectionImpl {
+	sc := NewCollection(&MemDBFactory{}, testDBInit{}, chain.Engine, params.TestChainConfig)
+	sc.SetPrimary(primary)
+	sc.idleTimeout = time.Minute
+	for shardID := uint32(0); shardID < 4; shardID++ {
+		if _, err := sc.ShardChain(shardID); err != nil {
+			t.Fatalf("cannot open shard chain %d: %v", shardID, err)
+		}
+	}
+	return sc
+}
+
+func TestCloseIdle(t *testing.T) {
+	sc := newTestCollection(t, 1)
+	defer sc.Close()
+	_, release, err := sc.AcquireShardChain(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, releaseAgain, err := sc.AcquireShardChain(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc.closeIdle(time.Now().Add(2 * time.Minute))
+	for shardID, open := range map[uint32]bool{0: true, 1: true, 2: false, 3: true} {
+		if _, ok := sc.pool[shardID]; ok != open {
+			t.Errorf("shard chain %d: expected open %t", shardID, open)
+		}
+	}
+
+	// the chain stays open until all its holders release it
+	release()
+	release()
+	sc.closeIdle(time.Now().Add(2 * time.Minute))
+	if _, ok := sc.pool[3]; !ok {
+		t.Error("expected the chain still held open")
+	}
+	releaseAgain()
+	sc.closeIdle(time.Now().Add(2 * time.Minute))
+	if _, ok := sc.pool[3]; ok {
+		t.Error("expected the released chain closed")
+	}
+
+	// the closed chains are opened again on their next use
+	if bc, err := sc.ShardChain(2); err != nil || bc.ShardID() != 2 {
+		t.Errorf("expected the shard chain opened again, got %v", err)
+	}
+}
+
+func TestCloseShardChain(t *testing.T) {
+	sc := newTestCollection(t, 1)
+	defer sc.Close()
+	if err := sc.CloseShardChain(0); err == nil {
+		t.Error("expected the beacon chain kept open")
+	}
+	if err := sc.CloseShardChain(1); err == nil {
+		t.Error("expected the chain of the node's shard kept open")
+	}
+	_, release, err := sc.AcquireShardChain(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.CloseShardChain(2); err == nil {
+		t.Error("expected the chain in use kept open")
+	}
+	release()
+	if err := sc.CloseShardChain(2); err != nil {
+		t.Errorf("expected the released chain closed, got %v", err)
+	}
+	if err := sc.CloseShardChain(2); err == nil {
+		t.Error("expected the closed chain not found")
+	}
+}
+
+func TestBeaconChainReader(t *testing.T) {
+	sc := newTestCollection(t, 1)
+	defer sc.Close()
+	beacon, err := sc.ShardChain(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, shardID := range []uint32{0, 1, 3} {
+		bc, err := sc.ShardChain(shardID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader, err := sc.beaconChainReader(bc)()
+		if err != nil {
+			t.Errorf("shard chain %d: cannot read the beacon chain: %v", shardID, err)
+			continue
+		}
+		if reader != beacon {
+			t.Errorf("shard chain %d: expected the beacon chain of the collection, got shard %d",
+				shardID, reader.ShardID())
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// analysisSamples is the number of the latest durations of a timer kept
+	// for its percentiles
+	analysisSamples = 1024
+	// maxAnalysisStarts bounds the sections started and not ended yet, the
+	// sections left on errors being dropped beyond
+	maxAnalysisStarts = 1024
+)
+
+// AnalysisTimer is the statistics of the durations of a section of code
+// between its AnalysisStart and AnalysisEnd
+type AnalysisTimer struct {
+	Name  string        `json:"name"`
+	Count uint64        `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	Min   time.Duration `json:"min"`
+	Max   time.Duration `json:"max"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+}
+
+// analysisHistogram is the durations of a section of code, all time for the
+// count and bounds, the latest ones for the percentiles
+type analysisHistogram struct {
+	count    uint64
+	total    time.Duration
+	min, max time.Duration
+	samples  []time.Duration
+	next     int
+}
+
+func (h *analysisHistogram) add(d time.Duration) {
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.total += d
+	if len(h.samples) < analysisSamples {
+		h.samples = append(h.samples, d)
+		return
+	}
+	h.samples[h.next] = d
+	h.next = (h.next + 1) % analysisSamples
+}
+
+func (h *analysisHistogram) timer(name string) AnalysisTimer {
+	sorted := append([]time.Duration{}, h.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p int) time.Duration {
+		if len(sorted) == 0 {
+			return 0
+		}
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	timer := AnalysisTimer{
+		Name:  name,
+		Count: h.count,
+		Min:   h.min,
+		Max:   h.max,
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+	}
+	if h.count > 0 {
+		timer.Mean = h.total / time.Duration(h.count)
+	}
+	return timer
+}
+
+// analysisTimers times the sections of code between AnalysisStart and
+// AnalysisEnd, a section being told by its name and details
+type analysisTimers struct {
+	lock       sync.Mutex
+	starts     map[string]time.Time
+	histograms map[string]*analysisHistogram
+}
+
+var timers = &analysisTimers{
+	starts:     map[string]time.Time{},
+	histograms: map[string]*analysisHistogram{},
+}
+
+// analysisEnabled tells whether AnalysisStart and AnalysisEnd record their
+// timers, only served along the debug server
+var analysisEnabled uint32
+
+// EnableAnalysisTimers makes AnalysisStart and AnalysisEnd record their
+// timers, served by AnalysisHandler
+func EnableAnalysisTimers() {
+	atomic.StoreUint32(&analysisEnabled, 1)
+}
+
+func analysisTimersEnabled() bool {
+	return atomic.LoadUint32(&analysisEnabled) == 1
+}
+
+func analysisKey(name string, more []interface{}) string {
+	return name + " " + fmt.Sprint(more...)
+}
+
+func (t *analysisTimers) start(name string, more []interface{}, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.starts) >= maxAnalysisStarts {
+		t.starts = map[string]time.Time{}
+	}
+	t.starts[analysisKey(name, more)] = now
+}
+
+func (t *analysisTimers) end(name string, more []interface{}, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	key := analysisKey(name, more)
+	start, ok := t.starts[key]
+	if !ok {
+		return
+	}
+	delete(t.starts, key)
+	h, ok := t.histograms[name]
+	if !ok {
+		h = &analysisHistogram{}
+		t.histograms[name] = h
+	}
+	h.add(now.Sub(start))
+}
+
+// AnalysisTimers returns the statistics of the timed sections of code, by
+// name
+func AnalysisTimers() []AnalysisTimer {
+	timers.lock.Lock()
+	defer timers.lock.Unlock()
+	all := make([]AnalysisTimer, 0, len(timers.histograms))
+	for name, h := range timers.histograms {
+		all = append(all, h.timer(name))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// AnalysisHandler serves the statistics of the timed sections of code as JSON,
+// those of the given name only if any
+func AnalysisHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		all := AnalysisTimers()
+		if name := r.URL.Query().Get("name"); name != "" {
+			found := []AnalysisTimer{}
+			for _, timer := range all {
+				if timer.Name == name {
+					found = append(found, timer)
+				}
+			}
+			all = found
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(all); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAnalysisTimers(t *testing.T) {
+	timers := &analysisTimers{
+		starts:     map[string]time.Time{},
+		histograms: map[string]*analysisHistogram{},
+	}
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		timers.start("propose", []interface{}{i}, now)
+		timers.end("propose", []interface{}{i}, now.Add(time.Duration(i)*time.Millisecond))
+	}
+	// the end of another section or of no start is not timed
+	timers.start("propose", []interface{}{1000}, now)
+	timers.end("propose", []interface{}{1001}, now.Add(time.Hour))
+
+	timer := timers.histograms["propose"].timer("propose")
+	if timer.Count != 100 || timer.Min != time.Millisecond || timer.Max != 100*time.Millisecond {
+		t.Errorf("unexpected count and bounds %+v", timer)
+	}
+	if timer.P50 != 50*time.Millisecond || timer.P99 != 99*time.Millisecond {
+		t.Errorf("unexpected percentiles %+v", timer)
+	}
+	if timer.Mean != 50500*time.Microsecond {
+		t.Errorf("unexpected mean %v", timer.Mean)
+	}
+}
+
+func TestAnalysisHandler(t *testing.T) {
+	served := func() []AnalysisTimer {
+		w := httptest.NewRecorder()
+		AnalysisHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/timers?name=testAnalysisHandler", nil))
+		found := []AnalysisTimer{}
+		if err := json.NewDecoder(w.Body).Decode(&found); err != nil {
+			t.Fatal(err)
+		}
+		return found
+	}
+
+	// without the debug server, the sections are not timed
+	atomic.StoreUint32(&analysisEnabled, 0)
+	AnalysisStart("testAnalysisHandler", 1)
+	AnalysisEnd("testAnalysisHandler", 1)
+	if found := served(); len(found) != 0 {
+		t.Errorf("unexpected timers while disabled %+v", found)
+	}
+
+	EnableAnalysisTimers()
+	AnalysisStart("testAnalysisHandler", 1)
+	AnalysisEnd("testAnalysisHandler", 1)
+	if found := served(); len(found) != 1 || found[0].Name != "testAnalysisHandler" || found[0].Count != 1 {
+		t.Errorf("unexpected timers %+v", found)
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Well-known logging modules whose level can be tuned at runtime
+const (
+	ModuleConsensus = "consensus"
+	ModuleNode      = "node"
+	ModuleSync      = "sync"
+	ModuleP2P       = "p2p"
+	ModuleTxPool    = "txpool"
+)
+
+// Modules are the well-known logging modules
+var Modules = []string{ModuleConsensus, ModuleNode, ModuleSync, ModuleP2P, ModuleTxPool}
+
+var (
+	moduleLevels   = map[string]zerolog.Level{}
+	moduleSamplers = map[string]*zerolog.BasicSampler{}
+	moduleLock     sync.RWMutex
+)
+
+// ModuleLogger returns the zerolog logger tagged with the given module.
+// If a level has been set for the module with SetModuleLogLevel, it overrides
+// the global log verbosity.
+func ModuleLogger(module string) *zerolog.Logger {
+	logger := Logger().With().Str("module", module).Logger()
+	return WithModuleLevel(module, &logger)
+}
+
+// WithModuleLevel returns the given logger of the module at the level set for
+// the module, if any, so that the loggers kept with a context of their own
+// follow the level of their module set on runtime
+func WithModuleLevel(module string, logger *zerolog.Logger) *zerolog.Logger {
+	moduleLock.RLock()
+	level, ok := moduleLevels[module]
+	moduleLock.RUnlock()
+	if !ok {
+		return logger
+	}
+	leveled := logger.Level(level)
+	return &leveled
+}
+
+// SampledLogger returns the module logger which only emits one of every n
+// messages logged under key. It is meant for high-frequency messages such as
+// the ones logged on every tick of a main loop.
+func SampledLogger(module, key string, n uint32) *zerolog.Logger {
+	id := module + "/" + key
+	moduleLock.RLock()
+	sampler, ok := moduleSamplers[id]
+	moduleLock.RUnlock()
+	if !ok {
+		moduleLock.Lock()
+		if sampler, ok = moduleSamplers[id]; !ok {
+			sampler = &zerolog.BasicSampler{N: n}
+			moduleSamplers[id] = sampler
+		}
+		moduleLock.Unlock()
+	}
+	logger := ModuleLogger(module).Sample(sampler)
+	return &logger
+}
+
+// SetModuleLogLevel sets the log level of the given module on runtime
+func SetModuleLogLevel(module string, level zerolog.Level) {
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	moduleLevels[module] = level
+}
+
+// ResetModuleLogLevel makes the given module follow the global log verbosity again
+func ResetModuleLogLevel(module string) {
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	delete(moduleLevels, module)
+}
+
+// ModuleLogLevels returns the log levels of all modules with an override
+func ModuleLogLevels() map[string]string {
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	levels := make(map[string]string, len(moduleLevels))
+	for module, level := range moduleLevels {
+		levels[module] = level.String()
+	}
+	return levels
+}
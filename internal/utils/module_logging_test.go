@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSetModuleLogLevel(t *testing.T) {
+	for _, module := range Modules {
+		SetModuleLogLevel(module, zerolog.DebugLevel)
+		if level := ModuleLogLevels()[module]; level != "debug" {
+			t.Fatalf("expected debug level for %s, got %q", module, level)
+		}
+		ResetModuleLogLevel(module)
+		if _, ok := ModuleLogLevels()[module]; ok {
+			t.Fatalf("%s level should have been reset", module)
+		}
+	}
+}
+
+func TestWithModuleLevel(t *testing.T) {
+	defer ResetModuleLogLevel(ModuleP2P)
+	for _, test := range []struct {
+		name     string
+		level    *zerolog.Level // level of the module, nil for none
+		expected []string       // levels of the messages written
+	}{
+		{"no override", nil, []string{"debug", "warn"}},
+		{"warn", levelOf(zerolog.WarnLevel), []string{"warn"}},
+		{"trace", levelOf(zerolog.TraceLevel), []string{"debug", "warn"}},
+		{"disabled", levelOf(zerolog.Disabled), nil},
+	} {
+		if test.level == nil {
+			ResetModuleLogLevel(ModuleP2P)
+		} else {
+			SetModuleLogLevel(ModuleP2P, *test.level)
+		}
+		out := bytes.Buffer{}
+		// the logger kept by the module, created before the level is set
+		logger := zerolog.New(&out).Level(zerolog.DebugLevel)
+		WithModuleLevel(ModuleP2P, &logger).Debug().Msg("")
+		WithModuleLevel(ModuleP2P, &logger).Warn().Msg("")
+		// the other modules keep the level of their logger
+		WithModuleLevel(ModuleSync, &logger).Trace().Msg("")
+
+		expected := ""
+		for _, level := range test.expected {
+			expected += `{"level":"` + level + `"}` + "\n"
+		}
+		if out.String() != expected {
+			t.Errorf("%s: expected %q written, got %q", test.name, expected, out.String())
+		}
+	}
+}
+
+func levelOf(level zerolog.Level) *zerolog.Level {
+	return &level
+}
+
+func TestSampledLoggerSharesSampler(t *testing.T) {
+	SampledLogger(ModuleSync, "tick", 10)
+	SampledLogger(ModuleSync, "tick", 10)
+	if len(moduleSamplers) != 1 {
+		t.Fatalf("expected one sampler, got %d", len(moduleSamplers))
+	}
+}
@@ -50,16 +50,98 @@ func SetLogVerbosity(verbosity log.Lvl) {
 	updateZeroLogLevel(int(verbosity))
 }
 
+// LogRotation configures the rotation of the log files
+type LogRotation struct {
+	MaxSize    int           // megabytes a file grows to before it gets rotated
+	MaxAge     time.Duration // age a file gets rotated at, 0 for no age limit
+	MaxBackups int           // rotated files kept, 0 to keep them all
+}
+
 // AddLogFile creates a StreamHandler that outputs JSON logs
 // into rotating files with specified max file size
 func AddLogFile(filepath string, maxSize int) {
-	AddLogHandler(log.StreamHandler(&lumberjack.Logger{
-		Filename: filepath,
-		MaxSize:  maxSize,
-		Compress: true,
-	}, log.JSONFormat()))
+	AddRotatingLogFile(filepath, LogRotation{MaxSize: maxSize})
+}
+
+// AddRotatingLogFile creates a StreamHandler that outputs JSON logs
+// into files rotated by size and by age
+func AddRotatingLogFile(filepath string, rotation LogRotation) {
+	AddLogHandler(log.StreamHandler(
+		newRotatingFile(filepath, rotation, logRotationCheck), log.JSONFormat(),
+	))
+
+	setZeroLoggerFileOutput(filepath, rotation)
+}
+
+// logRotationCheck is the interval the age of the rotating log files is
+// checked at
+const logRotationCheck = time.Minute
 
-	setZeroLoggerFileOutput(filepath, maxSize)
+// rotatingFile is a log file rotated by size, and by age once the time since
+// it was started, the modification time of the file found on opening it or
+// the time of its last rotation, exceeds the max age
+type rotatingFile struct {
+	*lumberjack.Logger
+	maxAge time.Duration
+	lock   sync.Mutex
+	start  time.Time
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// newRotatingFile opens the log file, checking its age in the background
+// every interval until it gets closed
+func newRotatingFile(filepath string, rotation LogRotation, interval time.Duration) *rotatingFile {
+	file := &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   filepath,
+			MaxSize:    rotation.MaxSize,
+			MaxBackups: rotation.MaxBackups,
+			Compress:   true,
+		},
+		maxAge: rotation.MaxAge,
+		start:  time.Now(),
+		stop:   make(chan struct{}),
+	}
+	if info, err := os.Stat(filepath); err == nil {
+		file.start = info.ModTime()
+	}
+	if rotation.MaxAge > 0 {
+		go file.rotateByAge(interval)
+	}
+	return file
+}
+
+func (file *rotatingFile) rotateByAge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := file.rotateIfOlder(now); err != nil {
+				fmt.Fprintf(os.Stderr, "cannot rotate log file %s: %v\n", file.Filename, err)
+			}
+		case <-file.stop:
+			return
+		}
+	}
+}
+
+// rotateIfOlder rotates the file if it is older than the max age at now
+func (file *rotatingFile) rotateIfOlder(now time.Time) error {
+	file.lock.Lock()
+	defer file.lock.Unlock()
+	if now.Sub(file.start) < file.maxAge {
+		return nil
+	}
+	file.start = now
+	return file.Rotate()
+}
+
+// Close stops rotating the file by age and closes it
+func (file *rotatingFile) Close() error {
+	file.once.Do(func() { close(file.stop) })
+	return file.Logger.Close()
 }
 
 // AddLogHandler add a log handler
@@ -101,18 +183,16 @@ func setZeroLogContext(port string, ip string) {
 
 // SetZeroLoggerFileOutput sets zeroLogger's output stream
 // to destinated filepath with log file rotation.
-func setZeroLoggerFileOutput(filepath string, maxSize int) error {
+func setZeroLoggerFileOutput(filepath string, rotation LogRotation) error {
 	dir := path.Dir(filepath)
 	filename := path.Base(filepath)
 
 	// Initialize ZeroLogger if it hasn't been already
 	// TODO: zerolog filename prefix can be removed once all loggers
 	// has been replaced
-	childLogger := Logger().Output(&lumberjack.Logger{
-		Filename: fmt.Sprintf("%s/zerolog-%s", dir, filename),
-		MaxSize:  maxSize,
-		Compress: true,
-	})
+	childLogger := Logger().Output(
+		newRotatingFile(fmt.Sprintf("%s/zerolog-%s", dir, filename), rotation, logRotationCheck),
+	)
 	zeroLogger = &childLogger
 
 	return nil
@@ -152,13 +232,21 @@ func ds() *zerolog.Logger {
 	return logger
 }
 
-// AnalysisStart ..
+// AnalysisStart starts timing the section of code of the given name and
+// details, see AnalysisTimers. The sections are only timed once
+// EnableAnalysisTimers was called.
 func AnalysisStart(name string, more ...interface{}) {
+	if analysisTimersEnabled() {
+		timers.start(name, more, time.Now())
+	}
 	ds().Debug().Msgf("ds-%s-start %s", name, fmt.Sprint(more...))
 }
 
-// AnalysisEnd ..
+// AnalysisEnd ends timing the section of code of the given name and details
 func AnalysisEnd(name string, more ...interface{}) {
+	if analysisTimersEnabled() {
+		timers.end(name, more, time.Now())
+	}
 	ds().Debug().Msgf("ds-%s-end %s", name, fmt.Sprint(more...))
 }
 
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -33,3 +36,72 @@ func TestSingleton(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestRotatingFileByAge(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		name     string
+		modified time.Duration // age of the file found on opening it, 0 for none
+		at       time.Duration // time since opening the age is checked at
+		rotated  bool
+	}{
+		{"new file", 0, 30 * time.Minute, false},
+		{"new file expired", 0, 2 * time.Hour, true},
+		{"recent file", 30 * time.Minute, 0, false},
+		{"old file", 2 * time.Hour, 0, true},
+		{"old file expiring", 50 * time.Minute, 20 * time.Minute, true},
+	} {
+		dir, err := ioutil.TempDir("", "hmy-log")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "validator.log")
+		if test.modified > 0 {
+			if err := ioutil.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, now.Add(-test.modified), now.Add(-test.modified)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// the age is checked by hand, not in the background
+		file := newRotatingFile(path, LogRotation{MaxAge: time.Hour}, time.Hour)
+		if _, err := file.Write([]byte("{}\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.rotateIfOlder(now.Add(test.at)); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rotated := len(files) > 1; rotated != test.rotated {
+			t.Errorf("%s: expected rotated %t, got %d files", test.name, test.rotated, len(files))
+		}
+	}
+}
+
+func TestRotatingFileClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hmy-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := newRotatingFile(filepath.Join(dir, "validator.log"), LogRotation{MaxAge: time.Hour}, time.Millisecond)
+	// closing the file stops rotating it, more than once alike
+	for i := 0; i < 2; i++ {
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-file.stop:
+	default:
+		t.Error("expected the rotation stopped")
+	}
+}
@@ -74,3 +74,20 @@ func AppendPriKey(multiKey *PrivateKey, key *bls.SecretKey) {
 		multiKey = &PrivateKey{PrivateKey: []*bls.SecretKey{key}}
 	}
 }
+
+// GroupByShard groups the keys by the shard they serve, as found by shardOf;
+// the keys serving no shard are left out
+func (multiKey PublicKey) GroupByShard(shardOf func(*bls.PublicKey) (uint32, bool)) map[uint32]*PublicKey {
+	groups := map[uint32]*PublicKey{}
+	for _, key := range multiKey.PublicKey {
+		shardID, ok := shardOf(key)
+		if !ok {
+			continue
+		}
+		if _, ok := groups[shardID]; !ok {
+			groups[shardID] = &PublicKey{}
+		}
+		groups[shardID].PublicKey = append(groups[shardID].PublicKey, key)
+	}
+	return groups
+}
@@ -0,0 +1,85 @@
+package node
+
+import (
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/harmony-one/harmony/api/proto"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	"github.com/harmony-one/harmony/consensus"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"golang.org/x/sync/semaphore"
+)
+
+// consensusMsgWorkers is the size of the worker pool dispatching the consensus
+// messages of each priority. The pools are separate from the common message
+// handlers and from each other, so a flood of low priority messages cannot
+// delay the messages needed for finality.
+var consensusMsgWorkers = [consensus.NumMsgPriorities]int64{
+	consensus.PriorityFinality: 64,
+	consensus.PriorityQuorum:   64,
+	consensus.PriorityAnnounce: 32,
+	consensus.PriorityOther:    200,
+}
+
+// consensusDispatcher dispatches the received consensus messages to the
+// consensus by priority
+type consensusDispatcher struct {
+	workers [consensus.NumMsgPriorities]*semaphore.Weighted
+}
+
+func newConsensusDispatcher() *consensusDispatcher {
+	d := &consensusDispatcher{}
+	for p := range d.workers {
+		d.workers[p] = semaphore.NewWeighted(consensusMsgWorkers[p])
+	}
+	return d
+}
+
+// dispatchConsensusMessage dispatches the message to the consensus if it is a
+// consensus message to be handled by it. It returns false if the message has
+// to go through the common message handlers instead. The publisher of the
+// message is empty if unknown.
+func (node *Node) dispatchConsensusMessage(content []byte, from libp2p_peer.ID) bool {
+	if node.consensusDispatcher == nil ||
+		node.NodeConfig.Role() == nodeconfig.ExplorerNode {
+		return false
+	}
+	if category, err := proto.GetMessageCategory(content); err != nil ||
+		category != proto.Consensus {
+		return false
+	}
+	node.host.LogRecvMessage(content)
+	payload, err := proto.GetConsensusMessagePayload(content)
+	if err != nil {
+		return false
+	}
+	msg, err := unmarshalConsensusMessage(payload)
+	if err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to unmarshal consensus message payload.")
+		return true
+	}
+	priority := consensus.PriorityOf(msg)
+	sem := node.consensusDispatcher.workers[priority]
+	if !sem.TryAcquire(1) {
+		utils.ModuleLogger(utils.ModuleNode).Info().
+			Str("priority", priority.String()).
+			Msg("could not acquire semaphore to dispatch consensus message")
+		return true
+	}
+	go func() {
+		defer sem.Release(1)
+		node.observeLeaderAnnounce(msg, from)
+		node.Consensus.EnqueueMessage(msg)
+	}()
+	return true
+}
+
+// unmarshalConsensusMessage decodes the payload of a consensus message
+func unmarshalConsensusMessage(payload []byte) (*msg_pb.Message, error) {
+	msg := &msg_pb.Message{}
+	if err := protobuf.Unmarshal(payload, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
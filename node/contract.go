@@ -27,7 +27,7 @@ const (
 func (node *Node) GetNonceOfAddress(address common.Address) uint64 {
 	state, err := node.Blockchain().State()
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("Failed to get chain state")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to get chain state")
 		return 0
 	}
 	return state.GetNonce(address)
@@ -37,7 +37,7 @@ func (node *Node) GetNonceOfAddress(address common.Address) uint64 {
 func (node *Node) GetBalanceOfAddress(address common.Address) (*big.Int, error) {
 	state, err := node.Blockchain().State()
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("Failed to get chain state")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to get chain state")
 		return nil, err
 	}
 	balance := big.NewInt(0)
@@ -55,7 +55,7 @@ func (node *Node) AddFaucetContractToPendingTransactions() {
 	contractFunds := big.NewInt(FaucetContractFund)
 	contractFunds = contractFunds.Mul(contractFunds, big.NewInt(denominations.One))
 	mycontracttx, _ := types.SignTx(
-		types.NewContractCreation(uint64(0), node.Consensus.ShardID, contractFunds, params.TxGasContractCreation*10, nil, dataEnc),
+		types.NewContractCreation(uint64(0), node.NodeConfig.ShardID, contractFunds, params.TxGasContractCreation*10, nil, dataEnc),
 		types.HomesteadSigner{},
 		priKey)
 	node.ContractAddresses = append(node.ContractAddresses, crypto.CreateAddress(crypto.PubkeyToAddress(priKey.PublicKey), uint64(0)))
@@ -69,8 +69,8 @@ func (node *Node) CallFaucetContract(address common.Address) common.Hash {
 	}
 	// Temporary code to workaround explorer issue for searching new addresses (https://github.com/harmony-one/harmony/issues/503)
 	nonce := atomic.AddUint64(&node.ContractDeployerCurrentNonce, 1)
-	tx, _ := types.SignTx(types.NewTransaction(nonce-1, address, node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, nil), types.HomesteadSigner{}, node.ContractDeployerKey)
-	utils.Logger().Info().Str("Address", common2.MustAddressToBech32(address)).Msg("Sending placeholder token to ")
+	tx, _ := types.SignTx(types.NewTransaction(nonce-1, address, node.NodeConfig.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, nil), types.HomesteadSigner{}, node.ContractDeployerKey)
+	utils.ModuleLogger(utils.ModuleNode).Info().Str("Address", common2.MustAddressToBech32(address)).Msg("Sending placeholder token to ")
 	node.addPendingTransactions(types.Transactions{tx})
 	// END Temporary code
 
@@ -81,20 +81,20 @@ func (node *Node) CallFaucetContract(address common.Address) common.Hash {
 func (node *Node) callGetFreeTokenWithNonce(address common.Address, nonce uint64) common.Hash {
 	abi, err := abi.JSON(strings.NewReader(contracts.FaucetABI))
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("Failed to generate faucet contract's ABI")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to generate faucet contract's ABI")
 		return common.Hash{}
 	}
 	bytesData, err := abi.Pack("request", address)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("Failed to generate ABI function bytes data")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to generate ABI function bytes data")
 		return common.Hash{}
 	}
 	if len(node.ContractAddresses) == 0 {
-		utils.Logger().Error().Err(err).Msg("Failed to find the contract address")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to find the contract address")
 		return common.Hash{}
 	}
-	tx, _ := types.SignTx(types.NewTransaction(nonce, node.ContractAddresses[0], node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, bytesData), types.HomesteadSigner{}, node.ContractDeployerKey)
-	utils.Logger().Info().Str("Address", common2.MustAddressToBech32(address)).Msg("Sending Free Token to ")
+	tx, _ := types.SignTx(types.NewTransaction(nonce, node.ContractAddresses[0], node.NodeConfig.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, bytesData), types.HomesteadSigner{}, node.ContractDeployerKey)
+	utils.ModuleLogger(utils.ModuleNode).Info().Str("Address", common2.MustAddressToBech32(address)).Msg("Sending Free Token to ")
 
 	node.addPendingTransactions(types.Transactions{tx})
 	return tx.Hash()
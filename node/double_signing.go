@@ -1,6 +1,7 @@
 package node
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/shard"
@@ -15,15 +16,43 @@ func (node *Node) processSlashCandidateMessage(msgPayload []byte) {
 	candidates := slash.Records{}
 
 	if err := rlp.DecodeBytes(msgPayload, &candidates); err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).Msg("unable to decode slash candidates message")
 		return
 	}
 
+	// only verify the first report of each double-signing event
+	candidates = node.slashGossip.unseen(candidates)
+	if len(candidates) == 0 {
+		return
+	}
+
 	if err := node.Blockchain().AddPendingSlashingCandidates(
 		candidates,
 	); err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).Msg("unable to add slash candidates to pending ")
+		return
+	}
+	// the events are marked as seen only once verified, so a forged report
+	// does not hide the genuine reports of the same event
+	node.slashGossip.markSeen(pendingEvents(
+		candidates, node.Blockchain().ReadPendingSlashingCandidates(),
+	))
+}
+
+// pendingEvents returns the candidates whose events are among the pending
+// slashing candidates
+func pendingEvents(candidates, pending slash.Records) slash.Records {
+	pendingKeys := map[common.Hash]struct{}{}
+	for i := range pending {
+		pendingKeys[pending[i].EventKey()] = struct{}{}
+	}
+	verified := slash.Records{}
+	for i := range candidates {
+		if _, ok := pendingKeys[candidates[i].EventKey()]; ok {
+			verified = append(verified, candidates[i])
+		}
 	}
+	return verified
 }
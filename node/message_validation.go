@@ -0,0 +1,151 @@
+package node
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/api/proto"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxBlockMessageSize is the largest message carrying blocks, the
+	// largest message the pubsub accepts
+	maxBlockMessageSize = 2_145_728
+	// maxVoteMessageSize is the largest consensus message not carrying a
+	// block, holding signatures and bitmaps only
+	maxVoteMessageSize = 64 * 1024
+	// maxPingMessageSize is the largest ping message
+	maxPingMessageSize = 64 * 1024
+	// maxHeadBeaconMessageSize is the largest head beacon message
+	maxHeadBeaconMessageSize = 512
+)
+
+// Reasons of rejecting an inbound message, each with its own counter
+const (
+	invalidShort    = "short"
+	invalidCategory = "category"
+	invalidType     = "type"
+	invalidSize     = "size"
+	invalidEnvelope = "envelope"
+	invalidService  = "service"
+)
+
+// invalidMessageCounters count the inbound messages rejected, by reason
+var invalidMessageCounters = map[string]metrics.Counter{}
+
+func init() {
+	for _, reason := range []string{
+		invalidShort, invalidCategory, invalidType,
+		invalidSize, invalidEnvelope, invalidService,
+	} {
+		invalidMessageCounters[reason] = metrics.NewRegisteredCounter("p2p/msg/invalid/"+reason, nil)
+	}
+}
+
+// invalidMessageError is the reason an inbound message is rejected
+type invalidMessageError struct {
+	reason string
+	err    error
+}
+
+func (e *invalidMessageError) Error() string {
+	return e.reason + ": " + e.err.Error()
+}
+
+func invalidMessage(reason string, format string, args ...interface{}) error {
+	return &invalidMessageError{reason, errors.Errorf(format, args...)}
+}
+
+// validateMessage checks the shape of the content of an inbound message before
+// it is dispatched: its category and type are known, its size is within the
+// limit of its type and, for a consensus message, the envelope parses and
+// holds the request of its type. The rejected messages are counted by reason.
+func validateMessage(content []byte) error {
+	err := checkMessage(content)
+	if e, ok := err.(*invalidMessageError); ok {
+		invalidMessageCounters[e.reason].Inc(1)
+	}
+	return err
+}
+
+func checkMessage(content []byte) error {
+	if len(content) < proto.MessageCategoryBytes+proto.MessageTypeBytes {
+		return invalidMessage(invalidShort, "message of %d bytes", len(content))
+	}
+	category, _ := proto.GetMessageCategory(content)
+	switch category {
+	case proto.Consensus:
+		return checkConsensusMessage(content[proto.MessageCategoryBytes:])
+	case proto.Node:
+		return checkNodeMessage(content)
+	}
+	return invalidMessage(invalidCategory, "unknown message category %d", category)
+}
+
+// checkConsensusMessage checks the envelope of a consensus message
+func checkConsensusMessage(payload []byte) error {
+	if len(payload) > maxBlockMessageSize {
+		return invalidMessage(invalidSize, "consensus message of %d bytes", len(payload))
+	}
+	msg, err := unmarshalConsensusMessage(payload)
+	if err != nil {
+		return invalidMessage(invalidEnvelope, "%v", err)
+	}
+	if msg.GetServiceType() != msg_pb.ServiceType_CONSENSUS {
+		return invalidMessage(invalidService, "service type %s", msg.GetServiceType())
+	}
+	maxSize, hasRequest := maxVoteMessageSize, false
+	switch msg.GetType() {
+	case msg_pb.MessageType_ANNOUNCE, msg_pb.MessageType_PREPARED:
+		maxSize = maxBlockMessageSize
+		hasRequest = msg.GetConsensus() != nil
+	case msg_pb.MessageType_PREPARE, msg_pb.MessageType_COMMIT, msg_pb.MessageType_COMMITTED:
+		hasRequest = msg.GetConsensus() != nil
+	case msg_pb.MessageType_VIEWCHANGE, msg_pb.MessageType_NEWVIEW:
+		hasRequest = msg.GetViewchange() != nil
+	default:
+		return invalidMessage(invalidType, "consensus message type %s", msg.GetType())
+	}
+	if !hasRequest {
+		return invalidMessage(invalidService, "%s message without its request", msg.GetType())
+	}
+	if len(payload) > maxSize {
+		return invalidMessage(invalidSize, "%s message of %d bytes", msg.GetType(), len(payload))
+	}
+	return nil
+}
+
+// checkNodeMessage checks the type and size of a node message
+func checkNodeMessage(content []byte) error {
+	msgType, _ := proto.GetMessageType(content)
+	payload, _ := proto.GetMessagePayload(content)
+	var maxSize int
+	switch proto_node.MessageType(msgType) {
+	case proto_node.Transaction, proto_node.Staking:
+		maxSize = types.MaxEncodedPoolTransactionSize
+	case proto_node.Block:
+		if len(payload) < 1 {
+			return invalidMessage(invalidShort, "block message without subtype")
+		}
+		switch proto_node.BlockMessageType(payload[0]) {
+		case proto_node.Sync, proto_node.SignedSync, proto_node.CrossLink,
+			proto_node.Receipt, proto_node.SlashCandidate, proto_node.EpochState,
+			proto_node.CXDelivered:
+		default:
+			return invalidMessage(invalidType, "block message subtype %d", payload[0])
+		}
+		maxSize = maxBlockMessageSize
+	case proto_node.PING:
+		maxSize = maxPingMessageSize
+	case proto_node.ChainHead:
+		maxSize = maxHeadBeaconMessageSize
+	default:
+		return invalidMessage(invalidType, "node message type %d", msgType)
+	}
+	if len(payload) > maxSize {
+		return invalidMessage(invalidSize, "node message type %d of %d bytes", msgType, len(payload))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/harmony-one/harmony/api/proto"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func consensusContent(t *testing.T, msg *msg_pb.Message) []byte {
+	payload, err := protobuf.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proto.ConstructConsensusMessage(payload)
+}
+
+func TestValidateMessage(t *testing.T) {
+	prepare := &msg_pb.Message{
+		ServiceType: msg_pb.ServiceType_CONSENSUS,
+		Type:        msg_pb.MessageType_PREPARE,
+		Request:     &msg_pb.Message_Consensus{Consensus: &msg_pb.ConsensusRequest{BlockNum: 1}},
+	}
+	bigPrepare := protobuf.Clone(prepare).(*msg_pb.Message)
+	bigPrepare.GetConsensus().Payload = make([]byte, maxVoteMessageSize)
+	staking := protobuf.Clone(prepare).(*msg_pb.Message)
+	staking.ServiceType = msg_pb.ServiceType_STAKING
+	wrongRequest := protobuf.Clone(prepare).(*msg_pb.Message)
+	wrongRequest.Type = msg_pb.MessageType_VIEWCHANGE
+	drand := protobuf.Clone(prepare).(*msg_pb.Message)
+	drand.Type = msg_pb.MessageType_DRAND_INIT
+
+	tests := []struct {
+		name    string
+		content []byte
+		reason  string
+	}{
+		{"prepare", consensusContent(t, prepare), ""},
+		{"transactions", proto_node.ConstructTransactionListMessageAccount(types.Transactions{}), ""},
+		{"short", []byte{byte(proto.Node)}, invalidShort},
+		{"drand category", []byte{byte(proto.DRand), 0, 0}, invalidCategory},
+		{"garbage envelope", []byte{byte(proto.Consensus), 0xff, 0xff, 0xff}, invalidEnvelope},
+		{"staking service", consensusContent(t, staking), invalidService},
+		{"mismatched request", consensusContent(t, wrongRequest), invalidService},
+		{"deprecated type", consensusContent(t, drand), invalidType},
+		{"oversized vote", consensusContent(t, bigPrepare), invalidSize},
+		{"unknown node type", []byte{byte(proto.Node), byte(proto_node.Client), 0}, invalidType},
+		{"unknown block subtype", []byte{byte(proto.Node), byte(proto_node.Block), 0xff}, invalidType},
+		{"cross shard deliveries", proto_node.ConstructCXDeliveredMessage(&proto_node.CXDeliveryProof{
+			Header: blockfactory.ForTest.NewHeader(big.NewInt(0)),
+		}), ""},
+		{"head beacon", proto_node.ConstructHeadBeaconMessage(&proto_node.HeadBeacon{
+			Signature: make([]byte, 96),
+		}), ""},
+		{"oversized head beacon", append(
+			[]byte{byte(proto.Node), byte(proto_node.ChainHead)},
+			make([]byte, maxHeadBeaconMessageSize+1)...,
+		), invalidSize},
+		{"oversized transactions", append(
+			[]byte{byte(proto.Node), byte(proto_node.Transaction)},
+			make([]byte, types.MaxEncodedPoolTransactionSize+1)...,
+		), invalidSize},
+	}
+	for _, test := range tests {
+		err := validateMessage(test.content)
+		if test.reason == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", test.name, err)
+			}
+			continue
+		}
+		e, ok := err.(*invalidMessageError)
+		if !ok || e.reason != test.reason {
+			t.Errorf("%s: got error %v, want reason %s", test.name, err, test.reason)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"math/big"
 	"os"
-	"strings"
 	"sync"
 	"time"
 
@@ -16,16 +15,22 @@ import (
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/beaconsig"
+	"github.com/harmony-one/harmony/api/service/storageguard"
 	"github.com/harmony-one/harmony/api/service/syncing"
 	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/api/service/telemetry"
 	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/hmy/gasprice"
 	"github.com/harmony-one/harmony/internal/chain"
+	"github.com/harmony-one/harmony/internal/checkpoint"
 	common2 "github.com/harmony-one/harmony/internal/common"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/internal/pipe"
 	"github.com/harmony-one/harmony/internal/shardchain"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node/worker"
@@ -35,7 +40,7 @@ import (
 	"github.com/harmony-one/harmony/staking/slash"
 	staking "github.com/harmony-one/harmony/staking/types"
 	"github.com/harmony-one/harmony/webhooks"
-	libp2p_pubsub "github.com/libp2p/go-libp2p-pubsub"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/semaphore"
 )
@@ -98,6 +103,11 @@ const (
 	SyncIDLength = 20
 )
 
+const (
+	proposedBlockPipeSize    = 1
+	proposedBlockSendTimeout = 5 * time.Second
+)
+
 // use to push new block to outofsync node
 type syncConfig struct {
 	timestamp int64
@@ -107,13 +117,14 @@ type syncConfig struct {
 // Node represents a protocol-participating node in the network
 type Node struct {
 	Consensus             *consensus.Consensus              // Consensus object containing all Consensus related data (e.g. committee members, signatures, commits)
-	BlockChannel          chan *types.Block                 // The channel to send newly proposed blocks
+	BlockChannel          *pipe.Pipe                        // The pipe to send newly proposed blocks
 	ConfirmedBlockChannel chan *types.Block                 // The channel to send confirmed blocks
 	BeaconBlockChannel    chan *types.Block                 // The channel to send beacon blocks for non-beaconchain nodes
 	pendingCXReceipts     map[string]*types.CXReceiptsProof // All the receipts received but not yet processed for Consensus
 	pendingCXMutex        sync.Mutex
 	// Shard databases
 	shardChains shardchain.Collection
+	isArchival  bool           // whether the shard chains keep their full history
 	Client      *client.Client // The presence of a client object means this node will also act as a client
 	SelfPeer    p2p.Peer
 	// TODO: Neighbors should store only neighbor nodes in the same shard
@@ -128,8 +139,17 @@ type Node struct {
 	downloaderServer     *downloader.Server
 	// Syncing component.
 	syncID                 [SyncIDLength]byte // a unique ID for the node during the state syncing process with peers
+	syncLock               sync.Mutex         // guards the creation of stateSync and beaconSync
 	stateSync, beaconSync  *syncing.StateSync
 	peerRegistrationRecord map[string]*syncConfig // record registration time (unixtime) of peers begin in syncing
+	syncIDRegistry         *syncIDRegistry        // peers holding the syncIDs of the registrations
+	syncPeerHandshakes     *lru.Cache             // incoming sync peer address => *downloader_pb.Handshake, the latest peers only
+	shardHeights           *syncing.HeightTable   // heights of the synced shards as reported by the sync peers
+	clockSkew              *syncing.ClockSkew     // skew of the local clock to the clocks of the sync peers
+	lastForkCheck          time.Time              // last time the local chain was checked for a minority fork
+	commitSigChecked       bool                   // whether the commit signature of the head at startup is known
+	resyncRequested        bool                   // set by the consensus watchdog, guarded by stateMutex
+	Checkpoint             *checkpoint.Checkpoint // trusted checkpoint to sync a fresh chain from instead of genesis
 	SyncingPeerProvider    SyncingPeerProvider
 	// The p2p host used to send/receive p2p messages
 	host p2p.Host
@@ -156,6 +176,39 @@ type Node struct {
 	keysToAddrsMutex sync.Mutex
 	// TransactionErrorSink contains error messages for any failed transaction, in memory only
 	TransactionErrorSink *types.TransactionErrorSink
+	// Dispatches the received consensus messages by priority
+	consensusDispatcher *consensusDispatcher
+	// peer of the leader the transactions are sent to under the leader
+	// fan-out, and the blocks proposed header first fetched from
+	leaderPeer leaderPeer
+	// Progress of the last crosslink of each shard, tracked by the beacon leader
+	crossLinkProgress map[uint32]crossLinkProgress
+	// Connections to the shard peers serving headers for crosslink recovery
+	crossLinkSyncs map[uint32]*syncing.StateSync
+	// Double-signing events already gossiped and reports waiting to be broadcast
+	slashGossip *slashGossip
+	// Handlers run on each block committed by consensus
+	postConsensusHooks postConsensusHooks
+	// Chain export or import run through the API
+	chainDump chainDump
+	// BLS keys the sync peers can authenticate with
+	syncAuthKeys committeeKeys
+	// whether the deliveries of the outgoing cross-shard transfers are tracked
+	cxDeliveryTracking bool
+	// suggests the gas price from the recent blocks
+	gasPriceOracle *gasprice.Oracle
+	// storage guard and the state of the optional writes it pauses
+	storageGuard *storageguard.Service
+	storage      storagePause
+	// commit signatures of the recent beacon blocks, nil on the beacon chain
+	beaconCommitSigs *beaconsig.Service
+	// archival providers the sync falls back to and the last sync progress
+	archivalProviders *syncing.ArchivalProviders
+	syncProgress      syncProgress
+	// interval at which the validator publishes its head beacon, 0 for never
+	headBeaconInterval time.Duration
+	// aggregates the head beacons of the validators, nil if not collecting
+	headCollector *telemetry.HeadCollector
 }
 
 // Blockchain returns the blockchain for the node's current shard.
@@ -163,7 +216,7 @@ func (node *Node) Blockchain() *core.BlockChain {
 	shardID := node.NodeConfig.ShardID
 	bc, err := node.shardChains.ShardChain(shardID)
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Uint32("shardID", shardID).
 			Err(err).
 			Msg("cannot get shard chain")
@@ -175,7 +228,7 @@ func (node *Node) Blockchain() *core.BlockChain {
 func (node *Node) Beaconchain() *core.BlockChain {
 	bc, err := node.shardChains.ShardChain(shard.BeaconChainShardID)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("cannot get beaconchain")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("cannot get beaconchain")
 	}
 	return bc
 }
@@ -183,36 +236,13 @@ func (node *Node) Beaconchain() *core.BlockChain {
 // TODO: make this batch more transactions
 func (node *Node) tryBroadcast(tx *types.Transaction) {
 	msg := proto_node.ConstructTransactionListMessageAccount(types.Transactions{tx})
-
-	shardGroupID := nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(tx.ShardID()))
-	utils.Logger().Info().Str("shardGroupID", string(shardGroupID)).Msg("tryBroadcast")
-
-	for attempt := 0; attempt < NumTryBroadCast; attempt++ {
-		if err := node.host.SendMessageToGroups([]nodeconfig.GroupID{shardGroupID},
-			p2p.ConstructMessage(msg)); err != nil && attempt < NumTryBroadCast {
-			utils.Logger().Error().Int("attempt", attempt).Msg("Error when trying to broadcast tx")
-		} else {
-			break
-		}
-	}
+	node.broadcastTxMessage(tx.ShardID(), msg)
 }
 
 func (node *Node) tryBroadcastStaking(stakingTx *staking.StakingTransaction) {
 	msg := proto_node.ConstructStakingTransactionListMessageAccount(staking.StakingTransactions{stakingTx})
-
-	shardGroupID := nodeconfig.NewGroupIDByShardID(
-		nodeconfig.ShardID(shard.BeaconChainShardID),
-	) // broadcast to beacon chain
-	utils.Logger().Info().Str("shardGroupID", string(shardGroupID)).Msg("tryBroadcastStaking")
-
-	for attempt := 0; attempt < NumTryBroadCast; attempt++ {
-		if err := node.host.SendMessageToGroups([]nodeconfig.GroupID{shardGroupID},
-			p2p.ConstructMessage(msg)); err != nil && attempt < NumTryBroadCast {
-			utils.Logger().Error().Int("attempt", attempt).Msg("Error when trying to broadcast staking tx")
-		} else {
-			break
-		}
-	}
+	// broadcast to beacon chain
+	node.broadcastTxMessage(shard.BeaconChainShardID, msg)
 }
 
 // Add new transactions to the pending transaction list.
@@ -224,7 +254,7 @@ func (node *Node) addPendingTransactions(newTxs types.Transactions) []error {
 	errs := node.TxPool.AddRemotes(poolTxs)
 
 	pendingCount, queueCount := node.TxPool.Stats()
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Int("length of newTxs", len(newTxs)).
 		Int("totalPending", pendingCount).
 		Int("totalQueued", queueCount).
@@ -242,7 +272,7 @@ func (node *Node) addPendingStakingTransactions(newStakingTxs staking.StakingTra
 		}
 		errs := node.TxPool.AddRemotes(poolTxs)
 		pendingCount, queueCount := node.TxPool.Stats()
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Int("length of newStakingTxs", len(poolTxs)).
 			Int("totalPending", pendingCount).
 			Int("totalQueued", queueCount).
@@ -263,25 +293,27 @@ func (node *Node) AddPendingStakingTransaction(
 				return errs[i]
 			}
 		}
-		utils.Logger().Info().Str("Hash", newStakingTx.Hash().Hex()).Msg("Broadcasting Staking Tx")
+		utils.ModuleLogger(utils.ModuleNode).Info().Str("Hash", newStakingTx.Hash().Hex()).Msg("Broadcasting Staking Tx")
 		node.tryBroadcastStaking(newStakingTx)
 	}
 	return nil
 }
 
 // AddPendingTransaction adds one new transaction to the pending transaction list.
+// Transactions for another shard are relayed to that shard instead.
 // This is only called from SDK.
 func (node *Node) AddPendingTransaction(newTx *types.Transaction) error {
-	if newTx.ShardID() == node.NodeConfig.ShardID {
-		errs := node.addPendingTransactions(types.Transactions{newTx})
-		for i := range errs {
-			if errs[i] != nil {
-				return errs[i]
-			}
+	if newTx.ShardID() != node.NodeConfig.ShardID {
+		return node.relayTransaction(newTx)
+	}
+	errs := node.addPendingTransactions(types.Transactions{newTx})
+	for i := range errs {
+		if errs[i] != nil {
+			return errs[i]
 		}
-		utils.Logger().Info().Str("Hash", newTx.Hash().Hex()).Msg("Broadcasting Tx")
-		node.tryBroadcast(newTx)
 	}
+	utils.ModuleLogger(utils.ModuleNode).Info().Str("Hash", newTx.Hash().Hex()).Msg("Broadcasting Tx")
+	node.tryBroadcast(newTx)
 	return nil
 }
 
@@ -291,7 +323,7 @@ func (node *Node) AddPendingReceipts(receipts *types.CXReceiptsProof) {
 	defer node.pendingCXMutex.Unlock()
 
 	if receipts.ContainsEmptyField() {
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Int("totalPendingReceipts", len(node.pendingCXReceipts)).
 			Msg("CXReceiptsProof contains empty field")
 		return
@@ -303,15 +335,15 @@ func (node *Node) AddPendingReceipts(receipts *types.CXReceiptsProof) {
 	// Sanity checks
 
 	if err := node.Blockchain().Validator().ValidateCXReceiptsProof(receipts); err != nil {
-		if !strings.Contains(err.Error(), rawdb.MsgNoShardStateFromDB) {
-			utils.Logger().Error().Err(err).Msg("[AddPendingReceipts] Invalid CXReceiptsProof")
+		if errors.Cause(err) != rawdb.ErrNoShardStateFromDB {
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[AddPendingReceipts] Invalid CXReceiptsProof")
 			return
 		}
 	}
 
 	// cross-shard receipt should not be coming from our shard
-	if s := node.Consensus.ShardID; s == shardID {
-		utils.Logger().Info().
+	if s := node.NodeConfig.ShardID; s == shardID {
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Uint32("my-shard", s).
 			Uint32("receipt-shard", shardID).
 			Msg("ShardID of incoming receipt was same as mine")
@@ -320,7 +352,7 @@ func (node *Node) AddPendingReceipts(receipts *types.CXReceiptsProof) {
 
 	if e := receipts.Header.Epoch(); blockNum == 0 ||
 		!node.Blockchain().Config().AcceptsCrossTx(e) {
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Uint64("incoming-epoch", e.Uint64()).
 			Msg("Incoming receipt had meaningless epoch")
 		return
@@ -331,7 +363,7 @@ func (node *Node) AddPendingReceipts(receipts *types.CXReceiptsProof) {
 	// DDoS protection
 	const maxCrossTxnSize = 4096
 	if s := len(node.pendingCXReceipts); s >= maxCrossTxnSize {
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Int("pending-cx-receipts-size", s).
 			Int("pending-cx-receipts-limit", maxCrossTxnSize).
 			Msg("Current pending cx-receipts reached size limit")
@@ -339,43 +371,56 @@ func (node *Node) AddPendingReceipts(receipts *types.CXReceiptsProof) {
 	}
 
 	if _, ok := node.pendingCXReceipts[key]; ok {
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Int("totalPendingReceipts", len(node.pendingCXReceipts)).
 			Msg("Already Got Same Receipt message")
 		return
 	}
 	node.pendingCXReceipts[key] = receipts
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Int("totalPendingReceipts", len(node.pendingCXReceipts)).
 		Msg("Got ONE more receipt message")
 }
 
 // Start kicks off the node message handling
 func (node *Node) Start() error {
-	allTopics := node.host.AllTopics()
-	if len(allTopics) == 0 {
+	if node.host.IsSentryMode() {
+		// the messages are relayed by the sentries, see handleRelayedMessage
+		select {}
+	}
+	allSubs, err := node.host.AllSubscriptions()
+	if err != nil {
+		return err
+	}
+	if len(allSubs) == 0 {
 		return errors.New("have no topics to listen to")
 	}
-	weighted := make([]*semaphore.Weighted, len(allTopics))
+	weighted := make([]*semaphore.Weighted, len(allSubs))
 	const maxMessageHandlers = 200
 	ctx := context.Background()
 	ownID := node.host.GetID()
 	errChan := make(chan error)
 
-	for i, topic := range allTopics {
-		sub, err := topic.Subscribe()
-		if err != nil {
-			return err
-		}
+	for i, sub := range allSubs {
 		weighted[i] = semaphore.NewWeighted(maxMessageHandlers)
-		msgChan := make(chan *libp2p_pubsub.Message)
+		msgChan := make(chan *p2p.Message)
 
-		go func(msgChan chan *libp2p_pubsub.Message, sem *semaphore.Weighted) {
+		go func(msgChan chan *p2p.Message, sem *semaphore.Weighted) {
 			for msg := range msgChan {
 				payload := msg.GetData()
 				if len(payload) < p2pMsgPrefixSize {
 					continue
 				}
+				if err := validateMessage(payload[p2pMsgPrefixSize:]); err != nil {
+					utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).
+						Str("from", msg.GetFrom().Pretty()).
+						Msg("dropping invalid incoming message")
+					continue
+				}
+				// consensus messages are dispatched by priority on their own
+				if node.dispatchConsensusMessage(payload[p2pMsgPrefixSize:], msg.GetFrom()) {
+					continue
+				}
 				if sem.TryAcquire(1) {
 					go func() {
 						node.HandleMessage(
@@ -383,14 +428,17 @@ func (node *Node) Start() error {
 						)
 						sem.Release(1)
 					}()
+				} else if node.host.IsTrustedPeer(msg.GetFrom()) {
+					// trusted peers are not subject to the handler limit
+					go node.HandleMessage(payload[p2pMsgPrefixSize:], msg.GetFrom())
 				} else {
-					utils.Logger().Info().
+					utils.ModuleLogger(utils.ModuleNode).Info().
 						Msg("could not acquire semaphore to process incoming message")
 				}
 			}
 		}(msgChan, weighted[i])
 
-		go func(msgChan chan *libp2p_pubsub.Message) {
+		go func(sub p2p.Subscription, msgChan chan *p2p.Message) {
 			for {
 				nextMsg, err := sub.Next(ctx)
 				if err != nil {
@@ -400,13 +448,14 @@ func (node *Node) Start() error {
 				if nextMsg.GetFrom() == ownID {
 					continue
 				}
+				node.host.RelayToValidators(sub.Topic(), nextMsg.GetData())
 				msgChan <- nextMsg
 			}
-		}(msgChan)
+		}(sub, msgChan)
 	}
 
 	for err := range errChan {
-		utils.Logger().Info().Err(err).Msg("issue while handling incoming p2p message")
+		utils.ModuleLogger(utils.ModuleNode).Info().Err(err).Msg("issue while handling incoming p2p message")
 	}
 	// NOTE never gets here
 	return nil
@@ -425,20 +474,56 @@ func New(
 	blacklist map[common.Address]struct{},
 	isArchival bool,
 ) *Node {
-	node := Node{}
-	node.unixTimeAtNodeStart = time.Now().Unix()
-	node.TransactionErrorSink = types.NewTransactionErrorSink()
 	// Get the node config that's created in the harmony.go program.
+	nodeConfig := nodeconfig.GetDefaultConfig()
 	if consensusObj != nil {
-		node.NodeConfig = nodeconfig.GetShardConfig(consensusObj.ShardID)
-	} else {
-		node.NodeConfig = nodeconfig.GetDefaultConfig()
+		nodeConfig = nodeconfig.GetShardConfig(consensusObj.ShardID)
 	}
+	return newNode(host, consensusObj, nodeConfig, chainDBFactory, blacklist, isArchival)
+}
+
+// NewRPCNode creates a node of the given shard in the RPC node role, keeping
+// up with the chains by sync and serving the RPC, without consensus.
+func NewRPCNode(
+	host p2p.Host,
+	shardID uint32,
+	chainDBFactory shardchain.DBFactory,
+	blacklist map[common.Address]struct{},
+	isArchival bool,
+) *Node {
+	nodeConfig := nodeconfig.GetShardConfig(shardID)
+	nodeConfig.SetRole(nodeconfig.RPCNode)
+	return newNode(host, nil, nodeConfig, chainDBFactory, blacklist, isArchival)
+}
+
+func newNode(
+	host p2p.Host,
+	consensusObj *consensus.Consensus,
+	nodeConfig *nodeconfig.ConfigType,
+	chainDBFactory shardchain.DBFactory,
+	blacklist map[common.Address]struct{},
+	isArchival bool,
+) *Node {
+	node := Node{}
+	node.unixTimeAtNodeStart = time.Now().Unix()
+	node.TransactionErrorSink = types.NewTransactionErrorSink()
+	node.shardHeights = syncing.NewHeightTable()
+	node.syncPeerHandshakes = mustNewLRU(syncPeerHandshakesLimit)
+	node.crossLinkProgress = map[uint32]crossLinkProgress{}
+	node.crossLinkSyncs = map[uint32]*syncing.StateSync{}
+	node.slashGossip = newSlashGossip()
+	node.RegisterPostConsensusHook("webhooks/availability", 100, node.availabilityWebhook)
+	node.RegisterPostConsensusHook("epochstate/push", 10, node.pushEpochState)
+	node.NodeConfig = nodeConfig
+	node.isArchival = isArchival
+	node.clockSkew = syncing.NewClockSkew(node.NodeConfig.MaxClockSkew)
 
-	copy(node.syncID[:], GenerateRandomString(SyncIDLength))
 	if host != nil {
 		node.host = host
 		node.SelfPeer = host.GetSelfPeer()
+		node.syncID = syncIDFromPeerID(host.GetID())
+	} else {
+		copy(node.syncID[:], GenerateRandomString(SyncIDLength))
 	}
 
 	networkType := node.NodeConfig.GetNetworkType()
@@ -451,11 +536,24 @@ func New(
 	if isArchival {
 		collection.DisableCache()
 	}
+	collection.SetPrimary(node.NodeConfig.ShardID)
+	collection.EnableIdleClose(node.NodeConfig.ShardChainIdleTimeout)
+	collection.EnableIntegrityCheck(node.NodeConfig.IntegrityCheckDepth)
+	collection.EnableShardStateVerification()
+	if node.NodeConfig.StatePruneRetention > 0 {
+		collection.EnableStatePruning(
+			node.NodeConfig.StatePruneRetention, node.NodeConfig.StatePruneBudget,
+		)
+	}
 	node.shardChains = collection
 
-	if host != nil && consensusObj != nil {
-		// Consensus and associated channel to communicate blocks
-		node.Consensus = consensusObj
+	if host != nil {
+		// Consensus and associated channel to communicate blocks, none for
+		// an RPC node
+		if consensusObj != nil {
+			node.Consensus = consensusObj
+			node.consensusDispatcher = newConsensusDispatcher()
+		}
 
 		// Load the chains.
 		blockchain := node.Blockchain() // this also sets node.isFirstTime if the DB is fresh
@@ -474,14 +572,22 @@ func New(
 			os.Exit(-1)
 		}
 
-		node.BlockChannel = make(chan *types.Block)
+		node.BlockChannel = pipe.New(pipe.Config{
+			Name:        "node/proposedBlock",
+			Size:        proposedBlockPipeSize,
+			SendTimeout: proposedBlockSendTimeout,
+			Policy:      pipe.DropOldest,
+		})
 		node.ConfirmedBlockChannel = make(chan *types.Block)
 		node.BeaconBlockChannel = make(chan *types.Block)
 		txPoolConfig := core.DefaultTxPoolConfig
 		txPoolConfig.Blacklist = blacklist
 		node.TxPool = core.NewTxPool(txPoolConfig, node.Blockchain().Config(), blockchain, node.TransactionErrorSink)
-		node.CxPool = core.NewCxPool(core.CxPoolSize)
+		node.CxPool = core.NewCxPool(node.Blockchain().ChainDb(), core.CxPoolSize)
 		node.Worker = worker.New(node.Blockchain().Config(), blockchain, chain.Engine)
+		node.Worker.SetBlockLimits(node.NodeConfig.BlockLimits)
+		node.Worker.SetParallelism(node.NodeConfig.TxParallelism)
+		node.setupGasPriceOracle(gasprice.DefaultConfig)
 
 		if node.Blockchain().ShardID() != shard.BeaconChainShardID {
 			node.BeaconWorker = worker.New(
@@ -490,11 +596,12 @@ func New(
 		}
 
 		node.pendingCXReceipts = map[string]*types.CXReceiptsProof{}
-		node.Consensus.VerifiedNewBlock = make(chan *types.Block)
 		chain.Engine.SetBeaconchain(beaconChain)
 		// the sequence number is the next block number to be added in consensus protocol, which is
 		// always one more than current chain header block
-		node.Consensus.SetBlockNum(blockchain.CurrentBlock().NumberU64() + 1)
+		if node.Consensus != nil {
+			node.Consensus.SetBlockNum(blockchain.CurrentBlock().NumberU64() + 1)
+		}
 
 		// Add Faucet contract to all shards, so that on testnet, we can demo wallet in explorer
 		if networkType != nodeconfig.Mainnet {
@@ -505,18 +612,21 @@ func New(
 		}
 	}
 
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Interface("genesis block header", node.Blockchain().GetHeaderByNumber(0)).
 		Msg("Genesis block hash")
 	// Setup initial state of syncing.
 	node.peerRegistrationRecord = map[string]*syncConfig{}
+	node.syncIDRegistry = newSyncIDRegistry(time.Duration(broadcastTimeout))
 	node.startConsensus = make(chan struct{})
-	go node.bootstrapConsensus()
+	if node.Consensus != nil {
+		go node.bootstrapConsensus()
+	}
 	// Broadcast double-signers reported by consensus
 	if node.Consensus != nil {
 		go func() {
 			for doubleSign := range node.Consensus.SlashChan {
-				utils.Logger().Info().
+				utils.ModuleLogger(utils.ModuleNode).Info().
 					RawJSON("double-sign-candidate", []byte(doubleSign.String())).
 					Msg("double sign notified by consensus leader")
 				// no point to broadcast the slash if we aren't even in the right epoch yet
@@ -532,13 +642,13 @@ func New(
 					}
 				}
 				if node.NodeConfig.ShardID != shard.BeaconChainShardID {
-					go node.BroadcastSlash(&doubleSign)
+					node.queueSlashBroadcast(doubleSign)
 				} else {
 					records := slash.Records{doubleSign}
 					if err := node.Blockchain().AddPendingSlashingCandidates(
 						records,
 					); err != nil {
-						utils.Logger().Err(err).Msg("could not add new slash to ending slashes")
+						utils.ModuleLogger(utils.ModuleNode).Err(err).Msg("could not add new slash to ending slashes")
 					}
 				}
 			}
@@ -553,7 +663,7 @@ func New(
 // keys for consensus
 func (node *Node) InitConsensusWithValidators() (err error) {
 	if node.Consensus == nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Msg("[InitConsensusWithValidators] consenus is nil; Cannot figure out shardID")
 		return errors.New(
 			"[InitConsensusWithValidators] consenus is nil; Cannot figure out shardID",
@@ -563,7 +673,7 @@ func (node *Node) InitConsensusWithValidators() (err error) {
 	blockNum := node.Blockchain().CurrentBlock().NumberU64()
 	node.Consensus.SetMode(consensus.Listening)
 	epoch := shard.Schedule.CalcEpochNumber(blockNum)
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Uint64("blockNum", blockNum).
 		Uint32("shardID", shardID).
 		Uint64("epoch", epoch.Uint64()).
@@ -572,7 +682,7 @@ func (node *Node) InitConsensusWithValidators() (err error) {
 		epoch, node.Consensus.ChainReader,
 	)
 	if err != nil {
-		utils.Logger().Err(err).
+		utils.ModuleLogger(utils.ModuleNode).Err(err).
 			Uint64("blockNum", blockNum).
 			Uint32("shardID", shardID).
 			Uint64("epoch", epoch.Uint64()).
@@ -585,7 +695,7 @@ func (node *Node) InitConsensusWithValidators() (err error) {
 	}
 	pubKeys, err := subComm.BLSPublicKeys()
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Uint32("shardID", shardID).
 			Uint64("blockNum", blockNum).
 			Msg("[InitConsensusWithValidators] PublicKeys is Empty, Cannot update public keys")
@@ -597,7 +707,7 @@ func (node *Node) InitConsensusWithValidators() (err error) {
 
 	for _, key := range pubKeys {
 		if node.Consensus.PubKey.Contains(key) {
-			utils.Logger().Info().
+			utils.ModuleLogger(utils.ModuleNode).Info().
 				Uint64("blockNum", blockNum).
 				Int("numPubKeys", len(pubKeys)).
 				Msg("[InitConsensusWithValidators] Successfully updated public keys")
@@ -650,10 +760,17 @@ func (node *Node) initNodeConfiguration() (service.NodeConfig, chan p2p.Peer, er
 	}
 
 	groups := []nodeconfig.GroupID{
-		node.NodeConfig.GetShardGroupID(),
 		nodeconfig.NewClientGroupIDByShardID(shard.BeaconChainShardID),
 		node.NodeConfig.GetClientGroupID(),
 	}
+	// an RPC node stays off the consensus traffic of the shard group
+	if node.NodeConfig.Role() != nodeconfig.RPCNode {
+		groups = append(groups, node.NodeConfig.GetShardGroupID())
+	}
+
+	if node.headCollector != nil {
+		groups = append(groups, nodeconfig.NewTelemetryGroupID())
+	}
 
 	// force the side effect of topic join
 	if err := node.host.SendMessageToGroups(groups, []byte{}); err != nil {
@@ -672,9 +789,10 @@ func (node *Node) ServiceManager() *service.Manager {
 func (node *Node) ShutDown() {
 	node.Blockchain().Stop()
 	node.Beaconchain().Stop()
-	const msg = "Successfully shut down!\n"
-	utils.Logger().Print(msg)
-	fmt.Print(msg)
+	if node.Consensus != nil {
+		node.Consensus.FBFTLog.Close()
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().Msg("Successfully shut down!")
 	os.Exit(0)
 }
 
@@ -684,18 +802,9 @@ func (node *Node) populateSelfAddresses(epoch *big.Int) {
 	node.keysToAddrsEpoch = epoch
 
 	shardID := node.Consensus.ShardID
-	shardState, err := node.Consensus.ChainReader.ReadShardState(epoch)
-	if err != nil {
-		utils.Logger().Error().Err(err).
-			Int64("epoch", epoch.Int64()).
-			Uint32("shard-id", shardID).
-			Msg("[PopulateSelfAddresses] failed to read shard")
-		return
-	}
-
-	committee, err := shardState.FindCommitteeByID(shardID)
+	committee, err := node.Consensus.ChainReader.EpochChain().Committee(epoch, shardID)
 	if err != nil {
-		utils.Logger().Error().Err(err).
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
 			Int64("epoch", epoch.Int64()).
 			Uint32("shard-id", shardID).
 			Msg("[PopulateSelfAddresses] failed to find shard committee")
@@ -706,7 +815,7 @@ func (node *Node) populateSelfAddresses(epoch *big.Int) {
 		blsStr := blskey.SerializeToHexStr()
 		shardkey := shard.FromLibBLSPublicKeyUnsafe(blskey)
 		if shardkey == nil {
-			utils.Logger().Error().
+			utils.ModuleLogger(utils.ModuleNode).Error().
 				Int64("epoch", epoch.Int64()).
 				Uint32("shard-id", shardID).
 				Str("blskey", blsStr).
@@ -715,7 +824,7 @@ func (node *Node) populateSelfAddresses(epoch *big.Int) {
 		}
 		addr, err := committee.AddressForBLSKey(*shardkey)
 		if err != nil {
-			utils.Logger().Error().Err(err).
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
 				Int64("epoch", epoch.Int64()).
 				Uint32("shard-id", shardID).
 				Str("blskey", blsStr).
@@ -723,7 +832,7 @@ func (node *Node) populateSelfAddresses(epoch *big.Int) {
 			return
 		}
 		node.KeysToAddrs[blsStr] = *addr
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleNode).Debug().
 			Int64("epoch", epoch.Int64()).
 			Uint32("shard-id", shardID).
 			Str("bls-key", blsStr).
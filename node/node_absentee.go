@@ -0,0 +1,60 @@
+package node
+
+import (
+	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/absentee"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/harmony-one/harmony/webhooks"
+)
+
+// absenteeAlert is the payload of the webhook called when a key of the node
+// goes absent from the commit signature of a block
+type absenteeAlert struct {
+	Key      string `json:"bls-public-key"`
+	ShardID  uint32 `json:"shard-id"`
+	BlockNum uint64 `json:"block-num"`
+}
+
+// setupAbsentees registers the service recording the committee members absent
+// from the commit signatures of the blocks of the shard chain
+func (node *Node) setupAbsentees() {
+	node.serviceManager.RegisterService(
+		service.Absentee,
+		absentee.New(node.Blockchain(), node.ownBLSKeys, node.alertAbsent),
+	)
+}
+
+// ownBLSKeys returns the consensus keys of the node
+func (node *Node) ownBLSKeys() []shard.BLSPublicKey {
+	keys := []shard.BLSPublicKey{}
+	if node.NodeConfig.ConsensusPubKey == nil {
+		return keys
+	}
+	for _, pubKey := range node.NodeConfig.ConsensusPubKey.PublicKey {
+		var key shard.BLSPublicKey
+		if err := key.FromLibBLSPublicKey(pubKey); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// alertAbsent calls the availability webhook of the absences of the keys of
+// the node, if set
+func (node *Node) alertAbsent(key shard.BLSPublicKey, blockNum uint64) {
+	h := node.NodeConfig.WebHooks.Hooks
+	if h == nil || h.Availability == nil || h.Availability.OnOwnKeyAbsent == "" {
+		return
+	}
+	url, alert := h.Availability.OnOwnKeyAbsent, absenteeAlert{
+		Key:      key.Hex(),
+		ShardID:  node.NodeConfig.ShardID,
+		BlockNum: blockNum,
+	}
+	go func() {
+		if _, err := webhooks.DoPost(url, alert); err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Msg("[Absentee] Cannot call the absence webhook")
+		}
+	}()
+}
@@ -0,0 +1,70 @@
+package node
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/harmony-one/harmony/api/service/syncing"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/node/worker"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// syncProgress is the last change of the head of the synced chain
+type syncProgress struct {
+	height uint64
+	since  time.Time
+}
+
+// syncFromArchivalIfStalled fetches the blocks from the archival providers
+// when the head of the chain did not move for the fallback period while it is
+// older than that period, then lets the p2p sync resume from the new head
+func (node *Node) syncFromArchivalIfStalled(bc *core.BlockChain, worker *worker.Worker) {
+	providers := node.NodeConfig.ArchivalProviders
+	after := node.NodeConfig.ArchivalFallbackAfter
+	if len(providers) == 0 || after <= 0 {
+		return
+	}
+	head := bc.CurrentHeader()
+	now := time.Now()
+	if head.Number().Uint64() != node.syncProgress.height || node.syncProgress.since.IsZero() {
+		node.syncProgress = syncProgress{height: head.Number().Uint64(), since: now}
+		return
+	}
+	headTime := time.Unix(head.Time().Int64(), 0)
+	if now.Sub(node.syncProgress.since) < after || now.Sub(headTime) < after {
+		return
+	}
+
+	if node.archivalProviders == nil {
+		node.archivalProviders = syncing.NewArchivalProviders(providers)
+	}
+	utils.ModuleLogger(utils.ModuleSync).Warn().
+		Uint64("blockNum", head.Number().Uint64()).
+		Dur("stalledFor", now.Sub(node.syncProgress.since)).
+		Msg("[SYNC] p2p sync stalled, falling back to archival providers")
+	inserted, err := node.stateSync.SyncFromArchival(bc, worker, node.archivalProviders)
+	logger := utils.ModuleLogger(utils.ModuleSync).Info()
+	if err != nil {
+		logger = utils.ModuleLogger(utils.ModuleSync).Warn().Err(err)
+	}
+	logger.
+		Int("inserted", inserted).
+		Uint64("blockNum", bc.CurrentBlock().NumberU64()).
+		Msg("[SYNC] synced from archival providers, resuming p2p sync")
+	// the next fallback waits for another period without progress
+	node.syncProgress = syncProgress{height: bc.CurrentBlock().NumberU64(), since: time.Now()}
+}
+
+// ArchivalHandler serves the blocks of the chain of the node and of the beacon
+// chain to the nodes using this node as an archival provider
+func (node *Node) ArchivalHandler() http.Handler {
+	return syncing.ArchivalHandler(func(shardID uint32) (*core.BlockChain, error) {
+		if shardID != node.NodeConfig.ShardID && shardID != shard.BeaconChainShardID {
+			return nil, errors.Errorf("shard %d not served", shardID)
+		}
+		return node.shardChains.ShardChain(shardID)
+	})
+}
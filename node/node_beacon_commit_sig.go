@@ -0,0 +1,54 @@
+package node
+
+import (
+	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/beaconsig"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// setupBeaconCommitSigs registers the service caching the commit signatures of
+// the recent beacon blocks, on the shard chain nodes only
+func (node *Node) setupBeaconCommitSigs() {
+	if node.NodeConfig.ShardID == shard.BeaconChainShardID {
+		return
+	}
+	node.beaconCommitSigs = beaconsig.New(
+		beaconsig.DefaultCapacity, node.fetchBeaconCommitSig,
+		func() uint64 { return node.Beaconchain().CurrentHeader().Number().Uint64() },
+	)
+	node.serviceManager.RegisterService(service.BeaconCommitSig, node.beaconCommitSigs)
+}
+
+// fetchBeaconCommitSig returns the commit signature of the beacon block of the
+// given number stored locally, or else fetched from the beacon sync peers
+func (node *Node) fetchBeaconCommitSig(number uint64) ([]byte, error) {
+	beacon := node.Beaconchain()
+	if sig, err := beacon.ReadCommitSig(number); err == nil &&
+		len(sig) > shard.BLSSignatureSizeInBytes {
+		return sig, nil
+	}
+	_, beaconSync := node.syncs()
+	if beaconSync == nil {
+		return nil, errors.New("no beacon sync to fetch the commit signature from")
+	}
+	return beaconSync.FindCommitSig(beacon, number)
+}
+
+// cacheBeaconCommitSig caches the verified commit signature of the beacon
+// block of the given number
+func (node *Node) cacheBeaconCommitSig(number uint64, sigAndBitmap []byte) {
+	if node.beaconCommitSigs != nil {
+		node.beaconCommitSigs.Add(number, sigAndBitmap)
+	}
+}
+
+// GetBeaconCommitSig returns the commit signature and bitmap of the beacon
+// block of the given number, for the crosslinks to be verified against the
+// beacon chain without storing its blocks
+func (node *Node) GetBeaconCommitSig(number uint64) ([]byte, error) {
+	if node.beaconCommitSigs == nil {
+		return node.fetchBeaconCommitSig(number)
+	}
+	return node.beaconCommitSigs.GetBeaconCommitSig(number)
+}
@@ -0,0 +1,127 @@
+package node
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/pkg/errors"
+)
+
+// chainDump runs the chain exports and imports, one at a time
+type chainDump struct {
+	mu       sync.Mutex
+	progress *core.ChainDumpProgress
+}
+
+// dumpPath returns the path of the dump of the given name in the dump
+// directory of the node
+func (node *Node) dumpPath(name string) (string, error) {
+	dir := node.NodeConfig.ChainDumpDir
+	if dir == "" {
+		return "", errors.New("chain dumps are disabled, no dump directory set")
+	}
+	if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return "", errors.Errorf("invalid dump name %#v, must be a file name", name)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// start starts the given job unless one is running
+func (d *chainDump) start(progress *core.ChainDumpProgress, run func() (*core.ChainDumpManifest, error)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.progress != nil && d.progress.Finished == nil {
+		return errors.Errorf("chain %s of %s still running", d.progress.Operation, d.progress.Path)
+	}
+	progress.Started = time.Now()
+	d.progress = progress
+	go func() {
+		manifest, err := run()
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		now := time.Now()
+		progress.Finished, progress.Manifest = &now, manifest
+		if err != nil {
+			progress.Error = err.Error()
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
+				Str("operation", progress.Operation).
+				Str("path", progress.Path).
+				Msg("[ChainDump] Chain dump failed")
+		}
+	}()
+	return nil
+}
+
+// report sets the current block of the running job
+func (d *chainDump) report(progress *core.ChainDumpProgress, number uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	progress.Current = number
+}
+
+// ExportChain starts writing the canonical blocks from..to of the given shard
+// to the dump of the given name in the dump directory, with a manifest holding
+// its checksum. A to of 0 exports up to the head.
+func (node *Node) ExportChain(shardID uint32, name string, from, to uint64) error {
+	path, err := node.dumpPath(name)
+	if err != nil {
+		return err
+	}
+	progress := &core.ChainDumpProgress{
+		Operation: core.ChainDumpExport, Path: path, ShardID: shardID, From: from, To: to,
+	}
+	return node.chainDump.start(progress, func() (*core.ChainDumpManifest, error) {
+		bc, release, err := node.shardChains.AcquireShardChain(shardID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return bc.ExportDump(path, from, to, func(number uint64) {
+			node.chainDump.report(progress, number)
+		})
+	})
+}
+
+// ImportChain starts inserting the blocks of the dump of the given name in the
+// dump directory into the chain of the shard of the dump, once the dump is
+// checked against its manifest
+func (node *Node) ImportChain(name string) error {
+	path, err := node.dumpPath(name)
+	if err != nil {
+		return err
+	}
+	manifest, err := core.ReadChainDumpManifest(path)
+	if err != nil {
+		return err
+	}
+	progress := &core.ChainDumpProgress{
+		Operation: core.ChainDumpImport, Path: path,
+		ShardID: manifest.ShardID, From: manifest.From, To: manifest.To,
+	}
+	return node.chainDump.start(progress, func() (*core.ChainDumpManifest, error) {
+		bc, release, err := node.shardChains.AcquireShardChain(manifest.ShardID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return bc.ImportDump(path, func(number uint64) {
+			node.chainDump.report(progress, number)
+		})
+	})
+}
+
+// ChainDumpProgress returns the progress of the last chain export or import,
+// nil if none was started
+func (node *Node) ChainDumpProgress() *core.ChainDumpProgress {
+	node.chainDump.mu.Lock()
+	defer node.chainDump.mu.Unlock()
+	if node.chainDump.progress == nil {
+		return nil
+	}
+	progress := *node.chainDump.progress
+	return &progress
+}
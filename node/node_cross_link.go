@@ -23,7 +23,7 @@ var (
 func (node *Node) VerifyBlockCrossLinks(block *types.Block) error {
 	cxLinksData := block.Header().CrossLinks()
 	if len(cxLinksData) == 0 {
-		utils.Logger().Debug().Msgf("[CrossLinkVerification] Zero CrossLinks in the header")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Msgf("[CrossLinkVerification] Zero CrossLinks in the header")
 		return nil
 	}
 
@@ -58,21 +58,21 @@ func (node *Node) ProcessCrossLinkMessage(msgPayload []byte) {
 	if node.NodeConfig.ShardID == shard.BeaconChainShardID {
 		pendingCLs, err := node.Blockchain().ReadPendingCrossLinks()
 		if err == nil && len(pendingCLs) >= maxPendingCrossLinkSize {
-			utils.Logger().Debug().
+			utils.ModuleLogger(utils.ModuleNode).Debug().
 				Msgf("[ProcessingCrossLink] Pending Crosslink reach maximum size: %d", len(pendingCLs))
 			return
 		}
 
 		crosslinks := []types.CrossLink{}
 		if err := rlp.DecodeBytes(msgPayload, &crosslinks); err != nil {
-			utils.Logger().Error().
+			utils.ModuleLogger(utils.ModuleNode).Error().
 				Err(err).
 				Msg("[ProcessingCrossLink] Crosslink Message Broadcast Unable to Decode")
 			return
 		}
 
 		candidates := []types.CrossLink{}
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleNode).Debug().
 			Msgf("[ProcessingCrossLink] Received crosslinks: %d", len(crosslinks))
 
 		for i, cl := range crosslinks {
@@ -81,26 +81,26 @@ func (node *Node) ProcessCrossLinkMessage(msgPayload []byte) {
 			}
 			exist, err := node.Blockchain().ReadCrossLink(cl.ShardID(), cl.Number().Uint64())
 			if err == nil && exist != nil {
-				utils.Logger().Err(err).
+				utils.ModuleLogger(utils.ModuleNode).Err(err).
 					Msgf("[ProcessingCrossLink] Cross Link already exists, pass. Beacon Epoch: %d, Block num: %d, Epoch: %d, shardID %d", node.Blockchain().CurrentHeader().Epoch(), cl.Number(), cl.Epoch(), cl.ShardID())
 				continue
 			}
 
 			if err = node.VerifyCrossLink(cl); err != nil {
-				utils.Logger().Info().
+				utils.ModuleLogger(utils.ModuleNode).Info().
 					Str("cross-link-issue", err.Error()).
 					Msgf("[ProcessingCrossLink] Failed to verify new cross link for blockNum %d epochNum %d shard %d skipped: %v", cl.BlockNum(), cl.Epoch().Uint64(), cl.ShardID(), cl)
 				continue
 			}
 
 			candidates = append(candidates, cl)
-			utils.Logger().Debug().
+			utils.ModuleLogger(utils.ModuleNode).Debug().
 				Msgf("[ProcessingCrossLink] Committing for shardID %d, blockNum %d",
 					cl.ShardID(), cl.Number().Uint64(),
 				)
 		}
 		Len, _ := node.Blockchain().AddPendingCrossLinks(candidates)
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleNode).Debug().
 			Msgf("[ProcessingCrossLink] Add pending crosslinks,  total pending: %d", Len)
 	}
 }
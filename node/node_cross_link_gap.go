@@ -0,0 +1,199 @@
+package node
+
+import (
+	"time"
+
+	"github.com/harmony-one/harmony/api/service/syncing"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+const (
+	// crossLinkGapBeaconBlocks is the number of beacon blocks a shard can go
+	// without a new crosslink before its missing crosslinks are requested
+	crossLinkGapBeaconBlocks = 4
+	// crossLinkGapCheckInterval is the interval between two crosslink gap checks
+	crossLinkGapCheckInterval = 20 * time.Second
+)
+
+// crossLinkProgress records when the last crosslink of a shard last advanced
+type crossLinkProgress struct {
+	blockNum  uint64 // shard block number of the last crosslink
+	beaconNum uint64 // beacon block number at which blockNum was first seen
+}
+
+// crossLinkGap is a range of shard blocks whose crosslinks are missing on the
+// beacon chain, from and to included
+type crossLinkGap struct {
+	shardID  uint32
+	from, to uint64
+}
+
+// monitorCrossLinkGaps lets the beacon leader request the headers of the shard
+// blocks whose crosslinks are missing from the shard peers, and turn them into
+// pending crosslinks
+func (node *Node) monitorCrossLinkGaps() {
+	ticker := time.NewTicker(crossLinkGapCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		bc := node.Blockchain()
+		if !node.Consensus.IsLeader() ||
+			!bc.Config().IsCrossLink(bc.CurrentHeader().Epoch()) {
+			continue
+		}
+		for _, gap := range node.detectCrossLinkGaps() {
+			node.recoverCrossLinkGap(gap)
+		}
+	}
+}
+
+// detectCrossLinkGaps returns the missing crosslinks of the shards whose last
+// crosslink has not advanced for crossLinkGapBeaconBlocks beacon blocks,
+// up to crossLinkBatchSize blocks per shard
+func (node *Node) detectCrossLinkGaps() []crossLinkGap {
+	bc := node.Blockchain()
+	pending := map[uint32]map[uint64]struct{}{}
+	if pendingCLs, err := bc.ReadPendingCrossLinks(); err == nil {
+		for _, cl := range pendingCLs {
+			if _, ok := pending[cl.ShardID()]; !ok {
+				pending[cl.ShardID()] = map[uint64]struct{}{}
+			}
+			pending[cl.ShardID()][cl.BlockNum()] = struct{}{}
+		}
+	}
+	lastCrossLink := func(shardID uint32) (uint64, bool) {
+		lastLink, err := bc.ReadShardLastCrossLink(shardID)
+		if err != nil || lastLink == nil {
+			return 0, false
+		}
+		return lastLink.BlockNum(), true
+	}
+	return findCrossLinkGaps(
+		node.crossLinkProgress,
+		bc.CurrentBlock().NumberU64(),
+		shard.Schedule.InstanceForEpoch(bc.CurrentHeader().Epoch()).NumShards(),
+		lastCrossLink,
+		pending,
+	)
+}
+
+// findCrossLinkGaps updates the progress of the last crosslinks of the shards
+// at the given beacon block and returns the missing crosslinks of the shards
+// which have not advanced for crossLinkGapBeaconBlocks beacon blocks, skipping
+// the pending ones
+func findCrossLinkGaps(
+	progress map[uint32]crossLinkProgress,
+	beaconNum uint64,
+	numShards uint32,
+	lastCrossLink func(shardID uint32) (uint64, bool),
+	pending map[uint32]map[uint64]struct{},
+) []crossLinkGap {
+	gaps := []crossLinkGap{}
+	for shardID := uint32(0); shardID < numShards; shardID++ {
+		if shardID == shard.BeaconChainShardID {
+			continue
+		}
+		last, ok := lastCrossLink(shardID)
+		if !ok {
+			// the first crosslinks of a shard are broadcast by the shard leader
+			continue
+		}
+		shardProgress, ok := progress[shardID]
+		if !ok || shardProgress.blockNum != last {
+			progress[shardID] = crossLinkProgress{
+				blockNum: last, beaconNum: beaconNum,
+			}
+			continue
+		}
+		if beaconNum < shardProgress.beaconNum+crossLinkGapBeaconBlocks {
+			continue
+		}
+		gap := crossLinkGap{shardID: shardID}
+		for blockNum := last + 1; blockNum <= last+crossLinkBatchSize; blockNum++ {
+			if _, ok := pending[shardID][blockNum]; ok {
+				continue
+			}
+			if gap.from == 0 {
+				gap.from = blockNum
+			}
+			gap.to = blockNum
+		}
+		if gap.from != 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	return gaps
+}
+
+// recoverCrossLinkGap requests the headers of the gap from the shard peers and
+// adds the crosslinks built from them to the pending crosslinks. The header
+// following a block carries the commit signature of the block.
+func (node *Node) recoverCrossLinkGap(gap crossLinkGap) {
+	logger := utils.ModuleLogger(utils.ModuleNode).With().
+		Uint32("shardID", gap.shardID).
+		Uint64("from", gap.from).
+		Uint64("to", gap.to).
+		Logger()
+	stateSync, err := node.crossLinkStateSync(gap.shardID)
+	if err != nil {
+		logger.Warn().Err(err).Msg("[CrossLinkGap] cannot connect to shard peers")
+		return
+	}
+	headers, err := stateSync.GetCanonicalHeaders(gap.from, uint32(gap.to-gap.from+2))
+	if err != nil {
+		logger.Warn().Err(err).Msg("[CrossLinkGap] cannot get shard headers")
+		return
+	}
+	candidates, err := crossLinksOfHeaders(headers, node.VerifyCrossLink)
+	if err != nil {
+		logger.Warn().Err(err).Msg("[CrossLinkGap] invalid crosslink from shard peers")
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	total, _ := node.Blockchain().AddPendingCrossLinks(candidates)
+	logger.Info().
+		Int("recovered", len(candidates)).
+		Int("totalPending", total).
+		Msg("[CrossLinkGap] recovered missing crosslinks")
+}
+
+// crossLinksOfHeaders returns the crosslinks of the consecutive headers, each
+// signed by the commit signature carried by the header following it, up to
+// the first one failing the verification
+func crossLinksOfHeaders(
+	headers []*block.Header, verify func(types.CrossLink) error,
+) ([]types.CrossLink, error) {
+	crossLinks := []types.CrossLink{}
+	for i := 0; i+1 < len(headers); i++ {
+		cl := types.NewCrossLink(headers[i+1], headers[i])
+		if err := verify(*cl); err != nil {
+			return crossLinks, errors.Wrapf(err, "crosslink of block %d", cl.BlockNum())
+		}
+		crossLinks = append(crossLinks, *cl)
+	}
+	return crossLinks, nil
+}
+
+// crossLinkStateSync returns the state sync connected to the peers of the
+// given shard, which serves the shard headers for crosslink recovery
+func (node *Node) crossLinkStateSync(shardID uint32) (*syncing.StateSync, error) {
+	if stateSync, ok := node.crossLinkSyncs[shardID]; ok &&
+		stateSync.GetActivePeerNumber() > 0 {
+		return stateSync, nil
+	}
+	peers, err := node.SyncingPeerProvider.SyncingPeers(shardID)
+	if err != nil {
+		return nil, err
+	}
+	stateSync := syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+	stateSync.SetHandshake(node.syncHandshake())
+	if err := stateSync.CreateSyncConfig(peers, false); err != nil {
+		return nil, err
+	}
+	node.crossLinkSyncs[shardID] = stateSync
+	return stateSync, nil
+}
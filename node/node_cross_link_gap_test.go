@@ -0,0 +1,109 @@
+package node
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/pkg/errors"
+)
+
+func TestFindCrossLinkGaps(t *testing.T) {
+	lastLinks := map[uint32]uint64{1: 100, 2: 200}
+	lastCrossLink := func(shardID uint32) (uint64, bool) {
+		last, ok := lastLinks[shardID]
+		return last, ok
+	}
+	progress := map[uint32]crossLinkProgress{}
+	pending := map[uint32]map[uint64]struct{}{
+		2: {201: {}, 202: {}, 205: {}},
+	}
+
+	// the first check records the progress of the shards
+	if gaps := findCrossLinkGaps(progress, 10, 4, lastCrossLink, pending); len(gaps) != 0 {
+		t.Errorf("expected no gap on the first check, got %v", gaps)
+	}
+	if progress[1] != (crossLinkProgress{100, 10}) || progress[2] != (crossLinkProgress{200, 10}) {
+		t.Errorf("unexpected progress %v", progress)
+	}
+	if _, ok := progress[3]; ok {
+		t.Error("expected no progress of the shard without crosslink")
+	}
+
+	// the shards are not behind before crossLinkGapBeaconBlocks beacon blocks
+	beaconNum := uint64(10 + crossLinkGapBeaconBlocks - 1)
+	if gaps := findCrossLinkGaps(progress, beaconNum, 4, lastCrossLink, pending); len(gaps) != 0 {
+		t.Errorf("expected no gap yet, got %v", gaps)
+	}
+
+	// shard 1 advances, shard 2 stays behind
+	lastLinks[1] = 105
+	beaconNum++
+	gaps := findCrossLinkGaps(progress, beaconNum, 4, lastCrossLink, pending)
+	expected := []crossLinkGap{{shardID: 2, from: 203, to: 200 + crossLinkBatchSize}}
+	if !reflect.DeepEqual(gaps, expected) {
+		t.Errorf("expected %v, got %v", expected, gaps)
+	}
+	if progress[1] != (crossLinkProgress{105, beaconNum}) {
+		t.Errorf("expected the progress of shard 1 updated, got %v", progress[1])
+	}
+
+	// no gap when all the missing crosslinks are pending
+	all := map[uint32]map[uint64]struct{}{2: {}}
+	for blockNum := uint64(201); blockNum <= 200+crossLinkBatchSize; blockNum++ {
+		all[2][blockNum] = struct{}{}
+	}
+	if gaps := findCrossLinkGaps(progress, beaconNum, 4, lastCrossLink, all); len(gaps) != 0 {
+		t.Errorf("expected no gap with all crosslinks pending, got %v", gaps)
+	}
+}
+
+func TestCrossLinksOfHeaders(t *testing.T) {
+	headers := []*block.Header{}
+	parent := common.Hash{}
+	for i := int64(0); i < 4; i++ {
+		header := blockfactory.NewTestHeader().With().
+			Number(big.NewInt(10 + i)).
+			ShardID(1).
+			ParentHash(parent).
+			LastCommitSignature([96]byte{byte(i)}).
+			Header()
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+
+	verified := func(types.CrossLink) error { return nil }
+	crossLinks, err := crossLinksOfHeaders(headers, verified)
+	if err != nil || len(crossLinks) != 3 {
+		t.Fatalf("expected 3 crosslinks, got %d %v", len(crossLinks), err)
+	}
+	for i, cl := range crossLinks {
+		if cl.BlockNum() != uint64(10+i) || cl.Hash() != headers[i].Hash() || cl.ShardID() != 1 {
+			t.Errorf("crosslink %d of block %d %x", i, cl.BlockNum(), cl.Hash())
+		}
+		// the signature of a block is carried by its child
+		if cl.Signature() != headers[i+1].LastCommitSignature() {
+			t.Errorf("crosslink %d not signed by the child header", i)
+		}
+	}
+
+	// the crosslinks stop at the first failing the verification
+	errInvalid := errors.New("invalid")
+	rejectSecond := func(cl types.CrossLink) error {
+		if cl.BlockNum() == 11 {
+			return errInvalid
+		}
+		return nil
+	}
+	crossLinks, err = crossLinksOfHeaders(headers, rejectSecond)
+	if errors.Cause(err) != errInvalid || len(crossLinks) != 1 {
+		t.Errorf("expected 1 crosslink and the error, got %d %v", len(crossLinks), err)
+	}
+	if crossLinks, err := crossLinksOfHeaders(headers[:1], verified); err != nil || len(crossLinks) != 0 {
+		t.Errorf("expected no crosslink of a single header, got %d %v", len(crossLinks), err)
+	}
+}
@@ -1,10 +1,13 @@
 package node
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/types"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/utils"
@@ -19,7 +22,7 @@ func (node *Node) BroadcastCXReceipts(newBlock *types.Block) {
 	commitSigAndBitmap := newBlock.GetCurrentCommitSig()
 	//#### Read payload data from committed msg
 	if len(commitSigAndBitmap) <= 96 {
-		utils.Logger().Debug().Int("commitSigAndBitmapLen", len(commitSigAndBitmap)).Msg("[BroadcastCXReceipts] commitSigAndBitmap Not Enough Length")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Int("commitSigAndBitmapLen", len(commitSigAndBitmap)).Msg("[BroadcastCXReceipts] commitSigAndBitmap Not Enough Length")
 	}
 	commitSig := make([]byte, 96)
 	commitBitmap := make([]byte, len(commitSigAndBitmap)-96)
@@ -33,7 +36,7 @@ func (node *Node) BroadcastCXReceipts(newBlock *types.Block) {
 	shardingConfig := shard.Schedule.InstanceForEpoch(epoch)
 	shardNum := int(shardingConfig.NumShards())
 	myShardID := node.Consensus.ShardID
-	utils.Logger().Info().Int("shardNum", shardNum).Uint32("myShardID", myShardID).Uint64("blockNum", newBlock.NumberU64()).Msg("[BroadcastCXReceipts]")
+	utils.ModuleLogger(utils.ModuleNode).Info().Int("shardNum", shardNum).Uint32("myShardID", myShardID).Uint64("blockNum", newBlock.NumberU64()).Msg("[BroadcastCXReceipts]")
 
 	for i := 0; i < shardNum; i++ {
 		if i == int(myShardID) {
@@ -46,7 +49,7 @@ func (node *Node) BroadcastCXReceipts(newBlock *types.Block) {
 // BroadcastCXReceiptsWithShardID broadcasts cross shard receipts to given ToShardID
 func (node *Node) BroadcastCXReceiptsWithShardID(block *types.Block, commitSig []byte, commitBitmap []byte, toShardID uint32) {
 	myShardID := node.Consensus.ShardID
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Uint32("toShardID", toShardID).
 		Uint32("myShardID", myShardID).
 		Uint64("blockNum", block.NumberU64()).
@@ -54,7 +57,7 @@ func (node *Node) BroadcastCXReceiptsWithShardID(block *types.Block, commitSig [
 
 	cxReceipts, err := node.Blockchain().ReadCXReceipts(toShardID, block.NumberU64(), block.Hash())
 	if err != nil || len(cxReceipts) == 0 {
-		utils.Logger().Info().Uint32("ToShardID", toShardID).
+		utils.ModuleLogger(utils.ModuleNode).Info().Uint32("ToShardID", toShardID).
 			Int("numCXReceipts", len(cxReceipts)).
 			Msg("[CXMerkleProof] No receipts found for the destination shard")
 		return
@@ -62,7 +65,7 @@ func (node *Node) BroadcastCXReceiptsWithShardID(block *types.Block, commitSig [
 
 	merkleProof, err := node.Blockchain().CXMerkleProof(toShardID, block)
 	if err != nil {
-		utils.Logger().Warn().
+		utils.ModuleLogger(utils.ModuleNode).Warn().
 			Uint32("ToShardID", toShardID).
 			Msg("[BroadcastCXReceiptsWithShardID] Unable to get merkleProof")
 		return
@@ -77,7 +80,7 @@ func (node *Node) BroadcastCXReceiptsWithShardID(block *types.Block, commitSig [
 	}
 
 	groupID := nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(toShardID))
-	utils.Logger().Info().Uint32("ToShardID", toShardID).
+	utils.ModuleLogger(utils.ModuleNode).Info().Uint32("ToShardID", toShardID).
 		Str("GroupID", string(groupID)).
 		Interface("cxp", cxReceiptsProof).
 		Msg("[BroadcastCXReceiptsWithShardID] ReadCXReceipts and MerkleProof ready. Sending CX receipts...")
@@ -87,32 +90,47 @@ func (node *Node) BroadcastCXReceiptsWithShardID(block *types.Block, commitSig [
 	)
 }
 
-// BroadcastMissingCXReceipts broadcasts missing cross shard receipts per request
+// BroadcastMissingCXReceipts broadcasts the queued missing cross shard
+// receipts due to be resent, until they are known to be delivered
 func (node *Node) BroadcastMissingCXReceipts() {
-	sendNextTime := []core.CxEntry{}
-	it := node.CxPool.Pool().Iterator()
-	for entry := range it.C {
-		cxEntry := entry.(core.CxEntry)
-		toShardID := cxEntry.ToShardID
-		blk := node.Blockchain().GetBlockByHash(cxEntry.BlockHash)
+	now := time.Now()
+	for _, entry := range node.CxPool.Due(now) {
+		if node.cxDelivered(entry) {
+			node.CxPool.Remove(entry)
+			continue
+		}
+		blk := node.Blockchain().GetBlockByHash(entry.BlockHash)
 		if blk == nil {
+			// the block is no longer known, its receipts cannot be sent
+			node.CxPool.Remove(entry)
 			continue
 		}
-		blockNum := blk.NumberU64()
-		nextHeader := node.Blockchain().GetHeaderByNumber(blockNum + 1)
+		nextHeader := node.Blockchain().GetHeaderByNumber(blk.NumberU64() + 1)
 		if nextHeader == nil {
-			sendNextTime = append(sendNextTime, cxEntry)
+			// this should not happen or maybe happen for impatient user
 			continue
 		}
 		sig := nextHeader.LastCommitSignature()
 		bitmap := nextHeader.LastCommitBitmap()
-		node.BroadcastCXReceiptsWithShardID(blk, sig[:], bitmap, toShardID)
+		node.BroadcastCXReceiptsWithShardID(blk, sig[:], bitmap, entry.ToShardID)
+		node.CxPool.Retry(entry, now)
+	}
+}
+
+// cxDelivered returns whether the receipts of the entry are all recorded as
+// spent by the destination shard
+func (node *Node) cxDelivered(entry core.CxEntry) bool {
+	cxReceipts, err := node.Blockchain().ReadCXReceipts(entry.ToShardID, entry.BlockNum, entry.BlockHash)
+	if err != nil || len(cxReceipts) == 0 {
+		return false
 	}
-	node.CxPool.Clear()
-	// this should not happen or maybe happen for impatient user
-	for _, entry := range sendNextTime {
-		node.CxPool.Add(entry)
+	db := node.Blockchain().ChainDb()
+	for _, cx := range cxReceipts {
+		if rawdb.ReadCXDelivery(db, cx.TxHash) == nil {
+			return false
+		}
 	}
+	return true
 }
 
 var (
@@ -163,11 +181,11 @@ func (node *Node) verifyIncomingReceipts(block *types.Block) error {
 func (node *Node) ProcessReceiptMessage(msgPayload []byte) {
 	cxp := types.CXReceiptsProof{}
 	if err := rlp.DecodeBytes(msgPayload, &cxp); err != nil {
-		utils.Logger().Error().Err(err).
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
 			Msg("[ProcessReceiptMessage] Unable to Decode message Payload")
 		return
 	}
-	utils.Logger().Debug().Interface("cxp", cxp).
+	utils.ModuleLogger(utils.ModuleNode).Debug().Interface("cxp", cxp).
 		Msg("[ProcessReceiptMessage] Add CXReceiptsProof to pending Receipts")
 	// TODO: integrate with txpool
 	node.AddPendingReceipts(&cxp)
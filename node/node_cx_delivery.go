@@ -0,0 +1,163 @@
+package node
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// EnableCXDeliveryTracking records the delivery of the outgoing cross-shard
+// transfers, once the destination shard is seen spending their receipts, and
+// notifies the source shards of the receipts spent by the blocks this node
+// leads. It must be called before the node is started.
+func (node *Node) EnableCXDeliveryTracking() {
+	node.cxDeliveryTracking = true
+	node.RegisterPostConsensusHook("cxdelivery/push", 20, node.pushCXDeliveries)
+}
+
+// pushCXDeliveries is run by the leader on each committed block. It pushes the
+// incoming receipts of the block, with the signed header committing to them,
+// to the client groups of their source shards.
+func (node *Node) pushCXDeliveries(newBlock *types.Block) error {
+	if !node.Consensus.IsLeader() || len(newBlock.IncomingReceipts()) == 0 {
+		return nil
+	}
+	sig := newBlock.GetCurrentCommitSig()
+	if len(sig) <= shard.BLSSignatureSizeInBytes {
+		sig, _ = node.Blockchain().ReadCommitSig(newBlock.NumberU64())
+	}
+	if len(sig) <= shard.BLSSignatureSizeInBytes {
+		return errNoCommitSig
+	}
+	groups, seen := []nodeconfig.GroupID{}, map[uint32]struct{}{}
+	for _, cxp := range newBlock.IncomingReceipts() {
+		fromShardID := cxp.Header.ShardID()
+		if _, ok := seen[fromShardID]; ok {
+			continue
+		}
+		seen[fromShardID] = struct{}{}
+		groups = append(groups, nodeconfig.NewClientGroupIDByShardID(nodeconfig.ShardID(fromShardID)))
+	}
+	msg := p2p.ConstructMessage(proto_node.ConstructCXDeliveredMessage(&proto_node.CXDeliveryProof{
+		Header:             newBlock.Header(),
+		CommitSigAndBitmap: sig,
+		IncomingReceipts:   newBlock.IncomingReceipts(),
+	}))
+	if err := node.host.SendMessageToGroups(groups, msg); err != nil {
+		return errors.Wrap(err, "cannot push cross shard deliveries")
+	}
+	return nil
+}
+
+// cxDeliveredHandler handles the incoming receipts of a destination shard
+// block, recording the delivery of the transfers of this shard among them
+func (node *Node) cxDeliveredHandler(payload []byte) {
+	if !node.cxDeliveryTracking {
+		return
+	}
+	if reason := node.StorageDegraded(); reason != "" {
+		utils.ModuleLogger(utils.ModuleNode).Debug().Str("reason", reason).Msg("[CXDelivered] Storage degraded, dropping cross shard deliveries")
+		return
+	}
+	proof := &proto_node.CXDeliveryProof{}
+	if err := rlp.DecodeBytes(payload, proof); err != nil || proof.Header == nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[CXDelivered] Cannot decode cross shard deliveries")
+		return
+	}
+	if err := node.verifyCXDeliveryProof(proof); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Warn().
+			Err(err).
+			Uint32("shardID", proof.Header.ShardID()).
+			Uint64("blockNum", proof.Header.Number().Uint64()).
+			Msg("[CXDelivered] Dropping cross shard deliveries")
+		return
+	}
+	recorded := node.recordCXDeliveries(proof)
+	utils.ModuleLogger(utils.ModuleNode).Debug().
+		Uint32("shardID", proof.Header.ShardID()).
+		Uint64("blockNum", proof.Header.Number().Uint64()).
+		Int("recorded", recorded).
+		Msg("[CXDelivered] Recorded cross shard deliveries")
+}
+
+// verifyCXDeliveryProof verifies that the incoming receipts are those the
+// header commits to and that the header is signed by a quorum of its committee
+func (node *Node) verifyCXDeliveryProof(proof *proto_node.CXDeliveryProof) error {
+	header := proof.Header
+	if header.ShardID() == node.Blockchain().ShardID() {
+		return errors.Errorf("header of own shard %d", header.ShardID())
+	}
+	incomingReceiptHash := types.EmptyRootHash
+	if len(proof.IncomingReceipts) > 0 {
+		incomingReceiptHash = types.DeriveSha(proof.IncomingReceipts)
+	}
+	if incomingReceiptHash != header.IncomingReceiptHash() {
+		return errors.New("incoming receipts do not match the header")
+	}
+	sigAndBitmap := proof.CommitSigAndBitmap
+	if len(sigAndBitmap) <= shard.BLSSignatureSizeInBytes {
+		return errNoCommitSig
+	}
+	// the beacon chain knows the committees of all the shards
+	beacon := node.Beaconchain()
+	return beacon.Engine().VerifyHeaderWithSignature(
+		beacon, header,
+		sigAndBitmap[:shard.BLSSignatureSizeInBytes],
+		sigAndBitmap[shard.BLSSignatureSizeInBytes:],
+		true,
+	)
+}
+
+// recordCXDeliveries records the delivery of the transfers of this shard whose
+// receipts are spent, as long as they come from the canonical chain, and
+// returns how many were recorded
+func (node *Node) recordCXDeliveries(proof *proto_node.CXDeliveryProof) int {
+	myShardID, db := node.Blockchain().ShardID(), node.Blockchain().ChainDb()
+	recorded := 0
+	for _, cxp := range proof.IncomingReceipts {
+		if cxp.Header == nil || cxp.Header.ShardID() != myShardID {
+			continue
+		}
+		for _, cx := range cxp.Receipts {
+			if cx.ShardID != myShardID || cx.ToShardID != proof.Header.ShardID() {
+				continue
+			}
+			if rawdb.ReadCXDelivery(db, cx.TxHash) != nil {
+				continue
+			}
+			tx, blockHash, _, _ := rawdb.ReadTransaction(db, cx.TxHash)
+			if tx == nil || blockHash != cxp.Header.Hash() || tx.ToShardID() == myShardID {
+				continue
+			}
+			if err := rawdb.WriteCXDelivery(db, &types.CXDelivery{
+				TxHash:    cx.TxHash,
+				ToShardID: cx.ToShardID,
+				BlockNum:  proof.Header.Number().Uint64(),
+				BlockHash: proof.Header.Hash(),
+			}); err != nil {
+				utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
+					Str("txHash", cx.TxHash.Hex()).
+					Msg("[CXDelivered] Cannot record cross shard delivery")
+				continue
+			}
+			recorded++
+		}
+		// the receipts queued for resending are done with once all delivered
+		entry := core.CxEntry{
+			BlockHash: cxp.Header.Hash(),
+			BlockNum:  cxp.Header.Number().Uint64(),
+			ToShardID: proof.Header.ShardID(),
+		}
+		if node.CxPool != nil && node.cxDelivered(entry) {
+			node.CxPool.Remove(entry)
+		}
+	}
+	return recorded
+}
@@ -0,0 +1,116 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// cxTestBlock writes to the database a block of the given shard holding the
+// transactions, and returns its header
+func cxTestBlock(t *testing.T, node *Node, shardID uint32, number int64, txs ...*types.Transaction) *block.Header {
+	header := blockfactory.NewTestHeader().With().ShardID(shardID).Number(big.NewInt(number)).Header()
+	receipts := make([]*types.Receipt, len(txs))
+	for i := range receipts {
+		receipts[i] = &types.Receipt{}
+	}
+	blk := types.NewBlock(header, txs, receipts, nil, nil, nil)
+	db := node.Blockchain().ChainDb()
+	rawdb.WriteBlock(db, blk)
+	rawdb.WriteTxLookupEntries(db, blk)
+	return blk.Header()
+}
+
+func TestRecordCXDeliveries(t *testing.T) {
+	node := newMemTestNode(t, p2p.NewMemNetwork(), "9050")
+	to := common.Address{0x11}
+	transfer := func(nonce uint64, toShardID uint32) *types.Transaction {
+		return types.NewCrossShardTransaction(nonce, &to, 0, toShardID, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	delivered, fromForeign, misattributed, misplaced := transfer(0, 1), transfer(1, 1), transfer(2, 1), transfer(3, 1)
+	toShard2, local, unknown := transfer(4, 2), transfer(5, 0), transfer(6, 1)
+	sent := cxTestBlock(t, node, 0, 5, delivered, fromForeign, misattributed, misplaced, toShard2, local)
+	other := cxTestBlock(t, node, 0, 6)
+	foreign := blockfactory.NewTestHeader().With().ShardID(2).Number(big.NewInt(5)).Header()
+	// the block of shard 1 spending the receipts
+	delivering := blockfactory.NewTestHeader().With().ShardID(1).Number(big.NewInt(9)).Header()
+
+	receipt := func(tx *types.Transaction, fromShardID uint32) *types.CXReceipt {
+		return &types.CXReceipt{TxHash: tx.Hash(), To: &to, ShardID: fromShardID, ToShardID: tx.ToShardID(), Amount: big.NewInt(1)}
+	}
+	for _, test := range []struct {
+		name     string
+		header   *block.Header // header of the source shard block
+		receipt  *types.CXReceipt
+		recorded bool
+	}{
+		{"delivered transfer", sent, receipt(delivered, 0), true},
+		{"already recorded", sent, receipt(delivered, 0), false},
+		{"receipts of another shard", foreign, receipt(fromForeign, 0), false},
+		{"receipt of another shard", sent, receipt(misattributed, 2), false},
+		{"receipt to another shard", sent, receipt(toShard2, 0), false},
+		{"local transfer", sent, receipt(local, 0), false},
+		{"unknown transfer", sent, receipt(unknown, 0), false},
+		{"transfer of another block", other, receipt(misplaced, 0), false},
+	} {
+		recorded := node.recordCXDeliveries(&proto_node.CXDeliveryProof{
+			Header: delivering,
+			IncomingReceipts: types.CXReceiptsProofs{
+				{Header: test.header, Receipts: types.CXReceipts{test.receipt}},
+			},
+		})
+		if (recorded == 1) != test.recorded {
+			t.Errorf("%s: expected recorded %t, got %d recorded", test.name, test.recorded, recorded)
+		}
+		delivery := rawdb.ReadCXDelivery(node.Blockchain().ChainDb(), test.receipt.TxHash)
+		if test.recorded && (delivery == nil || delivery.BlockNum != 9 || delivery.BlockHash != delivering.Hash() ||
+			delivery.ToShardID != 1) {
+			t.Errorf("%s: unexpected delivery %+v", test.name, delivery)
+		}
+	}
+}
+
+func TestVerifyCXDeliveryProof(t *testing.T) {
+	node := newMemTestNode(t, p2p.NewMemNetwork(), "9051")
+	receipts := types.CXReceiptsProofs{{
+		Header:   blockfactory.NewTestHeader().With().ShardID(0).Number(big.NewInt(5)).Header(),
+		Receipts: types.CXReceipts{{TxHash: common.Hash{0x11}, ToShardID: 1, Amount: big.NewInt(1)}},
+	}}
+	header := func(shardID uint32, incomingReceiptHash common.Hash) *block.Header {
+		return blockfactory.NewTestHeader().With().
+			ShardID(shardID).Number(big.NewInt(9)).IncomingReceiptHash(incomingReceiptHash).Header()
+	}
+
+	for _, test := range []struct {
+		name     string
+		proof    *proto_node.CXDeliveryProof
+		expected string
+	}{
+		{
+			"header of own shard",
+			&proto_node.CXDeliveryProof{Header: header(0, types.EmptyRootHash)},
+			"header of own shard 0",
+		},
+		{
+			"receipts not committed to",
+			&proto_node.CXDeliveryProof{Header: header(1, types.EmptyRootHash), IncomingReceipts: receipts},
+			"incoming receipts do not match the header",
+		},
+		{
+			"no commit signature",
+			&proto_node.CXDeliveryProof{Header: header(1, types.DeriveSha(receipts)), IncomingReceipts: receipts},
+			errNoCommitSig.Error(),
+		},
+	} {
+		if err := node.verifyCXDeliveryProof(test.proof); err == nil || err.Error() != test.expected {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+}
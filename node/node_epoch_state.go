@@ -0,0 +1,126 @@
+package node
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/block"
+	"github.com/harmony-one/harmony/core/types"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// pushEpochState is run by the beacon leader on each committed block. On the
+// last block of an epoch, it pushes the shard state of the next epoch along
+// with the signed header holding it to the client groups of the shards, so
+// the shard nodes can compute their committees without syncing beacon blocks.
+func (node *Node) pushEpochState(newBlock *types.Block) error {
+	if node.NodeConfig.ShardID != shard.BeaconChainShardID ||
+		!node.Consensus.IsLeader() || len(newBlock.Header().ShardState()) == 0 {
+		return nil
+	}
+	shardState, err := shard.DecodeWrapper(newBlock.Header().ShardState())
+	if err != nil {
+		return err
+	}
+	sig := newBlock.GetCurrentCommitSig()
+	if len(sig) <= shard.BLSSignatureSizeInBytes {
+		sig, _ = node.Blockchain().ReadCommitSig(newBlock.NumberU64())
+	}
+	if len(sig) <= shard.BLSSignatureSizeInBytes {
+		return errNoCommitSig
+	}
+	groups := []nodeconfig.GroupID{}
+	for _, committee := range shardState.Shards {
+		if committee.ShardID != shard.BeaconChainShardID {
+			groups = append(groups, nodeconfig.NewClientGroupIDByShardID(nodeconfig.ShardID(committee.ShardID)))
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	msg := p2p.ConstructMessage(proto_node.ConstructEpochStateMessage(
+		&proto_node.EpochStateProof{Header: newBlock.Header(), CommitSigAndBitmap: sig},
+	))
+	if err := node.host.SendMessageToGroups(groups, msg); err != nil {
+		return errors.Wrap(err, "cannot push epoch state")
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Uint64("blockNum", newBlock.NumberU64()).
+		Uint64("epoch", shardState.Epoch.Uint64()).
+		Int("shards", len(groups)).
+		Msg("[EpochState] Pushed shard state of next epoch")
+	return nil
+}
+
+// epochStateHandler handles the shard state of an epoch pushed by the beacon
+// leader. The state is stored with the beacon chain once the header holding
+// it is verified against the beacon committee of its epoch, itself known from
+// the beacon chain or the previous push.
+func (node *Node) epochStateHandler(payload []byte) {
+	if node.NodeConfig.ShardID == shard.BeaconChainShardID {
+		return
+	}
+	proof := &proto_node.EpochStateProof{}
+	if err := rlp.DecodeBytes(payload, proof); err != nil || proof.Header == nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[EpochState] Cannot decode epoch state")
+		return
+	}
+	if err := node.storeEpochState(proof); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Warn().
+			Err(err).
+			Uint64("blockNum", proof.Header.Number().Uint64()).
+			Str("hash", proof.Header.Hash().Hex()).
+			Msg("[EpochState] Dropping epoch state")
+	}
+}
+
+// storeEpochState verifies and stores the pushed shard state
+func (node *Node) storeEpochState(proof *proto_node.EpochStateProof) error {
+	header := proof.Header
+	if header.ShardID() != shard.BeaconChainShardID {
+		return errors.Errorf("header of shard %d", header.ShardID())
+	}
+	if len(header.ShardState()) == 0 {
+		return errors.New("header holds no shard state")
+	}
+	shardState, err := shard.DecodeWrapper(header.ShardState())
+	if err != nil {
+		return err
+	}
+	if shardState.Epoch == nil || shardState.Epoch.Uint64() != header.Epoch().Uint64()+1 {
+		return errors.Errorf("shard state of epoch %v in header of epoch %v", shardState.Epoch, header.Epoch())
+	}
+	beacon := node.Beaconchain()
+	if _, err := beacon.ReadShardState(shardState.Epoch); err == nil {
+		return nil // known already
+	}
+	if err := node.verifyBeaconHeaderSig(header, proof.CommitSigAndBitmap); err != nil {
+		return err
+	}
+	node.cacheBeaconCommitSig(header.Number().Uint64(), proof.CommitSigAndBitmap)
+	if _, err := beacon.WriteShardStateBytes(
+		beacon.ChainDb(), shardState.Epoch, header.ShardState(),
+	); err != nil {
+		return errors.Wrap(err, "cannot store shard state")
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Uint64("blockNum", header.Number().Uint64()).
+		Uint64("epoch", shardState.Epoch.Uint64()).
+		Msg("[EpochState] Stored beacon shard state of next epoch")
+	return nil
+}
+
+// verifyBeaconHeaderSig verifies that the header is signed by a quorum of the
+// beacon committee of its epoch
+func (node *Node) verifyBeaconHeaderSig(header *block.Header, commitSigAndBitmap []byte) error {
+	if len(commitSigAndBitmap) <= shard.BLSSignatureSizeInBytes {
+		return errNoCommitSig
+	}
+	sig := commitSigAndBitmap[:shard.BLSSignatureSizeInBytes]
+	bitmap := commitSigAndBitmap[shard.BLSSignatureSizeInBytes:]
+	beacon := node.Beaconchain()
+	return beacon.Engine().VerifyHeaderWithSignature(beacon, header, sig, bitmap, true)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/harmony-one/harmony/api/service/explorer"
 	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/consensus/signature"
+	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/utils"
 )
@@ -20,20 +21,20 @@ var once sync.Once
 // ExplorerMessageHandler passes received message in node_handler to explorer service
 func (node *Node) ExplorerMessageHandler(payload []byte) {
 	if len(payload) == 0 {
-		utils.Logger().Error().Msg("Payload is empty")
+		utils.ModuleLogger(utils.ModuleNode).Error().Msg("Payload is empty")
 		return
 	}
 	msg := &msg_pb.Message{}
 	err := protobuf.Unmarshal(payload, msg)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("Failed to unmarshal message payload.")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to unmarshal message payload.")
 		return
 	}
 
 	if msg.Type == msg_pb.MessageType_COMMITTED {
 		recvMsg, err := consensus.ParseFBFTMessage(msg)
 		if err != nil {
-			utils.Logger().Error().Err(err).
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
 				Msg("[Explorer] onCommitted unable to parse msg")
 			return
 		}
@@ -42,20 +43,20 @@ func (node *Node) ExplorerMessageHandler(payload []byte) {
 			recvMsg.Payload, 0,
 		)
 		if err != nil {
-			utils.Logger().Error().Err(err).
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
 				Msg("[Explorer] readSignatureBitmapPayload failed")
 			return
 		}
 
 		if !node.Consensus.Decider.IsQuorumAchievedByMask(mask) {
-			utils.Logger().Error().Msg("[Explorer] not have enough signature power")
+			utils.ModuleLogger(utils.ModuleNode).Error().Msg("[Explorer] not have enough signature power")
 			return
 		}
 
 		block := node.Consensus.FBFTLog.GetBlockByHash(recvMsg.BlockHash)
 
 		if block == nil {
-			utils.Logger().Info().
+			utils.ModuleLogger(utils.ModuleNode).Info().
 				Uint64("msgBlock", recvMsg.BlockNum).
 				Msg("[Explorer] Haven't received the block before the committed msg")
 			node.Consensus.FBFTLog.AddMessage(recvMsg)
@@ -65,7 +66,7 @@ func (node *Node) ExplorerMessageHandler(payload []byte) {
 		commitPayload := signature.ConstructCommitPayload(node.Blockchain(),
 			block.Epoch(), block.Hash(), block.Number().Uint64(), block.Header().ViewID().Uint64())
 		if !aggSig.VerifyHash(mask.AggregatePublic, commitPayload) {
-			utils.Logger().
+			utils.ModuleLogger(utils.ModuleNode).
 				Error().Err(err).
 				Uint64("msgBlock", recvMsg.BlockNum).
 				Msg("[Explorer] Failed to verify the multi signature for commit phase")
@@ -78,12 +79,12 @@ func (node *Node) ExplorerMessageHandler(payload []byte) {
 
 		recvMsg, err := consensus.ParseFBFTMessage(msg)
 		if err != nil {
-			utils.Logger().Error().Err(err).Msg("[Explorer] Unable to parse Prepared msg")
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Unable to parse Prepared msg")
 			return
 		}
-		block, blockObj := recvMsg.Block, &types.Block{}
-		if err := rlp.DecodeBytes(block, blockObj); err != nil {
-			utils.Logger().Error().Err(err).Msg("explorer could not rlp decode block")
+		blockObj, err := node.Consensus.FetchPreparedBlock(recvMsg)
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("explorer could not get the prepared block")
 			return
 		}
 		// Add the block into FBFT log.
@@ -104,8 +105,10 @@ func (node *Node) ExplorerMessageHandler(payload []byte) {
 
 // AddNewBlockForExplorer add new block for explorer.
 func (node *Node) AddNewBlockForExplorer(block *types.Block) {
-	utils.Logger().Debug().Uint64("blockHeight", block.NumberU64()).Msg("[Explorer] Adding new block for explorer node")
-	if _, err := node.Blockchain().InsertChain([]*types.Block{block}, true); err == nil {
+	utils.ModuleLogger(utils.ModuleNode).Debug().Uint64("blockHeight", block.NumberU64()).Msg("[Explorer] Adding new block for explorer node")
+	if err := node.Blockchain().InsertPipeline().Insert(
+		block, core.InsertBroadcast, true,
+	); err == nil {
 		if len(block.Header().ShardState()) > 0 {
 			node.Consensus.UpdateConsensusInformation()
 		}
@@ -115,7 +118,7 @@ func (node *Node) AddNewBlockForExplorer(block *types.Block) {
 		// TODO: some blocks can be dumped before state syncing finished.
 		// And they would be dumped again here. Please fix it.
 		once.Do(func() {
-			utils.Logger().Info().Int64("starting height", int64(block.NumberU64())-1).
+			utils.ModuleLogger(utils.ModuleNode).Info().Int64("starting height", int64(block.NumberU64())-1).
 				Msg("[Explorer] Populating explorer data from state synced blocks")
 			go func() {
 				for blockHeight := int64(block.NumberU64()) - 1; blockHeight >= 0; blockHeight-- {
@@ -125,7 +128,7 @@ func (node *Node) AddNewBlockForExplorer(block *types.Block) {
 			}()
 		})
 	} else {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Error when adding new block for explorer node")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Error when adding new block for explorer node")
 	}
 }
 
@@ -134,9 +137,13 @@ func (node *Node) commitBlockForExplorer(block *types.Block) {
 	if block.ShardID() != node.NodeConfig.ShardID {
 		return
 	}
-	// Dump new block into level db.
-	utils.Logger().Info().Uint64("blockNum", block.NumberU64()).Msg("[Explorer] Committing block into explorer DB")
-	explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, true).Dump(block, block.NumberU64())
+	// Dump new block into level db, unless the storage is degraded.
+	if node.storage.skipExplorerDump(block.NumberU64()) {
+		utils.ModuleLogger(utils.ModuleNode).Warn().Uint64("blockNum", block.NumberU64()).Msg("[Explorer] Storage degraded, block dump deferred")
+	} else {
+		utils.ModuleLogger(utils.ModuleNode).Info().Uint64("blockNum", block.NumberU64()).Msg("[Explorer] Committing block into explorer DB")
+		explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, true).Dump(block, block.NumberU64())
+	}
 
 	curNum := block.NumberU64()
 	if curNum-100 > 0 {
@@ -151,11 +158,11 @@ func (node *Node) GetTransactionsHistory(address, txType, order string) ([]commo
 	key := explorer.GetAddressKey(address)
 	bytes, err := explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, false).GetDB().Get([]byte(key), nil)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
 		return make([]common.Hash, 0), nil
 	}
 	if err = rlp.DecodeBytes(bytes, &addressData); err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
 		return nil, err
 	}
 	if order == "DESC" {
@@ -183,11 +190,11 @@ func (node *Node) GetStakingTransactionsHistory(address, txType, order string) (
 	key := explorer.GetAddressKey(address)
 	bytes, err := explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, false).GetDB().Get([]byte(key), nil)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
 		return make([]common.Hash, 0), nil
 	}
 	if err = rlp.DecodeBytes(bytes, &addressData); err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
 		return nil, err
 	}
 	if order == "DESC" {
@@ -215,11 +222,11 @@ func (node *Node) GetTransactionsCount(address, txType string) (uint64, error) {
 	key := explorer.GetAddressKey(address)
 	bytes, err := explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, false).GetDB().Get([]byte(key), nil)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
 		return 0, nil
 	}
 	if err = rlp.DecodeBytes(bytes, &addressData); err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
 		return 0, err
 	}
 
@@ -238,11 +245,11 @@ func (node *Node) GetStakingTransactionsCount(address, txType string) (uint64, e
 	key := explorer.GetAddressKey(address)
 	bytes, err := explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, false).GetDB().Get([]byte(key), nil)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot get storage db instance")
 		return 0, nil
 	}
 	if err = rlp.DecodeBytes(bytes, &addressData); err != nil {
-		utils.Logger().Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[Explorer] Cannot convert address data from DB")
 		return 0, err
 	}
 
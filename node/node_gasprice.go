@@ -0,0 +1,28 @@
+package node
+
+import (
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/hmy/gasprice"
+)
+
+// setupGasPriceOracle feeds the gas price oracle with the recent blocks of the
+// chain, then with each block committed by consensus
+func (node *Node) setupGasPriceOracle(cfg gasprice.Config) {
+	oracle := gasprice.NewOracle(cfg)
+	chain := node.Blockchain()
+	head := chain.CurrentBlock().NumberU64()
+	from := uint64(0)
+	if head >= uint64(cfg.Blocks) {
+		from = head - uint64(cfg.Blocks) + 1
+	}
+	for num := from; num <= head; num++ {
+		if block := chain.GetBlockByNumber(num); block != nil {
+			oracle.Update(block)
+		}
+	}
+	node.gasPriceOracle = oracle
+	node.RegisterPostConsensusHook("gasprice/update", 50, func(block *types.Block) error {
+		oracle.Update(block)
+		return nil
+	})
+}
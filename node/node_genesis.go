@@ -64,7 +64,7 @@ func (gi *genesisInitializer) InitChainDB(db ethdb.Database, shardID uint32) err
 
 // SetupGenesisBlock sets up a genesis blockchain.
 func (node *Node) SetupGenesisBlock(db ethdb.Database, shardID uint32, myShardState *shard.State) {
-	utils.Logger().Info().Interface("shardID", shardID).Msg("setting up a brand new chain database")
+	utils.ModuleLogger(utils.ModuleNode).Info().Interface("shardID", shardID).Msg("setting up a brand new chain database")
 	if shardID == node.NodeConfig.ShardID {
 		node.isFirstTime = true
 	}
@@ -123,6 +123,20 @@ func (node *Node) SetupGenesisBlock(db ethdb.Database, shardID uint32, myShardSt
 		ExtraData:      []byte("Harmony for One and All. Open Consensus for 10B."),
 	}
 
+	// The private network of the genesis spec, if any, has its own genesis
+	if spec := nodeconfig.GetGenesisSpec(); spec != nil {
+		chainConfig = *spec.Config
+		genesisAlloc = make(core.GenesisAlloc)
+		for address, balance := range spec.Alloc() {
+			genesisAlloc[address] = core.GenesisAccount{Balance: balance}
+		}
+		gspec.Factory = blockfactory.NewFactory(&chainConfig)
+		gspec.Alloc = genesisAlloc
+		gspec.GasLimit = spec.GasLimit
+		gspec.Timestamp = spec.Timestamp
+		gspec.ExtraData = []byte(spec.ExtraData)
+	}
+
 	// Store genesis block into db.
 	gspec.MustCommit(db)
 }
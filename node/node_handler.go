@@ -39,11 +39,11 @@ func (node *Node) processSkippedMsgTypeByteValue(
 	case proto_node.SlashCandidate:
 		node.processSlashCandidateMessage(content)
 	case proto_node.Receipt:
-		utils.Logger().Debug().Msg("NET: received message: Node/Receipt")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/Receipt")
 		node.ProcessReceiptMessage(content)
 	case proto_node.CrossLink:
 		// only beacon chain will accept the header from other shards
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleNode).Debug().
 			Uint32("shardID", node.NodeConfig.ShardID).
 			Msg("NET: received message: Node/CrossLink")
 		if node.NodeConfig.ShardID != shard.BeaconChainShardID {
@@ -51,7 +51,7 @@ func (node *Node) processSkippedMsgTypeByteValue(
 		}
 		node.ProcessCrossLinkMessage(content)
 	default:
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Int("message-iota-value", int(cat)).
 			Msg("Invariant usage of processSkippedMsgTypeByteValue violated")
 	}
@@ -61,21 +61,21 @@ func (node *Node) processSkippedMsgTypeByteValue(
 func (node *Node) HandleMessage(content []byte, sender libp2p_peer.ID) {
 	// log in-coming metrics
 	node.host.LogRecvMessage(content)
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Int64("TotalIn", node.host.GetBandwidthTotals().TotalIn).
 		Float64("RateIn", node.host.GetBandwidthTotals().RateIn).
 		Msg("[metrics][p2p] traffic in in bytes")
 
 	msgCategory, err := proto.GetMessageCategory(content)
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).
 			Msg("HandleMessage get message category failed")
 		return
 	}
 	msgType, err := proto.GetMessageType(content)
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).
 			Msg("HandleMessage get message type failed")
 		return
@@ -83,7 +83,7 @@ func (node *Node) HandleMessage(content []byte, sender libp2p_peer.ID) {
 
 	msgPayload, err := proto.GetMessagePayload(content)
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).
 			Msg("HandleMessage get message payload failed")
 		return
@@ -92,55 +92,43 @@ func (node *Node) HandleMessage(content []byte, sender libp2p_peer.ID) {
 	switch msgCategory {
 	case proto.Consensus:
 		msgPayload, _ := proto.GetConsensusMessagePayload(content)
-		if node.NodeConfig.Role() == nodeconfig.ExplorerNode {
+		switch node.NodeConfig.Role() {
+		case nodeconfig.ExplorerNode:
 			node.ExplorerMessageHandler(msgPayload)
-		} else {
+		case nodeconfig.RPCNode:
+			// no consensus to pass the message to
+		default:
 			node.ConsensusMessageHandler(msgPayload)
 		}
 	case proto.Node:
 		actionType := proto_node.MessageType(msgType)
 		switch actionType {
 		case proto_node.Transaction:
-			utils.Logger().Debug().Msg("NET: received message: Node/Transaction")
+			utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/Transaction")
 			node.transactionMessageHandler(msgPayload)
 		case proto_node.Staking:
-			utils.Logger().Debug().Msg("NET: received message: Node/Staking")
+			utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/Staking")
 			node.stakingMessageHandler(msgPayload)
 		case proto_node.Block:
-			utils.Logger().Debug().Msg("NET: received message: Node/Block")
+			utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/Block")
 			if len(msgPayload) < 1 {
-				utils.Logger().Debug().Msgf("Invalid block message size")
+				utils.ModuleLogger(utils.ModuleNode).Debug().Msgf("Invalid block message size")
 				return
 			}
 
 			switch blockMsgType := proto_node.BlockMessageType(msgPayload[0]); blockMsgType {
 			case proto_node.Sync:
-				utils.Logger().Debug().Msg("NET: received message: Node/Sync")
-				blocks := []*types.Block{}
-				if err := rlp.DecodeBytes(msgPayload[1:], &blocks); err != nil {
-					utils.Logger().Error().
-						Err(err).
-						Msg("block sync")
-				} else {
-					// for non-beaconchain node, subscribe to beacon block broadcast
-					if node.Blockchain().ShardID() != shard.BeaconChainShardID &&
-						node.NodeConfig.Role() != nodeconfig.ExplorerNode {
-						for _, block := range blocks {
-							if block.ShardID() == 0 {
-								utils.Logger().Info().
-									Uint64("block", blocks[0].NumberU64()).
-									Msgf("Beacon block being handled by block channel: %d", block.NumberU64())
-								go func(blk *types.Block) {
-									node.BeaconBlockChannel <- blk
-								}(block)
-							}
-						}
-					}
-					if node.Client != nil && node.Client.UpdateBlocks != nil && blocks != nil {
-						utils.Logger().Info().Msg("Block being handled by client")
-						node.Client.UpdateBlocks(blocks)
-					}
-				}
+				utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/Sync")
+				node.blocksSyncHandler(msgPayload[1:])
+			case proto_node.SignedSync:
+				utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/SignedSync")
+				node.signedBlocksHandler(msgPayload[1:])
+			case proto_node.EpochState:
+				utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/EpochState")
+				node.epochStateHandler(msgPayload[1:])
+			case proto_node.CXDelivered:
+				utils.ModuleLogger(utils.ModuleNode).Debug().Msg("NET: received message: Node/CXDelivered")
+				node.cxDeliveredHandler(msgPayload[1:])
 			case
 				proto_node.SlashCandidate,
 				proto_node.Receipt,
@@ -150,20 +138,22 @@ func (node *Node) HandleMessage(content []byte, sender libp2p_peer.ID) {
 			}
 		case proto_node.PING:
 			node.pingMessageHandler(msgPayload, sender)
+		case proto_node.ChainHead:
+			node.headBeaconHandler(msgPayload)
 		}
 	default:
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Str("Unknown MsgCateogry", string(msgCategory))
 	}
 }
 
 func (node *Node) transactionMessageHandler(msgPayload []byte) {
 	if len(msgPayload) >= types.MaxEncodedPoolTransactionSize {
-		utils.Logger().Warn().Err(core.ErrOversizedData).Msgf("encoded tx size: %d", len(msgPayload))
+		utils.ModuleLogger(utils.ModuleNode).Warn().Err(core.ErrOversizedData).Msgf("encoded tx size: %d", len(msgPayload))
 		return
 	}
 	if len(msgPayload) < 1 {
-		utils.Logger().Debug().Msgf("Invalid transaction message size")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Msgf("Invalid transaction message size")
 		return
 	}
 	txMessageType := proto_node.TransactionMessageType(msgPayload[0])
@@ -173,7 +163,7 @@ func (node *Node) transactionMessageHandler(msgPayload []byte) {
 		txs := types.Transactions{}
 		err := rlp.Decode(bytes.NewReader(msgPayload[1:]), &txs) // skip the Send messge type
 		if err != nil {
-			utils.Logger().Error().
+			utils.ModuleLogger(utils.ModuleNode).Error().
 				Err(err).
 				Msg("Failed to deserialize transaction list")
 			return
@@ -184,11 +174,11 @@ func (node *Node) transactionMessageHandler(msgPayload []byte) {
 
 func (node *Node) stakingMessageHandler(msgPayload []byte) {
 	if len(msgPayload) >= types.MaxEncodedPoolTransactionSize {
-		utils.Logger().Warn().Err(core.ErrOversizedData).Msgf("encoded tx size: %d", len(msgPayload))
+		utils.ModuleLogger(utils.ModuleNode).Warn().Err(core.ErrOversizedData).Msgf("encoded tx size: %d", len(msgPayload))
 		return
 	}
 	if len(msgPayload) < 1 {
-		utils.Logger().Debug().Msgf("Invalid staking transaction message size")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Msgf("Invalid staking transaction message size")
 		return
 	}
 	txMessageType := proto_node.TransactionMessageType(msgPayload[0])
@@ -198,7 +188,7 @@ func (node *Node) stakingMessageHandler(msgPayload []byte) {
 		txs := staking.StakingTransactions{}
 		err := rlp.Decode(bytes.NewReader(msgPayload[1:]), &txs) // skip the Send messge type
 		if err != nil {
-			utils.Logger().Error().
+			utils.ModuleLogger(utils.ModuleNode).Error().
 				Err(err).
 				Msg("Failed to deserialize staking transaction list")
 			return
@@ -212,30 +202,43 @@ func (node *Node) stakingMessageHandler(msgPayload []byte) {
 // TODO (lc): broadcast the new blocks to new nodes doing state sync
 func (node *Node) BroadcastNewBlock(newBlock *types.Block) {
 	groups := []nodeconfig.GroupID{node.NodeConfig.GetClientGroupID()}
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Msgf(
 			"broadcasting new block %d, group %s", newBlock.NumberU64(), groups[0],
 		)
-	msg := p2p.ConstructMessage(
-		proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock}),
-	)
+	payload := proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock})
+	// let the shard nodes verify the beacon block before inserting it, once
+	// all of them decode the signed blocks
+	if newBlock.ShardID() == shard.BeaconChainShardID &&
+		node.Blockchain().Config().IsSignedBeaconSync(newBlock.Epoch()) {
+		sig := newBlock.GetCurrentCommitSig()
+		if len(sig) <= shard.BLSSignatureSizeInBytes {
+			sig, _ = node.Blockchain().ReadCommitSig(newBlock.NumberU64())
+		}
+		if len(sig) > shard.BLSSignatureSizeInBytes {
+			payload = proto_node.ConstructSignedBlocksSyncMessage(
+				[]*proto_node.SignedBlock{{Block: newBlock, CommitSigAndBitmap: sig}},
+			)
+		}
+	}
+	msg := p2p.ConstructMessage(payload)
 	if err := node.host.SendMessageToGroups(groups, msg); err != nil {
-		utils.Logger().Warn().Err(err).Msg("cannot broadcast new block")
+		utils.ModuleLogger(utils.ModuleNode).Warn().Err(err).Msg("cannot broadcast new block")
 	}
 }
 
 // BroadcastSlash ..
-func (node *Node) BroadcastSlash(witness *slash.Record) {
+func (node *Node) BroadcastSlash(witnesses slash.Records) {
 	if err := node.host.SendMessageToGroups(
 		[]nodeconfig.GroupID{nodeconfig.NewGroupIDByShardID(shard.BeaconChainShardID)},
 		p2p.ConstructMessage(
-			proto_node.ConstructSlashMessage(slash.Records{*witness})),
+			proto_node.ConstructSlashMessage(witnesses)),
 	); err != nil {
-		utils.Logger().Err(err).
-			RawJSON("record", []byte(witness.String())).
+		utils.ModuleLogger(utils.ModuleNode).Err(err).
+			RawJSON("records", []byte(witnesses.String())).
 			Msg("could not send slash record to beaconchain")
 	}
-	utils.Logger().Info().Msg("broadcast the double sign record")
+	utils.ModuleLogger(utils.ModuleNode).Info().Msg("broadcast the double sign record")
 }
 
 // BroadcastCrossLink is called by consensus leader to
@@ -248,7 +251,7 @@ func (node *Node) BroadcastCrossLink(newBlock *types.Block) {
 		return
 	}
 
-	utils.Logger().Info().Msgf(
+	utils.ModuleLogger(utils.ModuleNode).Info().Msgf(
 		"Construct and Broadcasting new crosslink to beacon chain groupID %s",
 		nodeconfig.NewGroupIDByShardID(shard.BeaconChainShardID),
 	)
@@ -259,7 +262,7 @@ func (node *Node) BroadcastCrossLink(newBlock *types.Block) {
 	// TODO chao: record the missing crosslink in local database instead of using latest crosslink
 	// if cannot find latest crosslink, broadcast latest 3 block headers
 	if err != nil {
-		utils.Logger().Debug().Err(err).Msg("[BroadcastCrossLink] ReadShardLastCrossLink Failed")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Msg("[BroadcastCrossLink] ReadShardLastCrossLink Failed")
 		header := node.Blockchain().GetHeaderByNumber(newBlock.NumberU64() - 2)
 		if header != nil && node.Blockchain().Config().IsCrossLink(header.Epoch()) {
 			headers = append(headers, header)
@@ -282,9 +285,9 @@ func (node *Node) BroadcastCrossLink(newBlock *types.Block) {
 		}
 	}
 
-	utils.Logger().Info().Msgf("[BroadcastCrossLink] Broadcasting Block Headers, latestBlockNum %d, currentBlockNum %d, Number of Headers %d", latestBlockNum, newBlock.NumberU64(), len(headers))
+	utils.ModuleLogger(utils.ModuleNode).Info().Msgf("[BroadcastCrossLink] Broadcasting Block Headers, latestBlockNum %d, currentBlockNum %d, Number of Headers %d", latestBlockNum, newBlock.NumberU64(), len(headers))
 	for _, header := range headers {
-		utils.Logger().Debug().Msgf(
+		utils.ModuleLogger(utils.ModuleNode).Debug().Msgf(
 			"[BroadcastCrossLink] Broadcasting %d",
 			header.Number().Uint64(),
 		)
@@ -299,8 +302,20 @@ func (node *Node) BroadcastCrossLink(newBlock *types.Block) {
 // VerifyNewBlock is called by consensus participants to verify the block (account model) they are
 // running consensus on
 func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
+	if err := node.VerifyBlockHeader(newBlock); err != nil {
+		return err
+	}
+	if err := node.VerifyBlockBody(newBlock); err != nil {
+		return err
+	}
+	return node.VerifyBlockState(newBlock)
+}
+
+// VerifyBlockHeader verifies the header of the block, with the commit
+// signature of the parent it holds, and its shard state
+func (node *Node) VerifyBlockHeader(newBlock *types.Block) error {
 	if err := node.Blockchain().Validator().ValidateHeader(newBlock, true); err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Str("blockHash", newBlock.Hash().Hex()).
 			Err(err).
 			Msg("[VerifyNewBlock] Cannot validate header for the new block")
@@ -308,7 +323,7 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 	}
 
 	if newBlock.ShardID() != node.Blockchain().ShardID() {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Uint32("my shard ID", node.Blockchain().ShardID()).
 			Uint32("new block's shard ID", newBlock.ShardID()).
 			Msg("[VerifyNewBlock] Wrong shard ID of the new block")
@@ -318,7 +333,7 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 	if err := node.Blockchain().Engine().VerifyShardState(
 		node.Blockchain(), node.Beaconchain(), newBlock.Header(),
 	); err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Str("blockHash", newBlock.Hash().Hex()).
 			Err(err).
 			Msg("[VerifyNewBlock] Cannot verify shard state for the new block")
@@ -326,30 +341,20 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 			"[VerifyNewBlock] Cannot verify shard state for the new block",
 		)
 	}
+	// not verified again on insertion
+	node.Blockchain().MarkBlockVerified(newBlock, core.VerifiedHeader)
+	return nil
+}
 
-	if err := node.Blockchain().ValidateNewBlock(newBlock); err != nil {
-		if hooks := node.NodeConfig.WebHooks.Hooks; hooks != nil {
-			if p := hooks.ProtocolIssues; p != nil {
-				url := p.OnCannotCommit
-				go func() {
-					webhooks.DoPost(url, map[string]interface{}{
-						"bad-header": newBlock.Header(),
-						"reason":     err.Error(),
-					})
-				}()
-			}
-		}
-		utils.Logger().Error().
+// VerifyBlockBody verifies the transactions, cross links and incoming
+// receipts of the block
+func (node *Node) VerifyBlockBody(newBlock *types.Block) error {
+	if err := node.Blockchain().Validator().ValidateBody(newBlock); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Str("blockHash", newBlock.Hash().Hex()).
-			Int("numTx", len(newBlock.Transactions())).
-			Int("numStakingTx", len(newBlock.StakingTransactions())).
 			Err(err).
-			Msg("[VerifyNewBlock] Cannot Verify New Block!!!")
-		return errors.Errorf(
-			"[VerifyNewBlock] Cannot Verify New Block!!! block-hash %s txn-count %d",
-			newBlock.Hash().Hex(),
-			len(newBlock.Transactions()),
-		)
+			Msg("[VerifyNewBlock] Cannot validate body of the new block")
+		return err
 	}
 
 	// Verify cross links
@@ -357,14 +362,14 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 	if node.NodeConfig.ShardID == shard.BeaconChainShardID {
 		err := node.VerifyBlockCrossLinks(newBlock)
 		if err != nil {
-			utils.Logger().Debug().Err(err).Msg("ops2 VerifyBlockCrossLinks Failed")
+			utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Msg("ops2 VerifyBlockCrossLinks Failed")
 			return err
 		}
 	}
 
 	// TODO: move into ValidateNewBlock
 	if err := node.verifyIncomingReceipts(newBlock); err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Str("blockHash", newBlock.Hash().Hex()).
 			Int("numIncomingReceipts", len(newBlock.IncomingReceipts())).
 			Err(err).
@@ -373,6 +378,37 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 			err, "[VerifyNewBlock] Cannot ValidateNewBlock",
 		)
 	}
+	node.Blockchain().MarkBlockVerified(newBlock, core.VerifiedBody)
+	return nil
+}
+
+// VerifyBlockState processes the block on the state of the current head and
+// verifies the result against the roots of its header
+func (node *Node) VerifyBlockState(newBlock *types.Block) error {
+	if err := node.Blockchain().ValidateNewBlock(newBlock); err != nil {
+		if hooks := node.NodeConfig.WebHooks.Hooks; hooks != nil {
+			if p := hooks.ProtocolIssues; p != nil {
+				url := p.OnCannotCommit
+				go func() {
+					webhooks.DoPost(url, map[string]interface{}{
+						"bad-header": newBlock.Header(),
+						"reason":     err.Error(),
+					})
+				}()
+			}
+		}
+		utils.ModuleLogger(utils.ModuleNode).Error().
+			Str("blockHash", newBlock.Hash().Hex()).
+			Int("numTx", len(newBlock.Transactions())).
+			Int("numStakingTx", len(newBlock.StakingTransactions())).
+			Err(err).
+			Msg("[VerifyNewBlock] Cannot Verify New Block!!!")
+		return errors.Errorf(
+			"[VerifyNewBlock] Cannot Verify New Block!!! block-hash %s txn-count %d",
+			newBlock.Hash().Hex(),
+			len(newBlock.Transactions()),
+		)
+	}
 	return nil
 }
 
@@ -402,8 +438,10 @@ func (node *Node) numSignaturesIncludedInBlock(block *types.Block) uint32 {
 func (node *Node) PostConsensusProcessing(
 	newBlock *types.Block,
 ) {
-	if _, err := node.Blockchain().InsertChain([]*types.Block{newBlock}, true); err != nil {
-		utils.Logger().Error().
+	if err := node.Blockchain().InsertPipeline().Insert(
+		newBlock, core.InsertConsensus, true,
+	); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).
 			Uint64("blockNum", newBlock.NumberU64()).
 			Str("parentHash", newBlock.Header().ParentHash().Hex()).
@@ -411,7 +449,7 @@ func (node *Node) PostConsensusProcessing(
 			Msg("Error Adding new block to blockchain")
 		return
 	}
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Uint64("blockNum", newBlock.NumberU64()).
 		Str("hash", newBlock.Header().Hash().Hex()).
 		Msg("Added New Block to Blockchain!!!")
@@ -430,7 +468,7 @@ func (node *Node) PostConsensusProcessing(
 		node.BroadcastCXReceipts(newBlock)
 	} else {
 		if node.Consensus.Mode() != consensus.Listening {
-			utils.Logger().Info().
+			utils.ModuleLogger(utils.ModuleNode).Info().
 				Uint64("blockNum", newBlock.NumberU64()).
 				Uint64("epochNum", newBlock.Epoch().Uint64()).
 				Uint64("ViewId", newBlock.Header().ViewID().Uint64()).
@@ -457,45 +495,52 @@ func (node *Node) PostConsensusProcessing(
 
 	// Clear metrics after one consensus cycle
 	node.host.ResetMetrics()
-	utils.Logger().Info().Msg("[metrics][p2p] Reset after 1 consensus cycle")
+	utils.ModuleLogger(utils.ModuleNode).Info().Msg("[metrics][p2p] Reset after 1 consensus cycle")
 
 	// Update consensus keys at last so the change of leader status doesn't mess up normal flow
 	if len(newBlock.Header().ShardState()) > 0 {
 		node.Consensus.SetMode(node.Consensus.UpdateConsensusInformation())
 	}
-	if h := node.NodeConfig.WebHooks.Hooks; h != nil {
-		if h.Availability != nil {
-			for _, addr := range node.GetAddresses(newBlock.Epoch()) {
-				wrapper, err := node.Beaconchain().ReadValidatorInformation(addr)
-				if err != nil {
-					return
-				}
-				snapshot, err := node.Beaconchain().ReadValidatorSnapshot(addr)
-				if err != nil {
-					return
-				}
-				computed := availability.ComputeCurrentSigning(
-					snapshot.Validator, wrapper,
-				)
-				beaconChainBlocks := uint64(node.Beaconchain().CurrentBlock().Header().Number().Int64()) %
-					shard.Schedule.BlocksPerEpoch()
-				computed.BlocksLeftInEpoch = shard.Schedule.BlocksPerEpoch() - beaconChainBlocks
-
-				if err != nil && computed.IsBelowThreshold {
-					url := h.Availability.OnDroppedBelowThreshold
-					go func() {
-						webhooks.DoPost(url, computed)
-					}()
-				}
-			}
+	node.runPostConsensusHooks(newBlock)
+}
+
+// availabilityWebhook notifies the availability webhook of the validators of
+// the node whose signing dropped below the threshold
+func (node *Node) availabilityWebhook(newBlock *types.Block) error {
+	h := node.NodeConfig.WebHooks.Hooks
+	if h == nil || h.Availability == nil {
+		return nil
+	}
+	for _, addr := range node.GetAddresses(newBlock.Epoch()) {
+		wrapper, err := node.Beaconchain().ReadValidatorInformation(addr)
+		if err != nil {
+			return err
+		}
+		snapshot, err := node.Beaconchain().ReadValidatorSnapshot(addr)
+		if err != nil {
+			return err
+		}
+		computed := availability.ComputeCurrentSigning(
+			snapshot.Validator, wrapper,
+		)
+		beaconChainBlocks := uint64(node.Beaconchain().CurrentBlock().Header().Number().Int64()) %
+			shard.Schedule.BlocksPerEpoch()
+		computed.BlocksLeftInEpoch = shard.Schedule.BlocksPerEpoch() - beaconChainBlocks
+
+		if err != nil && computed.IsBelowThreshold {
+			url := h.Availability.OnDroppedBelowThreshold
+			go func() {
+				webhooks.DoPost(url, computed)
+			}()
 		}
 	}
+	return nil
 }
 
 func (node *Node) pingMessageHandler(msgPayload []byte, sender libp2p_peer.ID) {
 	ping, err := proto_discovery.GetPingMessage(msgPayload)
 	if err != nil {
-		utils.Logger().Error().
+		utils.ModuleLogger(utils.ModuleNode).Error().
 			Err(err).
 			Msg("Can't get Ping Message")
 	}
@@ -507,16 +552,27 @@ func (node *Node) pingMessageHandler(msgPayload []byte, sender libp2p_peer.ID) {
 		ConsensusPubKey: nil,
 	}
 
+	if len(ping.Node.Addrs) > 0 {
+		addrs, err := p2p.ParseMultiaddrs(ping.Node.Addrs)
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Warn().Err(err).
+				Interface("PeerID", peer.PeerID).
+				Msg("[PING] Ignoring the advertised addresses")
+		} else {
+			peer.Addrs = addrs
+		}
+	}
+
 	if ping.Node.PubKey != nil {
 		peer.ConsensusPubKey = &bls.PublicKey{}
 		if err := peer.ConsensusPubKey.Deserialize(ping.Node.PubKey[:]); err != nil {
-			utils.Logger().Error().
+			utils.ModuleLogger(utils.ModuleNode).Error().
 				Err(err).
 				Msg("UnmarshalBinary Failed")
 		}
 	}
 
-	utils.Logger().Debug().
+	utils.ModuleLogger(utils.ModuleNode).Debug().
 		Str("Version", ping.NodeVer).
 		Str("IP", peer.IP).
 		Str("Port", peer.Port).
@@ -531,14 +587,14 @@ func (node *Node) pingMessageHandler(msgPayload []byte, sender libp2p_peer.ID) {
 	}
 
 	if err := node.host.ConnectHostPeer(peer); err != nil {
-		utils.Logger().Info().Err(err).
+		utils.ModuleLogger(utils.ModuleNode).Info().Err(err).
 			Str("peer", peer.String()).
 			Msg("could not direct connect to this peer")
 	}
 
 	if ping.Node.Role != proto_node.ClientRole {
 		node.AddPeers([]*p2p.Peer{&peer})
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Str("Peer", peer.String()).
 			Int("# Peers", node.host.GetPeerCount()).
 			Msg("Add Peer to Node")
@@ -552,11 +608,11 @@ func (node *Node) bootstrapConsensus() {
 	for range tick.C {
 		numPeersNow := node.host.GetPeerCount()
 		if numPeersNow >= node.Consensus.MinPeers {
-			utils.Logger().Info().Msg("[bootstrap] StartConsensus")
+			utils.ModuleLogger(utils.ModuleNode).Info().Msg("[bootstrap] StartConsensus")
 			node.startConsensus <- struct{}{}
 			return
 		}
-		utils.Logger().Info().
+		utils.ModuleLogger(utils.ModuleNode).Info().
 			Int("numPeersNow", numPeersNow).
 			Int("targetNumPeers", node.Consensus.MinPeers).
 			Int("next-peer-count-check-in-seconds", 5).
@@ -566,5 +622,10 @@ func (node *Node) bootstrapConsensus() {
 
 // ConsensusMessageHandler passes received message in node_handler to consensus
 func (node *Node) ConsensusMessageHandler(msgPayload []byte) {
-	node.Consensus.MsgChan <- msgPayload
+	msg, err := unmarshalConsensusMessage(msgPayload)
+	if err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("Failed to unmarshal consensus message payload.")
+		return
+	}
+	node.Consensus.EnqueueMessage(msg)
 }
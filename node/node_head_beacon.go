@@ -0,0 +1,122 @@
+package node
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/api/service/telemetry"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+)
+
+// EnableHeadBeacons makes the validator publish the head of its shard chain,
+// signed with its first BLS key, to the telemetry group at the given interval.
+// It must be called before the node starts syncing.
+func (node *Node) EnableHeadBeacons(interval time.Duration) {
+	node.headBeaconInterval = interval
+}
+
+// EnableHeadCollector makes the node join the telemetry group and aggregate
+// the head beacons of the validators of all shards. It must be called before
+// the node is started.
+func (node *Node) EnableHeadCollector() {
+	node.headCollector = telemetry.NewHeadCollector(node.isShardCommitteeKey)
+}
+
+// HeadDistribution returns the distribution of the heads reported by the
+// validators of each shard, nil if the node does not collect them
+func (node *Node) HeadDistribution() []telemetry.ShardHeads {
+	if node.headCollector == nil {
+		return nil
+	}
+	return node.headCollector.Distribution(time.Now())
+}
+
+// publishHeadBeacons publishes the head beacons of the validator until the node stops
+func (node *Node) publishHeadBeacons() {
+	ticker := time.NewTicker(node.headBeaconInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := node.publishHeadBeacon(); err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Msg("[HeadBeacon] cannot publish the head beacon")
+		}
+	}
+}
+
+// publishHeadBeacon publishes the current head of the shard chain, if this
+// validator is in the committee of its shard
+func (node *Node) publishHeadBeacon() error {
+	keys := node.NodeConfig.ConsensusPriKey
+	if keys == nil || len(keys.PrivateKey) == 0 ||
+		!node.Consensus.IsValidatorInCommittee(keys.PrivateKey[0].GetPublicKey()) {
+		return nil
+	}
+	header := node.Blockchain().CurrentHeader()
+	beacon := &proto_node.HeadBeacon{
+		ShardID: header.ShardID(),
+		Height:  header.Number().Uint64(),
+		Hash:    header.Hash(),
+		ViewID:  node.Consensus.GetViewID(),
+		Time:    uint64(time.Now().Unix()),
+	}
+	if err := beacon.Sign(keys.PrivateKey[0]); err != nil {
+		return err
+	}
+	return node.host.SendMessageToGroups(
+		[]nodeconfig.GroupID{nodeconfig.NewTelemetryGroupID()},
+		p2p.ConstructMessage(proto_node.ConstructHeadBeaconMessage(beacon)),
+	)
+}
+
+// headBeaconHandler records the head beacon of a validator, if the node
+// collects them
+func (node *Node) headBeaconHandler(payload []byte) {
+	if node.headCollector == nil {
+		return
+	}
+	beacon := &proto_node.HeadBeacon{}
+	if err := rlp.DecodeBytes(payload, beacon); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Msg("[HeadBeacon] cannot decode the head beacon")
+		return
+	}
+	if err := node.headCollector.Add(beacon, time.Now()); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).
+			Uint32("shardID", beacon.ShardID).
+			Str("key", beacon.PubKey.Hex()).
+			Msg("[HeadBeacon] dropping the head beacon")
+	}
+}
+
+// isShardCommitteeKey tells whether the key is in the committee of the given
+// shard in the current or previous epoch of the beacon chain
+func (node *Node) isShardCommitteeKey(shardID uint32, key *bls.PublicKey) bool {
+	wrapper := shard.FromLibBLSPublicKeyUnsafe(key)
+	if wrapper == nil {
+		return false
+	}
+	epoch := node.Beaconchain().CurrentHeader().Epoch()
+	epochs := []*big.Int{epoch}
+	if epoch.Sign() > 0 {
+		epochs = append(epochs, new(big.Int).Sub(epoch, common.Big1))
+	}
+	for _, epoch := range epochs {
+		shardState, err := node.Beaconchain().ReadShardState(epoch)
+		if err != nil {
+			continue
+		}
+		committee, err := shardState.FindCommitteeByID(shardID)
+		if err != nil {
+			continue
+		}
+		if _, ok := committee.SlotIndex(*wrapper); ok {
+			return true
+		}
+	}
+	return false
+}
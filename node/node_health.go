@@ -0,0 +1,106 @@
+package node
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/consensus"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Health is the aggregate health of the node served on /healthz
+type Health struct {
+	Healthy       bool              `json:"healthy"`
+	State         string            `json:"state"`
+	InSync        bool              `json:"inSync"`
+	BlockNumber   uint64            `json:"blockNumber"`
+	Validator     bool              `json:"validator"`
+	ConsensusMode string            `json:"consensusMode"`
+	Peers         int               `json:"peers"`
+	Services      map[string]string `json:"services"`
+	Degraded      string            `json:"degraded,omitempty"`
+}
+
+// Health returns the health of the node: it is healthy when it is in sync,
+// has peers, is not syncing consensus if a validator, all its supervised
+// services are healthy and its storage is not degraded
+func (node *Node) Health() Health {
+	node.stateMutex.Lock()
+	state := node.State
+	node.stateMutex.Unlock()
+
+	health := Health{
+		State:       state.String(),
+		InSync:      state != NodeNotInSync,
+		BlockNumber: node.Blockchain().CurrentBlock().NumberU64(),
+		Validator:   node.NodeConfig.Role() == nodeconfig.Validator,
+		Peers:       node.host.GetPeerCount(),
+		Services:    map[string]string{},
+	}
+	health.Healthy = health.InSync && health.Peers > 0
+	if health.Degraded = node.StorageDegraded(); health.Degraded != "" {
+		health.Healthy = false
+	}
+	if node.Consensus != nil {
+		mode := node.Consensus.Mode()
+		health.ConsensusMode = mode.String()
+		if health.Validator && mode == consensus.Syncing {
+			health.Healthy = false
+		}
+	}
+	if node.serviceManager != nil {
+		for t, err := range node.serviceManager.Health() {
+			if err != nil {
+				health.Services[t.String()] = err.Error()
+				health.Healthy = false
+			} else {
+				health.Services[t.String()] = "ok"
+			}
+		}
+	}
+	return health
+}
+
+// HealthHandler serves the health of the node as JSON, with status 503 if
+// the node is unhealthy
+func (node *Node) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := node.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if !health.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(health)
+	})
+}
+
+// ServeHealthz serves the health of the node on /healthz at the given address.
+// It returns the error of listening on the address, the errors of serving
+// once listening being logged.
+func (node *Node) ServeHealthz(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", node.HealthHandler())
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).
+				Str("addr", addr).
+				Msg("Stopped serving /healthz")
+		}
+	}()
+	return nil
+}
+
+// SuperviseServices starts restarting the crashed services of the node
+func (node *Node) SuperviseServices() {
+	if node.serviceManager == nil {
+		return
+	}
+	node.serviceManager.Supervise(service.SuperviseInterval)
+}
@@ -0,0 +1,69 @@
+package node
+
+import (
+	"os"
+
+	proto_discovery "github.com/harmony-one/harmony/api/proto/discovery"
+	"github.com/harmony-one/harmony/crypto/hash"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+// IdentityKeyFile is the name of the file under the data directory holding
+// the P2P identity key of the node.
+const IdentityKeyFile = ".hmykey"
+
+// errNoIdentityKeyFile is returned when rotating the identity of a node whose
+// key is not persisted.
+var errNoIdentityKeyFile = errors.New("P2P identity key is not persisted")
+
+// syncIDFromPeerID derives the syncID from the PeerID, so that it stays the
+// same across restarts.
+func syncIDFromPeerID(id libp2p_peer.ID) [SyncIDLength]byte {
+	var syncID [SyncIDLength]byte
+	copy(syncID[:], hash.Keccak256([]byte(id)))
+	return syncID
+}
+
+// RotateIdentity replaces the persisted P2P identity key of the node with a
+// new one and announces the new PeerID to the shard group. The previous key is
+// kept next to it with an .old suffix. The new identity is used from the next
+// restart of the node.
+func (node *Node) RotateIdentity() (libp2p_peer.ID, error) {
+	path := node.NodeConfig.P2PKeyFile
+	if path == "" {
+		return "", errNoIdentityKeyFile
+	}
+	key, _, err := utils.GenKeyP2PRand()
+	if err != nil {
+		return "", err
+	}
+	newID, err := libp2p_peer.IDFromPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(path, path+".old"); err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrapf(err, "cannot back up P2P key %s", path)
+	}
+	if err := utils.SaveKeyToFile(path, key); err != nil {
+		return "", errors.Wrapf(err, "cannot save P2P key %s", path)
+	}
+
+	self := node.SelfPeer
+	self.PeerID = newID
+	ping := proto_discovery.NewPingMessage(self, self.ConsensusPubKey == nil)
+	if err := node.host.SendMessageToGroups(
+		[]nodeconfig.GroupID{node.NodeConfig.GetShardGroupID()},
+		p2p.ConstructMessage(ping.ConstructPingMessage()),
+	); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Warn().Err(err).Msg("cannot announce new PeerID")
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Str("oldPeerID", node.SelfPeer.PeerID.Pretty()).
+		Str("newPeerID", newID.Pretty()).
+		Msg("Rotated P2P identity, effective from next restart")
+	return newID, nil
+}
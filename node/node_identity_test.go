@@ -0,0 +1,133 @@
+package node
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/consensus/quorum"
+	bls2 "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/hmyapi/apiv1"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+func newIdentityTestNode(t *testing.T, port string) *Node {
+	blsKey := bls2.RandPrivateKey()
+	leader := p2p.Peer{IP: "127.0.0.1", Port: port, ConsensusPubKey: blsKey.GetPublicKey()}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", port)
+	host, err := p2p.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := consensus.New(
+		host, shard.BeaconChainShardID, leader, multibls.GetPrivateKey(blsKey), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot craeate consensus: %v", err)
+	}
+	return New(host, consensus, testDBFactory, nil, false)
+}
+
+func TestRotateIdentity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate_identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := newIdentityTestNode(t, "8890")
+	keyFile := node.NodeConfig.P2PKeyFile
+	defer func() { node.NodeConfig.P2PKeyFile = keyFile }()
+
+	node.NodeConfig.P2PKeyFile = ""
+	if _, err := node.RotateIdentity(); err != errNoIdentityKeyFile {
+		t.Errorf("expected no rotation without key file, got %v", err)
+	}
+
+	node.NodeConfig.P2PKeyFile = filepath.Join(dir, IdentityKeyFile)
+	oldKey, _, err := utils.GenKeyP2PRand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.SaveKeyToFile(node.NodeConfig.P2PKeyFile, oldKey); err != nil {
+		t.Fatal(err)
+	}
+	oldID, _ := libp2p_peer.IDFromPrivateKey(oldKey)
+
+	newID, err := node.RotateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newID == oldID {
+		t.Error("expected a new PeerID")
+	}
+	newKey, _, err := utils.LoadKeyFromFile(node.NodeConfig.P2PKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := libp2p_peer.IDFromPrivateKey(newKey); id != newID {
+		t.Errorf("expected the key of %v persisted, got %v", newID, id)
+	}
+	backup, _, err := utils.LoadKeyFromFile(node.NodeConfig.P2PKeyFile + ".old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := libp2p_peer.IDFromPrivateKey(backup); id != oldID {
+		t.Errorf("expected the previous key %v kept, got %v", oldID, id)
+	}
+}
+
+func TestAdminEndpointLocalOnly(t *testing.T) {
+	node := newIdentityTestNode(t, "8891")
+	apis := []rpc.API{{
+		Namespace: "admin",
+		Version:   "1.0",
+		Service:   apiv1.NewPrivateAdminAPI(node, node.host, node.TxPool, nil),
+		Public:    false,
+	}}
+	if err := node.startAdmin("127.0.0.1:0", apis); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		adminListener.Close()
+		adminHandler.Stop()
+		adminListener, adminHandler = nil, nil
+	}()
+	url := "http://" + adminListener.Addr().String()
+
+	for _, test := range []struct {
+		host     string
+		expected int
+	}{
+		{"localhost", http.StatusOK},
+		{"127.0.0.1", http.StatusOK},
+		{"example.com", http.StatusForbidden},
+	} {
+		req, err := http.NewRequest("POST", url, bytes.NewBufferString(
+			`{"jsonrpc":"2.0","id":1,"method":"admin_listPeers","params":[]}`,
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = test.host
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.expected {
+			t.Errorf("host %s: expected status %d, got %d", test.host, test.expected, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package node
+
+import (
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// metadata returns the metadata of the node advertised to its peers
+func (node *Node) metadata() p2p.Metadata {
+	return p2p.Metadata{
+		Version:  nodeconfig.GetVersion(),
+		Role:     node.NodeConfig.Role().String(),
+		ShardID:  node.NodeConfig.ShardID,
+		Archival: node.isArchival,
+	}
+}
+
+// advertiseMetadata starts exchanging the metadata of the node with its peers,
+// once its role is set, and the peers they know of the shard of the node
+func (node *Node) advertiseMetadata() {
+	if node.host != nil {
+		node.host.SetMetadata(node.metadata())
+		node.host.EnablePeerExchange()
+	}
+}
@@ -1,9 +1,8 @@
 package node
 
 import (
-	"errors"
+	"math/big"
 	"sort"
-	"strings"
 	"time"
 
 	staking "github.com/harmony-one/harmony/staking/types"
@@ -13,46 +12,77 @@ import (
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
 )
 
 // Constants of proposing a new block
 const (
 	SleepPeriod           = 20 * time.Millisecond
 	IncomingReceiptsLimit = 6000 // 2000 * (numShards - 1)
+	// clockSkewRetryPeriod is how often a leader with a skewed clock checks
+	// its clock again before proposing
+	clockSkewRetryPeriod = time.Second
 )
 
+// ProposalStartDelay is how long the leader waits for the other nodes to be
+// ready before proposing its first block
+var ProposalStartDelay = 30 * time.Second
+
 // WaitForConsensusReadyV2 listen for the readiness signal from consensus and generate new block for consensus.
 // only leader will receive the ready signal
 // TODO: clean pending transactions for validators; or validators not prepare pending transactions
 func (node *Node) WaitForConsensusReadyV2(readySignal chan struct{}, stopChan chan struct{}, stoppedChan chan struct{}) {
 	go func() {
+		// A panic stops the loop, closing stoppedChan first, for the service
+		// supervisor to restart it
+		defer func() {
+			if r := recover(); r != nil {
+				utils.ModuleLogger(utils.ModuleNode).Error().
+					Interface("panic", r).
+					Msg("Block proposal loop panicked")
+			}
+		}()
 		// Setup stoppedChan
 		defer close(stoppedChan)
 
-		utils.Logger().Debug().
+		utils.ModuleLogger(utils.ModuleNode).Debug().
 			Msg("Waiting for Consensus ready")
-		// TODO: make local net start faster
-		time.Sleep(30 * time.Second) // Wait for other nodes to be ready (test-only)
+		time.Sleep(ProposalStartDelay) // Wait for other nodes to be ready (test-only)
 
 		for {
 			// keep waiting for Consensus ready
 			select {
 			case <-stopChan:
-				utils.Logger().Debug().
+				utils.ModuleLogger(utils.ModuleNode).Debug().
 					Msg("Consensus new block proposal: STOPPED!")
 				return
 			case <-readySignal:
 				for node.Consensus != nil && node.Consensus.IsLeader() {
 					time.Sleep(SleepPeriod)
+					// the timestamp of a block proposed with a skewed clock
+					// may be refused, let the view change elect another leader
+					if node.clockSkew.Exceeded() {
+						skew, peers := node.clockSkew.Skew()
+						utils.ModuleLogger(utils.ModuleNode).Warn().
+							Dur("skew", skew).
+							Int("peers", peers).
+							Msg("[WaitForConsensusReadyV2] Not proposing with a local clock skewed from the peers")
+						time.Sleep(clockSkewRetryPeriod)
+						continue
+					}
 
-					utils.Logger().Debug().
+					utils.ModuleLogger(utils.ModuleNode).Debug().
 						Uint64("blockNum", node.Blockchain().CurrentBlock().NumberU64()+1).
 						Msg("PROPOSING NEW BLOCK ------------------------------------------------")
 
-					newBlock, err := node.proposeNewBlock()
+					var err error
+					newBlock := node.reproposeBlock()
+					if newBlock == nil {
+						newBlock, err = node.proposeNewBlock()
+					}
 
 					if err == nil {
-						utils.Logger().Debug().
+						utils.ModuleLogger(utils.ModuleNode).Debug().
 							Uint64("blockNum", newBlock.NumberU64()).
 							Uint64("epoch", newBlock.Epoch().Uint64()).
 							Uint64("viewID", newBlock.Header().ViewID().Uint64()).
@@ -62,10 +92,10 @@ func (node *Node) WaitForConsensusReadyV2(readySignal chan struct{}, stopChan ch
 							Msg("=========Successfully Proposed New Block==========")
 
 						// Send the new block to Consensus so it can be confirmed.
-						node.BlockChannel <- newBlock
+						node.BlockChannel.Send(newBlock)
 						break
 					} else {
-						utils.Logger().Err(err).Msg("!!!!!!!!!Failed Proposing New Block!!!!!!!!!")
+						utils.ModuleLogger(utils.ModuleNode).Err(err).Msg("!!!!!!!!!Failed Proposing New Block!!!!!!!!!")
 					}
 				}
 			}
@@ -85,17 +115,11 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 	// Update worker's current header and
 	// state data in preparation to propose/process new transactions
 	var (
-		coinbase    = node.GetAddressForBLSKey(node.Consensus.LeaderPubKey, header.Epoch())
+		coinbase    = node.leaderCoinbase(header.Epoch())
 		beneficiary = coinbase
 		err         error
 	)
 
-	// After staking, all coinbase will be the address of bls pub key
-	if node.Blockchain().Config().IsStaking(header.Epoch()) {
-		blsPubKeyBytes := node.Consensus.LeaderPubKey.GetAddress()
-		coinbase.SetBytes(blsPubKeyBytes[:])
-	}
-
 	emptyAddr := common.Address{}
 	if coinbase == emptyAddr {
 		return nil, errors.New("[proposeNewBlock] Failed setting coinbase")
@@ -117,7 +141,7 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 
 	pendingPoolTxs, err := node.TxPool.Pending()
 	if err != nil {
-		utils.Logger().Err(err).Msg("Failed to fetch pending transactions")
+		utils.ModuleLogger(utils.ModuleNode).Err(err).Msg("Failed to fetch pending transactions")
 		return nil, err
 	}
 	pendingPlainTxs := map[common.Address]types.Transactions{}
@@ -133,7 +157,7 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 					pendingStakingTxs = append(pendingStakingTxs, stakingTx)
 				}
 			} else {
-				utils.Logger().Err(types.ErrUnknownPoolTxType).
+				utils.ModuleLogger(utils.ModuleNode).Err(types.ErrUnknownPoolTxType).
 					Msg("Failed to parse pending transactions")
 				return nil, types.ErrUnknownPoolTxType
 			}
@@ -149,7 +173,7 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 	if err := node.Worker.CommitTransactions(
 		pendingPlainTxs, pendingStakingTxs, beneficiary,
 	); err != nil {
-		utils.Logger().Error().Err(err).Msg("cannot commit transactions")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("cannot commit transactions")
 		return nil, err
 	}
 
@@ -178,24 +202,24 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 				exist, err := node.Blockchain().ReadCrossLink(pending.ShardID(), pending.BlockNum())
 				if err == nil || exist != nil {
 					invalidToDelete = append(invalidToDelete, pending)
-					utils.Logger().Debug().
+					utils.ModuleLogger(utils.ModuleNode).Debug().
 						AnErr("[proposeNewBlock] pending crosslink is already committed onchain", err)
 					continue
 				}
 				if err := node.VerifyCrossLink(pending); err != nil {
 					invalidToDelete = append(invalidToDelete, pending)
-					utils.Logger().Debug().
+					utils.ModuleLogger(utils.ModuleNode).Debug().
 						AnErr("[proposeNewBlock] pending crosslink verification failed", err)
 					continue
 				}
 				crossLinksToPropose = append(crossLinksToPropose, pending)
 			}
-			utils.Logger().Debug().
+			utils.ModuleLogger(utils.ModuleNode).Debug().
 				Msgf("[proposeNewBlock] Proposed %d crosslinks from %d pending crosslinks",
 					len(crossLinksToPropose), len(allPending),
 				)
 		} else {
-			utils.Logger().Error().Err(err).Msgf(
+			utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msgf(
 				"[proposeNewBlock] Unable to Read PendingCrossLinks, number of crosslinks: %d",
 				len(allPending),
 			)
@@ -222,7 +246,7 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 	// Prepare last commit signatures
 	sig, mask, err := node.Consensus.BlockCommitSig(header.Number().Uint64() - 1)
 	if err != nil {
-		utils.Logger().Error().Err(err).Msg("[proposeNewBlock] Cannot get commit signatures from last block")
+		utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[proposeNewBlock] Cannot get commit signatures from last block")
 		return nil, err
 	}
 
@@ -232,6 +256,49 @@ func (node *Node) proposeNewBlock() (*types.Block, error) {
 	)
 }
 
+// leaderCoinbase returns the coinbase of the blocks proposed by the current leader
+func (node *Node) leaderCoinbase(epoch *big.Int) common.Address {
+	coinbase := node.GetAddressForBLSKey(node.Consensus.LeaderPubKey(), epoch)
+	// After staking, all coinbase will be the address of bls pub key
+	if node.Blockchain().Config().IsStaking(epoch) {
+		blsPubKeyBytes := node.Consensus.LeaderPubKey().GetAddress()
+		coinbase.SetBytes(blsPubKeyBytes[:])
+	}
+	return coinbase
+}
+
+// reproposeBlock returns the block announced at the same height before a view
+// change, by this leader or the one before, moved to the current view, so it
+// is re-announced instead of being rebuilt. The block keeps the coinbase of
+// its proposer, which its state was built with, as the blocks committed after
+// a view change do. It returns nil if there is no such block.
+func (node *Node) reproposeBlock() *types.Block {
+	currentHeader := node.Blockchain().CurrentHeader()
+	cached := node.Consensus.CachedProposal(
+		currentHeader.Number().Uint64()+1, currentHeader.Hash(),
+	)
+	if cached == nil {
+		return nil
+	}
+	viewID := node.Consensus.GetViewID()
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Uint64("blockNum", cached.NumberU64()).
+		Uint64("oldViewID", cached.Header().ViewID().Uint64()).
+		Uint64("viewID", viewID).
+		Msg("Re-proposing block announced before view change")
+	return reproposal(cached, viewID)
+}
+
+// reproposal returns the block moved to the given view
+func reproposal(cached *types.Block, viewID uint64) *types.Block {
+	header := cached.Header()
+	header.SetViewID(new(big.Int).SetUint64(viewID))
+	return types.NewBlockWithHeader(header).WithBody(
+		cached.Transactions(), cached.StakingTransactions(),
+		cached.Uncles(), cached.IncomingReceipts(),
+	)
+}
+
 func (node *Node) proposeReceiptsProof() []*types.CXReceiptsProof {
 	if !node.Blockchain().Config().HasCrossTxFields(node.Worker.GetCurrentHeader().Epoch()) {
 		return []*types.CXReceiptsProof{}
@@ -269,7 +336,7 @@ Loop:
 		}
 		// check double spent
 		if node.Blockchain().IsSpent(cxp) {
-			utils.Logger().Debug().Interface("cxp", cxp).Msg("[proposeReceiptsProof] CXReceipt is spent")
+			utils.ModuleLogger(utils.ModuleNode).Debug().Interface("cxp", cxp).Msg("[proposeReceiptsProof] CXReceipt is spent")
 			continue
 		}
 		hash := cxp.MerkleProof.BlockHash
@@ -287,15 +354,15 @@ Loop:
 		}
 
 		if err := node.Blockchain().Validator().ValidateCXReceiptsProof(cxp); err != nil {
-			if strings.Contains(err.Error(), rawdb.MsgNoShardStateFromDB) {
+			if errors.Cause(err) == rawdb.ErrNoShardStateFromDB {
 				pendingReceiptsList = append(pendingReceiptsList, cxp)
 			} else {
-				utils.Logger().Error().Err(err).Msg("[proposeReceiptsProof] Invalid CXReceiptsProof")
+				utils.ModuleLogger(utils.ModuleNode).Error().Err(err).Msg("[proposeReceiptsProof] Invalid CXReceiptsProof")
 			}
 			continue
 		}
 
-		utils.Logger().Debug().Interface("cxp", cxp).Msg("[proposeReceiptsProof] CXReceipts Added")
+		utils.ModuleLogger(utils.ModuleNode).Debug().Interface("cxp", cxp).Msg("[proposeReceiptsProof] CXReceipts Added")
 		validReceiptsList = append(validReceiptsList, cxp)
 		numProposed = numProposed + len(cxp.Receipts)
 	}
@@ -308,6 +375,6 @@ Loop:
 		node.pendingCXReceipts[key] = v
 	}
 
-	utils.Logger().Debug().Msgf("[proposeReceiptsProof] number of validReceipts %d", len(validReceiptsList))
+	utils.ModuleLogger(utils.ModuleNode).Debug().Msgf("[proposeReceiptsProof] number of validReceipts %d", len(validReceiptsList))
 	return validReceiptsList
 }
@@ -0,0 +1,45 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestReproposal(t *testing.T) {
+	// the block was proposed by the leader before the view change
+	header := blockfactory.NewTestHeader().With().
+		Number(big.NewInt(10)).
+		ViewID(big.NewInt(10)).
+		Coinbase(common.BigToAddress(big.NewInt(1))).
+		Header()
+	tx := types.NewTransaction(
+		0, common.BigToAddress(big.NewInt(2)), 0, big.NewInt(1), 21000, big.NewInt(1), nil,
+	)
+	cached := types.NewBlockWithHeader(header).WithBody(
+		types.Transactions{tx}, nil, nil, nil,
+	)
+
+	block := reproposal(cached, 12)
+	if block.Header().ViewID().Uint64() != 12 {
+		t.Errorf("expected the block moved to view 12, got %v", block.Header().ViewID())
+	}
+	if block.Coinbase() != cached.Coinbase() {
+		t.Errorf("expected the coinbase of the proposer kept, got %x", block.Coinbase())
+	}
+	if block.Root() != cached.Root() || block.NumberU64() != cached.NumberU64() {
+		t.Error("expected the state of the block kept")
+	}
+	if len(block.Transactions()) != 1 || block.Transactions()[0].Hash() != tx.Hash() {
+		t.Errorf("expected the transactions kept, got %v", block.Transactions())
+	}
+	if block.Hash() == cached.Hash() {
+		t.Error("expected a new block hash in the new view")
+	}
+	if cached.Header().ViewID().Uint64() != 10 {
+		t.Errorf("expected the cached block unchanged, got view %v", cached.Header().ViewID())
+	}
+}
@@ -0,0 +1,29 @@
+package node
+
+import (
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// PinPeers pins the static and trusted peers of the node configuration on the
+// P2P host; more can be pinned at runtime through the admin API.
+func (node *Node) PinPeers() error {
+	for _, s := range node.NodeConfig.StaticPeers {
+		addr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return errors.Wrapf(err, "invalid static peer %s", s)
+		}
+		if _, err := node.host.AddStaticPeer(addr); err != nil {
+			return err
+		}
+	}
+	for _, s := range node.NodeConfig.TrustedPeers {
+		id, err := libp2p_peer.IDB58Decode(s)
+		if err != nil {
+			return errors.Wrapf(err, "invalid trusted peer %s", s)
+		}
+		node.host.AddTrustedPeer(id)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package node
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/internal/utils"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+// maxProposalPeers bounds the peers of the shard asked for a block proposed
+// header first, before the leader
+const maxProposalPeers = 3
+
+var errNoProposalPeer = errors.New("no peer to fetch the proposal from")
+
+// proposalPeers returns the peers to fetch a block proposed header first
+// from: a few peers of the shard at random, then the leader if known as a
+// last resort, to spare its bandwidth
+func (node *Node) proposalPeers() []libp2p_peer.ID {
+	self, leader := node.host.GetID(), node.leaderPeer.get(time.Now())
+	peers := []libp2p_peer.ID{}
+	for _, info := range node.host.ListPeers() {
+		if info.Metadata == nil || info.Metadata.ShardID != node.NodeConfig.ShardID ||
+			info.ID == leader || info.ID == self {
+			continue
+		}
+		peers = append(peers, info.ID)
+	}
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	if len(peers) > maxProposalPeers {
+		peers = peers[:maxProposalPeers]
+	}
+	if leader != "" && leader != self {
+		peers = append(peers, leader)
+	}
+	return peers
+}
+
+// fetchProposal fetches the encoded block proposed header first from the
+// peers of the shard, or from the leader if they fail
+func (node *Node) fetchProposal(hash common.Hash) ([]byte, error) {
+	err := errNoProposalPeer
+	for _, id := range node.proposalPeers() {
+		var proposal []byte
+		if proposal, err = node.host.FetchProposal(id, hash); err == nil {
+			return proposal, nil
+		}
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).
+			Str("peer", id.Pretty()).
+			Hex("blockHash", hash[:]).
+			Msg("cannot fetch the proposal from the peer")
+	}
+	return nil, err
+}
+
+// serveProposals serves the blocks of the consensus rounds of the node to the
+// peers fetching those proposed header first, and fetches them likewise
+func (node *Node) serveProposals() {
+	if node.host == nil || node.Consensus == nil {
+		return
+	}
+	node.Consensus.FetchProposal = node.fetchProposal
+	node.host.SetProposalProvider(node.Consensus.EncodedProposal)
+}
@@ -0,0 +1,227 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/node/worker"
+	staking "github.com/harmony-one/harmony/staking/types"
+	"github.com/pkg/errors"
+)
+
+// ReplayProposal proposes anew the block of the given number on the state of
+// its parent, from the transactions of the block and the given candidate ones
+// still in the pool, and reports the differences with the block: the
+// transactions not selected and why, and the validity of its incoming receipts
+// and crosslinks. It needs the state of the parent block, so an archival node
+// for old blocks.
+func (node *Node) ReplayProposal(
+	blockNum uint64, candidates []common.Hash,
+) (*worker.ProposalReplay, error) {
+	chain := node.Blockchain()
+	blk := chain.GetBlockByNumber(blockNum)
+	if blk == nil {
+		return nil, errors.Errorf("block %d not found", blockNum)
+	}
+	w, err := worker.NewReplay(chain, blk)
+	if err != nil {
+		return nil, err
+	}
+	beneficiary, err := chain.GetECDSAFromCoinbase(w.GetCurrentHeader())
+	if err != nil {
+		return nil, err
+	}
+	report := &worker.ProposalReplay{
+		BlockNum:  blockNum,
+		BlockHash: blk.Hash(),
+		GasLimit:  blk.GasLimit(),
+		GasUsed:   blk.GasUsed(),
+	}
+
+	// Pending transactions: those of the block, then the candidates
+	signer := types.NewEIP155Signer(chain.Config().ChainID)
+	pendingPlain := map[common.Address]types.Transactions{}
+	pendingStaking := staking.StakingTransactions{}
+	replayed := map[common.Hash]*worker.ReplayedTx{}
+	add := func(tx types.PoolTransaction, included bool) {
+		r := &worker.ReplayedTx{Hash: tx.Hash(), Included: included}
+		replayed[tx.Hash()] = r
+		report.Transactions = append(report.Transactions, r)
+		switch tx := tx.(type) {
+		case *types.Transaction:
+			from, err := types.Sender(signer, tx)
+			if err != nil {
+				r.Reason = err.Error()
+				return
+			}
+			pendingPlain[from] = append(pendingPlain[from], tx)
+		case *staking.StakingTransaction:
+			r.Staking = true
+			pendingStaking = append(pendingStaking, tx)
+		}
+	}
+	for _, tx := range blk.Transactions() {
+		add(tx, true)
+	}
+	for _, tx := range blk.StakingTransactions() {
+		add(tx, true)
+	}
+	for _, hash := range candidates {
+		if _, ok := replayed[hash]; ok {
+			continue
+		}
+		if tx := node.TxPool.Get(hash); tx != nil {
+			add(tx, false)
+			continue
+		}
+		r := &worker.ReplayedTx{Hash: hash, Reason: "not in the transaction pool"}
+		if _, _, num, _ := rawdb.ReadTransaction(chain.ChainDb(), hash); num != 0 {
+			r.Reason = fmt.Sprintf("included in block %d", num)
+		} else if _, _, num, _ := rawdb.ReadStakingTransaction(chain.ChainDb(), hash); num != 0 {
+			r.Staking = true
+			r.Reason = fmt.Sprintf("included in block %d", num)
+		}
+		report.Transactions = append(report.Transactions, r)
+	}
+	for _, txs := range pendingPlain {
+		sort.Sort(types.TxByNonce(txs))
+	}
+
+	if err := w.CommitTransactions(pendingPlain, pendingStaking, beneficiary); err != nil {
+		return nil, err
+	}
+	plainTxs, stakingTxs := w.CommittedTransactions()
+	selected := []common.Hash{}
+	for _, tx := range plainTxs {
+		selected = append(selected, tx.Hash())
+	}
+	for _, tx := range stakingTxs {
+		selected = append(selected, tx.Hash())
+	}
+	for _, hash := range selected {
+		if r, ok := replayed[hash]; ok {
+			r.Selected = true
+		}
+	}
+	for _, r := range report.Transactions {
+		if r.Selected || r.Reason != "" {
+			continue
+		}
+		if err := w.Rejected(r.Hash); err != nil {
+			r.Reason = err.Error()
+		} else {
+			r.Reason = "not reached: block gas limit or an earlier transaction of the sender"
+		}
+	}
+	report.ReplayedGasUsed = w.GetCurrentHeader().GasUsed()
+
+	// Incoming receipts, all spent by the block by now
+	validReceipts := []*types.CXReceiptsProof{}
+	for _, cxp := range blk.IncomingReceipts() {
+		r := &worker.ReplayedReceipts{
+			ShardID:   cxp.MerkleProof.ShardID,
+			BlockNum:  cxp.MerkleProof.BlockNum.Uint64(),
+			BlockHash: cxp.MerkleProof.BlockHash,
+			Receipts:  len(cxp.Receipts),
+			Valid:     true,
+		}
+		if err := chain.Validator().ValidateCXReceiptsProof(cxp); err != nil {
+			r.Valid, r.Reason = false, err.Error()
+		} else {
+			validReceipts = append(validReceipts, cxp)
+		}
+		report.Receipts = append(report.Receipts, r)
+	}
+	if chain.Config().HasCrossTxFields(blk.Epoch()) {
+		if err := w.CommitReceipts(validReceipts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Crosslinks
+	if data := blk.Header().CrossLinks(); len(data) > 0 {
+		crossLinks := types.CrossLinks{}
+		if err := rlp.DecodeBytes(data, &crossLinks); err != nil {
+			return nil, errors.Wrap(err, "cannot decode crosslinks")
+		}
+		for _, cl := range crossLinks {
+			r := &worker.ReplayedCrossLink{
+				ShardID:   cl.ShardID(),
+				BlockNum:  cl.BlockNum(),
+				BlockHash: cl.Hash(),
+				Valid:     true,
+			}
+			if err := node.VerifyCrossLink(cl); err != nil {
+				r.Valid, r.Reason = false, err.Error()
+			}
+			report.CrossLinks = append(report.CrossLinks, r)
+		}
+	}
+
+	report.Differences = proposalDifferences(blk, report, selected, w.GetCurrentHeader().IncomingReceiptHash())
+	return report, nil
+}
+
+// proposalDifferences lists how the replayed proposal differs from the block
+func proposalDifferences(
+	blk *types.Block, report *worker.ProposalReplay,
+	selected []common.Hash, incomingReceiptHash common.Hash,
+) []string {
+	diffs := []string{}
+	for _, r := range report.Transactions {
+		switch {
+		case r.Included && !r.Selected:
+			diffs = append(diffs, fmt.Sprintf("transaction %s of the block not selected: %s", r.Hash.Hex(), r.Reason))
+		case !r.Included && r.Selected:
+			diffs = append(diffs, fmt.Sprintf("candidate transaction %s selected", r.Hash.Hex()))
+		}
+	}
+	// the order of the transactions of the block selected again
+	included := []common.Hash{}
+	for _, tx := range blk.Transactions() {
+		included = append(included, tx.Hash())
+	}
+	for _, tx := range blk.StakingTransactions() {
+		included = append(included, tx.Hash())
+	}
+	reselected := []common.Hash{}
+	for _, hash := range selected {
+		for _, h := range included {
+			if h == hash {
+				reselected = append(reselected, hash)
+				break
+			}
+		}
+	}
+	for i := 0; i < len(reselected) && i < len(included); i++ {
+		if reselected[i] != included[i] {
+			diffs = append(diffs, fmt.Sprintf("transaction order differs from index %d", i))
+			break
+		}
+	}
+	if report.GasUsed != report.ReplayedGasUsed {
+		diffs = append(diffs, fmt.Sprintf("gas used %d, replayed %d", report.GasUsed, report.ReplayedGasUsed))
+	}
+	for _, r := range report.Receipts {
+		if !r.Valid {
+			diffs = append(diffs, fmt.Sprintf(
+				"incoming receipts of shard %d block %d invalid: %s", r.ShardID, r.BlockNum, r.Reason,
+			))
+		}
+	}
+	if len(blk.IncomingReceipts()) > 0 && incomingReceiptHash != blk.Header().IncomingReceiptHash() {
+		diffs = append(diffs, "incoming receipt hash differs")
+	}
+	for _, r := range report.CrossLinks {
+		if !r.Valid {
+			diffs = append(diffs, fmt.Sprintf(
+				"crosslink of shard %d block %d invalid: %s", r.ShardID, r.BlockNum, r.Reason,
+			))
+		}
+	}
+	return diffs
+}
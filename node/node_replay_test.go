@@ -0,0 +1,109 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/hmyapi/apiv1"
+	"github.com/harmony-one/harmony/node/worker"
+)
+
+func TestProposalDifferences(t *testing.T) {
+	txs, receipts := []*types.Transaction{}, []*types.Receipt{}
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		txs = append(txs, types.NewTransaction(nonce, common.Address{1}, 0, big.NewInt(1), 21000, big.NewInt(1), nil))
+		receipts = append(receipts, types.NewReceipt(nil, false, 21000*(nonce+1)))
+	}
+	header := blockfactory.NewTestHeader().With().Number(big.NewInt(1)).Header()
+	blk := types.NewBlock(header, txs, receipts, nil, nil, nil)
+	candidate := common.HexToHash("0x1")
+
+	report := &worker.ProposalReplay{
+		GasUsed:         42000,
+		ReplayedGasUsed: 42000,
+		Transactions: []*worker.ReplayedTx{
+			{Hash: txs[0].Hash(), Included: true, Selected: true},
+			{Hash: txs[1].Hash(), Included: true, Selected: true},
+			{Hash: candidate},
+		},
+	}
+	selected := []common.Hash{txs[0].Hash(), txs[1].Hash()}
+	if diffs := proposalDifferences(blk, report, selected, common.Hash{}); len(diffs) != 0 {
+		t.Errorf("expected no differences, got %v", diffs)
+	}
+
+	report.Transactions[1].Selected = false
+	report.Transactions[1].Reason = "nonce too high"
+	report.Transactions[2].Selected = true
+	report.ReplayedGasUsed = 21000
+	report.Receipts = []*worker.ReplayedReceipts{{ShardID: 1, BlockNum: 5, Reason: "bad proof"}}
+	report.CrossLinks = []*worker.ReplayedCrossLink{{ShardID: 2, BlockNum: 7, Reason: "bad signature"}}
+	selected = []common.Hash{candidate, txs[0].Hash()}
+	expected := []string{
+		"transaction " + txs[1].Hash().Hex() + " of the block not selected: nonce too high",
+		"candidate transaction " + candidate.Hex() + " selected",
+		"gas used 42000, replayed 21000",
+		"incoming receipts of shard 1 block 5 invalid: bad proof",
+		"crosslink of shard 2 block 7 invalid: bad signature",
+	}
+	if diffs := proposalDifferences(blk, report, selected, common.Hash{}); !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("expected differences %q, got %q", expected, diffs)
+	}
+
+	// the transactions of the block selected in another order
+	for _, r := range report.Transactions {
+		r.Selected, r.Reason = r.Included, ""
+	}
+	report.ReplayedGasUsed, report.Receipts, report.CrossLinks = 42000, nil, nil
+	selected = []common.Hash{txs[1].Hash(), txs[0].Hash()}
+	expected = []string{"transaction order differs from index 0"}
+	if diffs := proposalDifferences(blk, report, selected, common.Hash{}); !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("expected differences %q, got %q", expected, diffs)
+	}
+}
+
+func TestAdminReplayProposal(t *testing.T) {
+	node := newIdentityTestNode(t, "8892")
+	apis := []rpc.API{{
+		Namespace: "admin",
+		Version:   "1.0",
+		Service:   apiv1.NewPrivateAdminAPI(node, node.host, node.TxPool, nil),
+		Public:    false,
+	}}
+	if err := node.startAdmin("127.0.0.1:0", apis); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		adminListener.Close()
+		adminHandler.Stop()
+		adminListener, adminHandler = nil, nil
+	}()
+
+	resp, err := http.Post("http://"+adminListener.Addr().String(), "application/json", bytes.NewBufferString(
+		`{"jsonrpc":"2.0","id":1,"method":"admin_replayProposal","params":[1000,[]]}`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var reply struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		t.Fatal(err)
+	}
+	// the replay is served, and fails on the block missing from the chain
+	if reply.Error == nil || reply.Error.Message != "block 1000 not found" {
+		t.Errorf("unexpected reply %+v", reply.Error)
+	}
+}
@@ -0,0 +1,57 @@
+package node
+
+import (
+	"github.com/harmony-one/harmony/internal/utils"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// SetupSentry hides the node behind the sentries of the node configuration,
+// or makes it the sentry of the validators in it. It must be called before the
+// services are set up, since a node behind sentries runs no peer discovery.
+func (node *Node) SetupSentry() error {
+	if len(node.NodeConfig.Sentries) > 0 {
+		sentries := []ma.Multiaddr{}
+		for _, s := range node.NodeConfig.Sentries {
+			addr, err := ma.NewMultiaddr(s)
+			if err != nil {
+				return errors.Wrapf(err, "invalid sentry %s", s)
+			}
+			sentries = append(sentries, addr)
+		}
+		if err := node.host.EnableSentryMode(sentries, node.handleRelayedMessage); err != nil {
+			return err
+		}
+	}
+	if len(node.NodeConfig.SentryFor) > 0 {
+		validators := []libp2p_peer.ID{}
+		for _, s := range node.NodeConfig.SentryFor {
+			id, err := libp2p_peer.IDB58Decode(s)
+			if err != nil {
+				return errors.Wrapf(err, "invalid validator %s", s)
+			}
+			validators = append(validators, id)
+		}
+		node.host.ServeAsSentry(validators)
+	}
+	return nil
+}
+
+// handleRelayedMessage handles a message relayed by a sentry as the message
+// loop of Start handles the messages received from pubsub
+func (node *Node) handleRelayedMessage(msg []byte, from libp2p_peer.ID) {
+	if len(msg) < p2pMsgPrefixSize {
+		return
+	}
+	if err := validateMessage(msg[p2pMsgPrefixSize:]); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Str("sentry", from.Pretty()).Msg("[Sentry] Dropping invalid relayed message")
+		return
+	}
+	// the relayed messages are not sent by their publisher
+	if node.dispatchConsensusMessage(msg[p2pMsgPrefixSize:], "") {
+		return
+	}
+	utils.ModuleLogger(utils.ModuleNode).Debug().Str("sentry", from.Pretty()).Msg("[Sentry] Handling relayed message")
+	go node.HandleMessage(msg[p2pMsgPrefixSize:], from)
+}
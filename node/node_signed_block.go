@@ -0,0 +1,98 @@
+package node
+
+import (
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/core/types"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+var errNoCommitSig = errors.New("commit signature and bitmap too short")
+
+// blocksSyncHandler handles the blocks broadcast without commit signatures,
+// each block handed over as soon as decoded
+func (node *Node) blocksSyncHandler(payload []byte) {
+	blocks := []*types.Block{}
+	if err := proto_node.DecodeBlocksSyncMessage(payload, func(block *types.Block) error {
+		// for non-beaconchain node, subscribe to beacon block broadcast
+		if block.ShardID() == shard.BeaconChainShardID {
+			if !node.Beaconchain().Config().AcceptsUnsignedBeaconSync(block.Epoch()) {
+				utils.ModuleLogger(utils.ModuleNode).Warn().
+					Uint64("blockNum", block.NumberU64()).
+					Str("hash", block.Hash().Hex()).
+					Msg("[Sync] dropping beacon block without commit signature")
+				return nil
+			}
+			node.handleBeaconBlock(block, "Beacon block being handled by block channel")
+		}
+		blocks = append(blocks, block)
+		return nil
+	}); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().
+			Err(err).
+			Int("decoded", len(blocks)).
+			Msg("block sync")
+	}
+	if node.Client != nil && node.Client.UpdateBlocks != nil && len(blocks) > 0 {
+		utils.ModuleLogger(utils.ModuleNode).Info().Msg("Block being handled by client")
+		node.Client.UpdateBlocks(blocks)
+	}
+}
+
+// signedBlocksHandler handles the beacon blocks broadcast along with their
+// commit signatures: the blocks are inserted only if quorum-signed by the
+// beacon committee, each block as soon as decoded and verified
+func (node *Node) signedBlocksHandler(payload []byte) {
+	blocks := []*types.Block{}
+	if err := proto_node.DecodeSignedBlocksSyncMessage(payload, func(sb *proto_node.SignedBlock) error {
+		if sb.Block == nil {
+			return nil
+		}
+		if err := node.verifyBeaconBlockSig(sb); err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Warn().
+				Err(err).
+				Uint64("blockNum", sb.Block.NumberU64()).
+				Str("hash", sb.Block.Hash().Hex()).
+				Msg("[SignedSync] dropping beacon block not signed by the committee")
+			return nil
+		}
+		node.cacheBeaconCommitSig(sb.Block.NumberU64(), sb.CommitSigAndBitmap)
+		node.handleBeaconBlock(sb.Block, "Verified beacon block being handled by block channel")
+		blocks = append(blocks, sb.Block)
+		return nil
+	}); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Error().
+			Err(err).
+			Int("decoded", len(blocks)).
+			Msg("signed block sync")
+	}
+	if node.Client != nil && node.Client.UpdateBlocks != nil && len(blocks) > 0 {
+		node.Client.UpdateBlocks(blocks)
+	}
+}
+
+// handleBeaconBlock hands the beacon block over to the beacon chain insertion,
+// on the shard nodes syncing the beacon chain
+func (node *Node) handleBeaconBlock(block *types.Block, msg string) {
+	if node.Blockchain().ShardID() == shard.BeaconChainShardID ||
+		node.NodeConfig.Role() == nodeconfig.ExplorerNode {
+		return
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Uint64("block", block.NumberU64()).
+		Msg(msg)
+	go func(blk *types.Block) {
+		node.BeaconBlockChannel <- blk
+	}(block)
+}
+
+// verifyBeaconBlockSig verifies that the block is a beacon block signed by a
+// quorum of the beacon committee of its epoch
+func (node *Node) verifyBeaconBlockSig(sb *proto_node.SignedBlock) error {
+	if sb.Block.ShardID() != shard.BeaconChainShardID {
+		return errors.Errorf("block of shard %d", sb.Block.ShardID())
+	}
+	return node.verifyBeaconHeaderSig(sb.Block.Header(), sb.CommitSigAndBitmap)
+}
@@ -0,0 +1,102 @@
+package node
+
+import (
+	"sync"
+
+	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/explorer"
+	"github.com/harmony-one/harmony/api/service/storageguard"
+	"github.com/harmony-one/harmony/internal/shardchain"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+)
+
+// storagePause is the state of the optional writes, the explorer dumps and
+// the cross shard delivery records, paused while the storage is degraded
+type storagePause struct {
+	mutex    sync.Mutex
+	degraded string // reason the storage is degraded, empty if it is not
+	skipped  bool   // whether explorer dumps were skipped
+	from     uint64 // first block whose explorer dump was skipped
+}
+
+// skipExplorerDump returns whether the explorer dump of the block is to be
+// skipped, recording the block to dump once the storage recovers
+func (p *storagePause) skipExplorerDump(blockNum uint64) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.degraded == "" {
+		return false
+	}
+	if !p.skipped || blockNum < p.from {
+		p.skipped, p.from = true, blockNum
+	}
+	return true
+}
+
+// setupStorageGuard registers the storage guard service if a threshold is set
+func (node *Node) setupStorageGuard() {
+	if node.NodeConfig.StorageMinFree == 0 && node.NodeConfig.StorageMaxWriteLatency == 0 {
+		return
+	}
+	node.storageGuard = storageguard.New(
+		storageguard.Config{
+			MinFree:         node.NodeConfig.StorageMinFree,
+			MaxWriteLatency: node.NodeConfig.StorageMaxWriteLatency,
+		},
+		node.storageVolumes, node.onStorageChange,
+	)
+	node.serviceManager.RegisterService(service.StorageGuard, node.storageGuard)
+}
+
+// storageVolumes returns the directories of the databases of the shard chain
+// of the node and of the beacon chain
+func (node *Node) storageVolumes() []storageguard.Volume {
+	shardIDs := []uint32{node.NodeConfig.ShardID}
+	if node.NodeConfig.ShardID != shard.BeaconChainShardID {
+		shardIDs = append(shardIDs, shard.BeaconChainShardID)
+	}
+	volumes := make([]storageguard.Volume, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		volumes = append(volumes, storageguard.Volume{
+			ShardID: shardID,
+			Dir:     shardchain.ChainDBDir(node.NodeConfig.DBDir, shardID),
+		})
+	}
+	return volumes
+}
+
+// onStorageChange pauses the optional writes while the storage is degraded,
+// and dumps the blocks skipped by the explorer once it recovers
+func (node *Node) onStorageChange(reason string) {
+	node.storage.mutex.Lock()
+	node.storage.degraded = reason
+	skipped, from := node.storage.skipped, node.storage.from
+	if reason == "" {
+		node.storage.skipped, node.storage.from = false, 0
+	}
+	node.storage.mutex.Unlock()
+	if reason != "" || !skipped {
+		return
+	}
+	go func() {
+		to := node.Blockchain().CurrentBlock().NumberU64()
+		utils.ModuleLogger(utils.ModuleNode).Info().
+			Uint64("from", from).
+			Uint64("to", to).
+			Msg("[StorageGuard] dumping the blocks skipped by the explorer")
+		for blockNum := from; blockNum <= to; blockNum++ {
+			if block := node.Blockchain().GetBlockByNumber(blockNum); block != nil {
+				explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, true).Dump(block, blockNum)
+			}
+		}
+	}()
+}
+
+// StorageDegraded returns the reason the storage of the node is degraded,
+// empty if it is not
+func (node *Node) StorageDegraded() string {
+	node.storage.mutex.Lock()
+	defer node.storage.mutex.Unlock()
+	return node.storage.degraded
+}
@@ -0,0 +1,74 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// errSyncIDTaken is returned when a syncID is registered by a peer while it
+// is held by another one
+var errSyncIDTaken = errors.New("syncID registered by another peer")
+
+// syncIDEntry is the peer holding a syncID until the entry expires
+type syncIDEntry struct {
+	peer   string
+	expiry time.Time
+}
+
+// syncIDRegistry maps the syncIDs registered for new block broadcasts to the
+// identity of the peers which registered them, so that a syncID colliding or
+// replayed by another peer is rejected until its registration expires
+type syncIDRegistry struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]syncIDEntry
+}
+
+func newSyncIDRegistry(ttl time.Duration) *syncIDRegistry {
+	return &syncIDRegistry{ttl: ttl, entries: map[string]syncIDEntry{}}
+}
+
+// register records the syncID for the peer, or refreshes its expiry if the
+// peer already holds it. It fails if another peer holds the syncID.
+func (r *syncIDRegistry) register(syncID, peer string, now time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if entry, ok := r.entries[syncID]; ok && entry.peer != peer && now.Before(entry.expiry) {
+		return errSyncIDTaken
+	}
+	r.entries[syncID] = syncIDEntry{peer: peer, expiry: now.Add(r.ttl)}
+	return nil
+}
+
+// remove drops the registration of the syncID
+func (r *syncIDRegistry) remove(syncID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.entries, syncID)
+}
+
+// expire drops the expired registrations and returns their syncIDs
+func (r *syncIDRegistry) expire(now time.Time) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	expired := []string{}
+	for syncID, entry := range r.entries {
+		if !now.Before(entry.expiry) {
+			delete(r.entries, syncID)
+			expired = append(expired, syncID)
+		}
+	}
+	return expired
+}
+
+// unregisterPeer closes the client of a registered peer and drops its
+// registration record. Its syncID stays held by the peer until it expires.
+// The caller must hold stateMutex.
+func (node *Node) unregisterPeer(peerID string) {
+	if config, ok := node.peerRegistrationRecord[peerID]; ok {
+		config.client.Close()
+		delete(node.peerRegistrationRecord, peerID)
+	}
+}
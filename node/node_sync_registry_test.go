@@ -0,0 +1,43 @@
+package node
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateRandomString(t *testing.T) {
+	s := GenerateRandomString(SyncIDLength)
+	if len(s) != SyncIDLength {
+		t.Fatalf("got %d letters, expected %d", len(s), SyncIDLength)
+	}
+	for _, c := range s {
+		if !strings.ContainsRune(string(letterRunes), c) {
+			t.Fatalf("unexpected letter %q in %q", c, s)
+		}
+	}
+	if s == GenerateRandomString(SyncIDLength) {
+		t.Error("two random strings are equal")
+	}
+}
+
+func TestSyncIDRegistry(t *testing.T) {
+	r := newSyncIDRegistry(time.Minute)
+	now := time.Now()
+	if err := r.register("id", "1.2.3.4:9000", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.register("id", "1.2.3.4:9000", now); err != nil {
+		t.Errorf("same peer cannot register again: %v", err)
+	}
+	if err := r.register("id", "5.6.7.8:9000", now); err != errSyncIDTaken {
+		t.Errorf("got %v registering a held syncID, expected %v", err, errSyncIDTaken)
+	}
+	later := now.Add(2 * time.Minute)
+	if err := r.register("id", "5.6.7.8:9000", later); err != nil {
+		t.Errorf("cannot register an expired syncID: %v", err)
+	}
+	if expired := r.expire(later.Add(2 * time.Minute)); len(expired) != 1 || expired[0] != "id" {
+		t.Errorf("got expired %v, expected [id]", expired)
+	}
+}
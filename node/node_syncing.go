@@ -2,12 +2,14 @@ package node
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/bls/ffi/go/bls"
 	"github.com/harmony-one/harmony/api/service/syncing"
 	"github.com/harmony-one/harmony/api/service/syncing/downloader"
 	downloader_pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
@@ -17,6 +19,8 @@ import (
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node/worker"
 	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 )
 
@@ -26,8 +30,21 @@ const (
 	inSyncThreshold   = 1 // unit in number of block
 	SyncFrequency     = 60
 	MinConnectedPeers = 10 // minimum number of peers connected to in node syncing
+	// syncPeerHandshakesLimit bounds the handshakes of the incoming sync peers
+	// kept, those of the least recent peers being evicted beyond
+	syncPeerHandshakesLimit = 1024
 )
 
+// mustNewLRU returns an LRU cache of the given size, panicking on a size the
+// cache cannot be created with
+func mustNewLRU(size int) *lru.Cache {
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot create a cache of size %d", size))
+	}
+	return cache
+}
+
 // getNeighborPeers is a helper function to return list of peers
 // based on different neightbor map
 func getNeighborPeers(neighbor *sync.Map) []p2p.Peer {
@@ -47,12 +64,88 @@ func (node *Node) DoSyncWithoutConsensus() {
 	go node.DoSyncing(node.Blockchain(), node.Worker, false) //Don't join consensus
 }
 
+// createStateSync creates a state sync advertising the capabilities of this node
+func (node *Node) createStateSync() *syncing.StateSync {
+	stateSync := syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+	stateSync.SetHandshake(node.syncHandshake())
+	stateSync.SetHeightTable(node.shardHeights)
+	stateSync.SetClockSkew(node.clockSkew)
+	if node.NodeConfig.Role() == nodeconfig.Validator && node.Consensus != nil {
+		stateSync.SetAuthKeys(node.Consensus.GetPrivateKeys())
+	}
+	return stateSync
+}
+
+// refreshShardHeights keeps the heights of the shards synced by this node up to date
+func (node *Node) refreshShardHeights() {
+	ticker := time.NewTicker(syncing.ShardHeightRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		stateSync, beaconSync := node.syncs()
+		if stateSync != nil && stateSync.GetActivePeerNumber() > 0 {
+			stateSync.RefreshShardHeight(node.Blockchain().ShardID())
+		}
+		if beaconSync != nil && beaconSync.GetActivePeerNumber() > 0 {
+			beaconSync.RefreshShardHeight(node.Beaconchain().ShardID())
+		}
+	}
+}
+
+// ShardHeights returns the view of the node on the heights of the shards it syncs
+func (node *Node) ShardHeights() []syncing.ShardHeight {
+	return node.shardHeights.All()
+}
+
+// syncHandshake returns the handshake exchanged with the sync peers
+func (node *Node) syncHandshake() *downloader_pb.Handshake {
+	return downloader.NewHandshake(
+		node.NodeConfig.ShardID, node.NodeConfig.Role().String(),
+		nodeconfig.GetVersion(), node.isArchival,
+	)
+}
+
+// SyncPeerHandshake returns the handshake received from the given incoming sync peer
+func (node *Node) SyncPeerHandshake(peer string) (*downloader_pb.Handshake, bool) {
+	handshake, ok := node.syncPeerHandshakes.Get(peer)
+	if !ok {
+		return nil, false
+	}
+	return handshake.(*downloader_pb.Handshake), true
+}
+
 // IsSameHeight tells whether node is at same bc height as a peer
 func (node *Node) IsSameHeight() (uint64, bool) {
+	return node.ensureStateSync().IsSameBlockchainHeight(node.Blockchain())
+}
+
+// ensureStateSync returns the state sync of the shard chain, created if none
+func (node *Node) ensureStateSync() *syncing.StateSync {
+	node.syncLock.Lock()
+	defer node.syncLock.Unlock()
 	if node.stateSync == nil {
-		node.stateSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+		node.stateSync = node.createStateSync()
+		utils.ModuleLogger(utils.ModuleSync).Debug().Msg("[SYNC] initialized state sync")
 	}
-	return node.stateSync.IsSameBlockchainHeight(node.Blockchain())
+	return node.stateSync
+}
+
+// ensureBeaconSync returns the state sync of the beacon chain, created if none
+func (node *Node) ensureBeaconSync() *syncing.StateSync {
+	node.syncLock.Lock()
+	defer node.syncLock.Unlock()
+	if node.beaconSync == nil {
+		utils.ModuleLogger(utils.ModuleSync).Info().Msg("initializing beacon sync")
+		node.beaconSync = node.createStateSync()
+	}
+	return node.beaconSync
+}
+
+// syncs returns the state syncs of the shard and beacon chains, nil until
+// created
+func (node *Node) syncs() (stateSync, beaconSync *syncing.StateSync) {
+	node.syncLock.Lock()
+	defer node.syncLock.Unlock()
+	return node.stateSync, node.beaconSync
 }
 
 // SyncingPeerProvider is an interface for getting the peers in the given shard.
@@ -162,12 +255,12 @@ func (node *Node) DoBeaconSyncing() {
 	go func(node *Node) {
 		// TODO ek – infinite loop; add shutdown/cleanup logic
 		for beaconBlock := range node.BeaconBlockChannel {
-			if node.beaconSync != nil {
-				err := node.beaconSync.UpdateBlockAndStatus(
+			if _, beaconSync := node.syncs(); beaconSync != nil {
+				err := beaconSync.UpdateBlockAndStatus(
 					beaconBlock, node.Beaconchain(), node.BeaconWorker, true,
 				)
 				if err != nil {
-					node.beaconSync.AddLastMileBlock(beaconBlock)
+					beaconSync.AddLastMileBlock(beaconBlock)
 				}
 			}
 		}
@@ -175,71 +268,125 @@ func (node *Node) DoBeaconSyncing() {
 
 	// TODO ek – infinite loop; add shutdown/cleanup logic
 	for {
-		if node.beaconSync == nil {
-			utils.Logger().Info().Msg("initializing beacon sync")
-			node.beaconSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
-		}
+		node.ensureBeaconSync()
 		if node.beaconSync.GetActivePeerNumber() == 0 {
-			utils.Logger().Info().Msg("no peers; bootstrapping beacon sync config")
+			utils.ModuleLogger(utils.ModuleSync).Info().Msg("no peers; bootstrapping beacon sync config")
 			// 0 means shardID=0 here
 			peers, err := node.SyncingPeerProvider.SyncingPeers(0)
 			if err != nil {
-				utils.Logger().Warn().
+				utils.ModuleLogger(utils.ModuleSync).Warn().
 					Err(err).
 					Msg("cannot retrieve beacon syncing peers")
 				continue
 			}
 			if err := node.beaconSync.CreateSyncConfig(peers, true); err != nil {
-				utils.Logger().Warn().Err(err).Msg("cannot create beacon sync config")
+				utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).Msg("cannot create beacon sync config")
 				continue
 			}
 		}
-		node.beaconSync.SyncLoop(node.Beaconchain(), node.BeaconWorker, true, nil)
+		node.beaconSyncLoop()
 		time.Sleep(time.Duration(SyncFrequency) * time.Second)
 	}
 }
 
+// beaconSyncLoop syncs the beacon chain once, keeping it open meanwhile
+func (node *Node) beaconSyncLoop() {
+	beaconChain, release, err := node.shardChains.AcquireShardChain(shard.BeaconChainShardID)
+	if err != nil {
+		utils.ModuleLogger(utils.ModuleSync).Error().Err(err).Msg("cannot get beaconchain")
+		return
+	}
+	defer release()
+	node.beaconSync.SyncLoop(beaconChain, node.BeaconWorker, true, nil)
+}
+
 // DoSyncing keep the node in sync with other peers, willJoinConsensus means the node will try to join consensus after catch up
 func (node *Node) DoSyncing(bc *core.BlockChain, worker *worker.Worker, willJoinConsensus bool) {
 	ticker := time.NewTicker(time.Duration(SyncFrequency) * time.Second)
+	// a node without consensus syncs on the ticker only
+	var blockNumLow chan struct{}
+	if node.Consensus != nil {
+		blockNumLow = node.Consensus.BlockNumLowChan
+	}
 	// TODO ek – infinite loop; add shutdown/cleanup logic
 	for {
 		select {
 		case <-ticker.C:
 			node.doSync(bc, worker, willJoinConsensus)
-		case <-node.Consensus.BlockNumLowChan:
+		case <-blockNumLow:
 			node.doSync(bc, worker, willJoinConsensus)
 		}
 	}
 }
 
+// repairCommitSig fetches the commit signature of the head block from the
+// sync peers if it is missing, as after a crash of the node before the next
+// block carried it, and returns whether the signature is known
+func (node *Node) repairCommitSig(bc *core.BlockChain) bool {
+	head := bc.CurrentBlock().NumberU64()
+	if head == 0 {
+		return true
+	}
+	if sig, err := bc.ReadCommitSig(head); err == nil && len(sig) > shard.BLSSignatureSizeInBytes {
+		return true
+	}
+	if _, err := node.stateSync.FetchCommitSig(bc, head); err != nil {
+		utils.ModuleLogger(utils.ModuleSync).Warn().
+			Err(err).
+			Uint64("blockNum", head).
+			Msg("[SYNC] cannot repair commit signature of head block")
+		return false
+	}
+	utils.ModuleLogger(utils.ModuleSync).Info().
+		Uint64("blockNum", head).
+		Msg("[SYNC] repaired commit signature of head block")
+	return true
+}
+
 // doSync keep the node in sync with other peers, willJoinConsensus means the node will try to join consensus after catch up
 func (node *Node) doSync(bc *core.BlockChain, worker *worker.Worker, willJoinConsensus bool) {
-	if node.stateSync == nil {
-		node.stateSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
-		utils.Logger().Debug().Msg("[SYNC] initialized state sync")
-	}
+	node.ensureStateSync()
+	node.syncFromArchivalIfStalled(bc, worker)
 	if node.stateSync.GetActivePeerNumber() < MinConnectedPeers {
 		shardID := bc.ShardID()
 		peers, err := node.SyncingPeerProvider.SyncingPeers(shardID)
 		if err != nil {
-			utils.Logger().Warn().
+			utils.ModuleLogger(utils.ModuleSync).Warn().
 				Err(err).
 				Uint32("shard_id", shardID).
 				Msg("cannot retrieve syncing peers")
 			return
 		}
 		if err := node.stateSync.CreateSyncConfig(peers, false); err != nil {
-			utils.Logger().Warn().
+			utils.ModuleLogger(utils.ModuleSync).Warn().
 				Err(err).
 				Interface("peers", peers).
 				Msg("[SYNC] create peers error")
 			return
 		}
-		utils.Logger().Debug().Int("len", node.stateSync.GetActivePeerNumber()).Msg("[SYNC] Get Active Peers")
+		utils.ModuleLogger(utils.ModuleSync).Debug().Int("len", node.stateSync.GetActivePeerNumber()).Msg("[SYNC] Get Active Peers")
+	}
+	if node.Checkpoint != nil && bc.CurrentBlock().NumberU64() == 0 {
+		if err := node.stateSync.BootstrapFromCheckpoint(bc, node.Checkpoint); err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().
+				Err(err).
+				Uint64("checkpoint", node.Checkpoint.BlockNumber).
+				Msg("[SYNC] cannot bootstrap from checkpoint")
+		}
+	}
+	if !node.commitSigChecked {
+		node.commitSigChecked = node.repairCommitSig(bc)
+	}
+	if time.Since(node.lastForkCheck) > syncing.ForkCheckInterval {
+		node.lastForkCheck = time.Now()
+		node.rollbackFork(bc)
 	}
 	// TODO: treat fake maximum height
-	if node.stateSync.IsOutOfSync(bc) {
+	node.stateMutex.Lock()
+	resync := node.resyncRequested
+	node.resyncRequested = false
+	node.stateMutex.Unlock()
+	if resync || node.stateSync.IsOutOfSync(bc) {
 		node.stateMutex.Lock()
 		node.State = NodeNotInSync
 		node.stateMutex.Unlock()
@@ -247,6 +394,13 @@ func (node *Node) doSync(bc *core.BlockChain, worker *worker.Worker, willJoinCon
 			node.Consensus.BlocksNotSynchronized()
 		}
 		node.stateSync.SyncLoop(bc, worker, false, node.Consensus)
+		if willJoinConsensus && resync {
+			if err := node.InitConsensusWithValidators(); err != nil {
+				utils.ModuleLogger(utils.ModuleSync).Warn().
+					Err(err).
+					Msg("[SYNC] cannot reinitialize consensus after stall")
+			}
+		}
 		if willJoinConsensus {
 			node.stateMutex.Lock()
 			node.State = NodeReadyForConsensus
@@ -259,6 +413,21 @@ func (node *Node) doSync(bc *core.BlockChain, worker *worker.Worker, willJoinCon
 	node.stateMutex.Unlock()
 }
 
+// rollbackFork rolls back the local chain if it is on a minority fork, so the
+// quorum-signed blocks get synced in the following sync round
+func (node *Node) rollbackFork(bc *core.BlockChain) {
+	fork := node.stateSync.DetectFork(bc)
+	if fork == nil {
+		return
+	}
+	if err := node.stateSync.RollbackFork(bc, fork); err != nil {
+		utils.ModuleLogger(utils.ModuleSync).Error().
+			Err(err).
+			Uint64("forkNumber", fork.Number).
+			Msg("[SYNC] cannot roll back minority fork")
+	}
+}
+
 // SupportBeaconSyncing sync with beacon chain for archival node in beacon chan or non-beacon node
 func (node *Node) SupportBeaconSyncing() {
 	go node.DoBeaconSyncing()
@@ -283,18 +452,92 @@ func (node *Node) SupportSyncing() {
 	}
 
 	go node.DoSyncing(node.Blockchain(), node.Worker, joinConsensus)
+	go node.refreshShardHeights()
+	if joinConsensus {
+		go node.watchConsensus()
+	}
+	if joinConsensus && node.headBeaconInterval > 0 {
+		go node.publishHeadBeacons()
+	}
+	if node.NodeConfig.ShardID == shard.BeaconChainShardID && joinConsensus {
+		go node.monitorCrossLinkGaps()
+	}
 }
 
 // InitSyncingServer starts downloader server.
 func (node *Node) InitSyncingServer() {
 	if node.downloaderServer == nil {
 		node.downloaderServer = downloader.NewServer(node)
+		policy := node.NodeConfig.SyncAuthPolicy
+		if policy == "" || policy == downloader.AuthNone {
+			return
+		}
+		auth, err := downloader.NewAuthenticator(
+			policy, node.host.GetID(), node.isCommitteeKey, node.NodeConfig.SyncAnonRate,
+		)
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Error().Err(err).
+				Msg("[SYNC] cannot authenticate sync peers, answering any peer")
+			return
+		}
+		node.downloaderServer.SetAuthenticator(auth)
+	}
+}
+
+// committeeKeys is the set of the BLS keys of the committees of all shards in
+// an epoch and the one before, the latter for the peers lagging behind an
+// epoch change
+type committeeKeys struct {
+	mu    sync.Mutex
+	epoch *big.Int // nil until the committees of an epoch are all read
+	keys  map[shard.BLSPublicKey]struct{}
+}
+
+// has returns whether the key is in the set of the given epoch, building the
+// set anew with the given reader on an epoch change
+func (c *committeeKeys) has(
+	key shard.BLSPublicKey, epoch *big.Int, read func(*big.Int) (*shard.State, error),
+) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.epoch == nil || c.epoch.Cmp(epoch) != 0 {
+		c.epoch, c.keys = new(big.Int).Set(epoch), map[shard.BLSPublicKey]struct{}{}
+		epochs := []*big.Int{epoch}
+		if epoch.Sign() > 0 {
+			epochs = append(epochs, new(big.Int).Sub(epoch, common.Big1))
+		}
+		for _, epoch := range epochs {
+			shardState, err := read(epoch)
+			if err != nil {
+				// read again on the next lookup
+				c.epoch = nil
+				continue
+			}
+			for _, com := range shardState.Shards {
+				for _, slot := range com.Slots {
+					c.keys[slot.BLSPublicKey] = struct{}{}
+				}
+			}
+		}
+	}
+	_, ok := c.keys[key]
+	return ok
+}
+
+// isCommitteeKey returns whether the given key is in the committee of any
+// shard in the current or the previous epoch
+func (node *Node) isCommitteeKey(key *bls.PublicKey) bool {
+	wrapper := shard.FromLibBLSPublicKeyUnsafe(key)
+	if wrapper == nil {
+		return false
 	}
+	chain := node.Blockchain()
+	return node.syncAuthKeys.has(*wrapper, chain.CurrentHeader().Epoch(), chain.ReadShardState)
 }
 
 // StartSyncingServer starts syncing server.
 func (node *Node) StartSyncingServer() {
-	utils.Logger().Info().Msg("[SYNC] support_syncing: StartSyncingServer")
+	utils.ModuleLogger(utils.ModuleSync).Info().Msg("[SYNC] support_syncing: StartSyncingServer")
 	if node.downloaderServer.GrpcServer == nil {
 		node.downloaderServer.Start(node.SelfPeer.IP, syncing.GetSyncingPort(node.SelfPeer.Port))
 	}
@@ -303,31 +546,30 @@ func (node *Node) StartSyncingServer() {
 // SendNewBlockToUnsync send latest verified block to unsync, registered nodes
 func (node *Node) SendNewBlockToUnsync() {
 	for {
-		block := <-node.Consensus.VerifiedNewBlock
+		block := (<-node.Consensus.VerifiedNewBlock.C()).(*types.Block)
 		blockHash, err := rlp.EncodeToBytes(block)
 		if err != nil {
-			utils.Logger().Warn().Msg("[SYNC] unable to encode block to hashes")
+			utils.ModuleLogger(utils.ModuleSync).Warn().Msg("[SYNC] unable to encode block to hashes")
 			continue
 		}
 
 		node.stateMutex.Lock()
+		node.syncIDRegistry.expire(time.Now())
 		for peerID, config := range node.peerRegistrationRecord {
 			elapseTime := time.Now().UnixNano() - config.timestamp
 			if elapseTime > broadcastTimeout {
-				utils.Logger().Warn().Str("peerID", peerID).Msg("[SYNC] SendNewBlockToUnsync to peer timeout")
-				node.peerRegistrationRecord[peerID].client.Close()
-				delete(node.peerRegistrationRecord, peerID)
+				utils.ModuleLogger(utils.ModuleSync).Warn().Str("peerID", peerID).Msg("[SYNC] SendNewBlockToUnsync to peer timeout")
+				node.unregisterPeer(peerID)
 				continue
 			}
 			response, err := config.client.PushNewBlock(node.GetSyncID(), blockHash, false)
 			// close the connection if cannot push new block to unsync node
 			if err != nil {
-				node.peerRegistrationRecord[peerID].client.Close()
-				delete(node.peerRegistrationRecord, peerID)
+				node.unregisterPeer(peerID)
+				continue
 			}
 			if response != nil && response.Type == downloader_pb.DownloaderResponse_INSYNC {
-				node.peerRegistrationRecord[peerID].client.Close()
-				delete(node.peerRegistrationRecord, peerID)
+				node.unregisterPeer(peerID)
 			}
 		}
 		node.stateMutex.Unlock()
@@ -355,7 +597,7 @@ func (node *Node) CalculateResponse(request *downloader_pb.DownloaderRequest, in
 		startHeight := startHeader.Number().Uint64()
 		endHeight := node.Blockchain().CurrentBlock().NumberU64()
 		if startHeight >= endHeight {
-			utils.Logger().
+			utils.ModuleLogger(utils.ModuleSync).
 				Debug().
 				Uint64("myHeight", endHeight).
 				Uint64("requestHeight", startHeight).
@@ -405,13 +647,92 @@ func (node *Node) CalculateResponse(request *downloader_pb.DownloaderRequest, in
 			}
 		}
 
+	// payload holds the hash of the current block and the time of the node,
+	// which are ignored by legacy peers
 	case downloader_pb.DownloaderRequest_BLOCKHEIGHT:
-		response.BlockHeight = node.Blockchain().CurrentBlock().NumberU64()
+		currentBlock := node.Blockchain().CurrentBlock()
+		currentHash := currentBlock.Hash()
+		response.BlockHeight = currentBlock.NumberU64()
+		response.Payload = append(response.Payload, currentHash[:], syncing.EncodePeerTime(time.Now()))
+
+	// payload i holds the storage RLP encoded receipts of block hashes[i], empty if unknown
+	case downloader_pb.DownloaderRequest_RECEIPTS:
+		if len(request.Hashes) > syncing.MaxReceiptsPerRequest {
+			return response, fmt.Errorf("[SYNC] GetReceipts Request contains too many hashes %v", len(request.Hashes))
+		}
+		var hash common.Hash
+		for _, bytes := range request.Hashes {
+			hash.SetBytes(bytes)
+			encodedReceipts := []byte{}
+			if receipts := node.Blockchain().GetReceiptsByHash(hash); receipts != nil {
+				storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+				for i, receipt := range receipts {
+					storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+				}
+				if encoded, err := rlp.EncodeToBytes(storageReceipts); err == nil {
+					encodedReceipts = encoded
+				}
+			}
+			response.Payload = append(response.Payload, encodedReceipts)
+		}
+
+	// payload i holds the trie node of hashes[i], empty if unknown
+	case downloader_pb.DownloaderRequest_STATENODE:
+		if len(request.Hashes) > syncing.MaxStateNodesPerRequest {
+			return response, fmt.Errorf("[SYNC] GetStateNodes Request contains too many hashes %v", len(request.Hashes))
+		}
+		var hash common.Hash
+		for _, bytes := range request.Hashes {
+			hash.SetBytes(bytes)
+			stateNode, err := node.Blockchain().TrieNode(hash)
+			if err != nil {
+				stateNode = []byte{}
+			}
+			response.Payload = append(response.Payload, stateNode)
+		}
+
+	// payload holds the RLP encoded canonical headers from request.BlockNumber up to the current block
+	case downloader_pb.DownloaderRequest_CANONICALHEADERS:
+		if request.Size > syncing.MaxCanonicalHeadersPerRequest {
+			return response, fmt.Errorf("[SYNC] GetCanonicalHeaders Request size too large %v", request.Size)
+		}
+		for i := uint64(0); i < uint64(request.Size); i++ {
+			header := node.Blockchain().GetHeaderByNumber(request.BlockNumber + i)
+			if header == nil {
+				break
+			}
+			encodedHeader, err := rlp.EncodeToBytes(header)
+			if err != nil {
+				break
+			}
+			response.Payload = append(response.Payload, encodedHeader)
+		}
+
+	// payload holds the commit signature and bitmap of the block, empty if unknown
+	case downloader_pb.DownloaderRequest_COMMITSIG:
+		sigAndBitmap, _ := node.Blockchain().ReadCommitSig(request.BlockNumber)
+		response.Payload = append(response.Payload, sigAndBitmap)
+
+	case downloader_pb.DownloaderRequest_HANDSHAKE:
+		if request.Handshake == nil {
+			return response, fmt.Errorf("[SYNC] Handshake Request contains no handshake")
+		}
+		node.syncPeerHandshakes.Add(incomingPeer, request.Handshake)
+		utils.ModuleLogger(utils.ModuleSync).Debug().
+			Str("incomingPeer", incomingPeer).
+			Uint32("protocolVersion", request.Handshake.ProtocolVersion).
+			Strs("features", request.Handshake.Features).
+			Uint32("shardID", request.Handshake.ShardID).
+			Str("role", request.Handshake.Role).
+			Str("version", request.Handshake.Version).
+			Bool("archival", request.Handshake.Archival).
+			Msg("[SYNC] handshake received")
+		response.Handshake = node.syncHandshake()
 
 	// this is the out of sync node acts as grpc server side
 	case downloader_pb.DownloaderRequest_NEWBLOCK:
 		if node.State != NodeNotInSync {
-			utils.Logger().Debug().
+			utils.ModuleLogger(utils.ModuleSync).Debug().
 				Str("state", node.State.String()).
 				Msg("[SYNC] new block received, but state is")
 			response.Type = downloader_pb.DownloaderResponse_INSYNC
@@ -420,7 +741,7 @@ func (node *Node) CalculateResponse(request *downloader_pb.DownloaderRequest, in
 		var blockObj types.Block
 		err := rlp.DecodeBytes(request.BlockHash, &blockObj)
 		if err != nil {
-			utils.Logger().Warn().Msg("[SYNC] unable to decode received new block")
+			utils.ModuleLogger(utils.ModuleSync).Warn().Msg("[SYNC] unable to decode received new block")
 			return response, err
 		}
 		node.stateSync.AddNewBlock(request.PeerHash, &blockObj)
@@ -429,46 +750,57 @@ func (node *Node) CalculateResponse(request *downloader_pb.DownloaderRequest, in
 		peerID := string(request.PeerHash[:])
 		ip := request.Ip
 		port := request.Port
+		now := time.Now()
 		node.stateMutex.Lock()
 		defer node.stateMutex.Unlock()
-		if _, ok := node.peerRegistrationRecord[peerID]; ok {
-			response.Type = downloader_pb.DownloaderResponse_FAIL
-			utils.Logger().Warn().
-				Interface("ip", ip).
-				Interface("port", port).
-				Msg("[SYNC] peerRegistration record already exists")
-			return response, nil
-		} else if len(node.peerRegistrationRecord) >= maxBroadcastNodes {
-			response.Type = downloader_pb.DownloaderResponse_FAIL
-			utils.Logger().Debug().
+		response.Type = downloader_pb.DownloaderResponse_FAIL
+		if config, ok := node.peerRegistrationRecord[peerID]; ok {
+			if now.UnixNano()-config.timestamp <= broadcastTimeout {
+				utils.ModuleLogger(utils.ModuleSync).Warn().
+					Interface("ip", ip).
+					Interface("port", port).
+					Msg("[SYNC] peerRegistration record already exists")
+				return response, nil
+			}
+			// the previous registration timed out, release its client
+			node.unregisterPeer(peerID)
+		}
+		if len(node.peerRegistrationRecord) >= maxBroadcastNodes {
+			utils.ModuleLogger(utils.ModuleSync).Debug().
 				Str("ip", ip).
 				Str("port", port).
 				Msg("[SYNC] maximum registration limit exceeds")
 			return response, nil
-		} else {
-			response.Type = downloader_pb.DownloaderResponse_FAIL
-			syncPort := syncing.GetSyncingPort(port)
-			client := downloader.ClientSetup(ip, syncPort)
-			if client == nil {
-				utils.Logger().Warn().
-					Str("ip", ip).
-					Str("port", port).
-					Msg("[SYNC] unable to setup client for peerID")
-				return response, nil
-			}
-			config := &syncConfig{timestamp: time.Now().UnixNano(), client: client}
-			node.peerRegistrationRecord[peerID] = config
-			utils.Logger().Debug().
+		}
+		if err := node.syncIDRegistry.register(peerID, net.JoinHostPort(ip, port), now); err != nil {
+			utils.ModuleLogger(utils.ModuleSync).Warn().Err(err).
 				Str("ip", ip).
 				Str("port", port).
-				Msg("[SYNC] register peerID success")
-			response.Type = downloader_pb.DownloaderResponse_SUCCESS
+				Msg("[SYNC] rejected peer registration")
+			return response, nil
+		}
+		syncPort := syncing.GetSyncingPort(port)
+		client := downloader.ClientSetup(ip, syncPort)
+		if client == nil {
+			node.syncIDRegistry.remove(peerID)
+			utils.ModuleLogger(utils.ModuleSync).Warn().
+				Str("ip", ip).
+				Str("port", port).
+				Msg("[SYNC] unable to setup client for peerID")
+			return response, nil
 		}
+		config := &syncConfig{timestamp: now.UnixNano(), client: client}
+		node.peerRegistrationRecord[peerID] = config
+		utils.ModuleLogger(utils.ModuleSync).Debug().
+			Str("ip", ip).
+			Str("port", port).
+			Msg("[SYNC] register peerID success")
+		response.Type = downloader_pb.DownloaderResponse_SUCCESS
 
 	case downloader_pb.DownloaderRequest_REGISTERTIMEOUT:
 		if node.State == NodeNotInSync {
 			count := node.stateSync.RegisterNodeInfo()
-			utils.Logger().Debug().
+			utils.ModuleLogger(utils.ModuleSync).Debug().
 				Int("number", count).
 				Msg("[SYNC] extra node registered")
 		}
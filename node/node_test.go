@@ -1,18 +1,32 @@
 package node
 
 import (
-	"errors"
+	"bytes"
+	"context"
+	"math/big"
+	"strconv"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/api/service/syncing"
+	downloader_pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
 	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/consensus/quorum"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
 	bls2 "github.com/harmony-one/harmony/crypto/bls"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/shardchain"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/multibls"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/shard"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,6 +64,21 @@ func TestNewNode(t *testing.T) {
 	}
 }
 
+func TestNewRPCNode(t *testing.T) {
+	self := p2p.Peer{IP: "127.0.0.1", Port: "8883"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9903")
+	host, err := p2p.NewHost(&self, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	node := NewRPCNode(host, shard.BeaconChainShardID, testDBFactory, nil, false)
+	assert.Nil(t, node.Consensus)
+	assert.Equal(t, nodeconfig.RPCNode, node.NodeConfig.Role())
+	assert.NotNil(t, node.Blockchain().CurrentBlock())
+	assert.NotNil(t, node.TxPool)
+	assert.False(t, node.IsCurrentlyLeader())
+}
+
 func TestLegacySyncingPeerProvider(t *testing.T) {
 	t.Run("ShardChain", func(t *testing.T) {
 		p := makeLegacySyncingPeerProvider()
@@ -221,3 +250,313 @@ func TestAddBeaconPeer(t *testing.T) {
 		}
 	}
 }
+
+func TestProposalPeers(t *testing.T) {
+	network := p2p.NewMemNetwork()
+	self := network.NewHost(p2p.Peer{IP: "127.0.0.1", Port: "9000"})
+	self.SetMetadata(p2p.Metadata{ShardID: 1})
+	leader := network.NewHost(p2p.Peer{IP: "127.0.0.1", Port: "9001"})
+	leader.SetMetadata(p2p.Metadata{ShardID: 1})
+	shardPeers := map[libp2p_peer.ID]bool{leader.GetID(): true}
+	for port := 9002; port < 9007; port++ {
+		peer := network.NewHost(p2p.Peer{IP: "127.0.0.1", Port: strconv.Itoa(port)})
+		peer.SetMetadata(p2p.Metadata{ShardID: 1})
+		shardPeers[peer.GetID()] = true
+	}
+	network.NewHost(p2p.Peer{IP: "127.0.0.1", Port: "9007"}).SetMetadata(p2p.Metadata{ShardID: 2})
+
+	node := &Node{host: self, NodeConfig: &nodeconfig.ConfigType{ShardID: 1}}
+	for _, test := range []struct {
+		name   string
+		leader libp2p_peer.ID
+		count  int
+	}{
+		{"leader unknown", "", maxProposalPeers},
+		{"leader last", leader.GetID(), maxProposalPeers + 1},
+		{"leader self", self.GetID(), maxProposalPeers},
+	} {
+		node.leaderPeer.set(test.leader, time.Now())
+		peers := node.proposalPeers()
+		if len(peers) != test.count {
+			t.Errorf("%s: expected %d peers, got %d", test.name, test.count, len(peers))
+			continue
+		}
+		for _, id := range peers[:maxProposalPeers] {
+			if !shardPeers[id] || id == test.leader {
+				t.Errorf("%s: expected the peers of the shard first, got %s", test.name, id.Pretty())
+			}
+		}
+		if test.count > maxProposalPeers && peers[maxProposalPeers] != test.leader {
+			t.Errorf("%s: expected the leader last, got %s", test.name, peers[maxProposalPeers].Pretty())
+		}
+	}
+}
+
+// newMemTestNode returns a beacon chain node on a host of the in-memory network
+func newMemTestNode(t *testing.T, network *p2p.MemNetwork, port string) *Node {
+	host := network.NewHost(p2p.Peer{IP: "127.0.0.1", Port: port})
+	blsKey := bls2.RandPrivateKey()
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	consensus, err := consensus.New(
+		host, shard.BeaconChainShardID, p2p.Peer{}, multibls.GetPrivateKey(blsKey), decider,
+	)
+	if err != nil {
+		t.Fatalf("Cannot create consensus: %v", err)
+	}
+	return New(host, consensus, testDBFactory, nil, false)
+}
+
+func TestRelayTransaction(t *testing.T) {
+	network := p2p.NewMemNetwork()
+	node := newMemTestNode(t, network, "9010")
+
+	// a node of shard 1 listening to the transactions of its clients
+	shard1 := network.NewHost(p2p.Peer{IP: "127.0.0.1", Port: "9011"})
+	clientGroup := nodeconfig.NewClientGroupIDByShardID(1)
+	shard1.SendMessageToGroups([]nodeconfig.GroupID{clientGroup}, []byte{})
+	subs, _ := shard1.AllSubscriptions()
+	received := func() int {
+		count := 0
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			msg, err := subs[0].Next(ctx)
+			cancel()
+			if err != nil {
+				return count
+			}
+			if len(msg.Data) > 0 {
+				count++
+			}
+		}
+	}
+	received()
+
+	key, _ := crypto.GenerateKey()
+	signer := types.MakeSigner(node.Blockchain().Config(), big.NewInt(0))
+	sign := func(tx *types.Transaction) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	numShards := shard.Schedule.InstanceForEpoch(big.NewInt(0)).NumShards()
+	for _, test := range []struct {
+		name     string
+		tx       *types.Transaction
+		expected error // cause of the error, nil if relayed
+	}{
+		{
+			"other shard",
+			sign(types.NewCrossShardTransaction(0, &common.Address{1}, 1, 1, big.NewInt(1), 21000, big.NewInt(1), nil)),
+			nil,
+		},
+		{
+			"unknown shard",
+			sign(types.NewCrossShardTransaction(0, &common.Address{1}, numShards, 1, big.NewInt(1), 21000, big.NewInt(1), nil)),
+			errUnknownTxShard,
+		},
+		{
+			"unknown destination shard",
+			sign(types.NewCrossShardTransaction(0, &common.Address{1}, 1, numShards, big.NewInt(1), 21000, big.NewInt(1), nil)),
+			errUnknownTxShard,
+		},
+		{
+			"unsigned",
+			types.NewCrossShardTransaction(0, &common.Address{1}, 1, 1, big.NewInt(1), 21000, big.NewInt(1), nil),
+			types.ErrInvalidChainID,
+		},
+	} {
+		err := node.AddPendingTransaction(test.tx)
+		if errors.Cause(err) != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+		relayed := 0
+		if test.expected == nil {
+			relayed = 1
+		}
+		if count := received(); count != relayed {
+			t.Errorf("%s: expected %d transactions relayed, got %d", test.name, relayed, count)
+		}
+		if pending, _ := node.TxPool.Stats(); pending != 0 {
+			t.Errorf("%s: expected no transaction in the pool of shard 0, got %d", test.name, pending)
+		}
+	}
+}
+
+func TestCheckConsensus(t *testing.T) {
+	node := newMemTestNode(t, p2p.NewMemNetwork(), "9020")
+	node.Consensus.BlockNumLowChan = make(chan struct{}, 1)
+	last := time.Unix(node.Blockchain().CurrentHeader().Time().Int64(), 0)
+	for _, test := range []struct {
+		name    string
+		mode    consensus.Mode
+		period  time.Duration
+		since   time.Duration // time since the last block
+		stalled bool
+	}{
+		{"committing", consensus.Normal, 5 * time.Second, 10 * time.Second, false},
+		{"at the stall", consensus.Normal, 5 * time.Second, consensusStallPeriods * 5 * time.Second, false},
+		{"stalled", consensus.Normal, 5 * time.Second, time.Hour, true},
+		{"syncing", consensus.Syncing, 5 * time.Second, time.Hour, false},
+		{"view changing", consensus.ViewChanging, 5 * time.Second, time.Hour, false},
+		{"no block period", consensus.Normal, 0, time.Hour, false},
+	} {
+		node.Consensus.SetMode(test.mode)
+		node.Consensus.BlockPeriod = test.period
+		node.State, node.resyncRequested = NodeReadyForConsensus, false
+
+		node.checkConsensus(last.Add(test.since))
+		if stalled := node.State == NodeNotInSync; stalled != test.stalled {
+			t.Errorf("%s: expected stalled %t, got state %s", test.name, test.stalled, node.State)
+		}
+		if node.resyncRequested != test.stalled {
+			t.Errorf("%s: expected the resync requested %t", test.name, test.stalled)
+		}
+		select {
+		case <-node.Consensus.BlockNumLowChan:
+			if !test.stalled {
+				t.Errorf("%s: expected no sync kicked", test.name)
+			}
+		default:
+			if test.stalled {
+				t.Errorf("%s: expected the sync kicked", test.name)
+			}
+		}
+	}
+}
+
+func TestPinPeers(t *testing.T) {
+	network := p2p.NewMemNetwork()
+	node := newMemTestNode(t, network, "9030")
+	key, _, _ := utils.GenKeyP2P("127.0.0.1", "9031")
+	peer, err := libp2p_peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	static, trusted := node.NodeConfig.StaticPeers, node.NodeConfig.TrustedPeers
+	defer func() {
+		node.NodeConfig.StaticPeers, node.NodeConfig.TrustedPeers = static, trusted
+	}()
+
+	for _, test := range []struct {
+		name    string
+		static  string
+		trusted string
+		valid   bool
+	}{
+		{"static and trusted peer", "/ip4/127.0.0.1/tcp/9031/p2p/" + peer.Pretty(), peer.Pretty(), true},
+		{"malformed static address", "127.0.0.1:9031", peer.Pretty(), false},
+		{"static address without peer ID", "/ip4/127.0.0.1/tcp/9031", peer.Pretty(), false},
+		{"malformed trusted peer ID", "/ip4/127.0.0.1/tcp/9031/p2p/" + peer.Pretty(), "9031", false},
+	} {
+		node.NodeConfig.StaticPeers = []string{test.static}
+		node.NodeConfig.TrustedPeers = []string{test.trusted}
+		for _, info := range node.host.StaticPeers() {
+			node.host.RemoveStaticPeer(info.ID)
+		}
+		node.host.RemoveTrustedPeer(peer)
+
+		err := node.PinPeers()
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected the peers pinned %t, got %v", test.name, test.valid, err)
+		}
+		if !test.valid {
+			continue
+		}
+		if peers := node.host.StaticPeers(); len(peers) != 1 || peers[0].ID != peer {
+			t.Errorf("%s: expected the static peer pinned, got %v", test.name, peers)
+		}
+		if !node.host.IsTrustedPeer(peer) {
+			t.Errorf("%s: expected the peer trusted", test.name)
+		}
+	}
+}
+
+func TestCalculateResponseCommitSig(t *testing.T) {
+	node := newMemTestNode(t, p2p.NewMemNetwork(), "9040")
+	sig := make([]byte, shard.BLSSignatureSizeInBytes+1)
+	sig[0] = 7
+	if err := node.Blockchain().WriteCommitSig(7, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		number   uint64
+		expected []byte
+	}{
+		{"signed block", 7, sig},
+		{"unknown block", 8, nil},
+	} {
+		response, err := node.CalculateResponse(&downloader_pb.DownloaderRequest{
+			Type: downloader_pb.DownloaderRequest_COMMITSIG, BlockNumber: test.number,
+		}, "")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(response.Payload) != 1 || !bytes.Equal(response.Payload[0], test.expected) {
+			t.Errorf("%s: expected the payload %x, got %x", test.name, test.expected, response.Payload)
+		}
+	}
+}
+
+func TestCalculateResponseReceiptsAndStateNodes(t *testing.T) {
+	node := newMemTestNode(t, p2p.NewMemNetwork(), "9041")
+	to := common.Address{0x11}
+	tx := types.NewTransaction(0, to, 0, big.NewInt(1), 21000, big.NewInt(1), nil)
+	header := cxTestBlock(t, node, 0, 5, tx)
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, TxHash: tx.Hash()}}
+	rawdb.WriteReceipts(node.Blockchain().ChainDb(), header.Hash(), 5, receipts)
+	root := node.Blockchain().CurrentBlock().Root()
+	unknown := common.Hash{0x22}
+
+	for _, test := range []struct {
+		name    string
+		typ     downloader_pb.DownloaderRequest_RequestType
+		hashes  []common.Hash
+		known   []bool // whether each hash is known, its payload empty otherwise
+		invalid bool
+	}{
+		{"receipts", downloader_pb.DownloaderRequest_RECEIPTS, []common.Hash{header.Hash(), unknown}, []bool{true, false}, false},
+		{"too many receipts", downloader_pb.DownloaderRequest_RECEIPTS, make([]common.Hash, syncing.MaxReceiptsPerRequest+1), nil, true},
+		{"state nodes", downloader_pb.DownloaderRequest_STATENODE, []common.Hash{root, unknown}, []bool{true, false}, false},
+		{"too many state nodes", downloader_pb.DownloaderRequest_STATENODE, make([]common.Hash, syncing.MaxStateNodesPerRequest+1), nil, true},
+	} {
+		request := &downloader_pb.DownloaderRequest{Type: test.typ}
+		for _, hash := range test.hashes {
+			request.Hashes = append(request.Hashes, hash.Bytes())
+		}
+		response, err := node.CalculateResponse(request, "")
+		if (err != nil) != test.invalid {
+			t.Errorf("%s: expected an error %t, got %v", test.name, test.invalid, err)
+			continue
+		}
+		if test.invalid {
+			continue
+		}
+		if len(response.Payload) != len(test.hashes) {
+			t.Fatalf("%s: expected %d payloads, got %d", test.name, len(test.hashes), len(response.Payload))
+		}
+		for i, payload := range response.Payload {
+			if !test.known[i] {
+				if len(payload) != 0 {
+					t.Errorf("%s: expected nothing for %x, got %x", test.name, test.hashes[i], payload)
+				}
+				continue
+			}
+			switch test.typ {
+			case downloader_pb.DownloaderRequest_RECEIPTS:
+				decoded := []*types.ReceiptForStorage{}
+				if err := rlp.DecodeBytes(payload, &decoded); err != nil || len(decoded) != 1 ||
+					decoded[0].CumulativeGasUsed != 21000 {
+					t.Errorf("%s: expected the receipts of block 5, got %+v, %v", test.name, decoded, err)
+				}
+			case downloader_pb.DownloaderRequest_STATENODE:
+				if crypto.Keccak256Hash(payload) != test.hashes[i] {
+					t.Errorf("%s: expected the state node %x, got %x", test.name, test.hashes[i], payload)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony/api/proto"
+	msg_pb "github.com/harmony-one/harmony/api/proto/message"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+// leaderPeerTTL is how long the peer of the leader is sent the transactions
+// and asked for the blocks proposed header first after its last announce, the
+// view changing meanwhile
+const leaderPeerTTL = time.Minute
+
+// leaderPeer is the peer of the leader of the shard, as learnt from the
+// announces it signed
+type leaderPeer struct {
+	lock sync.RWMutex
+	id   libp2p_peer.ID
+	seen time.Time
+}
+
+func (l *leaderPeer) set(id libp2p_peer.ID, now time.Time) {
+	l.lock.Lock()
+	l.id, l.seen = id, now
+	l.lock.Unlock()
+}
+
+// get returns the peer of the leader, empty if unknown or not seen lately
+func (l *leaderPeer) get(now time.Time) libp2p_peer.ID {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	if now.Sub(l.seen) > leaderPeerTTL {
+		return ""
+	}
+	return l.id
+}
+
+// observeLeaderAnnounce records the peer which published the announce as the
+// one of the leader if the announce is signed by the leader of the round
+func (node *Node) observeLeaderAnnounce(msg *msg_pb.Message, from libp2p_peer.ID) {
+	if from == "" || msg.GetType() != msg_pb.MessageType_ANNOUNCE {
+		return
+	}
+	if err := node.Consensus.VerifyLeaderMessage(msg); err != nil {
+		return
+	}
+	node.leaderPeer.set(from, time.Now())
+}
+
+// sendTxToLeader sends the transaction message straight to the leader of the
+// shard of the node under the leader fan-out. It returns false if the message
+// has to be published to the group of the shard instead.
+func (node *Node) sendTxToLeader(shardID uint32, msg []byte) bool {
+	if node.NodeConfig.TxBroadcast.Fanout != nodeconfig.TxFanoutLeader ||
+		shardID != node.NodeConfig.ShardID {
+		return false
+	}
+	leader := node.leaderPeer.get(time.Now())
+	switch leader {
+	case "":
+		return false
+	case node.host.GetID():
+		// already in the pool of the leader
+		return true
+	}
+	if err := node.host.SendDirect(leader, msg); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).
+			Str("leader", leader.Pretty()).
+			Msg("cannot send transactions to the leader, publishing them")
+		return false
+	}
+	return true
+}
+
+// broadcastTxMessage sends the transaction message to the validators of the
+// shard after the fan-out of the node, and to the client group of the shard
+// if configured
+func (node *Node) broadcastTxMessage(shardID uint32, msg []byte) {
+	p2pMsg := p2p.ConstructMessage(msg)
+	groups := []nodeconfig.GroupID{}
+	if !node.sendTxToLeader(shardID, p2pMsg) {
+		groups = append(groups, nodeconfig.NewGroupIDByShardID(nodeconfig.ShardID(shardID)))
+	}
+	if node.NodeConfig.TxBroadcast.Clients {
+		groups = append(groups, nodeconfig.NewClientGroupIDByShardID(nodeconfig.ShardID(shardID)))
+	}
+	if len(groups) == 0 {
+		return
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().Interface("groups", groups).Msg("broadcastTxMessage")
+
+	for attempt := 0; attempt < NumTryBroadCast; attempt++ {
+		if err := node.host.SendMessageToGroups(groups, p2pMsg); err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Error().Int("attempt", attempt).Msg("Error when trying to broadcast transactions")
+		} else {
+			break
+		}
+	}
+}
+
+// handleDirectMessage handles a message sent straight to the node, only the
+// transactions being accepted this way
+func (node *Node) handleDirectMessage(msg []byte, from libp2p_peer.ID) {
+	if len(msg) < p2pMsgPrefixSize {
+		return
+	}
+	content := msg[p2pMsgPrefixSize:]
+	if err := validateMessage(content); err != nil {
+		utils.ModuleLogger(utils.ModuleNode).Debug().Err(err).Str("from", from.Pretty()).Msg("dropping invalid direct message")
+		return
+	}
+	category, err := proto.GetMessageCategory(content)
+	if err != nil || category != proto.Node {
+		return
+	}
+	msgType, err := proto.GetMessageType(content)
+	if err != nil {
+		return
+	}
+	switch proto_node.MessageType(msgType) {
+	case proto_node.Transaction, proto_node.Staking:
+		node.HandleMessage(content, from)
+	}
+}
+
+// acceptDirectTransactions accepts the transactions sent straight to the
+// validator while it leads
+func (node *Node) acceptDirectTransactions() {
+	if node.host != nil && node.NodeConfig.Role() == nodeconfig.Validator {
+		node.host.SetDirectHandler(node.handleDirectMessage)
+	}
+}
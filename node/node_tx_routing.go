@@ -0,0 +1,52 @@
+package node
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/core/types"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+var (
+	errUnknownTxShard = errors.New("transaction shard is not in the network")
+	errTxRelayFailed  = errors.New("cannot relay transaction to its shard")
+
+	relayedTxCounter = metrics.NewRegisteredCounter("node/tx/relayed", nil)
+)
+
+// relayTransaction validates a transaction submitted to this node for another
+// shard and relays it to the client group of its shard, whose nodes add it to
+// their transaction pool.
+func (node *Node) relayTransaction(tx *types.Transaction) error {
+	header := node.Blockchain().CurrentHeader()
+	numShards := shard.Schedule.InstanceForEpoch(header.Epoch()).NumShards()
+	if tx.ShardID() >= numShards || tx.ToShardID() >= numShards {
+		return errors.Wrapf(
+			errUnknownTxShard, "shard %d to shard %d, network has %d shards",
+			tx.ShardID(), tx.ToShardID(), numShards,
+		)
+	}
+	signer := types.MakeSigner(node.Blockchain().Config(), header.Epoch())
+	if _, err := types.Sender(signer, tx); err != nil {
+		return errors.Wrap(err, "invalid transaction signature")
+	}
+
+	msg := proto_node.ConstructTransactionListMessageAccount(types.Transactions{tx})
+	clientGroupID := nodeconfig.NewClientGroupIDByShardID(nodeconfig.ShardID(tx.ShardID()))
+	if err := node.host.SendMessageToGroups(
+		[]nodeconfig.GroupID{clientGroupID}, p2p.ConstructMessage(msg),
+	); err != nil {
+		return errors.Wrapf(errTxRelayFailed, "shard %d: %v", tx.ShardID(), err)
+	}
+	relayedTxCounter.Inc(1)
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Str("Hash", tx.Hash().Hex()).
+		Uint32("shardID", tx.ShardID()).
+		Str("clientGroupID", string(clientGroupID)).
+		Msg("Relayed Tx to its shard")
+	return nil
+}
@@ -1,21 +1,29 @@
 package node
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
 )
 
 var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
-// GenerateRandomString generates a random string with given length
+// GenerateRandomString generates a random string with given length from a
+// cryptographically secure source. It panics if the source fails.
 func GenerateRandomString(n int) string {
-	b := make([]rune, n)
-	for i := range b {
-		b[i] = letterRunes[rand.Intn(len(letterRunes))]
+	// bytes above the largest multiple of the number of letters are rejected
+	// so that every letter is equally likely
+	limit := byte(256 - 256%len(letterRunes))
+	b := make([]rune, 0, n)
+	buf := make([]byte, n)
+	for len(b) < n {
+		if _, err := rand.Read(buf); err != nil {
+			panic("cannot read random bytes: " + err.Error())
+		}
+		for _, c := range buf {
+			if c >= limit || len(b) == n {
+				continue
+			}
+			b = append(b, letterRunes[int(c)%len(letterRunes)])
+		}
 	}
 	return string(b)
 }
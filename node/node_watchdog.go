@@ -0,0 +1,66 @@
+package node
+
+import (
+	"time"
+
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+const (
+	// consensusStallPeriods is the number of block periods without a new
+	// block after which the consensus of the shard is considered stalled
+	consensusStallPeriods = 10
+	// consensusWatchdogInterval is the interval between two stall checks
+	consensusWatchdogInterval = 10 * time.Second
+)
+
+// watchConsensus resyncs a validator whose consensus stalled in normal mode:
+// the node is switched to NodeNotInSync and the sync is kicked, after which
+// doSync rejoins consensus with the validators of the synced chain
+func (node *Node) watchConsensus() {
+	ticker := time.NewTicker(consensusWatchdogInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		node.checkConsensus(now)
+	}
+}
+
+// checkConsensus switches the node to NodeNotInSync and kicks the sync if the
+// consensus stalled at now
+func (node *Node) checkConsensus(now time.Time) {
+	stalled, since := node.consensusStalled(now)
+	if !stalled {
+		return
+	}
+	utils.ModuleLogger(utils.ModuleNode).Warn().
+		Dur("sinceLastBlock", since).
+		Uint64("blockNum", node.Blockchain().CurrentBlock().NumberU64()).
+		Msg("[ConsensusWatchdog] consensus stalled, resyncing")
+
+	node.stateMutex.Lock()
+	node.State = NodeNotInSync
+	node.resyncRequested = true
+	node.stateMutex.Unlock()
+	select {
+	case node.Consensus.BlockNumLowChan <- struct{}{}:
+	case <-time.After(1 * time.Second):
+		// already syncing, the request is picked up by the next round
+	}
+}
+
+// consensusStalled returns whether no block was committed for
+// consensusStallPeriods block periods at now while consensus is in normal
+// mode, and the time since the last block
+func (node *Node) consensusStalled(now time.Time) (bool, time.Duration) {
+	if node.Consensus.Mode() != consensus.Normal {
+		return false, 0
+	}
+	period := node.Consensus.BlockPeriod
+	if period <= 0 {
+		return false, 0
+	}
+	last := time.Unix(node.Blockchain().CurrentHeader().Time().Int64(), 0)
+	since := now.Sub(last)
+	return since > consensusStallPeriods*period, since
+}
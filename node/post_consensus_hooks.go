@@ -0,0 +1,88 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// PostConsensusHook processes a block committed by consensus, after it is
+// inserted into the chain
+type PostConsensusHook func(*types.Block) error
+
+// postConsensusHook is a registered PostConsensusHook
+type postConsensusHook struct {
+	name     string
+	priority int
+	seq      int
+	hook     PostConsensusHook
+	timer    metrics.Timer
+}
+
+// postConsensusHooks is the ordered registry of the post-consensus hooks
+type postConsensusHooks struct {
+	mutex sync.RWMutex
+	hooks []*postConsensusHook
+}
+
+// RegisterPostConsensusHook registers the hook run after each block committed
+// by consensus is inserted. The hooks run one at a time, lower priorities
+// first and in registration order for equal priorities. A hook failing or
+// panicking does not keep the following ones from running.
+func (node *Node) RegisterPostConsensusHook(
+	name string, priority int, hook PostConsensusHook,
+) {
+	r := &node.postConsensusHooks
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// copy on write, the running hooks keep iterating over the old slice
+	hooks := append(append([]*postConsensusHook{}, r.hooks...), &postConsensusHook{
+		name:     name,
+		priority: priority,
+		seq:      len(r.hooks),
+		hook:     hook,
+		timer:    metrics.GetOrRegisterTimer("node/postconsensus/"+name, nil),
+	})
+	sort.SliceStable(hooks, func(i, j int) bool {
+		if hooks[i].priority != hooks[j].priority {
+			return hooks[i].priority < hooks[j].priority
+		}
+		return hooks[i].seq < hooks[j].seq
+	})
+	r.hooks = hooks
+}
+
+// runPostConsensusHooks runs the registered hooks on the committed block
+func (node *Node) runPostConsensusHooks(block *types.Block) {
+	r := &node.postConsensusHooks
+	r.mutex.RLock()
+	hooks := r.hooks
+	r.mutex.RUnlock()
+	for _, h := range hooks {
+		start := time.Now()
+		err := h.run(block)
+		h.timer.UpdateSince(start)
+		if err != nil {
+			utils.ModuleLogger(utils.ModuleNode).Warn().
+				Err(err).
+				Str("hook", h.name).
+				Uint64("blockNum", block.NumberU64()).
+				Msg("[PostConsensusHook] hook failed")
+		}
+	}
+}
+
+// run runs the hook, turning a panic into an error
+func (h *postConsensusHook) run(block *types.Block) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h.hook(block)
+}
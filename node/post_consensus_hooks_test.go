@@ -0,0 +1,35 @@
+package node
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestPostConsensusHooks(t *testing.T) {
+	node := &Node{}
+	ran := []string{}
+	hook := func(name string, err error) PostConsensusHook {
+		return func(*types.Block) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+	node.RegisterPostConsensusHook("late", 10, hook("late", nil))
+	node.RegisterPostConsensusHook("failing", 0, hook("failing", errors.New("failed")))
+	node.RegisterPostConsensusHook("panicking", 0, func(*types.Block) error {
+		ran = append(ran, "panicking")
+		panic("hook panicked")
+	})
+	node.RegisterPostConsensusHook("early", -1, hook("early", nil))
+
+	node.runPostConsensusHooks(types.NewBlockWithHeader(blockfactory.NewTestHeader()))
+
+	want := []string{"early", "failing", "panicking", "late"}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("hooks ran %v, want %v", ran, want)
+	}
+}
@@ -2,14 +2,17 @@ package node
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/hmy"
+	"github.com/harmony-one/harmony/hmy/gasprice"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/hmyapi"
 	"github.com/harmony-one/harmony/internal/hmyapi/apiv1"
@@ -19,8 +22,9 @@ import (
 )
 
 const (
-	rpcHTTPPortOffset = 500
-	rpcWSPortOffset   = 800
+	rpcHTTPPortOffset  = 500
+	rpcAdminPortOffset = 700
+	rpcWSPortOffset    = 800
 )
 
 var (
@@ -36,11 +40,50 @@ var (
 	wsModules        = []string{"hmy", "hmyv2", "net", "netv2", "web3"}
 	wsOrigins        = []string{"*"}
 	harmony          *hmy.Harmony
+
+	// admin HTTP RPC, served on the local endpoint to local callers only
+	adminListener     net.Listener
+	adminHandler      *rpc.Server
+	adminEndpoint     = ""
+	adminModules      = []string{"admin"}
+	adminVirtualHosts = []string{"localhost", "127.0.0.1"}
+	adminOrigins      = []string{"http://localhost", "http://127.0.0.1"}
 )
 
 // IsCurrentlyLeader exposes if node is currently the leader node
 func (node *Node) IsCurrentlyLeader() bool {
-	return node.Consensus.IsLeader()
+	return node.Consensus != nil && node.Consensus.IsLeader()
+}
+
+// LeaderStats returns the performance of the leaders of the consensus rounds
+// seen by this node
+func (node *Node) LeaderStats() []consensus.LeaderStats {
+	if node.Consensus == nil {
+		return nil
+	}
+	return node.Consensus.LeaderStats()
+}
+
+// SubscribeConsensusPhaseEvents subscribes the channel to the transitions of
+// the consensus of the node
+func (node *Node) SubscribeConsensusPhaseEvents(ch chan<- consensus.PhaseEvent) event.Subscription {
+	if node.Consensus == nil {
+		// no event ever sent without consensus
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			<-quit
+			return nil
+		})
+	}
+	return node.Consensus.SubscribePhaseEvents(ch)
+}
+
+// SuggestGasPrice returns the gas price suggested from the recent blocks of
+// the shard
+func (node *Node) SuggestGasPrice() *big.Int {
+	if node.gasPriceOracle == nil {
+		return new(big.Int).Set(gasprice.DefaultConfig.Default)
+	}
+	return node.gasPriceOracle.SuggestPrice()
 }
 
 // PendingCXReceipts returns node.pendingCXReceiptsProof
@@ -73,7 +116,7 @@ func (node *Node) ReportPlainErrorSink() types.TransactionErrorReports {
 func (node *Node) StartRPC(nodePort string) error {
 	// Gather all the possible APIs to surface
 	harmony, _ = hmy.New(
-		node, node.TxPool, node.CxPool, new(event.TypeMux), node.Consensus.ShardID,
+		node, node.TxPool, node.CxPool, new(event.TypeMux), node.NodeConfig.ShardID,
 	)
 
 	apis := node.APIs()
@@ -98,6 +141,11 @@ func (node *Node) StartRPC(nodePort string) error {
 		node.stopHTTP()
 		return err
 	}
+	adminEndpoint = fmt.Sprintf("127.0.0.1:%v", port+rpcAdminPortOffset)
+	if err := node.startAdmin(adminEndpoint, apis); err != nil {
+		node.stopHTTP()
+		return err
+	}
 
 	return nil
 }
@@ -114,7 +162,7 @@ func (node *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, c
 		return err
 	}
 
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Str("url", fmt.Sprintf("http://%s", endpoint)).
 		Str("cors", strings.Join(cors, ",")).
 		Str("vhosts", strings.Join(vhosts, ",")).
@@ -130,7 +178,7 @@ func (node *Node) stopHTTP() {
 	if httpListener != nil {
 		httpListener.Close()
 		httpListener = nil
-		utils.Logger().Info().Str("url", fmt.Sprintf("http://%s", httpEndpoint)).Msg("HTTP endpoint closed")
+		utils.ModuleLogger(utils.ModuleNode).Info().Str("url", fmt.Sprintf("http://%s", httpEndpoint)).Msg("HTTP endpoint closed")
 	}
 	if httpHandler != nil {
 		httpHandler.Stop()
@@ -138,6 +186,23 @@ func (node *Node) stopHTTP() {
 	}
 }
 
+// startAdmin initializes and starts the admin HTTP RPC endpoint, which only
+// accepts the requests of local hosts and origins whatever the public RPC
+func (node *Node) startAdmin(endpoint string, apis []rpc.API) error {
+	listener, handler, err := rpc.StartHTTPEndpoint(
+		endpoint, apis, adminModules, adminOrigins, adminVirtualHosts, httpTimeouts,
+	)
+	if err != nil {
+		return err
+	}
+	utils.ModuleLogger(utils.ModuleNode).Info().
+		Str("url", fmt.Sprintf("http://%s", endpoint)).
+		Msg("Admin HTTP endpoint opened")
+	adminListener = listener
+	adminHandler = handler
+	return nil
+}
+
 // startWS initializes and starts the websocket RPC endpoint.
 func (node *Node) startWS(
 	endpoint string, apis []rpc.API, modules []string, wsOrigins []string, exposeAll bool,
@@ -150,7 +215,7 @@ func (node *Node) startWS(
 	if err != nil {
 		return err
 	}
-	utils.Logger().Info().
+	utils.ModuleLogger(utils.ModuleNode).Info().
 		Str("url", fmt.Sprintf("ws://%s", listener.Addr())).
 		Msg("WebSocket endpoint opened")
 	return nil
@@ -161,6 +226,10 @@ func (node *Node) startWS(
 func (node *Node) APIs() []rpc.API {
 	// Gather all the possible APIs to surface
 	apis := hmyapi.GetAPIs(harmony.APIBackend)
+	var viewChanger apiv1.ViewChanger
+	if node.Consensus != nil {
+		viewChanger = node.Consensus
+	}
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -181,5 +250,11 @@ func (node *Node) APIs() []rpc.API {
 			Service:   apiv2.NewPublicNetAPI(node.host, harmony.APIBackend.NetVersion()),
 			Public:    true,
 		},
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   apiv1.NewPrivateAdminAPI(node, node.host, node.TxPool, viewChanger),
+			Public:    false,
+		},
 	}...)
 }
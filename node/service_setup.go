@@ -3,6 +3,7 @@ package node
 import (
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/api/service"
 	"github.com/harmony-one/harmony/api/service/blockproposal"
@@ -11,33 +12,33 @@ import (
 	"github.com/harmony-one/harmony/api/service/discovery"
 	"github.com/harmony-one/harmony/api/service/explorer"
 	"github.com/harmony-one/harmony/api/service/networkinfo"
+	"github.com/harmony-one/harmony/api/service/walletwatch"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
 )
 
 func (node *Node) setupForValidator() {
 	nodeConfig, chanPeer, _ := node.initNodeConfiguration()
-	// Register peer discovery service
-	node.serviceManager.RegisterService(
-		service.PeerDiscovery,
-		discovery.New(node.host, nodeConfig, chanPeer, node.AddBeaconPeer),
-	)
-	// Register networkinfo service. "0" is the beacon shard ID
-	node.serviceManager.RegisterService(
-		service.NetworkInfo,
-		networkinfo.MustNew(
-			node.host, node.NodeConfig.GetShardGroupID(), chanPeer, nil, node.networkInfoDHTPath(),
-		),
-	)
-	// Register consensus service.
-	node.serviceManager.RegisterService(
-		service.Consensus,
-		consensus.New(node.BlockChannel, node.Consensus, node.startConsensus),
-	)
-	// Register new block service.
+	// A validator behind sentries is reached through them only
+	if !node.host.IsSentryMode() {
+		// Register peer discovery service
+		node.serviceManager.RegisterService(
+			service.PeerDiscovery,
+			discovery.New(node.host, nodeConfig, chanPeer, node.AddBeaconPeer),
+		)
+		// Register networkinfo service. "0" is the beacon shard ID
+		node.serviceManager.RegisterService(
+			service.NetworkInfo,
+			node.newNetworkInfo(chanPeer),
+		)
+	}
+	node.setupConsensusServices()
+	node.setupBeaconCommitSigs()
+	// Register wallet watch service.
 	node.serviceManager.RegisterService(
-		service.BlockProposal,
-		blockproposal.New(node.Consensus.ReadySignal, node.WaitForConsensusReadyV2),
+		service.WalletWatch,
+		walletwatch.New(node.Beaconchain(), node.watchedAddresses),
 	)
 
 	if node.NodeConfig.GetNetworkType() != nodeconfig.Mainnet {
@@ -51,6 +52,21 @@ func (node *Node) setupForValidator() {
 	}
 }
 
+// setupConsensusServices registers the services running the consensus and
+// proposing the blocks.
+func (node *Node) setupConsensusServices() {
+	// Register consensus service.
+	node.serviceManager.RegisterService(
+		service.Consensus,
+		consensus.New(node.BlockChannel, node.Consensus, node.startConsensus),
+	)
+	// Register new block service.
+	node.serviceManager.RegisterService(
+		service.BlockProposal,
+		blockproposal.New(node.Consensus.ReadySignal, node.WaitForConsensusReadyV2),
+	)
+}
+
 func (node *Node) setupForExplorerNode() {
 	nodeConfig, chanPeer, _ := node.initNodeConfiguration()
 
@@ -61,8 +77,7 @@ func (node *Node) setupForExplorerNode() {
 	// Register networkinfo service.
 	node.serviceManager.RegisterService(
 		service.NetworkInfo,
-		networkinfo.MustNew(
-			node.host, node.NodeConfig.GetShardGroupID(), chanPeer, nil, node.networkInfoDHTPath()),
+		node.newNetworkInfo(chanPeer),
 	)
 	// Register explorer service.
 	node.serviceManager.RegisterService(
@@ -70,6 +85,45 @@ func (node *Node) setupForExplorerNode() {
 	)
 }
 
+// setupForRPCNode registers the services finding the peers to sync from, the
+// RPC node running no consensus.
+func (node *Node) setupForRPCNode() {
+	nodeConfig, chanPeer, _ := node.initNodeConfiguration()
+
+	// Register peer discovery service.
+	node.serviceManager.RegisterService(
+		service.PeerDiscovery, discovery.New(node.host, nodeConfig, chanPeer, nil),
+	)
+	// Register networkinfo service.
+	node.serviceManager.RegisterService(
+		service.NetworkInfo,
+		node.newNetworkInfo(chanPeer),
+	)
+}
+
+// watchedAddresses returns the addresses of the bls keys run by the node in
+// the current epoch.
+func (node *Node) watchedAddresses() []common.Address {
+	addrs := []common.Address{}
+	for _, addr := range node.GetAddresses(node.Blockchain().CurrentHeader().Epoch()) {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// newNetworkInfo creates the networkinfo service of the node's shard.
+func (node *Node) newNetworkInfo(chanPeer chan p2p.Peer) *networkinfo.Service {
+	networkInfo := networkinfo.MustNew(
+		node.host, node.NodeConfig.GetShardGroupID(), chanPeer, nil, node.networkInfoDHTPath(),
+	)
+	networkInfo.DNSSeed = node.NodeConfig.DNSSeed
+	networkInfo.ShardID = node.NodeConfig.ShardID
+	networkInfo.CurrentEpoch = func() uint64 {
+		return node.Blockchain().CurrentHeader().Epoch().Uint64()
+	}
+	return networkInfo
+}
+
 // ServiceManagerSetup setups service store.
 func (node *Node) ServiceManagerSetup() {
 	node.serviceManager = &service.Manager{}
@@ -79,14 +133,36 @@ func (node *Node) ServiceManagerSetup() {
 		node.setupForValidator()
 	case nodeconfig.ExplorerNode:
 		node.setupForExplorerNode()
+	case nodeconfig.RPCNode:
+		node.setupForRPCNode()
+	}
+	node.setupStorageGuard()
+	node.setupAbsentees()
+	node.serviceManager.SetupServiceMessageChan(node.serviceMessageChan)
+	node.advertiseMetadata()
+	node.acceptDirectTransactions()
+	node.serveProposals()
+}
+
+// ConsensusServiceManagerSetup setups the service store with the consensus
+// services only, for the nodes of an in-process localnet which discover no
+// peers.
+func (node *Node) ConsensusServiceManagerSetup() error {
+	node.serviceManager = &service.Manager{}
+	node.serviceMessageChan = make(map[service.Type]chan *msg_pb.Message)
+	// join the topics of the node
+	if _, _, err := node.initNodeConfiguration(); err != nil {
+		return err
 	}
+	node.setupConsensusServices()
 	node.serviceManager.SetupServiceMessageChan(node.serviceMessageChan)
+	return nil
 }
 
 // RunServices runs registered services.
 func (node *Node) RunServices() {
 	if node.serviceManager == nil {
-		utils.Logger().Info().Msg("Service manager is not set up yet.")
+		utils.ModuleLogger(utils.ModuleNode).Info().Msg("Service manager is not set up yet.")
 		return
 	}
 	node.serviceManager.RunServices()
@@ -95,7 +171,7 @@ func (node *Node) RunServices() {
 // StopServices runs registered services.
 func (node *Node) StopServices() {
 	if node.serviceManager == nil {
-		utils.Logger().Info().Msg("Service manager is not set up yet.")
+		utils.ModuleLogger(utils.ModuleNode).Info().Msg("Service manager is not set up yet.")
 		return
 	}
 	node.serviceManager.StopServicesByRole([]service.Type{})
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/staking/slash"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// seenSlashesCacheSize is the number of double-signing events remembered
+	// as already gossiped
+	seenSlashesCacheSize = 1024
+	// slashBroadcastDelay is how long the witness reports are aggregated
+	// before being broadcast
+	slashBroadcastDelay = 2 * time.Second
+)
+
+// slashGossip remembers the double-signing events already gossiped, keyed by
+// slash.Record.EventKey, and aggregates the reports waiting to be broadcast
+type slashGossip struct {
+	mutex   sync.Mutex
+	seen    *lru.Cache
+	pending slash.Records
+}
+
+func newSlashGossip() *slashGossip {
+	seen, _ := lru.New(seenSlashesCacheSize)
+	return &slashGossip{seen: seen}
+}
+
+// unseen returns the records of the events not gossiped yet, one per event
+func (g *slashGossip) unseen(records slash.Records) slash.Records {
+	fresh := slash.Records{}
+	for _, record := range records.Dedup() {
+		if !g.seen.Contains(record.EventKey()) {
+			fresh = append(fresh, record)
+		}
+	}
+	return fresh
+}
+
+// markSeen marks the events of the records as gossiped
+func (g *slashGossip) markSeen(records slash.Records) {
+	for _, record := range records {
+		g.seen.Add(record.EventKey(), struct{}{})
+	}
+}
+
+// isSeen returns whether the event of the record was already gossiped
+func (g *slashGossip) isSeen(key common.Hash) bool {
+	return g.seen.Contains(key)
+}
+
+// queueSlashBroadcast queues the double-sign record reported by consensus to
+// the beacon chain; the reports received within slashBroadcastDelay are
+// broadcast together, once per event
+func (node *Node) queueSlashBroadcast(record slash.Record) {
+	g := node.slashGossip
+	if g.isSeen(record.EventKey()) {
+		utils.ModuleLogger(utils.ModuleNode).Debug().
+			Str("offender", record.Evidence.Offender.Hex()).
+			Msg("double sign already gossiped, not rebroadcasting")
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.pending = append(g.pending, record)
+	if len(g.pending) == 1 {
+		time.AfterFunc(slashBroadcastDelay, node.flushSlashBroadcast)
+	}
+}
+
+// flushSlashBroadcast broadcasts the queued double-sign records of the events
+// not gossiped yet
+func (node *Node) flushSlashBroadcast() {
+	g := node.slashGossip
+	g.mutex.Lock()
+	records := g.unseen(g.pending)
+	g.markSeen(records)
+	g.pending = nil
+	g.mutex.Unlock()
+	if len(records) > 0 {
+		node.BroadcastSlash(records)
+	}
+}
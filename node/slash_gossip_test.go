@@ -0,0 +1,64 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/staking/slash"
+)
+
+// testSlashRecord returns a report of the given witness of the double signing
+// of the offender on the given blocks
+func testSlashRecord(offender, reporter byte, first, second byte) slash.Record {
+	record := slash.Record{Reporter: common.Address{reporter}}
+	record.Evidence.Offender = common.Address{offender}
+	record.Evidence.FirstVote.BlockHeaderHash = common.Hash{first}
+	record.Evidence.SecondVote.BlockHeaderHash = common.Hash{second}
+	return record
+}
+
+func TestSlashGossipUnseen(t *testing.T) {
+	g := newSlashGossip()
+	event := testSlashRecord(1, 1, 1, 2)
+	// the same event reported by another witness, votes swapped
+	sameEvent := testSlashRecord(1, 2, 2, 1)
+	other := testSlashRecord(2, 1, 1, 2)
+
+	fresh := g.unseen(slash.Records{event, sameEvent, other})
+	if len(fresh) != 2 || fresh[0].Reporter != event.Reporter || fresh[1].Reporter != other.Reporter {
+		t.Fatalf("expected one record per event, got %v", fresh)
+	}
+	// the events are not seen until marked
+	if g.isSeen(event.EventKey()) || len(g.unseen(slash.Records{event})) != 1 {
+		t.Error("expected the event unseen before being marked")
+	}
+
+	g.markSeen(slash.Records{event})
+	if !g.isSeen(sameEvent.EventKey()) {
+		t.Error("expected the event seen whoever reports it")
+	}
+	if fresh := g.unseen(slash.Records{sameEvent, other}); len(fresh) != 1 || fresh[0].Reporter != other.Reporter {
+		t.Errorf("expected only the other event unseen, got %v", fresh)
+	}
+}
+
+func TestPendingEvents(t *testing.T) {
+	verified := testSlashRecord(1, 1, 1, 2)
+	forged := testSlashRecord(2, 1, 1, 2)
+	// the event already pending from the report of another witness
+	reported := testSlashRecord(3, 1, 1, 2)
+	pending := slash.Records{verified, testSlashRecord(3, 2, 2, 1)}
+
+	marked := pendingEvents(slash.Records{verified, forged, reported}, pending)
+	if len(marked) != 2 || marked[0].EventKey() != verified.EventKey() ||
+		marked[1].EventKey() != reported.EventKey() {
+		t.Errorf("expected the pending events only, got %v", marked)
+	}
+
+	// a forged report does not hide the genuine report of its event
+	g := newSlashGossip()
+	g.markSeen(marked)
+	if g.isSeen(forged.EventKey()) {
+		t.Error("expected the event of the forged report unseen")
+	}
+}
@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// parallel tells whether the transactions of the block proposed are executed
+// in parallel. The transactions only touch the state of their accounts from
+// the S3 epoch, without an intermediate root in their receipts, and from the
+// staking epoch, without crediting the coinbase with their fee.
+func (w *Worker) parallel() bool {
+	epoch := w.current.header.Epoch()
+	return w.parallelism > 1 && w.config.IsS3(epoch) && w.config.IsStaking(epoch)
+}
+
+// executedTx is the outcome of a transaction executed on a fork of the state
+type executedTx struct {
+	tx      *types.Transaction
+	state   *state.DB
+	receipt *types.Receipt
+	cx      *types.CXReceipt
+	err     error
+}
+
+// commitTransactionsParallel commits the transactions in batches of one
+// transaction per account, executed at once on forks of the state. The
+// outcomes are merged in order as long as the transactions did not read the
+// state written by the ones merged before them, after which the rest of the
+// batch is executed serially. The block is the same as the one of a serial
+// execution. It returns when out of transactions or gas.
+func (w *Worker) commitTransactionsParallel(
+	txs *types.TransactionsByPriceAndNonce, coinbase common.Address,
+) {
+	merged, serial := 0, 0
+	for {
+		batch := w.nextBatch(txs)
+		if len(batch) == 0 {
+			break
+		}
+		executed := w.executeBatch(batch, coinbase)
+		written := state.NewAccessSet()
+		for i, outcome := range executed {
+			if !w.mergeExecuted(outcome, written) {
+				serial += w.commitBatch(txs, batch[i:], coinbase)
+				break
+			}
+			merged++
+		}
+	}
+	utils.Logger().Debug().
+		Int("merged", merged).
+		Int("serial", serial).
+		Msg("Transactions executed in parallel")
+}
+
+// commitBatch commits the transactions left of a batch serially, as the
+// serial loop does, returning how many were executed. The batch being shifted
+// past, the transactions left of an account skipped are dropped.
+func (w *Worker) commitBatch(
+	txs *types.TransactionsByPriceAndNonce, batch []*types.Transaction,
+	coinbase common.Address,
+) int {
+	for i, tx := range batch {
+		if w.current.gasPool.Gas() < params.TxGas {
+			return i
+		}
+		from, _ := types.Sender(w.current.signer, tx)
+		w.current.state.Prepare(tx.Hash(), common.Hash{}, len(w.current.txs))
+		_, err := w.commitTransaction(tx, coinbase)
+		if skipsAccount(tx, from, err) {
+			txs.Drop(from)
+		}
+	}
+	return len(batch)
+}
+
+// nextBatch returns the next transactions to commit, of distinct accounts, up
+// to the parallelism of the worker, shifting past them. The transactions not
+// committable are rejected as in a serial execution.
+func (w *Worker) nextBatch(txs *types.TransactionsByPriceAndNonce) []*types.Transaction {
+	batch := []*types.Transaction{}
+	senders := map[common.Address]struct{}{}
+	size := common.StorageSize(0)
+	for len(batch) < w.parallelism {
+		if w.current.gasPool.Gas() < params.TxGas {
+			break
+		}
+		tx := txs.Peek()
+		if tx == nil {
+			break
+		}
+		from, _ := types.Sender(w.current.signer, tx)
+		if _, ok := senders[from]; ok {
+			// the next transaction of an account depends on the previous one
+			break
+		}
+		if tx.Protected() && !w.config.IsEIP155(w.current.header.Epoch()) {
+			w.current.rejected[tx.Hash()] = errReplayProtected
+			txs.Pop()
+			continue
+		}
+		if !w.fits(tx.Size()) {
+			w.current.rejected[tx.Hash()] = errBlockSizeReached
+			txs.Pop()
+			continue
+		}
+		if !w.fits(size + tx.Size()) {
+			// whether it fits depends on the transactions of the batch committed
+			break
+		}
+		if tx.ShardID() != w.chain.ShardID() {
+			w.current.rejected[tx.Hash()] = errWrongShard
+			txs.Shift()
+			continue
+		}
+		senders[from] = struct{}{}
+		size += tx.Size()
+		batch = append(batch, tx)
+		txs.Shift()
+	}
+	return batch
+}
+
+// executeBatch executes each transaction of the batch on its own fork of the
+// current state, tracking the state it accesses
+func (w *Worker) executeBatch(
+	batch []*types.Transaction, coinbase common.Address,
+) []*executedTx {
+	executed := make([]*executedTx, len(batch))
+	gas := w.current.gasPool.Gas()
+	wg := sync.WaitGroup{}
+	for i, tx := range batch {
+		outcome := &executedTx{tx: tx, state: w.current.state.Fork()}
+		outcome.state.TrackAccess()
+		executed[i] = outcome
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcome.state.Prepare(outcome.tx.Hash(), common.Hash{}, 0)
+			gasUsed := uint64(0)
+			outcome.receipt, outcome.cx, _, outcome.err = core.ApplyTransaction(
+				w.config, w.chain, &coinbase, new(core.GasPool).AddGas(gas),
+				outcome.state, w.current.header, outcome.tx, &gasUsed, vm.Config{},
+			)
+		}()
+	}
+	wg.Wait()
+	return executed
+}
+
+// mergeExecuted applies the outcome of a transaction executed in parallel to
+// the current state, unless it would differ from a serial execution, adding
+// the state the transaction wrote to the written one
+func (w *Worker) mergeExecuted(outcome *executedTx, written *state.AccessSet) bool {
+	if outcome.err != nil || outcome.receipt == nil {
+		// executed again serially to reject it the same way
+		return false
+	}
+	reads, writes := outcome.state.AccessedState()
+	if written.Intersects(reads) {
+		return false
+	}
+	// the gas bought by a serial execution is the gas limit of the transaction
+	if w.current.gasPool.Gas() < outcome.tx.Gas() {
+		return false
+	}
+	txHash := outcome.tx.Hash()
+	w.current.state.Prepare(txHash, common.Hash{}, len(w.current.txs))
+	if err := w.current.state.ApplyWrites(outcome.state); err != nil {
+		return false
+	}
+	w.current.state.Finalise(true)
+	w.current.gasPool.SubGas(outcome.receipt.GasUsed)
+	written.Merge(writes)
+
+	for _, log := range outcome.state.GetLogs(txHash) {
+		cpy := *log
+		w.current.state.AddLog(&cpy)
+	}
+	receipt := outcome.receipt
+	gasUsed := w.current.header.GasUsed() + receipt.GasUsed
+	w.current.header.SetGasUsed(gasUsed)
+	receipt.CumulativeGasUsed = gasUsed
+	if w.config.IsReceiptLog(w.current.header.Epoch()) {
+		receipt.Logs = w.current.state.GetLogs(txHash)
+	}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+	w.current.txs = append(w.current.txs, outcome.tx)
+	w.current.receipts = append(w.current.receipts, receipt)
+	w.current.size += uint64(outcome.tx.Size())
+	if outcome.cx != nil {
+		w.current.outcxs = append(w.current.outcxs, outcome.cx)
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	staking "github.com/harmony-one/harmony/staking/types"
+	"github.com/pkg/errors"
+)
+
+// ReplayedTx is the outcome of a transaction in a replayed proposal
+type ReplayedTx struct {
+	Hash     common.Hash `json:"hash"`
+	Staking  bool        `json:"staking"`
+	Included bool        `json:"included"`         // in the historical block
+	Selected bool        `json:"selected"`         // in the replayed proposal
+	Reason   string      `json:"reason,omitempty"` // why it was not selected
+}
+
+// ReplayedReceipts is the outcome of the incoming receipts of a source shard
+// block in a replayed proposal
+type ReplayedReceipts struct {
+	ShardID   uint32      `json:"shard-id"`
+	BlockNum  uint64      `json:"block-num"`
+	BlockHash common.Hash `json:"block-hash"`
+	Receipts  int         `json:"receipts"`
+	Valid     bool        `json:"valid"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// ReplayedCrossLink is the outcome of a crosslink in a replayed proposal
+type ReplayedCrossLink struct {
+	ShardID   uint32      `json:"shard-id"`
+	BlockNum  uint64      `json:"block-num"`
+	BlockHash common.Hash `json:"block-hash"`
+	Valid     bool        `json:"valid"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// ProposalReplay is the report of a block proposed anew on the state of its
+// parent, and of the differences with the historical block
+type ProposalReplay struct {
+	BlockNum        uint64               `json:"block-num"`
+	BlockHash       common.Hash          `json:"block-hash"`
+	GasLimit        uint64               `json:"gas-limit"`
+	GasUsed         uint64               `json:"gas-used"`
+	ReplayedGasUsed uint64               `json:"replayed-gas-used"`
+	Transactions    []*ReplayedTx        `json:"transactions"`
+	Receipts        []*ReplayedReceipts  `json:"incoming-receipts"`
+	CrossLinks      []*ReplayedCrossLink `json:"crosslinks"`
+	Differences     []string             `json:"differences"`
+}
+
+// NewReplay returns a worker proposing anew the given block on the state of
+// its parent, with the gas limit, time and coinbase the block was proposed
+// with. The state of the parent must still be available.
+func NewReplay(chain *core.BlockChain, blk *types.Block) (*Worker, error) {
+	parent := chain.GetBlockByHash(blk.ParentHash())
+	if parent == nil {
+		return nil, errors.Errorf("parent of block %d not found", blk.NumberU64())
+	}
+	config := chain.Config()
+	w := &Worker{
+		config:  config,
+		factory: blockfactory.NewFactory(config),
+		chain:   chain,
+		engine:  chain.Engine(),
+	}
+	h := blk.Header()
+	header := w.factory.NewHeader(h.Epoch()).With().
+		ParentHash(parent.Hash()).
+		Number(h.Number()).
+		GasLimit(h.GasLimit()).
+		Time(h.Time()).
+		ShardID(h.ShardID()).
+		Coinbase(h.Coinbase()).
+		Header()
+	if err := w.makeCurrent(parent, header); err != nil {
+		return nil, errors.Wrapf(err, "state of block %d not available", parent.NumberU64())
+	}
+	return w, nil
+}
+
+// CommittedTransactions returns the transactions committed to the proposed block
+func (w *Worker) CommittedTransactions() (types.Transactions, staking.StakingTransactions) {
+	return w.current.txs, w.current.stakingTxs
+}
+
+// Rejected returns why the given transaction tried was not committed to the
+// proposed block, nil if it was not tried or was committed
+func (w *Worker) Rejected(hash common.Hash) error {
+	return w.current.rejected[hash]
+}
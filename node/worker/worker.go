@@ -19,6 +19,7 @@ import (
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
 	common2 "github.com/harmony-one/harmony/internal/common"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	"github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/shard"
@@ -39,18 +40,66 @@ type environment struct {
 	outcxs     []*types.CXReceipt       // cross shard transaction receipts (source shard)
 	incxs      []*types.CXReceiptsProof // cross shard receipts and its proof (desitinatin shard)
 	slashes    slash.Records
+	rejected   map[common.Hash]error // why the transactions tried were not committed
+	limits     shardingconfig.BlockLimits
+	size       uint64 // encoded size of the transactions committed
 }
 
 // Worker is the main object which takes care of submitting new work to consensus engine
 // and gathering the sealing result.
 type Worker struct {
-	config   *params.ChainConfig
-	factory  blockfactory.Factory
-	chain    *core.BlockChain
-	current  *environment // An environment for current running cycle.
-	engine   consensus_engine.Engine
-	gasFloor uint64
-	gasCeil  uint64
+	config  *params.ChainConfig
+	factory blockfactory.Factory
+	chain   *core.BlockChain
+	current *environment // An environment for current running cycle.
+	engine  consensus_engine.Engine
+	// blockLimits overrides the block limits of the sharding schedule, its
+	// zero fields keeping the ones of the schedule
+	blockLimits shardingconfig.BlockLimits
+	// parallelism is the number of transactions executed at once when
+	// proposing, 1 or less for a serial execution
+	parallelism int
+}
+
+// SetBlockLimits overrides the block limits of the sharding schedule with the
+// non zero fields of the given ones, from the next block proposed
+func (w *Worker) SetBlockLimits(limits shardingconfig.BlockLimits) {
+	w.blockLimits = limits
+}
+
+// SetParallelism sets the number of transactions executed at once when
+// proposing, from the next block proposed, 1 or less for a serial execution
+func (w *Worker) SetParallelism(n int) {
+	w.parallelism = n
+}
+
+// BlockLimits returns the limits of the blocks proposed in the given epoch
+func (w *Worker) BlockLimits(epoch *big.Int) shardingconfig.BlockLimits {
+	limits := shard.Schedule.InstanceForEpoch(epoch).BlockLimits(w.chain.ShardID())
+	merged := limits
+	if w.blockLimits.GasFloor > 0 {
+		merged.GasFloor = w.blockLimits.GasFloor
+	}
+	if w.blockLimits.GasCeil > 0 {
+		merged.GasCeil = w.blockLimits.GasCeil
+	}
+	if w.blockLimits.MaxBytes > 0 {
+		merged.MaxBytes = w.blockLimits.MaxBytes
+	}
+	if err := merged.Validate(); err != nil {
+		utils.Logger().Warn().Err(err).
+			Interface("override", w.blockLimits).
+			Msg("Invalid block limits override, using the sharding schedule's")
+		return limits
+	}
+	return merged
+}
+
+// fits returns whether a transaction of the given size fits in the proposed
+// block, with room left for the rest of the block
+func (w *Worker) fits(size common.StorageSize) bool {
+	max := w.current.limits.TxBytes()
+	return max == 0 || w.current.size+uint64(size) <= max
 }
 
 // CommitTransactions commits transactions for new block.
@@ -65,6 +114,10 @@ func (w *Worker) CommitTransactions(
 
 	txs := types.NewTransactionsByPriceAndNonce(w.current.signer, pendingNormal)
 	// NORMAL
+	if w.parallel() {
+		// the serial loop commits what the parallel execution left
+		w.commitTransactionsParallel(txs, coinbase)
+	}
 	for {
 		// If we don't have enough gas for any further transactions then we're done
 		if w.current.gasPool.Gas() < params.TxGas {
@@ -84,6 +137,15 @@ func (w *Worker) CommitTransactions(
 		// phase, start ignoring the sender until we do.
 		if tx.Protected() && !w.config.IsEIP155(w.current.header.Epoch()) {
 			utils.Logger().Info().Str("hash", tx.Hash().Hex()).Str("eip155Epoch", w.config.EIP155Epoch.String()).Msg("Ignoring reply protected transaction")
+			w.current.rejected[tx.Hash()] = errReplayProtected
+			txs.Pop()
+			continue
+		}
+		if !w.fits(tx.Size()) {
+			// Pop the transaction too large for the remaining space, a smaller one
+			// of another account may still fit
+			utils.Logger().Info().Str("hash", tx.Hash().Hex()).Msg("Block size limit reached for transaction")
+			w.current.rejected[tx.Hash()] = errBlockSizeReached
 			txs.Pop()
 			continue
 		}
@@ -91,37 +153,17 @@ func (w *Worker) CommitTransactions(
 		w.current.state.Prepare(tx.Hash(), common.Hash{}, len(w.current.txs))
 
 		if tx.ShardID() != w.chain.ShardID() {
+			w.current.rejected[tx.Hash()] = errWrongShard
 			txs.Shift()
 			continue
 		}
 
 		_, err := w.commitTransaction(tx, coinbase)
-
-		sender, _ := common2.AddressToBech32(from)
-		switch err {
-		case core.ErrGasLimitReached:
-			// Pop the current out-of-gas transaction without shifting in the next from the account
-			utils.Logger().Info().Str("sender", sender).Msg("Gas limit exceeded for current block")
-			txs.Pop()
-
-		case core.ErrNonceTooLow:
-			// New head notification data race between the transaction pool and miner, shift
-			utils.Logger().Info().Str("sender", sender).Uint64("nonce", tx.Nonce()).Msg("Skipping transaction with low nonce")
-			txs.Shift()
-
-		case core.ErrNonceTooHigh:
-			// Reorg notification data race between the transaction pool and miner, skip account =
-			utils.Logger().Info().Str("sender", sender).Uint64("nonce", tx.Nonce()).Msg("Skipping account with high nonce")
+		if skipsAccount(tx, from, err) {
+			// Pop the transaction without shifting in the next from the account
 			txs.Pop()
-
-		case nil:
-			// Everything ok, collect the logs and shift in the next transaction from the same account
-			txs.Shift()
-
-		default:
-			// Strange error, discard the transaction and get the next in line (note, the
-			// nonce-too-high clause will prevent us from executing in vain).
-			utils.Logger().Info().Str("hash", tx.Hash().Hex()).AnErr("err", err).Msg("Transaction failed, account skipped")
+		} else {
+			// Shift in the next transaction from the same account
 			txs.Shift()
 		}
 	}
@@ -141,6 +183,11 @@ func (w *Worker) CommitTransactions(
 				txs.Pop()
 				continue
 			}
+			if !w.fits(tx.Size()) {
+				utils.Logger().Info().Str("hash", tx.Hash().Hex()).Msg("Block size limit reached for staking transaction")
+				w.current.rejected[tx.Hash()] = errBlockSizeReached
+				continue
+			}
 
 			// Start executing the transaction
 			w.current.state.Prepare(tx.Hash(), common.Hash{}, len(w.current.txs)+len(w.current.stakingTxs))
@@ -164,10 +211,44 @@ func (w *Worker) CommitTransactions(
 		Int("newStakingTxns", len(w.current.stakingTxs)).
 		Uint64("blockGasLimit", w.current.header.GasLimit()).
 		Uint64("blockGasUsed", w.current.header.GasUsed()).
+		Uint64("blockMaxBytes", w.current.limits.MaxBytes).
+		Uint64("txnsBytes", w.current.size).
 		Msg("Block gas limit and usage info")
 	return nil
 }
 
+// skipsAccount logs the outcome of committing the transaction of the sender,
+// returning whether the transactions left of the sender are to be skipped
+func skipsAccount(tx *types.Transaction, from common.Address, err error) bool {
+	sender, _ := common2.AddressToBech32(from)
+	switch err {
+	case core.ErrGasLimitReached:
+		// Skip the current out-of-gas transaction without the next from the account
+		utils.Logger().Info().Str("sender", sender).Msg("Gas limit exceeded for current block")
+		return true
+
+	case core.ErrNonceTooLow:
+		// New head notification data race between the transaction pool and miner, shift
+		utils.Logger().Info().Str("sender", sender).Uint64("nonce", tx.Nonce()).Msg("Skipping transaction with low nonce")
+		return false
+
+	case core.ErrNonceTooHigh:
+		// Reorg notification data race between the transaction pool and miner, skip account =
+		utils.Logger().Info().Str("sender", sender).Uint64("nonce", tx.Nonce()).Msg("Skipping account with high nonce")
+		return true
+
+	case nil:
+		// Everything ok, collect the logs and shift in the next transaction from the same account
+		return false
+
+	default:
+		// Strange error, discard the transaction and get the next in line (note, the
+		// nonce-too-high clause will prevent us from executing in vain).
+		utils.Logger().Info().Str("hash", tx.Hash().Hex()).AnErr("err", err).Msg("Transaction failed, account skipped")
+		return false
+	}
+}
+
 func (w *Worker) commitStakingTransaction(
 	tx *staking.StakingTransaction, coinbase common.Address,
 ) ([]*types.Log, error) {
@@ -183,6 +264,7 @@ func (w *Worker) commitStakingTransaction(
 		utils.Logger().Error().
 			Err(err).Interface("stkTxn", tx).
 			Msg("Staking transaction failed commitment")
+		w.current.rejected[tx.Hash()] = err
 		return nil, err
 	}
 	if receipt == nil {
@@ -191,11 +273,17 @@ func (w *Worker) commitStakingTransaction(
 
 	w.current.stakingTxs = append(w.current.stakingTxs, tx)
 	w.current.receipts = append(w.current.receipts, receipt)
+	w.current.size += uint64(tx.Size())
 	return receipt.Logs, nil
 }
 
 var (
-	errNilReceipt = errors.New("nil receipt")
+	errNilReceipt      = errors.New("nil receipt")
+	errReplayProtected = errors.New("replay protected transaction before EIP155")
+	errWrongShard      = errors.New("transaction of another shard")
+	// errBlockSizeReached is the reason of the transactions not committed
+	// for exceeding the space left in the block
+	errBlockSizeReached = errors.New("block size limit reached")
 )
 
 func (w *Worker) commitTransaction(
@@ -220,6 +308,7 @@ func (w *Worker) commitTransaction(
 		utils.Logger().Error().
 			Err(err).Interface("txn", tx).
 			Msg("Transaction failed commitment")
+		w.current.rejected[tx.Hash()] = err
 		return nil, errNilReceipt
 	}
 	if receipt == nil {
@@ -228,6 +317,7 @@ func (w *Worker) commitTransaction(
 	}
 	w.current.txs = append(w.current.txs, tx)
 	w.current.receipts = append(w.current.receipts, receipt)
+	w.current.size += uint64(tx.Size())
 	if cx != nil {
 		w.current.outcxs = append(w.current.outcxs, cx)
 	}
@@ -267,10 +357,11 @@ func (w *Worker) UpdateCurrent() error {
 	timestamp := time.Now().Unix()
 
 	epoch := w.GetNewEpoch()
+	limits := w.BlockLimits(epoch)
 	header := w.factory.NewHeader(epoch).With().
 		ParentHash(parent.Hash()).
 		Number(num.Add(num, common.Big1)).
-		GasLimit(core.CalcGasLimit(parent, w.gasFloor, w.gasCeil)).
+		GasLimit(core.CalcGasLimit(parent, limits.GasFloor, limits.GasCeil)).
 		Time(big.NewInt(timestamp)).
 		ShardID(w.chain.ShardID()).
 		Header()
@@ -289,9 +380,11 @@ func (w *Worker) makeCurrent(parent *types.Block, header *block.Header) error {
 		return err
 	}
 	env := &environment{
-		signer: types.NewEIP155Signer(w.config.ChainID),
-		state:  state,
-		header: header,
+		signer:   types.NewEIP155Signer(w.config.ChainID),
+		state:    state,
+		header:   header,
+		rejected: map[common.Hash]error{},
+		limits:   w.BlockLimits(header.Epoch()),
 	}
 
 	w.current = env
@@ -499,6 +592,11 @@ func (w *Worker) FinalizeNewBlock(
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot finalize block")
 	}
+	if max := w.current.limits.MaxBytes; max > 0 && uint64(block.Size()) > max {
+		return nil, errors.Errorf(
+			"block of %d bytes exceeds the limit of %d bytes", uint64(block.Size()), max,
+		)
+	}
 
 	return block, nil
 }
@@ -513,18 +611,17 @@ func New(
 		chain:   chain,
 		engine:  engine,
 	}
-	worker.gasFloor = 80000000
-	worker.gasCeil = 120000000
 
 	parent := worker.chain.CurrentBlock()
 	num := parent.Number()
 	timestamp := time.Now().Unix()
 
 	epoch := worker.GetNewEpoch()
+	limits := worker.BlockLimits(epoch)
 	header := worker.factory.NewHeader(epoch).With().
 		ParentHash(parent.Hash()).
 		Number(num.Add(num, common.Big1)).
-		GasLimit(core.CalcGasLimit(parent, worker.gasFloor, worker.gasCeil)).
+		GasLimit(core.CalcGasLimit(parent, limits.GasFloor, limits.GasCeil)).
 		Time(big.NewInt(timestamp)).
 		ShardID(worker.chain.ShardID()).
 		Header()
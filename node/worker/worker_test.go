@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"crypto/ecdsa"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -92,3 +93,144 @@ func TestCommitTransactions(t *testing.T) {
 		t.Error("Transaction is not committed")
 	}
 }
+
+func TestCommitTransactionsSizeHeadroom(t *testing.T) {
+	var (
+		database = ethdb.NewMemDatabase()
+		gspec    = core.Genesis{
+			Config:  chainConfig,
+			Factory: blockFactory,
+			Alloc:   core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			ShardID: 0,
+		}
+	)
+	gspec.MustCommit(database)
+	chain, _ := core.NewBlockChain(database, nil, gspec.Config, chain2.Engine, vm.Config{}, nil)
+	worker := New(params.TestChainConfig, chain, chain2.Engine)
+	worker.current.limits.MaxBytes = 64 * 1024
+
+	tx, _ := types.SignTx(types.NewTransaction(0, testBankAddress, uint32(0), big.NewInt(1), params.TxGas, nil, nil), types.HomesteadSigner{}, testBankKey)
+	// the transaction fits in the block, but not in the room of the transactions
+	worker.current.size = worker.current.limits.TxBytes() - uint64(tx.Size()) + 1
+	txs := map[common.Address]types.Transactions{testBankAddress: {tx}}
+	if err := worker.CommitTransactions(txs, nil, testBankAddress); err != nil {
+		t.Fatal(err)
+	}
+	if len(worker.current.txs) != 0 {
+		t.Fatal("transaction committed in the headroom of the block")
+	}
+	if err := worker.Rejected(tx.Hash()); err != errBlockSizeReached {
+		t.Errorf("expected the transaction rejected for the block size, got %v", err)
+	}
+}
+
+// newParallelTestWorker returns a worker on a chain funding the given accounts,
+// executing the transactions with the given parallelism
+func newParallelTestWorker(t *testing.T, accounts []common.Address, parallelism int) *Worker {
+	alloc := core.GenesisAlloc{}
+	for _, addr := range accounts {
+		alloc[addr] = core.GenesisAccount{Balance: testBankFunds}
+	}
+	database := ethdb.NewMemDatabase()
+	gspec := core.Genesis{
+		Config:  chainConfig,
+		Factory: blockFactory,
+		Alloc:   alloc,
+		ShardID: 0,
+	}
+	gspec.MustCommit(database)
+	chain, err := core.NewBlockChain(database, nil, gspec.Config, chain2.Engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker := New(params.TestChainConfig, chain, chain2.Engine)
+	worker.SetParallelism(parallelism)
+	if parallelism > 1 && !worker.parallel() {
+		t.Fatal("transactions not executed in parallel")
+	}
+	return worker
+}
+
+func TestCommitTransactionsParallel(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 4)
+	accounts := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		accounts[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	fresh := common.HexToAddress("0x1234")
+
+	// tx is a transaction of the test, by the index of its sender
+	type tx struct {
+		from  int
+		nonce uint64
+		to    common.Address
+		gas   uint64
+		price int64
+	}
+	for _, test := range []struct {
+		name      string
+		txs       []tx
+		gasPool   uint64 // gas of the block, if limited
+		committed int
+	}{
+		{"independent", []tx{
+			{0, 0, fresh, params.TxGas, 4}, {1, 0, fresh, params.TxGas, 3},
+			{2, 0, common.Address{2}, params.TxGas, 2}, {3, 0, common.Address{3}, params.TxGas, 1},
+			{0, 1, common.Address{4}, params.TxGas, 4}, {1, 1, common.Address{5}, params.TxGas, 3},
+		}, 0, 6},
+		{"dependent", []tx{
+			{0, 0, accounts[1], params.TxGas, 4}, {1, 0, accounts[2], params.TxGas, 3},
+			{2, 0, accounts[3], params.TxGas, 2}, {3, 0, accounts[0], params.TxGas, 1},
+		}, 0, 4},
+		{"gas limit", []tx{
+			{0, 0, fresh, 100000, 4}, {1, 0, fresh, params.TxGas, 3},
+			{2, 0, fresh, params.TxGas, 2}, {0, 1, fresh, params.TxGas, 1},
+			{3, 0, fresh, params.TxGas, 1},
+		}, 2*params.TxGas + 50000, 3},
+		{"nonce too high", []tx{
+			{0, 1, fresh, params.TxGas, 4}, {0, 2, fresh, params.TxGas, 4},
+			{1, 0, fresh, params.TxGas, 3}, {2, 0, fresh, params.TxGas, 2},
+		}, 0, 2},
+	} {
+		signed := make([]*types.Transaction, len(test.txs))
+		for i, tx := range test.txs {
+			signed[i], _ = types.SignTx(
+				types.NewTransaction(tx.nonce, tx.to, 0, big.NewInt(1), tx.gas, big.NewInt(tx.price), nil),
+				types.HomesteadSigner{}, keys[tx.from],
+			)
+		}
+		workers := []*Worker{}
+		for _, parallelism := range []int{1, 4} {
+			worker := newParallelTestWorker(t, accounts, parallelism)
+			if test.gasPool > 0 {
+				worker.current.gasPool = new(core.GasPool).AddGas(test.gasPool)
+			}
+			pending := map[common.Address]types.Transactions{}
+			for i, tx := range test.txs {
+				pending[accounts[tx.from]] = append(pending[accounts[tx.from]], signed[i])
+			}
+			if err := worker.CommitTransactions(pending, nil, accounts[0]); err != nil {
+				t.Fatal(err)
+			}
+			workers = append(workers, worker)
+		}
+
+		serial, parallel := workers[0], workers[1]
+		if len(serial.current.txs) != test.committed {
+			t.Errorf("%s: expected %d transactions committed, got %d", test.name, test.committed, len(serial.current.txs))
+		}
+		if got, want := types.DeriveSha(types.Transactions(parallel.current.txs)), types.DeriveSha(types.Transactions(serial.current.txs)); got != want {
+			t.Errorf("%s: expected the transactions of the serial execution, got %d of %d", test.name, len(parallel.current.txs), len(serial.current.txs))
+		}
+		if got, want := types.DeriveSha(types.Receipts(parallel.current.receipts)), types.DeriveSha(types.Receipts(serial.current.receipts)); got != want {
+			t.Errorf("%s: expected the receipts of the serial execution", test.name)
+		}
+		if got, want := parallel.current.header.GasUsed(), serial.current.header.GasUsed(); got != want {
+			t.Errorf("%s: expected %d gas used, got %d", test.name, want, got)
+		}
+		if got, want := parallel.current.state.IntermediateRoot(true), serial.current.state.IntermediateRoot(true); got != want {
+			t.Errorf("%s: expected the state root %x, got %x", test.name, want, got)
+		}
+	}
+}
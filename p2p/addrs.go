@@ -0,0 +1,51 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+	"github.com/pkg/errors"
+)
+
+// ParseMultiaddrs parses the given multiaddrs, ex: /dns4/node.example.com/tcp/9000
+func ParseMultiaddrs(addrs []string) ([]ma.Multiaddr, error) {
+	result := make([]ma.Multiaddr, 0, len(addrs))
+	for _, addr := range addrs {
+		parsed, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid multiaddr %q", addr)
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
+
+// AdvertiseAddrs is the host option of a node reached through addresses it
+// does not listen on, such as a validator behind a load balancer: the given
+// external addresses are advertised first, then the listen addresses of the
+// host, without the private and loopback ones if suppressPrivate is set
+func AdvertiseAddrs(external []ma.Multiaddr, suppressPrivate bool) libp2p.Option {
+	return libp2p.AddrsFactory(func(listen []ma.Multiaddr) []ma.Multiaddr {
+		return advertisedAddrs(listen, external, suppressPrivate)
+	})
+}
+
+func advertisedAddrs(listen, external []ma.Multiaddr, suppressPrivate bool) []ma.Multiaddr {
+	result := append([]ma.Multiaddr{}, external...)
+	for _, addr := range listen {
+		if suppressPrivate && (manet.IsPrivateAddr(addr) || manet.IsIPLoopback(addr)) {
+			continue
+		}
+		known := false
+		for _, seen := range result {
+			if seen.Equal(addr) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			result = append(result, addr)
+		}
+	}
+	return result
+}
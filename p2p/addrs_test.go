@@ -0,0 +1,48 @@
+package p2p
+
+import (
+	"testing"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+func TestAdvertisedAddrs(t *testing.T) {
+	parse := func(addrs ...string) []ma.Multiaddr {
+		result, err := ParseMultiaddrs(addrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	listen := parse("/ip4/127.0.0.1/tcp/9000", "/ip4/10.0.0.5/tcp/9000", "/ip4/34.1.2.3/tcp/9000")
+	external := parse("/dns4/validator.example.com/tcp/9000", "/ip4/34.1.2.3/tcp/9000")
+
+	tests := []struct {
+		name            string
+		suppressPrivate bool
+		expected        []ma.Multiaddr
+	}{
+		{"all", false, parse(
+			"/dns4/validator.example.com/tcp/9000", "/ip4/34.1.2.3/tcp/9000",
+			"/ip4/127.0.0.1/tcp/9000", "/ip4/10.0.0.5/tcp/9000",
+		)},
+		{"public", true, external},
+	}
+	for _, test := range tests {
+		got := advertisedAddrs(listen, external, test.suppressPrivate)
+		if len(got) != len(test.expected) {
+			t.Errorf("%s: got %v, expected %v", test.name, got, test.expected)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(test.expected[i]) {
+				t.Errorf("%s: got %v, expected %v", test.name, got, test.expected)
+				break
+			}
+		}
+	}
+
+	if _, err := ParseMultiaddrs([]string{"not an address"}); err == nil {
+		t.Error("invalid multiaddr parsed")
+	}
+}
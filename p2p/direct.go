@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/pkg/errors"
+)
+
+// DirectProtocol is the stream protocol sending a message straight to a peer
+// instead of publishing it to a group, ex: the transactions sent to the leader
+// of the shard. The messages received are handled as the ones relayed by a
+// sentry, the handler deciding which ones it accepts.
+const DirectProtocol = protocol.ID("/harmony/direct/1.0.0")
+
+// directTimeout bounds the sending of a direct message
+const directTimeout = 5 * time.Second
+
+var errNoDirectHandler = errors.New("peer does not accept direct messages")
+
+// directMessages holds the handler of the direct messages of a host, nil
+// until set
+type directMessages struct {
+	lock    sync.RWMutex
+	handler RelayHandler
+}
+
+func (d *directMessages) getHandler() RelayHandler {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.handler
+}
+
+// SetDirectHandler accepts the direct messages of the peers, passing them to
+// the handler
+func (host *HostV2) SetDirectHandler(handler RelayHandler) {
+	host.direct.lock.Lock()
+	host.direct.handler = handler
+	host.direct.lock.Unlock()
+	host.h.SetStreamHandler(DirectProtocol, host.handleDirectStream)
+}
+
+// SendDirect sends the message to the peer over a stream of its own
+func (host *HostV2) SendDirect(id libp2p_peer.ID, msg []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), directTimeout)
+	defer cancel()
+	s, err := host.h.NewStream(ctx, id, DirectProtocol)
+	if err != nil {
+		return err
+	}
+	s.SetDeadline(time.Now().Add(directTimeout))
+	if err := rlp.Encode(s, msg); err != nil {
+		s.Reset()
+		return err
+	}
+	host.metrics.LogSentMessage(int64(len(msg)))
+	return s.Close()
+}
+
+// handleDirectStream reads the message sent by a peer
+func (host *HostV2) handleDirectStream(s libp2p_network.Stream) {
+	from := s.Conn().RemotePeer()
+	s.SetDeadline(time.Now().Add(directTimeout))
+	stream := rlp.NewStream(s, 0)
+	if _, size, err := stream.Kind(); err != nil || size > maxRelayMessageSize {
+		s.Reset()
+		return
+	}
+	var msg []byte
+	if err := stream.Decode(&msg); err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", from.Pretty()).Msg("invalid direct message")
+		s.Reset()
+		return
+	}
+	s.Close()
+	if handler := host.direct.getHandler(); handler != nil {
+		handler(msg, from)
+	}
+}
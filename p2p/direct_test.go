@@ -0,0 +1,69 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+type directMessage struct {
+	msg  []byte
+	from libp2p_peer.ID
+}
+
+func TestSendDirect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	alice, bob := newMockHost(t, network, 9100), newMockHost(t, network, 9101)
+	if err := network.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := network.ConnectPeers(alice.GetID(), bob.GetID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// bob accepts no direct message yet
+	if err := alice.SendDirect(bob.GetID(), []byte("tx")); err == nil {
+		t.Error("direct message sent to a peer not accepting them")
+	}
+
+	received := make(chan directMessage, 1)
+	bob.SetDirectHandler(func(msg []byte, from libp2p_peer.ID) {
+		received <- directMessage{msg, from}
+	})
+	if err := alice.SendDirect(bob.GetID(), []byte("tx")); err != nil {
+		t.Fatalf("cannot send direct message: %v", err)
+	}
+	select {
+	case m := <-received:
+		if !bytes.Equal(m.msg, []byte("tx")) || m.from != alice.GetID() {
+			t.Errorf("unexpected direct message %q from %s", m.msg, m.from.Pretty())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("direct message not received")
+	}
+}
+
+func TestMemSendDirect(t *testing.T) {
+	network := NewMemNetwork()
+	alice := network.NewHost(Peer{IP: "127.0.0.1", Port: "9000"})
+	bob := network.NewHost(Peer{IP: "127.0.0.1", Port: "9001"})
+	if err := alice.SendDirect(bob.GetID(), []byte("tx")); err != errNoDirectHandler {
+		t.Errorf("unexpected error %v", err)
+	}
+	received := make(chan directMessage, 1)
+	bob.SetDirectHandler(func(msg []byte, from libp2p_peer.ID) {
+		received <- directMessage{msg, from}
+	})
+	if err := alice.SendDirect(bob.GetID(), []byte("tx")); err != nil {
+		t.Fatal(err)
+	}
+	if m := <-received; !bytes.Equal(m.msg, []byte("tx")) || m.from != alice.GetID() {
+		t.Errorf("unexpected direct message %q from %s", m.msg, m.from)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/harmony-one/bls/ffi/go/bls"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
 	"github.com/harmony-one/harmony/internal/utils"
@@ -34,7 +35,39 @@ type Host interface {
 	ConnectHostPeer(Peer) error
 	// SendMessageToGroups sends a message to one or more multicast groups.
 	SendMessageToGroups(groups []nodeconfig.GroupID, msg []byte) error
-	AllTopics() []*libp2p_pubsub.Topic
+	// AllSubscriptions subscribes to the groups joined
+	AllSubscriptions() ([]Subscription, error)
+
+	// static and trusted peers pinned by the node operator
+	AddStaticPeer(addr ma.Multiaddr) (libp2p_peer.ID, error)
+	RemoveStaticPeer(id libp2p_peer.ID) bool
+	StaticPeers() []libp2p_peer.AddrInfo
+	AddTrustedPeer(id libp2p_peer.ID)
+	RemoveTrustedPeer(id libp2p_peer.ID) bool
+	TrustedPeers() []libp2p_peer.ID
+	IsTrustedPeer(id libp2p_peer.ID) bool
+
+	// sentry architecture, see SentryProtocol
+	EnableSentryMode(sentries []ma.Multiaddr, handler RelayHandler) error
+	ServeAsSentry(validators []libp2p_peer.ID)
+	IsSentryMode() bool
+	RelayToValidators(group string, msg []byte)
+
+	// metadata exchanged with the peers, see MetadataProtocol
+	SetMetadata(meta Metadata)
+	PeerMetadata(id libp2p_peer.ID) (Metadata, bool)
+	ListPeers() []PeerInfo
+
+	// peer exchange, see PexProtocol
+	EnablePeerExchange()
+
+	// messages sent straight to a peer, see DirectProtocol
+	SetDirectHandler(handler RelayHandler)
+	SendDirect(id libp2p_peer.ID, msg []byte) error
+
+	// proposals fetched from the peers, see ProposalProtocol
+	SetProposalProvider(provider ProposalProvider)
+	FetchProposal(id libp2p_peer.ID, hash common.Hash) ([]byte, error)
 
 	// libp2p.metrics related
 	GetBandwidthTotals() libp2p_metrics.Stats
@@ -63,19 +96,27 @@ func (p Peer) String() string {
 }
 
 // NewHost ..
-func NewHost(self *Peer, key libp2p_crypto.PrivKey) (Host, error) {
+func NewHost(self *Peer, key libp2p_crypto.PrivKey, opts ...libp2p.Option) (Host, error) {
 	listenAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%s", self.Port))
 	if err != nil {
 		return nil, errors.Wrapf(err,
 			"cannot create listen multiaddr from port %#v", self.Port)
 	}
 	ctx := context.Background()
-	p2pHost, err := libp2p.New(ctx,
+	opts = append([]libp2p.Option{
 		libp2p.ListenAddrs(listenAddr), libp2p.Identity(key),
-	)
+	}, opts...)
+	p2pHost, err := libp2p.New(ctx, opts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot initialize libp2p host")
 	}
+	return NewHostFromLibp2p(self, key, p2pHost)
+}
+
+// NewHostFromLibp2p creates the host over the given libp2p host, such as the
+// in-memory hosts of a mock network.
+func NewHostFromLibp2p(self *Peer, key libp2p_crypto.PrivKey, p2pHost libp2p_host.Host) (Host, error) {
+	ctx := context.Background()
 	traceFile := os.Getenv("P2P_TRACEFILE")
 
 	const MaxSize = 2_145_728
@@ -93,28 +134,41 @@ func NewHost(self *Peer, key libp2p_crypto.PrivKey) (Host, error) {
 	}
 
 	self.PeerID = p2pHost.ID()
-	subLogger := utils.Logger().With().Str("hostID", p2pHost.ID().Pretty()).Logger()
 
 	newMetrics := libp2p_metrics.NewBandwidthCounter()
 
+	logger := utils.Logger().With().
+		Str("module", utils.ModuleP2P).
+		Str("hostID", p2pHost.ID().Pretty()).
+		Logger()
 	// has to save the private key for host
 	h := &HostV2{
-		h:       p2pHost,
-		joiner:  topicJoiner{pubsub},
-		joined:  map[string]*libp2p_pubsub.Topic{},
-		self:    *self,
-		priKey:  key,
-		logger:  &subLogger,
-		metrics: newMetrics,
+		h:         p2pHost,
+		joiner:    topicJoiner{pubsub},
+		joined:    map[string]*libp2p_pubsub.Topic{},
+		self:      *self,
+		priKey:    key,
+		metrics:   newMetrics,
+		pinned:    newPinnedPeers(),
+		sentry:    newSentryRelay(),
+		pex:       newPeerExchange(),
+		direct:    &directMessages{},
+		proposals: &proposals{},
+		logger:    &logger,
 	}
+	go h.redialStaticPeers()
 
 	if err != nil {
 		return nil, err
 	}
-	utils.Logger().Info().
+	pubKey := "nil" // RPC nodes have no consensus key
+	if self.ConsensusPubKey != nil {
+		pubKey = self.ConsensusPubKey.SerializeToHexStr()
+	}
+	utils.ModuleLogger(utils.ModuleP2P).Info().
 		Str("self", net.JoinHostPort(self.IP, self.Port)).
 		Interface("PeerID", self.PeerID).
-		Str("PubKey", self.ConsensusPubKey.SerializeToHexStr()).
+		Str("PubKey", pubKey).
 		Msg("libp2p host ready")
 	return h, nil
 }
@@ -139,10 +193,22 @@ type HostV2 struct {
 	self   Peer
 	priKey libp2p_crypto.PrivKey
 	lock   sync.Mutex
-	// logger
-	logger *zerolog.Logger
 	// metrics
 	metrics *libp2p_metrics.BandwidthCounter
+	// static and trusted peers
+	pinned *pinnedPeers
+	// sentry architecture
+	sentry *sentryRelay
+	// metadata advertised to the peers, nil until set
+	metadata *Metadata
+	// peer exchange
+	pex *peerExchange
+	// direct messages
+	direct *directMessages
+	// proposals served to the peers
+	proposals *proposals
+	// logger tagged with the host, the level of the p2p module applied on use
+	logger *zerolog.Logger
 }
 
 func (host *HostV2) getTopic(topic string) (*libp2p_pubsub.Topic, error) {
@@ -162,6 +228,9 @@ func (host *HostV2) getTopic(topic string) (*libp2p_pubsub.Topic, error) {
 // It returns a nil error if and only if it has succeeded to schedule the given
 // message for sending.
 func (host *HostV2) SendMessageToGroups(groups []nodeconfig.GroupID, msg []byte) (err error) {
+	if host.IsSentryMode() {
+		return host.relayToSentries(groups, msg)
+	}
 
 	for _, group := range groups {
 		t, e := host.getTopic(string(group))
@@ -177,7 +246,7 @@ func (host *HostV2) SendMessageToGroups(groups []nodeconfig.GroupID, msg []byte)
 		// log out-going metrics
 		host.metrics.LogSentMessage(int64(len(msg)))
 	}
-	host.logger.Info().
+	host.getLogger().Info().
 		Int64("TotalOut", host.GetBandwidthTotals().TotalOut).
 		Float64("RateOut", host.GetBandwidthTotals().RateOut).
 		Msg("[metrics][p2p] traffic out in bytes")
@@ -193,7 +262,7 @@ func (host *HostV2) AddPeer(p *Peer) error {
 	}
 
 	if p.PeerID == "" {
-		host.logger.Error().Msg("AddPeer PeerID is EMPTY")
+		host.getLogger().Error().Msg("AddPeer PeerID is EMPTY")
 		return fmt.Errorf("AddPeer error: peerID is empty")
 	}
 
@@ -202,13 +271,13 @@ func (host *HostV2) AddPeer(p *Peer) error {
 	addr := fmt.Sprintf("/ip4/%s/tcp/%s", p.IP, p.Port)
 	targetAddr, err := ma.NewMultiaddr(addr)
 	if err != nil {
-		host.logger.Error().Err(err).Msg("AddPeer NewMultiaddr error")
+		host.getLogger().Error().Err(err).Msg("AddPeer NewMultiaddr error")
 		return err
 	}
 
 	p.Addrs = append(p.Addrs, targetAddr)
 	host.Peerstore().AddAddrs(p.PeerID, p.Addrs, libp2p_peerstore.PermanentAddrTTL)
-	host.logger.Info().Interface("peer", *p).Msg("AddPeer add to libp2p_peerstore")
+	host.getLogger().Info().Interface("peer", *p).Msg("AddPeer add to libp2p_peerstore")
 	return nil
 }
 
@@ -217,6 +286,12 @@ func (host *HostV2) Peerstore() libp2p_peerstore.Peerstore {
 	return host.h.Peerstore()
 }
 
+// getLogger returns the p2p module logger with the host ID added, following
+// the runtime changes of the module log level
+func (host *HostV2) getLogger() *zerolog.Logger {
+	return utils.WithModuleLevel(utils.ModuleP2P, host.logger)
+}
+
 // GetID returns ID.Pretty
 func (host *HostV2) GetID() libp2p_peer.ID {
 	return host.h.ID()
@@ -258,19 +333,19 @@ func (host *HostV2) ConnectHostPeer(peer Peer) error {
 	addr := fmt.Sprintf("/ip4/%s/tcp/%s/ipfs/%s", peer.IP, peer.Port, peer.PeerID.Pretty())
 	peerAddr, err := ma.NewMultiaddr(addr)
 	if err != nil {
-		host.logger.Error().Err(err).Interface("peer", peer).Msg("ConnectHostPeer")
+		host.getLogger().Error().Err(err).Interface("peer", peer).Msg("ConnectHostPeer")
 		return err
 	}
 	peerInfo, err := libp2p_peer.AddrInfoFromP2pAddr(peerAddr)
 	if err != nil {
-		host.logger.Error().Err(err).Interface("peer", peer).Msg("ConnectHostPeer")
+		host.getLogger().Error().Err(err).Interface("peer", peer).Msg("ConnectHostPeer")
 		return err
 	}
 	if err := host.h.Connect(ctx, *peerInfo); err != nil {
-		host.logger.Warn().Err(err).Interface("peer", peer).Msg("can't connect to peer")
+		host.getLogger().Warn().Err(err).Interface("peer", peer).Msg("can't connect to peer")
 		return err
 	}
-	host.logger.Info().Interface("node", *peerInfo).Msg("connected to peer host")
+	host.getLogger().Info().Interface("node", *peerInfo).Msg("connected to peer host")
 	return nil
 }
 
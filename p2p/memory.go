@@ -0,0 +1,437 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	libp2p_host "github.com/libp2p/go-libp2p-core/host"
+	libp2p_metrics "github.com/libp2p/go-libp2p-core/metrics"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// memQueueSize is the number of messages a subscription of an in-memory host
+// queues before dropping new ones, as pubsub does for slow subscribers
+const memQueueSize = 1024
+
+var errMemSentryMode = errors.New("sentry mode is not supported by the in-memory host")
+
+// MemNetwork is an in-memory network of hosts, which exchange the messages of
+// their groups over channels instead of libp2p, for tests and simulations
+type MemNetwork struct {
+	lock  sync.RWMutex
+	hosts map[libp2p_peer.ID]*MemHost
+	// subs are the subscriptions to each group
+	subs map[string][]*memSubscription
+}
+
+// NewMemNetwork creates an empty in-memory network
+func NewMemNetwork() *MemNetwork {
+	return &MemNetwork{
+		hosts: map[libp2p_peer.ID]*MemHost{},
+		subs:  map[string][]*memSubscription{},
+	}
+}
+
+// NewHost adds a host to the network, whose peer ID is derived from its
+// address
+func (n *MemNetwork) NewHost(self Peer) *MemHost {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	self.PeerID = libp2p_peer.ID(fmt.Sprintf("mem-%d-%s:%s", len(n.hosts), self.IP, self.Port))
+	host := &MemHost{
+		network:   n,
+		self:      self,
+		joined:    map[string]*memSubscription{},
+		peers:     map[libp2p_peer.ID]Peer{},
+		metrics:   libp2p_metrics.NewBandwidthCounter(),
+		pinned:    newPinnedPeers(),
+		direct:    &directMessages{},
+		proposals: &proposals{},
+	}
+	n.hosts[self.PeerID] = host
+	return host
+}
+
+// Hosts returns the hosts of the network
+func (n *MemNetwork) Hosts() []*MemHost {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	hosts := make([]*MemHost, 0, len(n.hosts))
+	for _, host := range n.hosts {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// join subscribes the host to the group, unless already subscribed
+func (n *MemNetwork) join(host *MemHost, group string) {
+	host.lock.Lock()
+	defer host.lock.Unlock()
+	if _, ok := host.joined[group]; ok {
+		return
+	}
+	sub := &memSubscription{
+		network: n,
+		topic:   group,
+		msgs:    make(chan *Message, memQueueSize),
+		done:    make(chan struct{}),
+	}
+	host.joined[group] = sub
+	n.lock.Lock()
+	n.subs[group] = append(n.subs[group], sub)
+	n.lock.Unlock()
+}
+
+// publish delivers the message to the subscriptions to the group
+func (n *MemNetwork) publish(from libp2p_peer.ID, group string, data []byte) {
+	msg := &Message{Data: data, From: from}
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	for _, sub := range n.subs[group] {
+		select {
+		case sub.msgs <- msg:
+		default:
+		}
+	}
+}
+
+// leave removes the subscription from the network
+func (n *MemNetwork) leave(sub *memSubscription) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	subs := n.subs[sub.topic]
+	for i, s := range subs {
+		if s == sub {
+			n.subs[sub.topic] = append(subs[:i:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// memSubscription is the subscription of an in-memory host to a group
+type memSubscription struct {
+	network *MemNetwork
+	topic   string
+	msgs    chan *Message
+	once    sync.Once
+	done    chan struct{}
+}
+
+func (s *memSubscription) Topic() string {
+	return s.topic
+}
+
+func (s *memSubscription) Next(ctx context.Context) (*Message, error) {
+	select {
+	case msg := <-s.msgs:
+		return msg, nil
+	case <-s.done:
+		return nil, errors.Errorf("subscription to %s cancelled", s.topic)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *memSubscription) Cancel() {
+	s.once.Do(func() {
+		s.network.leave(s)
+		close(s.done)
+	})
+}
+
+// MemHost is a host of an in-memory network. Like the libp2p host, it joins a
+// group the first time it sends to it and receives its own messages.
+type MemHost struct {
+	network *MemNetwork
+	self    Peer
+	lock    sync.Mutex
+	joined  map[string]*memSubscription
+	peers   map[libp2p_peer.ID]Peer
+	metrics *libp2p_metrics.BandwidthCounter
+	pinned  *pinnedPeers
+	// metadata advertised to the other hosts, nil until set
+	metadata *Metadata
+	// handler of the direct messages
+	direct *directMessages
+	// provider of the proposals served to the other hosts
+	proposals *proposals
+}
+
+// GetSelfPeer gets self peer
+func (host *MemHost) GetSelfPeer() Peer {
+	return host.self
+}
+
+// AddPeer adds the peer to the known peers
+func (host *MemHost) AddPeer(p *Peer) error {
+	if p.PeerID == "" {
+		return errors.New("AddPeer error: peerID is empty")
+	}
+	host.lock.Lock()
+	host.peers[p.PeerID] = *p
+	host.lock.Unlock()
+	return nil
+}
+
+// GetID returns the peer ID of the host
+func (host *MemHost) GetID() libp2p_peer.ID {
+	return host.self.PeerID
+}
+
+// GetP2PHost returns nil, the in-memory host having no libp2p host
+func (host *MemHost) GetP2PHost() libp2p_host.Host {
+	return nil
+}
+
+// GetPeerCount returns the number of other hosts in the network
+func (host *MemHost) GetPeerCount() int {
+	host.network.lock.RLock()
+	defer host.network.lock.RUnlock()
+	return len(host.network.hosts) - 1
+}
+
+// ConnectHostPeer checks the peer is a host of the network, all of which are
+// connected
+func (host *MemHost) ConnectHostPeer(peer Peer) error {
+	host.network.lock.RLock()
+	_, ok := host.network.hosts[peer.PeerID]
+	host.network.lock.RUnlock()
+	if !ok {
+		return errors.Errorf("peer %s is not in the in-memory network", peer.PeerID)
+	}
+	return host.AddPeer(&peer)
+}
+
+// SendMessageToGroups sends a message to one or more multicast groups.
+func (host *MemHost) SendMessageToGroups(groups []nodeconfig.GroupID, msg []byte) error {
+	for _, group := range groups {
+		host.network.join(host, string(group))
+		host.network.publish(host.self.PeerID, string(group), msg)
+		host.metrics.LogSentMessage(int64(len(msg)))
+	}
+	return nil
+}
+
+// AllSubscriptions returns the subscriptions to the groups joined
+func (host *MemHost) AllSubscriptions() ([]Subscription, error) {
+	host.lock.Lock()
+	defer host.lock.Unlock()
+	subs := make([]Subscription, 0, len(host.joined))
+	for _, sub := range host.joined {
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// AddStaticPeer pins the peer at the given multiaddress as a static peer
+func (host *MemHost) AddStaticPeer(addr ma.Multiaddr) (libp2p_peer.ID, error) {
+	info, err := libp2p_peer.AddrInfoFromP2pAddr(addr)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid static peer address %s", addr)
+	}
+	host.pinned.lock.Lock()
+	host.pinned.static[info.ID] = *info
+	host.pinned.lock.Unlock()
+	return info.ID, nil
+}
+
+// RemoveStaticPeer unpins the given static peer; it returns false if the peer
+// was not static
+func (host *MemHost) RemoveStaticPeer(id libp2p_peer.ID) bool {
+	host.pinned.lock.Lock()
+	defer host.pinned.lock.Unlock()
+	_, ok := host.pinned.static[id]
+	delete(host.pinned.static, id)
+	return ok
+}
+
+// StaticPeers returns the static peers of the host
+func (host *MemHost) StaticPeers() []libp2p_peer.AddrInfo {
+	host.pinned.lock.RLock()
+	defer host.pinned.lock.RUnlock()
+	peers := make([]libp2p_peer.AddrInfo, 0, len(host.pinned.static))
+	for _, info := range host.pinned.static {
+		peers = append(peers, info)
+	}
+	return peers
+}
+
+// AddTrustedPeer exempts the given peer from rate limiting
+func (host *MemHost) AddTrustedPeer(id libp2p_peer.ID) {
+	host.pinned.lock.Lock()
+	host.pinned.trusted[id] = struct{}{}
+	host.pinned.lock.Unlock()
+}
+
+// RemoveTrustedPeer subjects the given peer to rate limiting again; it
+// returns false if the peer was not trusted
+func (host *MemHost) RemoveTrustedPeer(id libp2p_peer.ID) bool {
+	host.pinned.lock.Lock()
+	defer host.pinned.lock.Unlock()
+	_, ok := host.pinned.trusted[id]
+	delete(host.pinned.trusted, id)
+	return ok
+}
+
+// TrustedPeers returns the trusted peers of the host
+func (host *MemHost) TrustedPeers() []libp2p_peer.ID {
+	host.pinned.lock.RLock()
+	defer host.pinned.lock.RUnlock()
+	peers := make([]libp2p_peer.ID, 0, len(host.pinned.trusted))
+	for id := range host.pinned.trusted {
+		peers = append(peers, id)
+	}
+	return peers
+}
+
+// IsTrustedPeer returns whether the given peer is exempt from rate limiting
+func (host *MemHost) IsTrustedPeer(id libp2p_peer.ID) bool {
+	host.pinned.lock.RLock()
+	defer host.pinned.lock.RUnlock()
+	_, ok := host.pinned.trusted[id]
+	return ok
+}
+
+// EnableSentryMode fails, the in-memory hosts having no sentries
+func (host *MemHost) EnableSentryMode(sentries []ma.Multiaddr, handler RelayHandler) error {
+	return errMemSentryMode
+}
+
+// ServeAsSentry does nothing, the in-memory hosts having no sentries
+func (host *MemHost) ServeAsSentry(validators []libp2p_peer.ID) {}
+
+// IsSentryMode returns false, the in-memory hosts having no sentries
+func (host *MemHost) IsSentryMode() bool {
+	return false
+}
+
+// RelayToValidators does nothing, the in-memory hosts having no sentries
+func (host *MemHost) RelayToValidators(group string, msg []byte) {}
+
+// SetMetadata sets the metadata of the host, read directly by the other hosts
+func (host *MemHost) SetMetadata(meta Metadata) {
+	host.lock.Lock()
+	host.metadata = &meta
+	host.lock.Unlock()
+}
+
+// PeerMetadata returns the metadata set by the given host of the network
+func (host *MemHost) PeerMetadata(id libp2p_peer.ID) (Metadata, bool) {
+	host.network.lock.RLock()
+	peer, ok := host.network.hosts[id]
+	host.network.lock.RUnlock()
+	if !ok {
+		return Metadata{}, false
+	}
+	peer.lock.Lock()
+	defer peer.lock.Unlock()
+	if peer.metadata == nil {
+		return Metadata{}, false
+	}
+	return *peer.metadata, true
+}
+
+// isShardPeer tells whether the given host of the network has the shard of
+// the host in its metadata
+func (host *MemHost) isShardPeer(id libp2p_peer.ID) bool {
+	host.lock.Lock()
+	own := host.metadata
+	host.lock.Unlock()
+	meta, ok := host.PeerMetadata(id)
+	return own != nil && ok && meta.ShardID == own.ShardID
+}
+
+// ListPeers returns the other hosts of the network, all of which are
+// connected, along with their metadata
+func (host *MemHost) ListPeers() []PeerInfo {
+	peers := []PeerInfo{}
+	for _, peer := range host.network.Hosts() {
+		if peer == host {
+			continue
+		}
+		info := PeerInfo{ID: peer.self.PeerID}
+		if meta, ok := host.PeerMetadata(peer.self.PeerID); ok {
+			info.Metadata = &meta
+		}
+		peers = append(peers, info)
+	}
+	return peers
+}
+
+// EnablePeerExchange does nothing, the in-memory hosts being all connected
+func (host *MemHost) EnablePeerExchange() {}
+
+// SetDirectHandler accepts the direct messages of the other hosts, passing
+// them to the handler
+func (host *MemHost) SetDirectHandler(handler RelayHandler) {
+	host.direct.lock.Lock()
+	host.direct.handler = handler
+	host.direct.lock.Unlock()
+}
+
+// SendDirect passes the message to the handler of the direct messages of the
+// given host of the network
+func (host *MemHost) SendDirect(id libp2p_peer.ID, msg []byte) error {
+	host.network.lock.RLock()
+	peer, ok := host.network.hosts[id]
+	host.network.lock.RUnlock()
+	if !ok {
+		return errors.Errorf("peer %s is not in the in-memory network", id)
+	}
+	handler := peer.direct.getHandler()
+	if handler == nil {
+		return errNoDirectHandler
+	}
+	host.metrics.LogSentMessage(int64(len(msg)))
+	go handler(msg, host.self.PeerID)
+	return nil
+}
+
+// SetProposalProvider serves the proposals of the provider to the other hosts
+func (host *MemHost) SetProposalProvider(provider ProposalProvider) {
+	host.proposals.lock.Lock()
+	host.proposals.provider = provider
+	host.proposals.lock.Unlock()
+}
+
+// FetchProposal returns the encoded proposal of the given hash provided by the
+// given host of the network
+func (host *MemHost) FetchProposal(id libp2p_peer.ID, hash common.Hash) ([]byte, error) {
+	host.network.lock.RLock()
+	peer, ok := host.network.hosts[id]
+	host.network.lock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("peer %s is not in the in-memory network", id)
+	}
+	proposal, err := peer.proposals.serve(
+		host.self.PeerID, peer.isShardPeer(host.self.PeerID), hash, time.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(proposal) == 0 {
+		return nil, errProposalNotFound
+	}
+	return proposal, nil
+}
+
+// GetBandwidthTotals returns total bandwidth of a node
+func (host *MemHost) GetBandwidthTotals() libp2p_metrics.Stats {
+	return host.metrics.GetBandwidthTotals()
+}
+
+// LogRecvMessage logs received message on node
+func (host *MemHost) LogRecvMessage(msg []byte) {
+	host.metrics.LogRecvMessage(int64(len(msg)))
+}
+
+// ResetMetrics resets metrics counters
+func (host *MemHost) ResetMetrics() {
+	host.metrics.Reset()
+}
@@ -0,0 +1,63 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+)
+
+func TestMemHostDeliversToGroupMembers(t *testing.T) {
+	network := NewMemNetwork()
+	alice := network.NewHost(Peer{IP: "127.0.0.1", Port: "9000"})
+	bob := network.NewHost(Peer{IP: "127.0.0.1", Port: "9001"})
+	carol := network.NewHost(Peer{IP: "127.0.0.1", Port: "9002"})
+	var _ Host = alice
+
+	shard, other := nodeconfig.GroupID("shard"), nodeconfig.GroupID("other")
+	// joining is a side effect of sending, as with the libp2p host
+	for _, host := range []*MemHost{alice, bob} {
+		if err := host.SendMessageToGroups([]nodeconfig.GroupID{shard}, []byte{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := carol.SendMessageToGroups([]nodeconfig.GroupID{other}, []byte{}); err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := bob.AllSubscriptions()
+	if err != nil || len(subs) != 1 || subs[0].Topic() != string(shard) {
+		t.Fatalf("unexpected subscriptions %v, error %v", subs, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// bob's own join message comes first
+	if msg, err := subs[0].Next(ctx); err != nil || msg.GetFrom() != bob.GetID() {
+		t.Fatalf("got %v, error %v", msg, err)
+	}
+	if err := alice.SendMessageToGroups([]nodeconfig.GroupID{shard}, []byte("block")); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := subs[0].Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.GetFrom() != alice.GetID() || !bytes.Equal(msg.GetData(), []byte("block")) {
+		t.Errorf("got %q from %s", msg.GetData(), msg.GetFrom())
+	}
+
+	carolSubs, _ := carol.AllSubscriptions()
+	carolSubs[0].Next(ctx)
+	short, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	if msg, err := carolSubs[0].Next(short); err == nil {
+		t.Errorf("carol received %q outside of its group", msg.GetData())
+	}
+
+	subs[0].Cancel()
+	if _, err := subs[0].Next(ctx); err == nil {
+		t.Error("cancelled subscription still delivers")
+	}
+}
@@ -0,0 +1,177 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// MetadataProtocol is the stream protocol over which the peers exchange their
+// metadata, on connection and periodically after, in the way of libp2p identify
+const MetadataProtocol = protocol.ID("/harmony/metadata/1.0.0")
+
+const (
+	// metadataKey is the peerstore key of the metadata of a peer
+	metadataKey = "harmony/metadata"
+	// maxMetadataSize bounds the metadata read from a peer
+	maxMetadataSize = 4096
+	// metadataExchangeInterval is how often the metadata is exchanged again
+	// with the connected peers
+	metadataExchangeInterval = 10 * time.Minute
+	// metadataTimeout bounds an exchange with a peer
+	metadataTimeout = 10 * time.Second
+)
+
+// Metadata describes the software and the role of a node, for debugging the
+// network
+type Metadata struct {
+	Version  string `json:"version"`
+	Role     string `json:"role"`
+	ShardID  uint32 `json:"shardID"`
+	Archival bool   `json:"archival"`
+}
+
+// PeerInfo is a connected peer along with the metadata it advertised, nil if
+// none was received
+type PeerInfo struct {
+	ID       libp2p_peer.ID
+	Addrs    []ma.Multiaddr
+	Metadata *Metadata
+}
+
+// SetMetadata sets the metadata of the host advertised to the peers, and starts
+// exchanging metadata with them
+func (host *HostV2) SetMetadata(meta Metadata) {
+	host.lock.Lock()
+	started := host.metadata != nil
+	host.metadata = &meta
+	host.lock.Unlock()
+	if started {
+		return
+	}
+	host.h.SetStreamHandler(MetadataProtocol, host.handleMetadataStream)
+	host.h.Network().Notify(&libp2p_network.NotifyBundle{
+		ConnectedF: func(_ libp2p_network.Network, conn libp2p_network.Conn) {
+			go host.exchangeMetadata(conn.RemotePeer())
+		},
+	})
+	for _, id := range host.h.Network().Peers() {
+		go host.exchangeMetadata(id)
+	}
+	go host.exchangeMetadataPeriodically()
+}
+
+// ownMetadata returns the metadata of the host, nil if not set
+func (host *HostV2) ownMetadata() *Metadata {
+	host.lock.Lock()
+	defer host.lock.Unlock()
+	return host.metadata
+}
+
+// PeerMetadata returns the metadata received from the given peer
+func (host *HostV2) PeerMetadata(id libp2p_peer.ID) (Metadata, bool) {
+	value, err := host.h.Peerstore().Get(id, metadataKey)
+	if err != nil {
+		return Metadata{}, false
+	}
+	meta, ok := value.(Metadata)
+	return meta, ok
+}
+
+// ListPeers returns the connected peers along with their metadata
+func (host *HostV2) ListPeers() []PeerInfo {
+	ids := host.h.Network().Peers()
+	peers := make([]PeerInfo, 0, len(ids))
+	for _, id := range ids {
+		info := PeerInfo{ID: id, Addrs: host.h.Peerstore().Addrs(id)}
+		if meta, ok := host.PeerMetadata(id); ok {
+			info.Metadata = &meta
+		}
+		peers = append(peers, info)
+	}
+	return peers
+}
+
+// exchangeMetadata sends the metadata of the host to the peer and stores the
+// one the peer answers with
+func (host *HostV2) exchangeMetadata(id libp2p_peer.ID) {
+	own := host.ownMetadata()
+	if own == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+	s, err := host.h.NewStream(ctx, id, MetadataProtocol)
+	if err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", id.Pretty()).Msg("cannot exchange metadata")
+		return
+	}
+	s.SetDeadline(time.Now().Add(metadataTimeout))
+	if err := json.NewEncoder(s).Encode(own); err != nil {
+		s.Reset()
+		return
+	}
+	meta, err := readMetadata(s)
+	if err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", id.Pretty()).Msg("invalid peer metadata")
+		s.Reset()
+		return
+	}
+	s.Close()
+	host.storeMetadata(id, meta)
+}
+
+// handleMetadataStream stores the metadata sent by a peer and answers with the
+// metadata of the host
+func (host *HostV2) handleMetadataStream(s libp2p_network.Stream) {
+	from := s.Conn().RemotePeer()
+	s.SetDeadline(time.Now().Add(metadataTimeout))
+	meta, err := readMetadata(s)
+	if err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", from.Pretty()).Msg("invalid peer metadata")
+		s.Reset()
+		return
+	}
+	host.storeMetadata(from, meta)
+	if own := host.ownMetadata(); own != nil {
+		if err := json.NewEncoder(s).Encode(own); err != nil {
+			s.Reset()
+			return
+		}
+	}
+	s.Close()
+}
+
+func (host *HostV2) storeMetadata(id libp2p_peer.ID, meta Metadata) {
+	if err := host.h.Peerstore().Put(id, metadataKey, meta); err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", id.Pretty()).Msg("cannot store peer metadata")
+	}
+}
+
+// exchangeMetadataPeriodically exchanges the metadata with the connected peers
+// again, for their changes, such as upgrades, to be picked up
+func (host *HostV2) exchangeMetadataPeriodically() {
+	ticker := time.NewTicker(metadataExchangeInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, id := range host.h.Network().Peers() {
+			host.exchangeMetadata(id)
+		}
+	}
+}
+
+// readMetadata reads the metadata of a peer from the stream
+func readMetadata(r io.Reader) (Metadata, error) {
+	var meta Metadata
+	if err := json.NewDecoder(io.LimitReader(r, maxMetadataSize)).Decode(&meta); err != nil {
+		return Metadata{}, errors.Wrap(err, "cannot decode metadata")
+	}
+	return meta, nil
+}
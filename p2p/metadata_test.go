@@ -0,0 +1,87 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/internal/utils"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+func newMockHost(t *testing.T, network mocknet.Mocknet, port int) *HostV2 {
+	key, _, err := utils.GenKeyP2P("127.0.0.1", fmt.Sprint(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2pHost, err := network.AddPeer(key, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, err := NewHostFromLibp2p(&Peer{IP: "127.0.0.1", Port: fmt.Sprint(port)}, key, p2pHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host.(*HostV2)
+}
+
+func TestMetadataExchange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	alice, bob := newMockHost(t, network, 9000), newMockHost(t, network, 9001)
+	aliceMeta := Metadata{Version: "v1", Role: "Validator", ShardID: 1}
+	bobMeta := Metadata{Version: "v2", Role: "ExplorerNode", ShardID: 1, Archival: true}
+	alice.SetMetadata(aliceMeta)
+	bob.SetMetadata(bobMeta)
+
+	if err := network.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.ConnectAllButSelf(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, ok := alice.PeerMetadata(bob.GetID())
+		if ok && got == bobMeta {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("bob's metadata not received, got %+v", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got, ok := bob.PeerMetadata(alice.GetID()); !ok || got != aliceMeta {
+		t.Errorf("alice's metadata not received, got %+v", got)
+	}
+	peers := alice.ListPeers()
+	if len(peers) != 1 || peers[0].ID != bob.GetID() || peers[0].Metadata == nil ||
+		*peers[0].Metadata != bobMeta {
+		t.Errorf("unexpected peers %+v", peers)
+	}
+}
+
+func TestMemHostMetadata(t *testing.T) {
+	network := NewMemNetwork()
+	alice := network.NewHost(Peer{IP: "127.0.0.1", Port: "9000"})
+	bob := network.NewHost(Peer{IP: "127.0.0.1", Port: "9001"})
+	if _, ok := alice.PeerMetadata(bob.GetID()); ok {
+		t.Fatal("metadata returned before being set")
+	}
+	meta := Metadata{Version: "v1", Role: "Validator", ShardID: 2}
+	bob.SetMetadata(meta)
+	if got, ok := alice.PeerMetadata(bob.GetID()); !ok || got != meta {
+		t.Errorf("got %+v", got)
+	}
+	peers := alice.ListPeers()
+	if len(peers) != 1 || peers[0].ID != bob.GetID() || peers[0].Metadata == nil {
+		t.Errorf("unexpected peers %+v", peers)
+	}
+}
@@ -0,0 +1,138 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	libp2p_peerstore "github.com/libp2p/go-libp2p-core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// staticPeerTag protects the connections to static peers from pruning
+	staticPeerTag = "harmony-static"
+	// staticPeerRedialInterval is how often the static peers are redialed
+	staticPeerRedialInterval = 30 * time.Second
+)
+
+// pinnedPeers are the peers pinned by the node operator: static peers are
+// always kept connected, trusted peers are exempt from rate limiting
+type pinnedPeers struct {
+	lock    sync.RWMutex
+	static  map[libp2p_peer.ID]libp2p_peer.AddrInfo
+	trusted map[libp2p_peer.ID]struct{}
+}
+
+func newPinnedPeers() *pinnedPeers {
+	return &pinnedPeers{
+		static:  map[libp2p_peer.ID]libp2p_peer.AddrInfo{},
+		trusted: map[libp2p_peer.ID]struct{}{},
+	}
+}
+
+// AddStaticPeer pins the peer at the given multiaddress, which must include
+// its peer ID, as a static peer, and dials it
+func (host *HostV2) AddStaticPeer(addr ma.Multiaddr) (libp2p_peer.ID, error) {
+	info, err := libp2p_peer.AddrInfoFromP2pAddr(addr)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid static peer address %s", addr)
+	}
+	host.pinned.lock.Lock()
+	host.pinned.static[info.ID] = *info
+	host.pinned.lock.Unlock()
+
+	host.Peerstore().AddAddrs(info.ID, info.Addrs, libp2p_peerstore.PermanentAddrTTL)
+	host.h.ConnManager().Protect(info.ID, staticPeerTag)
+	go host.dialStaticPeer(*info)
+	host.getLogger().Info().Str("peer", info.ID.Pretty()).Msg("added static peer")
+	return info.ID, nil
+}
+
+// RemoveStaticPeer unpins the given static peer, leaving its connection open
+// until pruned; it returns false if the peer was not static
+func (host *HostV2) RemoveStaticPeer(id libp2p_peer.ID) bool {
+	host.pinned.lock.Lock()
+	_, ok := host.pinned.static[id]
+	delete(host.pinned.static, id)
+	host.pinned.lock.Unlock()
+	if ok {
+		host.h.ConnManager().Unprotect(id, staticPeerTag)
+		host.getLogger().Info().Str("peer", id.Pretty()).Msg("removed static peer")
+	}
+	return ok
+}
+
+// StaticPeers returns the static peers of the host
+func (host *HostV2) StaticPeers() []libp2p_peer.AddrInfo {
+	host.pinned.lock.RLock()
+	defer host.pinned.lock.RUnlock()
+	peers := make([]libp2p_peer.AddrInfo, 0, len(host.pinned.static))
+	for _, info := range host.pinned.static {
+		peers = append(peers, info)
+	}
+	return peers
+}
+
+// AddTrustedPeer exempts the given peer from rate limiting
+func (host *HostV2) AddTrustedPeer(id libp2p_peer.ID) {
+	host.pinned.lock.Lock()
+	host.pinned.trusted[id] = struct{}{}
+	host.pinned.lock.Unlock()
+	host.getLogger().Info().Str("peer", id.Pretty()).Msg("added trusted peer")
+}
+
+// RemoveTrustedPeer subjects the given peer to rate limiting again; it
+// returns false if the peer was not trusted
+func (host *HostV2) RemoveTrustedPeer(id libp2p_peer.ID) bool {
+	host.pinned.lock.Lock()
+	_, ok := host.pinned.trusted[id]
+	delete(host.pinned.trusted, id)
+	host.pinned.lock.Unlock()
+	if ok {
+		host.getLogger().Info().Str("peer", id.Pretty()).Msg("removed trusted peer")
+	}
+	return ok
+}
+
+// TrustedPeers returns the trusted peers of the host
+func (host *HostV2) TrustedPeers() []libp2p_peer.ID {
+	host.pinned.lock.RLock()
+	defer host.pinned.lock.RUnlock()
+	peers := make([]libp2p_peer.ID, 0, len(host.pinned.trusted))
+	for id := range host.pinned.trusted {
+		peers = append(peers, id)
+	}
+	return peers
+}
+
+// IsTrustedPeer returns whether the given peer is exempt from rate limiting
+func (host *HostV2) IsTrustedPeer(id libp2p_peer.ID) bool {
+	host.pinned.lock.RLock()
+	defer host.pinned.lock.RUnlock()
+	_, ok := host.pinned.trusted[id]
+	return ok
+}
+
+func (host *HostV2) dialStaticPeer(info libp2p_peer.AddrInfo) {
+	if host.h.Network().Connectedness(info.ID) == libp2p_network.Connected {
+		return
+	}
+	if err := host.h.Connect(context.Background(), info); err != nil {
+		host.getLogger().Warn().Err(err).Str("peer", info.ID.Pretty()).Msg("can't connect to static peer")
+	}
+}
+
+// redialStaticPeers reconnects the static peers whose connection dropped
+func (host *HostV2) redialStaticPeers() {
+	ticker := time.NewTicker(staticPeerRedialInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, info := range host.StaticPeers() {
+			host.dialStaticPeer(info)
+		}
+	}
+}
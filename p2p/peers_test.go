@@ -0,0 +1,114 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// p2pAddr returns the multiaddress of the host including its peer ID
+func p2pAddr(t *testing.T, host *HostV2) ma.Multiaddr {
+	addrs, err := libp2p_peer.AddrInfoToP2pAddrs(&libp2p_peer.AddrInfo{
+		ID: host.GetID(), Addrs: host.GetP2PHost().Addrs(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addrs[0]
+}
+
+func TestStaticPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	alice, bob := newMockHost(t, network, 9400), newMockHost(t, network, 9401)
+	if err := network.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	noID, _ := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/9401")
+
+	for _, test := range []struct {
+		name  string
+		addr  ma.Multiaddr
+		valid bool
+	}{
+		{"address with peer ID", p2pAddr(t, bob), true},
+		{"address without peer ID", noID, false},
+	} {
+		id, err := alice.AddStaticPeer(test.addr)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected the peer added %t, got %v", test.name, test.valid, err)
+		}
+		if test.valid && id != bob.GetID() {
+			t.Errorf("%s: expected peer %s, got %s", test.name, bob.GetID(), id)
+		}
+	}
+	if peers := alice.StaticPeers(); len(peers) != 1 || peers[0].ID != bob.GetID() {
+		t.Fatalf("expected bob static, got %v", peers)
+	}
+
+	// the static peer is dialed, and redialed once disconnected
+	alice.dialStaticPeer(alice.StaticPeers()[0])
+	if c := alice.GetP2PHost().Network().Connectedness(bob.GetID()); c != libp2p_network.Connected {
+		t.Fatalf("expected bob connected, got %v", c)
+	}
+	if err := network.DisconnectPeers(alice.GetID(), bob.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	alice.dialStaticPeer(alice.StaticPeers()[0])
+	if c := alice.GetP2PHost().Network().Connectedness(bob.GetID()); c != libp2p_network.Connected {
+		t.Errorf("expected bob reconnected, got %v", c)
+	}
+
+	for _, test := range []struct {
+		name     string
+		id       libp2p_peer.ID
+		expected bool
+	}{
+		{"static peer", bob.GetID(), true},
+		{"removed static peer", bob.GetID(), false},
+		{"unknown peer", alice.GetID(), false},
+	} {
+		if removed := alice.RemoveStaticPeer(test.id); removed != test.expected {
+			t.Errorf("%s: expected removed %t, got %t", test.name, test.expected, removed)
+		}
+	}
+	if peers := alice.StaticPeers(); len(peers) != 0 {
+		t.Errorf("expected no static peer, got %v", peers)
+	}
+}
+
+func TestTrustedPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	alice, bob := newMockHost(t, network, 9410), newMockHost(t, network, 9411)
+	alice.AddTrustedPeer(bob.GetID())
+
+	for _, test := range []struct {
+		name    string
+		id      libp2p_peer.ID
+		trusted bool
+		remove  bool // whether the peer is removed from the trusted peers after
+	}{
+		{"trusted peer", bob.GetID(), true, true},
+		{"removed trusted peer", bob.GetID(), false, false},
+		{"untrusted peer", alice.GetID(), false, true},
+	} {
+		if trusted := alice.IsTrustedPeer(test.id); trusted != test.trusted {
+			t.Errorf("%s: expected trusted %t, got %t", test.name, test.trusted, trusted)
+		}
+		if peers := alice.TrustedPeers(); (len(peers) == 1 && peers[0] == test.id) != test.trusted {
+			t.Errorf("%s: expected listed %t, got %v", test.name, test.trusted, peers)
+		}
+		if test.remove {
+			if removed := alice.RemoveTrustedPeer(test.id); removed != test.trusted {
+				t.Errorf("%s: expected removed %t, got %t", test.name, test.trusted, removed)
+			}
+		}
+	}
+}
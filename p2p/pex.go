@@ -0,0 +1,276 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	libp2p_peerstore "github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// PexProtocol is the stream protocol over which the peers exchange a sample of
+// the harmony peers they know, tagged with their shard, for the mesh of a new
+// shard to form faster than through DHT discovery alone. The exchanges are not
+// signed, the peers received being only dialed, but are rate limited.
+const PexProtocol = protocol.ID("/harmony/pex/1.0.0")
+
+const (
+	// maxPexPeers bounds the peers sent in an exchange
+	maxPexPeers = 32
+	// maxPexAddrs bounds the addresses of a peer sent in an exchange
+	maxPexAddrs = 8
+	// maxPexSize bounds the exchange read from a peer
+	maxPexSize = 64 * 1024
+	// maxPexDials bounds the peers of the own shard dialed after an exchange
+	maxPexDials = 8
+	// maxPexServed bounds the peers whose last exchange is remembered
+	maxPexServed = 1024
+	// pexInterval is how often the host asks a connected peer for peers
+	pexInterval = 2 * time.Minute
+	// pexServeInterval is the minimum time between two exchanges served to the
+	// same peer, trusted peers excepted
+	pexServeInterval = 30 * time.Second
+	// pexTimeout bounds an exchange with a peer
+	pexTimeout = 10 * time.Second
+)
+
+// PexPeer is a harmony peer known to a node, as sent in a peer exchange
+type PexPeer struct {
+	ID      string   `json:"id"`
+	Addrs   []string `json:"addrs"`
+	ShardID uint32   `json:"shardID"`
+}
+
+// pexRequest asks a peer for the peers it knows, those of the shard first
+type pexRequest struct {
+	ShardID uint32 `json:"shardID"`
+}
+
+// peerExchange is the state of the peer exchange of a host
+type peerExchange struct {
+	lock    sync.Mutex
+	enabled bool
+	served  map[libp2p_peer.ID]time.Time // last exchange served to each peer
+}
+
+func newPeerExchange() *peerExchange {
+	return &peerExchange{served: map[libp2p_peer.ID]time.Time{}}
+}
+
+// allow tells whether an exchange can be served to the peer at the given
+// time, and records it if so
+func (pex *peerExchange) allow(id libp2p_peer.ID, now time.Time) bool {
+	pex.lock.Lock()
+	defer pex.lock.Unlock()
+	if last, ok := pex.served[id]; ok && now.Sub(last) < pexServeInterval {
+		return false
+	}
+	if len(pex.served) >= maxPexServed {
+		for peer, last := range pex.served {
+			if now.Sub(last) >= pexServeInterval {
+				delete(pex.served, peer)
+			}
+		}
+		if len(pex.served) >= maxPexServed {
+			return false
+		}
+	}
+	pex.served[id] = now
+	return true
+}
+
+// EnablePeerExchange serves the harmony peers known to the host to the peers
+// asking for them, and periodically asks a connected peer for more, dialing
+// those of the own shard. The shard is the one of the metadata of the host.
+func (host *HostV2) EnablePeerExchange() {
+	host.pex.lock.Lock()
+	enabled := host.pex.enabled
+	host.pex.enabled = true
+	host.pex.lock.Unlock()
+	if enabled {
+		return
+	}
+	host.h.SetStreamHandler(PexProtocol, host.handlePexStream)
+	go host.exchangePeersPeriodically()
+}
+
+// harmonyPeers returns the connected peers whose metadata was received, that
+// is known to speak the harmony protocol
+func (host *HostV2) harmonyPeers() map[libp2p_peer.ID]Metadata {
+	peers := map[libp2p_peer.ID]Metadata{}
+	for _, id := range host.h.Network().Peers() {
+		if meta, ok := host.PeerMetadata(id); ok {
+			peers[id] = meta
+		}
+	}
+	return peers
+}
+
+// samplePeers returns a random sample of the harmony peers connected to the
+// host, those of the given shard first, leaving the requesting peer out
+func (host *HostV2) samplePeers(shardID uint32, exclude libp2p_peer.ID) []PexPeer {
+	inShard, others := []PexPeer{}, []PexPeer{}
+	for id, meta := range host.harmonyPeers() {
+		if id == exclude {
+			continue
+		}
+		addrs := host.h.Peerstore().Addrs(id)
+		if len(addrs) == 0 {
+			continue
+		}
+		if len(addrs) > maxPexAddrs {
+			addrs = addrs[:maxPexAddrs]
+		}
+		p := PexPeer{ID: id.Pretty(), ShardID: meta.ShardID}
+		for _, addr := range addrs {
+			p.Addrs = append(p.Addrs, addr.String())
+		}
+		if meta.ShardID == shardID {
+			inShard = append(inShard, p)
+		} else {
+			others = append(others, p)
+		}
+	}
+	rand.Shuffle(len(inShard), func(i, j int) { inShard[i], inShard[j] = inShard[j], inShard[i] })
+	rand.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+	sample := append(inShard, others...)
+	if len(sample) > maxPexPeers {
+		sample = sample[:maxPexPeers]
+	}
+	return sample
+}
+
+// handlePexStream answers a peer asking for peers with a sample of those known
+// to the host, unless the peer asked too recently
+func (host *HostV2) handlePexStream(s libp2p_network.Stream) {
+	from := s.Conn().RemotePeer()
+	if !host.IsTrustedPeer(from) && !host.pex.allow(from, time.Now()) {
+		host.getLogger().Debug().Str("peer", from.Pretty()).Msg("peer exchange rate limited")
+		s.Reset()
+		return
+	}
+	s.SetDeadline(time.Now().Add(pexTimeout))
+	req := pexRequest{}
+	if err := json.NewDecoder(io.LimitReader(s, maxPexSize)).Decode(&req); err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", from.Pretty()).Msg("invalid peer exchange request")
+		s.Reset()
+		return
+	}
+	if err := json.NewEncoder(s).Encode(host.samplePeers(req.ShardID, from)); err != nil {
+		s.Reset()
+		return
+	}
+	s.Close()
+}
+
+// requestPeers asks the peer for the peers it knows, those of the shard of the
+// host first
+func (host *HostV2) requestPeers(id libp2p_peer.ID) ([]PexPeer, error) {
+	own := host.ownMetadata()
+	if own == nil {
+		return nil, errors.New("no metadata to exchange peers with")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pexTimeout)
+	defer cancel()
+	s, err := host.h.NewStream(ctx, id, PexProtocol)
+	if err != nil {
+		return nil, err
+	}
+	s.SetDeadline(time.Now().Add(pexTimeout))
+	if err := json.NewEncoder(s).Encode(pexRequest{ShardID: own.ShardID}); err != nil {
+		s.Reset()
+		return nil, err
+	}
+	peers := []PexPeer{}
+	if err := json.NewDecoder(io.LimitReader(s, maxPexSize)).Decode(&peers); err != nil {
+		s.Reset()
+		return nil, errors.Wrap(err, "cannot decode exchanged peers")
+	}
+	s.Close()
+	if len(peers) > maxPexPeers {
+		peers = peers[:maxPexPeers]
+	}
+	return peers, nil
+}
+
+// addPexPeers adds the exchanged peers to the peerstore, and dials those of the
+// own shard not connected yet, up to maxPexDials. It returns the peers dialed.
+func (host *HostV2) addPexPeers(peers []PexPeer) int {
+	own := host.ownMetadata()
+	dialed := 0
+	for _, p := range peers {
+		id, err := libp2p_peer.IDB58Decode(p.ID)
+		if err != nil || id == host.GetID() {
+			continue
+		}
+		addrs := []ma.Multiaddr{}
+		for i, s := range p.Addrs {
+			if i >= maxPexAddrs {
+				break
+			}
+			if addr, err := ma.NewMultiaddr(s); err == nil {
+				addrs = append(addrs, addr)
+			}
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+		host.h.Peerstore().AddAddrs(id, addrs, libp2p_peerstore.TempAddrTTL)
+		if own == nil || p.ShardID != own.ShardID || dialed >= maxPexDials ||
+			host.h.Network().Connectedness(id) == libp2p_network.Connected {
+			continue
+		}
+		dialed++
+		go func(info libp2p_peer.AddrInfo) {
+			ctx, cancel := context.WithTimeout(context.Background(), pexTimeout)
+			defer cancel()
+			if err := host.h.Connect(ctx, info); err != nil {
+				host.getLogger().Debug().Err(err).Str("peer", info.ID.Pretty()).Msg("cannot dial exchanged peer")
+			}
+		}(libp2p_peer.AddrInfo{ID: id, Addrs: addrs})
+	}
+	return dialed
+}
+
+// exchangePeers asks a random connected harmony peer for the peers it knows
+func (host *HostV2) exchangePeers() {
+	candidates := []libp2p_peer.ID{}
+	for id := range host.harmonyPeers() {
+		candidates = append(candidates, id)
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	id := candidates[rand.Intn(len(candidates))]
+	peers, err := host.requestPeers(id)
+	if err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", id.Pretty()).Msg("cannot exchange peers")
+		return
+	}
+	dialed := host.addPexPeers(peers)
+	host.getLogger().Debug().
+		Str("peer", id.Pretty()).
+		Int("received", len(peers)).
+		Int("dialed", dialed).
+		Msg("exchanged peers")
+}
+
+// exchangePeersPeriodically exchanges peers once the metadata of the first
+// peers is received, and every pexInterval after
+func (host *HostV2) exchangePeersPeriodically() {
+	time.Sleep(metadataTimeout)
+	host.exchangePeers()
+	ticker := time.NewTicker(pexInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		host.exchangePeers()
+	}
+}
@@ -0,0 +1,88 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	libp2p_peerstore "github.com/libp2p/go-libp2p-core/peerstore"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+func waitForMetadata(t *testing.T, host *HostV2, id libp2p_peer.ID) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := host.PeerMetadata(id); ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("metadata of %s not received", id.Pretty())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPeerExchange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	alice, bob := newMockHost(t, network, 9000), newMockHost(t, network, 9001)
+	carol, dave := newMockHost(t, network, 9002), newMockHost(t, network, 9003)
+	for _, host := range []*HostV2{alice, bob, carol} {
+		host.SetMetadata(Metadata{Version: "v1", Role: "Validator", ShardID: 1})
+		host.EnablePeerExchange()
+	}
+	dave.SetMetadata(Metadata{Version: "v1", Role: "Validator", ShardID: 2})
+
+	if err := network.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	// alice only knows bob, who knows carol and dave
+	for _, peer := range []*HostV2{alice, carol, dave} {
+		if _, err := network.ConnectPeers(bob.GetID(), peer.GetID()); err != nil {
+			t.Fatal(err)
+		}
+		// the mock network does not identify the peers, the addresses are
+		// added as identify would
+		bob.h.Peerstore().AddAddrs(peer.GetID(), peer.h.Peerstore().Addrs(peer.GetID()), libp2p_peerstore.PermanentAddrTTL)
+	}
+	waitForMetadata(t, alice, bob.GetID())
+	waitForMetadata(t, bob, carol.GetID())
+	waitForMetadata(t, bob, dave.GetID())
+
+	peers, err := alice.requestPeers(bob.GetID())
+	if err != nil {
+		t.Fatalf("cannot exchange peers: %v", err)
+	}
+	if len(peers) != 2 || peers[0].ID != carol.GetID().Pretty() || peers[0].ShardID != 1 ||
+		peers[1].ID != dave.GetID().Pretty() || peers[1].ShardID != 2 {
+		t.Fatalf("unexpected exchanged peers %+v", peers)
+	}
+	if dialed := alice.addPexPeers(peers); dialed != 1 {
+		t.Errorf("dialed %d peers, expected carol only", dialed)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for alice.h.Network().Connectedness(carol.GetID()) != libp2p_network.Connected {
+		if time.Now().After(deadline) {
+			t.Fatal("alice not connected to carol")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if alice.h.Network().Connectedness(dave.GetID()) == libp2p_network.Connected {
+		t.Error("alice connected to dave of another shard")
+	}
+	if len(alice.Peerstore().Addrs(dave.GetID())) == 0 {
+		t.Error("addresses of dave not added to the peerstore")
+	}
+
+	// the exchanges served are rate limited, trusted peers excepted
+	if _, err := alice.requestPeers(bob.GetID()); err == nil {
+		t.Error("second exchange not rate limited")
+	}
+	bob.AddTrustedPeer(alice.GetID())
+	if _, err := alice.requestPeers(bob.GetID()); err != nil {
+		t.Errorf("exchange with trusted peer rate limited: %v", err)
+	}
+}
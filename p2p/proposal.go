@@ -0,0 +1,196 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/pkg/errors"
+)
+
+// ProposalProtocol is the stream protocol fetching a proposed block by its
+// hash from a peer, ex: the body of a block the leader proposed header first.
+// The peer answers with the encoded block, empty if it does not have it.
+const ProposalProtocol = protocol.ID("/harmony/proposal/1.0.0")
+
+const (
+	// proposalTimeout bounds the fetching of a proposal from a peer
+	proposalTimeout = 5 * time.Second
+	// proposalRate is the proposals a peer may fetch per second, in bursts of
+	// proposalBurst
+	proposalRate  = 1
+	proposalBurst = 5
+	// maxProposalBuckets bounds the peers whose fetches are tracked
+	maxProposalBuckets = 1024
+)
+
+var (
+	errProposalNotFound    = errors.New("peer does not have the proposal")
+	errProposalNotShard    = errors.New("peer is not of the shard")
+	errProposalRateLimited = errors.New("peer fetches proposals too often")
+)
+
+// ProposalProvider returns the encoded proposal of the given hash, nil if
+// unknown
+type ProposalProvider func(hash common.Hash) []byte
+
+// proposals holds the provider of the proposals served by a host, nil until
+// set, and the budget of the peers fetching them
+type proposals struct {
+	lock     sync.RWMutex
+	provider ProposalProvider
+	buckets  map[libp2p_peer.ID]*proposalBucket
+}
+
+// proposalBucket is a token bucket limiting the fetches of a peer
+type proposalBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (p *proposals) getProvider() ProposalProvider {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.provider
+}
+
+// provide returns the encoded proposal of the given hash, nil if unknown or
+// no provider is set
+func (p *proposals) provide(hash common.Hash) []byte {
+	if provider := p.getProvider(); provider != nil {
+		return provider(hash)
+	}
+	return nil
+}
+
+// serve returns the encoded proposal of the given hash for the peer, nil if
+// unknown, unless the peer is not of the shard of the host or fetches them
+// too often
+func (p *proposals) serve(
+	id libp2p_peer.ID, shardPeer bool, hash common.Hash, now time.Time,
+) ([]byte, error) {
+	if !shardPeer {
+		return nil, errProposalNotShard
+	}
+	if !p.allow(id, now) {
+		return nil, errProposalRateLimited
+	}
+	return p.provide(hash), nil
+}
+
+// allow spends a token of the bucket of the peer, telling whether it had one
+func (p *proposals) allow(id libp2p_peer.ID, now time.Time) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.buckets == nil {
+		p.buckets = map[libp2p_peer.ID]*proposalBucket{}
+	}
+	b, ok := p.buckets[id]
+	if !ok {
+		if len(p.buckets) >= maxProposalBuckets {
+			p.prune(now)
+		}
+		b = &proposalBucket{tokens: proposalBurst, last: now}
+		p.buckets[id] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * proposalRate
+	if b.tokens > proposalBurst {
+		b.tokens = proposalBurst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune drops the buckets full again, of the peers not fetching lately
+func (p *proposals) prune(now time.Time) {
+	for id, b := range p.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*proposalRate >= proposalBurst {
+			delete(p.buckets, id)
+		}
+	}
+}
+
+// isShardPeer tells whether the peer advertised the shard of the host
+func (host *HostV2) isShardPeer(id libp2p_peer.ID) bool {
+	own := host.ownMetadata()
+	meta, ok := host.PeerMetadata(id)
+	return own != nil && ok && meta.ShardID == own.ShardID
+}
+
+// SetProposalProvider serves the proposals of the provider to the peers
+func (host *HostV2) SetProposalProvider(provider ProposalProvider) {
+	host.proposals.lock.Lock()
+	host.proposals.provider = provider
+	host.proposals.lock.Unlock()
+	host.h.SetStreamHandler(ProposalProtocol, host.handleProposalStream)
+}
+
+// FetchProposal fetches the encoded proposal of the given hash from the peer
+func (host *HostV2) FetchProposal(id libp2p_peer.ID, hash common.Hash) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), proposalTimeout)
+	defer cancel()
+	s, err := host.h.NewStream(ctx, id, ProposalProtocol)
+	if err != nil {
+		return nil, err
+	}
+	s.SetDeadline(time.Now().Add(proposalTimeout))
+	if err := rlp.Encode(s, hash); err != nil {
+		s.Reset()
+		return nil, err
+	}
+	stream := rlp.NewStream(s, 0)
+	_, size, err := stream.Kind()
+	if err != nil {
+		s.Reset()
+		return nil, err
+	}
+	if size > maxRelayMessageSize {
+		s.Reset()
+		return nil, errors.Errorf("proposal of %d bytes", size)
+	}
+	var proposal []byte
+	if err := stream.Decode(&proposal); err != nil {
+		s.Reset()
+		return nil, err
+	}
+	s.Close()
+	if len(proposal) == 0 {
+		return nil, errProposalNotFound
+	}
+	host.metrics.LogRecvMessage(int64(len(proposal)))
+	return proposal, nil
+}
+
+// handleProposalStream answers the peer of the shard with the proposal it asks
+// for
+func (host *HostV2) handleProposalStream(s libp2p_network.Stream) {
+	from := s.Conn().RemotePeer()
+	s.SetDeadline(time.Now().Add(proposalTimeout))
+	var hash common.Hash
+	if err := rlp.NewStream(s, common.HashLength+1).Decode(&hash); err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", from.Pretty()).Msg("invalid proposal request")
+		s.Reset()
+		return
+	}
+	proposal, err := host.proposals.serve(from, host.isShardPeer(from), hash, time.Now())
+	if err != nil {
+		host.getLogger().Debug().Err(err).Str("peer", from.Pretty()).Msg("proposal request refused")
+		s.Reset()
+		return
+	}
+	if err := rlp.Encode(s, proposal); err != nil {
+		s.Reset()
+		return
+	}
+	host.metrics.LogSentMessage(int64(len(proposal)))
+	s.Close()
+}
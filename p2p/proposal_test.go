@@ -0,0 +1,119 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+func TestFetchProposal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	alice, bob := newMockHost(t, network, 9200), newMockHost(t, network, 9201)
+	if err := network.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := network.ConnectPeers(alice.GetID(), bob.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	bob.SetMetadata(Metadata{ShardID: 1})
+	bob.storeMetadata(alice.GetID(), Metadata{ShardID: 1})
+
+	// bob serves no proposal yet
+	known := common.HexToHash("0x1")
+	if _, err := alice.FetchProposal(bob.GetID(), known); err == nil {
+		t.Error("proposal fetched from a peer not serving them")
+	}
+
+	bob.SetProposalProvider(func(hash common.Hash) []byte {
+		if hash == known {
+			return []byte("block")
+		}
+		return nil
+	})
+	proposal, err := alice.FetchProposal(bob.GetID(), known)
+	if err != nil {
+		t.Fatalf("cannot fetch proposal: %v", err)
+	}
+	if !bytes.Equal(proposal, []byte("block")) {
+		t.Errorf("unexpected proposal %q", proposal)
+	}
+	if _, err := alice.FetchProposal(bob.GetID(), common.HexToHash("0x2")); err != errProposalNotFound {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// nor does bob serve the peers of another shard
+	bob.storeMetadata(alice.GetID(), Metadata{ShardID: 2})
+	if _, err := alice.FetchProposal(bob.GetID(), known); err == nil {
+		t.Error("proposal fetched by a peer of another shard")
+	}
+}
+
+func TestMemFetchProposal(t *testing.T) {
+	network := NewMemNetwork()
+	alice := network.NewHost(Peer{IP: "127.0.0.1", Port: "9000"})
+	bob := network.NewHost(Peer{IP: "127.0.0.1", Port: "9001"})
+	alice.SetMetadata(Metadata{ShardID: 1})
+	bob.SetMetadata(Metadata{ShardID: 1})
+	known := common.HexToHash("0x1")
+	if _, err := alice.FetchProposal(bob.GetID(), known); err != errProposalNotFound {
+		t.Errorf("unexpected error %v", err)
+	}
+	bob.SetProposalProvider(func(hash common.Hash) []byte {
+		if hash == known {
+			return []byte("block")
+		}
+		return nil
+	})
+	if proposal, err := alice.FetchProposal(bob.GetID(), known); err != nil || !bytes.Equal(proposal, []byte("block")) {
+		t.Errorf("unexpected proposal %q, error %v", proposal, err)
+	}
+}
+
+func TestServeProposal(t *testing.T) {
+	known := common.HexToHash("0x1")
+	now := time.Now()
+	for _, test := range []struct {
+		name      string
+		shardPeer bool
+		fetches   int           // fetches before the one tested
+		elapsed   time.Duration // time since the fetches
+		expected  error
+	}{
+		{"shard peer", true, 0, 0, nil},
+		{"another shard", false, 0, 0, errProposalNotShard},
+		{"burst", true, proposalBurst - 1, 0, nil},
+		{"too often", true, proposalBurst, 0, errProposalRateLimited},
+		{"refilled", true, proposalBurst, time.Second, nil},
+	} {
+		p := &proposals{provider: func(hash common.Hash) []byte {
+			if hash == known {
+				return []byte("block")
+			}
+			return nil
+		}}
+		id := libp2p_peer.ID("peer")
+		for i := 0; i < test.fetches; i++ {
+			if _, err := p.serve(id, true, known, now); err != nil {
+				t.Fatalf("%s: fetch %d refused: %v", test.name, i, err)
+			}
+		}
+		proposal, err := p.serve(id, test.shardPeer, known, now.Add(test.elapsed))
+		if err != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+		if served := bytes.Equal(proposal, []byte("block")); served != (test.expected == nil) {
+			t.Errorf("%s: expected the proposal served %t, got %q", test.name, test.expected == nil, proposal)
+		}
+		// the budget is per peer
+		if _, err := p.serve(libp2p_peer.ID("other"), true, known, now); err != nil {
+			t.Errorf("%s: another peer refused: %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	libp2p "github.com/libp2p/go-libp2p"
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// SentryProtocol is the stream protocol relaying the messages of a validator
+// through its sentries, which keep the validator off the public network.
+const SentryProtocol = protocol.ID("/harmony/sentry/1.0.0")
+
+const (
+	// maxRelayMessageSize bounds the relayed messages, as pubsub does
+	maxRelayMessageSize = 2_145_728
+	// relayWriteTimeout bounds a relay write, so a stalled peer does not
+	// hold up the relay to the others
+	relayWriteTimeout = 5 * time.Second
+)
+
+var errNotSentryPeer = errors.New("peer is neither a sentry nor a validator behind it")
+
+// RelayHandler handles a message relayed by a sentry
+type RelayHandler func(msg []byte, from libp2p_peer.ID)
+
+// relayMessage is a message relayed over the sentry protocol: from a validator
+// to be published to the groups, from a sentry as received on the groups
+type relayMessage struct {
+	Groups []string
+	Msg    []byte
+}
+
+// sentryRelay holds the sentry state of a host: the sentries of a validator,
+// or the validators behind a sentry
+type sentryRelay struct {
+	lock       sync.RWMutex
+	sentries   map[libp2p_peer.ID]struct{}
+	validators map[libp2p_peer.ID]struct{}
+	streams    map[libp2p_peer.ID]*relayStream
+	handler    RelayHandler
+}
+
+// relayStream is the outbound sentry protocol stream to a peer
+type relayStream struct {
+	lock   sync.Mutex
+	stream libp2p_network.Stream
+}
+
+func newSentryRelay() *sentryRelay {
+	return &sentryRelay{
+		sentries:   map[libp2p_peer.ID]struct{}{},
+		validators: map[libp2p_peer.ID]struct{}{},
+		streams:    map[libp2p_peer.ID]*relayStream{},
+	}
+}
+
+// SuppressAddrs is the host option of a validator behind sentries, which does
+// not advertise its own addresses to the peers it connects to
+func SuppressAddrs() libp2p.Option {
+	return libp2p.AddrsFactory(func([]ma.Multiaddr) []ma.Multiaddr { return nil })
+}
+
+// EnableSentryMode puts the host of a validator behind the given sentries: it
+// stays connected to them only and sends and receives messages through them.
+// Relayed messages are passed to the handler.
+func (host *HostV2) EnableSentryMode(sentries []ma.Multiaddr, handler RelayHandler) error {
+	if len(sentries) == 0 {
+		return errors.New("no sentries given")
+	}
+	ids := []libp2p_peer.ID{}
+	for _, addr := range sentries {
+		id, err := host.AddStaticPeer(addr)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	host.sentry.lock.Lock()
+	for _, id := range ids {
+		host.sentry.sentries[id] = struct{}{}
+	}
+	host.sentry.handler = handler
+	host.sentry.lock.Unlock()
+
+	host.h.SetStreamHandler(SentryProtocol, host.handleRelayStream)
+	host.h.Network().Notify(&libp2p_network.NotifyBundle{
+		ConnectedF: func(_ libp2p_network.Network, conn libp2p_network.Conn) {
+			if !host.isSentry(conn.RemotePeer()) {
+				conn.Close()
+			}
+		},
+	})
+	for _, conn := range host.h.Network().Conns() {
+		if !host.isSentry(conn.RemotePeer()) {
+			conn.Close()
+		}
+	}
+	host.getLogger().Info().Int("sentries", len(ids)).Msg("sentry mode enabled")
+	return nil
+}
+
+// ServeAsSentry relays the messages of the given validators to the network and
+// the messages received from the network to them
+func (host *HostV2) ServeAsSentry(validators []libp2p_peer.ID) {
+	host.sentry.lock.Lock()
+	for _, id := range validators {
+		host.sentry.validators[id] = struct{}{}
+	}
+	host.sentry.lock.Unlock()
+	for _, id := range validators {
+		host.h.ConnManager().Protect(id, staticPeerTag)
+		host.AddTrustedPeer(id)
+	}
+	host.h.SetStreamHandler(SentryProtocol, host.handleRelayStream)
+	host.getLogger().Info().Int("validators", len(validators)).Msg("serving as sentry")
+}
+
+// IsSentryMode returns whether the host is a validator behind sentries
+func (host *HostV2) IsSentryMode() bool {
+	host.sentry.lock.RLock()
+	defer host.sentry.lock.RUnlock()
+	return len(host.sentry.sentries) > 0
+}
+
+// RelayToValidators forwards a message received on the given group to the
+// validators behind the host, if it serves as their sentry
+func (host *HostV2) RelayToValidators(group string, msg []byte) {
+	host.sentry.lock.RLock()
+	validators := make([]libp2p_peer.ID, 0, len(host.sentry.validators))
+	for id := range host.sentry.validators {
+		validators = append(validators, id)
+	}
+	host.sentry.lock.RUnlock()
+	for _, id := range validators {
+		if host.h.Network().Connectedness(id) != libp2p_network.Connected {
+			continue
+		}
+		if err := host.relay(id, relayMessage{[]string{group}, msg}); err != nil {
+			host.getLogger().Debug().Err(err).Str("validator", id.Pretty()).Msg("cannot relay message to validator")
+		}
+	}
+}
+
+// relayToSentries sends the message of a validator to its sentries, to be
+// published to the given groups
+func (host *HostV2) relayToSentries(groups []nodeconfig.GroupID, msg []byte) (err error) {
+	m := relayMessage{Msg: msg}
+	for _, group := range groups {
+		m.Groups = append(m.Groups, string(group))
+	}
+	host.sentry.lock.RLock()
+	sentries := make([]libp2p_peer.ID, 0, len(host.sentry.sentries))
+	for id := range host.sentry.sentries {
+		sentries = append(sentries, id)
+	}
+	host.sentry.lock.RUnlock()
+	sent := false
+	for _, id := range sentries {
+		if e := host.relay(id, m); e != nil {
+			err = e
+			continue
+		}
+		sent = true
+	}
+	if sent {
+		host.metrics.LogSentMessage(int64(len(msg)))
+		return nil
+	}
+	return errors.Wrap(err, "cannot relay message to any sentry")
+}
+
+// relay writes the message to the outbound stream to the peer, opening it if
+// needed
+func (host *HostV2) relay(id libp2p_peer.ID, m relayMessage) error {
+	host.sentry.lock.Lock()
+	rs, ok := host.sentry.streams[id]
+	if !ok {
+		rs = &relayStream{}
+		host.sentry.streams[id] = rs
+	}
+	host.sentry.lock.Unlock()
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	if rs.stream == nil {
+		s, err := host.h.NewStream(context.Background(), id, SentryProtocol)
+		if err != nil {
+			return err
+		}
+		rs.stream = s
+	}
+	return rs.write(m)
+}
+
+// write writes the message to the stream within relayWriteTimeout, dropping
+// the stream on failure so the next relay opens a new one. The caller holds
+// the stream lock.
+func (rs *relayStream) write(m relayMessage) error {
+	rs.stream.SetWriteDeadline(time.Now().Add(relayWriteTimeout))
+	if err := rlp.Encode(rs.stream, m); err != nil {
+		rs.stream.Reset()
+		rs.stream = nil
+		return err
+	}
+	return nil
+}
+
+// handleRelayStream reads the messages relayed by a sentry or a validator
+// behind it
+func (host *HostV2) handleRelayStream(s libp2p_network.Stream) {
+	from := s.Conn().RemotePeer()
+	fromSentry, fromValidator := host.isSentry(from), host.isValidator(from)
+	if !fromSentry && !fromValidator {
+		host.getLogger().Warn().Err(errNotSentryPeer).Str("peer", from.Pretty()).Msg("rejected sentry stream")
+		s.Reset()
+		return
+	}
+	stream := rlp.NewStream(s, 0)
+	for {
+		var m relayMessage
+		if _, size, err := stream.Kind(); err != nil || size > maxRelayMessageSize {
+			s.Reset()
+			return
+		}
+		if err := stream.Decode(&m); err != nil {
+			s.Reset()
+			return
+		}
+		host.LogRecvMessage(m.Msg)
+		switch {
+		case fromValidator:
+			for _, group := range m.Groups {
+				t, err := host.getTopic(group)
+				if err != nil {
+					host.getLogger().Warn().Err(err).Str("group", group).Msg("cannot relay validator message")
+					continue
+				}
+				if err := t.Publish(context.Background(), m.Msg); err != nil {
+					host.getLogger().Warn().Err(err).Str("group", group).Msg("cannot relay validator message")
+				}
+			}
+		case fromSentry:
+			host.sentry.lock.RLock()
+			handler := host.sentry.handler
+			host.sentry.lock.RUnlock()
+			if handler != nil {
+				handler(m.Msg, from)
+			}
+		}
+	}
+}
+
+func (host *HostV2) isSentry(id libp2p_peer.ID) bool {
+	host.sentry.lock.RLock()
+	defer host.sentry.lock.RUnlock()
+	_, ok := host.sentry.sentries[id]
+	return ok
+}
+
+func (host *HostV2) isValidator(id libp2p_peer.ID) bool {
+	host.sentry.lock.RLock()
+	defer host.sentry.lock.RUnlock()
+	_, ok := host.sentry.validators[id]
+	return ok
+}
@@ -0,0 +1,131 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	libp2p_network "github.com/libp2p/go-libp2p-core/network"
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+var errWriteTimeout = errors.New("write deadline exceeded")
+
+// stalledStream is a stream whose peer never reads: its writes fail once the
+// write deadline is reached, and fail right away without one
+type stalledStream struct {
+	libp2p_network.Stream
+	deadline time.Time
+	reset    bool
+}
+
+func (s *stalledStream) SetWriteDeadline(t time.Time) error {
+	s.deadline = t
+	return nil
+}
+
+func (s *stalledStream) Write([]byte) (int, error) {
+	if s.deadline.IsZero() {
+		return 0, errors.New("write blocked forever")
+	}
+	time.Sleep(time.Until(s.deadline))
+	return 0, errWriteTimeout
+}
+
+func (s *stalledStream) Reset() error {
+	s.reset = true
+	return nil
+}
+
+func TestRelayWriteDeadline(t *testing.T) {
+	stream := &stalledStream{}
+	rs := &relayStream{stream: stream}
+	start := time.Now()
+	err := rs.write(relayMessage{Groups: []string{"group"}, Msg: []byte("msg")})
+	if err != errWriteTimeout {
+		t.Fatalf("expected the write to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > relayWriteTimeout+time.Second {
+		t.Errorf("write to a stalled stream took %v", elapsed)
+	}
+	if !stream.reset || rs.stream != nil {
+		t.Error("expected the stalled stream reset and dropped")
+	}
+}
+
+func TestRelayToValidators(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := mocknet.New(ctx)
+	sentry, validator := newMockHost(t, network, 9300), newMockHost(t, network, 9301)
+	if err := network.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	sentryAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/9300/p2p/%s", sentry.GetID().Pretty()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan directMessage, 1)
+	if err := validator.EnableSentryMode([]ma.Multiaddr{sentryAddr}, func(msg []byte, from libp2p_peer.ID) {
+		received <- directMessage{msg, from}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sentry.ServeAsSentry([]libp2p_peer.ID{validator.GetID()})
+	if _, err := network.ConnectPeers(validator.GetID(), sentry.GetID()); err != nil {
+		t.Fatal(err)
+	}
+
+	sentry.RelayToValidators("group", []byte("msg"))
+	select {
+	case m := <-received:
+		if !bytes.Equal(m.msg, []byte("msg")) || m.from != sentry.GetID() {
+			t.Errorf("unexpected relayed message %q from %s", m.msg, m.from.Pretty())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("relayed message not received")
+	}
+}
+
+func TestRelayStreamWrite(t *testing.T) {
+	var buf bytes.Buffer
+	stream := &bufferStream{buf: &buf}
+	rs := &relayStream{stream: stream}
+	m := relayMessage{Groups: []string{"group"}, Msg: []byte("msg")}
+	if err := rs.write(m); err != nil {
+		t.Fatal(err)
+	}
+	if stream.deadline.IsZero() || time.Until(stream.deadline) > relayWriteTimeout {
+		t.Errorf("unexpected write deadline %v", stream.deadline)
+	}
+	var decoded relayMessage
+	if err := rlp.Decode(&buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Msg, m.Msg) || len(decoded.Groups) != 1 || decoded.Groups[0] != "group" {
+		t.Errorf("unexpected relayed message %+v", decoded)
+	}
+}
+
+// bufferStream is a stream writing to a buffer
+type bufferStream struct {
+	libp2p_network.Stream
+	buf      *bytes.Buffer
+	deadline time.Time
+}
+
+func (s *bufferStream) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+func (s *bufferStream) SetWriteDeadline(t time.Time) error {
+	s.deadline = t
+	return nil
+}
@@ -0,0 +1,72 @@
+package p2p
+
+import (
+	"context"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-core/peer"
+	libp2p_pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// Message is a message received on a group
+type Message struct {
+	Data []byte
+	From libp2p_peer.ID
+}
+
+// GetData returns the content of the message
+func (m *Message) GetData() []byte {
+	return m.Data
+}
+
+// GetFrom returns the peer which sent the message
+func (m *Message) GetFrom() libp2p_peer.ID {
+	return m.From
+}
+
+// Subscription delivers the messages received on a group joined by the host,
+// including those the host sent itself
+type Subscription interface {
+	// Topic is the group of the subscription
+	Topic() string
+	// Next blocks until the next message is received or the context is done
+	Next(ctx context.Context) (*Message, error)
+	// Cancel stops the delivery of the messages
+	Cancel()
+}
+
+// pubsubSubscription is the subscription to a libp2p pubsub topic
+type pubsubSubscription struct {
+	sub *libp2p_pubsub.Subscription
+}
+
+func (s pubsubSubscription) Topic() string {
+	return s.sub.Topic()
+}
+
+func (s pubsubSubscription) Next(ctx context.Context) (*Message, error) {
+	msg, err := s.sub.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Data: msg.GetData(), From: msg.GetFrom()}, nil
+}
+
+func (s pubsubSubscription) Cancel() {
+	s.sub.Cancel()
+}
+
+// AllSubscriptions subscribes to all the topics joined
+func (host *HostV2) AllSubscriptions() ([]Subscription, error) {
+	subs := []Subscription{}
+	for _, topic := range host.AllTopics() {
+		sub, err := topic.Subscribe()
+		if err != nil {
+			for _, s := range subs {
+				s.Cancel()
+			}
+			return nil, err
+		}
+		subs = append(subs, pubsubSubscription{sub})
+	}
+	return subs, nil
+}
@@ -169,8 +169,8 @@ options:
    -D             do not download Harmony binaries (default: download when start)
    -N network     join the given network (mainnet, testnet, staking, partner, stress, devnet, tnet; default: mainnet)
    -n port        specify the public base port of the node (default: 9000)
-   -T nodetype    specify the node type (validator, explorer; default: validator)
-   -i shardid     specify the shard id (valid only with explorer node; default: 1)
+   -T nodetype    specify the node type (validator, explorer, rpc; default: validator)
+   -i shardid     specify the shard id (valid only with explorer and rpc node; default: 1)
    -b             download harmony_db files from shard specified by -i <shardid> (default: off)
    -a dbfile      specify the db file to download (default:off)
    -U FOLDER      specify the upgrade folder to download binaries
@@ -312,6 +312,7 @@ unset -v bootnodes REL network_type dns_zone syncdir
 case "${node_type}" in
 validator) ;;
 explorer) archival=true;;
+rpc) ;;
 *)
    usage ;;
 esac
@@ -950,7 +951,7 @@ do
          ;;
       esac
       ;;
-   explorer)
+   explorer|rpc)
       args+=(
       -node_type="${node_type}"
       -shard_id="${shard_id}"
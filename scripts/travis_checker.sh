@@ -88,6 +88,24 @@ else
 	ok=false
 fi
 
+echo "Running go test with race detector on consensus..."
+if go test -race -count=1 ./consensus/...
+then
+	echo "go test -race succeeded."
+else
+	echo "go test -race FAILED!"
+	ok=false
+fi
+
+echo "Running go test with byzantine faults on consensus..."
+if go test -tags byzantine -count=1 ./consensus/...
+then
+	echo "go test -tags byzantine succeeded."
+else
+	echo "go test -tags byzantine FAILED!"
+	ok=false
+fi
+
 if ! ${ok}
 then
 	echo "Some checks failed; see output above."
@@ -225,6 +225,13 @@ type ChainReader interface {
 	CurrentHeader() *block.Header
 }
 
+// EpochChainReader is implemented by the chains keeping an epoch chain, which
+// looks up the shard state of an epoch without reading the full chain
+type EpochChainReader interface {
+	// EpochShardState retrieves the shard state of the given epoch
+	EpochShardState(epoch *big.Int) (*shard.State, error)
+}
+
 // DataProvider ..
 type DataProvider interface {
 	StakingCandidatesReader
@@ -292,9 +299,15 @@ func eposStakedCommittee(
 	shardState.Shards = make([]shard.Committee, shardCount)
 	hAccounts := s.HmyAccounts()
 	shardHarmonyNodes := s.NumHarmonyOperatedNodesPerShard()
+	if len(hAccounts) < shardCount*shardHarmonyNodes {
+		return nil, errors.Errorf(
+			"%d harmony accounts for %d nodes in %d shards",
+			len(hAccounts), shardHarmonyNodes, shardCount,
+		)
+	}
 
 	for i := 0; i < shardCount; i++ {
-		shardState.Shards[i] = shard.Committee{uint32(i), shard.SlotList{}}
+		shardState.Shards[i] = shard.Committee{ShardID: uint32(i), Slots: shard.SlotList{}}
 		for j := 0; j < shardHarmonyNodes; j++ {
 			index := i + j*shardCount
 			pub := &bls.PublicKey{}
@@ -359,22 +372,42 @@ func (def partialStakingEnabled) Compute(
 	instance := shard.Schedule.InstanceForEpoch(epoch)
 	if preStaking {
 		// Pre-staking shard state doesn't need to set epoch (backward compatible)
+		shardState, err := reshardedCommittee(epoch, stakerReader)
+		if err != nil {
+			return nil, err
+		}
+		if shardState != nil {
+			shardState.Epoch = nil
+			return shardState, nil
+		}
 		return preStakingEnabledCommittee(instance), nil
 	}
-	// Sanity check, can't compute against epochs in past
+	// Sanity check, can't compute against epochs in past, but the committees
+	// of past epochs are on record
 	if e := stakerReader.CurrentHeader().Epoch(); epoch.Cmp(e) == -1 {
+		if reader, ok := stakerReader.(EpochChainReader); ok {
+			if shardState, err := reader.EpochShardState(epoch); err == nil {
+				return shardState, nil
+			}
+		}
 		utils.Logger().Error().Uint64("header-epoch", e.Uint64()).
 			Uint64("compute-epoch", epoch.Uint64()).
 			Msg("Tried to compute committee for epoch in past")
 		return nil, ErrComputeForEpochInPast
 	}
-	utils.AnalysisStart("computeEPoSStakedCommittee")
-	shardState, err := eposStakedCommittee(instance, stakerReader)
-	utils.AnalysisEnd("computeEPoSStakedCommittee")
-
+	shardState, err := reshardedCommittee(epoch, stakerReader)
 	if err != nil {
 		return nil, err
 	}
+	if shardState == nil {
+		utils.AnalysisStart("computeEPoSStakedCommittee")
+		shardState, err = eposStakedCommittee(instance, stakerReader)
+		utils.AnalysisEnd("computeEPoSStakedCommittee")
+
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Set the epoch of shard state
 	shardState.Epoch = big.NewInt(0).Set(epoch)
@@ -0,0 +1,106 @@
+package committee
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+var (
+	errReshardNoState        = errors.New("no shard state to reshard")
+	errReshardNoShard        = errors.New("cannot reshard to no shard")
+	errReshardShardIDs       = errors.New("shard state not sorted by shard id")
+	errReshardEmptyCommittee = errors.New("resharding leaves a committee empty")
+)
+
+// Reshard splits or merges the committees of the shard state into the given
+// number of shards, deterministically and moving as few nodes as possible.
+//
+// The shard s of the new state descends from the shard s % n of the old one,
+// n being the smaller of the two numbers of shards. Merging, the committees
+// of the shards removed are appended in order to those of their parents.
+// Splitting, the nodes of a shard are dealt in order among it and its
+// children, the node k going to the child k % number of children. The slots
+// keep their stake.
+func Reshard(state *shard.State, numShards int) (*shard.State, error) {
+	if state == nil || len(state.Shards) == 0 {
+		return nil, errReshardNoState
+	}
+	if numShards < 1 {
+		return nil, errReshardNoShard
+	}
+	for i := range state.Shards {
+		if state.Shards[i].ShardID != uint32(i) {
+			return nil, errors.Wrapf(
+				errReshardShardIDs, "committee %d of shard %d", i, state.Shards[i].ShardID,
+			)
+		}
+	}
+	resharded := &shard.State{Shards: make([]shard.Committee, numShards)}
+	if state.Epoch != nil {
+		resharded.Epoch = new(big.Int).Set(state.Epoch)
+	}
+	for i := range resharded.Shards {
+		resharded.Shards[i] = shard.Committee{ShardID: uint32(i), Slots: shard.SlotList{}}
+	}
+
+	oldShards := len(state.Shards)
+	if numShards <= oldShards {
+		// merge the committees of the removed shards into their parents
+		for i := range state.Shards {
+			parent := &resharded.Shards[i%numShards]
+			parent.Slots = append(parent.Slots, state.Shards[i].Slots.DeepCopy()...)
+		}
+	} else {
+		// deal the nodes of each shard among it and its children
+		for i := range state.Shards {
+			children := (numShards - i + oldShards - 1) / oldShards
+			for k, slot := range state.Shards[i].Slots.DeepCopy() {
+				child := &resharded.Shards[i+(k%children)*oldShards]
+				child.Slots = append(child.Slots, slot)
+			}
+		}
+	}
+
+	for i := range resharded.Shards {
+		if len(resharded.Shards[i].Slots) == 0 {
+			return nil, errors.Wrapf(errReshardEmptyCommittee, "shard %d", i)
+		}
+	}
+	return resharded, nil
+}
+
+// reshardedCommittee returns the committees of the previous epoch split or
+// merged into the shards of the epoch if the sharding schedule changes the
+// number of shards at the epoch, nil otherwise. The nodes already synced stay
+// in their shards as much as possible across the change, the committees being
+// computed anew from the next epoch on. The committees of the previous epoch
+// failing to be read or resharded is an error, as computing them anew would
+// move the nodes to the shards they are not synced with.
+func reshardedCommittee(epoch *big.Int, reader DataProvider) (*shard.State, error) {
+	if reader == nil || epoch.Sign() <= 0 {
+		return nil, nil
+	}
+	prevEpoch := new(big.Int).Sub(epoch, common.Big1)
+	numShards := shard.Schedule.InstanceForEpoch(epoch).NumShards()
+	if shard.Schedule.InstanceForEpoch(prevEpoch).NumShards() == numShards {
+		return nil, nil
+	}
+	prevState, err := reader.ReadShardState(prevEpoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read the committees of epoch %d to reshard", prevEpoch)
+	}
+	resharded, err := Reshard(prevState, int(numShards))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot reshard the committees of epoch %d", prevEpoch)
+	}
+	utils.Logger().Info().
+		Uint64("epoch", epoch.Uint64()).
+		Int("from", len(prevState.Shards)).
+		Uint32("to", numShards).
+		Msg("resharded the committees")
+	return resharded, nil
+}
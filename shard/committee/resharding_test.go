@@ -0,0 +1,311 @@
+package committee
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/block"
+	blockfactory "github.com/harmony-one/harmony/block/factory"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
+	"github.com/harmony-one/harmony/internal/genesis"
+	"github.com/harmony-one/harmony/internal/params"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/pkg/errors"
+)
+
+// testShardState returns a shard state of the given committee sizes, the
+// nodes numbered in order across the shards
+func testShardState(sizes ...int) *shard.State {
+	state := &shard.State{Epoch: big.NewInt(3)}
+	node := 0
+	for i, size := range sizes {
+		com := shard.Committee{ShardID: uint32(i), Slots: shard.SlotList{}}
+		for j := 0; j < size; j++ {
+			node++
+			key := shard.BLSPublicKey{}
+			key[0], key[1] = byte(node>>8), byte(node)
+			stake := numeric.NewDec(int64(node))
+			com.Slots = append(com.Slots, shard.Slot{
+				EcdsaAddress:   common.BigToAddress(big.NewInt(int64(node))),
+				BLSPublicKey:   key,
+				EffectiveStake: &stake,
+			})
+		}
+		state.Shards = append(state.Shards, com)
+	}
+	return state
+}
+
+// testNodes returns the nodes of each committee by number
+func testNodes(state *shard.State) [][]int {
+	nodes := [][]int{}
+	for _, com := range state.Shards {
+		shardNodes := []int{}
+		for _, slot := range com.Slots {
+			shardNodes = append(shardNodes, int(slot.BLSPublicKey[0])<<8|int(slot.BLSPublicKey[1]))
+		}
+		nodes = append(nodes, shardNodes)
+	}
+	return nodes
+}
+
+func TestReshard(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		sizes     []int
+		numShards int
+		expected  [][]int
+	}{
+		{"same", []int{2, 2}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"one shard", []int{1}, 1, [][]int{{1}}},
+		{"merge to one", []int{2, 1, 3}, 1, [][]int{{1, 2, 3, 4, 5, 6}}},
+		{"merge in half", []int{2, 2, 2, 2}, 2, [][]int{{1, 2, 5, 6}, {3, 4, 7, 8}}},
+		{"merge uneven", []int{1, 1, 1, 1, 1}, 2, [][]int{{1, 3, 5}, {2, 4}}},
+		{"split from one", []int{5}, 3, [][]int{{1, 4}, {2, 5}, {3}}},
+		{"split double", []int{2, 3}, 4, [][]int{{1}, {3, 5}, {2}, {4}}},
+		{"split uneven", []int{4, 4}, 3, [][]int{{1, 3}, {5, 6, 7, 8}, {2, 4}}},
+		{"split to single nodes", []int{3}, 3, [][]int{{1}, {2}, {3}}},
+	} {
+		resharded, err := Reshard(testShardState(test.sizes...), test.numShards)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if nodes := testNodes(resharded); !reflect.DeepEqual(nodes, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, nodes)
+		}
+		for i, com := range resharded.Shards {
+			if com.ShardID != uint32(i) {
+				t.Errorf("%s: committee %d of shard %d", test.name, i, com.ShardID)
+			}
+		}
+	}
+}
+
+func TestReshardKeepsSlots(t *testing.T) {
+	state := testShardState(3, 2)
+	resharded, err := Reshard(state, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resharded.Epoch.Cmp(state.Epoch) != 0 || resharded.Epoch == state.Epoch {
+		t.Errorf("expected a copy of the epoch, got %v", resharded.Epoch)
+	}
+	// every node is kept once, with its address and stake
+	found := map[shard.BLSPublicKey]shard.Slot{}
+	for _, com := range resharded.Shards {
+		for _, slot := range com.Slots {
+			if _, ok := found[slot.BLSPublicKey]; ok {
+				t.Errorf("node %x resharded twice", slot.BLSPublicKey[:2])
+			}
+			found[slot.BLSPublicKey] = slot
+		}
+	}
+	for _, com := range state.Shards {
+		for _, slot := range com.Slots {
+			kept, ok := found[slot.BLSPublicKey]
+			if !ok {
+				t.Errorf("node %x dropped", slot.BLSPublicKey[:2])
+				continue
+			}
+			if kept.EcdsaAddress != slot.EcdsaAddress || !kept.EffectiveStake.Equal(*slot.EffectiveStake) {
+				t.Errorf("node %x changed: %+v", slot.BLSPublicKey[:2], kept)
+			}
+		}
+	}
+
+	// the shard state resharded is not modified
+	if nodes := testNodes(state); !reflect.DeepEqual(nodes, [][]int{{1, 2, 3}, {4, 5}}) {
+		t.Errorf("shard state modified: %v", nodes)
+	}
+}
+
+func TestReshardDeterministic(t *testing.T) {
+	state := testShardState(4, 3, 5, 2)
+	for _, numShards := range []int{1, 2, 3, 4, 5, 8} {
+		first, err := Reshard(state, numShards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := Reshard(testShardState(4, 3, 5, 2), numShards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		firstHash, againHash := first.Hash(), again.Hash()
+		if firstHash != againHash {
+			t.Errorf("resharding to %d shards not deterministic", numShards)
+		}
+	}
+}
+
+func TestReshardRoundTrip(t *testing.T) {
+	// splitting then merging back restores the committees, up to their order
+	state := testShardState(4, 4)
+	split, err := Reshard(state, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := Reshard(split, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]int{{1, 3, 2, 4}, {5, 7, 6, 8}}
+	if nodes := testNodes(merged); !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, nodes)
+	}
+}
+
+func TestReshardErrors(t *testing.T) {
+	unsorted := testShardState(1, 1)
+	unsorted.Shards[0].ShardID, unsorted.Shards[1].ShardID = 1, 0
+	for _, test := range []struct {
+		name      string
+		state     *shard.State
+		numShards int
+		expected  error
+	}{
+		{"nil state", nil, 1, errReshardNoState},
+		{"no committee", &shard.State{}, 1, errReshardNoState},
+		{"no shard", testShardState(1), 0, errReshardNoShard},
+		{"negative shards", testShardState(1), -1, errReshardNoShard},
+		{"unsorted", unsorted, 1, errReshardShardIDs},
+		{"more shards than nodes", testShardState(2), 3, errReshardEmptyCommittee},
+		{"empty child", testShardState(3, 1), 4, errReshardEmptyCommittee},
+		{"empty committee kept", testShardState(1, 0), 2, errReshardEmptyCommittee},
+		{"all empty merged", testShardState(0, 0), 1, errReshardEmptyCommittee},
+	} {
+		if _, err := Reshard(test.state, test.numShards); errors.Cause(err) != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+// reshardTestInstance is a sharding instance of the given number of shards,
+// without any genesis node
+type reshardTestInstance struct {
+	shardingconfig.Instance
+	numShards uint32
+}
+
+func (i reshardTestInstance) NumShards() uint32                    { return i.numShards }
+func (i reshardTestInstance) NumNodesPerShard() int                { return 0 }
+func (i reshardTestInstance) NumHarmonyOperatedNodesPerShard() int { return 0 }
+func (i reshardTestInstance) HmyAccounts() []genesis.DeployAccount { return nil }
+func (i reshardTestInstance) FnAccounts() []genesis.DeployAccount  { return nil }
+
+// reshardTestSchedule changes the number of shards at the given epoch
+type reshardTestSchedule struct {
+	shardingconfig.Schedule
+	epoch         int64
+	before, after uint32
+}
+
+func (s reshardTestSchedule) InstanceForEpoch(epoch *big.Int) shardingconfig.Instance {
+	if epoch.Int64() < s.epoch {
+		return reshardTestInstance{numShards: s.before}
+	}
+	return reshardTestInstance{numShards: s.after}
+}
+
+// reshardTestReader provides the committees on record to Compute
+type reshardTestReader struct {
+	DataProvider
+	config *params.ChainConfig
+	epoch  *big.Int // of the current header
+	states map[uint64]*shard.State
+	reads  int
+}
+
+func (r *reshardTestReader) Config() *params.ChainConfig {
+	return r.config
+}
+
+func (r *reshardTestReader) CurrentHeader() *block.Header {
+	return blockfactory.NewTestHeader().With().Epoch(r.epoch).Header()
+}
+
+func (r *reshardTestReader) ReadShardState(epoch *big.Int) (*shard.State, error) {
+	r.reads++
+	if state, ok := r.states[epoch.Uint64()]; ok {
+		return state.DeepCopy(), nil
+	}
+	return nil, errors.Errorf("no shard state of epoch %d", epoch)
+}
+
+func TestComputeResharding(t *testing.T) {
+	defer func(schedule shardingconfig.Schedule) { shard.Schedule = schedule }(shard.Schedule)
+	staking := *params.TestChainConfig
+	staking.StakingEpoch = big.NewInt(0)
+	preStaking := *params.TestChainConfig
+	preStaking.StakingEpoch = params.EpochTBD
+
+	for _, test := range []struct {
+		name          string
+		config        *params.ChainConfig
+		before, after uint32
+		epoch         int64
+		states        map[uint64]*shard.State
+		expected      [][]int
+		expectedEpoch *big.Int
+		expectedErr   string
+		reads         int
+	}{
+		{
+			name: "split at the schedule change", config: &staking,
+			before: 2, after: 4, epoch: 5,
+			states:   map[uint64]*shard.State{4: testShardState(2, 2)},
+			expected: [][]int{{1}, {3}, {2}, {4}}, expectedEpoch: big.NewInt(5), reads: 1,
+		},
+		{
+			name: "merge before staking", config: &preStaking,
+			before: 2, after: 1, epoch: 5,
+			states:   map[uint64]*shard.State{4: testShardState(2, 1)},
+			expected: [][]int{{1, 2, 3}}, reads: 1,
+		},
+		{
+			name: "failed read", config: &staking,
+			before: 2, after: 4, epoch: 5,
+			expectedErr: "cannot read the committees of epoch 4 to reshard: no shard state of epoch 4", reads: 1,
+		},
+		{
+			name: "failed resharding", config: &staking,
+			before: 1, after: 3, epoch: 5,
+			states:      map[uint64]*shard.State{4: testShardState(2)},
+			expectedErr: "cannot reshard the committees of epoch 4: shard 2: resharding leaves a committee empty", reads: 1,
+		},
+		{
+			name: "unchanged shard count", config: &preStaking,
+			before: 2, after: 4, epoch: 4,
+			states:   map[uint64]*shard.State{3: testShardState(2, 2)},
+			expected: [][]int{{}, {}},
+		},
+	} {
+		shard.Schedule = reshardTestSchedule{epoch: 5, before: test.before, after: test.after}
+		reader := &reshardTestReader{
+			config: test.config, epoch: big.NewInt(test.epoch - 1), states: test.states,
+		}
+		state, err := WithStakingEnabled.Compute(big.NewInt(test.epoch), reader)
+		if reader.reads != test.reads {
+			t.Errorf("%s: expected %d reads of the committees, got %d", test.name, test.reads, reader.reads)
+		}
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr || state != nil {
+				t.Errorf("%s: expected error %q, got %v, %v", test.name, test.expectedErr, state, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if nodes := testNodes(state); !reflect.DeepEqual(nodes, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, nodes)
+		}
+		if !reflect.DeepEqual(state.Epoch, test.expectedEpoch) {
+			t.Errorf("%s: expected the epoch %v, got %v", test.name, test.expectedEpoch, state.Epoch)
+		}
+	}
+}
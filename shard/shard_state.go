@@ -58,6 +58,8 @@ type SlotList []Slot
 type Committee struct {
 	ShardID uint32   `json:"shard-id"`
 	Slots   SlotList `json:"subcommittee"`
+	// index maps the BLS keys to their offset in Slots, nil if not built
+	index map[BLSPublicKey]int
 }
 
 func (l SlotList) String() string {
@@ -102,6 +104,7 @@ func DecodeWrapper(shardState []byte) (*State, error) {
 	)
 	err1 = rlp.DecodeBytes(shardState, &newSS)
 	if err1 == nil {
+		newSS.BuildIndex()
 		return &newSS, nil
 	}
 	err2 = rlp.DecodeBytes(shardState, &oldSS)
@@ -117,6 +120,7 @@ func DecodeWrapper(shardState []byte) (*State, error) {
 			}
 		}
 		newSS.Epoch = nil // Make sure for legacy state, the epoch is nil
+		newSS.BuildIndex()
 		return &newSS, nil
 	}
 	return nil, err2
@@ -294,6 +298,27 @@ func (ss *State) DeepCopy() *State {
 	return &r
 }
 
+// BuildIndex indexes the slots of the committees by BLS key, for the lookups
+// by key to take constant time. The state must not be modified after.
+func (ss *State) BuildIndex() {
+	for i := range ss.Shards {
+		ss.Shards[i].BuildIndex()
+	}
+}
+
+// FindCommitteeByKey returns the committee the given BLS key is a member of
+func (ss *State) FindCommitteeByKey(key BLSPublicKey) (*Committee, bool) {
+	if ss == nil {
+		return nil, false
+	}
+	for i := range ss.Shards {
+		if _, ok := ss.Shards[i].SlotIndex(key); ok {
+			return &ss.Shards[i], true
+		}
+	}
+	return nil, false
+}
+
 // Big ..
 func (pk BLSPublicKey) Big() *big.Int {
 	return new(big.Int).SetBytes(pk[:])
@@ -390,9 +415,45 @@ func (c *Committee) DeepCopy() Committee {
 	r := Committee{}
 	r.ShardID = c.ShardID
 	r.Slots = c.Slots.DeepCopy()
+	if c.index != nil {
+		r.BuildIndex()
+	}
 	return r
 }
 
+// BuildIndex indexes the slots of the committee by BLS key, for the lookups
+// by key to take constant time. The slots must not be modified after.
+func (c *Committee) BuildIndex() {
+	index := make(map[BLSPublicKey]int, len(c.Slots))
+	for i := range c.Slots {
+		if _, ok := index[c.Slots[i].BLSPublicKey]; !ok {
+			index[c.Slots[i].BLSPublicKey] = i
+		}
+	}
+	c.index = index
+}
+
+// SlotIndex returns the offset of the slot of the given BLS key in the
+// committee, looked up in the index if built
+func (c *Committee) SlotIndex(key BLSPublicKey) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[key]
+		if ok && i < len(c.Slots) && c.Slots[i].BLSPublicKey == key {
+			return i, true
+		}
+		if !ok && len(c.index) == len(c.Slots) {
+			return 0, false
+		}
+	}
+	// no index, or the slots were modified since built
+	for i := range c.Slots {
+		if c.Slots[i].BLSPublicKey == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 // Hash ..
 func (c *Committee) Hash() common.Hash {
 	return hash.FromRLPNew256(c)
@@ -455,11 +516,9 @@ func (c *Committee) AddressForBLSKey(key BLSPublicKey) (*common.Address, error)
 	if c == nil {
 		return nil, ErrSubCommitteeNil
 	}
-
-	for _, slot := range c.Slots {
-		if CompareBLSPublicKey(slot.BLSPublicKey, key) == 0 {
-			return &slot.EcdsaAddress, nil
-		}
+	if i, ok := c.SlotIndex(key); ok {
+		addr := c.Slots[i].EcdsaAddress
+		return &addr, nil
 	}
 	return nil, ErrValidNotInCommittee
 }
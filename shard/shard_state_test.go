@@ -164,3 +164,53 @@ func TestCompatibilityOldShardStateIntoNew(t *testing.T) {
 	}
 
 }
+
+func TestCommitteeIndex(t *testing.T) {
+	ss := &State{Epoch: big.NewInt(1), Shards: []Committee{{
+		ShardID: 0,
+		Slots: []Slot{
+			{common.Address{0x11}, blsPubKey1, nil},
+			{common.Address{0x22}, blsPubKey2, nil},
+		},
+	}, {
+		ShardID: 1,
+		Slots: []Slot{
+			{common.Address{0x33}, blsPubKey3, nil},
+		},
+	}}}
+	data, err := rlp.EncodeToBytes(ss)
+	if err != nil {
+		t.Fatalf("cannot encode shard state: %v", err)
+	}
+	decoded, err := DecodeWrapper(data)
+	if err != nil {
+		t.Fatalf("cannot decode shard state: %v", err)
+	}
+	if decoded.Shards[0].index == nil || decoded.Shards[1].index == nil {
+		t.Fatal("decoded shard state not indexed")
+	}
+	if addr, err := decoded.Shards[0].AddressForBLSKey(blsPubKey2); err != nil || *addr != (common.Address{0x22}) {
+		t.Errorf("address of key 2: %v %v", addr, err)
+	}
+	if _, err := decoded.Shards[0].AddressForBLSKey(blsPubKey3); err != ErrValidNotInCommittee {
+		t.Errorf("key 3 found in shard 0: %v", err)
+	}
+	if c, ok := decoded.FindCommitteeByKey(blsPubKey3); !ok || c.ShardID != 1 {
+		t.Error("committee of key 3 not found")
+	}
+	if _, ok := decoded.FindCommitteeByKey(blsPubKey4); ok {
+		t.Error("committee of key 4 found")
+	}
+
+	// the slots appended after the index was built are still found
+	c := &decoded.Shards[1]
+	c.Slots = append(c.Slots, Slot{common.Address{0x44}, blsPubKey4, nil})
+	if i, ok := c.SlotIndex(blsPubKey4); !ok || i != 1 {
+		t.Errorf("slot of key 4 at %d %v, expected 1", i, ok)
+	}
+
+	// without index, the slots are scanned
+	if addr, err := ss.Shards[1].AddressForBLSKey(blsPubKey3); err != nil || *addr != (common.Address{0x33}) {
+		t.Errorf("address of key 3 without index: %v %v", addr, err)
+	}
+}
@@ -100,6 +100,26 @@ func (r *stakingEra) ReadRoundResult() *reward.CompletedRound {
 	return &r.CompletedRound
 }
 
+type withUndelegations struct {
+	reward.Reader
+	round reward.CompletedRound
+}
+
+// WithUndelegations adds the undelegations paid out in the block to the
+// result of its round
+func WithUndelegations(
+	r reward.Reader, undelegations []reward.DelegatorPayout,
+) reward.Reader {
+	round := *r.ReadRoundResult()
+	round.Undelegations = undelegations
+	return &withUndelegations{r, round}
+}
+
+// ReadRoundResult ..
+func (w *withUndelegations) ReadRoundResult() *reward.CompletedRound {
+	return &w.round
+}
+
 func adjust(amount numeric.Dec) numeric.Dec {
 	return amount.MulTruncate(
 		numeric.NewDecFromBigInt(big.NewInt(denominations.One)),
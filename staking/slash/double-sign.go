@@ -1,6 +1,7 @@
 package slash
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
@@ -288,6 +289,31 @@ func (r Record) Hash() common.Hash {
 	return hash.FromRLPNew256(r)
 }
 
+// EventKey identifies the double-signing event of the record, regardless of
+// the witness reporting it: the offender and the two conflicting block hashes
+func (r Record) EventKey() common.Hash {
+	first, second := r.Evidence.FirstVote.BlockHeaderHash, r.Evidence.SecondVote.BlockHeaderHash
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
+	}
+	return hash.FromRLPNew256([]interface{}{r.Evidence.Offender, first, second})
+}
+
+// Dedup returns the records keeping only the first report of each
+// double-signing event
+func (r Records) Dedup() Records {
+	deduped, seen := Records{}, map[common.Hash]struct{}{}
+	for i := range r {
+		key := r[i].EventKey()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, r[i])
+	}
+	return deduped
+}
+
 // SetDifference returns all the records that are in ys but not in r
 func (r Records) SetDifference(ys Records) Records {
 	diff, set := Records{}, map[common.Hash]struct{}{}
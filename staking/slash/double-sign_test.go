@@ -542,6 +542,28 @@ func TestSetDifference(t *testing.T) {
 	}
 }
 
+func TestDedup(t *testing.T) {
+	first := defaultSlashRecord()
+	// another witness of the same event, with the votes in reverse order
+	second := defaultSlashRecord()
+	second.Reporter = common.BigToAddress(big.NewInt(42))
+	second.Evidence.FirstVote, second.Evidence.SecondVote =
+		second.Evidence.SecondVote, second.Evidence.FirstVote
+	if first.EventKey() != second.EventKey() {
+		t.Fatal("reports of the same event have different keys")
+	}
+	other := defaultSlashRecord()
+	other.Evidence.Offender = common.BigToAddress(big.NewInt(43))
+
+	deduped := Records{first, second, other}.Dedup()
+	if len(deduped) != 2 {
+		t.Fatalf("got %d records, want 2", len(deduped))
+	}
+	if deduped[0].Reporter != first.Reporter || deduped[1].Hash() != other.Hash() {
+		t.Error("dedup did not keep the first report of each event")
+	}
+}
+
 // TODO bytes used for this example are stale, need to update RLP dump
 // func TestApply(t *testing.T) {
 // 	slashes := exampleSlashRecords()
@@ -0,0 +1,209 @@
+// Package localnet runs a localnet of in-process nodes over an in-memory p2p
+// network, for the integration tests of the node and its consensus.
+//
+// The nodes share the process-wide sharding schedule and network type, so a
+// process runs one localnet at a time.
+package localnet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/consensus/quorum"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
+	"github.com/harmony-one/harmony/internal/genesis"
+	"github.com/harmony-one/harmony/internal/shardchain"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/multibls"
+	"github.com/harmony-one/harmony/node"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/shard"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// basePort is the port of the first node, the others following it
+	basePort = 16000
+	// pollInterval is how often the chains of the nodes are checked
+	pollInterval = 100 * time.Millisecond
+)
+
+// Config is the configuration of a localnet
+type Config struct {
+	// NumNodes is the number of nodes, all validating the beacon chain
+	NumNodes int
+	// BlockPeriod is how long the leader waits between proposals
+	BlockPeriod time.Duration
+}
+
+// Localnet is a beacon chain committee of in-process nodes, linked by an
+// in-memory p2p network and keeping their chains in memory
+type Localnet struct {
+	Nodes   []*node.Node
+	network mocknet.Mocknet
+	cancel  context.CancelFunc
+}
+
+// New creates the nodes of the localnet, connected to one another but not
+// started yet
+func New(config Config) (*Localnet, error) {
+	if config.NumNodes < 1 {
+		return nil, errors.Errorf("localnet of %d nodes", config.NumNodes)
+	}
+	keys := make([]*multibls.PrivateKey, config.NumNodes)
+	accounts := make([]genesis.DeployAccount, config.NumNodes)
+	for i := range keys {
+		key := bls_cosi.RandPrivateKey()
+		pub := key.GetPublicKey()
+		keys[i] = multibls.GetPrivateKey(key)
+		accounts[i] = genesis.DeployAccount{
+			Index:        strconv.Itoa(i),
+			Address:      common.BytesToAddress(crypto.Keccak256(pub.Serialize())[12:]).Hex(),
+			BLSPublicKey: pub.SerializeToHexStr(),
+			ShardID:      shard.BeaconChainShardID,
+		}
+	}
+	instance, err := shardingconfig.NewInstance(
+		1, config.NumNodes, config.NumNodes, numeric.OneDec(),
+		accounts, nil, nil, shardingconfig.VLBPE,
+	)
+	if err != nil {
+		return nil, err
+	}
+	shard.Schedule = shardingconfig.NewFixedSchedule(instance)
+	nodeconfig.SetShardingSchedule(shard.Schedule)
+	nodeconfig.SetNetworkType(nodeconfig.Localnet)
+	nodeconfig.SetDefaultRole(nodeconfig.Validator)
+	beacon := nodeconfig.ShardID(shard.BeaconChainShardID)
+	shardConfig := nodeconfig.GetShardConfig(shard.BeaconChainShardID)
+	shardConfig.SetRole(nodeconfig.Validator)
+	shardConfig.SetBeaconGroupID(nodeconfig.NewGroupIDByShardID(beacon))
+	shardConfig.SetShardGroupID(nodeconfig.NewGroupIDByShardID(beacon))
+	shardConfig.SetClientGroupID(nodeconfig.NewClientGroupIDByShardID(beacon))
+	node.ProposalStartDelay = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	localnet := &Localnet{network: mocknet.New(ctx), cancel: cancel}
+	for i, key := range keys {
+		n, err := localnet.newNode(i, key, config)
+		if err != nil {
+			cancel()
+			return nil, errors.Wrapf(err, "cannot create node %d", i)
+		}
+		localnet.Nodes = append(localnet.Nodes, n)
+	}
+	if err := localnet.network.LinkAll(); err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := localnet.network.ConnectAllButSelf(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return localnet, nil
+}
+
+// newNode creates the i-th node of the localnet, running the consensus with
+// the given key
+func (l *Localnet) newNode(i int, key *multibls.PrivateKey, config Config) (*node.Node, error) {
+	port := strconv.Itoa(basePort + i)
+	p2pKey, _, err := utils.GenKeyP2P("127.0.0.1", port)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/%s", port))
+	if err != nil {
+		return nil, err
+	}
+	p2pHost, err := l.network.AddPeer(p2pKey, addr)
+	if err != nil {
+		return nil, err
+	}
+	self := p2p.Peer{IP: "127.0.0.1", Port: port, ConsensusPubKey: key.PrivateKey[0].GetPublicKey()}
+	host, err := p2p.NewHostFromLibp2p(&self, p2pKey, p2pHost)
+	if err != nil {
+		return nil, err
+	}
+
+	decider := quorum.NewDecider(quorum.SuperMajorityVote, shard.BeaconChainShardID)
+	c, err := consensus.New(host, shard.BeaconChainShardID, p2p.Peer{}, key, decider)
+	if err != nil {
+		return nil, err
+	}
+	c.Decider.SetMyPublicKeyProvider(func() (*multibls.PublicKey, error) {
+		return c.PubKey, nil
+	})
+	c.MinPeers = config.NumNodes - 1
+	c.BlockPeriod = config.BlockPeriod
+
+	n := node.New(host, c, &shardchain.MemDBFactory{}, nil, false)
+	c.ChainReader = n.Blockchain()
+	if err := n.InitConsensusWithValidators(); err != nil {
+		return nil, err
+	}
+	c.SetViewID(n.Blockchain().CurrentHeader().ViewID().Uint64() + 1)
+	c.BlockVerifier = n
+	c.OnConsensusDone = n.PostConsensusProcessing
+	n.State = node.NodeWaitToJoin
+	c.SetMode(c.UpdateConsensusInformation())
+	return n, nil
+}
+
+// Start starts the consensus and the message handling of the nodes
+func (l *Localnet) Start() error {
+	for i, n := range l.Nodes {
+		if err := n.ConsensusServiceManagerSetup(); err != nil {
+			return errors.Wrapf(err, "cannot set up node %d", i)
+		}
+		n.RunServices()
+		go func(i int, n *node.Node) {
+			if err := n.Start(); err != nil {
+				utils.Logger().Error().Err(err).Int("node", i).Msg("localnet node stopped")
+			}
+		}(i, n)
+	}
+	return nil
+}
+
+// WaitForBlock waits until all the nodes committed the block of the given
+// number, failing after the timeout
+func (l *Localnet) WaitForBlock(number uint64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		lagging := -1
+		for i, n := range l.Nodes {
+			if n.Blockchain().CurrentBlock().NumberU64() < number {
+				lagging = i
+				break
+			}
+		}
+		if lagging < 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf(
+				"node %d at block %d after %v, waiting for block %d", lagging,
+				l.Nodes[lagging].Blockchain().CurrentBlock().NumberU64(), timeout, number,
+			)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Stop stops the consensus of the nodes and closes the p2p network
+func (l *Localnet) Stop() {
+	for _, n := range l.Nodes {
+		n.StopServices()
+	}
+	l.cancel()
+}
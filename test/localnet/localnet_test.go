@@ -0,0 +1,30 @@
+package localnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalnetCommitsBlocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs consensus rounds")
+	}
+	localnet, err := New(Config{NumNodes: 4, BlockPeriod: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := localnet.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer localnet.Stop()
+
+	if err := localnet.WaitForBlock(3, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	hash := localnet.Nodes[0].Blockchain().GetHeaderByNumber(3).Hash()
+	for i, n := range localnet.Nodes[1:] {
+		if got := n.Blockchain().GetHeaderByNumber(3).Hash(); got != hash {
+			t.Errorf("node %d committed block %x, node 0 %x", i+1, got, hash)
+		}
+	}
+}
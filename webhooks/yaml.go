@@ -17,6 +17,7 @@ const (
 // AvailabilityHooks ..
 type AvailabilityHooks struct {
 	OnDroppedBelowThreshold string `yaml:"on-dropped-below-threshold"`
+	OnOwnKeyAbsent          string `yaml:"on-own-key-absent"`
 }
 
 // DoubleSignWebHooks ..